@@ -19,7 +19,7 @@ import (
 
 	"github.com/solean/ponder/internal/api"
 	"github.com/solean/ponder/internal/appstate"
-	"github.com/solean/ponder/internal/db"
+	"github.com/solean/ponder/pkg/db"
 )
 
 // devAPIEnvVar optionally exposes the API on a localhost port for browser
@@ -32,6 +32,8 @@ type App struct {
 	ctx          context.Context
 	cancel       context.CancelFunc
 	database     *sql.DB
+	readDB       *sql.DB
+	server       *api.Server
 	staticAssets fs.FS
 
 	mu         sync.RWMutex
@@ -100,6 +102,19 @@ func (a *App) failStartup(stage string, err error) {
 	}
 }
 
+// Notify satisfies appstate.Notifier, surfacing background failures such as
+// a failed nightly backup in a native dialog.
+func (a *App) Notify(title, message string) {
+	log.Printf("%s: %s", title, message)
+	if a.ctx != nil {
+		_, _ = wailsruntime.MessageDialog(a.ctx, wailsruntime.MessageDialogOptions{
+			Type:    wailsruntime.ErrorDialog,
+			Title:   title,
+			Message: message,
+		})
+	}
+}
+
 // PickLogFile satisfies api.Desktop with a native open dialog. Returns "" if
 // the user cancels.
 func (a *App) PickLogFile() (string, error) {
@@ -150,7 +165,11 @@ func (a *App) startup(ctx context.Context) {
 		return
 	}
 
-	dbPath := filepath.Join(supportDir, "ponder.db")
+	dbPath, err := appstate.DesktopDBPath(supportDir)
+	if err != nil {
+		a.failStartup("resolve database path", err)
+		return
+	}
 	database, err := db.Open(dbPath)
 	if err != nil {
 		a.failStartup("open database", err)
@@ -162,7 +181,14 @@ func (a *App) startup(ctx context.Context) {
 		return
 	}
 
+	reader, err := db.OpenReader(dbPath)
+	if err != nil {
+		_ = database.Close()
+		a.failStartup("open database reader", err)
+		return
+	}
 	store := db.NewStore(database)
+	store.SetReader(reader)
 	currentLogPath, prevLogPath, _ := appstate.DefaultMTGALogPaths()
 	runtimeService, err := appstate.NewService(appstate.Options{
 		Store:              store,
@@ -174,8 +200,10 @@ func (a *App) startup(ctx context.Context) {
 			PickFile: true,
 			Reveal:   true,
 		},
+		Notifier: a,
 	})
 	if err != nil {
+		_ = reader.Close()
 		_ = database.Close()
 		a.failStartup("initialize runtime state", err)
 		return
@@ -183,6 +211,7 @@ func (a *App) startup(ctx context.Context) {
 
 	server := api.NewServer(store, "", runtimeService)
 	server.SetDesktop(a)
+	server.SetImageCacheDir(filepath.Join(supportDir, "card-images"))
 
 	if started, err := runtimeService.MaybeAutoStartLive(); err != nil {
 		log.Printf("auto-start live tracking failed: %v", err)
@@ -194,8 +223,11 @@ func (a *App) startup(ctx context.Context) {
 	server.SetStaticAssets(a.staticAssets)
 	bgCtx, cancel := context.WithCancel(context.Background())
 	server.StartUpdateChecker(bgCtx)
+	runtimeService.StartNightlyBackups(bgCtx)
 
 	a.database = database
+	a.readDB = reader
+	a.server = server
 	a.cancel = cancel
 	a.mu.Lock()
 	a.apiHandler = server.Handler()
@@ -236,6 +268,14 @@ func (a *App) shutdown() {
 	if a.cancel != nil {
 		a.cancel()
 	}
+	if a.server != nil {
+		a.server.Close()
+		a.server = nil
+	}
+	if a.readDB != nil {
+		_ = a.readDB.Close()
+		a.readDB = nil
+	}
 	if a.database != nil {
 		_ = a.database.Close()
 		a.database = nil
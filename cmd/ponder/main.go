@@ -1,21 +1,40 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	iofs "io/fs"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/solean/ponder/internal/api"
 	"github.com/solean/ponder/internal/appstate"
-	"github.com/solean/ponder/internal/db"
-	"github.com/solean/ponder/internal/ingest"
+	"github.com/solean/ponder/internal/decksync"
+	"github.com/solean/ponder/internal/export"
+	"github.com/solean/ponder/internal/importer"
+	"github.com/solean/ponder/internal/ratings"
+	"github.com/solean/ponder/internal/scryfall"
+	"github.com/solean/ponder/internal/webhook"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/ingest"
+	"github.com/solean/ponder/pkg/model"
+	"github.com/solean/ponder/web"
 )
 
 const defaultDBPath = "data/ponder.db"
@@ -49,6 +68,70 @@ func main() {
 		if err := runCompact(ctx, os.Args[2:]); err != nil {
 			log.Fatalf("compact failed: %v", err)
 		}
+	case "merge":
+		if err := runMerge(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("merge failed: %v", err)
+		}
+	case "reprocess":
+		if err := runReprocess(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("reprocess failed: %v", err)
+		}
+	case "prune":
+		if err := runPrune(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("prune failed: %v", err)
+		}
+	case "migrate-db":
+		if err := runMigrateDB(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("migrate-db failed: %v", err)
+		}
+	case "backup":
+		if err := runBackup(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("backup failed: %v", err)
+		}
+	case "restore":
+		if err := runRestore(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("restore failed: %v", err)
+		}
+	case "migrate":
+		if err := runMigrate(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("migrate failed: %v", err)
+		}
+	case "cards":
+		if err := runCards(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("cards failed: %v", err)
+		}
+	case "ratings":
+		if err := runRatings(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("ratings failed: %v", err)
+		}
+	case "doctor":
+		if err := runDoctor(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("doctor failed: %v", err)
+		}
+	case "export":
+		if err := runExport(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("export failed: %v", err)
+		}
+	case "import":
+		if err := runImport(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("import failed: %v", err)
+		}
+	case "archive":
+		if err := runArchive(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("archive failed: %v", err)
+		}
+	case "rawlog":
+		if err := runRawLog(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("rawlog failed: %v", err)
+		}
+	case "deck":
+		if err := runDeck(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("deck failed: %v", err)
+		}
+	case "service":
+		if err := runService(os.Args[2:]); err != nil {
+			log.Fatalf("service failed: %v", err)
+		}
 	default:
 		printUsage()
 		os.Exit(1)
@@ -57,25 +140,66 @@ func main() {
 
 func printUsage() {
 	fmt.Println("ponder commands:")
-	fmt.Println("  parse -db <path> [-log <path>] [-include-prev=true] [-resume=true]")
-	fmt.Println("  tail  -db <path> [-log <path>] [-interval=2s] [-verbose=false]")
-	fmt.Println("  serve -db <path> [-addr=:8080] [-web-dist=<path>]")
+	fmt.Println("  parse -db <path> [-log <path|dir|glob>] [-include-prev=true] [-resume=true] [-workers=1] [-decode-workers=0] [-raw-level=minimal] [-pprof <dir>] [-dry-run=false]")
+	fmt.Println("  tail  -db <path> [-log <path>] [-interval=2s] [-verbose=false] [-archive-dir=<dir>] [-notify=match,draft,milestone] [-webhook=<url,...> -webhook-secret=<secret>] [-raw-level=minimal]")
+	fmt.Println("  serve -db <path> [-addr=127.0.0.1:8080] [-live=false] [-web-dist=<path>] [-base-path=/prefix] [-access-log=true] [-allowed-origins=<origin,...>] [-api-key=<token>] [-tls-cert=<pem> -tls-key=<pem> | -tls-self-signed] [-webhook=<url,...> -webhook-secret=<secret>]")
 	fmt.Println("  compact -db <path>")
+	fmt.Println("  merge -db <path> -from <path>")
+	fmt.Println("  reprocess -db <path>")
+	fmt.Println("  prune -db <path> [-max-age-days=N] [-max-rows=N] [-compress-after-days=N] [-keep-unread=false]")
+	fmt.Println("  migrate-db -from <path> -to <path> [-support-dir=<path>]")
+	fmt.Println("  backup -db <path> -out <path>")
+	fmt.Println("  restore -db <path> -from <path>")
+	fmt.Println("  migrate -db <path> [-to=<version>]")
+	fmt.Println("  cards sync -db <path> [-force=false]")
+	fmt.Println("  ratings import -db <path> -set <code> [-format=PremierDraft] [<17lands.csv|json>]")
+	fmt.Println("  doctor -db <path> [-log <path>] [-fix=false]")
+	fmt.Println("  export -db <path> [-out=export] [-table=matches,decks,draft-picks,card-plays,collection] [-format=csv|ndjson|json|mtggoldfish|deckbox] [-event=<name>] [-result=win|loss] [-include-archived=false]")
+	fmt.Println("  archive -out <dir> [-log <path>] [-watch=false] [-every=1m]")
+	fmt.Println("  import -db <path> -source mtgatracker|untapped|17lands <file.csv>...")
+	fmt.Println("  rawlog check -db <path> <log>...")
+	fmt.Println("  rawlog write -db <path> -out <file>")
+	fmt.Println("  deck export -db <path> (-id <deck id> | -opponent-of <match id>) [-out <file>]")
+	fmt.Println("  deck push -db <path> -id <deck id> -service moxfield|archidekt")
+	fmt.Println("  service install|uninstall|status [-db <path>] [-addr=127.0.0.1:8080]")
 	fmt.Println("")
 	fmt.Println("If -log is omitted, parse/tail default to:")
-	fmt.Println("  ~/Library/Logs/Wizards Of The Coast/MTGA/Player.log")
+	if current, _, err := appstate.DefaultMTGALogPaths(); err == nil {
+		fmt.Println("  " + current)
+	} else {
+		fmt.Println("  ~/Library/Logs/Wizards Of The Coast/MTGA/Player.log")
+	}
 	fmt.Println("parse also includes Player-prev.log by default.")
 }
 
 func runParse(ctx context.Context, args []string) error {
 	fs := flag.NewFlagSet("parse", flag.ContinueOnError)
 	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
-	logPath := fs.String("log", "", "arena log path (optional; defaults to MTGA macOS path)")
+	logPath := fs.String("log", "", "arena log path, or a directory or glob of archived logs (optional; defaults to the MTGA log path)")
 	includePrev := fs.Bool("include-prev", true, "when -log is omitted, parse Player-prev.log before Player.log")
 	resume := fs.Bool("resume", true, "resume from previous offset")
+	dryRun := fs.Bool("dry-run", false, "parse into a scratch database, leaving -db untouched, and print the event kinds seen")
+	workers := fs.Int("workers", 1, "parse up to this many archived logs at once; the newest file is always parsed last")
+	decodeWorkers := fs.Int("decode-workers", 0, "goroutines decoding game-state JSON ahead of the database writer (0 = one per CPU, 1 = none)")
+	rawLevelName := fs.String("raw-level", "minimal", "what to keep in events_raw: "+strings.Join(db.RawEventLevels, ", "))
+	pprofDir := fs.String("pprof", "", "write CPU and heap profiles of the parse (cpu.pprof, heap.pprof) into this directory")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	rawLevel, err := db.ParseRawEventLevel(*rawLevelName)
+	if err != nil {
+		return err
+	}
+	if *pprofDir != "" {
+		stopProfiles, err := startProfiles(*pprofDir)
+		if err != nil {
+			return err
+		}
+		defer stopProfiles()
+	}
+	if *dryRun {
+		return runParseDryRun(ctx, *logPath, *includePrev)
+	}
 
 	database, err := db.Open(*dbPath)
 	if err != nil {
@@ -86,8 +210,14 @@ func runParse(ctx context.Context, args []string) error {
 	if err := db.Init(ctx, database); err != nil {
 		return err
 	}
+	warnIfMoved(ctx, db.NewStore(database), *dbPath)
 
-	parser := ingest.NewParser(db.NewStore(database))
+	store := db.NewStore(database)
+	store.SetRawEventLevel(rawLevel)
+	parser := ingest.NewParser(store)
+	if *decodeWorkers > 0 {
+		parser.SetDecodeWorkers(*decodeWorkers)
+	}
 
 	logPaths, err := appstate.ResolveParseLogPaths(*logPath, *includePrev)
 	if err != nil {
@@ -102,16 +232,18 @@ func runParse(ctx context.Context, args []string) error {
 	var totalEconomySnapshots int64
 	var totalDecks int64
 	var totalDraftPicks int64
+	var totalSkipped int64
 	startedAt := time.Now().UTC()
 
-	for _, path := range logPaths {
-		stats, err := parser.ParseFile(ctx, path, *resume)
-		if err != nil {
-			return fmt.Errorf("parse %s: %w", path, err)
-		}
-
+	allStats, err := parser.ParseFiles(ctx, logPaths, *resume, *workers)
+	interrupted := errors.Is(err, context.Canceled) && ctx.Err() != nil
+	if err != nil && !interrupted {
+		return err
+	}
+	for _, stats := range allStats {
+		path := stats.LogPath
 		duration := stats.CompletedAt.Sub(stats.StartedAt)
-		log.Printf("parsed %s: lines=%d bytes=%d raw_events=%d matches=%d rank_snapshots=%d economy_snapshots=%d decks=%d draft_picks=%d duration=%s",
+		log.Printf("parsed %s: lines=%d bytes=%d raw_events=%d matches=%d rank_snapshots=%d economy_snapshots=%d decks=%d draft_picks=%d skipped=%d duration=%s",
 			path,
 			stats.LinesRead,
 			stats.BytesRead,
@@ -121,8 +253,12 @@ func runParse(ctx context.Context, args []string) error {
 			stats.EconomySnapshots,
 			stats.DecksUpserted,
 			stats.DraftPicksAdded,
+			stats.LinesSkipped,
 			duration,
 		)
+		if stats.LinesSkipped > 0 {
+			log.Printf("skipped %d lines of %s whose handlers failed; details are in the parse_errors table", stats.LinesSkipped, path)
+		}
 
 		totalLines += stats.LinesRead
 		totalBytes += stats.BytesRead
@@ -132,9 +268,10 @@ func runParse(ctx context.Context, args []string) error {
 		totalEconomySnapshots += stats.EconomySnapshots
 		totalDecks += stats.DecksUpserted
 		totalDraftPicks += stats.DraftPicksAdded
+		totalSkipped += stats.LinesSkipped
 	}
 
-	log.Printf("parse complete (files=%d): lines=%d bytes=%d raw_events=%d matches=%d rank_snapshots=%d economy_snapshots=%d decks=%d draft_picks=%d duration=%s",
+	log.Printf("parse complete (files=%d): lines=%d bytes=%d raw_events=%d matches=%d rank_snapshots=%d economy_snapshots=%d decks=%d draft_picks=%d skipped=%d duration=%s",
 		len(logPaths),
 		totalLines,
 		totalBytes,
@@ -144,13 +281,134 @@ func runParse(ctx context.Context, args []string) error {
 		totalEconomySnapshots,
 		totalDecks,
 		totalDraftPicks,
+		totalSkipped,
 		time.Since(startedAt),
 	)
+	if interrupted {
+		log.Printf("parse interrupted; progress up to the last line read is saved, and the next parse resumes from there")
+		return nil
+	}
 
 	compactReplays(ctx, db.NewStore(database))
 	return nil
 }
 
+// runParseDryRun runs the whole parser over the logs into a scratch
+// database that is deleted afterwards, then prints how many lines of each
+// event kind it saw and which kinds it has no handler for.
+func runParseDryRun(ctx context.Context, logPath string, includePrev bool) error {
+	logPaths, err := appstate.ResolveParseLogPaths(logPath, includePrev)
+	if err != nil {
+		return err
+	}
+
+	scratchDir, err := os.MkdirTemp("", "ponder-dry-run-")
+	if err != nil {
+		return fmt.Errorf("create scratch dir: %w", err)
+	}
+	defer os.RemoveAll(scratchDir)
+	database, err := db.Open(filepath.Join(scratchDir, "dry-run.db"))
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+
+	parser := ingest.NewParser(db.NewStore(database))
+	parser.CountEventKinds()
+	kinds := map[string]int64{}
+	unrecognized := map[string]int64{}
+	var total model.ParseStats
+	for _, path := range logPaths {
+		stats, err := parser.ParseFile(ctx, path, false)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		total.LinesRead += stats.LinesRead
+		total.MatchesUpserted += stats.MatchesUpserted
+		total.DecksUpserted += stats.DecksUpserted
+		total.DraftPicksAdded += stats.DraftPicksAdded
+		total.RankSnapshots += stats.RankSnapshots
+		total.EconomySnapshots += stats.EconomySnapshots
+		total.LinesSkipped += stats.LinesSkipped
+		for kind, n := range stats.EventKinds {
+			kinds[kind] += n
+		}
+		for kind, n := range stats.UnrecognizedKinds {
+			unrecognized[kind] += n
+		}
+	}
+
+	fmt.Printf("dry run over %d file(s), nothing written to the database:\n", len(logPaths))
+	for _, path := range logPaths {
+		fmt.Printf("  %s\n", path)
+	}
+	fmt.Printf("lines=%d matches=%d decks=%d draft_picks=%d rank_snapshots=%d economy_snapshots=%d skipped=%d\n",
+		total.LinesRead, total.MatchesUpserted, total.DecksUpserted, total.DraftPicksAdded,
+		total.RankSnapshots, total.EconomySnapshots, total.LinesSkipped)
+	printKindCounts("event kinds", kinds)
+	printKindCounts("unrecognized (stored raw, not applied)", unrecognized)
+	if total.LinesSkipped > 0 {
+		fmt.Printf("%d lines failed their handlers and would be skipped\n", total.LinesSkipped)
+	}
+	return nil
+}
+
+func printKindCounts(title string, counts map[string]int64) {
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if counts[kinds[i]] != counts[kinds[j]] {
+			return counts[kinds[i]] > counts[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+	fmt.Printf("%s: %d\n", title, len(kinds))
+	for _, kind := range kinds {
+		fmt.Printf("  %8d  %s\n", counts[kind], kind)
+	}
+}
+
+// startProfiles starts a CPU profile into dir/cpu.pprof. The returned func
+// stops it and writes the heap in use then to dir/heap.pprof.
+func startProfiles(dir string) (func(), error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create pprof dir: %w", err)
+	}
+	cpuPath := filepath.Join(dir, "cpu.pprof")
+	cpuFile, err := os.Create(cpuPath)
+	if err != nil {
+		return nil, fmt.Errorf("create cpu profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		_ = cpuFile.Close()
+		return nil, fmt.Errorf("start cpu profile: %w", err)
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		if err := cpuFile.Close(); err != nil {
+			log.Printf("write cpu profile: %v", err)
+		}
+		heapPath := filepath.Join(dir, "heap.pprof")
+		heapFile, err := os.Create(heapPath)
+		if err != nil {
+			log.Printf("create heap profile: %v", err)
+			return
+		}
+		defer heapFile.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(heapFile); err != nil {
+			log.Printf("write heap profile: %v", err)
+			return
+		}
+		log.Printf("wrote profiles %s and %s; inspect with `go tool pprof`", cpuPath, heapPath)
+	}, nil
+}
+
 func compactReplays(ctx context.Context, store *db.Store) {
 	started := time.Now()
 	result, err := store.RunMaintenance(ctx)
@@ -180,20 +438,66 @@ func runCompact(ctx context.Context, args []string) error {
 	if err := db.Init(ctx, database); err != nil {
 		return err
 	}
+	warnIfMoved(ctx, db.NewStore(database), *dbPath)
 
 	compactReplays(ctx, db.NewStore(database))
 	return nil
 }
 
-func runTail(ctx context.Context, args []string) error {
-	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+// runMerge imports matches, decks, drafts, and raw events from another
+// database, skipping those already present.
+func runMerge(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path to merge into")
+	from := fs.String("from", "", "other ponder database to import")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*from) == "" {
+		return fmt.Errorf("-from is required")
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
+
+	started := time.Now()
+	result, err := store.MergeFrom(ctx, *from)
+	if err != nil {
+		return err
+	}
+	log.Printf("merged %s: matches=%d decks=%d decks_updated=%d deck_versions=%d draft_sessions=%d draft_picks=%d raw_events=%d duration=%s",
+		*from, result.Matches, result.Decks, result.DecksUpdated, result.DeckVersions,
+		result.DraftSessions, result.DraftPicks, result.RawEvents, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// runImport reads CSV exports from other trackers into matches and drafts
+// marked with their source. Rows already here are left alone, so importing a
+// file again, or one that overlaps parsed logs, adds only what is new.
+func runImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
 	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
-	logPath := fs.String("log", "", "arena log path (optional; defaults to MTGA macOS Player.log)")
-	interval := fs.Duration("interval", 2*time.Second, "poll interval")
-	verbose := fs.Bool("verbose", false, "log each poll, including idle polls")
+	source := fs.String("source", "", "tracker the files came from: "+strings.Join(importer.Sources, ", "))
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	*source = strings.ToLower(strings.TrimSpace(*source))
+	if *source == "" {
+		return fmt.Errorf("-source is required (%s)", strings.Join(importer.Sources, ", "))
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		return fmt.Errorf("pass one or more CSV files to import")
+	}
 
 	database, err := db.Open(*dbPath)
 	if err != nil {
@@ -204,72 +508,182 @@ func runTail(ctx context.Context, args []string) error {
 	if err := db.Init(ctx, database); err != nil {
 		return err
 	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
 
-	parser := ingest.NewParser(db.NewStore(database))
-	activeLogPath := strings.TrimSpace(*logPath)
-	if activeLogPath == "" {
-		current, _, err := appstate.DefaultMTGALogPaths()
+	for _, path := range files {
+		file, err := os.Open(path)
 		if err != nil {
 			return err
 		}
-		activeLogPath = current
+		read, err := importer.Read(file, *source)
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		result, err := store.Import(ctx, *source, read.Matches, read.Drafts)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		log.Printf("imported %s: matches=%d matches_skipped=%d draft_sessions=%d drafts_skipped=%d draft_picks=%d rows_skipped=%d",
+			path, result.Matches, result.MatchesSkipped, result.DraftSessions, result.DraftsSkipped, result.DraftPicks, read.Skipped)
+		if result.PicksUnresolved > 0 {
+			log.Printf("  %d picks named cards missing from the card catalog; run `ponder cards sync` and import again", result.PicksUnresolved)
+		}
 	}
-	if _, err := os.Stat(activeLogPath); err != nil {
-		return fmt.Errorf("tail log path not found: %s (%w)", activeLogPath, err)
+	return nil
+}
+
+// runReprocess replays stored outgoing requests through the current parser
+// to rebuild decks, drafts, and event runs without the original logs.
+// Matches are not rebuilt.
+func runReprocess(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("reprocess", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
 
-	log.Printf("tailing %s every %s", activeLogPath, interval.String())
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
 
-	go compactReplays(ctx, db.NewStore(database))
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
 
-	ticker := time.NewTicker(*interval)
-	defer ticker.Stop()
+	started := time.Now()
+	stats, err := ingest.NewParser(store).Reprocess(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("reprocess complete: raw_events=%d decks=%d draft_picks=%d duration=%s",
+		stats.RawEvents, stats.DecksUpserted, stats.DraftPicksAdded, time.Since(started).Round(time.Millisecond))
+	return nil
+}
 
-	for {
-		stats, err := parser.ParseFile(ctx, activeLogPath, true)
-		if err != nil {
-			log.Printf("tail parse error: %v", err)
-		} else {
-			hasActivity := stats.LinesRead > 0 ||
-				stats.RawEventsStored > 0 ||
-				stats.MatchesUpserted > 0 ||
-				stats.EconomySnapshots > 0 ||
-				stats.DecksUpserted > 0 ||
-				stats.DraftPicksAdded > 0
+// runPrune applies a retention policy to events_raw and reports the space
+// reclaimed.
+func runPrune(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("prune", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	maxAgeDays := fs.Int("max-age-days", 0, "delete raw events stored more than this many days ago (0 keeps all; default: the retentionDays setting)")
+	maxRows := fs.Int64("max-rows", 0, "keep only the newest this many raw events (0 keeps all)")
+	compressAfterDays := fs.Int("compress-after-days", 0, "zstd-compress raw event payloads older than this many days (0 disables)")
+	keepUnread := fs.Bool("keep-unread", false, "keep raw events nothing reads back, such as those stored with parse -raw-level full")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *maxAgeDays < 0 || *maxRows < 0 || *compressAfterDays < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
 
-			if hasActivity {
-				log.Printf(
-					"tail activity: lines=%d bytes=%d raw_events=%d matches=%d economy_snapshots=%d decks=%d draft_picks=%d duration=%s",
-					stats.LinesRead,
-					stats.BytesRead,
-					stats.RawEventsStored,
-					stats.MatchesUpserted,
-					stats.EconomySnapshots,
-					stats.DecksUpserted,
-					stats.DraftPicksAdded,
-					stats.CompletedAt.Sub(stats.StartedAt),
-				)
-			} else if *verbose {
-				log.Printf("tail idle: no new lines")
-			}
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
+
+	maxAgeSet := false
+	fs.Visit(func(f *flag.Flag) { maxAgeSet = maxAgeSet || f.Name == "max-age-days" })
+	if !maxAgeSet {
+		settings, err := store.GetSettings(ctx)
+		if err != nil {
+			return err
 		}
+		*maxAgeDays = settings.RetentionDays
+	}
 
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
+	const day = 24 * time.Hour
+	started := time.Now()
+	result, err := store.Prune(ctx, db.RawEventPolicy{
+		MaxAge:        time.Duration(*maxAgeDays) * day,
+		MaxRows:       *maxRows,
+		CompressAfter: time.Duration(*compressAfterDays) * day,
+		KeepUnread:    *keepUnread,
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("pruned raw events: unread=%d expired=%d over_limit=%d compressed=%d bytes_before=%d bytes_after=%d reclaimed=%d duration=%s",
+		result.Unread, result.Expired, result.OverLimit, result.Compressed,
+		result.BytesBefore, result.BytesAfter, result.BytesReclaimed,
+		time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// runMigrateDB copies a database to a new location, verifies the copy table
+// by table, repoints the desktop config if it used the old file, and leaves a
+// tombstone in the old file so tools still opening it say where data went.
+func runMigrateDB(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("migrate-db", flag.ContinueOnError)
+	from := fs.String("from", "", "current sqlite database path")
+	to := fs.String("to", "", "new sqlite database path (must not exist)")
+	supportDir := fs.String("support-dir", "", "desktop app support dir whose config.json to update (default: the platform support dir)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*from) == "" || strings.TrimSpace(*to) == "" {
+		return fmt.Errorf("-from and -to are required")
+	}
+
+	started := time.Now()
+	sums, err := db.MigrateDatabase(ctx, *from, *to)
+	if err != nil {
+		return err
+	}
+	var rows int64
+	for _, sum := range sums {
+		rows += sum.Rows
+		log.Printf("verified %s: rows=%d checksum=%s", sum.Table, sum.Rows, sum.Checksum[:16])
+	}
+	log.Printf("copied %s to %s: tables=%d rows=%d duration=%s", *from, *to, len(sums), rows, time.Since(started).Round(time.Millisecond))
+
+	dir := strings.TrimSpace(*supportDir)
+	if dir == "" {
+		dir, err = appstate.DefaultSupportDir()
+		if err != nil {
+			return err
 		}
 	}
+	updated, err := appstate.RecordDBMove(dir, *from, *to)
+	if err != nil {
+		return fmt.Errorf("database copied and verified, but updating config failed: %w", err)
+	}
+	if updated {
+		log.Printf("updated %s; restart the desktop app to use the new database", filepath.Join(dir, "config.json"))
+	} else {
+		log.Printf("desktop config does not use %s; left unchanged", *from)
+	}
+	log.Printf("pass -db %s to parse/tail/serve/compact; the old file is kept with a note pointing at the new one", *to)
+	return nil
 }
 
-func runServe(ctx context.Context, args []string) error {
-	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+// runBackup writes a VACUUM INTO copy of the database after an integrity
+// check. It is safe while tail or serve is writing.
+func runBackup(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
 	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
-	addr := fs.String("addr", ":8080", "http listen address")
-	webDist := fs.String("web-dist", "", "path to built frontend dist")
+	out := fs.String("out", "", "backup file to write (must not exist)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if strings.TrimSpace(*out) == "" {
+		return fmt.Errorf("-out is required")
+	}
+	if _, err := os.Stat(*out); err == nil {
+		return fmt.Errorf("%s already exists", *out)
+	}
 
 	database, err := db.Open(*dbPath)
 	if err != nil {
@@ -280,39 +694,1169 @@ func runServe(ctx context.Context, args []string) error {
 	if err := db.Init(ctx, database); err != nil {
 		return err
 	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
 
-	staticDir := *webDist
-	if staticDir == "" {
-		cwd, err := os.Getwd()
-		if err == nil {
-			staticDir = api.DefaultStaticDir(cwd)
-		}
+	started := time.Now()
+	if err := store.IntegrityCheck(ctx); err != nil {
+		return err
 	}
-	if staticDir != "" {
-		staticDir, _ = filepath.Abs(staticDir)
+	if err := store.BackupTo(ctx, *out); err != nil {
+		return err
 	}
+	var size int64
+	if info, err := os.Stat(*out); err == nil {
+		size = info.Size()
+	}
+	log.Printf("backed up %s to %s: bytes=%d duration=%s", *dbPath, *out, size, time.Since(started).Round(time.Millisecond))
+	return nil
+}
 
-	store := db.NewStore(database)
-	currentLogPath, prevLogPath, _ := appstate.DefaultMTGALogPaths()
-	runtimeService, err := appstate.NewService(appstate.Options{
-		Store:              store,
-		DBPath:             *dbPath,
-		DefaultLogPath:     currentLogPath,
-		DefaultPrevLogPath: prevLogPath,
-	})
-	if err != nil {
+// runDoctor checks the database for corruption and inconsistent rows and
+// prints a repair plan; -fix applies the repairs that need no judgment.
+func runDoctor(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	logPath := fs.String("log", "", "arena log path (optional; defaults to the MTGA log path)")
+	fix := fs.Bool("fix", false, "apply the fixable repairs (back up first with `ponder backup`)")
+	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
-	go compactReplays(ctx, store)
-
-	if started, err := runtimeService.MaybeAutoStartLive(); err != nil {
-		log.Printf("auto-start live tracking failed: %v", err)
-	} else if started {
-		log.Printf("live tracking auto-started")
+	// Setup comes first: most "nothing shows up" reports are a missing log
+	// or detailed logs being off, not a damaged database.
+	reportLogDirs()
+	activeLogPath := strings.TrimSpace(*logPath)
+	if activeLogPath == "" {
+		current, _, err := appstate.DefaultMTGALogPaths()
+		if err != nil {
+			return err
+		}
+		activeLogPath = current
+	}
+	failed := 0
+	for _, check := range appstate.CheckEnvironment(activeLogPath, time.Now()) {
+		if reportEnvironmentCheck(check) {
+			failed++
+		}
+	}
+
+	database, err := db.Open(*dbPath)
+	if err == nil {
+		defer database.Close()
+		err = db.Init(ctx, database)
+	}
+	if err != nil {
+		reportEnvironmentCheck(appstate.EnvironmentCheck{
+			Name:   "database_writable",
+			Status: appstate.CheckFail,
+			Detail: err.Error(),
+			Fix:    "make sure " + filepath.Dir(*dbPath) + " exists and this user can write to it",
+		})
+		return fmt.Errorf("cannot open %s", *dbPath)
+	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
+
+	writable := appstate.EnvironmentCheck{Name: "database_writable", Status: appstate.CheckOK, Detail: *dbPath}
+	if err := store.CheckWritable(ctx); err != nil {
+		writable.Status = appstate.CheckFail
+		writable.Detail = err.Error()
+		writable.Fix = "check the file's permissions and free disk space, and stop any other ponder writing to it (the desktop app, or a tail)"
+	}
+	if reportEnvironmentCheck(writable) {
+		failed++
+	}
+
+	report, err := store.Doctor(ctx, *fix)
+	if err != nil {
+		return err
+	}
+	if report.Healthy() {
+		log.Printf("doctor: %s looks healthy", *dbPath)
+	}
+	unfixed := 0
+	for _, issue := range report.Issues {
+		log.Printf("%s: found=%d fixed=%d", issue.Check, issue.Found, issue.Fixed)
+		for _, sample := range issue.Samples {
+			log.Printf("  e.g. %s", sample)
+		}
+		log.Printf("  repair: %s", issue.Repair)
+		if issue.Fixed < issue.Found {
+			unfixed++
+		}
+	}
+	if !*fix && !report.Healthy() {
+		log.Printf("run doctor -fix to apply the fixable repairs")
+	}
+	if unfixed+failed > 0 {
+		return fmt.Errorf("%d check(s) still report problems", unfixed+failed)
+	}
+	return nil
+}
+
+// reportEnvironmentCheck logs one setup check and its fix, returning whether
+// it failed.
+func reportEnvironmentCheck(check appstate.EnvironmentCheck) bool {
+	log.Printf("%s: %s (%s)", check.Name, check.Status, check.Detail)
+	if check.Fix != "" && check.Status != appstate.CheckOK {
+		log.Printf("  fix: %s", check.Fix)
+	}
+	return check.Status == appstate.CheckFail
+}
+
+// reportLogDirs lists every place parse and tail look for Arena's logs, in
+// the order they are tried, so a missing log can be traced to a path.
+func reportLogDirs() {
+	candidates, err := appstate.MTGALogDirCandidates()
+	if err != nil {
+		log.Printf("log paths: %v", err)
+		return
+	}
+	for _, candidate := range candidates {
+		state := "no Player.log"
+		if candidate.Found {
+			state = "found Player.log"
+		}
+		log.Printf("log path checked: %s (%s)", candidate.Dir, state)
+	}
+}
+
+// runExport writes the export tables to files without starting the server.
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	outDir := fs.String("out", "export", "directory to write one file per table into")
+	tables := fs.String("table", strings.Join(export.Tables, ","), "comma-separated tables to export")
+	format := fs.String("format", "csv", "file format: "+strings.Join(export.Formats, ", ")+", or "+strings.Join(export.CollectionFormats, ", ")+" for the collection")
+	eventName := fs.String("event", "", "only matches (and decks and drafts) from this event")
+	result := fs.String("result", "", "only matches with this result")
+	includeArchived := fs.Bool("include-archived", false, "include archived matches")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if _, err := export.NewWriter(io.Discard, *format); err != nil {
+		return err
+	}
+	var selected []string
+	for _, table := range strings.Split(*tables, ",") {
+		table = strings.TrimSpace(table)
+		if table == "" {
+			continue
+		}
+		if !export.TableKnown(table) {
+			return fmt.Errorf("unknown table %q (use %s)", table, strings.Join(export.Tables, ", "))
+		}
+		selected = append(selected, table)
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("-table needs at least one of %s", strings.Join(export.Tables, ", "))
+	}
+	if export.IsCollectionFormat(*format) {
+		tableSet := false
+		fs.Visit(func(f *flag.Flag) { tableSet = tableSet || f.Name == "table" })
+		if !tableSet {
+			selected = []string{"collection"}
+		}
+		if len(selected) != 1 || selected[0] != "collection" {
+			return fmt.Errorf("-format %s only exports the collection table", *format)
+		}
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	filter := db.MatchFilter{
+		EventName:       strings.TrimSpace(*eventName),
+		Result:          strings.TrimSpace(*result),
+		IncludeArchived: *includeArchived,
+	}
+	for _, table := range selected {
+		path := filepath.Join(*outDir, table+export.Extension(*format))
+		rows, err := exportTableToFile(ctx, store, table, filter, *format, path)
+		if err != nil {
+			return fmt.Errorf("export %s: %w", table, err)
+		}
+		log.Printf("export: wrote %d %s rows to %s", rows, table, path)
+	}
+	return nil
+}
+
+func exportTableToFile(ctx context.Context, store *db.Store, table string, filter db.MatchFilter, format, path string) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	buffered := bufio.NewWriter(file)
+	writer, err := export.NewWriter(buffered, format)
+	if err != nil {
+		return 0, err
+	}
+	rows := 0
+	err = export.StreamTable(ctx, store, table, filter, func(row any) error {
+		rows++
+		return writer.Write(row)
+	})
+	if err != nil {
+		return rows, err
+	}
+	if err := writer.Close(); err != nil {
+		return rows, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return rows, err
+	}
+	return rows, file.Close()
+}
+
+// runRestore replaces the database with a verified backup, keeping the
+// current file beside it, and brings the restored copy up to the current
+// schema.
+func runRestore(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path to replace")
+	from := fs.String("from", "", "backup file to restore")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*from) == "" {
+		return fmt.Errorf("-from is required")
+	}
+
+	aside, err := db.RestoreDatabase(ctx, *from, *dbPath)
+	if err != nil {
+		return err
+	}
+	if aside != "" {
+		log.Printf("kept the previous database as %s", aside)
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		return fmt.Errorf("restored, but migrating the restored copy failed: %w", err)
+	}
+	log.Printf("restored %s from %s", *dbPath, *from)
+	return nil
+}
+
+// runMigrate brings the schema to the latest numbered migration, or reverts
+// to -to before running an older build, then lists every migration's state.
+func runMigrate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	to := fs.Int("to", db.LatestMigration(), "migration version to move to; lower versions revert newer migrations")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	// Init applies every pending migration, so only run it when moving up;
+	// reverting works on a database Init has already set up.
+	if *to >= db.LatestMigration() {
+		if err := db.Init(ctx, database); err != nil {
+			return err
+		}
+	}
+	stepped, err := db.MigrateTo(ctx, database, *to)
+	if err != nil {
+		return err
+	}
+	if len(stepped) > 0 {
+		log.Printf("reverted migrations %v", stepped)
+	}
+
+	statuses, err := db.MigrationStatuses(ctx, database)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		state := "pending"
+		if status.AppliedAt != "" {
+			state = "applied " + status.AppliedAt
+		}
+		fmt.Printf("%4d  %-32s %s\n", status.Version, status.Name, state)
+	}
+	return nil
+}
+
+// runCards dispatches card catalog subcommands; "sync" imports Scryfall's
+// bulk card data so API enrichment needs no per-request lookups.
+func runCards(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "sync" {
+		return fmt.Errorf("usage: cards sync -db <path> [-force=false]")
+	}
+	fs := flag.NewFlagSet("cards sync", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	force := fs.Bool("force", false, "download and import even if the bulk file is unchanged since the last sync")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
+
+	started := time.Now()
+	// No overall client timeout: the bulk file is several hundred megabytes.
+	syncer := scryfall.Syncer{Client: &http.Client{}}
+	result, err := syncer.Sync(ctx, store, *force)
+	if err != nil {
+		return err
+	}
+	if result.Skipped {
+		log.Printf("card catalog already matches Scryfall bulk data from %s; pass -force to re-import", result.Version)
+		return nil
+	}
+	log.Printf("imported %d Arena cards from Scryfall bulk data (%s) in %s", result.Cards, result.Version, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// runRatings loads 17Lands card ratings for a set, from a saved CSV or JSON
+// file or else straight from 17Lands, so draft picks can be graded.
+func runRatings(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("usage: ratings import -db <path> -set <code> [-format=%s] [<17lands.csv|json>]", ratings.DefaultFormat)
+	if len(args) == 0 || args[0] != "import" {
+		return usage
+	}
+	fs := flag.NewFlagSet("ratings import", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	setCode := fs.String("set", "", "set code the ratings are for, e.g. BLB")
+	format := fs.String("format", ratings.DefaultFormat, "17Lands format the ratings come from, e.g. PremierDraft or QuickDraft")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	*setCode = strings.ToUpper(strings.TrimSpace(*setCode))
+	if *setCode == "" {
+		return fmt.Errorf("-set is required")
+	}
+	if fs.NArg() > 1 {
+		return usage
+	}
+
+	var cards []db.CardRating
+	if fs.NArg() == 1 {
+		file, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		cards, err = ratings.Read(file)
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", fs.Arg(0), err)
+		}
+	} else {
+		var err error
+		cards, err = ratings.Fetch(ctx, &http.Client{Timeout: time.Minute}, "", *setCode, *format)
+		if err != nil {
+			return err
+		}
+	}
+	if len(cards) == 0 {
+		return fmt.Errorf("no ratings found for %s %s", *setCode, *format)
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
+
+	stored, err := store.ReplaceCardRatings(ctx, *setCode, *format, cards)
+	if err != nil {
+		return err
+	}
+	log.Printf("imported %d %s card ratings for %s", stored, *format, *setCode)
+	return nil
+}
+
+// warnIfMoved flags a database that migrate-db has already copied elsewhere,
+// since new data written here will not reach the live copy.
+func warnIfMoved(ctx context.Context, store *db.Store, dbPath string) {
+	movedTo, err := store.MovedTo(ctx)
+	if err != nil || movedTo == "" {
+		return
+	}
+	log.Printf("warning: %s was migrated to %s; use -db %s", dbPath, movedTo, movedTo)
+}
+
+// defaultLogArchiveEvery spaces log copies; Arena only rotates on launch, so
+// a minute between copies loses nothing.
+const defaultLogArchiveEvery = time.Minute
+
+// runArchive copies Player.log and Player-prev.log into a directory named by
+// each log's start time, once or, with -watch, whenever the log changes.
+func runArchive(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("archive", flag.ContinueOnError)
+	logPath := fs.String("log", "", "arena log path (optional; defaults to the MTGA log path)")
+	outDir := fs.String("out", "", "directory to keep archived logs in")
+	watch := fs.Bool("watch", false, "keep running and archive as the log grows")
+	every := fs.Duration("every", defaultLogArchiveEvery, "with -watch, the least time between copies")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*outDir) == "" {
+		return fmt.Errorf("-out is required")
+	}
+	activeLogPath := strings.TrimSpace(*logPath)
+	if activeLogPath == "" {
+		current, _, err := appstate.DefaultMTGALogPaths()
+		if err != nil {
+			return err
+		}
+		activeLogPath = current
+	}
+
+	archiver := &appstate.LogArchiver{Dir: *outDir, Every: *every}
+	if !*watch {
+		grown, err := archiver.Archive(appstate.LogArchiveSources(activeLogPath), time.Now())
+		for _, path := range grown {
+			log.Printf("archived log to %s", path)
+		}
+		return err
+	}
+
+	// Changes wake a throttled pass; the ticker copies whatever a throttled
+	// wake left behind, such as the last lines before Arena quits.
+	wake, _ := ingest.WatchLog(ctx, activeLogPath, *every)
+	ticker := time.NewTicker(*every)
+	defer ticker.Stop()
+	log.Printf("archiving %s to %s", activeLogPath, *outDir)
+	archiveLogs(archiver, activeLogPath, true)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-wake:
+			archiveLogs(archiver, activeLogPath, false)
+		case <-ticker.C:
+			archiveLogs(archiver, activeLogPath, true)
+		}
+	}
+}
+
+// archiveLogs runs one archive pass, throttled unless now is set, logging
+// rather than failing: a full disk should not stop tailing.
+func archiveLogs(archiver *appstate.LogArchiver, logPath string, now bool) {
+	archive := archiver.MaybeArchive
+	if now {
+		archive = archiver.Archive
+	}
+	grown, err := archive(appstate.LogArchiveSources(logPath), time.Now())
+	if err != nil {
+		log.Printf("log archive failed: %v", err)
+	}
+	for _, path := range grown {
+		log.Printf("archived log to %s", path)
+	}
+}
+
+// tailNotifyEvery is how often tail -notify checks for finished matches,
+// drafts, and milestones between parse passes.
+const tailNotifyEvery = 10 * time.Second
+
+func checkNotifications(ctx context.Context, notifier *appstate.EventNotifier) {
+	if err := notifier.Check(ctx, time.Now()); err != nil && ctx.Err() == nil {
+		log.Printf("notification check failed: %v", err)
+	}
+}
+
+// startWebhooks starts delivering ingest events to the comma-separated
+// urls and returns the sink to hand the parser, or nil when there are none.
+func startWebhooks(ctx context.Context, urls, secret string) (ingest.EventSink, error) {
+	targets := splitList(urls)
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	for _, target := range targets {
+		parsed, err := url.Parse(target)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid webhook url %q (need http:// or https://)", target)
+		}
+	}
+	if strings.TrimSpace(secret) == "" {
+		log.Printf("warning: webhooks are unsigned; set -webhook-secret so receivers can verify them")
+	}
+	dispatcher := webhook.New(targets, secret)
+	go dispatcher.Run(ctx)
+	log.Printf("sending %s webhooks to %s", strings.Join(ingest.EventTypes, ", "), strings.Join(targets, ", "))
+	return dispatcher.Send, nil
+}
+
+// teeEventSinks sends each event to every non-nil sink.
+func teeEventSinks(sinks ...ingest.EventSink) ingest.EventSink {
+	var active []ingest.EventSink
+	for _, sink := range sinks {
+		if sink != nil {
+			active = append(active, sink)
+		}
+	}
+	return func(event ingest.Event) {
+		for _, sink := range active {
+			sink(event)
+		}
+	}
+}
+
+// runDeck prints a stored deck, or the cards an opponent revealed in a
+// match, as Arena import text for pasting into Arena or Moxfield.
+func runDeck(ctx context.Context, args []string) error {
+	const usage = "usage: deck export -db <path> (-id <deck id> | -opponent-of <match id>) [-out <file>]\n       deck push -db <path> -id <deck id> -service moxfield|archidekt"
+	if len(args) > 0 && args[0] == "push" {
+		return runDeckPush(ctx, args[1:])
+	}
+	if len(args) == 0 || args[0] != "export" {
+		return fmt.Errorf(usage)
+	}
+	fs := flag.NewFlagSet("deck export", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	deckID := fs.Int64("id", 0, "deck id to export, as shown in the UI and API")
+	opponentOf := fs.Int64("opponent-of", 0, "export the opponent's revealed cards from this match id instead")
+	out := fs.String("out", "", "write to this file instead of stdout")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if (*deckID > 0) == (*opponentOf > 0) {
+		return fmt.Errorf(usage)
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
+
+	server := api.NewServer(store, "", nil)
+	var text string
+	if *deckID > 0 {
+		text, err = server.DeckImportText(ctx, *deckID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("deck %d not found", *deckID)
+		}
+	} else {
+		text, err = server.OpponentDeckImportText(ctx, *opponentOf)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("no opponent cards recorded for match %d", *opponentOf)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(*out) == "" {
+		fmt.Print(text)
+		return nil
+	}
+	if err := os.WriteFile(*out, []byte(text), 0o644); err != nil {
+		return fmt.Errorf("write decklist: %w", err)
+	}
+	log.Printf("wrote %s", *out)
+	return nil
+}
+
+// runRawLog checks archived or current logs against events_raw, or writes
+// the stored requests back out as a synthetic Player.log for services that
+// take log uploads after the originals rotated away.
+func runRawLog(ctx context.Context, args []string) error {
+	const usage = "usage: rawlog check -db <path> <log>...\n       rawlog write -db <path> -out <file>"
+	if len(args) == 0 || (args[0] != "check" && args[0] != "write") {
+		return fmt.Errorf(usage)
+	}
+	fs := flag.NewFlagSet("rawlog "+args[0], flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	out := fs.String("out", "", "synthetic log to write (write only)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if (args[0] == "check" && fs.NArg() == 0) || (args[0] == "write" && strings.TrimSpace(*out) == "") {
+		return fmt.Errorf(usage)
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
+
+	if args[0] == "write" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("create synthetic log: %w", err)
+		}
+		defer file.Close()
+		written, err := ingest.WriteRawLog(ctx, store, file)
+		if err != nil {
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("write synthetic log: %w", err)
+		}
+		full, err := store.HoldsFullRawEvents(ctx)
+		if err != nil {
+			return err
+		}
+		if !full {
+			log.Printf("wrote %d stored requests to %s (deck submissions and draft picks only; parse with -raw-level full for a log with game messages)", written, *out)
+			return nil
+		}
+		log.Printf("wrote %d stored lines to %s", written, *out)
+		return nil
+	}
+
+	coverage, err := store.RawEventCoverage(ctx)
+	if err != nil {
+		return err
+	}
+	for _, row := range coverage {
+		period := "no timestamps"
+		if row.FirstAt != "" {
+			period = row.FirstAt + " to " + row.LastAt
+		}
+		log.Printf("stored: %s events=%d lines=%d-%d %s", row.LogPath, row.Events, row.FirstLine, row.LastLine, period)
+	}
+	incomplete := 0
+	for _, path := range fs.Args() {
+		check, err := ingest.CheckRawLog(ctx, store, path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		period := "no timestamps"
+		if check.FirstAt != "" {
+			period = check.FirstAt + " to " + check.LastAt
+		}
+		storedPeriod := "none"
+		if check.StoredFirstAt != "" {
+			storedPeriod = check.StoredFirstAt + " to " + check.StoredLastAt
+		}
+		var problems []string
+		if check.Stored < check.Events {
+			problems = append(problems, fmt.Sprintf("MISSING %d (lines %v)", check.Events-check.Stored, check.Missing))
+		}
+		if len(check.Mistimed) > 0 {
+			problems = append(problems, fmt.Sprintf("MISTIMED lines %v", check.Mistimed))
+		}
+		status := "ok"
+		if len(problems) > 0 {
+			status = strings.Join(problems, "; ")
+			incomplete++
+		}
+		if check.Untimed > 0 {
+			status += fmt.Sprintf(" (%d stored without a timestamp)", check.Untimed)
+		}
+		log.Printf("%s: %s lines=%d requests=%d stored=%d stored-period=%s %s", path, period, check.Lines, check.Events, check.Stored, storedPeriod, status)
+	}
+	if incomplete > 0 {
+		return fmt.Errorf("%d of %d logs have requests missing from events_raw or stored under other times", incomplete, fs.NArg())
+	}
+	return nil
+}
+
+// runDeckPush sends a deck to Moxfield or Archidekt with the token saved in
+// settings. Without a token it prints the import text and the site's import
+// page instead, so the deck can still be pasted in by hand.
+func runDeckPush(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("deck push", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	deckID := fs.Int64("id", 0, "deck id to push, as shown in the UI and API")
+	serviceName := fs.String("service", "", "site to push to: "+strings.Join(decksync.Names(), ", "))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *deckID <= 0 || strings.TrimSpace(*serviceName) == "" {
+		return fmt.Errorf("usage: deck push -db <path> -id <deck id> -service %s", strings.Join(decksync.Names(), "|"))
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	store := db.NewStore(database)
+	warnIfMoved(ctx, store, *dbPath)
+
+	server := api.NewServer(store, "", nil)
+	result, err := server.PushDeck(ctx, *deckID, *serviceName)
+	if errors.Is(err, api.ErrNoDeckServiceToken) {
+		service, _ := server.DeckService(*serviceName)
+		text, err := server.DeckImportText(ctx, *deckID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("deck %d not found", *deckID)
+		}
+		if err != nil {
+			return err
+		}
+		log.Printf("no %s token saved (set %sToken in settings); paste this at %s", service.Name, service.Name, service.ImportPage)
+		fmt.Print(text)
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("deck %d not found", *deckID)
+	}
+	if err != nil {
+		return err
+	}
+	verb := "updated"
+	if result.Created {
+		verb = "created"
+	}
+	log.Printf("%s %s deck %s: %s", verb, result.Service, result.RemoteID, result.URL)
+	return nil
+}
+
+// runService installs, removes, or reports the login service: a launchd
+// agent on macOS or a Task Scheduler task on Windows running `serve -live`,
+// so tracking and the web UI are up without a terminal.
+func runService(args []string) error {
+	const usage = "usage: service install|uninstall|status [-db <path>] [-addr=127.0.0.1:8080]"
+	if len(args) == 0 {
+		return fmt.Errorf(usage)
+	}
+	switch args[0] {
+	case "status":
+		status := appstate.GetDaemonStatus()
+		switch {
+		case !status.Supported:
+			log.Printf("%s", status.Note)
+		case status.Installed:
+			log.Printf("login service installed: %s", status.Path)
+		default:
+			log.Printf("login service not installed")
+		}
+		return nil
+	case "uninstall":
+		status, err := appstate.UninstallDaemon()
+		if err != nil {
+			return err
+		}
+		log.Printf("removed login service %s", status.Path)
+		return nil
+	case "install":
+	default:
+		return fmt.Errorf(usage)
+	}
+
+	fs := flag.NewFlagSet("service install", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "sqlite database path (default: the desktop app's database)")
+	addr := fs.String("addr", "127.0.0.1:8080", "http listen address for the service")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	path := strings.TrimSpace(*dbPath)
+	if path == "" {
+		supportDir, err := appstate.DefaultSupportDir()
+		if err != nil {
+			return err
+		}
+		if path, err = appstate.DesktopDBPath(supportDir); err != nil {
+			return err
+		}
+	}
+	// The service starts outside any shell, so relative paths would resolve
+	// against / (launchd) or System32 (Task Scheduler).
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve db path: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create db dir: %w", err)
+	}
+	exe, err := appstate.ResolveDaemonExecutable()
+	if err != nil {
+		return err
+	}
+
+	cfg := appstate.DaemonConfig{
+		Executable: exe,
+		Args:       []string{"serve", "-live", "-db", path, "-addr", *addr},
+		LogPath:    filepath.Join(filepath.Dir(path), "ponder-service.log"),
+	}
+	status, err := appstate.InstallDaemon(cfg)
+	if err != nil {
+		return err
+	}
+	log.Printf("installed login service %s: %s %s", status.Path, exe, strings.Join(cfg.Args, " "))
+	log.Printf("it is running now and starts at every login; open http://%s", *addr)
+	return nil
+}
+
+func runTail(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	logPath := fs.String("log", "", "arena log path (optional; defaults to MTGA macOS Player.log)")
+	interval := fs.Duration("interval", 2*time.Second, "poll interval where filesystem notifications are unavailable")
+	verbose := fs.Bool("verbose", false, "log each poll, including idle polls")
+	archiveDir := fs.String("archive-dir", "", "also copy Player.log and Player-prev.log into this directory, so no log is lost to rotation")
+	notify := fs.String("notify", "", "comma-separated events to show desktop notifications for: "+strings.Join(appstate.NotifyEvents, ", "))
+	webhookURLs := fs.String("webhook", "", "comma-separated URLs to POST ingest events to as JSON ("+strings.Join(ingest.EventTypes, ", ")+")")
+	webhookSecret := fs.String("webhook-secret", os.Getenv("PONDER_WEBHOOK_SECRET"), "sign webhooks with HMAC-SHA256 under this secret (default $PONDER_WEBHOOK_SECRET)")
+	rawLevelName := fs.String("raw-level", "minimal", "what to keep in events_raw: "+strings.Join(db.RawEventLevels, ", "))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	rawLevel, err := db.ParseRawEventLevel(*rawLevelName)
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	warnIfMoved(ctx, db.NewStore(database), *dbPath)
+
+	store := db.NewStore(database)
+	store.SetRawEventLevel(rawLevel)
+	parser := ingest.NewParser(store)
+	sink, err := startWebhooks(ctx, *webhookURLs, *webhookSecret)
+	if err != nil {
+		return err
+	}
+	parser.SetEventSink(sink)
+	notifier, err := appstate.NewEventNotifier(db.NewStore(database), appstate.DesktopNotifier{}, splitList(*notify))
+	if err != nil {
+		return err
+	}
+	activeLogPath := strings.TrimSpace(*logPath)
+	if activeLogPath == "" {
+		current, _, err := appstate.DefaultMTGALogPaths()
+		if err != nil {
+			return err
+		}
+		activeLogPath = current
+	}
+	if _, err := os.Stat(activeLogPath); err != nil {
+		return fmt.Errorf("tail log path not found: %s (%w)", activeLogPath, err)
+	}
+
+	go compactReplays(ctx, db.NewStore(database))
+
+	wake, notifying := ingest.WatchLog(ctx, activeLogPath, *interval)
+	if notifying {
+		log.Printf("tailing %s on change", activeLogPath)
+	} else {
+		log.Printf("tailing %s every %s", activeLogPath, interval.String())
+	}
+
+	var archiver *appstate.LogArchiver
+	var archiveTick <-chan time.Time
+	if dir := strings.TrimSpace(*archiveDir); dir != "" {
+		archiver = &appstate.LogArchiver{Dir: dir, Every: defaultLogArchiveEvery}
+		ticker := time.NewTicker(defaultLogArchiveEvery)
+		defer ticker.Stop()
+		archiveTick = ticker.C
+		log.Printf("archiving logs to %s", dir)
+	}
+	// Checked on a timer as well as after each pass, so a match held back
+	// waiting for its rank still notifies if Arena goes quiet.
+	var notifyTick <-chan time.Time
+	if notifier.Enabled() {
+		ticker := time.NewTicker(tailNotifyEvery)
+		defer ticker.Stop()
+		notifyTick = ticker.C
+		log.Printf("desktop notifications on for %s", *notify)
+	}
+
+	for {
+		if archiver != nil {
+			archiveLogs(archiver, activeLogPath, false)
+		}
+		results, err := parser.TailFile(ctx, activeLogPath)
+		if err != nil {
+			log.Printf("tail parse error: %v", err)
+		}
+		for _, stats := range results {
+			hasActivity := stats.LinesRead > 0 ||
+				stats.RawEventsStored > 0 ||
+				stats.MatchesUpserted > 0 ||
+				stats.EconomySnapshots > 0 ||
+				stats.DecksUpserted > 0 ||
+				stats.DraftPicksAdded > 0
+
+			if stats.LogPath != activeLogPath {
+				log.Printf("tail caught up rotated log %s", stats.LogPath)
+			}
+			if hasActivity {
+				log.Printf(
+					"tail activity: lines=%d bytes=%d raw_events=%d matches=%d economy_snapshots=%d decks=%d draft_picks=%d skipped=%d duration=%s",
+					stats.LinesRead,
+					stats.BytesRead,
+					stats.RawEventsStored,
+					stats.MatchesUpserted,
+					stats.EconomySnapshots,
+					stats.DecksUpserted,
+					stats.DraftPicksAdded,
+					stats.LinesSkipped,
+					stats.CompletedAt.Sub(stats.StartedAt),
+				)
+			} else if *verbose && err == nil {
+				log.Printf("tail idle: no new lines")
+			}
+		}
+		if notifier.Enabled() {
+			checkNotifications(ctx, notifier)
+		}
+
+		for waiting := true; waiting; {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-wake:
+				waiting = false
+			case <-archiveTick:
+				archiveLogs(archiver, activeLogPath, true)
+			case <-notifyTick:
+				checkNotifications(ctx, notifier)
+			}
+		}
+	}
+}
+
+func runServe(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDBPath, "sqlite database path")
+	addr := fs.String("addr", "127.0.0.1:8080", "http listen address (use :8080 to listen on all interfaces, or unix:/path/to.sock for a Unix domain socket)")
+	webDist := fs.String("web-dist", "", "path to built frontend dist (default: ./web/dist when present, else the copy embedded in the binary)")
+	accessLog := fs.Bool("access-log", true, "log one line per HTTP request (method, path, status, duration)")
+	basePath := fs.String("base-path", "", "serve every route under this path prefix (e.g. /mtga) for a shared reverse proxy")
+	apiKey := fs.String("api-key", os.Getenv("PONDER_API_KEY"), "require this bearer token on /api/* requests (default $PONDER_API_KEY)")
+	tlsCert := fs.String("tls-cert", "", "PEM certificate file; serve HTTPS when set with -tls-key")
+	tlsKey := fs.String("tls-key", "", "PEM private key file for -tls-cert")
+	tlsSelfSigned := fs.Bool("tls-self-signed", false, "serve HTTPS with a generated self-signed certificate stored next to the database")
+	allowedOrigins := fs.String("allowed-origins", "", "comma-separated extra browser origins granted CORS access (e.g. http://nas.lan:8080)")
+	live := fs.Bool("live", false, "also tail the MTGA log, starting once it exists, as the login service does")
+	webhookURLs := fs.String("webhook", "", "comma-separated URLs to POST live-tracking events to as JSON ("+strings.Join(ingest.EventTypes, ", ")+")")
+	webhookSecret := fs.String("webhook-secret", os.Getenv("PONDER_WEBHOOK_SECRET"), "sign webhooks with HMAC-SHA256 under this secret (default $PONDER_WEBHOOK_SECRET)")
+	pickAdvisor := fs.String("pick-advisor", "", "URL of an external draft pick advisor to POST each pack and pool to (default: rank by imported 17Lands ratings)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := db.Init(ctx, database); err != nil {
+		return err
+	}
+	warnIfMoved(ctx, db.NewStore(database), *dbPath)
+
+	// An explicit -web-dist wins, then a checkout's web/dist (fresher than
+	// the embedded copy while developing), then the build embedded in the
+	// binary, so an installed binary needs no external files.
+	staticDir := *webDist
+	var embeddedAssets iofs.FS
+	if staticDir == "" {
+		if cwd, err := os.Getwd(); err == nil {
+			if fi, err := os.Stat(api.DefaultStaticDir(cwd)); err == nil && fi.IsDir() {
+				staticDir = api.DefaultStaticDir(cwd)
+			}
+		}
+		if staticDir == "" {
+			if assets, err := web.Dist(); err == nil {
+				embeddedAssets = assets
+			} else {
+				log.Printf("embedded frontend unavailable: %v", err)
+			}
+		}
+	}
+	if staticDir != "" {
+		staticDir, _ = filepath.Abs(staticDir)
+	}
+
+	// API reads get their own pool so they never wait on a connection held
+	// by live-tracking writes.
+	reader, err := db.OpenReader(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	store := db.NewStore(database)
+	store.SetReader(reader)
+	sink, err := startWebhooks(ctx, *webhookURLs, *webhookSecret)
+	if err != nil {
+		return err
+	}
+	// Decks and drafts live tracking stores have their card images fetched
+	// in the background; a full queue drops batches rather than stall
+	// parsing, and the UI fetches whatever was missed on demand.
+	imagePrefetch := make(chan []int64, 64)
+	sink = teeEventSinks(sink, func(event ingest.Event) {
+		if ids := ingest.EventCardIDs(event); len(ids) > 0 {
+			select {
+			case imagePrefetch <- ids:
+			default:
+			}
+		}
+	})
+	currentLogPath, prevLogPath, _ := appstate.DefaultMTGALogPaths()
+	runtimeService, err := appstate.NewService(appstate.Options{
+		Store:              store,
+		DBPath:             *dbPath,
+		DefaultLogPath:     currentLogPath,
+		DefaultPrevLogPath: prevLogPath,
+		EventSink:          sink,
+	})
+	if err != nil {
+		return err
+	}
+
+	go compactReplays(ctx, store)
+
+	if *live {
+		go startLiveWhenLogExists(ctx, runtimeService)
+	} else if started, err := runtimeService.MaybeAutoStartLive(); err != nil {
+		log.Printf("auto-start live tracking failed: %v", err)
+	} else if started {
+		log.Printf("live tracking auto-started")
 	}
 
 	server := api.NewServer(store, staticDir, runtimeService)
+	if embeddedAssets != nil {
+		server.SetStaticAssets(embeddedAssets)
+	}
+	server.SetAllowedOrigins(splitList(*allowedOrigins))
+	server.SetAPIKey(*apiKey)
+	server.SetBasePath(*basePath)
+	server.SetAccessLog(*accessLog)
+	server.SetImageCacheDir(filepath.Join(filepath.Dir(*dbPath), "card-images"))
+	if *pickAdvisor != "" {
+		if parsed, err := url.Parse(*pickAdvisor); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return fmt.Errorf("-pick-advisor must be an http(s) URL")
+		}
+		server.SetPickAdvisor(api.HTTPAdvisor{URL: *pickAdvisor, Client: &http.Client{Timeout: 5 * time.Second}})
+	}
+	if err := configureTLS(server, *dbPath, *addr, *tlsCert, *tlsKey, *tlsSelfSigned); err != nil {
+		return err
+	}
+	if _, isUnix := api.UnixSocketPath(*addr); !isUnix && !api.IsLoopbackAddr(*addr) && strings.TrimSpace(*apiKey) == "" {
+		log.Printf("warning: %s accepts non-local connections; match history is readable by anyone who can reach it (set -api-key to require a token)", *addr)
+	}
+	go server.PrefetchCardImages(ctx, imagePrefetch)
 	server.StartUpdateChecker(ctx)
+	runtimeService.StartNightlyBackups(ctx)
 	return server.Run(ctx, *addr)
 }
+
+// liveStartRetry is how often serve -live looks for the MTGA log before
+// Arena has created it, as at login.
+const liveStartRetry = 30 * time.Second
+
+// startLiveWhenLogExists starts live tracking, retrying until the log
+// appears so a service started at login picks up Arena's first launch.
+func startLiveWhenLogExists(ctx context.Context, service *appstate.Service) {
+	ticker := time.NewTicker(liveStartRetry)
+	defer ticker.Stop()
+	for {
+		if _, err := service.StartLive(); err == nil {
+			log.Printf("live tracking started")
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// configureTLS wires -tls-cert/-tls-key, or generates (and reuses) a
+// self-signed pair under <db dir>/tls for -tls-self-signed.
+func configureTLS(server *api.Server, dbPath, addr, certFile, keyFile string, selfSigned bool) error {
+	if (certFile == "") != (keyFile == "") {
+		return fmt.Errorf("-tls-cert and -tls-key must be set together")
+	}
+	if selfSigned {
+		if certFile != "" {
+			return fmt.Errorf("-tls-self-signed cannot be combined with -tls-cert")
+		}
+		dir := filepath.Join(filepath.Dir(dbPath), "tls")
+		certFile = filepath.Join(dir, "ponder-cert.pem")
+		keyFile = filepath.Join(dir, "ponder-key.pem")
+		var hosts []string
+		if host, _, err := net.SplitHostPort(addr); err == nil && host != "" {
+			hosts = append(hosts, host)
+		}
+		if hostname, err := os.Hostname(); err == nil {
+			hosts = append(hosts, hostname)
+		}
+		if err := api.EnsureSelfSignedCert(certFile, keyFile, hosts); err != nil {
+			return err
+		}
+		log.Printf("using self-signed certificate %s", certFile)
+	}
+	if certFile != "" {
+		server.SetTLS(certFile, keyFile)
+	}
+	return nil
+}
+
+// splitList parses a comma-separated flag value, dropping blank entries.
+func splitList(raw string) []string {
+	out := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -3,6 +3,7 @@ module github.com/solean/ponder
 go 1.25.0
 
 require (
+	github.com/fsnotify/fsnotify v1.9.0
 	github.com/klauspost/compress v1.18.6
 	github.com/wailsapp/wails/v2 v2.13.0
 	modernc.org/sqlite v1.39.1
@@ -7,7 +7,7 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 // wubrgOrder sorts color identity strings into canonical WUBRG order so
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// SetAccessLog turns on one structured log line per request: method, path,
+// status, and duration.
+func (s *Server) SetAccessLog(enabled bool) {
+	s.accessLog = enabled
+}
+
+func (s *Server) withAccessLog(next http.Handler) http.Handler {
+	if !s.accessLog {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w}
+		started := time.Now()
+		// Deferred so requests aborted by a panic are still logged.
+		defer func() {
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			slog.Info("http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"duration_ms", float64(time.Since(started).Microseconds())/1000,
+			)
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// withRecovery turns a handler panic into a 500 JSON error and logs the
+// stack, so one bad handler fails its own request rather than the process.
+// Once a response has started its status can't change, so the connection
+// is aborted instead.
+func withRecovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			log.Printf("panic serving %s %s: %v\n%s", r.Method, r.URL.Path, recovered, debug.Stack())
+			if recorder.status != 0 {
+				panic(http.ErrAbortHandler)
+			}
+			writeError(recorder, http.StatusInternalServerError, "internal server error")
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
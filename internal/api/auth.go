@@ -0,0 +1,53 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// SetAPIKey requires every /api/* (and /metrics) request to carry
+// "Authorization: Bearer <key>". An empty key (the default) leaves the API
+// open, which is fine while the server only listens on localhost.
+func (s *Server) SetAPIKey(key string) {
+	s.apiKey = strings.TrimSpace(key)
+}
+
+// withAPIKey enforces the configured bearer token on API routes. Static
+// frontend assets stay public so the app can load and then authenticate its
+// own requests; CORS preflights are answered before this runs. Card images
+// are public Scryfall art loaded by <img> tags, which cannot send the header.
+func (s *Server) withAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.apiKey == "" || (!strings.HasPrefix(r.URL.Path, "/api/") && r.URL.Path != "/metrics") || isCardImagePath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, ok := bearerToken(r.Header.Get("Authorization"))
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.apiKey)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ponder"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// queryKeyValid checks the API key passed as ?key=, for pages that clients
+// such as OBS and calendar apps load without an Authorization header.
+func (s *Server) queryKeyValid(r *http.Request) bool {
+	return s.apiKey == "" || subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("key")), []byte(s.apiKey)) == 1
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func isCardImagePath(path string) bool {
+	return strings.HasPrefix(path, "/api/cards/") && strings.HasSuffix(path, "/image")
+}
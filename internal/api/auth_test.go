@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAPIKeyGuardsAPIRoutes(t *testing.T) {
+	server := NewServer(nil, "", nil)
+	server.SetStaticAssets(fstest.MapFS{"index.html": {Data: []byte("<html>app</html>")}})
+	server.SetAPIKey("s3cret")
+	handler := server.Handler()
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		auth       string
+		wantStatus int
+	}{
+		{"missing token", http.MethodGet, "/api/nope", "", http.StatusUnauthorized},
+		{"wrong token", http.MethodGet, "/api/nope", "Bearer nope", http.StatusUnauthorized},
+		{"wrong scheme", http.MethodGet, "/api/nope", "Basic s3cret", http.StatusUnauthorized},
+		{"valid token reaches route", http.MethodGet, "/api/nope", "bearer s3cret", http.StatusNotFound},
+		{"preflight skips auth", http.MethodOptions, "/api/nope", "", http.StatusNoContent},
+		{"frontend stays public", http.MethodGet, "/matches/1", "", http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.auth != "" {
+				req.Header.Set("Authorization", tc.auth)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("%s %s: status = %d, want %d", tc.method, tc.path, rec.Code, tc.wantStatus)
+			}
+			if tc.wantStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Fatal("401 response is missing WWW-Authenticate")
+			}
+		})
+	}
+}
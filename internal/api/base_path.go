@@ -0,0 +1,68 @@
+package api
+
+import (
+	"bytes"
+	"html"
+	"net/http"
+	"strings"
+)
+
+// SetBasePath mounts every route (API, /metrics, and the frontend) under
+// prefix, e.g. "/mtga", for a shared reverse proxy that forwards the
+// sub-path as-is. Empty or "/" mounts at the root.
+func (s *Server) SetBasePath(prefix string) {
+	s.basePath = normalizeBasePath(prefix)
+}
+
+func normalizeBasePath(prefix string) string {
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+// withBasePath strips the base path before routing. The bare prefix
+// redirects to prefix/ so relative URLs resolve; anything outside the
+// prefix is 404.
+func (s *Server) withBasePath(next http.Handler) http.Handler {
+	if s.basePath == "" {
+		return next
+	}
+	stripped := http.StripPrefix(s.basePath, next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == s.basePath:
+			target := s.basePath + "/"
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+		case strings.HasPrefix(r.URL.Path, s.basePath+"/"):
+			stripped.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// rebaseIndexHTML points index.html's <base href> at the base path. The
+// build's asset URLs are relative to it, and the frontend reads its router
+// basename and API prefix from document.baseURI. Builds without a base tag
+// get one right after <head>.
+func rebaseIndexHTML(page []byte, basePath string) []byte {
+	baseHref := []byte(`<base href="` + html.EscapeString(basePath+"/") + `"`)
+	if bytes.Contains(page, []byte(`<base href="/"`)) {
+		return bytes.Replace(page, []byte(`<base href="/"`), baseHref, 1)
+	}
+	head := bytes.Index(page, []byte("<head>"))
+	if head < 0 {
+		return page
+	}
+	insertAt := head + len("<head>")
+	out := make([]byte, 0, len(page)+len(baseHref)+2)
+	out = append(out, page[:insertAt]...)
+	out = append(out, baseHref...)
+	out = append(out, " />"...)
+	return append(out, page[insertAt:]...)
+}
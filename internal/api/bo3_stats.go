@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// handleBo3Stats serves GET /api/stats/bo3: best-of-three match win rate
+// next to game win rate, and game one next to post-board games, overall and
+// per deck. A post-board rate well off game one's is the sideboarding
+// signal, so each rate carries a 95% interval.
+func (s *Server) handleBo3Stats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	out, err := s.store.Bo3Records(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fillBo3Split(&out.Overall)
+	for index := range out.Decks {
+		fillBo3Split(&out.Decks[index].Bo3Split)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func fillBo3Split(split *model.Bo3Split) {
+	fillWinRateInterval(&split.Matches)
+	fillWinRateInterval(&split.Games)
+	fillWinRateInterval(&split.GameOne)
+	fillWinRateInterval(&split.PostBoard)
+	if split.GameOne.WinRate != nil && split.PostBoard.WinRate != nil {
+		delta := *split.PostBoard.WinRate - *split.GameOne.WinRate
+		split.PostBoardDelta = &delta
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestBo3StatsSplitsMatchGameAndPostBoardRecords(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// Matches 1-3 are Bo3 for Control; match 3 has no game results yet and
+	// match 4 is best-of-one, so it is left out.
+	for _, query := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, format, created_at, updated_at) VALUES (1, 'deck-control', 'Control', 'Traditional Standard', 'x', 'x')`,
+		`INSERT INTO matches (id, arena_match_id, event_name, format, result, created_at, updated_at) VALUES
+			(1, 'm1', 'Traditional_Ladder', 'bo3', 'win', 'x', 'x'),
+			(2, 'm2', 'Traditional_Ladder', 'bo3', 'loss', 'x', 'x'),
+			(3, 'm3', 'Traditional_Ladder', 'bo3', 'win', 'x', 'x'),
+			(4, 'm4', 'Ladder', 'bo1', 'win', 'x', 'x')`,
+		`INSERT INTO match_decks (match_id, deck_id, snapshot_reason, created_at) VALUES
+			(1, 1, 'x', 'x'), (2, 1, 'x', 'x'), (3, 1, 'x', 'x'), (4, 1, 'x', 'x')`,
+		`INSERT INTO games (match_id, game_number, result, derived_at) VALUES
+			(1, 1, 'loss', 'x'), (1, 2, 'win', 'x'), (1, 3, 'win', 'x'),
+			(2, 1, 'win', 'x'), (2, 2, 'loss', 'x'), (2, 3, 'loss', 'x'),
+			(3, 1, 'unknown', 'x'),
+			(4, 1, 'win', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(db.NewStore(database), "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/bo3", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var stats model.Bo3Stats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	overall := stats.Overall
+	if overall.Matches.Record.Games != 3 || overall.Matches.Record.Wins != 2 || stats.MatchesWithoutGames != 1 {
+		t.Fatalf("matches = %+v, without games %d; want 2-1 with one missing games", overall.Matches.Record, stats.MatchesWithoutGames)
+	}
+	if overall.Games.Record.Games != 6 || overall.Games.Record.Wins != 3 {
+		t.Fatalf("games = %+v, want 3-3", overall.Games.Record)
+	}
+	if overall.GameOne.Record.Wins != 1 || overall.GameOne.Record.Games != 2 ||
+		overall.PostBoard.Record.Wins != 2 || overall.PostBoard.Record.Games != 4 {
+		t.Fatalf("game one %+v post-board %+v, want 1-1 and 2-2", overall.GameOne.Record, overall.PostBoard.Record)
+	}
+	if overall.PostBoardDelta == nil || math.Abs(*overall.PostBoardDelta) > 1e-9 {
+		t.Fatalf("post-board delta = %v, want 0", overall.PostBoardDelta)
+	}
+	if len(stats.Decks) != 1 || stats.Decks[0].DeckName != "Control" || stats.Decks[0].Matches.Record.Games != 3 ||
+		stats.Decks[0].Matches.WinRate == nil {
+		t.Fatalf("decks = %+v, want Control's three Bo3 matches with rates filled", stats.Decks)
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/db"
+)
+
+const (
+	// calendarDefaultDays is how far back the feed goes without ?days=.
+	calendarDefaultDays = 90
+	calendarMaxDays     = 3650
+	// calendarDefaultGap is the break between matches, in minutes, that
+	// ends a play session; it leaves room for deck building and queues.
+	calendarDefaultGap = 30
+)
+
+// handleCalendar serves /calendar.ics, an iCalendar feed with one event per
+// play session: a block of matches, with its match count and record, so a
+// calendar app subscribed to it shows the time spent playing. ?days= and
+// ?gap= (minutes) tune the window and what splits sessions. Calendar apps
+// cannot send an Authorization header, so with an API key set the key is
+// passed as ?key=, as for overlays.
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.queryKeyValid(r) {
+		http.Error(w, "missing or invalid key", http.StatusUnauthorized)
+		return
+	}
+	days, err := boundedQueryInt(r, "days", calendarDefaultDays, calendarMaxDays)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	gap, err := boundedQueryInt(r, "gap", calendarDefaultGap, 24*60)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := s.store.Clock().Now()
+	sessions, err := s.store.PlaySessions(r.Context(), now.AddDate(0, 0, -days), time.Duration(gap)*time.Minute)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write([]byte(playSessionCalendar(sessions, now)))
+}
+
+// boundedQueryInt reads a positive integer query parameter no larger than
+// limit, or fallback when it is absent.
+func boundedQueryInt(r *http.Request, name string, fallback, limit int) (int, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(name))
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 || value > limit {
+		return 0, fmt.Errorf("%s must be between 1 and %d", name, limit)
+	}
+	return value, nil
+}
+
+// playSessionCalendar renders sessions as an RFC 5545 calendar. Each event's
+// UID is its start time, so a refreshed feed updates a session that grew
+// rather than adding a second one.
+func playSessionCalendar(sessions []db.PlaySession, now time.Time) string {
+	var b strings.Builder
+	line := func(text string) {
+		b.WriteString(foldCalendarLine(text))
+		b.WriteString("\r\n")
+	}
+	stamp := now.UTC().Format(calendarTimeLayout)
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//ponder//Play sessions//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:MTG Arena")
+	for _, session := range sessions {
+		end := session.End
+		if !end.After(session.Start) {
+			end = session.Start.Add(time.Minute)
+		}
+		record := fmt.Sprintf("%d-%d", session.Wins, session.Losses)
+		if session.Draws > 0 {
+			record += fmt.Sprintf("-%d", session.Draws)
+		}
+		matches := "matches"
+		if session.Matches == 1 {
+			matches = "match"
+		}
+		events := make([]string, 0, len(session.Events))
+		for _, name := range session.Events {
+			events = append(events, eventnames.Display(name, eventnames.DefaultLanguage, ""))
+		}
+
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:session-%d@ponder", session.Start.Unix()))
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + session.Start.UTC().Format(calendarTimeLayout))
+		line("DTEND:" + end.UTC().Format(calendarTimeLayout))
+		line("SUMMARY:" + escapeCalendarText(fmt.Sprintf("MTG Arena: %d %s, %s", session.Matches, matches, record)))
+		description := fmt.Sprintf("%d %s (%s) over %s.", session.Matches, matches, record, end.Sub(session.Start).Round(time.Minute))
+		if len(events) > 0 {
+			description += "\nEvents: " + strings.Join(events, ", ")
+		}
+		line("DESCRIPTION:" + escapeCalendarText(description))
+		line("TRANSP:OPAQUE")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+const calendarTimeLayout = "20060102T150405Z"
+
+// escapeCalendarText escapes a TEXT value per RFC 5545 section 3.3.11.
+func escapeCalendarText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldCalendarLine splits a content line longer than 75 octets into
+// continuation lines, without cutting a UTF-8 character in two.
+func foldCalendarLine(text string) string {
+	const limit = 75
+	if len(text) <= limit {
+		return text
+	}
+	var b strings.Builder
+	width := limit
+	for len(text) > width {
+		cut := width
+		for cut > 0 && text[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(text[:cut])
+		b.WriteString("\r\n ")
+		text = text[cut:]
+		// The leading space of a continuation line counts toward its 75.
+		width = limit - 1
+	}
+	b.WriteString(text)
+	return b.String()
+}
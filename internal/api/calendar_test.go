@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestCalendarFeedGroupsMatchesIntoPlaySessions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// Two matches 10 minutes apart, then one three hours later, all within
+	// the feed's window; the 2020 match is outside it.
+	if _, err := database.ExecContext(ctx, `INSERT INTO matches (arena_match_id, event_name, started_at, ended_at, result, created_at, updated_at) VALUES
+		('m1', 'Ladder', '2026-03-05T19:00:00Z', '2026-03-05T19:20:00Z', 'win', 'x', 'x'),
+		('m2', 'Ladder', '2026-03-05T19:30:00Z', '2026-03-05T19:50:00Z', 'loss', 'x', 'x'),
+		('m3', 'Ladder', '2026-03-05T22:50:00Z', '2026-03-05T23:05:00Z', 'win', 'x', 'x'),
+		('old', 'Ladder', '2020-01-01T12:00:00Z', '2020-01-01T12:20:00Z', 'win', 'x', 'x')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	store := db.NewStore(database)
+	store.SetClock(db.FixedClock(time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC)))
+	server := NewServer(store, "", nil)
+	server.SetAPIKey("k3y")
+	handler := server.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("feed without key status = %d, want 401", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics?key=k3y", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/calendar") {
+		t.Fatalf("feed status = %d, content type %q; body: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+	body := rec.Body.String()
+	if got := strings.Count(body, "BEGIN:VEVENT"); got != 2 {
+		t.Fatalf("feed has %d events, want 2 sessions; body: %s", got, body)
+	}
+	for _, want := range []string{
+		"DTSTART:20260305T190000Z\r\nDTEND:20260305T195000Z\r\n",
+		`SUMMARY:MTG Arena: 2 matches\, 1-1`,
+		`SUMMARY:MTG Arena: 1 match\, 1-0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("feed missing %q; body: %s", want, body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics?key=k3y&gap=240", nil))
+	if got := strings.Count(rec.Body.String(), "BEGIN:VEVENT"); got != 1 {
+		t.Fatalf("feed with a 4h gap has %d events, want 1", got)
+	}
+}
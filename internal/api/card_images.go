@@ -0,0 +1,255 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	scryfallArenaCardURL = "https://api.scryfall.com/cards/arena"
+	// cardImageMaxBytes bounds a single download; Scryfall's largest PNGs are
+	// around 1.5MB.
+	cardImageMaxBytes = 8 << 20
+	// cardImageMaxAge lets browsers keep art for a month; Scryfall only
+	// replaces an image when a better scan appears.
+	cardImageMaxAge = 30 * 24 * time.Hour
+)
+
+// cardImageVersions are the Scryfall image sizes the proxy will fetch, with
+// the file extension each is served as.
+var cardImageVersions = map[string]string{
+	"small":       ".jpg",
+	"normal":      ".jpg",
+	"large":       ".jpg",
+	"png":         ".png",
+	"art_crop":    ".jpg",
+	"border_crop": ".jpg",
+}
+
+var errCardImageNotFound = errors.New("card image not found")
+
+// SetImageCacheDir stores proxied card images under dir so each one is only
+// fetched from Scryfall once. Without it images are fetched per request.
+func (s *Server) SetImageCacheDir(dir string) {
+	s.imageCacheDir = strings.TrimSpace(dir)
+}
+
+// handleCardImage serves /api/cards/{grpId}/image?version=normal from the
+// disk cache, fetching it from Scryfall on a miss.
+func (s *Server) handleCardImage(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/cards/"), "/"), "/")
+	if len(parts) != 2 || parts[1] != "image" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	cardID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || cardID <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid card id")
+		return
+	}
+	version := strings.TrimSpace(r.URL.Query().Get("version"))
+	if version == "" {
+		version = "normal"
+	}
+	ext, ok := cardImageVersions[version]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "version must be one of small, normal, large, png, art_crop, border_crop")
+		return
+	}
+
+	image, modTime, err := s.loadCardImage(r.Context(), cardID, version, ext)
+	if errors.Is(err, errCardImageNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", http.DetectContentType(image))
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(cardImageMaxAge.Seconds())))
+	http.ServeContent(w, r, strconv.FormatInt(cardID, 10)+ext, modTime, bytes.NewReader(image))
+}
+
+// loadCardImage returns the cached image, or fetches and caches it.
+// Concurrent misses for the same image share one Scryfall request.
+func (s *Server) loadCardImage(ctx context.Context, cardID int64, version, ext string) ([]byte, time.Time, error) {
+	var cachePath string
+	if s.imageCacheDir != "" {
+		cachePath = filepath.Join(s.imageCacheDir, version, strconv.FormatInt(cardID, 10)+ext)
+		if info, err := os.Stat(cachePath); err == nil {
+			if image, err := os.ReadFile(cachePath); err == nil {
+				return image, info.ModTime(), nil
+			}
+		}
+	}
+
+	key := version + "/" + strconv.FormatInt(cardID, 10)
+	s.imageFetchMu.Lock()
+	if s.imageFetches == nil {
+		s.imageFetches = map[string]*cardImageFetch{}
+	}
+	fetch, inFlight := s.imageFetches[key]
+	if !inFlight {
+		fetch = &cardImageFetch{done: make(chan struct{})}
+		s.imageFetches[key] = fetch
+	}
+	s.imageFetchMu.Unlock()
+
+	if !inFlight {
+		// The fetch outlives any one request so a client that gives up does
+		// not fail the others waiting on it.
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.httpClient.Timeout+time.Second)
+		fetch.image, fetch.err = s.fetchCardImage(fetchCtx, cardID, version)
+		cancel()
+		if fetch.err == nil && cachePath != "" {
+			// A failed cache write still has a good image to serve.
+			if err := writeFileAtomic(cachePath, fetch.image); err != nil {
+				log.Printf("card image cache write failed: %v", err)
+			}
+		}
+		s.imageFetchMu.Lock()
+		delete(s.imageFetches, key)
+		s.imageFetchMu.Unlock()
+		close(fetch.done)
+	}
+
+	select {
+	case <-fetch.done:
+	case <-ctx.Done():
+		return nil, time.Time{}, ctx.Err()
+	}
+	if fetch.err != nil {
+		return nil, time.Time{}, fetch.err
+	}
+	return fetch.image, time.Now(), nil
+}
+
+type cardImageFetch struct {
+	done  chan struct{}
+	image []byte
+	err   error
+}
+
+// fetchCardImage asks Scryfall for the card's image by Arena id; the API
+// answers with a redirect to its image CDN, which the client follows.
+func (s *Server) fetchCardImage(ctx context.Context, cardID int64, version string) ([]byte, error) {
+	imageURL := fmt.Sprintf("%s/%d?format=image&version=%s", scryfallArenaCardURL, cardID, url.QueryEscape(version))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build scryfall image request: %w", err)
+	}
+	req.Header.Set("Accept", "image/*")
+	req.Header.Set("User-Agent", "ponder/0.1 (local tracker)")
+
+	res, err := s.doScryfall(req)
+	if err != nil {
+		return nil, fmt.Errorf("request scryfall image: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, errCardImageNotFound
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("scryfall image status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	image, err := io.ReadAll(io.LimitReader(res.Body, cardImageMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read scryfall image: %w", err)
+	}
+	if len(image) > cardImageMaxBytes {
+		return nil, fmt.Errorf("scryfall image exceeds %d bytes", cardImageMaxBytes)
+	}
+	if !strings.HasPrefix(http.DetectContentType(image), "image/") {
+		return nil, fmt.Errorf("scryfall returned %s, not an image", http.DetectContentType(image))
+	}
+	return image, nil
+}
+
+// writeFileAtomic writes through a temp file and rename so a crash never
+// leaves a truncated image that would be served from cache forever.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// cardImagePrefetchVersion is the size PrefetchCardImages caches, the one
+// the UI asks for by default.
+const cardImagePrefetchVersion = "normal"
+
+// PrefetchCardImages caches the images of each batch of card ids it
+// receives, until ctx ends or batches is closed, so the first view of a new
+// deck or draft is served from disk instead of a burst of Scryfall requests.
+// Cards already cached are skipped; the Scryfall throttle paces the rest.
+// Without a cache directory it only drains batches.
+func (s *Server) PrefetchCardImages(ctx context.Context, batches <-chan []int64) {
+	ext := cardImageVersions[cardImagePrefetchVersion]
+	for {
+		var ids []int64
+		select {
+		case <-ctx.Done():
+			return
+		case batch, ok := <-batches:
+			if !ok {
+				return
+			}
+			ids = batch
+		}
+		if s.imageCacheDir == "" {
+			continue
+		}
+		failed := 0
+		for _, cardID := range ids {
+			if ctx.Err() != nil {
+				return
+			}
+			cachePath := filepath.Join(s.imageCacheDir, cardImagePrefetchVersion, strconv.FormatInt(cardID, 10)+ext)
+			if _, err := os.Stat(cachePath); err == nil {
+				continue
+			}
+			if _, _, err := s.loadCardImage(ctx, cardID, cardImagePrefetchVersion, ext); err != nil && !errors.Is(err, errCardImageNotFound) {
+				failed++
+			}
+		}
+		if failed > 0 {
+			log.Printf("card image prefetch: %d of %d images failed", failed, len(ids))
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCardImageFetchesOnceThenServesFromDisk(t *testing.T) {
+	var art bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.White)
+	if err := png.Encode(&art, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+
+	var requests []string
+	server := NewServer(nil, "", nil)
+	server.httpClient = &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req.URL.String())
+			status, body := http.StatusOK, art.Bytes()
+			if req.URL.Path == "/cards/arena/404" {
+				status, body = http.StatusNotFound, []byte(`{"object":"error"}`)
+			}
+			return &http.Response{
+				StatusCode: status,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(bytes.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+	cacheDir := t.TempDir()
+	server.SetImageCacheDir(cacheDir)
+	handler := server.Handler()
+
+	for attempt := range 2 {
+		req := httptest.NewRequest(http.MethodGet, "/api/cards/91234/image?version=png", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("attempt %d status = %d: %s", attempt, rec.Code, rec.Body.String())
+		}
+		if got := rec.Header().Get("Content-Type"); got != "image/png" {
+			t.Fatalf("attempt %d Content-Type = %q", attempt, got)
+		}
+		if rec.Header().Get("Content-Encoding") != "" || !bytes.Equal(rec.Body.Bytes(), art.Bytes()) {
+			t.Fatalf("attempt %d body was altered (encoding %q)", attempt, rec.Header().Get("Content-Encoding"))
+		}
+	}
+	if len(requests) != 1 || requests[0] != "https://api.scryfall.com/cards/arena/91234?format=image&version=png" {
+		t.Fatalf("scryfall requests = %v, want one image fetch", requests)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "png", "91234.png")); err != nil {
+		t.Fatalf("cached file: %v", err)
+	}
+
+	for path, want := range map[string]int{
+		"/api/cards/404/image":               http.StatusNotFound,
+		"/api/cards/91234/image?version=xxl": http.StatusBadRequest,
+		"/api/cards/abc/image":               http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Fatalf("%s status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
+func TestPrefetchCardImagesFillsCacheOnce(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	server := NewServer(nil, "", nil)
+	server.httpClient = &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req.URL.Path)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(bytes.NewReader([]byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"))),
+				Request:    req,
+			}, nil
+		}),
+	}
+	cacheDir := t.TempDir()
+	server.SetImageCacheDir(cacheDir)
+
+	batches := make(chan []int64, 2)
+	batches <- []int64{11, 12}
+	batches <- []int64{12}
+	close(batches)
+	server.PrefetchCardImages(context.Background(), batches)
+
+	if len(requests) != 2 {
+		t.Fatalf("scryfall requests = %v, want one per card", requests)
+	}
+	for _, name := range []string{"11.jpg", "12.jpg"} {
+		if _, err := os.Stat(filepath.Join(cacheDir, "normal", name)); err != nil {
+			t.Fatalf("cached %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"container/list"
+	"sync"
+)
+
+// cardNameCacheSize holds every Arena card several times over; an entry is
+// a card ID and a short name.
+const cardNameCacheSize = 32768
+
+// cardNameCache is a fixed-size LRU of resolved card names, shared by every
+// request, so deck and match details seen before name their cards without
+// querying the store or the MTGA card database. Only names that resolved
+// are kept; a card still unnamed is looked up again next time.
+type cardNameCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // most recently used at the front
+	entries map[int64]*list.Element
+}
+
+type cardNameEntry struct {
+	cardID int64
+	name   string
+}
+
+func newCardNameCache(max int) *cardNameCache {
+	return &cardNameCache{max: max, order: list.New(), entries: make(map[int64]*list.Element)}
+}
+
+// lookup copies the cached names of cardIDs into out and returns the IDs
+// it has no name for.
+func (c *cardNameCache) lookup(cardIDs []int64, out map[int64]string) []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var missing []int64
+	for _, cardID := range cardIDs {
+		elem, ok := c.entries[cardID]
+		if !ok {
+			missing = append(missing, cardID)
+			continue
+		}
+		c.order.MoveToFront(elem)
+		out[cardID] = elem.Value.(*cardNameEntry).name
+	}
+	return missing
+}
+
+// add caches names, evicting the least recently used beyond the limit.
+func (c *cardNameCache) add(names map[int64]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for cardID, name := range names {
+		if name == "" {
+			continue
+		}
+		if elem, ok := c.entries[cardID]; ok {
+			elem.Value.(*cardNameEntry).name = name
+			c.order.MoveToFront(elem)
+			continue
+		}
+		c.entries[cardID] = c.order.PushFront(&cardNameEntry{cardID: cardID, name: name})
+		for c.order.Len() > c.max {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cardNameEntry).cardID)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestCardNameCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	cache := newCardNameCache(2)
+	cache.add(map[int64]string{1: "Opt"})
+	cache.add(map[int64]string{2: "Shock", 3: ""})
+
+	out := map[int64]string{}
+	if missing := cache.lookup([]int64{1}, out); len(missing) != 0 {
+		t.Fatalf("missing = %v, want none", missing)
+	}
+	cache.add(map[int64]string{4: "Duress"})
+
+	out = map[int64]string{}
+	missing := cache.lookup([]int64{1, 2, 3, 4}, out)
+	if len(missing) != 2 || missing[0] != 2 || missing[1] != 3 {
+		t.Fatalf("missing = %v, want [2 3]", missing)
+	}
+	if out[1] != "Opt" || out[4] != "Duress" || len(out) != 2 {
+		t.Fatalf("out = %v", out)
+	}
+}
+
+func TestResolveCardNamesServesRepeatsFromMemory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	store := db.NewStore(database)
+	if err := store.UpsertCardNames(ctx, map[int64]string{5001: "Lightning Strike", 5002: "Opt"}); err != nil {
+		t.Fatalf("upsert card names: %v", err)
+	}
+	server := NewServer(store, "", nil)
+
+	names := server.resolveCardNames(ctx, []int64{5001, 5002})
+	if names[5001] != "Lightning Strike" || names[5002] != "Opt" {
+		t.Fatalf("first names = %v", names)
+	}
+
+	if _, err := database.ExecContext(ctx, `DELETE FROM card_catalog`); err != nil {
+		t.Fatalf("clear card catalog: %v", err)
+	}
+	names = server.resolveCardNames(ctx, []int64{5002, 5001, 5002})
+	if len(names) != 2 || names[5001] != "Lightning Strike" || names[5002] != "Opt" {
+		t.Fatalf("cached names = %v", names)
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// resolveDeckPaperValue prices a deck's main deck and sideboard from the
+// latest weekly snapshot. It returns nil when no card in the deck has a
+// price, which is the case until `cards sync` has run.
+func (s *Server) resolveDeckPaperValue(ctx context.Context, cards []model.DeckCardRow) *model.DeckPaperValue {
+	cardIDs := make([]int64, 0, len(cards))
+	for _, card := range cards {
+		if card.Section == "main" || card.Section == "sideboard" {
+			cardIDs = append(cardIDs, card.CardID)
+		}
+	}
+	prices, err := s.store.LatestCardPrices(ctx, cardIDs)
+	if err != nil {
+		log.Printf("deck paper value: %v", err)
+		return nil
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+	var out model.DeckPaperValue
+	for _, card := range cards {
+		if (card.Section != "main" && card.Section != "sideboard") || card.Quantity <= 0 {
+			continue
+		}
+		price, ok := prices[card.CardID]
+		if ok && price.USD != nil {
+			out.USD += *price.USD * float64(card.Quantity)
+		} else {
+			out.UnpricedCards += card.Quantity
+		}
+		if ok && price.EUR != nil {
+			out.EUR += *price.EUR * float64(card.Quantity)
+		}
+	}
+	out.USD = roundCents(out.USD)
+	out.EUR = roundCents(out.EUR)
+	return &out
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// handleCollectionValue serves GET /api/collection/value: the collection's
+// paper value at each weekly price snapshot.
+func (s *Server) handleCollectionValue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	out, err := s.store.CollectionValueHistory(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for index := range out.Points {
+		out.Points[index].USD = roundCents(out.Points[index].USD)
+		out.Points[index].EUR = roundCents(out.Points[index].EUR)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestDeckPaperValueAndCollectionValueHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	for _, query := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, created_at, updated_at) VALUES (1, 'arena-deck', 'Burn', 'x', 'x')`,
+		`INSERT INTO deck_cards (deck_id, section, card_id, quantity) VALUES
+			(1, 'main', 100, 4), (1, 'main', 200, 2), (1, 'sideboard', 300, 1)`,
+		`INSERT INTO deck_versions (id, deck_id, version_number, cards_hash, created_at) VALUES (1, 1, 1, 'a', 'x')`,
+		`INSERT INTO deck_version_cards (deck_version_id, section, card_id, quantity) VALUES
+			(1, 'main', 100, 4), (1, 'main', 200, 2), (1, 'sideboard', 300, 1)`,
+		`INSERT INTO card_catalog (arena_id, name, updated_at) VALUES
+			(100, 'Lightning Strike', 'x'), (200, 'Alchemy Card', 'x'), (300, 'Abrade', 'x')`,
+		`INSERT INTO card_price_snapshots (week, arena_id, usd, eur, updated_at) VALUES
+			('2026-10-05', 100, 0.20, 0.15, 'x'), ('2026-10-05', 300, 1.00, NULL, 'x'),
+			('2026-10-12', 100, 0.30, 0.25, 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	handler := NewServer(db.NewStore(database), "", nil).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/decks/1", nil))
+	var detail model.DeckDetail
+	if err := json.Unmarshal(rec.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("decode deck: %v; body: %s", err, rec.Body.String())
+	}
+	// Each card takes its newest price: Lightning Strike this week, Abrade
+	// from last week; the Alchemy card has none.
+	want := model.DeckPaperValue{USD: 2.20, EUR: 1.00, UnpricedCards: 2}
+	if detail.PaperValue == nil || *detail.PaperValue != want {
+		t.Fatalf("paper value = %+v, want %+v", detail.PaperValue, want)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/collection/value", nil))
+	var history model.CollectionValueHistory
+	if err := json.Unmarshal(rec.Body.Bytes(), &history); err != nil {
+		t.Fatalf("decode value: %v; body: %s", err, rec.Body.String())
+	}
+	wantPoints := []model.CollectionValuePoint{
+		{Week: "2026-10-05", USD: 1.80, EUR: 0.60, PricedCards: 5},
+		{Week: "2026-10-12", USD: 1.20, EUR: 1.00, PricedCards: 4},
+	}
+	if history.Cards != 7 || len(history.Points) != 2 || history.Points[0] != wantPoints[0] || history.Points[1] != wantPoints[1] {
+		t.Fatalf("history = %+v, want 7 cards and %+v", history, wantPoints)
+	}
+}
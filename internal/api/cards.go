@@ -0,0 +1,80 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// cardLookupMaxIDs caps one /api/cards request; a full draft pool or a
+// 250-card cube fits, while a runaway client can't trigger thousands of
+// Scryfall lookups at once.
+const cardLookupMaxIDs = 500
+
+// handleCards serves GET /api/cards?ids=1,2,3: name, set, and colors for each
+// Arena card id, keyed by id. Misses go through the same cache, MTGA raw
+// database, and Scryfall chain the other endpoints use, so every frontend
+// sees the same names. Cards nothing could resolve are omitted.
+func (s *Server) handleCards(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/cards" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	cardIDs, err := parseCardIDList(r.URL.Query().Get("ids"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(cardIDs) > cardLookupMaxIDs {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d ids per request", cardLookupMaxIDs))
+		return
+	}
+
+	ctx := r.Context()
+	names := s.resolveCardNames(ctx, cardIDs)
+	metadata := s.resolveCardMetadata(ctx, cardIDs)
+
+	out := make(map[int64]model.CardInfo, len(cardIDs))
+	for _, cardID := range cardIDs {
+		name, hasName := names[cardID]
+		meta, hasMeta := metadata[cardID]
+		if !hasName && !hasMeta {
+			continue
+		}
+		out[cardID] = model.CardInfo{
+			CardID:          cardID,
+			Name:            name,
+			SetCode:         meta.SetCode,
+			CollectorNumber: meta.CollectorNumber,
+			Rarity:          meta.Rarity,
+			Colors:          normalizeDeckColors(strings.Split(meta.ColorIdentity, "")),
+			ManaValue:       meta.ManaValue,
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// parseCardIDList reads a comma-separated id list, dropping duplicates.
+func parseCardIDList(raw string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid card id %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return uniqueCardIDs(ids), nil
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestCardsResolvesCachedAndFetchedCards(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	store := db.NewStore(database)
+	if err := store.UpsertCardNames(ctx, map[int64]string{5001: "Lightning Strike", 5004: "Alchemy Card"}); err != nil {
+		t.Fatalf("upsert card names: %v", err)
+	}
+	manaValue := 2.0
+	// 5004 was cached without a set, as when no source had one for it.
+	if err := store.UpsertCardMetadata(ctx, map[int64]db.CardMetadata{
+		5001: {ColorIdentity: "R", ManaValue: &manaValue, SetCode: "dmu"},
+		5004: {ColorIdentity: "G", ManaValue: &manaValue},
+	}); err != nil {
+		t.Fatalf("upsert card metadata: %v", err)
+	}
+
+	var scryfallQueries []string
+	server := NewServer(store, "", nil)
+	server.httpClient = &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			scryfallQueries = append(scryfallQueries, req.URL.Query().Get("q"))
+			body := `{"data":[{"arena_id":5002,"name":"Opt","set":"ELD","color_identity":["U"],"cmc":1}],"has_more":false}`
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/cards?ids=5001,5002,5003,5001,5004", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var out map[string]model.CardInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(out) != 3 {
+		t.Fatalf("cards = %+v, want 5001, 5002, and 5004 only", out)
+	}
+	if cached := out["5001"]; cached.Name != "Lightning Strike" || cached.SetCode != "dmu" || len(cached.Colors) != 1 || cached.Colors[0] != "R" {
+		t.Fatalf("cached card = %+v", cached)
+	}
+	if fetched := out["5002"]; fetched.Name != "Opt" || fetched.SetCode != "eld" || len(fetched.Colors) != 1 || fetched.Colors[0] != "U" {
+		t.Fatalf("fetched card = %+v", fetched)
+	}
+	for _, query := range scryfallQueries {
+		if strings.Contains(query, "5001") || strings.Contains(query, "5004") {
+			t.Fatalf("scryfall asked for cached card: %q", query)
+		}
+	}
+
+	// The fetched card is cached for next time.
+	cachedMeta, err := store.LookupCardMetadata(ctx, []int64{5002})
+	if err != nil || cachedMeta[5002].SetCode != "eld" {
+		t.Fatalf("cached metadata = %+v, %v", cachedMeta, err)
+	}
+
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/cards?ids=5001,abc", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid id status = %d, want 400", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/cards?ids=5001", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST status = %d, want 405", rec.Code)
+	}
+}
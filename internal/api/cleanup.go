@@ -0,0 +1,103 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+)
+
+// handleDeleteMatch serves DELETE /api/matches/{id}.
+func (s *Server) handleDeleteMatch(w http.ResponseWriter, r *http.Request, matchID int64) {
+	deleted, err := s.store.DeleteMatch(r.Context(), matchID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "match not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "deleted", "matchId": matchID})
+}
+
+// handleArchiveMatch serves POST /api/matches/{id}/archive and /unarchive.
+func (s *Server) handleArchiveMatch(w http.ResponseWriter, r *http.Request, matchID int64, archived bool) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	found, err := s.store.SetMatchArchived(r.Context(), matchID, archived)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, "match not found")
+		return
+	}
+	status := "unarchived"
+	if archived {
+		status = "archived"
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": status, "matchId": matchID})
+}
+
+// handleDeleteDeck serves DELETE /api/decks/{id}.
+func (s *Server) handleDeleteDeck(w http.ResponseWriter, r *http.Request, deckID int64) {
+	deleted, err := s.store.DeleteDeck(r.Context(), deckID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "deck not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "deleted", "deckId": deckID})
+}
+
+// handleDeckMerge serves POST /api/decks/merge, folding duplicate decks
+// (for example from a test parse) into one.
+func (s *Server) handleDeckMerge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	payload := struct {
+		TargetID  int64   `json:"targetId"`
+		SourceIDs []int64 `json:"sourceIds"`
+	}{}
+	if err := decodeJSONBody(r, &payload); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if payload.TargetID <= 0 {
+		writeError(w, http.StatusBadRequest, "missing targetId")
+		return
+	}
+	sources := make([]int64, 0, len(payload.SourceIDs))
+	for _, id := range payload.SourceIDs {
+		if id <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid deck id in sourceIds")
+			return
+		}
+		if id != payload.TargetID {
+			sources = append(sources, id)
+		}
+	}
+	if len(sources) == 0 {
+		writeError(w, http.StatusBadRequest, "sourceIds must name at least one deck other than targetId")
+		return
+	}
+
+	out, err := s.store.MergeDecks(r.Context(), payload.TargetID, sources)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "deck not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// handleCollectionInsights serves per-card first/last-seen dates. ?view=
+// narrows the card list to "never-played" (held but never cast or played by
+// us) or "new" (first seen at or after ?since=, default the start of this
+// month); counts always cover every card.
+func (s *Server) handleCollectionInsights(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	view := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("view")))
+	switch view {
+	case "":
+		view = "all"
+	case "all", "never-played", "new":
+	default:
+		writeError(w, http.StatusBadRequest, "invalid view (use all, never-played, or new)")
+		return
+	}
+
+	var since time.Time
+	if raw := strings.TrimSpace(r.URL.Query().Get("since")); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			parsed, err = time.Parse(time.RFC3339, raw)
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid since (use YYYY-MM-DD or RFC 3339)")
+			return
+		}
+		since = parsed
+	}
+
+	out, err := s.store.CollectionInsights(r.Context(), since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out.View = view
+	if view != "all" {
+		filtered := make([]model.CollectionCardInsight, 0, len(out.Cards))
+		for _, card := range out.Cards {
+			if (view == "never-played" && card.TimesPlayed == 0) || (view == "new" && card.FirstSeenAt >= out.Since) {
+				filtered = append(filtered, card)
+			}
+		}
+		out.Cards = filtered
+	}
+
+	missing := make([]int64, 0)
+	for _, card := range out.Cards {
+		if card.CardName == "" {
+			missing = append(missing, card.CardID)
+		}
+	}
+	if len(missing) > 0 {
+		resolved := s.resolveCardNames(r.Context(), missing)
+		for index := range out.Cards {
+			if out.Cards[index].CardName == "" {
+				out.Cards[index].CardName = resolved[out.Cards[index].CardID]
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestCollectionInsightsFiltersByView(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	store := db.NewStore(database)
+	if err := store.UpsertCardNames(ctx, map[int64]string{5001: "Lightning Strike", 5002: "Opt"}); err != nil {
+		t.Fatalf("upsert card names: %v", err)
+	}
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	cards := []db.DeckCard{{Section: "main", CardID: 5001, Quantity: 4}, {Section: "main", CardID: 5002, Quantity: 4}}
+	if _, err := store.UpsertDeck(ctx, tx, "deck-1", "Traditional_Ladder", "Izzet", "TraditionalStandard", "test", "2026-07-01T00:00:00Z", cards); err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("upsert deck: %v", err)
+	}
+	if _, err := store.UpsertMatchStart(ctx, tx, "match-1", "Traditional_Ladder", 1, "2026-07-12T18:00:00Z"); err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("insert match: %v", err)
+	}
+	if err := store.UpsertMatchCardPlay(ctx, tx, "match-1", 1, 101, 5001, 1, 3, "main1", "stack", "2026-07-12T18:05:00Z", "test"); err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("insert card play: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	handler := NewServer(store, "", nil).Handler()
+
+	fetch := func(target string, want int) model.CollectionInsights {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != want {
+			t.Fatalf("GET %s: status = %d, want %d", target, rec.Code, want)
+		}
+		var out model.CollectionInsights
+		if want == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+				t.Fatalf("decode %s: %v", target, err)
+			}
+		}
+		return out
+	}
+
+	never := fetch("/api/collection/insights?view=never-played", http.StatusOK)
+	if never.TotalCards != 2 || len(never.Cards) != 1 || never.Cards[0].CardName != "Opt" {
+		t.Fatalf("never-played = %+v", never)
+	}
+	recent := fetch("/api/collection/insights?view=new&since=2026-07-01", http.StatusOK)
+	if recent.NewCount != 2 || len(recent.Cards) != 2 || recent.Since != "2026-07-01T00:00:00Z" {
+		t.Fatalf("new = %+v", recent)
+	}
+	fetch("/api/collection/insights?view=owned", http.StatusBadRequest)
+	fetch("/api/collection/insights?since=last-week", http.StatusBadRequest)
+}
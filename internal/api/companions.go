@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// handleCompanionStats serves GET /api/stats/companions: match win rate by
+// the companion or Brawl commander in the player's deck, and against each
+// one opponents revealed, with 95% intervals.
+func (s *Server) handleCompanionStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	out, err := s.store.CompanionRecords(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	lists := [][]model.CompanionRecord{out.Companions, out.Commanders, out.OpponentCompanions, out.OpponentCommanders}
+	var missing []int64
+	for _, list := range lists {
+		for index := range list {
+			fillWinRateInterval(&list[index].Rate)
+			if strings.TrimSpace(list[index].CardName) == "" {
+				missing = append(missing, list[index].CardID)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		names := s.resolveCardNames(r.Context(), missing)
+		for _, list := range lists {
+			for index := range list {
+				if strings.TrimSpace(list[index].CardName) == "" {
+					list[index].CardName = names[list[index].CardID]
+				}
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
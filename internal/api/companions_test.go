@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestCompanionStatsByOwnAndOpponentCompanionsAndCommanders(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// Lurrus decks win twice; a Brawl deck led by Alela loses once. Opponents
+	// reveal Jegantha in a ladder match and Kenrith in the Brawl match; the
+	// emblem-like card seen on turn 5 is not a companion.
+	for _, query := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, format, created_at, updated_at) VALUES
+			(1, 'deck-lurrus', 'Lurrus', 'Standard', 'x', 'x'), (2, 'deck-alela', 'Alela', 'Brawl', 'x', 'x')`,
+		`INSERT INTO deck_cards (deck_id, section, card_id, quantity) VALUES
+			(1, 'main', 10, 4), (1, 'companion', 100, 1), (2, 'command', 200, 1)`,
+		`INSERT INTO card_catalog (arena_id, name, updated_at) VALUES
+			(100, 'Lurrus of the Dream-Den', 'x'), (200, 'Alela, Artful Provocateur', 'x'),
+			(300, 'Jegantha, the Wellspring', 'x'), (400, 'Kenrith, the Returned King', 'x')`,
+		`INSERT INTO matches (id, arena_match_id, event_name, player_seat_id, result, created_at, updated_at) VALUES
+			(1, 'm1', 'Ladder', 1, 'win', 'x', 'x'),
+			(2, 'm2', 'Ladder', 1, 'win', 'x', 'x'),
+			(3, 'm3', 'Play_Brawl', 1, 'loss', 'x', 'x')`,
+		`INSERT INTO match_decks (match_id, deck_id, snapshot_reason, created_at) VALUES
+			(1, 1, 'x', 'x'), (2, 1, 'x', 'x'), (3, 2, 'x', 'x')`,
+		`INSERT INTO match_replay_frames (id, match_id, game_number, game_state_id, turn_number, created_at) VALUES
+			(1, 1, 1, 1, 1, 'x'), (2, 1, 1, 2, 5, 'x'), (3, 3, 1, 1, 0, 'x')`,
+		`INSERT INTO match_replay_frame_objects (frame_id, instance_id, card_id, owner_seat_id, zone_type, created_at) VALUES
+			(1, 50, 300, 2, 'command', 'x'),
+			(1, 51, 100, 1, 'command', 'x'),
+			(2, 52, 999, 2, 'command', 'x'),
+			(3, 53, 400, 2, 'command', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(db.NewStore(database), "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/companions", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var stats model.CompanionStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(stats.Companions) != 1 || stats.Companions[0].CardName != "Lurrus of the Dream-Den" ||
+		stats.Companions[0].Rate.Record.Wins != 2 || stats.Companions[0].Rate.WinRate == nil {
+		t.Fatalf("companions = %+v, want Lurrus 2-0", stats.Companions)
+	}
+	if len(stats.Commanders) != 1 || stats.Commanders[0].CardID != 200 || stats.Commanders[0].Rate.Record.Losses != 1 {
+		t.Fatalf("commanders = %+v, want Alela 0-1", stats.Commanders)
+	}
+	if len(stats.OpponentCompanions) != 1 || stats.OpponentCompanions[0].CardName != "Jegantha, the Wellspring" ||
+		stats.OpponentCompanions[0].Rate.Record.Wins != 1 {
+		t.Fatalf("opponent companions = %+v, want Jegantha 1-0", stats.OpponentCompanions)
+	}
+	if len(stats.OpponentCommanders) != 1 || stats.OpponentCommanders[0].CardID != 400 {
+		t.Fatalf("opponent commanders = %+v, want Kenrith", stats.OpponentCommanders)
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
-	"github.com/solean/ponder/internal/db"
+	"github.com/solean/ponder/pkg/db"
 )
 
 func queryInt64(r *http.Request, name string) int64 {
@@ -132,3 +133,67 @@ func (s *Server) handleDeckAnalyticsGames(w http.ResponseWriter, r *http.Request
 	}
 	writeJSON(w, http.StatusOK, rows)
 }
+
+const (
+	// deckDrawImpactMinGames is the default games a card needs both drawn
+	// and not drawn before its win-rate difference is reported.
+	deckDrawImpactMinGames = 10
+	deckDrawImpactMaxGames = 1000
+)
+
+// handleDeckCardDrawImpact serves GET /api/decks/{id}/analytics/cards: each
+// card's game win rate when drawn against when not, so weak flex slots stand
+// out. ?minGames= sets the sample each side needs; cards short of it are
+// listed after the rest without a delta.
+func (s *Server) handleDeckCardDrawImpact(w http.ResponseWriter, r *http.Request, deckID int64) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	minGames, err := boundedQueryInt(r, "minGames", deckDrawImpactMinGames, deckDrawImpactMaxGames)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx := r.Context()
+	out, err := s.store.GetDeckCardDrawImpact(ctx, deckID, queryInt64(r, "version"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out.MinGames = int64(minGames)
+
+	missingNameCardIDs := make([]int64, 0)
+	for index := range out.Cards {
+		card := &out.Cards[index]
+		if strings.TrimSpace(card.CardName) == "" {
+			missingNameCardIDs = append(missingNameCardIDs, card.CardID)
+		}
+		fillWinRateInterval(&card.Drawn)
+		fillWinRateInterval(&card.NotDrawn)
+		if card.Drawn.Record.Games >= out.MinGames && card.NotDrawn.Record.Games >= out.MinGames {
+			delta := *card.Drawn.WinRate - *card.NotDrawn.WinRate
+			card.Qualified, card.WinRateDelta = true, &delta
+		}
+	}
+	if len(missingNameCardIDs) > 0 {
+		resolved := s.resolveCardNames(ctx, missingNameCardIDs)
+		for index := range out.Cards {
+			if strings.TrimSpace(out.Cards[index].CardName) == "" {
+				out.Cards[index].CardName = resolved[out.Cards[index].CardID]
+			}
+		}
+	}
+
+	sort.SliceStable(out.Cards, func(i, j int) bool {
+		a, b := out.Cards[i], out.Cards[j]
+		if a.Qualified != b.Qualified {
+			return a.Qualified
+		}
+		if a.Qualified && *a.WinRateDelta != *b.WinRateDelta {
+			return *a.WinRateDelta < *b.WinRateDelta
+		}
+		return a.Drawn.Record.Games > b.Drawn.Record.Games
+	})
+	writeJSON(w, http.StatusOK, out)
+}
@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,7 +10,7 @@ import (
 	"net/url"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 const rawCardLookupBatchMax = 900
@@ -127,12 +126,16 @@ func (s *Server) resolveCardColorIdentities(ctx context.Context, cardIDs []int64
 
 	resolved := make(map[int64][]string, len(cardIDs))
 
-	localColors, err := s.fetchCardColorIdentitiesFromMTGARaw(ctx, cardIDs)
+	// Cached metadata (filled by lookups or a bulk card sync) answers
+	// without touching the MTGA files or the network.
+	cached, err := s.store.LookupCardMetadata(ctx, cardIDs)
 	if err != nil {
-		log.Printf("local MTGA card color lookup failed: %v", err)
+		log.Printf("card metadata lookup failed: %v", err)
 	}
-	for cardID, colors := range localColors {
-		resolved[cardID] = colors
+	for cardID, meta := range cached {
+		if meta.SetCode != "" {
+			resolved[cardID] = parseCachedColorIdentity(meta.ColorIdentity)
+		}
 	}
 
 	unresolved := make([]int64, 0, len(cardIDs))
@@ -142,6 +145,23 @@ func (s *Server) resolveCardColorIdentities(ctx context.Context, cardIDs []int64
 		}
 	}
 
+	if len(unresolved) > 0 {
+		localColors, err := s.fetchCardColorIdentitiesFromMTGARaw(ctx, unresolved)
+		if err != nil {
+			log.Printf("local MTGA card color lookup failed: %v", err)
+		}
+		for cardID, colors := range localColors {
+			resolved[cardID] = colors
+		}
+
+		unresolved = unresolved[:0]
+		for _, cardID := range cardIDs {
+			if _, ok := resolved[cardID]; !ok {
+				unresolved = append(unresolved, cardID)
+			}
+		}
+	}
+
 	if len(unresolved) > 0 {
 		fetchedColors, fetchErr := s.fetchCardColorIdentitiesFromScryfall(ctx, unresolved)
 		if fetchErr != nil {
@@ -162,21 +182,13 @@ func (s *Server) fetchCardColorIdentitiesFromMTGARaw(ctx context.Context, cardID
 		return out, nil
 	}
 
-	rawDBPath := discoverMTGARawCardDBPath()
-	if strings.TrimSpace(rawDBPath) == "" {
-		return out, nil
-	}
-
-	rawDB, err := sql.Open("sqlite", rawDBPath)
+	rawDB, release, err := s.mtgaRaw.acquire(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("open MTGA raw card db %q: %w", rawDBPath, err)
+		return nil, err
 	}
-	defer rawDB.Close()
-	rawDB.SetMaxOpenConns(1)
-	rawDB.SetMaxIdleConns(1)
-
-	if err := rawDB.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("ping MTGA raw card db %q: %w", rawDBPath, err)
+	defer release()
+	if rawDB == nil {
+		return out, nil
 	}
 
 	for start := 0; start < len(cardIDs); start += rawCardLookupBatchMax {
@@ -274,7 +286,7 @@ func (s *Server) fetchCardColorBatch(ctx context.Context, cardIDs []int64) (map[
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "ponder/0.1 (local tracker)")
 
-	res, err := s.httpClient.Do(req)
+	res, err := s.doScryfall(req)
 	if err != nil {
 		return nil, fmt.Errorf("request scryfall colors: %w", err)
 	}
@@ -312,7 +324,7 @@ func (s *Server) fetchCardColorBatch(ctx context.Context, cardIDs []int64) (map[
 		nextReq.Header.Set("Accept", "application/json")
 		nextReq.Header.Set("User-Agent", "ponder/0.1 (local tracker)")
 
-		nextRes, err := s.httpClient.Do(nextReq)
+		nextRes, err := s.doScryfall(nextReq)
 		if err != nil {
 			return out, fmt.Errorf("request scryfall color next page: %w", err)
 		}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// deckCurveTop is the last curve bucket; costlier cards are folded into it.
+const deckCurveTop = 7
+
+// deckTypeGroups mirrors the deck page's visual grouping, in priority order:
+// a card goes to the first group its type line mentions.
+var deckTypeGroups = []struct {
+	group string
+	word  string
+}{
+	{"lands", "land"},
+	{"creatures", "creature"},
+	{"planeswalkers", "planeswalker"},
+	{"instants", "instant"},
+	{"sorceries", "sorcery"},
+	{"battles", "battle"},
+	{"artifacts", "artifact"},
+	{"enchantments", "enchantment"},
+}
+
+// resolveDeckComposition looks up mana value, color identity, and type line
+// for a deck's main-deck cards and summarizes them. Cards should already
+// have names so basic lands are recognized without a type line.
+func (s *Server) resolveDeckComposition(ctx context.Context, cards []model.DeckCardRow) *model.DeckComposition {
+	cardIDs := make([]int64, 0, len(cards))
+	for _, card := range cards {
+		if card.Section == "main" {
+			cardIDs = append(cardIDs, card.CardID)
+		}
+	}
+	metadata := s.resolveCardMetadata(ctx, cardIDs)
+	typeLines := s.resolveCardTypeLines(ctx, cardIDs)
+	composition := buildDeckComposition(cards, metadata, typeLines)
+	return &composition
+}
+
+func buildDeckComposition(cards []model.DeckCardRow, metadata map[int64]db.CardMetadata, typeLines map[int64]string) model.DeckComposition {
+	out := model.DeckComposition{
+		Curve:  make([]model.DeckCurveBucket, 0, deckCurveTop+1),
+		Colors: []model.DeckColorCount{},
+		Types:  []model.DeckTypeCount{},
+	}
+	for manaValue := int64(0); manaValue <= deckCurveTop; manaValue++ {
+		label := strconv.FormatInt(manaValue, 10)
+		if manaValue == deckCurveTop {
+			label += "+"
+		}
+		out.Curve = append(out.Curve, model.DeckCurveBucket{ManaValue: manaValue, Label: label})
+	}
+
+	colorCounts := make(map[string]int64, len(deckColorOrder)+1)
+	typeCounts := make(map[string]int64, len(deckTypeGroups)+2)
+	var manaValueTotal float64
+	var manaValueCards int64
+	for _, card := range cards {
+		if card.Section != "main" || card.Quantity <= 0 {
+			continue
+		}
+		out.Cards += card.Quantity
+
+		typeLine, typeKnown := typeLines[card.CardID]
+		group := "unknown"
+		switch {
+		case isBasicLandName(card.CardName):
+			group = "lands"
+		case typeKnown:
+			group = deckTypeGroup(typeLine)
+		}
+		typeCounts[group] += card.Quantity
+		if group == "lands" {
+			out.Lands += card.Quantity
+			continue
+		}
+
+		meta, metaKnown := metadata[card.CardID]
+		if metaKnown {
+			colors := normalizeDeckColors(strings.Split(meta.ColorIdentity, ""))
+			if len(colors) == 0 {
+				colorCounts["C"] += card.Quantity
+			}
+			for _, color := range colors {
+				colorCounts[color] += card.Quantity
+			}
+		}
+		if !metaKnown || meta.ManaValue == nil {
+			out.UnknownManaValue += card.Quantity
+			continue
+		}
+		manaValueTotal += *meta.ManaValue * float64(card.Quantity)
+		manaValueCards += card.Quantity
+		bucket := &out.Curve[min(max(int64(*meta.ManaValue), 0), deckCurveTop)]
+		bucket.Cards += card.Quantity
+		if group == "creatures" {
+			bucket.Creatures += card.Quantity
+		}
+	}
+
+	if manaValueCards > 0 {
+		avg := manaValueTotal / float64(manaValueCards)
+		out.AvgManaValue = &avg
+	}
+	for _, color := range append(append([]string{}, deckColorOrder...), "C") {
+		if count := colorCounts[color]; count > 0 {
+			out.Colors = append(out.Colors, model.DeckColorCount{Color: color, Cards: count})
+		}
+	}
+	for _, group := range deckTypeGroups {
+		if count := typeCounts[group.group]; count > 0 {
+			out.Types = append(out.Types, model.DeckTypeCount{Type: group.group, Cards: count})
+		}
+	}
+	for _, group := range []string{"other", "unknown"} {
+		if count := typeCounts[group]; count > 0 {
+			out.Types = append(out.Types, model.DeckTypeCount{Type: group, Cards: count})
+		}
+	}
+	return out
+}
+
+func deckTypeGroup(typeLine string) string {
+	lower := strings.ToLower(typeLine)
+	for _, group := range deckTypeGroups {
+		if strings.Contains(lower, group.word) {
+			return group.group
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestBuildDeckCompositionBucketsMainDeck(t *testing.T) {
+	t.Parallel()
+
+	mv := func(v float64) *float64 { return &v }
+	cards := []model.DeckCardRow{
+		{Section: "main", CardID: 1, Quantity: 4, CardName: "Llanowar Elves"},
+		{Section: "main", CardID: 2, Quantity: 2, CardName: "Growth Spiral"},
+		{Section: "main", CardID: 3, Quantity: 1, CardName: "Emrakul, the Promised End"},
+		{Section: "main", CardID: 4, Quantity: 9, CardName: "Forest"},
+		{Section: "main", CardID: 5, Quantity: 2, CardName: "Mystery Card"},
+		{Section: "sideboard", CardID: 6, Quantity: 3, CardName: "Negate"},
+	}
+	metadata := map[int64]db.CardMetadata{
+		1: {ColorIdentity: "G", ManaValue: mv(1)},
+		2: {ColorIdentity: "GU", ManaValue: mv(2)},
+		3: {ColorIdentity: "", ManaValue: mv(13)},
+		6: {ColorIdentity: "U", ManaValue: mv(2)},
+	}
+	typeLines := map[int64]string{
+		1: "Creature — Elf Druid",
+		2: "Instant",
+		3: "Legendary Creature — Eldrazi",
+	}
+
+	out := buildDeckComposition(cards, metadata, typeLines)
+	if out.Cards != 18 || out.Lands != 9 || out.UnknownManaValue != 2 {
+		t.Fatalf("totals = %+v", out)
+	}
+	if len(out.Curve) != 8 || out.Curve[7].Label != "7+" {
+		t.Fatalf("curve = %+v, want buckets 0 through 7+", out.Curve)
+	}
+	if out.Curve[1].Cards != 4 || out.Curve[1].Creatures != 4 || out.Curve[2].Cards != 2 || out.Curve[2].Creatures != 0 || out.Curve[7].Cards != 1 {
+		t.Fatalf("curve = %+v", out.Curve)
+	}
+	if out.AvgManaValue == nil || *out.AvgManaValue != 3 {
+		t.Fatalf("avg mana value = %v, want 3", out.AvgManaValue)
+	}
+
+	wantColors := []model.DeckColorCount{{Color: "U", Cards: 2}, {Color: "G", Cards: 6}, {Color: "C", Cards: 1}}
+	if len(out.Colors) != len(wantColors) {
+		t.Fatalf("colors = %+v, want %+v", out.Colors, wantColors)
+	}
+	for index, want := range wantColors {
+		if out.Colors[index] != want {
+			t.Fatalf("colors = %+v, want %+v", out.Colors, wantColors)
+		}
+	}
+
+	wantTypes := []model.DeckTypeCount{{Type: "lands", Cards: 9}, {Type: "creatures", Cards: 5}, {Type: "instants", Cards: 2}, {Type: "unknown", Cards: 2}}
+	if len(out.Types) != len(wantTypes) {
+		t.Fatalf("types = %+v, want %+v", out.Types, wantTypes)
+	}
+	for index, want := range wantTypes {
+		if out.Types[index] != want {
+			t.Fatalf("types = %+v, want %+v", out.Types, wantTypes)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+const (
+	// deckCastCurveMinCasts is the default casts a card needs before it can
+	// be flagged as coming down late.
+	deckCastCurveMinCasts = 5
+	deckCastCurveMaxCasts = 1000
+)
+
+// handleDeckCastCurve serves GET /api/decks/{id}/analytics/curve: the deck's
+// mana curve next to the turns its spells were actually cast, and each
+// card's cast turns, with cards that average a turn or more behind their
+// mana value first. ?minCasts= sets the casts a card needs to be flagged;
+// ?version= restricts to one deck version.
+func (s *Server) handleDeckCastCurve(w http.ResponseWriter, r *http.Request, deckID int64) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	minCasts, err := boundedQueryInt(r, "minCasts", deckCastCurveMinCasts, deckCastCurveMaxCasts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx := r.Context()
+	out, err := s.store.GetDeckCastTurns(ctx, deckID, queryInt64(r, "version"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out.MinCasts = int64(minCasts)
+
+	cardIDs := make([]int64, 0, len(out.Cards))
+	missingNameCardIDs := make([]int64, 0)
+	for _, card := range out.Cards {
+		cardIDs = append(cardIDs, card.CardID)
+		if strings.TrimSpace(card.CardName) == "" {
+			missingNameCardIDs = append(missingNameCardIDs, card.CardID)
+		}
+	}
+	if len(missingNameCardIDs) > 0 {
+		resolved := s.resolveCardNames(ctx, missingNameCardIDs)
+		for index := range out.Cards {
+			if strings.TrimSpace(out.Cards[index].CardName) == "" {
+				out.Cards[index].CardName = resolved[out.Cards[index].CardID]
+			}
+		}
+	}
+	buildDeckCastCurve(&out, s.resolveCardMetadata(ctx, cardIDs), s.resolveCardTypeLines(ctx, cardIDs))
+	writeJSON(w, http.StatusOK, out)
+}
+
+// buildDeckCastCurve fills in mana values, the curve buckets, and each
+// card's delay, dropping lands that were never cast.
+func buildDeckCastCurve(out *model.DeckCastCurve, metadata map[int64]db.CardMetadata, typeLines map[int64]string) {
+	out.Curve = make([]model.DeckCastCurveBucket, 0, deckCurveTop+1)
+	for manaValue := int64(0); manaValue <= deckCurveTop; manaValue++ {
+		label := strconv.FormatInt(manaValue, 10)
+		if manaValue == deckCurveTop {
+			label += "+"
+		}
+		out.Curve = append(out.Curve, model.DeckCastCurveBucket{ManaValue: manaValue, Label: label})
+	}
+	turnSums := make([]int64, deckCurveTop+1)
+
+	cards := out.Cards[:0]
+	for _, card := range out.Cards {
+		typeLine, typeKnown := typeLines[card.CardID]
+		isLand := isBasicLandName(card.CardName) || (typeKnown && deckTypeGroup(typeLine) == "lands")
+		if isLand && card.Casts == 0 {
+			continue
+		}
+		var turnSum int64
+		for index, count := range card.CastTurns {
+			turn := int64(index + 1)
+			turnSum += turn * count
+			out.Curve[min(turn, deckCurveTop)].CastsOnTurn += count
+		}
+		if card.Casts > 0 {
+			average := float64(turnSum) / float64(card.Casts)
+			card.AverageCastTurn = &average
+		}
+
+		meta, metaKnown := metadata[card.CardID]
+		if !metaKnown || meta.ManaValue == nil {
+			out.UnknownManaValue += card.Copies
+			cards = append(cards, card)
+			continue
+		}
+		card.ManaValue = meta.ManaValue
+		bucket := min(max(int64(*meta.ManaValue), 0), deckCurveTop)
+		out.Curve[bucket].Cards += card.Copies
+		out.Curve[bucket].Casts += card.Casts
+		turnSums[bucket] += turnSum
+		if card.AverageCastTurn != nil {
+			onCurveTurn := max(int64(*meta.ManaValue), 1)
+			for index, count := range card.CastTurns {
+				if int64(index+1) <= onCurveTurn {
+					card.OnCurveCasts += count
+				}
+			}
+			delay := *card.AverageCastTurn - float64(onCurveTurn)
+			card.AverageDelay = &delay
+			card.Late = card.Casts >= out.MinCasts && delay >= 1
+		}
+		cards = append(cards, card)
+	}
+	out.Cards = cards
+	for index := range out.Curve {
+		if bucket := &out.Curve[index]; bucket.Casts > 0 {
+			average := float64(turnSums[index]) / float64(bucket.Casts)
+			bucket.AverageCastTurn = &average
+		}
+	}
+
+	sort.SliceStable(out.Cards, func(i, j int) bool {
+		a, b := out.Cards[i], out.Cards[j]
+		if a.Late != b.Late {
+			return a.Late
+		}
+		if (a.AverageDelay != nil) != (b.AverageDelay != nil) {
+			return a.AverageDelay != nil
+		}
+		if a.AverageDelay != nil && *a.AverageDelay != *b.AverageDelay {
+			return *a.AverageDelay > *b.AverageDelay
+		}
+		return a.Casts > b.Casts
+	})
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestBuildDeckCastCurveFlagsLateCards(t *testing.T) {
+	t.Parallel()
+
+	mv := func(v float64) *float64 { return &v }
+	out := model.DeckCastCurve{
+		MinCasts: 3,
+		Cards: []model.DeckCardCastTurns{
+			// Two-drop always cast on turn two.
+			{CardID: 1, CardName: "Bear", Copies: 4, Casts: 4, CastTurns: []int64{0, 4}},
+			// Two-drop that comes down on turns three and four.
+			{CardID: 2, CardName: "Slow Bear", Copies: 4, Casts: 4, CastTurns: []int64{0, 0, 2, 2}},
+			// Five-drop cast late but too rarely to flag.
+			{CardID: 3, CardName: "Dragon", Copies: 2, Casts: 1, CastTurns: []int64{0, 0, 0, 0, 0, 0, 0, 0, 1}},
+			{CardID: 4, CardName: "Forest", Copies: 9},
+			{CardID: 5, CardName: "Mystery", Copies: 1, Casts: 1, CastTurns: []int64{1}},
+		},
+	}
+	metadata := map[int64]db.CardMetadata{1: {ManaValue: mv(2)}, 2: {ManaValue: mv(2)}, 3: {ManaValue: mv(5)}}
+	typeLines := map[int64]string{1: "Creature — Bear", 2: "Creature — Bear", 3: "Creature — Dragon", 4: "Basic Land — Forest"}
+
+	buildDeckCastCurve(&out, metadata, typeLines)
+	if len(out.Cards) != 4 || out.UnknownManaValue != 1 {
+		t.Fatalf("cards = %+v, unknown = %d; want the forest dropped and one unknown", out.Cards, out.UnknownManaValue)
+	}
+	slow := out.Cards[0]
+	if slow.CardID != 2 || !slow.Late || slow.OnCurveCasts != 0 || *slow.AverageDelay != 1.5 {
+		t.Fatalf("first card = %+v, want the slow bear 1.5 turns late", slow)
+	}
+	if dragon := out.Cards[1]; dragon.CardID != 3 || dragon.Late || *dragon.AverageDelay != 4 {
+		t.Fatalf("second card = %+v, want the unflagged dragon", dragon)
+	}
+	if bear := out.Cards[2]; bear.CardID != 1 || bear.Late || bear.OnCurveCasts != 4 || *bear.AverageDelay != 0 {
+		t.Fatalf("third card = %+v, want the on-curve bear", bear)
+	}
+
+	two := out.Curve[2]
+	if two.Cards != 8 || two.Casts != 8 || two.AverageCastTurn == nil || *two.AverageCastTurn != 2.75 || two.CastsOnTurn != 4 {
+		t.Fatalf("two-drop bucket = %+v", two)
+	}
+	if out.Curve[1].CastsOnTurn != 1 || out.Curve[7].CastsOnTurn != 1 || out.Curve[5].Cards != 2 {
+		t.Fatalf("curve = %+v", out.Curve)
+	}
+}
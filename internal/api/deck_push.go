@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/solean/ponder/internal/decksync"
+	"github.com/solean/ponder/pkg/model"
+)
+
+var (
+	// ErrUnknownDeckService is returned for a push to a site decksync does
+	// not know.
+	ErrUnknownDeckService = errors.New("unknown deck service")
+	// ErrNoDeckServiceToken is returned for a push to a site with no token
+	// in settings; the deck can still be imported there by hand.
+	ErrNoDeckServiceToken = errors.New("no token saved for deck service")
+
+	errDeckServiceFailed = errors.New("deck service request failed")
+)
+
+// DeckService finds a deckbuilding site by name, as -service and the push
+// endpoint name it.
+func (s *Server) DeckService(name string) (decksync.Service, error) {
+	services := s.deckServices
+	if services == nil {
+		services = decksync.Services
+	}
+	for _, service := range services {
+		if strings.EqualFold(service.Name, strings.TrimSpace(name)) {
+			return service, nil
+		}
+	}
+	return decksync.Service{}, fmt.Errorf("%w %q (use %s)", ErrUnknownDeckService, name, strings.Join(decksync.Names(), ", "))
+}
+
+// PushDeck sends a stored deck to a deckbuilding site with the token saved
+// in settings, updating the copy an earlier push made if there is one. It
+// returns sql.ErrNoRows when the deck does not exist.
+func (s *Server) PushDeck(ctx context.Context, deckID int64, serviceName string) (model.DeckPushResult, error) {
+	service, err := s.DeckService(serviceName)
+	if err != nil {
+		return model.DeckPushResult{}, err
+	}
+	settings, err := s.store.GetSettings(ctx)
+	if err != nil {
+		return model.DeckPushResult{}, err
+	}
+	token := deckServiceToken(settings, service.Name)
+	if strings.TrimSpace(token) == "" {
+		return model.DeckPushResult{}, fmt.Errorf("%w: set %sToken in settings", ErrNoDeckServiceToken, service.Name)
+	}
+
+	detail, err := s.store.GetDeckDetail(ctx, deckID, 1)
+	if err != nil {
+		return model.DeckPushResult{}, err
+	}
+	text, err := s.DeckImportText(ctx, deckID)
+	if err != nil {
+		return model.DeckPushResult{}, err
+	}
+	previousID, err := s.store.DeckPushID(ctx, deckID, service.Name)
+	if err != nil {
+		return model.DeckPushResult{}, err
+	}
+	deck := decksync.Deck{Name: detail.Name, Format: detail.Format, Text: text}
+	if deck.Name == "" {
+		deck.Name = fmt.Sprintf("Deck %d", deckID)
+	}
+	remoteID, err := decksync.Push(ctx, s.httpClient, service, token, previousID, deck)
+	if err != nil {
+		return model.DeckPushResult{}, fmt.Errorf("%w: %w", errDeckServiceFailed, err)
+	}
+	pushedAt, err := s.store.RecordDeckPush(ctx, deckID, service.Name, remoteID)
+	if err != nil {
+		return model.DeckPushResult{}, err
+	}
+	return model.DeckPushResult{
+		Service:  service.Name,
+		RemoteID: remoteID,
+		URL:      service.PageURL(remoteID),
+		Created:  previousID == "",
+		PushedAt: pushedAt,
+	}, nil
+}
+
+func deckServiceToken(settings model.Settings, service string) string {
+	switch service {
+	case "moxfield":
+		return settings.MoxfieldToken
+	case "archidekt":
+		return settings.ArchidektToken
+	}
+	return ""
+}
+
+// handleDeckPush serves POST /api/decks/:id/push with {"service": ...}.
+func (s *Server) handleDeckPush(w http.ResponseWriter, r *http.Request, deckID int64) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	payload := struct {
+		Service string `json:"service"`
+	}{}
+	if err := decodeJSONBody(r, &payload); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	result, err := s.PushDeck(r.Context(), deckID, payload.Service)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "deck not found")
+	case errors.Is(err, ErrUnknownDeckService), errors.Is(err, ErrNoDeckServiceToken):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errDeckServiceFailed):
+		writeError(w, http.StatusBadGateway, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, result)
+	}
+}
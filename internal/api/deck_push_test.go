@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solean/ponder/internal/decksync"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestDeckPushCreatesThenUpdatesRemoteDeck(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	for _, query := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, format, created_at, updated_at) VALUES (1, 'arena-deck', 'Burn', 'Standard', 'x', 'x')`,
+		`INSERT INTO deck_cards (deck_id, section, card_id, quantity) VALUES (1, 'main', 100, 4)`,
+		`INSERT INTO card_catalog (arena_id, name, updated_at) VALUES (100, 'Lightning Strike', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	var methods []string
+	var importText string
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		var body struct {
+			ImportText string `json:"importText"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		importText = body.ImportText
+		_, _ = w.Write([]byte(`{"publicId": "aBc123"}`))
+	}))
+	defer remote.Close()
+
+	server := NewServer(db.NewStore(database), "", nil)
+	server.httpClient = remote.Client()
+	server.deckServices = []decksync.Service{{
+		Name:       "moxfield",
+		APIURL:     remote.URL + "/v2/decks",
+		DeckURL:    "https://www.moxfield.com/decks/%s",
+		AuthScheme: "Bearer",
+	}}
+	handler := server.Handler()
+	push := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/decks/1/push", strings.NewReader(`{"service": "moxfield"}`)))
+		return rec
+	}
+
+	if rec := push(); rec.Code != http.StatusBadRequest {
+		t.Fatalf("push without a token status = %d, want 400; body: %s", rec.Code, rec.Body.String())
+	}
+	settings := db.DefaultSettings()
+	settings.MoxfieldToken = "tok"
+	if err := db.NewStore(database).SaveSettings(ctx, settings); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+
+	for i, wantCreated := range []bool{true, false} {
+		rec := push()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("push %d status = %d; body: %s", i, rec.Code, rec.Body.String())
+		}
+		var result model.DeckPushResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if result.RemoteID != "aBc123" || result.Created != wantCreated || result.URL != "https://www.moxfield.com/decks/aBc123" {
+			t.Fatalf("push %d = %+v", i, result)
+		}
+	}
+	if len(methods) != 2 || methods[0] != "POST /v2/decks" || methods[1] != "PUT /v2/decks/aBc123" {
+		t.Fatalf("remote requests = %v, want a create then an update", methods)
+	}
+	if importText != "Deck\n4 Lightning Strike\n" {
+		t.Fatalf("import text = %q", importText)
+	}
+}
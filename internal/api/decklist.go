@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// arenaSectionHeaders orders deck sections as Arena's import text does and
+// names each one; sections outside this list are left out of the export.
+var arenaSectionHeaders = []struct {
+	section string
+	header  string
+}{
+	{"command", "Commander"},
+	{"companion", "Companion"},
+	{"main", "Deck"},
+	{"sideboard", "Sideboard"},
+}
+
+type decklistCard struct {
+	Section  string
+	CardID   int64
+	Quantity int64
+	Name     string
+}
+
+// DeckImportText renders a stored deck as Arena import text, such as
+// "4 Lightning Strike (DMU) 137", for pasting into Arena or Moxfield. It
+// returns sql.ErrNoRows when the deck has no cards.
+func (s *Server) DeckImportText(ctx context.Context, deckID int64) (string, error) {
+	rows, err := s.store.ListDeckCards(ctx, deckID)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", sql.ErrNoRows
+	}
+	s.enrichDeckCardNames(ctx, rows)
+	cards := make([]decklistCard, 0, len(rows))
+	for _, row := range rows {
+		cards = append(cards, decklistCard{Section: row.Section, CardID: row.CardID, Quantity: row.Quantity, Name: row.CardName})
+	}
+	return s.renderDecklist(ctx, cards), nil
+}
+
+// OpponentDeckImportText renders the opponent's decklist for a match, as
+// far as the cards they revealed show it, in the same format. It returns
+// sql.ErrNoRows when none were seen.
+func (s *Server) OpponentDeckImportText(ctx context.Context, matchID int64) (string, error) {
+	rows, err := s.store.ListOpponentObservedCards(ctx, matchID)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", sql.ErrNoRows
+	}
+	cardIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		cardIDs = append(cardIDs, row.CardID)
+	}
+	names := s.resolveCardNames(ctx, cardIDs)
+	cards := make([]decklistCard, 0, len(rows))
+	for _, row := range rows {
+		name := row.CardName
+		if name == "" {
+			name = names[row.CardID]
+		}
+		cards = append(cards, decklistCard{Section: "main", CardID: row.CardID, Quantity: row.Quantity, Name: name})
+	}
+	return s.renderDecklist(ctx, cards), nil
+}
+
+func (s *Server) renderDecklist(ctx context.Context, cards []decklistCard) string {
+	cardIDs := make([]int64, 0, len(cards))
+	for _, card := range cards {
+		cardIDs = append(cardIDs, card.CardID)
+	}
+	metadata := s.resolveCardMetadata(ctx, cardIDs)
+
+	var b strings.Builder
+	for _, section := range arenaSectionHeaders {
+		wroteHeader := false
+		for _, card := range cards {
+			if card.Section != section.section || card.Quantity <= 0 {
+				continue
+			}
+			if !wroteHeader {
+				if b.Len() > 0 {
+					b.WriteString("\n")
+				}
+				b.WriteString(section.header + "\n")
+				wroteHeader = true
+			}
+			meta := metadata[card.CardID]
+			b.WriteString(arenaDecklistLine(card.Quantity, card.Name, card.CardID, meta.SetCode, meta.CollectorNumber) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// arenaDecklistLine formats one card. Without a set the printing is left to
+// the importer; without a name the Arena id is kept so the line is not lost.
+func arenaDecklistLine(quantity int64, name string, cardID int64, setCode, collectorNumber string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = fmt.Sprintf("Unknown Card %d", cardID)
+	}
+	line := fmt.Sprintf("%d %s", quantity, name)
+	if setCode = strings.TrimSpace(setCode); setCode != "" {
+		line += " (" + strings.ToUpper(setCode) + ")"
+		if collectorNumber = strings.TrimSpace(collectorNumber); collectorNumber != "" {
+			line += " " + collectorNumber
+		}
+	}
+	return line
+}
+
+// writeDecklist answers a decklist request as plain text.
+func writeDecklist(w http.ResponseWriter, text string, err error, notFound string) {
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(text))
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestDeckDecklistRendersArenaImportText(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	for _, query := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, created_at, updated_at) VALUES (1, 'arena-deck', 'Burn', 'x', 'x')`,
+		`INSERT INTO deck_cards (deck_id, section, card_id, quantity) VALUES
+			(1, 'sideboard', 300, 2), (1, 'main', 100, 4), (1, 'main', 200, 20)`,
+		`INSERT INTO card_catalog (arena_id, name, updated_at) VALUES
+			(100, 'Lightning Strike', 'x'), (200, 'Mountain', 'x'), (300, 'Abrade', 'x')`,
+		`INSERT INTO card_metadata (arena_id, set_code, collector_number, updated_at) VALUES
+			(100, 'dmu', '137', 'x'), (200, 'dmu', '269', 'x'), (300, 'dmu', '', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	handler := NewServer(db.NewStore(database), "", nil).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/decks/1/decklist", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	want := "Deck\n4 Lightning Strike (DMU) 137\n20 Mountain (DMU) 269\n\nSideboard\n2 Abrade (DMU)\n"
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("decklist =\n%s\nwant\n%s", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/decks/2/decklist", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing deck status = %d, want 404", rec.Code)
+	}
+}
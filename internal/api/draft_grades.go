@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// gradeDraftPicks rates every card in picks, whose names must already be
+// resolved, from the 17Lands ratings imported for the draft's set, and grades
+// each pick against the best-rated card in its pack. Drafts of a set with no
+// ratings imported are left as they are.
+func (s *Server) gradeDraftPicks(ctx context.Context, eventName string, picks []model.DraftPickRow) {
+	setCode := eventnames.SetCode(eventName)
+	if setCode == "" || len(picks) == 0 {
+		return
+	}
+	ratings, err := s.store.ListCardRatings(ctx, setCode)
+	if err != nil {
+		log.Printf("card ratings lookup failed: %v", err)
+		return
+	}
+	if len(ratings) == 0 {
+		return
+	}
+	lookup := newRatingLookup(ratings)
+
+	for i := range picks {
+		for j := range picks[i].PickedCards {
+			picks[i].PickedCards[j].Rating = lookup.rating(picks[i].PickedCards[j])
+		}
+		var best *model.DraftPickCard
+		for j := range picks[i].PackCards {
+			card := &picks[i].PackCards[j]
+			card.Rating = lookup.rating(*card)
+			if card.Rating != nil && (best == nil || *card.Rating > *best.Rating) {
+				best = card
+			}
+		}
+		if best == nil {
+			continue
+		}
+		grade := &model.DraftPickGrade{
+			BestCardID:   best.CardID,
+			BestCardName: best.CardName,
+			BestRating:   *best.Rating,
+		}
+		if len(picks[i].PickedCards) > 0 && picks[i].PickedCards[0].Rating != nil {
+			pickRating := *picks[i].PickedCards[0].Rating
+			delta := pickRating - grade.BestRating
+			grade.PickRating = &pickRating
+			grade.Delta = &delta
+		}
+		picks[i].Grade = grade
+	}
+}
+
+// ratingLookup finds a card's GIH WR by Arena id, which 17Lands' JSON
+// carries, or else by name, which is all its CSV has. Names also match on
+// their front face, since Arena and 17Lands name double-faced cards
+// differently.
+type ratingLookup struct {
+	byArenaID map[int64]float64
+	byName    map[string]float64
+}
+
+func newRatingLookup(ratings []db.CardRating) ratingLookup {
+	lookup := ratingLookup{byArenaID: map[int64]float64{}, byName: map[string]float64{}}
+	for _, rating := range ratings {
+		if rating.GIHWR == nil {
+			continue
+		}
+		if rating.ArenaID > 0 {
+			lookup.byArenaID[rating.ArenaID] = *rating.GIHWR
+		}
+		for _, key := range ratingNameKeys(rating.Name) {
+			if _, taken := lookup.byName[key]; !taken {
+				lookup.byName[key] = *rating.GIHWR
+			}
+		}
+	}
+	return lookup
+}
+
+func (l ratingLookup) rating(card model.DraftPickCard) *float64 {
+	if value, ok := l.byArenaID[card.CardID]; ok {
+		return &value
+	}
+	for _, key := range ratingNameKeys(card.CardName) {
+		if value, ok := l.byName[key]; ok {
+			return &value
+		}
+	}
+	return nil
+}
+
+// ratingNameKeys is the lowercased name and, for "Front // Back", the front
+// face alone.
+func ratingNameKeys(name string) []string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil
+	}
+	keys := []string{name}
+	if front, _, split := strings.Cut(name, " // "); split {
+		keys = append(keys, strings.TrimSpace(front))
+	}
+	return keys
+}
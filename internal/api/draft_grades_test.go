@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestDraftPicksGradedAgainstImportedRatings(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	for _, query := range []string{
+		`INSERT INTO draft_sessions (id, event_name, draft_id, is_bot_draft, created_at, updated_at)
+			VALUES (1, 'PremierDraft_BLB_20240801', 'd1', 0, 'x', 'x')`,
+		`INSERT INTO draft_picks (draft_session_id, pack_number, pick_number, picked_card_ids, pack_card_ids, created_at) VALUES
+			(1, 1, 1, '[100]', '[100,200,300]', 'x'),
+			(1, 1, 2, '[400]', '[400]', 'x')`,
+		`INSERT INTO card_catalog (arena_id, name, updated_at) VALUES
+			(100, 'Pond Prophet', 'x'), (200, 'Season of Loss', 'x'), (300, 'Unrated Card', 'x'), (400, 'Also Unrated', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	store := db.NewStore(database)
+	rate := func(v float64) *float64 { return &v }
+	if _, err := store.ReplaceCardRatings(ctx, "blb", "PremierDraft", []db.CardRating{
+		{Name: "Pond Prophet", GIHWR: rate(0.58)},
+		{Name: "Season of Loss", ArenaID: 200, GIHWR: rate(0.61)},
+	}); err != nil {
+		t.Fatalf("ReplaceCardRatings: %v", err)
+	}
+	handler := NewServer(store, "", nil).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/drafts/1/picks", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var picks []model.DraftPickRow
+	if err := json.Unmarshal(rec.Body.Bytes(), &picks); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(picks) != 2 {
+		t.Fatalf("picks = %d, want 2", len(picks))
+	}
+	grade := picks[0].Grade
+	if grade == nil || grade.BestCardID != 200 || grade.BestRating != 0.61 || grade.PickRating == nil || *grade.PickRating != 0.58 {
+		t.Fatalf("grade = %+v, want Pond Prophet (0.58) against Season of Loss (0.61)", grade)
+	}
+	if grade.Delta == nil || *grade.Delta > -0.029 || *grade.Delta < -0.031 {
+		t.Fatalf("delta = %v, want -0.03", grade.Delta)
+	}
+	if picks[1].Grade != nil {
+		t.Fatalf("pick with no rated cards graded %+v", picks[1].Grade)
+	}
+}
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+const (
+	// draftPodSize is how many seats a pack passes through before it comes
+	// back: a card passed at pick p can be taken again at pick p+8.
+	draftPodSize = 8
+	// draftWheeledCardsLimit caps the strong cards listed per set.
+	draftWheeledCardsLimit = 10
+)
+
+// draftRarityOrder is the order first-pick rarities are listed in.
+var draftRarityOrder = []string{"mythic", "rare", "uncommon", "common"}
+
+// handleDraftStats serves GET /api/stats/drafts: per set, what the player
+// first-picks by rarity and color, how often passed cards (and strong ones
+// in particular) wheel, and how far picks stray from the imported ratings.
+func (s *Server) handleDraftStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	ctx := r.Context()
+	sessions, err := s.store.ListDraftSessions(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	states := make(map[string]*draftSetState)
+	var order []string
+	for _, session := range sessions {
+		setCode := strings.ToUpper(limitedSetCode(session.EventName))
+		if setCode == "" {
+			continue
+		}
+		picks, err := s.store.ListDraftPicks(ctx, session.ID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if len(picks) == 0 {
+			continue
+		}
+		state, ok := states[setCode]
+		if !ok {
+			state = s.newDraftSetState(ctx, setCode)
+			states[setCode] = state
+			order = append(order, setCode)
+		}
+		s.enrichDraftPickCardNames(ctx, picks)
+		s.gradeDraftPicks(ctx, session.EventName, picks)
+		state.addDraft(session, picks)
+	}
+
+	var firstPickIDs []int64
+	for _, state := range states {
+		firstPickIDs = append(firstPickIDs, state.firstPickIDs...)
+	}
+	metadata := s.resolveCardMetadata(ctx, firstPickIDs)
+
+	out := model.DraftStatsResponse{Sets: make([]model.DraftSetAnalytics, 0, len(order))}
+	for _, setCode := range order {
+		out.Sets = append(out.Sets, states[setCode].finalize(metadata))
+	}
+	sort.SliceStable(out.Sets, func(i, j int) bool {
+		return out.Sets[i].LastDraftedAt > out.Sets[j].LastDraftedAt
+	})
+	writeJSON(w, http.StatusOK, out)
+}
+
+// draftSetState accumulates one set's drafts.
+type draftSetState struct {
+	stats        model.DraftSetAnalytics
+	firstPickIDs []int64
+	wheeled      map[int64]*model.DraftWheelCard
+	ratingDelta  float64
+}
+
+// newDraftSetState sets the strong-card cut for setCode: the GIH WR at the
+// top quarter of its imported ratings.
+func (s *Server) newDraftSetState(ctx context.Context, setCode string) *draftSetState {
+	state := &draftSetState{
+		stats:   model.DraftSetAnalytics{SetCode: setCode},
+		wheeled: make(map[int64]*model.DraftWheelCard),
+	}
+	ratings, err := s.store.ListCardRatings(ctx, setCode)
+	if err != nil {
+		log.Printf("card ratings lookup failed: %v", err)
+		return state
+	}
+	var rates []float64
+	for _, rating := range ratings {
+		if rating.GIHWR != nil {
+			rates = append(rates, *rating.GIHWR)
+		}
+	}
+	if len(rates) > 0 {
+		slices.Sort(rates)
+		strong := rates[len(rates)*3/4]
+		state.stats.RatingsImported = true
+		state.stats.StrongRating = &strong
+	}
+	return state
+}
+
+func (state *draftSetState) addDraft(session model.DraftSessionRow, picks []model.DraftPickRow) {
+	set := &state.stats
+	set.Drafts++
+	if session.StartedAt > set.LastDraftedAt {
+		set.LastDraftedAt = session.StartedAt
+	}
+
+	type pickKey struct{ pack, pick int64 }
+	byPick := make(map[pickKey]model.DraftPickRow, len(picks))
+	for _, pick := range picks {
+		byPick[pickKey{pick.PackNumber, pick.PickNumber}] = pick
+	}
+	for _, pick := range picks {
+		if pick.PackNumber == 1 && pick.PickNumber == 1 && len(pick.PickedCards) > 0 {
+			set.FirstPicks++
+			state.firstPickIDs = append(state.firstPickIDs, pick.PickedCards[0].CardID)
+		}
+		if pick.Grade != nil && pick.Grade.Delta != nil {
+			set.RatedPicks++
+			state.ratingDelta += *pick.Grade.Delta
+			if *pick.Grade.Delta >= 0 {
+				set.TopRatedPicks++
+			}
+		}
+
+		returned, ok := byPick[pickKey{pick.PackNumber, pick.PickNumber + draftPodSize}]
+		if !ok {
+			continue
+		}
+		back := make(map[int64]bool, len(returned.PackCards))
+		for _, card := range returned.PackCards {
+			back[card.CardID] = true
+		}
+		taken := make(map[int64]bool, len(pick.PickedCards))
+		for _, card := range pick.PickedCards {
+			taken[card.CardID] = true
+		}
+		passed := make(map[int64]bool, len(pick.PackCards))
+		for _, card := range pick.PackCards {
+			if taken[card.CardID] || passed[card.CardID] {
+				continue
+			}
+			passed[card.CardID] = true
+			set.WheelChances++
+			if back[card.CardID] {
+				set.Wheeled++
+			}
+			if set.StrongRating == nil || card.Rating == nil || *card.Rating < *set.StrongRating {
+				continue
+			}
+			set.StrongWheelChances++
+			entry, ok := state.wheeled[card.CardID]
+			if !ok {
+				entry = &model.DraftWheelCard{CardID: card.CardID, CardName: card.CardName, Rating: card.Rating}
+				state.wheeled[card.CardID] = entry
+			}
+			entry.Passed++
+			if back[card.CardID] {
+				set.StrongWheeled++
+				entry.Wheeled++
+			}
+		}
+	}
+}
+
+func (state *draftSetState) finalize(metadata map[int64]db.CardMetadata) model.DraftSetAnalytics {
+	set := state.stats
+	rate := func(part, whole int64) *float64 {
+		if whole == 0 {
+			return nil
+		}
+		value := float64(part) / float64(whole)
+		return &value
+	}
+	set.WheelRate = rate(set.Wheeled, set.WheelChances)
+	set.StrongWheelRate = rate(set.StrongWheeled, set.StrongWheelChances)
+	set.TopRatedPickRate = rate(set.TopRatedPicks, set.RatedPicks)
+	if set.RatedPicks > 0 {
+		average := state.ratingDelta / float64(set.RatedPicks)
+		set.AverageRatingDelta = &average
+	}
+
+	rarities := make(map[string]int64)
+	colors := make(map[string]int64)
+	for _, cardID := range state.firstPickIDs {
+		meta, ok := metadata[cardID]
+		rarity := strings.ToLower(strings.TrimSpace(meta.Rarity))
+		if rarity == "" {
+			rarity = "unknown"
+		}
+		rarities[rarity]++
+		if ok {
+			colors[firstPickColorBucket(parseCachedColorIdentity(meta.ColorIdentity))]++
+		} else {
+			colors["unknown"]++
+		}
+	}
+	set.FirstPickRarities = draftPickShares(rarities, draftRarityOrder, set.FirstPicks)
+	set.FirstPickColors = draftPickShares(colors, append(append([]string{}, deckColorOrder...), "multicolor", "colorless"), set.FirstPicks)
+
+	set.WheeledStrongCards = make([]model.DraftWheelCard, 0, len(state.wheeled))
+	for _, card := range state.wheeled {
+		if card.Wheeled > 0 {
+			set.WheeledStrongCards = append(set.WheeledStrongCards, *card)
+		}
+	}
+	sort.Slice(set.WheeledStrongCards, func(i, j int) bool {
+		a, b := set.WheeledStrongCards[i], set.WheeledStrongCards[j]
+		if a.Wheeled != b.Wheeled {
+			return a.Wheeled > b.Wheeled
+		}
+		return *a.Rating > *b.Rating
+	})
+	if len(set.WheeledStrongCards) > draftWheeledCardsLimit {
+		set.WheeledStrongCards = set.WheeledStrongCards[:draftWheeledCardsLimit]
+	}
+	return set
+}
+
+// draftPickShares lists counts in order, then any other keys alphabetically
+// (such as "unknown").
+func draftPickShares(counts map[string]int64, order []string, total int64) []model.DraftPickShare {
+	keys := make([]string, 0, len(counts))
+	for _, key := range order {
+		if counts[key] > 0 {
+			keys = append(keys, key)
+		}
+	}
+	var rest []string
+	for key := range counts {
+		if !slices.Contains(order, key) {
+			rest = append(rest, key)
+		}
+	}
+	slices.Sort(rest)
+	out := make([]model.DraftPickShare, 0, len(counts))
+	for _, key := range append(keys, rest...) {
+		share := model.DraftPickShare{Key: key, Count: counts[key]}
+		if total > 0 {
+			share.Share = float64(share.Count) / float64(total)
+		}
+		out = append(out, share)
+	}
+	return out
+}
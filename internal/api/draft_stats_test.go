@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestDraftStatsFirstPicksWheelsAndRatingDeviation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// Pick 1 takes 100 over the better-rated 300, which wheels at pick 9.
+	for _, query := range []string{
+		`INSERT INTO draft_sessions (id, event_name, draft_id, is_bot_draft, started_at, created_at, updated_at)
+			VALUES (1, 'PremierDraft_BLB_20240801', 'd1', 0, '2024-08-02T10:00:00Z', 'x', 'x')`,
+		`INSERT INTO draft_picks (draft_session_id, pack_number, pick_number, picked_card_ids, pack_card_ids, created_at) VALUES
+			(1, 1, 1, '[100]', '[100,200,300,400]', 'x'),
+			(1, 1, 9, '[300]', '[300,500]', 'x')`,
+		`INSERT INTO card_catalog (arena_id, name, updated_at) VALUES
+			(100, 'Pond Prophet', 'x'), (200, 'Season of Loss', 'x'), (300, 'Mabel''s Mettle', 'x'),
+			(400, 'Lupinflower Village', 'x'), (500, 'Brambleguard Captain', 'x')`,
+		`INSERT INTO card_metadata (arena_id, color_identity, set_code, rarity, updated_at) VALUES (100, 'GU', 'BLB', 'uncommon', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	store := db.NewStore(database)
+	rate := func(v float64) *float64 { return &v }
+	if _, err := store.ReplaceCardRatings(ctx, "blb", "PremierDraft", []db.CardRating{
+		{ArenaID: 100, Name: "Pond Prophet", GIHWR: rate(0.60)},
+		{ArenaID: 200, Name: "Season of Loss", GIHWR: rate(0.55)},
+		{ArenaID: 300, Name: "Mabel's Mettle", GIHWR: rate(0.62)},
+		{ArenaID: 400, Name: "Lupinflower Village", GIHWR: rate(0.50)},
+		{ArenaID: 500, Name: "Brambleguard Captain", GIHWR: rate(0.52)},
+	}); err != nil {
+		t.Fatalf("ReplaceCardRatings: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(store, "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/drafts", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var stats model.DraftStatsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(stats.Sets) != 1 {
+		t.Fatalf("sets = %+v, want BLB", stats.Sets)
+	}
+	set := stats.Sets[0]
+	if set.SetCode != "BLB" || set.Drafts != 1 || set.FirstPicks != 1 {
+		t.Fatalf("set = %+v", set)
+	}
+	if len(set.FirstPickRarities) != 1 || set.FirstPickRarities[0].Key != "uncommon" ||
+		len(set.FirstPickColors) != 1 || set.FirstPickColors[0].Key != "multicolor" || set.FirstPickColors[0].Share != 1 {
+		t.Fatalf("first picks = %+v / %+v, want one multicolor uncommon", set.FirstPickRarities, set.FirstPickColors)
+	}
+	if set.WheelChances != 3 || set.Wheeled != 1 || set.StrongWheelChances != 1 || set.StrongWheeled != 1 {
+		t.Fatalf("wheels = %d/%d, strong %d/%d; want 1 of 3 and 1 of 1", set.Wheeled, set.WheelChances, set.StrongWheeled, set.StrongWheelChances)
+	}
+	if set.StrongRating == nil || *set.StrongRating != 0.60 || len(set.WheeledStrongCards) != 1 || set.WheeledStrongCards[0].CardName != "Mabel's Mettle" {
+		t.Fatalf("strong cut %v, wheeled strong cards %+v", set.StrongRating, set.WheeledStrongCards)
+	}
+	if set.RatedPicks != 2 || set.TopRatedPicks != 1 || set.AverageRatingDelta == nil || math.Abs(*set.AverageRatingDelta-(-0.01)) > 1e-9 {
+		t.Fatalf("rating deviation = %d/%d, average %v", set.TopRatedPicks, set.RatedPicks, set.AverageRatingDelta)
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// economyGoldPerGem is Arena's own exchange rate for event entries: Premier
+// Draft costs 10,000 gold or 1,500 gems, Quick Draft 5,000 or 750.
+const economyGoldPerGem = 10000.0 / 1500.0
+
+// handleEconomyStats serves GET /api/stats/economy: per event kind, what a
+// settled run costs and returns in gems and gold on average, answering
+// whether an event is worth entering. ?goldPerGem= overrides the rate used
+// to fold gold into the gem-equivalent EV.
+func (s *Server) handleEconomyStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	goldPerGem := economyGoldPerGem
+	if raw := strings.TrimSpace(r.URL.Query().Get("goldPerGem")); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value <= 0 {
+			writeError(w, http.StatusBadRequest, "goldPerGem must be a positive number")
+			return
+		}
+		goldPerGem = value
+	}
+	runs, err := s.store.ListEventRunEconomies(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for index := range runs {
+		runs[index].SetCode = limitedSetCode(runs[index].EventName)
+	}
+	lang := eventnames.Language(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))
+	writeJSON(w, http.StatusOK, buildEconomyStats(runs, goldPerGem, lang))
+}
+
+// buildEconomyStats groups runs by event kind. A run is settled once its
+// rewards are claimed and linked; until then its entry cost alone would
+// drag the EV down, so it is only counted as unsettled.
+func buildEconomyStats(runs []model.EventRunEconomy, goldPerGem float64, lang string) model.EconomyStats {
+	byKind := make(map[string]*model.EventTypeEconomy)
+	for _, run := range runs {
+		kind := eventnames.Kind(run.EventName)
+		if kind == "" {
+			kind = strings.ToLower(strings.TrimSpace(run.EventType))
+		}
+		if kind == "" {
+			kind = "other"
+		}
+		entry, ok := byKind[kind]
+		if !ok {
+			label := eventnames.KindLabel(kind, lang)
+			if label == "" {
+				label = strings.ReplaceAll(kind, "_", " ")
+			}
+			entry = &model.EventTypeEconomy{Kind: kind, DisplayName: label}
+			byKind[kind] = entry
+		}
+		if run.Status == "active" || run.LinkConfidence == "none" {
+			entry.UnsettledRuns++
+			continue
+		}
+		entry.Runs++
+		entry.Wins += run.Wins
+		entry.Losses += run.Losses
+		entry.EntryGold += run.EntryGold
+		entry.EntryGems += run.EntryGems
+		entry.RewardGold += run.RewardGold
+		entry.RewardGems += run.RewardGems
+		entry.RewardCards += run.RewardCards
+		for _, booster := range run.RewardBoosters {
+			entry.RewardBoosters += booster.Count
+		}
+		entry.NetGold += run.NetGold
+		entry.NetGems += run.NetGems
+	}
+
+	out := model.EconomyStats{
+		GoldPerGem: goldPerGem,
+		EventTypes: make([]model.EventTypeEconomy, 0, len(byKind)),
+		Runs:       runs,
+	}
+	for _, entry := range byKind {
+		if entry.Runs > 0 {
+			runs := float64(entry.Runs)
+			entry.AverageWins = float64(entry.Wins) / runs
+			entry.EVGold = float64(entry.NetGold) / runs
+			entry.EVGems = float64(entry.NetGems) / runs
+			entry.EVGemEquivalent = entry.EVGems + entry.EVGold/goldPerGem
+		}
+		out.EventTypes = append(out.EventTypes, *entry)
+	}
+	sort.Slice(out.EventTypes, func(i, j int) bool {
+		a, b := out.EventTypes[i], out.EventTypes[j]
+		if a.Runs != b.Runs {
+			return a.Runs > b.Runs
+		}
+		return a.Kind < b.Kind
+	})
+	return out
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestEconomyStatsAveragesSettledRunsPerEventKind(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	store := db.NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	if _, _, err := store.InsertEconomySnapshot(ctx, tx, "Player.log", 1, db.EconomySnapshotRecord{ObservedAt: "2026-07-12T18:40:38Z"}); err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("insert snapshot: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit snapshot: %v", err)
+	}
+	// A gem entry that won 1,200 gems, a gold entry that won 50, and an
+	// active run whose fee must not count against the EV yet.
+	for _, query := range []string{
+		`INSERT INTO event_runs (event_name, event_type, entry_currency_type, entry_currency_paid, status, started_at, wins, losses, updated_at) VALUES
+			('QuickDraft_BLB_20240801', 'draft', 'Gem', 750, 'claimed', '2026-07-01T10:00:00Z', 6, 3, 'x'),
+			('QuickDraft_DSK_20241001', 'draft', 'Gold', 5000, 'claimed', '2026-07-05T10:00:00Z', 1, 3, 'x'),
+			('QuickDraft_FDN_20241201', 'draft', 'Gem', 750, 'active', '2026-07-10T10:00:00Z', 2, 0, 'x')`,
+		`INSERT INTO economy_transactions (snapshot_id, change_index, source, event_name, event_link, gems_delta, boosters_delta_json, created_at) VALUES
+			(1, 0, 'EventReward', 'QuickDraft_BLB_20240801', 'exact', 1200, '[{"setCode":"BLB","count":1}]', 'x'),
+			(1, 1, 'EventReward', 'QuickDraft_DSK_20241001', 'exact', 50, '[]', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(store, "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/economy?goldPerGem=5", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var stats model.EconomyStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(stats.Runs) != 3 || len(stats.EventTypes) != 1 {
+		t.Fatalf("stats = %+v, want three runs of one kind", stats)
+	}
+	quick := stats.EventTypes[0]
+	if quick.Kind != "quick_draft" || quick.DisplayName != "Quick Draft" || quick.Runs != 2 || quick.UnsettledRuns != 1 {
+		t.Fatalf("quick draft = %+v, want two settled runs and one unsettled", quick)
+	}
+	if quick.NetGems != 500 || quick.NetGold != -5000 || quick.EVGems != 250 || quick.EVGold != -2500 || quick.AverageWins != 3.5 {
+		t.Fatalf("quick draft totals = %+v", quick)
+	}
+	// 250 gems less 2,500 gold at 5 gold per gem.
+	if math.Abs(quick.EVGemEquivalent-(-250)) > 1e-9 || quick.RewardBoosters != 1 {
+		t.Fatalf("quick draft gem-equivalent EV = %v, boosters %d; want -250 and 1", quick.EVGemEquivalent, quick.RewardBoosters)
+	}
+
+	rec = httptest.NewRecorder()
+	NewServer(store, "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/economy?goldPerGem=0", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("goldPerGem=0 status = %d, want 400", rec.Code)
+	}
+}
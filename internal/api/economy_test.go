@@ -8,8 +8,8 @@ import (
 	"path/filepath"
 	"testing"
 
-	"github.com/solean/ponder/internal/db"
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
 )
 
 func TestEconomyEndpointReturnsLatestAndHistory(t *testing.T) {
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// etagPaths are the list endpoints whose responses are worth revalidating:
+// they are large, polled on every page visit, and usually unchanged between
+// visits. Detail, live, and streaming endpoints are deliberately excluded —
+// buffering them would defeat streaming or waste work on one-off reads.
+var etagPaths = map[string]bool{
+	"/api/overview":         true,
+	"/api/matches":          true,
+	"/api/decks":            true,
+	"/api/drafts":           true,
+	"/api/economy":          true,
+	"/api/rank-history":     true,
+	"/api/queue-times":      true,
+	"/api/limited/matchups": true,
+}
+
+// localizedPaths are the etagPaths whose bodies carry event display names
+// in the language Accept-Language picks, so caches must key on it as well.
+var localizedPaths = map[string]bool{
+	"/api/matches":      true,
+	"/api/decks":        true,
+	"/api/drafts":       true,
+	"/api/rank-history": true,
+}
+
+// etagRecorder buffers a handler's response so its body can be hashed before
+// anything reaches the client.
+type etagRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *etagRecorder) Header() http.Header { return r.header }
+
+func (r *etagRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *etagRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// withETag tags successful list responses with a weak ETag over the
+// uncompressed body and answers matching If-None-Match revalidations with 304,
+// so an unchanged matches list costs one hash instead of a full transfer. The
+// ETag is weak because gzip may re-encode the same representation. Localized
+// lists also vary on Accept-Language, since each language has its own body.
+func withETag(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method != http.MethodGet && r.Method != http.MethodHead) || !etagPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &etagRecorder{header: make(http.Header)}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		for key, values := range rec.header {
+			w.Header()[key] = values
+		}
+		if localizedPaths[r.URL.Path] {
+			w.Header().Add("Vary", "Accept-Language")
+		}
+		if rec.status != http.StatusOK {
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(rec.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(rec.body.Bytes())
+		etag := `W/"` + hex.EncodeToString(sum[:12]) + `"`
+		w.Header().Set("ETag", etag)
+		// Always revalidate: the data changes whenever ingest runs.
+		w.Header().Set("Cache-Control", "no-cache")
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Type")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(rec.body.Bytes())
+	})
+}
+
+// etagMatches implements If-None-Match's weak comparison: any listed tag
+// (or "*") equal to ours once W/ prefixes are ignored.
+func etagMatches(header, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == want {
+			return true
+		}
+	}
+	return false
+}
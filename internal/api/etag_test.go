@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestListResponsesRevalidateWithETag(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	handler := NewServer(db.NewStore(database), "", nil).Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/matches", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("first status = %d, want 200", rec.Code)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("missing ETag on list response")
+	}
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("list response not gzipped")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/matches", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("revalidation status = %d, want 304", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Fatalf("304 carried a %d-byte body", rec.Body.Len())
+	}
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("304 kept Content-Encoding %q", rec.Header().Get("Content-Encoding"))
+	}
+	if vary := rec.Header().Values("Vary"); !slices.Contains(vary, "Accept-Language") || !slices.Contains(vary, "Accept-Encoding") {
+		t.Fatalf("localized list Vary = %q, want Accept-Language and Accept-Encoding", vary)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/matches", nil)
+	req.Header.Set("If-None-Match", `W/"stale"`)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("stale revalidation status = %d, want 200", rec.Code)
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	cases := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"*", true},
+		{`W/"abc"`, true},
+		{`"abc"`, true},
+		{`"x", W/"abc"`, true},
+		{`"abcd"`, false},
+	}
+	for _, tc := range cases {
+		if got := etagMatches(tc.header, `W/"abc"`); got != tc.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tc.header, got, tc.want)
+		}
+	}
+}
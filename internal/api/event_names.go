@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// eventNameTarget pairs a raw event name with the field its display name is
+// written to.
+type eventNameTarget struct {
+	raw     string
+	display *string
+}
+
+// wantsRawEventNames reports whether the caller opted out of display names
+// with ?raw=true, e.g. a script that only keys on Arena identifiers.
+func wantsRawEventNames(r *http.Request) bool {
+	raw := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("raw")))
+	return raw == "1" || raw == "true"
+}
+
+// enrichEventDisplayNames fills each target's display name in the language
+// the request asks for (?lang= or Accept-Language). Set names come from the
+// cached set catalog only; uncached sets show their code.
+func (s *Server) enrichEventDisplayNames(r *http.Request, targets []eventNameTarget) {
+	if len(targets) == 0 || wantsRawEventNames(r) {
+		return
+	}
+	lang := eventnames.Language(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))
+	setNames := s.eventSetNames(r.Context(), targets)
+	for _, target := range targets {
+		code := strings.ToLower(eventnames.SetCode(target.raw))
+		*target.display = eventnames.Display(target.raw, lang, setNames[code])
+	}
+}
+
+func (s *Server) eventSetNames(ctx context.Context, targets []eventNameTarget) map[string]string {
+	out := map[string]string{}
+	if s.store == nil {
+		return out
+	}
+	seen := map[string]bool{}
+	codes := []string{}
+	for _, target := range targets {
+		code := strings.ToLower(eventnames.SetCode(target.raw))
+		if code != "" && !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	sets, err := s.store.LookupSets(ctx, codes)
+	if err != nil {
+		return out
+	}
+	for code, info := range sets {
+		out[strings.ToLower(code)] = info.Name
+	}
+	return out
+}
+
+// eventTargets lists a target for each row; fields returns a row's raw
+// event name and the field its display name goes to.
+func eventTargets[Row any](rows []Row, fields func(*Row) (raw string, display *string)) []eventNameTarget {
+	targets := make([]eventNameTarget, 0, len(rows))
+	for index := range rows {
+		raw, display := fields(&rows[index])
+		targets = append(targets, eventNameTarget{raw: raw, display: display})
+	}
+	return targets
+}
+
+func matchEventTargets(rows []model.MatchRow) []eventNameTarget {
+	return eventTargets(rows, func(row *model.MatchRow) (string, *string) { return row.EventName, &row.EventDisplayName })
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestMatchesCarryEventDisplayNamesUnlessRaw(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	store := db.NewStore(database)
+	if err := store.UpsertSets(ctx, map[string]model.SetInfo{"blb": {Code: "blb", Name: "Bloomburrow"}}); err != nil {
+		t.Fatalf("upsert sets: %v", err)
+	}
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	if _, err := store.UpsertMatchStart(ctx, tx, "match-1", "PremierDraft_BLB_20240730", 1, "2026-07-12T18:00:00Z"); err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("insert match: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	handler := NewServer(store, "", nil).Handler()
+
+	fetch := func(target, acceptLanguage string) model.MatchRow {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: status = %d", target, rec.Code)
+		}
+		var rows []model.MatchRow
+		if err := json.Unmarshal(rec.Body.Bytes(), &rows); err != nil {
+			t.Fatalf("decode %s: %v", target, err)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("GET %s: rows = %d, want 1", target, len(rows))
+		}
+		return rows[0]
+	}
+
+	if got := fetch("/api/matches", "").EventDisplayName; got != "Premier Draft: Bloomburrow" {
+		t.Fatalf("eventDisplayName = %q, want Premier Draft: Bloomburrow", got)
+	}
+	if got := fetch("/api/matches", "fr-FR,fr;q=0.9").EventDisplayName; got != "Draft Premier: Bloomburrow" {
+		t.Fatalf("French eventDisplayName = %q", got)
+	}
+	row := fetch("/api/matches?raw=true", "")
+	if row.EventDisplayName != "" || row.EventName != "PremierDraft_BLB_20240730" {
+		t.Fatalf("raw row = %q / %q, want raw name only", row.EventName, row.EventDisplayName)
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/solean/ponder/internal/export"
+	"github.com/solean/ponder/pkg/db"
+)
+
+// exportFlushEvery bounds how many rows sit in the response buffers before
+// they are pushed to the client, so long exports show progress and proxies
+// don't time out waiting for the first byte.
+const exportFlushEvery = 500
+
+// rowStreamer writes each row as it is produced instead of marshaling the
+// whole result set. NDJSON is the default; format=json wraps the same rows in
+// a single array, format=csv writes one header line then a line per row, and
+// format=parquet writes a row group every 50,000 rows and the footer last.
+type rowStreamer struct {
+	w       export.Writer
+	flusher http.Flusher
+	rows    int
+}
+
+func newRowStreamer(w http.ResponseWriter, format, filename string) (*rowStreamer, error) {
+	writer, err := export.NewWriter(w, format)
+	if err != nil {
+		return nil, err
+	}
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+export.Extension(format)+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	streamer := &rowStreamer{w: writer}
+	streamer.flusher, _ = w.(http.Flusher)
+	return streamer, nil
+}
+
+func (s *rowStreamer) write(row any) error {
+	if err := s.w.Write(row); err != nil {
+		return err
+	}
+	s.rows++
+	if s.flusher != nil && s.rows%exportFlushEvery == 0 {
+		s.flusher.Flush()
+	}
+	return nil
+}
+
+func (s *rowStreamer) close() {
+	_ = s.w.Close()
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+// handleExport streams whole tables for offline analysis:
+// /api/export/matches, /card-plays, /decks, and /draft-picks, filtered by
+// event, result, and includeArchived as /api/matches is, and /collection,
+// which also takes format=mtggoldfish or deckbox. The status line is
+// sent before the first row, so a mid-stream failure can only be logged and
+// the response truncated.
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	query := r.URL.Query()
+	format := query.Get("format")
+	includeArchived := strings.ToLower(strings.TrimSpace(query.Get("includeArchived")))
+	filter := db.MatchFilter{
+		EventName:       strings.TrimSpace(query.Get("event")),
+		Result:          strings.TrimSpace(query.Get("result")),
+		IncludeArchived: includeArchived == "1" || includeArchived == "true",
+	}
+
+	table := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/export/"), "/")
+	if !export.TableKnown(table) {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if export.IsCollectionFormat(format) && table != "collection" {
+		writeError(w, http.StatusBadRequest, "format "+format+" only exports the collection")
+		return
+	}
+	streamer, err := newRowStreamer(w, format, table)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := export.StreamTable(r.Context(), s.store, table, filter, streamer.write); err != nil {
+		log.Printf("export %s aborted after %d rows: %v", r.URL.Path, streamer.rows, err)
+		return
+	}
+	streamer.close()
+}
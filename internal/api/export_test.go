@@ -0,0 +1,174 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestExportMatchesStreamsNDJSONAndJSONArray(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	store := db.NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	const matchCount = 1200
+	for index := 0; index < matchCount; index++ {
+		startedAt := fmt.Sprintf("2026-07-12T18:%02d:%02dZ", index/60%60, index%60)
+		if _, err := store.UpsertMatchStart(ctx, tx, fmt.Sprintf("match-%d", index), "Ladder", 1, startedAt); err != nil {
+			_ = tx.Rollback()
+			t.Fatalf("insert match %d: %v", index, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit matches: %v", err)
+	}
+	handler := NewServer(store, "", nil).Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export/matches", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ndjson status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Fatalf("ndjson content type = %q", got)
+	}
+	lines := 0
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var row model.MatchRow
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("decode ndjson line %d: %v", lines, err)
+		}
+		lines++
+	}
+	if lines != matchCount {
+		t.Fatalf("ndjson rows = %d, want %d", lines, matchCount)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/export/matches?format=json", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var rows []model.MatchRow
+	if err := json.NewDecoder(rec.Body).Decode(&rows); err != nil {
+		t.Fatalf("decode json array: %v", err)
+	}
+	if len(rows) != matchCount {
+		t.Fatalf("json rows = %d, want %d", len(rows), matchCount)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/export/matches?format=csv", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("decode csv: %v", err)
+	}
+	if len(records) != matchCount+1 || records[0][0] != "id" {
+		t.Fatalf("csv records = %d (header %v), want header plus %d rows", len(records), records[0], matchCount)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/export/matches?format=parquet", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !bytes.HasPrefix(rec.Body.Bytes(), []byte("PAR1")) || !bytes.HasSuffix(rec.Body.Bytes(), []byte("PAR1")) {
+		t.Fatalf("parquet export status = %d, want a Parquet file", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/export/matches?format=xlsx", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unsupported format status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestExportCollectionInSiteLayouts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	for _, query := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, created_at, updated_at) VALUES (1, 'arena-deck', 'Burn', 'x', 'x')`,
+		`INSERT INTO deck_versions (id, deck_id, version_number, cards_hash, created_at) VALUES (1, 1, 1, 'a', 'x'), (2, 1, 2, 'b', 'x')`,
+		`INSERT INTO deck_version_cards (deck_version_id, section, card_id, quantity) VALUES
+			(1, 'main', 100, 2), (2, 'main', 100, 3), (2, 'sideboard', 100, 1), (2, 'main', 200, 20)`,
+		`INSERT INTO draft_sessions (id, event_name, draft_id, is_bot_draft, created_at, updated_at) VALUES (1, 'QuickDraft_DMU', 'd1', 1, 'x', 'x')`,
+		`INSERT INTO draft_picks (draft_session_id, pack_number, pick_number, picked_card_ids, created_at) VALUES
+			(1, 1, 1, '[300]', 'x'), (1, 1, 2, '[300]', 'x')`,
+		`INSERT INTO card_catalog (arena_id, name, updated_at) VALUES
+			(100, 'Lightning Strike', 'x'), (200, 'Mountain', 'x'), (300, 'Abrade', 'x')`,
+		`INSERT INTO card_metadata (arena_id, set_code, collector_number, updated_at) VALUES
+			(100, 'dmu', '137', 'x'), (300, 'dmu', '117', 'x')`,
+		`INSERT INTO set_catalog (code, name, updated_at) VALUES ('dmu', 'Dominaria United', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	handler := NewServer(db.NewStore(database), "", nil).Handler()
+
+	read := func(path string) [][]string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s status = %d; body: %s", path, rec.Code, rec.Body.String())
+		}
+		records, err := csv.NewReader(rec.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("decode %s: %v", path, err)
+		}
+		return records
+	}
+
+	got := fmt.Sprint(read("/api/export/collection?format=mtggoldfish"))
+	want := fmt.Sprint([][]string{
+		{"Card", "Set ID", "Set Name", "Quantity", "Foil"},
+		{"Abrade", "DMU", "Dominaria United", "2", "REGULAR"},
+		{"Lightning Strike", "DMU", "Dominaria United", "4", "REGULAR"},
+	})
+	if got != want {
+		t.Fatalf("mtggoldfish =\n%s\nwant\n%s", got, want)
+	}
+	deckbox := read("/api/export/collection?format=deckbox")
+	if len(deckbox) != 3 || deckbox[2][0] != "4" || deckbox[2][2] != "Lightning Strike" || deckbox[2][3] != "Dominaria United" || deckbox[2][4] != "137" {
+		t.Fatalf("deckbox = %v", deckbox)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/export/matches?format=deckbox", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("deckbox matches status = %d, want 400", rec.Code)
+	}
+}
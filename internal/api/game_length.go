@@ -0,0 +1,19 @@
+package api
+
+import "net/http"
+
+// handleGameLengthStats serves GET /api/stats/game-length: average turns
+// and seconds per match, overall and by deck, format, set, and month, to
+// show how grindy each deck is and how the pace of play moves over time.
+func (s *Server) handleGameLengthStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	out, err := s.store.GameLengthStats(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
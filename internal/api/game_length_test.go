@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestGameLengthStatsGroupByDeckFormatSetAndMonth(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	for _, query := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, created_at, updated_at) VALUES (1, 'd1', 'Mono Red', 'x', 'x')`,
+		`INSERT INTO matches (id, arena_match_id, event_name, format, started_at, result, turn_count, seconds_count, created_at, updated_at) VALUES
+			(1, 'm1', 'Ladder', 'Standard', '2026-02-10T19:00:00Z', 'win', 6, 600, 'x', 'x'),
+			(2, 'm2', 'Ladder', 'Standard', '2026-03-01T19:00:00Z', 'loss', 10, 1200, 'x', 'x'),
+			(3, 'm3', 'QuickDraft_BLB_20240801', 'Limited', '2026-03-02T19:00:00Z', 'win', 12, NULL, 'x', 'x'),
+			(4, 'm4', 'QuickDraft_BLB_20240801', 'Limited', '2026-03-03T19:00:00Z', 'loss', NULL, NULL, 'x', 'x')`,
+		`INSERT INTO match_decks (match_id, deck_id, snapshot_reason, created_at) VALUES
+			(1, 1, 'x', 'x'), (2, 1, 'x', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(db.NewStore(database), "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/game-length", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var stats model.GameLengthStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	overall := stats.Overall
+	if overall.Matches != 4 || overall.TurnMatches != 3 || *overall.AverageTurns != 28.0/3 || overall.SecondsMatches != 2 || *overall.AverageSeconds != 900 {
+		t.Fatalf("overall = %+v", overall)
+	}
+	if *overall.WinAverageTurns != 9 || *overall.LossAverageTurns != 10 {
+		t.Fatalf("overall win/loss turns = %v/%v, want 9 and 10", *overall.WinAverageTurns, *overall.LossAverageTurns)
+	}
+	if len(stats.Decks) != 1 || stats.Decks[0].Label != "Mono Red" || *stats.Decks[0].AverageTurns != 8 {
+		t.Fatalf("decks = %+v, want Mono Red averaging 8 turns", stats.Decks)
+	}
+	if len(stats.Formats) != 2 || len(stats.Sets) != 1 || stats.Sets[0].Key != "BLB" || stats.Sets[0].AverageSeconds != nil {
+		t.Fatalf("formats = %+v, sets = %+v", stats.Formats, stats.Sets)
+	}
+	if len(stats.Months) != 2 || stats.Months[0].Key != "2026-02" || stats.Months[1].Matches != 3 {
+		t.Fatalf("months = %+v, want February then three March matches", stats.Months)
+	}
+}
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/solean/ponder/internal/graphql"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// maxGraphQLBodyBytes caps query documents; real queries are a few KB.
+const maxGraphQLBodyBytes = 1 << 20
+
+// maxGraphQLDepth and maxGraphQLFields bound what one query may ask for.
+// The deepest real query (a match's deck's analytics and their rows) nests
+// five levels, and selecting every field the UI shows stays well under the
+// field budget; the limits are there to stop relationship fields fanning a
+// small document out into thousands of store queries.
+const (
+	maxGraphQLDepth  = 8
+	maxGraphQLFields = 500
+)
+
+// handleGraphQL serves read-only GraphQL queries over the same data as the
+// REST endpoints. Object fields use the REST JSON names; relationship fields
+// (a match's deck, plays, and opponent cards; a deck's analytics; a draft's
+// picks) let one request replace several REST round trips.
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, maxGraphQLBodyBytes)
+		if err := decodeJSONBody(r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		writeError(w, http.StatusBadRequest, "missing query")
+		return
+	}
+
+	resp := s.graphQLSchema(r).Execute(r.Context(), req)
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, resp)
+}
+
+// graphQLSchema builds the resolvers for one request so relationship fields
+// can share its context, its display-name preferences, and a memo of match
+// and deck details already loaded by sibling fields.
+func (s *Server) graphQLSchema(r *http.Request) *graphql.Schema {
+	matchDetails := map[int64]model.MatchDetail{}
+	matchDetail := func(id int64) (*model.MatchDetail, error) {
+		if detail, ok := matchDetails[id]; ok {
+			return &detail, nil
+		}
+		detail, err := s.loadMatchDetail(r, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		matchDetails[id] = detail
+		return &detail, nil
+	}
+	deckDetails := map[int64]model.DeckDetail{}
+	deckDetail := func(id int64) (*model.DeckDetail, error) {
+		if detail, ok := deckDetails[id]; ok {
+			return &detail, nil
+		}
+		detail, err := s.loadDeckDetail(r, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		deckDetails[id] = detail
+		return &detail, nil
+	}
+	drafts := func(ctx context.Context) ([]model.DraftSessionRow, error) {
+		rows, err := s.store.ListDraftSessions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.enrichEventDisplayNames(r, eventTargets(rows, func(row *model.DraftSessionRow) (string, *string) { return row.EventName, &row.EventDisplayName }))
+		return rows, nil
+	}
+	// matchField adapts a MatchDetail projection into a MatchRow field.
+	matchField := func(pick func(*model.MatchDetail) any) graphql.FieldResolver {
+		return func(_ context.Context, parent any, _ graphql.Args) (any, error) {
+			detail, err := matchDetail(parent.(model.MatchRow).ID)
+			if err != nil || detail == nil {
+				return nil, err
+			}
+			return pick(detail), nil
+		}
+	}
+
+	return &graphql.Schema{
+		Query: map[string]graphql.RootResolver{
+			"overview": func(ctx context.Context, args graphql.Args) (any, error) {
+				scope, err := parseOverviewScope(args.String("from", ""), args.String("to", ""), args.String("format", ""), args.String("queue", ""))
+				if err != nil {
+					return nil, err
+				}
+				out, err := s.store.Overview(ctx, args.Int("recent", 20), scope)
+				if err != nil {
+					return nil, err
+				}
+				s.enrichMatchDeckColors(ctx, out.Recent)
+				s.enrichEventDisplayNames(r, matchEventTargets(out.Recent))
+				return out, nil
+			},
+			"matches": func(ctx context.Context, args graphql.Args) (any, error) {
+				rows, err := s.store.ListMatches(ctx, args.Int("limit", 200), args.String("event", ""), args.String("result", ""), args.Bool("includeArchived", false))
+				if err != nil {
+					return nil, err
+				}
+				s.enrichMatchDeckColors(ctx, rows)
+				s.enrichEventDisplayNames(r, matchEventTargets(rows))
+				return rows, nil
+			},
+			"match": func(_ context.Context, args graphql.Args) (any, error) {
+				detail, err := matchDetail(args.Int("id", 0))
+				if err != nil || detail == nil {
+					return nil, err
+				}
+				return detail.Match, nil
+			},
+			"decks": func(ctx context.Context, args graphql.Args) (any, error) {
+				scope := strings.ToLower(args.String("scope", ""))
+				switch scope {
+				case "", "constructed", "draft", "all":
+				default:
+					return nil, fmt.Errorf("invalid scope %q (use constructed, draft, or all)", scope)
+				}
+				rows, err := s.store.ListDecksByScope(ctx, scope)
+				if err != nil {
+					return nil, err
+				}
+				s.enrichEventDisplayNames(r, eventTargets(rows, func(row *model.DeckSummaryRow) (string, *string) { return row.EventName, &row.EventDisplayName }))
+				return rows, nil
+			},
+			"deck": func(_ context.Context, args graphql.Args) (any, error) {
+				return deckDetail(args.Int("id", 0))
+			},
+			"drafts": func(ctx context.Context, _ graphql.Args) (any, error) {
+				return drafts(ctx)
+			},
+			"draft": func(ctx context.Context, args graphql.Args) (any, error) {
+				rows, err := drafts(ctx)
+				if err != nil {
+					return nil, err
+				}
+				id := args.Int("id", 0)
+				for _, row := range rows {
+					if row.ID == id {
+						return row, nil
+					}
+				}
+				return nil, nil
+			},
+			"rankHistory": func(ctx context.Context, _ graphql.Args) (any, error) {
+				rows, err := s.store.ListRankHistory(ctx)
+				if err != nil {
+					return nil, err
+				}
+				s.enrichEventDisplayNames(r, eventTargets(rows, func(row *model.RankHistoryPoint) (string, *string) { return row.EventName, &row.EventDisplayName }))
+				return rows, nil
+			},
+			"queueTimes": func(ctx context.Context, _ graphql.Args) (any, error) {
+				return s.store.QueueTimeStats(ctx)
+			},
+		},
+		Fields: map[reflect.Type]map[string]graphql.FieldResolver{
+			reflect.TypeOf(model.MatchRow{}): {
+				"deck": func(_ context.Context, parent any, _ graphql.Args) (any, error) {
+					row := parent.(model.MatchRow)
+					if row.DeckID == nil {
+						return nil, nil
+					}
+					return deckDetail(*row.DeckID)
+				},
+				"games":         matchField(func(d *model.MatchDetail) any { return d.Games }),
+				"cardPlays":     matchField(func(d *model.MatchDetail) any { return d.CardPlays }),
+				"opponentCards": matchField(func(d *model.MatchDetail) any { return d.OpponentObservedCards }),
+				"coverage":      matchField(func(d *model.MatchDetail) any { return d.Coverage }),
+			},
+			reflect.TypeOf(model.DeckSummaryRow{}): {
+				"detail": func(_ context.Context, parent any, _ graphql.Args) (any, error) {
+					return deckDetail(parent.(model.DeckSummaryRow).DeckID)
+				},
+			},
+			reflect.TypeOf(model.DeckDetail{}): {
+				"analytics": func(ctx context.Context, parent any, args graphql.Args) (any, error) {
+					return s.store.GetDeckAnalytics(ctx, parent.(model.DeckDetail).DeckID, args.Int("version", 0))
+				},
+			},
+			reflect.TypeOf(model.DraftSessionRow{}): {
+				"draftPicks": func(ctx context.Context, parent any, _ graphql.Args) (any, error) {
+					rows, err := s.store.ListDraftPicks(ctx, parent.(model.DraftSessionRow).ID)
+					if err != nil {
+						return nil, err
+					}
+					s.enrichDraftPickCardNames(ctx, rows)
+					return rows, nil
+				},
+			},
+		},
+		MaxDepth:  maxGraphQLDepth,
+		MaxFields: maxGraphQLFields,
+	}
+}
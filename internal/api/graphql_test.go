@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestGraphQLFetchesMatchWithDeckAndPlaysInOneRequest(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	store := db.NewStore(database)
+	if err := store.UpsertCardNames(ctx, map[int64]string{5001: "Lightning Strike"}); err != nil {
+		t.Fatalf("upsert card names: %v", err)
+	}
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	matchID, err := store.UpsertMatchStart(ctx, tx, "match-gql", "Traditional_Ladder", 1, "2026-07-12T18:00:00Z")
+	if err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("insert match: %v", err)
+	}
+	cards := []db.DeckCard{{Section: "main", CardID: 5001, Quantity: 4}}
+	if _, err := store.UpsertDeck(ctx, tx, "deck-gql", "Traditional_Ladder", "Izzet Prowess", "TraditionalStandard", "test", "2026-07-01T00:00:00Z", cards); err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("upsert deck: %v", err)
+	}
+	if _, err := store.LinkMatchToDeckByArenaDeckID(ctx, tx, "match-gql", "deck-gql", "event_deck"); err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("link deck: %v", err)
+	}
+	if err := store.UpsertMatchCardPlay(ctx, tx, "match-gql", 1, 101, 5001, 1, 3, "main1", "stack", "2026-07-12T18:05:00Z", "test"); err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("insert card play: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	handler := NewServer(store, "", nil).Handler()
+
+	body, _ := json.Marshal(map[string]any{
+		"query": `query Match($id: Int!) {
+			match(id: $id) {
+				id
+				eventName
+				deck { name cards { cardName quantity } }
+				cardPlays { turnNumber cardName }
+				missing: match_nope
+			}
+			nothing: match(id: 999999) { id }
+		}`,
+		"variables": map[string]any{"id": matchID},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Match struct {
+				ID        int64  `json:"id"`
+				EventName string `json:"eventName"`
+				Deck      struct {
+					Name  string              `json:"name"`
+					Cards []model.DeckCardRow `json:"cards"`
+				} `json:"deck"`
+				CardPlays []model.MatchCardPlayRow `json:"cardPlays"`
+			} `json:"match"`
+			Nothing *struct{} `json:"nothing"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+			Path    []any  `json:"path"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	match := resp.Data.Match
+	if match.ID != matchID || match.EventName != "Traditional_Ladder" {
+		t.Fatalf("match = %+v", match)
+	}
+	if match.Deck.Name != "Izzet Prowess" || len(match.Deck.Cards) != 1 || match.Deck.Cards[0].CardName != "Lightning Strike" {
+		t.Fatalf("deck = %+v", match.Deck)
+	}
+	if len(match.CardPlays) != 1 || match.CardPlays[0].CardName != "Lightning Strike" || match.CardPlays[0].TurnNumber == nil || *match.CardPlays[0].TurnNumber != 3 {
+		t.Fatalf("cardPlays = %+v", match.CardPlays)
+	}
+	if resp.Data.Nothing != nil {
+		t.Fatalf("unknown match resolved to %+v, want null", resp.Data.Nothing)
+	}
+	if len(resp.Errors) != 1 || len(resp.Errors[0].Path) != 2 || resp.Errors[0].Path[1] != "missing" {
+		t.Fatalf("errors = %+v, want one error at match.missing", resp.Errors)
+	}
+}
+
+func TestGraphQLRejectsMutationsEmptyAndOverlyDeepQueries(t *testing.T) {
+	t.Parallel()
+
+	handler := NewServer(nil, "", nil).Handler()
+	for _, tc := range []struct {
+		body string
+		want int
+	}{
+		{body: `{}`, want: http.StatusBadRequest},
+		{body: `{"query":"mutation { deleteEverything }"}`, want: http.StatusBadRequest},
+		{body: `{"query":"{ match(id: 1) { id }"}`, want: http.StatusBadRequest},
+		{body: `{"query":"{ match(id: 1) { deck { detail { a { b { c { d { e { f } } } } } } } } }"}`, want: http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/graphql", bytes.NewReader([]byte(tc.body)))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Fatalf("%s: status = %d, want %d", tc.body, rec.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestHealthReportsSchemaVersionAndIngestLag(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	database, err := db.Open(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	store := db.NewStore(database)
+
+	logPath := filepath.Join(dir, "Player.log")
+	if err := os.WriteFile(logPath, make([]byte, 100), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	if err := store.SaveIngestState(ctx, tx, logPath, 60, 3); err != nil {
+		t.Fatalf("save ingest state: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(store, "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var health model.HealthStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("decode health: %v", err)
+	}
+	if health.Status != "ok" || !health.Database {
+		t.Fatalf("health = %+v, want ok with database reachable", health)
+	}
+	if health.SchemaVersion != db.SchemaVersion {
+		t.Fatalf("schemaVersion = %d, want %d", health.SchemaVersion, db.SchemaVersion)
+	}
+	if len(health.Logs) != 1 {
+		t.Fatalf("logs = %+v, want one entry", health.Logs)
+	}
+	got := health.Logs[0]
+	if got.Offset != 60 || got.FileSize != 100 || got.BytesBehind != 40 {
+		t.Fatalf("log status = %+v, want offset 60 of 100 (40 behind)", got)
+	}
+	if health.LastIngestAt == "" || health.LastIngestAt != got.UpdatedAt {
+		t.Fatalf("lastIngestAt = %q, want %q", health.LastIngestAt, got.UpdatedAt)
+	}
+}
+
+func TestHealthReportsUnreachableDatabase(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	_ = database.Close()
+
+	rec := httptest.NewRecorder()
+	NewServer(db.NewStore(database), "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+	var health model.HealthStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("decode health: %v", err)
+	}
+	if health.Database || health.Status != "unavailable" || health.DatabaseError == "" {
+		t.Fatalf("health = %+v, want unavailable database", health)
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// handleLimitedColors serves GET /api/stats/limited/colors?set=BLB: the
+// set's draft runs grouped by the final build's main colors and by splash.
+// Cards with no cached colors are looked up, and cached, before grouping.
+func (s *Server) handleLimitedColors(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	setCode := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("set")))
+	if setCode == "" {
+		writeError(w, http.StatusBadRequest, "set is required")
+		return
+	}
+
+	ctx := r.Context()
+	decks, err := s.store.ListLimitedDeckColors(ctx, setCode)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var unresolved []int64
+	for _, deck := range decks {
+		unresolved = append(unresolved, deck.UnresolvedCardIDs...)
+	}
+	if len(unresolved) > 0 && len(s.resolveCardMetadata(ctx, unresolved)) > 0 {
+		if decks, err = s.store.ListLimitedDeckColors(ctx, setCode); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, buildLimitedColorStats(setCode, decks))
+}
+
+func buildLimitedColorStats(setCode string, decks []db.LimitedDeckColors) model.LimitedColorStats {
+	out := model.LimitedColorStats{
+		SetCode:    setCode,
+		ColorPairs: []model.LimitedColorRecord{},
+		Splashes:   []model.LimitedColorRecord{},
+		Splashed:   model.LimitedColorRecord{Key: "splashed", Colors: []string{}, Splash: []string{}},
+		Unsplashed: model.LimitedColorRecord{Key: "unsplashed", Colors: []string{}, Splash: []string{}},
+	}
+	pairs := map[string]*model.LimitedColorRecord{}
+	splashes := map[string]*model.LimitedColorRecord{}
+	group := func(groups map[string]*model.LimitedColorRecord, colors, splash []string) *model.LimitedColorRecord {
+		key := strings.Join(colors, "")
+		if len(splash) > 0 {
+			key += "+" + strings.Join(splash, "")
+		}
+		record, ok := groups[key]
+		if !ok {
+			record = &model.LimitedColorRecord{
+				Key:    key,
+				Colors: append([]string{}, colors...),
+				Splash: append([]string{}, splash...),
+			}
+			groups[key] = record
+		}
+		return record
+	}
+
+	for _, deck := range decks {
+		if deck.Wins+deck.Losses == 0 {
+			continue
+		}
+		out.Runs++
+		if !deck.Known {
+			out.UnknownRuns++
+			continue
+		}
+		trophy := deck.Wins >= db.LimitedTrophyWins(deck.EventName)
+		records := []*model.LimitedColorRecord{group(pairs, deck.Colors, nil)}
+		if len(deck.Splash) > 0 {
+			records = append(records, group(splashes, deck.Colors, deck.Splash), &out.Splashed)
+		} else {
+			records = append(records, &out.Unsplashed)
+		}
+		for _, record := range records {
+			record.Runs++
+			record.Wins += deck.Wins
+			record.Losses += deck.Losses
+			if trophy {
+				record.Trophies++
+			}
+		}
+	}
+
+	collect := func(groups map[string]*model.LimitedColorRecord) []model.LimitedColorRecord {
+		records := make([]model.LimitedColorRecord, 0, len(groups))
+		for _, record := range groups {
+			record.AverageWins, record.WinRate = limitedRunRates(record.Runs, record.Wins, record.Losses)
+			records = append(records, *record)
+		}
+		sort.Slice(records, func(i, j int) bool {
+			if records[i].Runs != records[j].Runs {
+				return records[i].Runs > records[j].Runs
+			}
+			return records[i].Key < records[j].Key
+		})
+		return records
+	}
+	out.ColorPairs = collect(pairs)
+	out.Splashes = collect(splashes)
+	out.Splashed.AverageWins, out.Splashed.WinRate = limitedRunRates(out.Splashed.Runs, out.Splashed.Wins, out.Splashed.Losses)
+	out.Unsplashed.AverageWins, out.Unsplashed.WinRate = limitedRunRates(out.Unsplashed.Runs, out.Unsplashed.Wins, out.Unsplashed.Losses)
+	return out
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestLimitedColorsGroupsRunsByPairAndSplash(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	for _, query := range []string{
+		`INSERT INTO draft_sessions (id, event_name, draft_id, is_bot_draft, started_at, created_at, updated_at) VALUES
+			(1, 'PremierDraft_BLB_20240801', 'd1', 0, '2024-08-02T10:00:00Z', 'x', 'x'),
+			(2, 'QuickDraft_BLB_20240815', 'd2', 1, '2024-08-16T10:00:00Z', 'x', 'x'),
+			(3, 'QuickDraft_DSK_20240924', 'd3', 1, '2024-09-25T10:00:00Z', 'x', 'x')`,
+		`INSERT INTO decks (id, arena_deck_id, event_name, name, format, created_at, updated_at) VALUES
+			(1, 'deck-ub', 'PremierDraft_BLB_20240801', 'UB', 'Draft', '2024-08-02T11:00:00Z', 'x'),
+			(2, 'deck-wg', 'QuickDraft_BLB_20240815', 'WG', 'Draft', '2024-08-16T11:00:00Z', 'x')`,
+		// UB with two red cards and a UR dual, which is colorless: a red splash.
+		`INSERT INTO deck_cards (deck_id, section, card_id, quantity) VALUES
+			(1, 'main', 10, 8), (1, 'main', 11, 8), (1, 'main', 12, 2), (1, 'main', 13, 1), (1, 'main', 1, 16),
+			(2, 'main', 20, 9), (2, 'main', 21, 8), (2, 'main', 1, 17)`,
+		`INSERT INTO card_metadata (arena_id, color_identity, colors, set_code, updated_at) VALUES
+			(1, '', '', 'blb', 'x'), (10, 'U', 'U', 'blb', 'x'), (11, 'B', 'B', 'blb', 'x'), (12, 'R', 'R', 'blb', 'x'),
+			(13, 'UR', '', 'blb', 'x'), (20, 'W', 'W', 'blb', 'x'), (21, 'G', 'G', 'blb', 'x')`,
+		`INSERT INTO matches (id, arena_match_id, event_name, started_at, result, created_at, updated_at) VALUES
+			(1, 'm1', 'PremierDraft_BLB_20240801', '2024-08-02T12:00:00Z', 'win', 'x', 'x'),
+			(2, 'm2', 'PremierDraft_BLB_20240801', '2024-08-02T13:00:00Z', 'loss', 'x', 'x'),
+			(3, 'm3', 'QuickDraft_BLB_20240815', '2024-08-16T12:00:00Z', 'win', 'x', 'x')`,
+		`INSERT INTO match_decks (match_id, deck_id, snapshot_reason, created_at) VALUES (1, 1, 'x', 'x'), (2, 1, 'x', 'x'), (3, 2, 'x', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	handler := NewServer(db.NewStore(database), "", nil).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/limited/colors", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("without set status = %d, want 400", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/limited/colors?set=blb", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var stats model.LimitedColorStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if stats.SetCode != "BLB" || stats.Runs != 2 || stats.UnknownRuns != 0 {
+		t.Fatalf("stats = %+v, want two known BLB runs", stats)
+	}
+	if len(stats.ColorPairs) != 2 || stats.ColorPairs[0].Key != "UB" || stats.ColorPairs[1].Key != "WG" {
+		t.Fatalf("color pairs = %+v, want UB then WG", stats.ColorPairs)
+	}
+	if len(stats.Splashes) != 1 || stats.Splashes[0].Key != "UB+R" || !slices.Equal(stats.Splashes[0].Splash, []string{"R"}) || stats.Splashes[0].WinRate != 0.5 {
+		t.Fatalf("splashes = %+v, want UB splashing red at 1-1", stats.Splashes)
+	}
+	if stats.Splashed.Runs != 1 || stats.Unsplashed.Runs != 1 || stats.Unsplashed.Wins != 1 {
+		t.Fatalf("splashed = %+v, unsplashed = %+v", stats.Splashed, stats.Unsplashed)
+	}
+}
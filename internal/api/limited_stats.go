@@ -0,0 +1,213 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// handleLimitedStats serves per-set draft summaries: trophies, average wins,
+// records by the final build's colors, and what was taken first.
+func (s *Server) handleLimitedStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx := r.Context()
+	runs, err := s.store.ListLimitedDraftRuns(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	matchIDs := make([]int64, 0, len(runs))
+	firstPickIDs := make([]int64, 0, len(runs))
+	for _, run := range runs {
+		if run.LastMatchID > 0 {
+			matchIDs = append(matchIDs, run.LastMatchID)
+		}
+		if run.FirstPickCardID > 0 {
+			firstPickIDs = append(firstPickIDs, run.FirstPickCardID)
+		}
+	}
+	deckQuantitiesByMatch, err := s.store.ListMatchDeckCardQuantities(ctx, matchIDs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	cardIDs := append([]int64{}, firstPickIDs...)
+	for _, quantities := range deckQuantitiesByMatch {
+		for cardID := range quantities {
+			cardIDs = append(cardIDs, cardID)
+		}
+	}
+	colorIdentityByCardID := s.resolveCardColorIdentities(ctx, cardIDs)
+	names := s.resolveCardNames(ctx, firstPickIDs)
+
+	writeJSON(w, http.StatusOK, buildLimitedStats(runs, deckQuantitiesByMatch, colorIdentityByCardID, names))
+}
+
+type limitedSetState struct {
+	stats      model.LimitedSetStats
+	colorPairs map[string]*model.LimitedColorPairStats
+	firstPicks map[int64]*model.LimitedFirstPick
+	pickColors map[string]int64
+}
+
+// buildLimitedStats groups draft runs by set, most recently drafted set
+// first. A run's colors come from the deck it last played a match with.
+func buildLimitedStats(
+	runs []db.LimitedDraftRun,
+	deckQuantitiesByMatch map[int64]map[int64]int64,
+	colorIdentityByCardID map[int64][]string,
+	names map[int64]string,
+) model.LimitedStatsResponse {
+	states := make(map[string]*limitedSetState)
+	var order []string
+	for _, run := range runs {
+		setCode := limitedSetCode(run.EventName)
+		state, ok := states[setCode]
+		if !ok {
+			state = &limitedSetState{
+				stats:      model.LimitedSetStats{SetCode: setCode},
+				colorPairs: make(map[string]*model.LimitedColorPairStats),
+				firstPicks: make(map[int64]*model.LimitedFirstPick),
+				pickColors: make(map[string]int64),
+			}
+			states[setCode] = state
+			order = append(order, setCode)
+		}
+		set := &state.stats
+		set.Drafts++
+		if run.StartedAt > set.LastDraftedAt {
+			set.LastDraftedAt = run.StartedAt
+		}
+
+		if run.FirstPickCardID > 0 {
+			pick, ok := state.firstPicks[run.FirstPickCardID]
+			if !ok {
+				colors := normalizeDeckColors(colorIdentityByCardID[run.FirstPickCardID])
+				pick = &model.LimitedFirstPick{
+					CardID:   run.FirstPickCardID,
+					CardName: names[run.FirstPickCardID],
+					Colors:   colors,
+				}
+				state.firstPicks[run.FirstPickCardID] = pick
+			}
+			pick.Count++
+			pick.Wins += run.Wins
+			pick.Losses += run.Losses
+			if _, known := colorIdentityByCardID[run.FirstPickCardID]; known {
+				state.pickColors[firstPickColorBucket(pick.Colors)]++
+			}
+		}
+
+		if run.Wins+run.Losses == 0 {
+			continue
+		}
+		trophy := run.Wins >= db.LimitedTrophyWins(run.EventName)
+		set.Runs++
+		set.Wins += run.Wins
+		set.Losses += run.Losses
+		if trophy {
+			set.Trophies++
+		}
+
+		colors, known := matchColorsForCardQuantities(deckQuantitiesByMatch[run.LastMatchID], colorIdentityByCardID)
+		colorsKey := ""
+		if known {
+			colorsKey = strings.Join(colors, "")
+		}
+		pair, ok := state.colorPairs[colorsKey]
+		if !ok {
+			pair = &model.LimitedColorPairStats{
+				ColorsKey:   colorsKey,
+				Colors:      append([]string{}, colors...),
+				ColorsKnown: known,
+			}
+			state.colorPairs[colorsKey] = pair
+		}
+		pair.Runs++
+		pair.Wins += run.Wins
+		pair.Losses += run.Losses
+		if trophy {
+			pair.Trophies++
+		}
+	}
+
+	out := model.LimitedStatsResponse{Sets: make([]model.LimitedSetStats, 0, len(order))}
+	for _, setCode := range order {
+		out.Sets = append(out.Sets, states[setCode].finalize())
+	}
+	sort.SliceStable(out.Sets, func(i, j int) bool {
+		return out.Sets[i].LastDraftedAt > out.Sets[j].LastDraftedAt
+	})
+	return out
+}
+
+func (state *limitedSetState) finalize() model.LimitedSetStats {
+	set := state.stats
+	set.AverageWins, set.WinRate = limitedRunRates(set.Runs, set.Wins, set.Losses)
+
+	set.ColorPairs = make([]model.LimitedColorPairStats, 0, len(state.colorPairs))
+	for _, pair := range state.colorPairs {
+		pair.AverageWins, pair.WinRate = limitedRunRates(pair.Runs, pair.Wins, pair.Losses)
+		set.ColorPairs = append(set.ColorPairs, *pair)
+	}
+	sort.Slice(set.ColorPairs, func(i, j int) bool {
+		left, right := set.ColorPairs[i], set.ColorPairs[j]
+		if left.ColorsKnown != right.ColorsKnown {
+			return left.ColorsKnown
+		}
+		if left.Runs != right.Runs {
+			return left.Runs > right.Runs
+		}
+		return left.ColorsKey < right.ColorsKey
+	})
+
+	set.FirstPicks = make([]model.LimitedFirstPick, 0, len(state.firstPicks))
+	for _, pick := range state.firstPicks {
+		set.FirstPicks = append(set.FirstPicks, *pick)
+	}
+	sort.Slice(set.FirstPicks, func(i, j int) bool {
+		if set.FirstPicks[i].Count != set.FirstPicks[j].Count {
+			return set.FirstPicks[i].Count > set.FirstPicks[j].Count
+		}
+		return set.FirstPicks[i].CardID < set.FirstPicks[j].CardID
+	})
+
+	set.FirstPickColors = make([]model.LimitedFirstPickColor, 0, len(state.pickColors))
+	for _, color := range append(append([]string{}, deckColorOrder...), "multicolor", "colorless") {
+		if count := state.pickColors[color]; count > 0 {
+			set.FirstPickColors = append(set.FirstPickColors, model.LimitedFirstPickColor{Color: color, Count: count})
+		}
+	}
+	return set
+}
+
+func limitedRunRates(runs, wins, losses int64) (float64, float64) {
+	averageWins, winRate := 0.0, 0.0
+	if runs > 0 {
+		averageWins = float64(wins) / float64(runs)
+	}
+	if decided := wins + losses; decided > 0 {
+		winRate = float64(wins) / float64(decided)
+	}
+	return averageWins, winRate
+}
+
+func firstPickColorBucket(colors []string) string {
+	switch len(colors) {
+	case 0:
+		return "colorless"
+	case 1:
+		return colors[0]
+	default:
+		return "multicolor"
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestBuildLimitedStatsGroupsRunsBySet(t *testing.T) {
+	t.Parallel()
+
+	runs := []db.LimitedDraftRun{
+		{SessionID: 1, EventName: "PremierDraft_BLB_20240801", StartedAt: "2024-08-02T10:00:00Z", FirstPickCardID: 10, LastMatchID: 101, Wins: 7, Losses: 1},
+		{SessionID: 2, EventName: "PremierDraft_BLB_20240801", StartedAt: "2024-08-03T10:00:00Z", FirstPickCardID: 10, LastMatchID: 102, Wins: 2, Losses: 3},
+		// Drafted but never played: counts as a draft, not a run.
+		{SessionID: 3, EventName: "QuickDraft_BLB_20240815", StartedAt: "2024-08-16T10:00:00Z", FirstPickCardID: 11},
+		{SessionID: 4, EventName: "TradDraft_DSK_20240924", StartedAt: "2024-09-25T10:00:00Z", FirstPickCardID: 12, LastMatchID: 103, Wins: 3, Losses: 0},
+	}
+	deckQuantities := map[int64]map[int64]int64{
+		101: {10: 8, 20: 8},
+		102: {10: 8, 20: 8},
+		103: {12: 9, 30: 8},
+	}
+	colorIdentity := map[int64][]string{
+		10: {"W"},
+		11: {"U", "R"},
+		12: {"B"},
+		20: {"G"},
+		30: {"B"},
+	}
+	names := map[int64]string{10: "Salvation Swan", 11: "Dragonhawk"}
+
+	out := buildLimitedStats(runs, deckQuantities, colorIdentity, names)
+	if len(out.Sets) != 2 || out.Sets[0].SetCode != "DSK" || out.Sets[1].SetCode != "BLB" {
+		t.Fatalf("sets = %+v, want DSK then BLB", out.Sets)
+	}
+
+	dsk := out.Sets[0]
+	if dsk.Trophies != 1 || dsk.Runs != 1 {
+		t.Fatalf("traditional 3-0 = %+v, want a trophy", dsk)
+	}
+
+	blb := out.Sets[1]
+	if blb.Drafts != 3 || blb.Runs != 2 || blb.Trophies != 1 || blb.AverageWins != 4.5 || blb.WinRate != 9.0/13.0 {
+		t.Fatalf("blb = %+v", blb)
+	}
+	if len(blb.ColorPairs) != 1 || blb.ColorPairs[0].ColorsKey != "WG" || blb.ColorPairs[0].Runs != 2 || blb.ColorPairs[0].Trophies != 1 {
+		t.Fatalf("blb color pairs = %+v", blb.ColorPairs)
+	}
+	if len(blb.FirstPicks) != 2 || blb.FirstPicks[0].CardName != "Salvation Swan" || blb.FirstPicks[0].Count != 2 || blb.FirstPicks[0].Wins != 9 {
+		t.Fatalf("blb first picks = %+v", blb.FirstPicks)
+	}
+	if len(blb.FirstPickColors) != 2 || blb.FirstPickColors[0].Color != "W" || blb.FirstPickColors[0].Count != 2 || blb.FirstPickColors[1].Color != "multicolor" {
+		t.Fatalf("blb first-pick colors = %+v", blb.FirstPickColors)
+	}
+}
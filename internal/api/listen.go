@@ -0,0 +1,61 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// unixAddrPrefix marks a listen address as a Unix domain socket, as in
+// "unix:/run/ponder/ponder.sock".
+const unixAddrPrefix = "unix:"
+
+// UnixSocketPath returns the socket path of a "unix:" listen address.
+func UnixSocketPath(addr string) (string, bool) {
+	if !strings.HasPrefix(addr, unixAddrPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(addr, unixAddrPrefix), true
+}
+
+// unixSocketMode lets the owner and a reverse proxy in the owner's group
+// connect, and nobody else on a shared machine.
+const unixSocketMode = 0o660
+
+// listen opens a TCP address, or a Unix domain socket for "unix:<path>".
+// A socket file left behind by an earlier run is replaced; a live one, or
+// any other kind of file at the path, is an error.
+func listen(addr string) (net.Listener, error) {
+	path, ok := UnixSocketPath(addr)
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("listen %s: missing socket path", addr)
+	}
+
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("listen %s: %s exists and is not a socket", addr, path)
+		}
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("listen %s: another server is already listening", addr)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, unixSocketMode); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("set socket permissions: %w", err)
+	}
+	return listener, nil
+}
@@ -5,7 +5,7 @@ import (
 	"errors"
 	"net/http"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 // openingHandSize is subtracted from the deck total when estimating how many
@@ -0,0 +1,19 @@
+package api
+
+import "net/http"
+
+// handleManaStats serves GET /api/stats/mana: how often each deck stumbles on
+// mana, from lands kept in the opening hand, missed land drops, and spells
+// still stuck in hand by the player's sixth turn.
+func (s *Server) handleManaStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	out, err := s.store.ManaStats(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
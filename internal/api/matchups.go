@@ -9,12 +9,12 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"regexp"
 	"sort"
 	"strings"
 
-	"github.com/solean/ponder/internal/db"
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
 )
 
 const (
@@ -42,26 +42,10 @@ func eventLooksLimited(format, eventName string) bool {
 		strings.Contains(combined, "limited")
 }
 
-var (
-	// Arena event names embed dates as bare YYYYMMDD tokens.
-	eventDateToken = regexp.MustCompile(`^\d{8}$`)
-	// Set codes are short all-caps alphanumerics (TMT, FIN, Y25); mixed-case
-	// type words like "QuickDraft" never match. Mirrors web/src/lib/events.ts.
-	eventSetCodeToken = regexp.MustCompile(`^[A-Z0-9]{2,5}$`)
-)
-
 // limitedSetCode extracts the set code from an Arena event name like
 // "QuickDraft_TMT_20260313" or "FIN_Quick_Draft"; empty when there is none.
 func limitedSetCode(eventName string) string {
-	for _, token := range strings.Split(eventName, "_") {
-		if eventDateToken.MatchString(token) {
-			continue
-		}
-		if eventSetCodeToken.MatchString(token) {
-			return token
-		}
-	}
-	return ""
+	return eventnames.SetCode(eventName)
 }
 
 // opponentCardFacts is everything classification knows about one card.
@@ -170,9 +154,12 @@ func classifyOpponent(quantities map[int64]int64, facts map[int64]opponentCardFa
 	return out
 }
 
-// resolveCardMetadata returns color identity and mana value for the given
-// cards, reading the local cache first, then the MTGA raw card database, then
-// Scryfall, caching anything newly resolved.
+// resolveCardMetadata returns color identity, mana value, and set for the
+// given cards, reading the local cache first, then the MTGA raw card
+// database, then Scryfall, caching anything newly resolved. A cached row is
+// final even without a set: a card no source has a set for is not looked up
+// again on every request. Rows cached before sets were tracked were dropped
+// by the card_metadata_details migration.
 func (s *Server) resolveCardMetadata(ctx context.Context, cardIDs []int64) map[int64]db.CardMetadata {
 	cardIDs = uniqueCardIDs(cardIDs)
 	if len(cardIDs) == 0 {
@@ -185,9 +172,13 @@ func (s *Server) resolveCardMetadata(ctx context.Context, cardIDs []int64) map[i
 		resolved = map[int64]db.CardMetadata{}
 	}
 
+	needsLookup := func(cardID int64) bool {
+		_, ok := resolved[cardID]
+		return !ok
+	}
 	unresolved := make([]int64, 0, len(cardIDs))
 	for _, cardID := range cardIDs {
-		if _, ok := resolved[cardID]; !ok {
+		if needsLookup(cardID) {
 			unresolved = append(unresolved, cardID)
 		}
 	}
@@ -207,7 +198,7 @@ func (s *Server) resolveCardMetadata(ctx context.Context, cardIDs []int64) map[i
 
 	unresolved = unresolved[:0]
 	for _, cardID := range cardIDs {
-		if _, ok := resolved[cardID]; !ok {
+		if needsLookup(cardID) {
 			unresolved = append(unresolved, cardID)
 		}
 	}
@@ -230,27 +221,29 @@ func (s *Server) resolveCardMetadata(ctx context.Context, cardIDs []int64) map[i
 	return resolved
 }
 
+// mtgaRawRarities maps the raw card database's Rarity enum to Scryfall's
+// rarity names; basic lands (1) are commons there. Unknown values map to "".
+var mtgaRawRarities = map[int64]string{
+	1: "common",
+	2: "common",
+	3: "uncommon",
+	4: "rare",
+	5: "mythic",
+}
+
 func (s *Server) fetchCardMetadataFromMTGARaw(ctx context.Context, cardIDs []int64) (map[int64]db.CardMetadata, error) {
 	out := make(map[int64]db.CardMetadata, len(cardIDs))
 	if len(cardIDs) == 0 {
 		return out, nil
 	}
 
-	rawDBPath := discoverMTGARawCardDBPath()
-	if strings.TrimSpace(rawDBPath) == "" {
-		return out, nil
-	}
-
-	rawDB, err := sql.Open("sqlite", rawDBPath)
+	rawDB, release, err := s.mtgaRaw.acquire(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("open MTGA raw card db %q: %w", rawDBPath, err)
+		return nil, err
 	}
-	defer rawDB.Close()
-	rawDB.SetMaxOpenConns(1)
-	rawDB.SetMaxIdleConns(1)
-
-	if err := rawDB.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("ping MTGA raw card db %q: %w", rawDBPath, err)
+	defer release()
+	if rawDB == nil {
+		return out, nil
 	}
 
 	for start := 0; start < len(cardIDs); start += rawCardLookupBatchMax {
@@ -267,7 +260,8 @@ func (s *Server) fetchCardMetadataFromMTGARaw(ctx context.Context, cardIDs []int
 		// Order_CMCWithXLast holds the card's mana value in current raw
 		// databases; it is a sort key, so guard against unexpected values.
 		rows, err := rawDB.QueryContext(ctx, fmt.Sprintf(`
-			SELECT GrpId, COALESCE(ColorIdentity, ''), Order_CMCWithXLast
+			SELECT GrpId, COALESCE(ColorIdentity, ''), COALESCE(Colors, ''), Order_CMCWithXLast,
+				COALESCE(ExpansionCode, ''), COALESCE(CollectorNumber, ''), COALESCE(Rarity, 0)
 			FROM Cards
 			WHERE GrpId IN (%s)
 		`, strings.Join(placeholders, ",")), args...)
@@ -276,14 +270,20 @@ func (s *Server) fetchCardMetadataFromMTGARaw(ctx context.Context, cardIDs []int
 		}
 		for rows.Next() {
 			var cardID int64
-			var rawColorIdentity string
+			var rawColorIdentity, rawColors string
 			var rawManaValue sql.NullFloat64
-			if err := rows.Scan(&cardID, &rawColorIdentity, &rawManaValue); err != nil {
+			var rawSetCode, rawCollectorNumber string
+			var rawRarity int64
+			if err := rows.Scan(&cardID, &rawColorIdentity, &rawColors, &rawManaValue, &rawSetCode, &rawCollectorNumber, &rawRarity); err != nil {
 				rows.Close()
 				return nil, fmt.Errorf("scan MTGA raw card metadata: %w", err)
 			}
 			meta := db.CardMetadata{
-				ColorIdentity: strings.Join(parseMTGARawColorIdentity(rawColorIdentity), ""),
+				ColorIdentity:   strings.Join(parseMTGARawColorIdentity(rawColorIdentity), ""),
+				Colors:          strings.Join(parseMTGARawColorIdentity(rawColors), ""),
+				SetCode:         strings.ToLower(strings.TrimSpace(rawSetCode)),
+				CollectorNumber: strings.TrimSpace(rawCollectorNumber),
+				Rarity:          mtgaRawRarities[rawRarity],
 			}
 			if rawManaValue.Valid && rawManaValue.Float64 >= 0 && rawManaValue.Float64 <= 20 {
 				value := rawManaValue.Float64
@@ -325,9 +325,13 @@ func (s *Server) fetchCardMetadataFromScryfall(ctx context.Context, cardIDs []in
 
 func (s *Server) fetchCardMetadataBatch(ctx context.Context, cardIDs []int64) (map[int64]db.CardMetadata, error) {
 	type responseCard struct {
-		ArenaID       int64    `json:"arena_id"`
-		ColorIdentity []string `json:"color_identity"`
-		ManaValue     *float64 `json:"cmc"`
+		ArenaID         int64    `json:"arena_id"`
+		ColorIdentity   []string `json:"color_identity"`
+		Colors          []string `json:"colors"`
+		ManaValue       *float64 `json:"cmc"`
+		Set             string   `json:"set"`
+		CollectorNumber string   `json:"collector_number"`
+		Rarity          string   `json:"rarity"`
 	}
 	type responsePayload struct {
 		Data     []responseCard `json:"data"`
@@ -352,8 +356,12 @@ func (s *Server) fetchCardMetadataBatch(ctx context.Context, cardIDs []int64) (m
 				continue
 			}
 			out[card.ArenaID] = db.CardMetadata{
-				ColorIdentity: strings.Join(normalizeDeckColors(card.ColorIdentity), ""),
-				ManaValue:     card.ManaValue,
+				ColorIdentity:   strings.Join(normalizeDeckColors(card.ColorIdentity), ""),
+				Colors:          strings.Join(normalizeDeckColors(card.Colors), ""),
+				ManaValue:       card.ManaValue,
+				SetCode:         strings.ToLower(card.Set),
+				CollectorNumber: strings.TrimSpace(card.CollectorNumber),
+				Rarity:          strings.ToLower(strings.TrimSpace(card.Rarity)),
 			}
 		}
 	}
@@ -367,7 +375,7 @@ func (s *Server) fetchCardMetadataBatch(ctx context.Context, cardIDs []int64) (m
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("User-Agent", "ponder/0.1 (local tracker)")
 
-		res, err := s.httpClient.Do(req)
+		res, err := s.doScryfall(req)
 		if err != nil {
 			return out, fmt.Errorf("request scryfall metadata: %w", err)
 		}
@@ -411,11 +419,13 @@ type matchupInputs struct {
 	names           map[int64]string
 	gameSummaries   map[int64]model.MatchGameSummary
 	overrides       map[int64]string
+	// deriveArchetypes is false when settings limit labels to manual ones.
+	deriveArchetypes bool
 }
 
 // loadMatchupInputs fetches deck-linked match rows (all decks when deckID <= 0)
-// plus the observed cards, card facts, game summaries, and manual overrides
-// that classification and aggregation need.
+// plus the observed cards, card facts, game summaries, manual overrides, and
+// archetype source setting that classification and aggregation need.
 func (s *Server) loadMatchupInputs(ctx context.Context, deckID int64) (*matchupInputs, error) {
 	matchRows, err := s.store.ListMatchupMatchRows(ctx, deckID)
 	if err != nil {
@@ -437,6 +447,10 @@ func (s *Server) loadMatchupInputs(ctx context.Context, deckID int64) (*matchupI
 	if err != nil {
 		return nil, err
 	}
+	settings, err := s.store.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	allCardIDs := make([]int64, 0)
 	for _, quantities := range observedByMatch {
@@ -462,12 +476,13 @@ func (s *Server) loadMatchupInputs(ctx context.Context, deckID int64) (*matchupI
 	}
 
 	return &matchupInputs{
-		matchRows:       matchRows,
-		observedByMatch: observedByMatch,
-		facts:           facts,
-		names:           names,
-		gameSummaries:   gameSummaries,
-		overrides:       overrides,
+		matchRows:        matchRows,
+		observedByMatch:  observedByMatch,
+		facts:            facts,
+		names:            names,
+		gameSummaries:    gameSummaries,
+		overrides:        overrides,
+		deriveArchetypes: settings.ArchetypeSignatureSource != db.ArchetypeSourceManual,
 	}, nil
 }
 
@@ -484,7 +499,7 @@ func (s *Server) handleDeckMatchups(w http.ResponseWriter, r *http.Request, deck
 		return
 	}
 	full := buildMatchupsResponse(inputs.matchRows, inputs.observedByMatch, inputs.facts,
-		inputs.names, inputs.gameSummaries, inputs.overrides)
+		inputs.names, inputs.gameSummaries, inputs.overrides, inputs.deriveArchetypes)
 	out := model.DeckMatchupsResponse{Archetypes: matchupArchetypes}
 	for index := range full.Decks {
 		if full.Decks[index].DeckID == deckID {
@@ -545,7 +560,7 @@ func (s *Server) handleLimitedMatchups(w http.ResponseWriter, r *http.Request) {
 	ownColorsByMatch := s.resolveOwnDeckColors(r.Context(), inputs.matchRows)
 	writeJSON(w, http.StatusOK, buildLimitedMatchupsResponse(inputs.matchRows,
 		inputs.observedByMatch, inputs.facts, inputs.names, inputs.gameSummaries,
-		inputs.overrides, ownColorsByMatch))
+		inputs.overrides, inputs.deriveArchetypes, ownColorsByMatch))
 }
 
 type matchupCellKey struct {
@@ -715,18 +730,23 @@ func (cell *matchupCellState) finalize() model.MatchupRow {
 }
 
 // classifyMatchupRow classifies one match's opponent and applies any manual
-// override.
+// override. With deriveArchetypes off only manual labels count; everything
+// else stays unknown.
 func classifyMatchupRow(
 	matchRow db.MatchupMatchRow,
 	observedByMatch map[int64]map[int64]int64,
 	facts map[int64]opponentCardFacts,
 	overrides map[int64]string,
+	deriveArchetypes bool,
 ) model.OpponentClassification {
 	classification := classifyOpponent(
 		observedByMatch[matchRow.MatchID],
 		facts,
 		eventLooksLimited(matchRow.Format, matchRow.EventName),
 	)
+	if !deriveArchetypes {
+		classification.Archetype = "unknown"
+	}
 	if override, ok := overrides[matchRow.MatchID]; ok && isAllowedArchetype(override) {
 		classification.Archetype = override
 		classification.Source = "manual"
@@ -750,6 +770,7 @@ func buildMatchupsResponse(
 	names map[int64]string,
 	gameSummaries map[int64]model.MatchGameSummary,
 	overrides map[int64]string,
+	deriveArchetypes bool,
 ) model.MatchupsResponse {
 	type deckState struct {
 		deck  model.MatchupDeck
@@ -761,7 +782,7 @@ func buildMatchupsResponse(
 	deckOrder := make([]int64, 0)
 
 	for _, matchRow := range matchRows {
-		classification := classifyMatchupRow(matchRow, observedByMatch, facts, overrides)
+		classification := classifyMatchupRow(matchRow, observedByMatch, facts, overrides, deriveArchetypes)
 
 		state, ok := decksByID[matchRow.DeckID]
 		if !ok {
@@ -821,6 +842,7 @@ func buildLimitedMatchupsResponse(
 	names map[int64]string,
 	gameSummaries map[int64]model.MatchGameSummary,
 	overrides map[int64]string,
+	deriveArchetypes bool,
 	ownColorsByMatch map[int64]ownDeckColors,
 ) model.LimitedMatchupsResponse {
 	type colorGroupState struct {
@@ -845,7 +867,7 @@ func buildLimitedMatchupsResponse(
 			continue
 		}
 		setCode := limitedSetCode(matchRow.EventName)
-		classification := classifyMatchupRow(matchRow, observedByMatch, facts, overrides)
+		classification := classifyMatchupRow(matchRow, observedByMatch, facts, overrides, deriveArchetypes)
 
 		state, ok := setsByCode[setCode]
 		if !ok {
@@ -4,8 +4,8 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/solean/ponder/internal/db"
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
 )
 
 func floatPointer(value float64) *float64 {
@@ -125,7 +125,7 @@ func TestBuildLimitedMatchupsResponsePoolsBySetAndColorPair(t *testing.T) {
 	}
 
 	out := buildLimitedMatchupsResponse(matchRows, observedByMatch, facts,
-		map[int64]string{}, summaries, map[int64]string{}, ownColors)
+		map[int64]string{}, summaries, map[int64]string{}, true, ownColors)
 
 	if len(out.Sets) != 2 {
 		t.Fatalf("sets = %+v, want TMT and FIN", out.Sets)
@@ -209,7 +209,7 @@ func TestBuildMatchupsResponseGroupsAndOverrides(t *testing.T) {
 	overrides := map[int64]string{3: "combo"}
 
 	out := buildMatchupsResponse(matchRows, observedByMatch, facts,
-		map[int64]string{1: "Goblin"}, summaries, overrides)
+		map[int64]string{1: "Goblin"}, summaries, overrides, true)
 
 	if len(out.Decks) != 1 || out.Decks[0].DeckID != 7 {
 		t.Fatalf("decks = %+v, want a single deck 7", out.Decks)
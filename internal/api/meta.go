@@ -0,0 +1,150 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+const (
+	metaDefaultWeeks = 8
+	metaMaxWeeks     = 52
+)
+
+// handleMeta serves GET /api/stats/meta: the opponent decks faced per
+// format and week over the last ?weeks= weeks (default 8), each with its
+// share of the week and how that moved from the week before. Opponents are
+// labeled as in the matchup views, so only deck-linked matches count.
+func (s *Server) handleMeta(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	weeks, err := boundedQueryInt(r, "weeks", metaDefaultWeeks, metaMaxWeeks)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	inputs, err := s.loadMatchupInputs(r.Context(), 0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	classify := func(matchRow db.MatchupMatchRow) model.OpponentClassification {
+		return classifyMatchupRow(matchRow, inputs.observedByMatch, inputs.facts, inputs.overrides, inputs.deriveArchetypes)
+	}
+	writeJSON(w, http.StatusOK, buildMetaReport(inputs.matchRows, classify, s.store.Clock().Now(), weeks))
+}
+
+// metaWeekStart is the Monday, in UTC, of the week containing t.
+func metaWeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// buildMetaReport groups matches by format, week, and opponent deck. The
+// week before the oldest one shown is tallied too, only for its deltas.
+func buildMetaReport(
+	matchRows []db.MatchupMatchRow,
+	classify func(db.MatchupMatchRow) model.OpponentClassification,
+	now time.Time,
+	weeks int,
+) model.MetaReport {
+	type weekState struct {
+		matches int64
+		decks   map[string]*model.MetaDeck
+	}
+	current := metaWeekStart(now)
+	oldest := current.AddDate(0, 0, -7*weeks)
+	byFormat := make(map[string]map[time.Time]*weekState)
+	for _, matchRow := range matchRows {
+		startedAt, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(matchRow.StartedAt))
+		if err != nil {
+			continue
+		}
+		week := metaWeekStart(startedAt)
+		if week.Before(oldest) || week.After(current) {
+			continue
+		}
+		format := strings.TrimSpace(matchRow.Format)
+		if format == "" {
+			format = "Unknown"
+		}
+		if byFormat[format] == nil {
+			byFormat[format] = make(map[time.Time]*weekState)
+		}
+		state := byFormat[format][week]
+		if state == nil {
+			state = &weekState{decks: make(map[string]*model.MetaDeck)}
+			byFormat[format][week] = state
+		}
+		classification := classify(matchRow)
+		colorsKey := strings.Join(classification.Colors, "")
+		key := colorsKey + "|" + classification.Archetype
+		deck := state.decks[key]
+		if deck == nil {
+			deck = &model.MetaDeck{
+				ColorsKey: colorsKey,
+				Colors:    append([]string{}, classification.Colors...),
+				Archetype: classification.Archetype,
+			}
+			state.decks[key] = deck
+		}
+		state.matches++
+		deck.Matches++
+		addMatchResult(&deck.Record, matchRow.Result)
+	}
+
+	out := model.MetaReport{Weeks: int64(weeks), Formats: []model.MetaFormat{}}
+	for format, byWeek := range byFormat {
+		entry := model.MetaFormat{Format: format, Weeks: []model.MetaWeek{}}
+		for offset := 0; offset < weeks; offset++ {
+			week := current.AddDate(0, 0, -7*offset)
+			state := byWeek[week]
+			if state == nil {
+				continue
+			}
+			previous := byWeek[week.AddDate(0, 0, -7)]
+			summary := model.MetaWeek{WeekStart: week.Format(time.DateOnly), Matches: state.matches, Decks: make([]model.MetaDeck, 0, len(state.decks))}
+			for key, deck := range state.decks {
+				deck.Share = float64(deck.Matches) / float64(state.matches)
+				if previous != nil {
+					var before float64
+					if prior := previous.decks[key]; prior != nil {
+						before = float64(prior.Matches) / float64(previous.matches)
+					}
+					delta := deck.Share - before
+					deck.PreviousShare, deck.ShareDelta = &before, &delta
+				}
+				summary.Decks = append(summary.Decks, *deck)
+			}
+			sort.Slice(summary.Decks, func(i, j int) bool {
+				a, b := summary.Decks[i], summary.Decks[j]
+				if a.Matches != b.Matches {
+					return a.Matches > b.Matches
+				}
+				if a.ColorsKey != b.ColorsKey {
+					return a.ColorsKey < b.ColorsKey
+				}
+				return a.Archetype < b.Archetype
+			})
+			entry.Matches += state.matches
+			entry.Weeks = append(entry.Weeks, summary)
+		}
+		if entry.Matches > 0 {
+			out.Formats = append(out.Formats, entry)
+		}
+	}
+	sort.Slice(out.Formats, func(i, j int) bool {
+		if out.Formats[i].Matches != out.Formats[j].Matches {
+			return out.Formats[i].Matches > out.Formats[j].Matches
+		}
+		return out.Formats[i].Format < out.Formats[j].Format
+	})
+	return out
+}
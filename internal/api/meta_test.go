@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestMetaReportSharesAndWeeklyDeltas(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// Last week (from Monday 2026-02-23): one aggro, one control. This week
+	// (from Monday 2026-03-02): three aggro, one control. The 2025 match is
+	// outside the window.
+	for _, query := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, format, created_at, updated_at) VALUES (1, 'd1', 'Mono Red', 'Standard', 'x', 'x')`,
+		`INSERT INTO matches (id, arena_match_id, event_name, format, started_at, result, created_at, updated_at) VALUES
+			(1, 'm1', 'Ladder', 'Standard', '2026-02-24T19:00:00Z', 'win', 'x', 'x'),
+			(2, 'm2', 'Ladder', 'Standard', '2026-03-01T19:00:00Z', 'loss', 'x', 'x'),
+			(3, 'm3', 'Ladder', 'Standard', '2026-03-02T19:00:00Z', 'win', 'x', 'x'),
+			(4, 'm4', 'Ladder', 'Standard', '2026-03-03T19:00:00Z', 'loss', 'x', 'x'),
+			(5, 'm5', 'Ladder', 'Standard', '2026-03-04T19:00:00Z', 'win', 'x', 'x'),
+			(6, 'm6', 'Ladder', 'Standard', '2026-03-05T19:00:00Z', 'win', 'x', 'x'),
+			(7, 'm7', 'Ladder', 'Standard', '2025-01-05T19:00:00Z', 'win', 'x', 'x')`,
+		`INSERT INTO match_decks (match_id, deck_id, snapshot_reason, created_at) VALUES
+			(1, 1, 'x', 'x'), (2, 1, 'x', 'x'), (3, 1, 'x', 'x'), (4, 1, 'x', 'x'), (5, 1, 'x', 'x'), (6, 1, 'x', 'x'), (7, 1, 'x', 'x')`,
+		`INSERT INTO match_opponent_archetype_overrides (match_id, archetype, created_at, updated_at) VALUES
+			(1, 'aggro', 'x', 'x'), (2, 'control', 'x', 'x'), (3, 'aggro', 'x', 'x'),
+			(4, 'aggro', 'x', 'x'), (5, 'aggro', 'x', 'x'), (6, 'control', 'x', 'x'), (7, 'combo', 'x', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	store := db.NewStore(database)
+	store.SetClock(db.FixedClock(time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC)))
+
+	rec := httptest.NewRecorder()
+	NewServer(store, "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/meta?weeks=4", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var report model.MetaReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(report.Formats) != 1 || report.Formats[0].Format != "Standard" || report.Formats[0].Matches != 6 {
+		t.Fatalf("formats = %+v, want six Standard matches", report.Formats)
+	}
+	weeks := report.Formats[0].Weeks
+	if len(weeks) != 2 || weeks[0].WeekStart != "2026-03-02" || weeks[1].WeekStart != "2026-02-23" {
+		t.Fatalf("weeks = %+v, want this week then last week", weeks)
+	}
+	aggro := weeks[0].Decks[0]
+	if aggro.Archetype != "aggro" || aggro.Matches != 3 || aggro.Share != 0.75 || aggro.Record.Wins != 2 {
+		t.Fatalf("this week's top deck = %+v, want aggro at 75%%", aggro)
+	}
+	if aggro.PreviousShare == nil || *aggro.PreviousShare != 0.5 || *aggro.ShareDelta != 0.25 {
+		t.Fatalf("aggro change = %v/%v, want 0.5 before and +0.25", aggro.PreviousShare, aggro.ShareDelta)
+	}
+	if weeks[1].Decks[0].ShareDelta != nil {
+		t.Fatalf("last week's delta = %v, want none with no week before it", *weeks[1].Decks[0].ShareDelta)
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/solean/ponder/internal/metrics"
+)
+
+// statusRecorder captures the response code for metrics while keeping
+// streaming handlers (SSE primers, exports) able to flush.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// withMetrics records request latency labelled by the mux pattern that
+// serves the path (e.g. "/api/matches/"), which keeps label cardinality fixed
+// no matter how many match or deck ids are requested.
+func withMetrics(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		recorder := &statusRecorder{ResponseWriter: w}
+		started := time.Now()
+		next.ServeHTTP(recorder, r)
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		metrics.HTTPRequestDuration.Observe(time.Since(started).Seconds(), route, r.Method, strconv.Itoa(status))
+	})
+}
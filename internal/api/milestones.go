@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+)
+
+// handleMilestones serves GET /api/stats/milestones: the current and best
+// win streaks and every milestone reached, newest first. Opponent
+// archetypes are classified as for the matchup views, so first wins against
+// an archetype follow the same labels and overrides.
+func (s *Server) handleMilestones(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	archetypes, err := s.matchArchetypes(r.Context())
+	if err != nil {
+		// Streaks and trophies do not need the labels; fall back to the
+		// manual overrides the store reads on its own.
+		log.Printf("milestone archetypes: %v", err)
+		archetypes = nil
+	}
+	stats, err := s.store.ListMilestones(r.Context(), archetypes)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// matchArchetypes labels the opponent of every deck-linked match, plus any
+// other match with a manual override, leaving out those still unknown.
+func (s *Server) matchArchetypes(ctx context.Context) (map[int64]string, error) {
+	inputs, err := s.loadMatchupInputs(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int64]string, len(inputs.matchRows))
+	for matchID, override := range inputs.overrides {
+		if isAllowedArchetype(override) {
+			out[matchID] = override
+		}
+	}
+	for _, matchRow := range inputs.matchRows {
+		classification := classifyMatchupRow(matchRow, inputs.observedByMatch, inputs.facts, inputs.overrides, inputs.deriveArchetypes)
+		if classification.Archetype != "unknown" {
+			out[matchRow.MatchID] = classification.Archetype
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestMilestonesTrackStreaksAndFirstArchetypeWins(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	for _, query := range []string{
+		`INSERT INTO matches (id, arena_match_id, event_name, opponent_name, started_at, ended_at, result, created_at, updated_at) VALUES
+			(1, 'm1', 'Ladder', 'Alpha', '2026-03-05T19:00:00Z', '2026-03-05T19:10:00Z', 'win', 'x', 'x'),
+			(2, 'm2', 'Ladder', 'Bravo', '2026-03-05T19:20:00Z', '2026-03-05T19:30:00Z', 'win', 'x', 'x'),
+			(3, 'm3', 'Ladder', 'Charlie', '2026-03-05T19:40:00Z', '2026-03-05T19:50:00Z', 'win', 'x', 'x'),
+			(4, 'm4', 'Ladder', 'Delta', '2026-03-05T20:00:00Z', '2026-03-05T20:10:00Z', 'loss', 'x', 'x'),
+			(5, 'm5', 'Ladder', 'Echo', '2026-03-05T20:20:00Z', '2026-03-05T20:30:00Z', 'win', 'x', 'x')`,
+		`INSERT INTO match_opponent_archetype_overrides (match_id, archetype, created_at, updated_at) VALUES
+			(2, 'aggro', 'x', 'x'), (5, 'aggro', 'x', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(db.NewStore(database), "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/milestones", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var stats model.MilestoneStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if stats.CurrentWinStreak != 1 || stats.BestWinStreak != 3 {
+		t.Fatalf("streaks = %d current, %d best; want 1 and 3", stats.CurrentWinStreak, stats.BestWinStreak)
+	}
+	if len(stats.Milestones) != 2 {
+		t.Fatalf("milestones = %+v, want the streak and the first aggro win", stats.Milestones)
+	}
+	streak, firstWin := stats.Milestones[0], stats.Milestones[1]
+	if streak.Key != "win_streak:3" || streak.MatchID != 3 || streak.Detail != "vs Charlie · Ranked" {
+		t.Fatalf("streak milestone = %+v", streak)
+	}
+	if firstWin.Key != "archetype_first_win:aggro" || firstWin.MatchID != 2 || firstWin.AchievedAt != "2026-03-05T19:30:00Z" {
+		t.Fatalf("archetype milestone = %+v", firstWin)
+	}
+}
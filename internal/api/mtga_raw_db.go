@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/solean/ponder/internal/appstate"
+)
+
+// mtgaRawDB keeps one read-only handle on Arena's raw card database for the
+// life of the server, instead of opening and pinging the file on every name,
+// color or metadata lookup. Arena rewrites the file when it patches, so each
+// acquire compares the file's path, size and mtime with what the handle was
+// opened on and reopens when they differ.
+type mtgaRawDB struct {
+	mu      sync.Mutex
+	current *mtgaRawHandle
+}
+
+type mtgaRawHandle struct {
+	db      *sql.DB
+	path    string
+	size    int64
+	modTime time.Time
+	// users counts acquires not yet released; a replaced handle is closed
+	// when its last user releases it.
+	users    int
+	replaced bool
+}
+
+// acquire returns a handle on the current raw card database, or a nil
+// handle when Arena's database cannot be found. release must be called once
+// the caller is done with the handle.
+func (r *mtgaRawDB) acquire(ctx context.Context) (rawDB *sql.DB, release func(), err error) {
+	rawDBPath := appstate.MTGARawCardDBPath()
+	if strings.TrimSpace(rawDBPath) == "" {
+		return nil, func() {}, nil
+	}
+	fi, err := os.Stat(rawDBPath)
+	if err != nil {
+		return nil, func() {}, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h := r.current
+	if h == nil || h.path != rawDBPath || h.size != fi.Size() || !h.modTime.Equal(fi.ModTime()) {
+		fresh, err := openMTGARawDB(ctx, rawDBPath)
+		if err != nil {
+			return nil, func() {}, err
+		}
+		if h != nil {
+			r.retire(h)
+		}
+		h = &mtgaRawHandle{db: fresh, path: rawDBPath, size: fi.Size(), modTime: fi.ModTime()}
+		r.current = h
+	}
+	h.users++
+	var once sync.Once
+	return h.db, func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			h.users--
+			if h.replaced && h.users == 0 {
+				_ = h.db.Close()
+			}
+		})
+	}, nil
+}
+
+// retire marks h replaced, closing it now if nothing is using it. r.mu must
+// be held.
+func (r *mtgaRawDB) retire(h *mtgaRawHandle) {
+	h.replaced = true
+	if h.users == 0 {
+		_ = h.db.Close()
+	}
+}
+
+// close drops the cached handle when the server shuts down. A handle still
+// in use is closed by its last release; a later acquire opens a fresh one.
+func (r *mtgaRawDB) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current != nil {
+		r.retire(r.current)
+		r.current = nil
+	}
+}
+
+// openMTGARawDB opens Arena's card database read-only; it is Arena's file,
+// and ponder only ever queries it.
+func openMTGARawDB(ctx context.Context, path string) (*sql.DB, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed
+	}
+	dsn := url.URL{Scheme: "file", Path: slashed, RawQuery: "mode=ro&_pragma=query_only(1)"}
+	rawDB, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("open MTGA raw card db %q: %w", path, err)
+	}
+	rawDB.SetMaxOpenConns(2)
+	rawDB.SetMaxIdleConns(2)
+
+	if err := rawDB.PingContext(ctx); err != nil {
+		_ = rawDB.Close()
+		return nil, fmt.Errorf("ping MTGA raw card db %q: %w", path, err)
+	}
+	return rawDB, nil
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeMTGARawCardDB(t *testing.T, path, name string) {
+	t.Helper()
+	rawDB, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open raw card db: %v", err)
+	}
+	defer rawDB.Close()
+	for _, stmt := range []string{
+		`CREATE TABLE IF NOT EXISTS Cards (GrpId INTEGER, TitleId INTEGER, AltTitleId INTEGER, InterchangeableTitleId INTEGER)`,
+		`CREATE TABLE IF NOT EXISTS Localizations_enUS (LocId INTEGER, Loc TEXT)`,
+		`DELETE FROM Cards`,
+		`DELETE FROM Localizations_enUS`,
+		`INSERT INTO Cards VALUES (7001, 1, 0, 0)`,
+	} {
+		if _, err := rawDB.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	if _, err := rawDB.Exec(`INSERT INTO Localizations_enUS VALUES (1, ?)`, name); err != nil {
+		t.Fatalf("insert localization: %v", err)
+	}
+}
+
+func TestMTGARawDBReusesHandleUntilFileChanges(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "Raw_CardDatabase_test.mtga")
+	writeMTGARawCardDB(t, path, "Llanowar Elves")
+	t.Setenv("MTGA_RAW_CARD_DB", path)
+
+	server := NewServer(nil, "", nil)
+	names, err := server.fetchCardNamesFromMTGARaw(ctx, []int64{7001})
+	if err != nil || names[7001] != "Llanowar Elves" {
+		t.Fatalf("first lookup = %v, %v", names, err)
+	}
+	first := server.mtgaRaw.current.db
+
+	if _, err := server.fetchCardNamesFromMTGARaw(ctx, []int64{7001}); err != nil {
+		t.Fatalf("second lookup: %v", err)
+	}
+	if server.mtgaRaw.current.db != first {
+		t.Fatal("unchanged raw card db was reopened")
+	}
+
+	// Hold the old handle across the swap, as a concurrent request would.
+	held, release, err := server.mtgaRaw.acquire(ctx)
+	if err != nil || held != first {
+		t.Fatalf("acquire = %p, %v; want %p", held, err, first)
+	}
+	writeMTGARawCardDB(t, path, "Elvish Mystic")
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	names, err = server.fetchCardNamesFromMTGARaw(ctx, []int64{7001})
+	if err != nil || names[7001] != "Elvish Mystic" {
+		t.Fatalf("lookup after update = %v, %v", names, err)
+	}
+	if server.mtgaRaw.current.db == first {
+		t.Fatal("updated raw card db was not reopened")
+	}
+	if err := held.PingContext(ctx); err != nil {
+		t.Fatalf("replaced handle closed while in use: %v", err)
+	}
+	release()
+	if err := held.PingContext(ctx); err == nil {
+		t.Fatal("replaced handle still open after release")
+	}
+}
+
+func TestServerCloseClosesMTGARawDB(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "Raw_CardDatabase_test.mtga")
+	writeMTGARawCardDB(t, path, "Llanowar Elves")
+	t.Setenv("MTGA_RAW_CARD_DB", path)
+
+	server := NewServer(nil, "", nil)
+	if _, err := server.fetchCardNamesFromMTGARaw(ctx, []int64{7001}); err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	handle := server.mtgaRaw.current.db
+
+	server.Close()
+	if server.mtgaRaw.current != nil {
+		t.Fatal("raw card db handle still cached after Close")
+	}
+	if err := handle.PingContext(ctx); err == nil {
+		t.Fatal("raw card db handle still open after Close")
+	}
+}
@@ -0,0 +1,500 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/solean/ponder/internal/ai"
+	"github.com/solean/ponder/internal/appstate"
+	"github.com/solean/ponder/internal/graphql"
+	"github.com/solean/ponder/internal/version"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// apiOperation documents one method on one path. Response and Request are
+// zero values of the Go types the handler encodes and decodes; their schemas
+// are derived by reflection so the document cannot drift from the structs.
+type apiOperation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Params      []apiParam
+	Request     any
+	Response    any
+	ContentType string // defaults to application/json
+	Runtime     bool   // only registered when runtime controls are available
+}
+
+type apiParam struct {
+	Name        string
+	In          string // "path" or "query"
+	Type        string // "integer", "string", or "boolean"
+	Description string
+}
+
+var (
+	matchIDParam = apiParam{Name: "id", In: "path", Type: "integer", Description: "Match ID"}
+	deckIDParam  = apiParam{Name: "id", In: "path", Type: "integer", Description: "Deck ID"}
+	draftIDParam = apiParam{Name: "id", In: "path", Type: "integer", Description: "Draft session ID"}
+	rawParam     = apiParam{Name: "raw", In: "query", Type: "boolean", Description: "Omit eventDisplayName"}
+	langParam    = apiParam{Name: "lang", In: "query", Type: "string", Description: "Display-name language (en, de, es, fr); defaults to Accept-Language"}
+
+	exportParams = []apiParam{
+		{Name: "format", In: "query", Type: "string", Description: "ndjson (default), json, or csv"},
+		{Name: "event", In: "query", Type: "string", Description: "Only rows from this event"},
+		{Name: "result", In: "query", Type: "string", Description: "Only matches with this result (matches and card plays)"},
+		{Name: "includeArchived", In: "query", Type: "boolean", Description: "Include archived matches"},
+	}
+)
+
+// apiOperations is the documented surface of routes(). Keep it in step when
+// adding a handler.
+var apiOperations = []apiOperation{
+	{Method: http.MethodGet, Path: "/api/health", Summary: "Database reachability, schema version, and ingest freshness", Response: model.HealthStatus{}},
+	{Method: http.MethodGet, Path: "/api/overview", Summary: "Headline win rates and recent matches",
+		Params: []apiParam{
+			{Name: "recent", In: "query", Type: "integer", Description: "Number of recent matches"},
+			{Name: "from", In: "query", Type: "string", Description: "First UTC day to include (YYYY-MM-DD or RFC 3339)"},
+			{Name: "to", In: "query", Type: "string", Description: "Last UTC day to include (YYYY-MM-DD or RFC 3339)"},
+			{Name: "format", In: "query", Type: "string", Description: "Match format, e.g. Standard"},
+			{Name: "queue", In: "query", Type: "string", Description: "Event name, event kind (ladder, premier_draft, ...), or ranked for both constructed ladders"},
+		},
+		Response: model.Overview{}},
+	{Method: http.MethodGet, Path: "/api/rank-history", Summary: "Rank progression after each ranked match",
+		Params: []apiParam{rawParam, langParam}, Response: []model.RankHistoryPoint{}},
+	{Method: http.MethodGet, Path: "/api/rank/history", Summary: "Rank progression as one chartable series per ladder, with season boundaries",
+		Params: []apiParam{rawParam, langParam}, Response: model.RankHistorySeries{}},
+	{Method: http.MethodGet, Path: "/api/queue-times", Summary: "Matchmaking wait by event and local hour", Response: model.QueueTimeStats{}},
+	{Method: http.MethodGet, Path: "/api/sessions", Summary: "Play sessions, newest first, with record, rank change, and loss streaks",
+		Params: []apiParam{
+			{Name: "days", In: "query", Type: "integer", Description: "How many days back (default 30)"},
+			{Name: "gap", In: "query", Type: "integer", Description: "Minutes between matches that start a new session (default 30)"},
+			{Name: "tilt", In: "query", Type: "integer", Description: "Loss streak that marks a session as tilted (default 3)"},
+		},
+		Response: []model.PlaySessionSummary{}},
+	{Method: http.MethodGet, Path: "/api/economy", Summary: "Currency history, transactions, and event-run economics", Response: model.EconomyHistory{}},
+	{Method: http.MethodGet, Path: "/api/collection/insights", Summary: "First- and last-seen dates for every card we have held",
+		Params: []apiParam{
+			{Name: "view", In: "query", Type: "string", Description: "all (default), never-played, or new"},
+			{Name: "since", In: "query", Type: "string", Description: "Cutoff for new cards (YYYY-MM-DD or RFC 3339); defaults to the start of this month"},
+		},
+		Response: model.CollectionInsights{}},
+	{Method: http.MethodGet, Path: "/api/collection/value", Summary: "The collection's paper value at each weekly Scryfall price snapshot",
+		Response: model.CollectionValueHistory{}},
+	{Method: http.MethodGet, Path: "/api/matches", Summary: "Recent matches, newest first",
+		Params: []apiParam{
+			{Name: "limit", In: "query", Type: "integer", Description: "Maximum rows (default 200)"},
+			{Name: "event", In: "query", Type: "string", Description: "Filter by raw event name"},
+			{Name: "result", In: "query", Type: "string", Description: "Filter by result (win, loss)"},
+			{Name: "includeArchived", In: "query", Type: "boolean", Description: "Include archived matches"},
+			rawParam, langParam,
+		},
+		Response: []model.MatchRow{}},
+	{Method: http.MethodGet, Path: "/api/matches/{id}", Summary: "Match detail with games, plays, and observed opponent cards",
+		Params: []apiParam{matchIDParam, rawParam, langParam}, Response: model.MatchDetail{}},
+	{Method: http.MethodDelete, Path: "/api/matches/{id}", Summary: "Delete a match and everything recorded for it",
+		Params: []apiParam{matchIDParam},
+		Response: struct {
+			Status  string `json:"status"`
+			MatchID int64  `json:"matchId"`
+		}{}},
+	{Method: http.MethodPost, Path: "/api/matches/{id}/archive", Summary: "Hide a match from lists and stats without deleting it",
+		Params: []apiParam{matchIDParam},
+		Response: struct {
+			Status  string `json:"status"`
+			MatchID int64  `json:"matchId"`
+		}{}},
+	{Method: http.MethodPost, Path: "/api/matches/{id}/unarchive", Summary: "Restore an archived match",
+		Params: []apiParam{matchIDParam},
+		Response: struct {
+			Status  string `json:"status"`
+			MatchID int64  `json:"matchId"`
+		}{}},
+	{Method: http.MethodGet, Path: "/api/matches/{id}/timeline", Summary: "Turn-by-turn plays, life changes, and annotations per game",
+		Params: []apiParam{matchIDParam}, Response: model.MatchTimeline{}},
+	{Method: http.MethodGet, Path: "/api/matches/{id}/replay", Summary: "Replay frames",
+		Params: []apiParam{matchIDParam}, Response: []model.MatchReplayFrameRow{}},
+	{Method: http.MethodGet, Path: "/api/matches/{id}/opponent-decklist", Summary: "The opponent's revealed cards as Arena import text",
+		Params: []apiParam{matchIDParam}, ContentType: "text/plain"},
+	{Method: http.MethodPost, Path: "/api/matches/{id}/opponent-archetype", Summary: "Override the derived opponent archetype (empty clears it)",
+		Params: []apiParam{matchIDParam},
+		Request: struct {
+			Archetype string `json:"archetype"`
+		}{},
+		Response: struct {
+			Status    string `json:"status"`
+			Archetype string `json:"archetype"`
+		}{}},
+	{Method: http.MethodGet, Path: "/api/limited/matchups", Summary: "Opponent color-pair records per limited set", Response: model.LimitedMatchupsResponse{}},
+	{Method: http.MethodGet, Path: "/api/stats/limited", Summary: "Draft trophies, color-pair records, and first picks per set", Response: model.LimitedStatsResponse{}},
+	{Method: http.MethodGet, Path: "/api/stats/limited/colors", Summary: "One set's draft records by the final build's main colors and splash",
+		Params:   []apiParam{{Name: "set", In: "query", Type: "string", Description: "Set code, e.g. BLB (required)"}},
+		Response: model.LimitedColorStats{}},
+	{Method: http.MethodGet, Path: "/api/stats/playdraw", Summary: "Game win rates on the play and on the draw, overall and per deck, with 95% intervals", Response: model.PlayDrawStats{}},
+	{Method: http.MethodGet, Path: "/api/stats/milestones", Summary: "Current and best win streaks and milestones reached, newest first", Response: model.MilestoneStats{}},
+	{Method: http.MethodGet, Path: "/api/stats/economy", Summary: "Average gems and gold each event kind returns per run, net of the entry fee",
+		Params: []apiParam{
+			{Name: "goldPerGem", In: "query", Type: "number", Description: "Gold per gem for the gem-equivalent EV; defaults to the draft entry rate (6.67)"},
+			langParam,
+		},
+		Response: model.EconomyStats{}},
+	{Method: http.MethodGet, Path: "/api/stats/time-of-day", Summary: "Match win rate by local hour of day and weekday, with 95% intervals",
+		Params: []apiParam{
+			{Name: "tz", In: "query", Type: "string", Description: "IANA time zone, e.g. Europe/Berlin; defaults to the server's local zone"},
+			{Name: "days", In: "query", Type: "integer", Description: "Only matches from the last N days; defaults to all"},
+		},
+		Response: model.TimeOfDayStats{}},
+	{Method: http.MethodGet, Path: "/api/stats/game-length", Summary: "Average turns and seconds per match, overall and by deck, format, set, and month", Response: model.GameLengthStats{}},
+	{Method: http.MethodGet, Path: "/api/stats/meta", Summary: "Opponent decks faced per format and week, with share changes from the week before",
+		Params:   []apiParam{{Name: "weeks", In: "query", Type: "integer", Description: "Weeks to report, newest first (default 8, max 52)"}},
+		Response: model.MetaReport{}},
+	{Method: http.MethodGet, Path: "/api/stats/drafts", Summary: "Per set: first picks by rarity and color, wheel rates, and picks against imported ratings", Response: model.DraftStatsResponse{}},
+	{Method: http.MethodGet, Path: "/api/stats/mana", Summary: "Mana screw and flood per deck: opening-hand lands, missed land drops, and spells stuck in hand", Response: model.ManaStats{}},
+	{Method: http.MethodGet, Path: "/api/stats/bo3", Summary: "Best-of-three match and game win rates, with game one against post-board games", Response: model.Bo3Stats{}},
+	{Method: http.MethodGet, Path: "/api/stats/companions", Summary: "Match win rates by companion and Brawl commander, yours and opponents'", Response: model.CompanionStats{}},
+	{Method: http.MethodGet, Path: "/api/stats/trophies", Summary: "Draft and sealed runs with trophies, and average wins per run by set and event kind",
+		Params: []apiParam{langParam}, Response: model.TrophyStats{}},
+	{Method: http.MethodGet, Path: "/api/stats/opponent-ranks", Summary: "Win rates per deck adjusted for the opponents' announced ranks", Response: model.OpponentRankStats{}},
+	{Method: http.MethodGet, Path: "/api/decks", Summary: "Deck summaries",
+		Params: []apiParam{
+			{Name: "scope", In: "query", Type: "string", Description: "constructed (default), draft, or all"},
+			rawParam, langParam,
+		},
+		Response: []model.DeckSummaryRow{}},
+	{Method: http.MethodGet, Path: "/api/decks/{id}", Summary: "Deck detail with versions and recent matches",
+		Params: []apiParam{deckIDParam, rawParam, langParam}, Response: model.DeckDetail{}},
+	{Method: http.MethodDelete, Path: "/api/decks/{id}", Summary: "Delete a deck; its matches are kept without a deck",
+		Params: []apiParam{deckIDParam},
+		Response: struct {
+			Status string `json:"status"`
+			DeckID int64  `json:"deckId"`
+		}{}},
+	{Method: http.MethodPost, Path: "/api/decks/merge", Summary: "Merge duplicate decks into one, moving their versions and matches",
+		Request: struct {
+			TargetID  int64   `json:"targetId"`
+			SourceIDs []int64 `json:"sourceIds"`
+		}{},
+		Response: model.DeckMergeResult{}},
+	{Method: http.MethodGet, Path: "/api/decks/{id}/analytics", Summary: "Deck card and game-shape analytics",
+		Params:   []apiParam{deckIDParam, {Name: "version", In: "query", Type: "integer", Description: "Restrict to one deck version"}},
+		Response: model.DeckAnalytics{}},
+	{Method: http.MethodGet, Path: "/api/decks/{id}/analytics/games", Summary: "Games behind one analytics facet",
+		Params: []apiParam{
+			deckIDParam,
+			{Name: "facet", In: "query", Type: "string", Description: "Analytics facet"},
+			{Name: "game", In: "query", Type: "string", Description: "Game filter"},
+			{Name: "playDraw", In: "query", Type: "string", Description: "play or draw"},
+			{Name: "landDrops", In: "query", Type: "string", Description: "Land-drop filter"},
+		},
+		Response: []model.DeckAnalyticsGameRef{}},
+	{Method: http.MethodGet, Path: "/api/decks/{id}/analytics/cards", Summary: "Deck win rate with each card drawn against not drawn",
+		Params: []apiParam{
+			deckIDParam,
+			{Name: "version", In: "query", Type: "integer", Description: "Restrict to one deck version"},
+			{Name: "minGames", In: "query", Type: "integer", Description: "Games needed both drawn and not drawn (default 10)"},
+		},
+		Response: model.DeckCardDrawStats{}},
+	{Method: http.MethodGet, Path: "/api/decks/{id}/analytics/curve", Summary: "Deck mana curve against the turns its spells were actually cast",
+		Params: []apiParam{
+			deckIDParam,
+			{Name: "version", In: "query", Type: "integer", Description: "Restrict to one deck version"},
+			{Name: "minCasts", In: "query", Type: "integer", Description: "Casts a card needs before it can be flagged late (default 5)"},
+		},
+		Response: model.DeckCastCurve{}},
+	{Method: http.MethodGet, Path: "/api/decks/{id}/matchups", Summary: "Opponent-archetype matchups for one deck",
+		Params: []apiParam{deckIDParam}, Response: model.DeckMatchupsResponse{}},
+	{Method: http.MethodGet, Path: "/api/decks/{id}/decklist", Summary: "A deck as Arena import text (\"4 Lightning Strike (DMU) 137\")",
+		Params: []apiParam{deckIDParam}, ContentType: "text/plain"},
+	{Method: http.MethodPost, Path: "/api/decks/{id}/push", Summary: "Push a deck to Moxfield or Archidekt with the token saved in settings",
+		Params: []apiParam{deckIDParam}, Request: struct {
+			Service string `json:"service"`
+		}{}, Response: model.DeckPushResult{}},
+	{Method: http.MethodGet, Path: "/api/decks/{id}/primer", Summary: "Saved AI deck primer",
+		Params: []apiParam{deckIDParam}, Response: model.DeckPrimer{}},
+	{Method: http.MethodPost, Path: "/api/decks/{id}/primer", Summary: "Generate an AI deck primer (server-sent events)",
+		Params: []apiParam{deckIDParam}, ContentType: "text/event-stream"},
+	{Method: http.MethodGet, Path: "/api/drafts", Summary: "Draft sessions",
+		Params: []apiParam{rawParam, langParam}, Response: []model.DraftSessionRow{}},
+	{Method: http.MethodGet, Path: "/api/drafts/{id}/picks", Summary: "Picks in one draft, graded against imported 17Lands ratings",
+		Params: []apiParam{draftIDParam}, Response: []model.DraftPickRow{}},
+	{Method: http.MethodGet, Path: "/api/drafts/{id}/suggestions", Summary: "Pick advisor's ranking of one pick's pack (the latest by default)",
+		Params: []apiParam{draftIDParam,
+			{Name: "pack", In: "query", Type: "integer", Description: "Pack number, with pick"},
+			{Name: "pick", In: "query", Type: "integer", Description: "Pick number, with pack"}},
+		Response: model.DraftPickSuggestions{}},
+	{Method: http.MethodGet, Path: "/api/sets", Summary: "Set names and icons keyed by lowercase code",
+		Params:   []apiParam{{Name: "codes", In: "query", Type: "string", Description: "Comma-separated set codes"}},
+		Response: map[string]model.SetInfo{}},
+	{Method: http.MethodGet, Path: "/api/settings", Summary: "Saved preferences, with defaults for unset keys", Response: model.Settings{}},
+	{Method: http.MethodPut, Path: "/api/settings", Summary: "Update preferences; omitted keys keep their saved values",
+		Request: model.Settings{}, Response: model.Settings{}},
+	{Method: http.MethodGet, Path: "/api/cards", Summary: "Name, set, rarity, and colors for Arena card ids, keyed by id",
+		Params:   []apiParam{{Name: "ids", In: "query", Type: "string", Description: "Comma-separated Arena card ids (at most 500)"}},
+		Response: map[string]model.CardInfo{}},
+	{Method: http.MethodGet, Path: "/api/cards/{grpId}/image", Summary: "Card image by Arena id, proxied from Scryfall and cached on disk",
+		Params: []apiParam{
+			{Name: "grpId", In: "path", Type: "integer", Description: "Arena card id"},
+			{Name: "version", In: "query", Type: "string", Description: "small, normal (default), large, png, art_crop, or border_crop"},
+		}, ContentType: "image/*"},
+	{Method: http.MethodGet, Path: "/api/export/matches", Summary: "Every match (NDJSON, a JSON array, or CSV)",
+		Params: exportParams, Response: []model.MatchRow{}, ContentType: "application/x-ndjson"},
+	{Method: http.MethodGet, Path: "/api/export/card-plays", Summary: "Every card play (NDJSON, a JSON array, or CSV)",
+		Params: exportParams, Response: []model.CardPlayExportRow{}, ContentType: "application/x-ndjson"},
+	{Method: http.MethodGet, Path: "/api/export/decks", Summary: "Every deck's current card list (NDJSON, a JSON array, or CSV)",
+		Params: exportParams, Response: []model.DeckCardExportRow{}, ContentType: "application/x-ndjson"},
+	{Method: http.MethodGet, Path: "/api/export/draft-picks", Summary: "Every draft pick (NDJSON, a JSON array, or CSV)",
+		Params: exportParams, Response: []model.DraftPickExportRow{}, ContentType: "application/x-ndjson"},
+	{Method: http.MethodGet, Path: "/api/export/collection", Summary: "Every card we have held with estimated copies (NDJSON, a JSON array, CSV, or MTGGoldfish/Deckbox CSV)",
+		Params:   []apiParam{{Name: "format", In: "query", Type: "string", Description: "ndjson (default), json, csv, mtggoldfish, or deckbox"}},
+		Response: []model.CollectionExportRow{}, ContentType: "application/x-ndjson"},
+	{Method: http.MethodGet, Path: "/api/ai/status", Summary: "Whether AI features are available", Response: ai.Status{}},
+	{Method: http.MethodGet, Path: "/api/live", Summary: "The match in progress, if any",
+		Response: struct {
+			Live *model.LiveMatch `json:"live"`
+		}{}},
+	{Method: http.MethodPost, Path: "/api/graphql", Summary: "Read-only GraphQL over matches, decks, drafts, and stats",
+		Request: graphql.Request{}, Response: graphql.Response{}},
+	{Method: http.MethodGet, Path: "/api/openapi.json", Summary: "This document", Response: map[string]any{}},
+
+	{Method: http.MethodGet, Path: "/api/runtime/status", Summary: "Runtime configuration and ingest state", Response: appstate.Status{}, Runtime: true},
+	{Method: http.MethodPost, Path: "/api/runtime/config", Summary: "Save runtime configuration", Request: appstate.Config{}, Response: appstate.Status{}, Runtime: true},
+	{Method: http.MethodPost, Path: "/api/runtime/import", Summary: "Import the configured logs now",
+		Request: struct {
+			Resume *bool `json:"resume"`
+		}{},
+		Response: appstate.OperationResult{}, Runtime: true},
+	{Method: http.MethodPost, Path: "/api/runtime/live/start", Summary: "Start live tracking", Response: appstate.Status{}, Runtime: true},
+	{Method: http.MethodPost, Path: "/api/runtime/live/stop", Summary: "Stop live tracking", Response: appstate.Status{}, Runtime: true},
+	{Method: http.MethodGet, Path: "/api/runtime/autostart", Summary: "Launch-at-login status", Response: appstate.AutostartStatus{}, Runtime: true},
+	{Method: http.MethodPost, Path: "/api/runtime/autostart", Summary: "Enable or disable launch at login",
+		Request: struct {
+			Enabled bool `json:"enabled"`
+		}{},
+		Response: appstate.AutostartStatus{}, Runtime: true},
+	{Method: http.MethodGet, Path: "/api/runtime/update-check", Summary: "Check GitHub for a newer release", Response: appstate.UpdateCheck{}, Runtime: true},
+	{Method: http.MethodPost, Path: "/api/runtime/pick-log", Summary: "Choose a log file with a native dialog (desktop only)",
+		Response: struct {
+			Path string `json:"path"`
+		}{}, Runtime: true},
+	{Method: http.MethodPost, Path: "/api/runtime/reveal", Summary: "Reveal a displayed path in the file manager (desktop only)",
+		Request: struct {
+			Path string `json:"path"`
+		}{},
+		Response: struct {
+			Status string `json:"status"`
+		}{}, Runtime: true},
+	{Method: http.MethodPost, Path: "/api/admin/backup", Summary: "Write a backup into the nightly backup directory now",
+		Response: appstate.BackupResult{}, Runtime: true},
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  map[string]any
+)
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	openAPIOnce.Do(func() {
+		openAPIDoc = buildOpenAPI(apiOperations)
+	})
+	writeJSON(w, http.StatusOK, openAPIDoc)
+}
+
+// buildOpenAPI renders operations as an OpenAPI 3.0 document. Runtime
+// operations are always listed (tagged "runtime") since the document
+// describes the contract rather than one server's configuration.
+func buildOpenAPI(operations []apiOperation) map[string]any {
+	gen := &schemaGenerator{schemas: map[string]any{
+		"Error": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"error": map[string]any{"type": "string"}},
+			"required":   []string{"error"},
+		},
+	}}
+
+	paths := map[string]any{}
+	for _, op := range operations {
+		item, _ := paths[op.Path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[op.Path] = item
+		}
+
+		contentType := op.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		okResponse := map[string]any{"description": "OK"}
+		if op.Response != nil {
+			okResponse["content"] = map[string]any{
+				contentType: map[string]any{"schema": gen.schemaFor(reflect.TypeOf(op.Response))},
+			}
+		} else if op.ContentType != "" {
+			okResponse["content"] = map[string]any{contentType: map[string]any{"schema": map[string]any{"type": "string"}}}
+		}
+
+		operation := map[string]any{
+			"summary": op.Summary,
+			"responses": map[string]any{
+				"200": okResponse,
+				"default": map[string]any{
+					"description": "Error",
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Error"}},
+					},
+				},
+			},
+		}
+		if op.Runtime {
+			operation["tags"] = []string{"runtime"}
+		}
+		if len(op.Params) > 0 {
+			params := make([]any, 0, len(op.Params))
+			for _, p := range op.Params {
+				params = append(params, map[string]any{
+					"name":        p.Name,
+					"in":          p.In,
+					"required":    p.In == "path",
+					"description": p.Description,
+					"schema":      map[string]any{"type": p.Type},
+				})
+			}
+			operation["parameters"] = params
+		}
+		if op.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": gen.schemaFor(reflect.TypeOf(op.Request))},
+				},
+			}
+		}
+		item[strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Ponder API",
+			"version":     version.Version,
+			"description": "MTG Arena match history and analytics recorded by Ponder.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": gen.schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+		// The API key is optional: servers without -api-key accept anonymous
+		// requests, so either requirement satisfies the document.
+		"security": []any{map[string]any{}, map[string]any{"bearerAuth": []string{}}},
+	}
+}
+
+// schemaGenerator maps Go types to JSON schemas, registering named structs
+// under components/schemas and referencing them by $ref.
+type schemaGenerator struct {
+	schemas map[string]any
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Pointer:
+		schema := g.schemaFor(t.Elem())
+		if _, isRef := schema["$ref"]; isRef {
+			// OpenAPI 3.0 ignores siblings of $ref, so wrap it to carry nullable.
+			return map[string]any{"allOf": []any{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := schemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			// Reserve the name first so self-referencing types terminate.
+			g.schemas[name] = map[string]any{}
+			g.schemas[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	g.collectFields(t, properties, &required)
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// collectFields follows encoding/json: embedded structs without a tag are
+// flattened, "-" is skipped, and omitempty fields are optional.
+func (g *schemaGenerator) collectFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.collectFields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = g.schemaFor(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaName is the bare type name for the model package and is prefixed
+// with the package name elsewhere, so appstate.Status and ai.Status differ.
+func schemaName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	if pkg == "model" || pkg == "" {
+		return t.Name()
+	}
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + t.Name()
+}
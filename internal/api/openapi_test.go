@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIDocumentResolvesEverySchemaRef(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewServer(nil, "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var doc struct {
+		OpenAPI    string                                `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Fatalf("openapi = %q, want 3.x", doc.OpenAPI)
+	}
+	for path, methods := range map[string][]string{
+		"/api/matches/{id}":      {"get"},
+		"/api/runtime/autostart": {"get", "post"},
+		"/api/export/card-plays": {"get"},
+	} {
+		for _, method := range methods {
+			if _, ok := doc.Paths[path][method]; !ok {
+				t.Errorf("document missing %s %s", method, path)
+			}
+		}
+	}
+	for _, name := range []string{"MatchDetail", "AppstateStatus", "AiStatus", "CardPlayExportRow"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("components missing schema %q", name)
+		}
+	}
+
+	var walk func(any)
+	walk = func(node any) {
+		switch v := node.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				name := strings.TrimPrefix(ref, "#/components/schemas/")
+				if _, ok := doc.Components.Schemas[name]; !ok {
+					t.Errorf("unresolved $ref %q", ref)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	var generic any
+	if err := json.Unmarshal(rec.Body.Bytes(), &generic); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+	walk(generic)
+}
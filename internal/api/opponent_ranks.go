@@ -0,0 +1,24 @@
+package api
+
+import "net/http"
+
+// handleOpponentRankStats serves GET /api/stats/opponent-ranks: each deck's
+// record next to the average rank of the opponents it came against and a
+// rank-weighted win rate, so a win rate farmed in Silver reads differently
+// from a slightly lower one held in Mythic.
+func (s *Server) handleOpponentRankStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	out, err := s.store.OpponentRankRecords(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fillWinRateInterval(&out.Overall.Matches)
+	for index := range out.Decks {
+		fillWinRateInterval(&out.Decks[index].Matches)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
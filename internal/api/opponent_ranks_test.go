@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestOpponentRankStatsWeighResultsByOpponentTier(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// The Silver deck goes 3-2 against Silver 2 opponents; the Mythic deck
+	// goes 1-1 in Mythic. A match against an unranked opponent only counts.
+	for _, query := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, format, created_at, updated_at) VALUES
+			(1, 'deck-silver', 'Farmer', 'Standard', 'x', 'x'), (2, 'deck-mythic', 'Climber', 'Standard', 'x', 'x')`,
+		`INSERT INTO matches (id, arena_match_id, event_name, result, opponent_rank_class, opponent_rank_level, created_at, updated_at) VALUES
+			(1, 'm1', 'Ladder', 'win', 'Silver', 2, 'x', 'x'),
+			(2, 'm2', 'Ladder', 'win', 'Silver', 2, 'x', 'x'),
+			(3, 'm3', 'Ladder', 'win', 'Silver', 2, 'x', 'x'),
+			(4, 'm4', 'Ladder', 'loss', 'Silver', 2, 'x', 'x'),
+			(5, 'm5', 'Ladder', 'loss', 'Silver', 2, 'x', 'x'),
+			(6, 'm6', 'Ladder', 'win', 'Mythic', NULL, 'x', 'x'),
+			(7, 'm7', 'Ladder', 'loss', 'Mythic', NULL, 'x', 'x'),
+			(8, 'm8', 'Play', 'win', NULL, NULL, 'x', 'x')`,
+		`INSERT INTO match_decks (match_id, deck_id, snapshot_reason, created_at) VALUES
+			(1, 1, 'x', 'x'), (2, 1, 'x', 'x'), (3, 1, 'x', 'x'), (4, 1, 'x', 'x'), (5, 1, 'x', 'x'),
+			(6, 2, 'x', 'x'), (7, 2, 'x', 'x'), (8, 2, 'x', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(db.NewStore(database), "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/opponent-ranks", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var out model.OpponentRankStats
+	if err := json.NewDecoder(rec.Body).Decode(&out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	near := func(got *float64, want float64) bool { return got != nil && math.Abs(*got-want) < 1e-9 }
+	if out.MatchesWithoutRank != 1 || out.Overall.Matches.Record.Games != 7 {
+		t.Fatalf("overall = %+v, %d without rank; want 7 ranked and 1 without", out.Overall.Matches.Record, out.MatchesWithoutRank)
+	}
+	// Wins weigh 3 against Silver and 7 against Mythic; losses 5 and 1.
+	if !near(out.Overall.WeightedWinRate, 16.0/27) {
+		t.Fatalf("overall weighted = %v, want 16/27", out.Overall.WeightedWinRate)
+	}
+	if len(out.Decks) != 2 {
+		t.Fatalf("decks = %+v, want 2", out.Decks)
+	}
+	farmer, climber := out.Decks[0], out.Decks[1]
+	if farmer.DeckName != "Farmer" || !near(farmer.Matches.WinRate, 0.6) || !near(farmer.WeightedWinRate, 9.0/19) {
+		t.Fatalf("farmer = %+v, want 60%% raw and 9/19 weighted", farmer)
+	}
+	if !near(farmer.AverageOpponentTier, 2.5) || farmer.AverageOpponentLabel != "Silver" {
+		t.Fatalf("farmer opponents = %v %q, want 2.5 Silver", farmer.AverageOpponentTier, farmer.AverageOpponentLabel)
+	}
+	if len(farmer.ByTier) != 1 || farmer.ByTier[0].Tier != "Silver" || farmer.ByTier[0].Record.Wins != 3 {
+		t.Fatalf("farmer by tier = %+v", farmer.ByTier)
+	}
+	if climber.Matches.Record.Games != 2 || !near(climber.WeightedWinRate, 7.0/8) || climber.AverageOpponentLabel != "Mythic" {
+		t.Fatalf("climber = %+v, want 1-1 in Mythic weighted 7/8", climber)
+	}
+	if *climber.WeightedWinRate <= *farmer.WeightedWinRate {
+		t.Fatalf("50%% in Mythic should outweigh 60%% in Silver")
+	}
+}
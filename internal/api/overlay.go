@@ -0,0 +1,234 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/model"
+)
+
+//go:embed overlay.html
+var overlayPageSource string
+
+var overlayPage = template.Must(template.New("overlay").Parse(overlayPageSource))
+
+const (
+	// overlayPollInterval is how often an overlay stream re-reads its
+	// snapshot; only changes are sent.
+	overlayPollInterval = 2 * time.Second
+	// overlayHeartbeat keeps idle streams from being dropped by proxies.
+	overlayHeartbeat = 15 * time.Second
+	// overlayDraftPackCards is how many of the pack's best-rated cards the
+	// draft overlay lists.
+	overlayDraftPackCards = 5
+)
+
+// overlays are the /overlay/<name> pages for OBS browser sources, each with
+// the snapshot its page renders.
+var overlays = map[string]struct {
+	title    string
+	snapshot func(s *Server, ctx context.Context) (any, error)
+}{
+	"record":   {"Today's record", (*Server).overlayRecord},
+	"opponent": {"Opponent cards", (*Server).overlayOpponent},
+	"draft":    {"Draft picks", (*Server).overlayDraft},
+}
+
+// handleOverlay serves /overlay/<name> as a page with a transparent
+// background, /overlay/<name>/events as a Server-Sent Events stream of its
+// snapshot (an `update` event whenever it changes), and
+// /overlay/<name>/state as that snapshot once. OBS cannot send an
+// Authorization header, so with an API key set the key is passed as ?key=.
+func (s *Server) handleOverlay(w http.ResponseWriter, r *http.Request) {
+	name, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/overlay/"), "/")
+	overlay, ok := overlays[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.queryKeyValid(r) {
+		http.Error(w, "missing or invalid key", http.StatusUnauthorized)
+		return
+	}
+	snapshot := func(ctx context.Context) (any, error) { return overlay.snapshot(s, ctx) }
+
+	switch rest {
+	case "":
+		// Absolute, so /overlay/<name>/ streams from the same place.
+		eventsURL := s.basePath + "/overlay/" + name + "/events"
+		if key := r.URL.Query().Get("key"); key != "" {
+			eventsURL += "?key=" + url.QueryEscape(key)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := overlayPage.Execute(w, map[string]string{"Kind": name, "Title": overlay.title, "EventsURL": eventsURL}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	case "state":
+		state, err := snapshot(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, state)
+	case "events":
+		s.streamOverlay(w, r, snapshot)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// streamOverlay sends the snapshot straight away and again whenever it
+// changes, until the client goes away.
+func (s *Server) streamOverlay(w http.ResponseWriter, r *http.Request, snapshot func(context.Context) (any, error)) {
+	flush := func() {}
+	if flusher, ok := w.(http.Flusher); ok {
+		flush = flusher.Flush
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flush()
+
+	ctx := r.Context()
+	poll := time.NewTicker(overlayPollInterval)
+	defer poll.Stop()
+	var last []byte
+	lastWrite := time.Now()
+	for {
+		state, err := snapshot(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		var data []byte
+		if err == nil {
+			data, err = json.Marshal(state)
+		}
+		switch {
+		case err != nil:
+			fmt.Fprintf(w, "event: error\ndata: %q\n\n", err.Error())
+			lastWrite = time.Now()
+			flush()
+		case !bytes.Equal(data, last):
+			fmt.Fprintf(w, "event: update\ndata: %s\n\n", data)
+			last = data
+			lastWrite = time.Now()
+			flush()
+		case time.Since(lastWrite) >= overlayHeartbeat:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			lastWrite = time.Now()
+			flush()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+		}
+	}
+}
+
+type overlayRecordState struct {
+	Wins   int64  `json:"wins"`
+	Losses int64  `json:"losses"`
+	Draws  int64  `json:"draws"`
+	Since  string `json:"since"`
+}
+
+// overlayRecord is today's record, from local midnight.
+func (s *Server) overlayRecord(ctx context.Context) (any, error) {
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	record, err := s.store.RecordSince(ctx, midnight)
+	if err != nil {
+		return nil, err
+	}
+	return overlayRecordState{Wins: record.Wins, Losses: record.Losses, Draws: record.Draws, Since: midnight.Format(time.RFC3339)}, nil
+}
+
+type overlayOpponentState struct {
+	Live     bool                            `json:"live"`
+	Opponent string                          `json:"opponent,omitempty"`
+	Event    string                          `json:"event,omitempty"`
+	Cards    []model.OpponentObservedCardRow `json:"cards"`
+}
+
+// overlayOpponent lists the cards the opponent has revealed in the match
+// in progress.
+func (s *Server) overlayOpponent(ctx context.Context) (any, error) {
+	state := overlayOpponentState{Cards: []model.OpponentObservedCardRow{}}
+	id, ok, err := s.store.GetLiveMatchID(ctx)
+	if err != nil || !ok {
+		return state, err
+	}
+	detail, err := s.store.GetMatchDetail(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	s.enrichOpponentObservedCardNames(ctx, detail.OpponentObservedCards)
+	state.Live = true
+	state.Opponent = detail.Match.Opponent
+	state.Event = eventnames.Display(detail.Match.EventName, eventnames.DefaultLanguage, "")
+	if detail.OpponentObservedCards != nil {
+		state.Cards = detail.OpponentObservedCards
+	}
+	return state, nil
+}
+
+type overlayDraftState struct {
+	Active     bool                  `json:"active"`
+	Event      string                `json:"event,omitempty"`
+	PackNumber int64                 `json:"packNumber,omitempty"`
+	PickNumber int64                 `json:"pickNumber,omitempty"`
+	Picked     []model.DraftPickCard `json:"picked"`
+	Grade      *model.DraftPickGrade `json:"grade,omitempty"`
+	TopCards   []model.DraftPickCard `json:"topCards"`
+}
+
+// overlayDraft shows the latest pick of a draft still in progress: what was
+// taken and, with 17Lands ratings imported, the pack's best-rated cards.
+func (s *Server) overlayDraft(ctx context.Context) (any, error) {
+	state := overlayDraftState{Picked: []model.DraftPickCard{}, TopCards: []model.DraftPickCard{}}
+	draft, found, err := s.store.LatestDraftSession(ctx)
+	if err != nil || !found || draft.CompletedAt != "" {
+		return state, err
+	}
+	picks, err := s.store.ListDraftPicks(ctx, draft.ID)
+	if err != nil || len(picks) == 0 {
+		return state, err
+	}
+	s.enrichDraftPickCardNames(ctx, picks)
+	s.gradeDraftPicks(ctx, draft.EventName, picks)
+
+	last := picks[len(picks)-1]
+	state.Active = true
+	state.Event = eventnames.Display(draft.EventName, eventnames.DefaultLanguage, "")
+	state.PackNumber = last.PackNumber
+	state.PickNumber = last.PickNumber
+	state.Picked = append(state.Picked, last.PickedCards...)
+	state.Grade = last.Grade
+
+	rated := make([]model.DraftPickCard, 0, len(last.PackCards))
+	for _, card := range last.PackCards {
+		if card.Rating != nil {
+			rated = append(rated, card)
+		}
+	}
+	sort.SliceStable(rated, func(i, j int) bool { return *rated[i].Rating > *rated[j].Rating })
+	state.TopCards = append(state.TopCards, rated[:min(len(rated), overlayDraftPackCards)]...)
+	return state, nil
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestOverlayRecordPageAndState(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	if _, err := database.ExecContext(ctx, `INSERT INTO matches (arena_match_id, ended_at, result, created_at, updated_at) VALUES
+		('today-win', ?, 'win', 'x', 'x'),
+		('today-loss', ?, 'loss', 'x', 'x'),
+		('old-win', '2020-01-01T12:00:00Z', 'win', 'x', 'x'),
+		('today-archived', ?, 'loss', 'x', 'x')`, now, now, now); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	if _, err := database.ExecContext(ctx, `UPDATE matches SET archived = 1 WHERE arena_match_id = 'today-archived'`); err != nil {
+		t.Fatalf("archive match: %v", err)
+	}
+	server := NewServer(db.NewStore(database), "", nil)
+	server.SetAPIKey("k3y")
+	handler := server.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/overlay/record", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("overlay without key status = %d, want 401", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/overlay/record?key=k3y", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"/overlay/record/events?key=k3y"`) {
+		t.Fatalf("overlay page status = %d, want the page streaming /overlay/record/events with the key; body: %s", rec.Code, rec.Body.String())
+	}
+
+	// A trailing slash must not change where the page streams from.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/overlay/record/?key=k3y", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"/overlay/record/events?key=k3y"`) {
+		t.Fatalf("overlay page with trailing slash status = %d, want the page streaming /overlay/record/events; body: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/overlay/record/state?key=k3y", nil))
+	var state overlayRecordState
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+		t.Fatalf("decode state: %v; body: %s", err, rec.Body.String())
+	}
+	if state.Wins != 1 || state.Losses != 1 || state.Draws != 0 {
+		t.Fatalf("record = %+v, want 1-1 today", state)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/overlay/nope?key=k3y", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown overlay status = %d, want 404", rec.Code)
+	}
+}
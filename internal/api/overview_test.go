@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestOverviewScopesByDaysFormatAndQueue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// Only matches 1 and 3 are ranked Standard in the first week of October.
+	if _, err := database.ExecContext(ctx, `
+		INSERT INTO matches (id, arena_match_id, event_name, format, started_at, result, created_at, updated_at) VALUES
+			(1, 'm1', 'Ladder', 'Standard', '2026-10-05T19:00:00Z', 'win', 'x', 'x'),
+			(2, 'm2', 'Ladder', 'Standard', '2026-09-01T19:00:00Z', 'loss', 'x', 'x'),
+			(3, 'm3', 'Traditional_Ladder', 'Standard', '2026-10-07T23:00:00Z', 'loss', 'x', 'x'),
+			(4, 'm4', 'Play', 'Standard', '2026-10-06T19:00:00Z', 'win', 'x', 'x'),
+			(5, 'm5', 'PremierDraft_BLB_20240801', 'Limited', '2026-10-06T19:00:00Z', 'win', 'x', 'x')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	handler := NewServer(db.NewStore(database), "", nil).Handler()
+	get := func(target string) model.Overview {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d; body: %s", target, rec.Code, rec.Body.String())
+		}
+		var out model.Overview
+		if err := json.NewDecoder(rec.Body).Decode(&out); err != nil {
+			t.Fatalf("%s: decode: %v", target, err)
+		}
+		return out
+	}
+
+	if all := get("/api/overview"); all.TotalMatches != 5 || len(all.Recent) != 5 {
+		t.Fatalf("unscoped overview = %d matches, %d recent; want all 5", all.TotalMatches, len(all.Recent))
+	}
+
+	ranked := get("/api/overview?from=2026-10-01&to=2026-10-07&format=standard&queue=ranked")
+	if ranked.TotalMatches != 2 || ranked.Wins != 1 || ranked.Losses != 1 || ranked.WinRate != 0.5 {
+		t.Fatalf("ranked overview = %+v, want 1-1 over 2 matches", ranked)
+	}
+	if len(ranked.Recent) != 2 || ranked.Recent[0].ID != 3 || ranked.Recent[1].ID != 1 {
+		t.Fatalf("ranked recent = %+v, want matches 3 then 1", ranked.Recent)
+	}
+
+	if ladder := get("/api/overview?from=2026-10-01&queue=ladder"); ladder.TotalMatches != 1 || len(ladder.Recent) != 1 {
+		t.Fatalf("ladder overview = %d matches, %d recent; want 1", ladder.TotalMatches, len(ladder.Recent))
+	}
+	if none := get("/api/overview?queue=brawl"); none.TotalMatches != 0 || len(none.Recent) != 0 {
+		t.Fatalf("brawl overview = %d matches, %d recent; want none", none.TotalMatches, len(none.Recent))
+	}
+
+	for _, target := range []string{"/api/overview?from=last-week", "/api/overview?from=2026-10-07&to=2026-10-01"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400", target, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// PickContext is what a PickAdvisor sees of a draft pick: the cards in the
+// pack and those already taken. It is also the JSON body an HTTPAdvisor
+// posts.
+type PickContext struct {
+	EventName  string                `json:"eventName"`
+	SetCode    string                `json:"setCode"`
+	PackNumber int64                 `json:"packNumber"`
+	PickNumber int64                 `json:"pickNumber"`
+	Pack       []model.DraftPickCard `json:"pack"`
+	Pool       []model.DraftPickCard `json:"pool"`
+}
+
+// PickAdvisor ranks the cards in a pack, best pick first. Cards it has no
+// opinion on may be left out.
+type PickAdvisor interface {
+	Suggest(ctx context.Context, pick PickContext) ([]model.PickSuggestion, error)
+}
+
+// SetPickAdvisor replaces the default RatingsAdvisor, e.g. with an
+// HTTPAdvisor for an external model.
+func (s *Server) SetPickAdvisor(advisor PickAdvisor) {
+	s.pickAdvisor = advisor
+}
+
+func (s *Server) advisor() PickAdvisor {
+	if s.pickAdvisor != nil {
+		return s.pickAdvisor
+	}
+	return RatingsAdvisor{Store: s.store}
+}
+
+// RatingsAdvisor ranks a pack by the 17Lands GIH WR imported for its set,
+// ignoring the pool. Unrated cards are left out.
+type RatingsAdvisor struct {
+	Store *db.Store
+}
+
+func (a RatingsAdvisor) Suggest(ctx context.Context, pick PickContext) ([]model.PickSuggestion, error) {
+	ratings, err := a.Store.ListCardRatings(ctx, pick.SetCode)
+	if err != nil || len(ratings) == 0 {
+		return nil, err
+	}
+	lookup := newRatingLookup(ratings)
+	out := make([]model.PickSuggestion, 0, len(pick.Pack))
+	for _, card := range pick.Pack {
+		rating := lookup.rating(card)
+		if rating == nil {
+			continue
+		}
+		out = append(out, model.PickSuggestion{
+			CardID:   card.CardID,
+			CardName: card.CardName,
+			Score:    *rating,
+			Reason:   fmt.Sprintf("%.1f%% GIH WR", *rating*100),
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out, nil
+}
+
+// HTTPAdvisor asks an external service, such as a draft model run locally,
+// for suggestions. It POSTs the PickContext as JSON to URL and expects
+// {"suggestions": [{"cardId", "score", "reason"}, ...]} back, best first.
+type HTTPAdvisor struct {
+	URL    string
+	Client *http.Client
+}
+
+// errPickAdvisorFailed wraps an external advisor's failure, which the API
+// reports as a bad gateway.
+var errPickAdvisorFailed = errors.New("pick advisor failed")
+
+func (a HTTPAdvisor) Suggest(ctx context.Context, pick PickContext) ([]model.PickSuggestion, error) {
+	body, err := json.Marshal(pick)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errPickAdvisorFailed, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errPickAdvisorFailed, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%w: %s: %s", errPickAdvisorFailed, resp.Status, strings.TrimSpace(string(snippet)))
+	}
+	var decoded struct {
+		Suggestions []model.PickSuggestion `json:"suggestions"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("%w: decode response: %v", errPickAdvisorFailed, err)
+	}
+
+	// Keep only cards actually in the pack, named as ponder names them.
+	names := make(map[int64]string, len(pick.Pack))
+	for _, card := range pick.Pack {
+		names[card.CardID] = card.CardName
+	}
+	out := make([]model.PickSuggestion, 0, len(decoded.Suggestions))
+	for _, suggestion := range decoded.Suggestions {
+		name, inPack := names[suggestion.CardID]
+		if !inPack {
+			continue
+		}
+		if name != "" {
+			suggestion.CardName = name
+		}
+		out = append(out, suggestion)
+	}
+	return out, nil
+}
+
+// handleDraftSuggestions serves GET /api/drafts/:id/suggestions: the
+// advisor's ranking of one pick's pack, given the cards taken before it.
+// ?pack= and ?pick= select the pick; without them it is the latest one.
+func (s *Server) handleDraftSuggestions(w http.ResponseWriter, r *http.Request, draftID int64) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var packNumber, pickNumber int64
+	for name, target := range map[string]*int64{"pack": &packNumber, "pick": &pickNumber} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || value <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid "+name)
+			return
+		}
+		*target = value
+	}
+	if (packNumber == 0) != (pickNumber == 0) {
+		writeError(w, http.StatusBadRequest, "pack and pick must be given together")
+		return
+	}
+
+	ctx := r.Context()
+	eventName, err := s.store.DraftSessionEventName(ctx, draftID)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "draft not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	picks, err := s.store.ListDraftPicks(ctx, draftID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	index := len(picks) - 1
+	if packNumber > 0 {
+		index = -1
+		for i, pick := range picks {
+			if pick.PackNumber == packNumber && pick.PickNumber == pickNumber {
+				index = i
+				break
+			}
+		}
+	}
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "pick not found")
+		return
+	}
+	s.enrichDraftPickCardNames(ctx, picks[:index+1])
+
+	pick := PickContext{
+		EventName:  eventName,
+		SetCode:    eventnames.SetCode(eventName),
+		PackNumber: picks[index].PackNumber,
+		PickNumber: picks[index].PickNumber,
+		Pack:       append([]model.DraftPickCard{}, picks[index].PackCards...),
+		Pool:       []model.DraftPickCard{},
+	}
+	for _, earlier := range picks[:index] {
+		pick.Pool = append(pick.Pool, earlier.PickedCards...)
+	}
+	suggestions, err := s.advisor().Suggest(ctx, pick)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errPickAdvisorFailed) {
+			status = http.StatusBadGateway
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+	if suggestions == nil {
+		suggestions = []model.PickSuggestion{}
+	}
+	writeJSON(w, http.StatusOK, model.DraftPickSuggestions{
+		PackNumber:  pick.PackNumber,
+		PickNumber:  pick.PickNumber,
+		Suggestions: suggestions,
+	})
+}
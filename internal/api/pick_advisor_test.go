@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestDraftSuggestionsFromRatingsAndExternalAdvisor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	for _, query := range []string{
+		`INSERT INTO draft_sessions (id, event_name, draft_id, is_bot_draft, created_at, updated_at)
+			VALUES (1, 'PremierDraft_BLB_20240801', 'd1', 0, 'x', 'x')`,
+		`INSERT INTO draft_picks (draft_session_id, pack_number, pick_number, picked_card_ids, pack_card_ids, created_at) VALUES
+			(1, 1, 1, '[100]', '[100,200,300]', 'x'),
+			(1, 1, 2, '[400]', '[200,300,400]', 'x')`,
+		`INSERT INTO card_catalog (arena_id, name, updated_at) VALUES
+			(100, 'Pond Prophet', 'x'), (200, 'Season of Loss', 'x'), (300, 'Unrated Card', 'x'), (400, 'Mabel''s Mettle', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	store := db.NewStore(database)
+	rate := func(v float64) *float64 { return &v }
+	if _, err := store.ReplaceCardRatings(ctx, "blb", "PremierDraft", []db.CardRating{
+		{Name: "Pond Prophet", GIHWR: rate(0.58)},
+		{Name: "Season of Loss", ArenaID: 200, GIHWR: rate(0.61)},
+		{Name: "Mabel's Mettle", GIHWR: rate(0.55)},
+	}); err != nil {
+		t.Fatalf("ReplaceCardRatings: %v", err)
+	}
+	server := NewServer(store, "", nil)
+	suggest := func(path string) model.DraftPickSuggestions {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s status = %d; body: %s", path, rec.Code, rec.Body.String())
+		}
+		var out model.DraftPickSuggestions
+		if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return out
+	}
+
+	got := suggest("/api/drafts/1/suggestions?pack=1&pick=1")
+	if got.PickNumber != 1 || len(got.Suggestions) != 2 || got.Suggestions[0].CardID != 200 || got.Suggestions[1].CardName != "Pond Prophet" {
+		t.Fatalf("ratings suggestions = %+v, want Season of Loss then Pond Prophet", got)
+	}
+
+	var posted PickContext
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&posted)
+		_, _ = w.Write([]byte(`{"suggestions": [{"cardId": 300, "score": 0.9, "reason": "on color"}, {"cardId": 999, "score": 0.5}]}`))
+	}))
+	defer remote.Close()
+	server.SetPickAdvisor(HTTPAdvisor{URL: remote.URL, Client: remote.Client()})
+
+	got = suggest("/api/drafts/1/suggestions")
+	if got.PickNumber != 2 || len(got.Suggestions) != 1 || got.Suggestions[0].CardName != "Unrated Card" || got.Suggestions[0].Reason != "on color" {
+		t.Fatalf("external suggestions = %+v, want only the in-pack card", got)
+	}
+	if posted.SetCode != "BLB" || len(posted.Pack) != 3 || len(posted.Pool) != 1 || posted.Pool[0].CardName != "Pond Prophet" {
+		t.Fatalf("posted pick = %+v, want the second pack with the first pick as pool", posted)
+	}
+}
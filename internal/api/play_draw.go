@@ -0,0 +1,49 @@
+package api
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// wilsonZ is the normal quantile for a 95% interval.
+const wilsonZ = 1.959964
+
+// handlePlayDrawStats serves GET /api/stats/playdraw: game win rates on the
+// play and on the draw, overall and per deck, each with a 95% confidence
+// interval so a gap over a handful of games is not read as a real one.
+func (s *Server) handlePlayDrawStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	out, err := s.store.PlayDrawRecords(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fillWinRateInterval(&out.Overall.OnPlay)
+	fillWinRateInterval(&out.Overall.OnDraw)
+	for index := range out.Decks {
+		fillWinRateInterval(&out.Decks[index].OnPlay)
+		fillWinRateInterval(&out.Decks[index].OnDraw)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// fillWinRateInterval sets the win rate and its Wilson score interval, which
+// unlike the normal approximation stays inside [0, 1] and behaves at small
+// sample sizes and at 0% or 100%.
+func fillWinRateInterval(rate *model.WinRateInterval) {
+	n := float64(rate.Record.Games)
+	if n == 0 {
+		return
+	}
+	p := float64(rate.Record.Wins) / n
+	z2 := wilsonZ * wilsonZ
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := wilsonZ / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	low, high := math.Max(0, center-margin), math.Min(1, center+margin)
+	rate.WinRate, rate.Low, rate.High = &p, &low, &high
+}
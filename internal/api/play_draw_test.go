@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestPlayDrawStatsWithWilsonIntervals(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	for _, query := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, format, created_at, updated_at) VALUES (1, 'deck-burn', 'Burn', 'Standard', 'x', 'x')`,
+		`INSERT INTO matches (id, arena_match_id, result, created_at, updated_at) VALUES
+			(1, 'm1', 'win', 'x', 'x'), (2, 'm2', 'loss', 'x', 'x'), (3, 'm3', 'win', 'x', 'x')`,
+		`INSERT INTO match_decks (match_id, deck_id, snapshot_reason, created_at) VALUES (1, 1, 'x', 'x'), (2, 1, 'x', 'x')`,
+		`INSERT INTO games (match_id, game_number, result, play_draw, derived_at) VALUES
+			(1, 1, 'win', 'play', 'x'), (1, 2, 'loss', 'draw', 'x'), (1, 3, 'win', 'play', 'x'),
+			(2, 1, 'loss', 'play', 'x'), (2, 2, 'loss', 'draw', 'x'),
+			(3, 1, 'win', 'draw', 'x'), (3, 2, 'win', NULL, 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	handler := NewServer(db.NewStore(database), "", nil).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/playdraw", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var stats model.PlayDrawStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	onPlay, onDraw := stats.Overall.OnPlay, stats.Overall.OnDraw
+	if onPlay.Record.Games != 3 || onPlay.Record.Wins != 2 || onDraw.Record.Games != 3 || onDraw.Record.Wins != 1 || stats.GamesWithoutPlayDraw != 1 {
+		t.Fatalf("overall = %+v, without play/draw %d", stats.Overall, stats.GamesWithoutPlayDraw)
+	}
+	// 2 wins in 3 games: Wilson 95% interval is about [0.208, 0.939].
+	if onPlay.Low == nil || math.Abs(*onPlay.Low-0.208) > 0.001 || math.Abs(*onPlay.High-0.939) > 0.001 {
+		t.Fatalf("on the play interval = %v..%v, want about 0.208..0.939", onPlay.Low, onPlay.High)
+	}
+	if len(stats.Decks) != 1 || stats.Decks[0].DeckName != "Burn" || stats.Decks[0].OnPlay.Record.Wins != 2 || stats.Decks[0].OnDraw.Record.Games != 2 {
+		t.Fatalf("decks = %+v, want Burn 2-1 on the play and 0-2 on the draw", stats.Decks)
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// scryfallMinInterval spaces out Scryfall API calls; Scryfall asks clients
+// to wait 50-100ms between requests and answers bursts with 429s.
+const scryfallMinInterval = 100 * time.Millisecond
+
+// requestThrottle hands out request slots at least interval apart, shared
+// by every caller.
+type requestThrottle struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the caller's slot comes up. A caller that gives up
+// still uses its slot, which only errs on the side of fewer requests.
+func (t *requestThrottle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	now := time.Now()
+	slot := now
+	if t.next.After(now) {
+		slot = t.next
+	}
+	t.next = slot.Add(t.interval)
+	t.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// errLookupsDisabled is returned instead of calling Scryfall when the
+// lookupsEnabled setting is off.
+var errLookupsDisabled = errors.New("card lookups are disabled in settings")
+
+// doScryfall sends req once the Scryfall throttle allows it, unless card
+// lookups are turned off in settings.
+func (s *Server) doScryfall(req *http.Request) (*http.Response, error) {
+	if s.store != nil {
+		settings, err := s.store.GetSettings(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		if !settings.LookupsEnabled {
+			return nil, errLookupsDisabled
+		}
+	}
+	if err := s.scryfallThrottle.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return s.httpClient.Do(req)
+}
+
+type cardNameFetch struct {
+	done chan struct{}
+	name string
+}
+
+// fetchCardNamesFromScryfall looks up names for cardIDs, sharing in-flight
+// lookups with concurrent callers so overlapping API requests (e.g. several
+// pages loading the same deck) ask Scryfall for each card once. Cards
+// another caller is already fetching are waited on rather than re-requested;
+// if that fetch fails they are simply missing from the result.
+func (s *Server) fetchCardNamesFromScryfall(ctx context.Context, cardIDs []int64) (map[int64]string, error) {
+	out := make(map[int64]string, len(cardIDs))
+	if len(cardIDs) == 0 {
+		return out, nil
+	}
+
+	owned := make(map[int64]*cardNameFetch, len(cardIDs))
+	waiting := make(map[int64]*cardNameFetch)
+	toFetch := make([]int64, 0, len(cardIDs))
+	s.nameFetchMu.Lock()
+	if s.nameFetches == nil {
+		s.nameFetches = map[int64]*cardNameFetch{}
+	}
+	for _, cardID := range cardIDs {
+		if _, seen := owned[cardID]; seen {
+			continue
+		}
+		if fetch, inFlight := s.nameFetches[cardID]; inFlight {
+			waiting[cardID] = fetch
+			continue
+		}
+		fetch := &cardNameFetch{done: make(chan struct{})}
+		s.nameFetches[cardID] = fetch
+		owned[cardID] = fetch
+		toFetch = append(toFetch, cardID)
+	}
+	s.nameFetchMu.Unlock()
+
+	fetched, err := s.fetchCardNameBatches(ctx, toFetch)
+	s.nameFetchMu.Lock()
+	for cardID, fetch := range owned {
+		fetch.name = fetched[cardID]
+		delete(s.nameFetches, cardID)
+		close(fetch.done)
+	}
+	s.nameFetchMu.Unlock()
+	for cardID, name := range fetched {
+		out[cardID] = name
+	}
+
+	for cardID, fetch := range waiting {
+		select {
+		case <-fetch.done:
+		case <-ctx.Done():
+			if err == nil {
+				err = ctx.Err()
+			}
+			return out, err
+		}
+		if fetch.name != "" {
+			out[cardID] = fetch.name
+		}
+	}
+	return out, err
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchCardNamesSharesInFlightLookups(t *testing.T) {
+	queries := make(chan string, 4)
+	release := make(chan struct{})
+	server := NewServer(nil, "", nil)
+	server.scryfallThrottle = &requestThrottle{}
+	server.httpClient = &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query().Get("q")
+			queries <- query
+			<-release
+			var cards []string
+			for _, term := range strings.Split(query, " or ") {
+				var cardID int64
+				fmt.Sscanf(term, "arenaid:%d", &cardID)
+				cards = append(cards, fmt.Sprintf(`{"arena_id":%d,"name":"Card %d"}`, cardID, cardID))
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(`{"data":[` + strings.Join(cards, ",") + `]}`)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	ctx := context.Background()
+	first := make(chan map[int64]string, 1)
+	go func() {
+		names, _ := server.fetchCardNamesFromScryfall(ctx, []int64{1, 2})
+		first <- names
+	}()
+	if got := <-queries; got != "arenaid:1 or arenaid:2" {
+		t.Fatalf("first query = %q", got)
+	}
+
+	second := make(chan map[int64]string, 1)
+	go func() {
+		names, _ := server.fetchCardNamesFromScryfall(ctx, []int64{2, 3})
+		second <- names
+	}()
+	// Card 2 is already in flight, so only card 3 is requested again.
+	if got := <-queries; got != "arenaid:3" {
+		t.Fatalf("second query = %q, want only the card not already in flight", got)
+	}
+	close(release)
+
+	if names := <-first; names[1] != "Card 1" || names[2] != "Card 2" {
+		t.Fatalf("first names = %v", names)
+	}
+	if names := <-second; names[2] != "Card 2" || names[3] != "Card 3" {
+		t.Fatalf("second names = %v", names)
+	}
+}
+
+func TestRequestThrottleSpacesRequests(t *testing.T) {
+	throttle := &requestThrottle{interval: 20 * time.Millisecond}
+	started := time.Now()
+	for range 3 {
+		if err := throttle.wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(started); elapsed < 40*time.Millisecond {
+		t.Fatalf("three requests took %v, want at least two intervals", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	throttle.interval = time.Hour
+	_ = throttle.wait(context.Background())
+	if err := throttle.wait(ctx); err == nil {
+		t.Fatal("wait with a canceled context succeeded, want error")
+	}
+}
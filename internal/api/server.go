@@ -10,6 +10,7 @@ import (
 	"io"
 	"io/fs"
 	"log"
+	"maps"
 	"net"
 	"net/http"
 	"net/url"
@@ -23,9 +24,11 @@ import (
 
 	"github.com/solean/ponder/internal/ai"
 	"github.com/solean/ponder/internal/appstate"
-	"github.com/solean/ponder/internal/db"
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/internal/decksync"
+	"github.com/solean/ponder/internal/metrics"
 	"github.com/solean/ponder/internal/version"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
 )
 
 type Server struct {
@@ -37,6 +40,30 @@ type Server struct {
 	httpClient   *http.Client
 	aiProvider   *ai.CLIProvider
 	aiGenBusy    sync.Mutex
+
+	// allowedOrigins are extra browser origins (beyond local dev servers)
+	// granted CORS access; their hostnames also pass the Host check.
+	allowedOrigins map[string]bool
+	apiKey         string
+	basePath       string
+	accessLog      bool
+	tlsCertFile    string
+	tlsKeyFile     string
+
+	imageCacheDir string
+	imageFetchMu  sync.Mutex
+	imageFetches  map[string]*cardImageFetch
+
+	scryfallThrottle *requestThrottle
+	nameFetchMu      sync.Mutex
+	nameFetches      map[int64]*cardNameFetch
+	cardNames        *cardNameCache
+	mtgaRaw          mtgaRawDB
+
+	// deckServices overrides decksync.Services, for tests.
+	deckServices []decksync.Service
+	// pickAdvisor ranks draft packs; nil means a RatingsAdvisor.
+	pickAdvisor PickAdvisor
 }
 
 func NewServer(store *db.Store, staticDir string, appState *appstate.Service) *Server {
@@ -47,7 +74,9 @@ func NewServer(store *db.Store, staticDir string, appState *appstate.Service) *S
 		httpClient: &http.Client{
 			Timeout: 8 * time.Second,
 		},
-		aiProvider: &ai.CLIProvider{},
+		aiProvider:       &ai.CLIProvider{},
+		scryfallThrottle: &requestThrottle{interval: scryfallMinInterval},
+		cardNames:        newCardNameCache(cardNameCacheSize),
 	}
 }
 
@@ -58,20 +87,49 @@ func (s *Server) routes() http.Handler {
 	mux.HandleFunc("/api/", func(w http.ResponseWriter, _ *http.Request) {
 		writeError(w, http.StatusNotFound, "not found")
 	})
+	mux.Handle("/metrics", metrics.Handler())
 	mux.HandleFunc("/api/health", s.handleHealth)
 	mux.HandleFunc("/api/overview", s.handleOverview)
 	mux.HandleFunc("/api/rank-history", s.handleRankHistory)
+	mux.HandleFunc("/api/rank/history", s.handleRankHistorySeries)
+	mux.HandleFunc("/api/queue-times", s.handleQueueTimes)
+	mux.HandleFunc("/api/sessions", s.handleSessions)
 	mux.HandleFunc("/api/economy", s.handleEconomy)
+	mux.HandleFunc("/api/collection/insights", s.handleCollectionInsights)
+	mux.HandleFunc("/api/collection/value", s.handleCollectionValue)
+	mux.HandleFunc("/api/cards", s.handleCards)
+	mux.HandleFunc("/api/cards/", s.handleCardImage)
 	mux.HandleFunc("/api/matches", s.handleMatches)
 	mux.HandleFunc("/api/matches/", s.handleMatchDetail)
 	mux.HandleFunc("/api/limited/matchups", s.handleLimitedMatchups)
+	mux.HandleFunc("/api/stats/limited", s.handleLimitedStats)
+	mux.HandleFunc("/api/stats/limited/colors", s.handleLimitedColors)
+	mux.HandleFunc("/api/stats/playdraw", s.handlePlayDrawStats)
+	mux.HandleFunc("/api/stats/milestones", s.handleMilestones)
+	mux.HandleFunc("/api/stats/economy", s.handleEconomyStats)
+	mux.HandleFunc("/api/stats/time-of-day", s.handleTimeOfDayStats)
+	mux.HandleFunc("/api/stats/game-length", s.handleGameLengthStats)
+	mux.HandleFunc("/api/stats/meta", s.handleMeta)
+	mux.HandleFunc("/api/stats/drafts", s.handleDraftStats)
+	mux.HandleFunc("/api/stats/mana", s.handleManaStats)
+	mux.HandleFunc("/api/stats/bo3", s.handleBo3Stats)
+	mux.HandleFunc("/api/stats/companions", s.handleCompanionStats)
+	mux.HandleFunc("/api/stats/trophies", s.handleTrophies)
+	mux.HandleFunc("/api/stats/opponent-ranks", s.handleOpponentRankStats)
 	mux.HandleFunc("/api/decks", s.handleDecks)
+	mux.HandleFunc("/api/decks/merge", s.handleDeckMerge)
 	mux.HandleFunc("/api/decks/", s.handleDeckDetail)
 	mux.HandleFunc("/api/drafts", s.handleDrafts)
 	mux.HandleFunc("/api/drafts/", s.handleDraftPicks)
 	mux.HandleFunc("/api/sets", s.handleSets)
+	mux.HandleFunc("/api/settings", s.handleSettings)
+	mux.HandleFunc("/api/export/", s.handleExport)
 	mux.HandleFunc("/api/ai/status", s.handleAIStatus)
 	mux.HandleFunc("/api/live", s.handleLive)
+	mux.HandleFunc("/api/graphql", s.handleGraphQL)
+	mux.HandleFunc("/api/openapi.json", s.handleOpenAPI)
+	mux.HandleFunc("/overlay/", s.handleOverlay)
+	mux.HandleFunc("/calendar.ics", s.handleCalendar)
 	if s.appState != nil {
 		mux.HandleFunc("/api/runtime/status", s.handleRuntimeStatus)
 		mux.HandleFunc("/api/runtime/config", s.handleRuntimeConfig)
@@ -82,6 +140,7 @@ func (s *Server) routes() http.Handler {
 		mux.HandleFunc("/api/runtime/update-check", s.handleRuntimeUpdateCheck)
 		mux.HandleFunc("/api/runtime/pick-log", s.handleRuntimePickLog)
 		mux.HandleFunc("/api/runtime/reveal", s.handleRuntimeReveal)
+		mux.HandleFunc("/api/admin/backup", s.handleAdminBackup)
 	}
 
 	staticAssets := s.staticAssets
@@ -91,7 +150,7 @@ func (s *Server) routes() http.Handler {
 		}
 	}
 	if staticAssets != nil {
-		mux.Handle("/", spaFileServer(staticAssets))
+		mux.Handle("/", spaFileServer(staticAssets, s.basePath))
 	} else if s.staticDir != "" {
 		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
@@ -99,7 +158,7 @@ func (s *Server) routes() http.Handler {
 		})
 	}
 
-	return withCORS(withGzip(mux))
+	return s.withAccessLog(s.withBasePath(s.withCORS(s.withAPIKey(withMetrics(mux, withGzip(withRecovery(withETag(mux))))))))
 }
 
 // SetStaticAssets serves the frontend from the given filesystem (typically
@@ -108,19 +167,48 @@ func (s *Server) SetStaticAssets(assets fs.FS) {
 	s.staticAssets = assets
 }
 
+// hashedAssetPrefix is where Vite emits content-hashed bundles; a file there
+// never changes under the same name, so browsers may cache it forever.
+const hashedAssetPrefix = "assets/"
+
 // spaFileServer serves the built frontend. The React app uses client-side
 // routing (BrowserRouter), so paths that don't match a real file — deep links
-// like /matches/675 — fall back to index.html.
-func spaFileServer(assets fs.FS) http.Handler {
+// like /matches/675 — fall back to index.html. Hashed assets are marked
+// immutable; everything else (index.html above all) must be revalidated so a
+// new build is picked up on reload. Under a base path, index.html is
+// rewritten so its relative URLs resolve beneath it.
+func spaFileServer(assets fs.FS, basePath string) http.Handler {
 	fileServer := http.FileServer(http.FS(assets))
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
 		if name != "" && name != "." {
 			if f, err := assets.Open(name); err == nil {
 				_ = f.Close()
+				if strings.HasPrefix(name, hashedAssetPrefix) {
+					w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+				} else {
+					w.Header().Set("Cache-Control", "no-cache")
+				}
 				fileServer.ServeHTTP(w, r)
 				return
 			}
+			// A missing hashed bundle is a stale reference from an old build;
+			// answering with index.html would poison it as JavaScript.
+			if strings.HasPrefix(name, hashedAssetPrefix) {
+				http.NotFound(w, r)
+				return
+			}
+		}
+		w.Header().Set("Cache-Control", "no-cache")
+		if basePath != "" {
+			page, err := fs.ReadFile(assets, "index.html")
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write(rebaseIndexHTML(page, basePath))
+			return
 		}
 		r.URL.Path = "/"
 		fileServer.ServeHTTP(w, r)
@@ -135,13 +223,41 @@ func (s *Server) Handler() http.Handler {
 
 type gzipResponseWriter struct {
 	http.ResponseWriter
-	gz *gzip.Writer
+	gz          *gzip.Writer
+	passthrough bool
+}
+
+// WriteHeader sends bodiless statuses (304 revalidations, 204s) uncompressed;
+// an empty gzip stream on a 304 would be a malformed response. Images pass
+// through too: they are already compressed, and http.ServeContent has set a
+// Content-Length for the raw bytes.
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if status == http.StatusNotModified || status == http.StatusNoContent ||
+		strings.HasPrefix(g.Header().Get("Content-Type"), "image/") {
+		g.Header().Del("Content-Encoding")
+		g.passthrough = true
+	}
+	g.ResponseWriter.WriteHeader(status)
 }
 
 func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if g.passthrough {
+		return g.ResponseWriter.Write(b)
+	}
 	return g.gz.Write(b)
 }
 
+// Flush pushes buffered compressed bytes through so streaming exports reach
+// the client incrementally instead of after the handler returns.
+func (g *gzipResponseWriter) Flush() {
+	if !g.passthrough {
+		_ = g.gz.Flush()
+	}
+	if flusher, ok := g.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 // withGzip compresses API responses. Replay payloads in particular are large
 // and highly repetitive, so this is a big win for the desktop webview.
 // Static assets are left alone because http.FileServer sets Content-Length.
@@ -158,22 +274,44 @@ func withGzip(next http.Handler) http.Handler {
 		w.Header().Set("Content-Encoding", "gzip")
 		w.Header().Add("Vary", "Accept-Encoding")
 		gz := gzip.NewWriter(w)
-		defer gz.Close()
-		next.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, gz: gz}, r)
+		gw := &gzipResponseWriter{ResponseWriter: w, gz: gz}
+		defer func() {
+			if !gw.passthrough {
+				_ = gz.Close()
+			}
+		}()
+		next.ServeHTTP(gw, r)
 	})
 }
 
+// Run serves on addr, a TCP address or "unix:<path>" for a Unix domain
+// socket, until ctx is done.
 func (s *Server) Run(ctx context.Context, addr string) error {
+	// A browser can't reach a Unix socket directly, so DNS rebinding isn't
+	// a concern there, and the fronting proxy forwards its own public Host.
+	handler := s.routes()
+	if _, isUnix := UnixSocketPath(addr); !isUnix {
+		handler = s.withHostCheck(handler)
+	}
 	httpServer := &http.Server{
-		Addr:              addr,
-		Handler:           withHostCheck(s.routes()),
+		Handler:           handler,
 		ReadHeaderTimeout: 10 * time.Second,
 	}
+	listener, err := listen(addr)
+	if err != nil {
+		return err
+	}
 
 	errCh := make(chan error, 1)
 	go func() {
-		log.Printf("HTTP server listening on %s", addr)
-		err := httpServer.ListenAndServe()
+		var err error
+		if s.tlsCertFile != "" || s.tlsKeyFile != "" {
+			log.Printf("HTTPS server listening on %s", addr)
+			err = httpServer.ServeTLS(listener, s.tlsCertFile, s.tlsKeyFile)
+		} else {
+			log.Printf("HTTP server listening on %s", addr)
+			err = httpServer.Serve(listener)
+		}
 		if err != nil && err != http.ErrServerClosed {
 			errCh <- err
 			return
@@ -186,12 +324,21 @@ func (s *Server) Run(ctx context.Context, addr string) error {
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		_ = httpServer.Shutdown(shutdownCtx)
+		s.Close()
 		return nil
 	case err := <-errCh:
+		s.Close()
 		return err
 	}
 }
 
+// Close releases what the server holds open between requests, the handle on
+// Arena's raw card database. Run calls it once the listener has shut down;
+// callers that only mount Handler call it when they stop.
+func (s *Server) Close() {
+	s.mtgaRaw.close()
+}
+
 // isLocalDevOrigin reports whether a browser Origin belongs to a local dev
 // server (e.g. Vite on http://localhost:5173). Cross-origin access is only
 // granted to those; arbitrary websites must not be able to read the API.
@@ -204,13 +351,40 @@ func isLocalDevOrigin(origin string) bool {
 	return host == "localhost" || host == "127.0.0.1" || host == "::1"
 }
 
-func withCORS(next http.Handler) http.Handler {
+// normalizeOrigin reduces an origin to lowercase scheme://host[:port], or ""
+// when it isn't an http(s) origin.
+func normalizeOrigin(origin string) string {
+	parsed, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ""
+	}
+	return strings.ToLower(parsed.Scheme + "://" + parsed.Host)
+}
+
+// SetAllowedOrigins grants CORS access to additional browser origins, e.g. a
+// frontend hosted elsewhere on the LAN. Local dev origins are always allowed;
+// invalid entries are ignored.
+func (s *Server) SetAllowedOrigins(origins []string) {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		if normalized := normalizeOrigin(origin); normalized != "" {
+			allowed[normalized] = true
+		}
+	}
+	s.allowedOrigins = allowed
+}
+
+func (s *Server) isAllowedOrigin(origin string) bool {
+	return isLocalDevOrigin(origin) || s.allowedOrigins[normalizeOrigin(origin)]
+}
+
+func (s *Server) withCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if origin := r.Header.Get("Origin"); origin != "" && isLocalDevOrigin(origin) {
+		if origin := r.Header.Get("Origin"); origin != "" && s.isAllowedOrigin(origin) {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Add("Vary", "Origin")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS, POST")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS, POST, PUT, DELETE")
 		}
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
@@ -222,15 +396,16 @@ func withCORS(next http.Handler) http.Handler {
 
 // withHostCheck rejects requests whose Host header is a non-local hostname.
 // DNS rebinding attacks reach a localhost server through a hostname the
-// attacker controls; direct IP and localhost access are unaffected.
-func withHostCheck(next http.Handler) http.Handler {
+// attacker controls; direct IP and localhost access are unaffected, and
+// hostnames of explicitly allowed origins are trusted too.
+func (s *Server) withHostCheck(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		host := r.Host
 		if split, _, err := net.SplitHostPort(host); err == nil {
 			host = split
 		}
 		host = strings.ToLower(strings.Trim(host, "[]"))
-		if host == "localhost" || host == "wails.localhost" || host == "wails" || net.ParseIP(host) != nil {
+		if host == "localhost" || host == "wails.localhost" || host == "wails" || net.ParseIP(host) != nil || s.isAllowedHost(host) {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -238,6 +413,29 @@ func withHostCheck(next http.Handler) http.Handler {
 	})
 }
 
+func (s *Server) isAllowedHost(host string) bool {
+	for origin := range s.allowedOrigins {
+		if parsed, err := url.Parse(origin); err == nil && parsed.Hostname() == host {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLoopbackAddr reports whether a listen address only accepts local
+// connections. An empty host (":8080") binds every interface.
+func IsLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -272,8 +470,41 @@ func decodeJSONBody(r *http.Request, dst any) error {
 	return nil
 }
 
-func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
-	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	health := model.HealthStatus{Status: "ok", Database: true, Logs: []model.IngestLogStatus{}}
+	if s.store == nil {
+		writeJSON(w, http.StatusOK, health)
+		return
+	}
+
+	ctx := r.Context()
+	if err := s.store.Ping(ctx); err != nil {
+		health.Status = "unavailable"
+		health.Database = false
+		health.DatabaseError = err.Error()
+		writeJSON(w, http.StatusServiceUnavailable, health)
+		return
+	}
+	if version, err := s.store.SchemaVersion(ctx); err == nil {
+		health.SchemaVersion = version
+	}
+	if version, err := s.store.MigrationVersion(ctx); err == nil {
+		health.MigrationVersion = version
+	}
+	if logs, err := s.store.IngestLogStates(ctx); err == nil {
+		for i := range logs {
+			logs[i].FileSize = -1
+			if info, statErr := os.Stat(logs[i].Path); statErr == nil {
+				logs[i].FileSize = info.Size()
+				logs[i].BytesBehind = max(info.Size()-logs[i].Offset, 0)
+			}
+		}
+		health.Logs = logs
+		if len(logs) > 0 {
+			health.LastIngestAt = logs[0].UpdatedAt
+		}
+	}
+	writeJSON(w, http.StatusOK, health)
 }
 
 func (s *Server) handleRuntimeStatus(w http.ResponseWriter, r *http.Request) {
@@ -312,6 +543,25 @@ func (s *Server) handleRuntimeConfig(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, status)
 }
 
+// handleAdminBackup serves POST /api/admin/backup: an on-demand copy into the
+// nightly backup directory, e.g. before upgrading.
+func (s *Server) handleAdminBackup(w http.ResponseWriter, r *http.Request) {
+	if s.appState == nil {
+		writeError(w, http.StatusNotFound, "runtime controls unavailable")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	result, err := s.appState.RunBackup(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
 func (s *Server) handleRuntimeImport(w http.ResponseWriter, r *http.Request) {
 	if s.appState == nil {
 		writeError(w, http.StatusNotFound, "runtime controls unavailable")
@@ -530,14 +780,52 @@ func currentBaseVersion(v string) string {
 	return v
 }
 
+// parseOverviewScope validates the overview's from/to days (YYYY-MM-DD or
+// RFC 3339, taken as UTC days), format, and queue.
+func parseOverviewScope(from, to, format, queue string) (db.OverviewScope, error) {
+	scope := db.OverviewScope{Format: strings.TrimSpace(format), Queue: strings.TrimSpace(queue)}
+	for _, bound := range []struct {
+		name, raw string
+		day       *string
+	}{{"from", from, &scope.From}, {"to", to, &scope.To}} {
+		raw := strings.TrimSpace(bound.raw)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			parsed, err = time.Parse(time.RFC3339, raw)
+		}
+		if err != nil {
+			return scope, fmt.Errorf("invalid %s (use YYYY-MM-DD or RFC 3339)", bound.name)
+		}
+		*bound.day = parsed.UTC().Format("2006-01-02")
+	}
+	if scope.From != "" && scope.To != "" && scope.To < scope.From {
+		return scope, errors.New("to is before from")
+	}
+	return scope, nil
+}
+
 func (s *Server) handleOverview(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
 	limit := int64(20)
-	if raw := strings.TrimSpace(r.URL.Query().Get("recent")); raw != "" {
+	if raw := strings.TrimSpace(query.Get("recent")); raw != "" {
 		if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
 			limit = v
 		}
 	}
-	out, err := s.store.Overview(r.Context(), limit)
+	format, err := s.requestFormat(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	scope, err := parseOverviewScope(query.Get("from"), query.Get("to"), format, query.Get("queue"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	out, err := s.store.Overview(r.Context(), limit, scope)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -552,9 +840,33 @@ func (s *Server) handleRankHistory(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	s.enrichEventDisplayNames(r, eventTargets(rows, func(row *model.RankHistoryPoint) (string, *string) { return row.EventName, &row.EventDisplayName }))
 	writeJSON(w, http.StatusOK, rows)
 }
 
+func (s *Server) handleRankHistorySeries(w http.ResponseWriter, r *http.Request) {
+	out, err := s.store.RankHistorySeries(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var targets []eventNameTarget
+	for index := range out.Series {
+		targets = append(targets, eventTargets(out.Series[index].Points, func(row *model.RankSeriesPoint) (string, *string) { return row.EventName, &row.EventDisplayName })...)
+	}
+	s.enrichEventDisplayNames(r, targets)
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleQueueTimes(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.store.QueueTimeStats(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
 func (s *Server) handleEconomy(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -594,13 +906,25 @@ func (s *Server) handleMatches(w http.ResponseWriter, r *http.Request) {
 	}
 	event := strings.TrimSpace(r.URL.Query().Get("event"))
 	result := strings.TrimSpace(r.URL.Query().Get("result"))
+	includeArchived := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("includeArchived")))
+	format, err := s.requestFormat(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 
-	rows, err := s.store.ListMatches(r.Context(), limit, event, result)
+	rows, err := s.store.ListMatchesFiltered(r.Context(), limit, db.MatchFilter{
+		EventName:       event,
+		Result:          result,
+		IncludeArchived: includeArchived == "1" || includeArchived == "true",
+		Format:          format,
+	})
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	s.enrichMatchDeckColors(r.Context(), rows)
+	s.enrichEventDisplayNames(r, matchEventTargets(rows))
 	writeJSON(w, http.StatusOK, rows)
 }
 
@@ -630,14 +954,25 @@ func (s *Server) handleMatchDetail(w http.ResponseWriter, r *http.Request) {
 		case "opponent-archetype":
 			s.handleMatchOpponentArchetype(w, r, id)
 			return
+		case "opponent-decklist":
+			text, err := s.OpponentDeckImportText(r.Context(), id)
+			writeDecklist(w, text, err, "no opponent cards recorded for match")
+			return
+		case "archive", "unarchive":
+			s.handleArchiveMatch(w, r, id, parts[1] == "archive")
+			return
 		case "timeline":
-			rows, err := s.store.ListMatchCardPlays(r.Context(), id)
+			timeline, err := s.store.MatchTimeline(r.Context(), id)
+			if errors.Is(err, sql.ErrNoRows) {
+				writeError(w, http.StatusNotFound, "match not found")
+				return
+			}
 			if err != nil {
 				writeError(w, http.StatusInternalServerError, err.Error())
 				return
 			}
-			s.enrichMatchCardPlayNames(r.Context(), rows)
-			writeJSON(w, http.StatusOK, rows)
+			s.enrichMatchTimelineNames(r.Context(), &timeline)
+			writeJSON(w, http.StatusOK, timeline)
 			return
 		case "replay":
 			frames, err := s.store.ListMatchReplayFrames(r.Context(), id)
@@ -653,7 +988,26 @@ func (s *Server) handleMatchDetail(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if r.Method == http.MethodDelete {
+		s.handleDeleteMatch(w, r, id)
+		return
+	}
+
+	out, err := s.loadMatchDetail(r, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "match not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
 
+// loadMatchDetail derives any missing match analytics and returns the
+// detail with card names, deck colors, and event display names filled in.
+func (s *Server) loadMatchDetail(r *http.Request, id int64) (model.MatchDetail, error) {
 	// Turn-stat land classification depends on cached type lines; resolving
 	// them first (with a freshness check on card_types in EnsureMatchAnalytics)
 	// lets the first derivation already judge lands in hand.
@@ -661,17 +1015,11 @@ func (s *Server) handleMatchDetail(w http.ResponseWriter, r *http.Request) {
 		s.ensureCardTypeLines(r.Context(), cardIDs)
 	}
 	if err := s.store.EnsureMatchAnalytics(r.Context(), id); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
-		return
+		return model.MatchDetail{}, err
 	}
 	out, err := s.store.GetMatchDetail(r.Context(), id)
-	if errors.Is(err, sql.ErrNoRows) {
-		writeError(w, http.StatusNotFound, "match not found")
-		return
-	}
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
-		return
+		return out, err
 	}
 
 	s.enrichOpponentObservedCardNames(r.Context(), out.OpponentObservedCards)
@@ -679,8 +1027,9 @@ func (s *Server) handleMatchDetail(w http.ResponseWriter, r *http.Request) {
 	s.enrichOpeningHandCardNames(r.Context(), out.Games)
 	matchRows := []model.MatchRow{out.Match}
 	s.enrichMatchDeckColors(r.Context(), matchRows)
+	s.enrichEventDisplayNames(r, matchEventTargets(matchRows))
 	out.Match = matchRows[0]
-	writeJSON(w, http.StatusOK, out)
+	return out, nil
 }
 
 func (s *Server) handleDecks(w http.ResponseWriter, r *http.Request) {
@@ -701,6 +1050,7 @@ func (s *Server) handleDecks(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	s.enrichEventDisplayNames(r, eventTargets(rows, func(row *model.DeckSummaryRow) (string, *string) { return row.EventName, &row.EventDisplayName }))
 	writeJSON(w, http.StatusOK, rows)
 }
 
@@ -732,26 +1082,62 @@ func (s *Server) handleDeckDetail(w http.ResponseWriter, r *http.Request) {
 		s.handleDeckMatchups(w, r, id)
 		return
 	}
+	if len(parts) == 2 && parts[1] == "decklist" {
+		text, err := s.DeckImportText(r.Context(), id)
+		writeDecklist(w, text, err, "deck not found")
+		return
+	}
+	if len(parts) == 2 && parts[1] == "push" {
+		s.handleDeckPush(w, r, id)
+		return
+	}
 	if len(parts) == 3 && parts[1] == "analytics" && parts[2] == "games" {
 		s.handleDeckAnalyticsGames(w, r, id)
 		return
 	}
+	if len(parts) == 3 && parts[1] == "analytics" && parts[2] == "cards" {
+		s.handleDeckCardDrawImpact(w, r, id)
+		return
+	}
+	if len(parts) == 3 && parts[1] == "analytics" && parts[2] == "curve" {
+		s.handleDeckCastCurve(w, r, id)
+		return
+	}
 	if len(parts) != 1 {
 		writeError(w, http.StatusNotFound, "not found")
 		return
 	}
+	if r.Method == http.MethodDelete {
+		s.handleDeleteDeck(w, r, id)
+		return
+	}
 
-	out, err := s.store.GetDeckDetail(r.Context(), id, 50)
+	out, err := s.loadDeckDetail(r, id)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// loadDeckDetail returns a deck with its 50 most recent matches, card
+// names, main-deck composition, paper value, deck colors, and event display
+// names filled in.
+func (s *Server) loadDeckDetail(r *http.Request, id int64) (model.DeckDetail, error) {
+	out, err := s.store.GetDeckDetail(r.Context(), id, 50)
+	if err != nil {
+		return out, err
+	}
 	s.enrichDeckCardNames(r.Context(), out.Cards)
+	out.Composition = s.resolveDeckComposition(r.Context(), out.Cards)
+	out.PaperValue = s.resolveDeckPaperValue(r.Context(), out.Cards)
 	for index := range out.Versions {
 		s.enrichDeckCardNames(r.Context(), out.Versions[index].Cards)
 	}
 	s.enrichMatchDeckColors(r.Context(), out.Matches)
-	writeJSON(w, http.StatusOK, out)
+	targets := append(matchEventTargets(out.Matches), eventNameTarget{raw: out.EventName, display: &out.EventDisplayName})
+	s.enrichEventDisplayNames(r, targets)
+	return out, nil
 }
 
 func (s *Server) handleDrafts(w http.ResponseWriter, r *http.Request) {
@@ -764,6 +1150,7 @@ func (s *Server) handleDrafts(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	s.enrichEventDisplayNames(r, eventTargets(rows, func(row *model.DraftSessionRow) (string, *string) { return row.EventName, &row.EventDisplayName }))
 	writeJSON(w, http.StatusOK, rows)
 }
 
@@ -774,7 +1161,7 @@ func (s *Server) handleDraftPicks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	parts := strings.Split(strings.TrimPrefix(r.URL.Path, prefix), "/")
-	if len(parts) != 2 || parts[1] != "picks" {
+	if len(parts) != 2 || (parts[1] != "picks" && parts[1] != "suggestions") {
 		writeError(w, http.StatusNotFound, "not found")
 		return
 	}
@@ -783,12 +1170,19 @@ func (s *Server) handleDraftPicks(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid draft id")
 		return
 	}
+	if parts[1] == "suggestions" {
+		s.handleDraftSuggestions(w, r, id)
+		return
+	}
 	rows, err := s.store.ListDraftPicks(r.Context(), id)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	s.enrichDraftPickCardNames(r.Context(), rows)
+	if eventName, err := s.store.DraftSessionEventName(r.Context(), id); err == nil {
+		s.gradeDraftPicks(r.Context(), eventName, rows)
+	}
 	writeJSON(w, http.StatusOK, rows)
 }
 
@@ -809,7 +1203,6 @@ func ParseAddr(raw string) (string, error) {
 const (
 	scryfallSearchURL      = "https://api.scryfall.com/cards/search"
 	scryfallSearchBatchMax = 40
-	mtgaRawCardDBEnvVar    = "MTGA_RAW_CARD_DB"
 )
 
 func parseDraftCardIDs(raw string) []int64 {
@@ -864,12 +1257,24 @@ func uniqueCardIDs(cardIDs []int64) []int64 {
 	return out
 }
 
+// resolveCardNames names cardIDs from the in-memory cache, falling back to
+// lookupCardNames for the rest.
 func (s *Server) resolveCardNames(ctx context.Context, cardIDs []int64) map[int64]string {
 	cardIDs = uniqueCardIDs(cardIDs)
-	if len(cardIDs) == 0 {
-		return map[int64]string{}
-	}
+	names := make(map[int64]string, len(cardIDs))
+	missing := s.cardNames.lookup(cardIDs, names)
+	if len(missing) == 0 {
+		return names
+	}
+	looked := s.lookupCardNames(ctx, missing)
+	s.cardNames.add(looked)
+	maps.Copy(names, looked)
+	return names
+}
 
+// lookupCardNames names cardIDs from the store's card cache, then the local
+// MTGA card database, then Scryfall, saving what the last two find.
+func (s *Server) lookupCardNames(ctx context.Context, cardIDs []int64) map[int64]string {
 	resolvedNames, err := s.store.LookupCardNames(ctx, cardIDs)
 	if err != nil {
 		log.Printf("card name lookup failed: %v", err)
@@ -1215,6 +1620,47 @@ func (s *Server) enrichMatchCardPlayNames(ctx context.Context, plays []model.Mat
 	}
 }
 
+// enrichMatchTimelineNames fills card names the catalog did not have for
+// plays and annotation card references, resolving them in one pass.
+func (s *Server) enrichMatchTimelineNames(ctx context.Context, timeline *model.MatchTimeline) {
+	var (
+		names   []*string
+		cardIDs []int64
+	)
+	want := func(cardID int64, name *string) {
+		if strings.TrimSpace(*name) == "" {
+			names = append(names, name)
+			cardIDs = append(cardIDs, cardID)
+		}
+	}
+	for gameIndex := range timeline.Games {
+		turns := timeline.Games[gameIndex].Turns
+		for turnIndex := range turns {
+			plays := turns[turnIndex].Plays
+			for playIndex := range plays {
+				want(plays[playIndex].CardID, &plays[playIndex].CardName)
+			}
+			annotations := turns[turnIndex].Annotations
+			for annotationIndex := range annotations {
+				annotation := &annotations[annotationIndex]
+				if annotation.Source != nil {
+					want(annotation.Source.CardID, &annotation.Source.CardName)
+				}
+				for targetIndex := range annotation.Targets {
+					want(annotation.Targets[targetIndex].CardID, &annotation.Targets[targetIndex].CardName)
+				}
+			}
+		}
+	}
+	if len(cardIDs) == 0 {
+		return
+	}
+	resolved := s.resolveCardNames(ctx, cardIDs)
+	for index, name := range names {
+		*name = resolved[cardIDs[index]]
+	}
+}
+
 func (s *Server) enrichMatchReplayNames(ctx context.Context, frames []model.MatchReplayFrameRow) {
 	if len(frames) == 0 {
 		return
@@ -1327,21 +1773,13 @@ func (s *Server) fetchCardNamesFromMTGARaw(ctx context.Context, cardIDs []int64)
 		return out, nil
 	}
 
-	rawDBPath := discoverMTGARawCardDBPath()
-	if strings.TrimSpace(rawDBPath) == "" {
-		return out, nil
-	}
-
-	rawDB, err := sql.Open("sqlite", rawDBPath)
+	rawDB, release, err := s.mtgaRaw.acquire(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("open MTGA raw card db %q: %w", rawDBPath, err)
+		return nil, err
 	}
-	defer rawDB.Close()
-	rawDB.SetMaxOpenConns(1)
-	rawDB.SetMaxIdleConns(1)
-
-	if err := rawDB.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("ping MTGA raw card db %q: %w", rawDBPath, err)
+	defer release()
+	if rawDB == nil {
+		return out, nil
 	}
 
 	placeholders := make([]string, 0, len(cardIDs))
@@ -1391,47 +1829,7 @@ func (s *Server) fetchCardNamesFromMTGARaw(ctx context.Context, cardIDs []int64)
 	return out, nil
 }
 
-func discoverMTGARawCardDBPath() string {
-	explicit := strings.TrimSpace(os.Getenv(mtgaRawCardDBEnvVar))
-	if explicit != "" {
-		if fi, err := os.Stat(explicit); err == nil && !fi.IsDir() {
-			return explicit
-		}
-	}
-
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return ""
-	}
-
-	patterns := []string{
-		filepath.Join(home, "Library", "Application Support", "com.wizards.mtga", "Downloads", "Raw", "Raw_CardDatabase*.mtga"),
-		filepath.Join(home, "AppData", "LocalLow", "Wizards Of The Coast", "MTGA", "Downloads", "Raw", "Raw_CardDatabase*.mtga"),
-	}
-
-	var newestPath string
-	var newestMod time.Time
-	for _, pattern := range patterns {
-		matches, err := filepath.Glob(pattern)
-		if err != nil {
-			continue
-		}
-		for _, match := range matches {
-			fi, err := os.Stat(match)
-			if err != nil || fi.IsDir() {
-				continue
-			}
-			if newestPath == "" || fi.ModTime().After(newestMod) {
-				newestPath = match
-				newestMod = fi.ModTime()
-			}
-		}
-	}
-
-	return newestPath
-}
-
-func (s *Server) fetchCardNamesFromScryfall(ctx context.Context, cardIDs []int64) (map[int64]string, error) {
+func (s *Server) fetchCardNameBatches(ctx context.Context, cardIDs []int64) (map[int64]string, error) {
 	out := make(map[int64]string, len(cardIDs))
 	if len(cardIDs) == 0 {
 		return out, nil
@@ -1483,7 +1881,7 @@ func (s *Server) fetchCardNameBatch(ctx context.Context, cardIDs []int64) (map[i
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "ponder/0.1 (local tracker)")
 
-	res, err := s.httpClient.Do(req)
+	res, err := s.doScryfall(req)
 	if err != nil {
 		return nil, fmt.Errorf("request scryfall: %w", err)
 	}
@@ -1522,7 +1920,7 @@ func (s *Server) fetchCardNameBatch(ctx context.Context, cardIDs []int64) (map[i
 		nextReq.Header.Set("Accept", "application/json")
 		nextReq.Header.Set("User-Agent", "ponder/0.1 (local tracker)")
 
-		nextRes, err := s.httpClient.Do(nextReq)
+		nextRes, err := s.doScryfall(nextReq)
 		if err != nil {
 			return names, fmt.Errorf("request scryfall next page: %w", err)
 		}
@@ -3,11 +3,15 @@ package api
 import (
 	"context"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"testing/fstest"
+	"time"
 )
 
 func TestSPAFallback(t *testing.T) {
@@ -31,6 +35,7 @@ func TestSPAFallback(t *testing.T) {
 		{"nested deep link falls back", "/drafts/12/picks", 200, "<html>app</html>"},
 		{"real asset served as-is", "/assets/index-ab.js", 200, "console.log('js')"},
 		{"unknown api path stays 404", "/api/nope", 404, ""},
+		{"missing hashed asset stays 404", "/assets/index-old.js", 404, ""},
 	}
 
 	for _, tc := range cases {
@@ -52,6 +57,55 @@ func TestSPAFallback(t *testing.T) {
 	}
 }
 
+func TestSPACacheHeaders(t *testing.T) {
+	assets := fstest.MapFS{
+		"index.html":         {Data: []byte("<html>app</html>")},
+		"favicon.svg":        {Data: []byte("<svg/>")},
+		"assets/index-ab.js": {Data: []byte("console.log('js')")},
+	}
+	server := NewServer(nil, "", nil)
+	server.SetStaticAssets(assets)
+	handler := server.Handler()
+
+	cases := map[string]string{
+		"/assets/index-ab.js": "public, max-age=31536000, immutable",
+		"/":                   "no-cache",
+		"/matches/675":        "no-cache",
+		"/favicon.svg":        "no-cache",
+	}
+	for target, want := range cases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+		if got := rec.Header().Get("Cache-Control"); got != want {
+			t.Fatalf("GET %s: Cache-Control = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestMetricsEndpointRecordsRouteLatency(t *testing.T) {
+	handler := NewServer(nil, "", nil).Handler()
+
+	for _, target := range []string{"/api/nope/1", "/api/nope/2"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics: status = %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE ponder_ingest_lines_parsed_total counter",
+		"# TYPE ponder_ingest_lag_bytes gauge",
+		`ponder_http_request_duration_seconds_count{route="/api/",method="GET",code="404"}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("/metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
 func TestRunUpdateCheckUsesPonderRepository(t *testing.T) {
 	var requestedURL string
 	server := NewServer(nil, "", nil)
@@ -82,3 +136,186 @@ type roundTripperFunc func(*http.Request) (*http.Response, error)
 func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
+
+func TestCORSAllowsConfiguredOrigins(t *testing.T) {
+	server := NewServer(nil, "", nil)
+	server.SetAllowedOrigins([]string{" HTTP://NAS.lan:8080 ", "not a url", ""})
+	handler := server.withHostCheck(server.Handler())
+
+	cases := []struct {
+		name       string
+		host       string
+		origin     string
+		wantStatus int
+		wantAllow  string
+	}{
+		{"local dev origin", "127.0.0.1:8080", "http://localhost:5173", http.StatusNoContent, "http://localhost:5173"},
+		{"configured origin", "nas.lan:8080", "http://nas.lan:8080", http.StatusNoContent, "http://nas.lan:8080"},
+		{"unknown origin gets no grant", "127.0.0.1:8080", "https://evil.example", http.StatusNoContent, ""},
+		{"unknown host rejected", "evil.example", "http://localhost:5173", http.StatusForbidden, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/api/overview", nil)
+			req.Host = tc.host
+			req.Header.Set("Origin", tc.origin)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tc.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tc.wantAllow {
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, tc.wantAllow)
+			}
+		})
+	}
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:8080": true,
+		"[::1]:8080":     true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"192.168.1.5:80": false,
+	}
+	for addr, want := range cases {
+		if got := IsLoopbackAddr(addr); got != want {
+			t.Fatalf("IsLoopbackAddr(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestBasePathMountsEveryRoute(t *testing.T) {
+	assets := fstest.MapFS{
+		"index.html":         {Data: []byte(`<html><head><base href="/" /></head>app</html>`)},
+		"assets/index-ab.js": {Data: []byte("console.log('js')")},
+	}
+	server := NewServer(nil, "", nil)
+	server.SetStaticAssets(assets)
+	server.SetBasePath("mtga/")
+	handler := server.Handler()
+
+	cases := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/mtga/", 200, `<html><head><base href="/mtga/" /></head>app</html>`},
+		{"/mtga/matches/675", 200, `<html><head><base href="/mtga/" /></head>app</html>`},
+		{"/mtga/assets/index-ab.js", 200, "console.log('js')"},
+		{"/mtga/api/nope", 404, ""},
+		{"/mtga", 301, ""},
+		{"/", 404, ""},
+		{"/api/nope", 404, ""},
+		{"/mtgax/", 404, ""},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", tc.path, nil))
+		if rec.Code != tc.wantStatus {
+			t.Fatalf("GET %s: status = %d, want %d", tc.path, rec.Code, tc.wantStatus)
+		}
+		if tc.wantBody != "" && rec.Body.String() != tc.wantBody {
+			t.Fatalf("GET %s: body = %q, want %q", tc.path, rec.Body.String(), tc.wantBody)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/mtga?x=1", nil))
+	if got := rec.Header().Get("Location"); got != "/mtga/?x=1" {
+		t.Fatalf("redirect Location = %q, want /mtga/?x=1", got)
+	}
+
+	if got := string(rebaseIndexHTML([]byte("<html><head><title>x</title></head></html>"), "/mtga")); got != `<html><head><base href="/mtga/" /><title>x</title></head></html>` {
+		t.Fatalf("rebase without base tag = %q", got)
+	}
+}
+
+func TestRecoveryTurnsPanicsIntoJSONErrors(t *testing.T) {
+	handler := withRecovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var detail *struct{ Name string }
+		_, _ = w.Write([]byte(detail.Name))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/matches/1", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"error":"internal server error"`) {
+		t.Fatalf("body = %q, want a JSON error", body)
+	}
+
+	// After the response has started, the only option left is aborting.
+	started := withRecovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		panic("mid-stream")
+	}))
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler", recovered)
+		}
+	}()
+	started.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/export/matches", nil))
+}
+
+func TestRunServesOnUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "ponder.sock")
+	// A stale socket from an earlier run is replaced.
+	stale, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	if unixListener, ok := stale.(*net.UnixListener); ok {
+		unixListener.SetUnlinkOnClose(false)
+	}
+	_ = stale.Close()
+
+	server := NewServer(nil, "", nil)
+	server.SetStaticAssets(fstest.MapFS{"index.html": {Data: []byte("<html>app</html>")}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Run(ctx, "unix:"+socketPath) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+		},
+	}}
+	var res *http.Response
+	for attempt := 0; attempt < 50; attempt++ {
+		// The proxy's public Host must not trip the DNS rebinding check.
+		res, err = client.Get("http://mtga.example.com/")
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("GET over unix socket: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.StatusCode)
+	}
+
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatalf("stat socket: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != unixSocketMode {
+		t.Fatalf("socket mode = %o, want %o", perm, unixSocketMode)
+	}
+	if _, err := listen("unix:" + socketPath); err == nil {
+		t.Fatal("listen on a live socket succeeded, want error")
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/model"
+)
+
+const (
+	sessionsDefaultDays = 30
+	// sessionsDefaultTilt is the loss streak that marks a session as tilted:
+	// three in a row is a common point to take a break.
+	sessionsDefaultTilt = 3
+)
+
+// handleSessions serves GET /api/sessions: play sessions newest first, each
+// with its record, how it moved the player's rank, and its worst loss
+// streak. ?days= and ?gap= (minutes) work as for the calendar feed, and
+// ?tilt= sets the loss streak that flags a session.
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	days, err := boundedQueryInt(r, "days", sessionsDefaultDays, calendarMaxDays)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	gap, err := boundedQueryInt(r, "gap", calendarDefaultGap, 24*60)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tilt, err := boundedQueryInt(r, "tilt", sessionsDefaultTilt, 100)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	since := s.store.Clock().Now().AddDate(0, 0, -days)
+	sessions, err := s.store.PlaySessions(ctx, since, time.Duration(gap)*time.Minute)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rankChanges, err := s.store.PlaySessionRankChanges(ctx, sessions)
+	if err != nil {
+		log.Printf("session rank changes: %v", err)
+		rankChanges = nil
+	}
+
+	out := make([]model.PlaySessionSummary, 0, len(sessions))
+	for i := len(sessions) - 1; i >= 0; i-- {
+		session := sessions[i]
+		summary := model.PlaySessionSummary{
+			StartedAt:         session.Start.UTC().Format(time.RFC3339),
+			EndedAt:           session.End.UTC().Format(time.RFC3339),
+			DurationSeconds:   int64(session.End.Sub(session.Start).Seconds()),
+			Matches:           session.Matches,
+			Wins:              session.Wins,
+			Losses:            session.Losses,
+			Draws:             session.Draws,
+			Events:            append([]string{}, session.Events...),
+			EventDisplayNames: make([]string, 0, len(session.Events)),
+			RankChanges:       []model.SessionRankChange{},
+			LongestLossStreak: session.LongestLossStreak,
+			EndingLossStreak:  session.EndingLossStreak,
+			Tilted:            session.LongestLossStreak >= int64(tilt),
+		}
+		if decided := session.Wins + session.Losses; decided > 0 {
+			summary.WinRate = float64(session.Wins) / float64(decided)
+		}
+		for _, name := range session.Events {
+			summary.EventDisplayNames = append(summary.EventDisplayNames, eventnames.Display(name, eventnames.DefaultLanguage, ""))
+		}
+		if session.LongestLossStreak > 0 {
+			summary.LossStreakStartedAt = session.LossStreakStart.UTC().Format(time.RFC3339)
+		}
+		if i < len(rankChanges) && rankChanges[i] != nil {
+			summary.RankChanges = rankChanges[i]
+		}
+		out = append(out, summary)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
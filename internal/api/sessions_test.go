@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestSessionsReportRecordRankChangeAndTilt(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// One match the day before, then a session of three losses in a row and
+	// a win that drops two pips of Gold 4.
+	for _, query := range []string{
+		`INSERT INTO matches (id, arena_match_id, event_name, started_at, ended_at, result, created_at, updated_at) VALUES
+			(1, 'm1', 'Ladder', '2026-03-04T19:00:00Z', '2026-03-04T19:20:00Z', 'win', 'x', 'x'),
+			(2, 'm2', 'Ladder', '2026-03-05T19:00:00Z', '2026-03-05T19:15:00Z', 'loss', 'x', 'x'),
+			(3, 'm3', 'Ladder', '2026-03-05T19:20:00Z', '2026-03-05T19:35:00Z', 'loss', 'x', 'x'),
+			(4, 'm4', 'Ladder', '2026-03-05T19:40:00Z', '2026-03-05T19:55:00Z', 'loss', 'x', 'x'),
+			(5, 'm5', 'Ladder', '2026-03-05T20:00:00Z', '2026-03-05T20:15:00Z', 'win', 'x', 'x')`,
+		`INSERT INTO match_rank_snapshots (match_id, observed_at, payload_json, constructed_season_ordinal, constructed_rank_class, constructed_level, constructed_step, created_at, updated_at) VALUES
+			(1, '2026-03-04T19:20:00Z', '{}', 12, 'Gold', 4, 3, 'x', 'x'),
+			(2, '2026-03-05T19:15:00Z', '{}', 12, 'Gold', 4, 2, 'x', 'x'),
+			(3, '2026-03-05T19:35:00Z', '{}', 12, 'Gold', 4, 1, 'x', 'x'),
+			(4, '2026-03-05T19:55:00Z', '{}', 12, 'Gold', 4, 0, 'x', 'x'),
+			(5, '2026-03-05T20:15:00Z', '{}', 12, 'Gold', 4, 1, 'x', 'x')`,
+	} {
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	store := db.NewStore(database)
+	store.SetClock(db.FixedClock(time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC)))
+	handler := NewServer(store, "", nil).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/sessions", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var sessions []model.PlaySessionSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &sessions); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("sessions = %+v, want 2", sessions)
+	}
+	latest := sessions[0]
+	if latest.Matches != 4 || latest.Wins != 1 || latest.Losses != 3 || latest.DurationSeconds != 75*60 {
+		t.Fatalf("latest session = %+v, want 1-3 over 75 minutes", latest)
+	}
+	if !latest.Tilted || latest.LongestLossStreak != 3 || latest.EndingLossStreak != 0 || latest.LossStreakStartedAt != "2026-03-05T19:00:00Z" {
+		t.Fatalf("latest session streak = %+v, want a tilted 3-loss streak from the first match", latest)
+	}
+	if len(latest.RankChanges) != 1 || latest.RankChanges[0].Steps == nil || *latest.RankChanges[0].Steps != -2 {
+		t.Fatalf("latest rank changes = %+v, want constructed -2", latest.RankChanges)
+	}
+	if sessions[1].Tilted || len(sessions[1].RankChanges) != 0 {
+		t.Fatalf("first session = %+v, want untilted with no rank change", sessions[1])
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/sessions?tilt=4", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &sessions); err != nil || sessions[0].Tilted {
+		t.Fatalf("sessions with tilt=4 = %s, want latest untilted", rec.Body.String())
+	}
+}
@@ -10,7 +10,7 @@ import (
 	"net/url"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 const scryfallSetURL = "https://api.scryfall.com/sets"
@@ -95,7 +95,7 @@ func (s *Server) fetchSetFromScryfall(ctx context.Context, code string) (*model.
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "ponder/0.1 (local tracker)")
 
-	res, err := s.httpClient.Do(req)
+	res, err := s.doScryfall(req)
 	if err != nil {
 		return nil, fmt.Errorf("request scryfall set: %w", err)
 	}
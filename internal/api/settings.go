@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+// handleSettings serves GET /api/settings and PUT /api/settings. A PUT body
+// only needs the keys it changes; the rest keep their saved values.
+func (s *Server) handleSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodPut:
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	settings, err := s.store.GetSettings(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, settings)
+		return
+	}
+
+	if err := decodeJSONBody(r, &settings); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := db.ValidateSettings(settings); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.store.SaveSettings(r.Context(), settings); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// requestFormat is the format a list or overview request is scoped to: its
+// ?format= when given, even empty to show every format, and otherwise the
+// defaultFormat setting.
+func (s *Server) requestFormat(r *http.Request) (string, error) {
+	query := r.URL.Query()
+	if query.Has("format") {
+		return strings.TrimSpace(query.Get("format")), nil
+	}
+	settings, err := s.store.GetSettings(r.Context())
+	if err != nil {
+		return "", err
+	}
+	return settings.DefaultFormat, nil
+}
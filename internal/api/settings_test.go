@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestSettingsPutKeepsOmittedKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	store := db.NewStore(database)
+	handler := NewServer(store, "", nil).Handler()
+
+	do := func(method, body string) (int, model.Settings) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/api/settings", strings.NewReader(body)))
+		var out model.Settings
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+				t.Fatalf("decode settings: %v", err)
+			}
+		}
+		return rec.Code, out
+	}
+
+	if code, got := do(http.MethodGet, ""); code != http.StatusOK || got != db.DefaultSettings() {
+		t.Fatalf("GET = %d %+v, want defaults %+v", code, got, db.DefaultSettings())
+	}
+
+	code, got := do(http.MethodPut, `{"retentionDays": 90, "defaultFormat": "Standard"}`)
+	want := db.DefaultSettings()
+	want.RetentionDays = 90
+	want.DefaultFormat = "Standard"
+	if code != http.StatusOK || got != want {
+		t.Fatalf("PUT = %d %+v, want %+v", code, got, want)
+	}
+
+	code, got = do(http.MethodPut, `{"lookupsEnabled": false, "archetypeSignatureSource": "manual"}`)
+	want.LookupsEnabled = false
+	want.ArchetypeSignatureSource = db.ArchetypeSourceManual
+	if code != http.StatusOK || got != want {
+		t.Fatalf("second PUT = %d %+v, want %+v", code, got, want)
+	}
+
+	if code, _ := do(http.MethodPut, `{"archetypeSignatureSource": "vibes"}`); code != http.StatusBadRequest {
+		t.Fatalf("PUT with unknown source = %d, want 400", code)
+	}
+	if code, _ := do(http.MethodPut, `{"retentionDays": -1}`); code != http.StatusBadRequest {
+		t.Fatalf("PUT with negative retention = %d, want 400", code)
+	}
+
+	saved, err := store.GetSettings(ctx)
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	if saved != want {
+		t.Fatalf("saved settings = %+v, want %+v (rejected PUTs must not save)", saved, want)
+	}
+}
+
+func TestDefaultFormatScopesOverviewAndMatches(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	if _, err := database.ExecContext(ctx, `INSERT INTO matches (arena_match_id, event_name, format, ended_at, result, created_at, updated_at) VALUES
+		('standard', 'Ladder', 'Standard', '2026-05-01T10:00:00Z', 'win', 'x', 'x'),
+		('historic', 'Historic_Ladder', 'Historic', '2026-05-01T11:00:00Z', 'loss', 'x', 'x')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	store := db.NewStore(database)
+	settings := db.DefaultSettings()
+	settings.DefaultFormat = "Standard"
+	if err := store.SaveSettings(ctx, settings); err != nil {
+		t.Fatalf("SaveSettings: %v", err)
+	}
+	handler := NewServer(store, "", nil).Handler()
+
+	matchIDs := func(path string) []string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", path, rec.Code, rec.Body.String())
+		}
+		var rows []model.MatchRow
+		if strings.HasPrefix(path, "/api/overview") {
+			var overview model.Overview
+			if err := json.Unmarshal(rec.Body.Bytes(), &overview); err != nil {
+				t.Fatalf("decode %s: %v", path, err)
+			}
+			rows = overview.Recent
+		} else if err := json.Unmarshal(rec.Body.Bytes(), &rows); err != nil {
+			t.Fatalf("decode %s: %v", path, err)
+		}
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.ArenaMatchID)
+		}
+		return ids
+	}
+
+	for _, tc := range []struct {
+		path string
+		want string
+	}{
+		{"/api/matches", "standard"},
+		{"/api/matches?format=", "historic,standard"},
+		{"/api/matches?format=Historic", "historic"},
+		{"/api/overview", "standard"},
+		{"/api/overview?format=", "historic,standard"},
+	} {
+		if got := strings.Join(matchIDs(tc.path), ","); got != tc.want {
+			t.Errorf("GET %s matches = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// handleTimeOfDayStats serves GET /api/stats/time-of-day: match win rate by
+// the hour of day and weekday each match started, with 95% intervals so a
+// bad hour over a few matches is not mistaken for a pattern. Times are in the
+// server's local zone unless ?tz= names another; ?days= limits the window.
+func (s *Server) handleTimeOfDayStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	now := s.store.Clock().Now()
+	loc := now.Location()
+	if name := strings.TrimSpace(r.URL.Query().Get("tz")); name != "" {
+		named, err := time.LoadLocation(name)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown time zone %q", name))
+			return
+		}
+		loc = named
+	}
+	days, err := boundedQueryInt(r, "days", 0, calendarMaxDays)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var since time.Time
+	if days > 0 {
+		since = now.AddDate(0, 0, -days)
+	}
+	starts, err := s.store.ListMatchStarts(r.Context(), since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := model.TimeOfDayStats{
+		TimeZone: loc.String(),
+		Hours:    make([]model.TimeSlotStats, 24),
+		Weekdays: make([]model.TimeSlotStats, 7),
+	}
+	for hour := range out.Hours {
+		out.Hours[hour] = model.TimeSlotStats{Slot: hour, Label: fmt.Sprintf("%02d:00", hour)}
+	}
+	for day := range out.Weekdays {
+		out.Weekdays[day] = model.TimeSlotStats{Slot: day, Label: time.Weekday(day).String()}
+	}
+	for _, start := range starts {
+		local := start.StartedAt.In(loc)
+		addMatchResult(&out.Hours[local.Hour()].Rate.Record, start.Result)
+		addMatchResult(&out.Weekdays[int(local.Weekday())].Rate.Record, start.Result)
+	}
+	for index := range out.Hours {
+		fillWinRateInterval(&out.Hours[index].Rate)
+	}
+	for index := range out.Weekdays {
+		fillWinRateInterval(&out.Weekdays[index].Rate)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
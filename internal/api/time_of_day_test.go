@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestTimeOfDayStatsBucketByHourAndWeekday(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// 2026-03-05 is a Thursday. The unfinished match has no result.
+	if _, err := database.ExecContext(ctx, `INSERT INTO matches (arena_match_id, started_at, result, created_at, updated_at) VALUES
+		('m1', '2026-03-05T23:10:00Z', 'loss', 'x', 'x'),
+		('m2', '2026-03-05T23:40:00Z', 'loss', 'x', 'x'),
+		('m3', '2026-03-06T00:15:00Z', 'win', 'x', 'x'),
+		('m4', '2026-03-05T19:00:00Z', 'win', 'x', 'x'),
+		('m5', '2026-03-05T19:30:00Z', NULL, 'x', 'x')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(db.NewStore(database), "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/time-of-day?tz=UTC", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var stats model.TimeOfDayStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if stats.TimeZone != "UTC" || len(stats.Hours) != 24 || len(stats.Weekdays) != 7 {
+		t.Fatalf("stats = %+v, want 24 hours and 7 weekdays in UTC", stats)
+	}
+	late := stats.Hours[23]
+	if late.Label != "23:00" || late.Rate.Record.Losses != 2 || late.Rate.WinRate == nil || *late.Rate.WinRate != 0 {
+		t.Fatalf("23:00 = %+v, want two losses", late)
+	}
+	if stats.Hours[19].Rate.Record.Games != 1 || stats.Hours[12].Rate.WinRate != nil {
+		t.Fatalf("19:00 = %+v, 12:00 = %+v; want one game, then none", stats.Hours[19], stats.Hours[12])
+	}
+	thursday, friday := stats.Weekdays[4], stats.Weekdays[5]
+	if thursday.Label != "Thursday" || thursday.Rate.Record.Games != 3 || friday.Rate.Record.Wins != 1 {
+		t.Fatalf("Thursday = %+v, Friday = %+v", thursday, friday)
+	}
+
+	rec = httptest.NewRecorder()
+	NewServer(db.NewStore(database), "", nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/time-of-day?tz=Nowhere/Special", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown tz status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// selfSignedValidity is how long a generated certificate lasts; it is
+// regenerated on startup once it is within selfSignedRenewBefore of expiry.
+const (
+	selfSignedValidity    = 365 * 24 * time.Hour
+	selfSignedRenewBefore = 7 * 24 * time.Hour
+)
+
+// SetTLS makes Run serve HTTPS with the given PEM certificate and key.
+func (s *Server) SetTLS(certFile, keyFile string) {
+	s.tlsCertFile = certFile
+	s.tlsKeyFile = keyFile
+}
+
+// EnsureSelfSignedCert writes a self-signed certificate and key to certFile
+// and keyFile unless a still-valid pair covering hosts is already there, so
+// browsers only need to trust it once per set of hosts. The certificate
+// covers localhost, loopback IPs, and any extra hosts (hostnames or IPs) the
+// server will be reached by.
+func EnsureSelfSignedCert(certFile, keyFile string, hosts []string) error {
+	if pair, err := tls.LoadX509KeyPair(certFile, keyFile); err == nil && len(pair.Certificate) > 0 {
+		if leaf, err := x509.ParseCertificate(pair.Certificate[0]); err == nil &&
+			time.Until(leaf.NotAfter) > selfSignedRenewBefore && certCoversHosts(leaf, hosts) {
+			return nil
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generate tls key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return fmt.Errorf("generate tls serial: %w", err)
+	}
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"ponder"}, CommonName: "ponder self-signed"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if host != "" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("create tls certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("marshal tls key: %w", err)
+	}
+
+	if err := writePEM(certFile, "CERTIFICATE", der, 0o644); err != nil {
+		return err
+	}
+	return writePEM(keyFile, "PRIVATE KEY", keyDER, 0o600)
+}
+
+// certCoversHosts reports whether every non-empty host is among leaf's
+// subject alternative names.
+func certCoversHosts(leaf *x509.Certificate, hosts []string) bool {
+	for _, host := range hosts {
+		if host != "" && leaf.VerifyHostname(host) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func writePEM(path, blockType string, der []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create %s dir: %w", path, err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, perm); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnsureSelfSignedCertGeneratesOnceAndCoversHosts(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls", "cert.pem")
+	keyFile := filepath.Join(dir, "tls", "key.pem")
+
+	if err := EnsureSelfSignedCert(certFile, keyFile, []string{"nas.lan", "192.168.1.20"}); err != nil {
+		t.Fatalf("EnsureSelfSignedCert: %v", err)
+	}
+	first, err := os.ReadFile(certFile)
+	if err != nil {
+		t.Fatalf("read cert: %v", err)
+	}
+	if info, err := os.Stat(keyFile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("key file = %v, %v; want mode 0600", info, err)
+	}
+
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadX509KeyPair: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+	for _, host := range []string{"localhost", "127.0.0.1", "nas.lan", "192.168.1.20"} {
+		if err := leaf.VerifyHostname(host); err != nil {
+			t.Fatalf("certificate does not cover %s: %v", host, err)
+		}
+	}
+
+	if err := EnsureSelfSignedCert(certFile, keyFile, nil); err != nil {
+		t.Fatalf("EnsureSelfSignedCert (reuse): %v", err)
+	}
+	second, err := os.ReadFile(certFile)
+	if err != nil {
+		t.Fatalf("reread cert: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatal("a still-valid certificate was regenerated")
+	}
+
+	// Binding to a host the cached certificate does not cover replaces it.
+	if err := EnsureSelfSignedCert(certFile, keyFile, []string{"nas.lan", "10.0.0.5"}); err != nil {
+		t.Fatalf("EnsureSelfSignedCert (new host): %v", err)
+	}
+	pair, err = tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadX509KeyPair (new host): %v", err)
+	}
+	leaf, err = x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		t.Fatalf("ParseCertificate (new host): %v", err)
+	}
+	if err := leaf.VerifyHostname("10.0.0.5"); err != nil {
+		t.Fatalf("regenerated certificate does not cover the new host: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// handleTrophies serves GET /api/stats/trophies: every draft and sealed run
+// with its record, trophies marked, and the trophy rate and average wins
+// per run overall and by set and event kind.
+func (s *Server) handleTrophies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	out, err := s.store.TrophyStats(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	lang := eventnames.Language(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))
+	for index := range out.Groups {
+		group := &out.Groups[index]
+		group.KindLabel = eventnames.KindLabel(group.Kind, lang)
+		if group.KindLabel == "" {
+			group.KindLabel = strings.ReplaceAll(group.Kind, "_", " ")
+		}
+	}
+	s.enrichEventDisplayNames(r, eventTargets(out.Runs, func(row *model.LimitedRun) (string, *string) { return row.EventName, &row.EventDisplayName }))
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestTrophiesListsLimitedRunsAndAveragesWins(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// Two finished BLB Premier Draft runs (one a 7-win trophy), a 3-0
+	// Traditional Draft trophy, an unfinished sealed run, and a ladder run
+	// that is not limited at all.
+	if _, err := database.ExecContext(ctx, `
+		INSERT INTO event_runs (event_name, status, started_at, ended_at, wins, losses, updated_at) VALUES
+			('PremierDraft_BLB_20240801', 'claimed', '2024-08-02T10:00:00Z', '2024-08-03T10:00:00Z', 7, 1, 'x'),
+			('PremierDraft_BLB_20240815', 'claimed', '2024-08-16T10:00:00Z', '2024-08-16T20:00:00Z', 3, 3, 'x'),
+			('TradDraft_BLB_20240801', 'claimed', '2024-08-20T10:00:00Z', '2024-08-20T12:00:00Z', 3, 0, 'x'),
+			('Sealed_BLB_20240801', 'active', '2024-08-25T10:00:00Z', NULL, 2, 1, 'x'),
+			('Ladder', 'active', '2024-08-01T10:00:00Z', NULL, 40, 30, 'x')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	handler := NewServer(db.NewStore(database), "", nil).Handler()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats/trophies", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var out model.TrophyStats
+	if err := json.NewDecoder(rec.Body).Decode(&out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if len(out.Runs) != 4 {
+		t.Fatalf("runs = %+v, want the four limited runs", out.Runs)
+	}
+	if first := out.Runs[0]; first.EventName != "Sealed_BLB_20240801" || first.Finished || first.Trophy {
+		t.Fatalf("newest run = %+v, want the unfinished sealed run", first)
+	}
+	if trad := out.Runs[1]; !trad.Trophy || trad.TrophyWins != 3 || trad.SetCode != "BLB" {
+		t.Fatalf("traditional run = %+v, want a 3-win trophy", trad)
+	}
+
+	summary := out.Summary
+	if summary.Runs != 3 || summary.ActiveRuns != 1 || summary.Trophies != 2 {
+		t.Fatalf("summary = %+v, want 3 finished runs, 1 active, 2 trophies", summary)
+	}
+	if summary.AverageWins == nil || *summary.AverageWins != 13.0/3 {
+		t.Fatalf("average wins = %v, want 13/3", summary.AverageWins)
+	}
+	if summary.LastTrophyAt != "2024-08-20T12:00:00Z" {
+		t.Fatalf("last trophy at = %q", summary.LastTrophyAt)
+	}
+
+	if len(out.Groups) != 3 {
+		t.Fatalf("groups = %+v, want premier, traditional and sealed", out.Groups)
+	}
+	premier := out.Groups[0]
+	if premier.Kind != "premier_draft" || premier.KindLabel == "" || premier.Runs != 2 || premier.Trophies != 1 {
+		t.Fatalf("premier group = %+v", premier)
+	}
+	if premier.AverageWins == nil || *premier.AverageWins != 5 || premier.TrophyRate == nil || *premier.TrophyRate != 0.5 {
+		t.Fatalf("premier averages = %v wins, %v rate; want 5 and 0.5", premier.AverageWins, premier.TrophyRate)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/overview", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("overview status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var overview model.Overview
+	if err := json.NewDecoder(rec.Body).Decode(&overview); err != nil {
+		t.Fatalf("decode overview: %v", err)
+	}
+	if overview.Limited.Runs != 3 || overview.Limited.Trophies != 2 {
+		t.Fatalf("overview limited = %+v, want the trophy summary", overview.Limited)
+	}
+}
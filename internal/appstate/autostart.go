@@ -1,5 +1,12 @@
 package appstate
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
 // AutostartStatus describes whether the app is configured to launch at login.
 type AutostartStatus struct {
 	Supported  bool   `json:"supported"`
@@ -8,3 +15,30 @@ type AutostartStatus struct {
 	Executable string `json:"executable,omitempty"`
 	Note       string `json:"note,omitempty"`
 }
+
+// autostartExecutable resolves the binary a LaunchAgent or login service
+// should point at and rejects transient binaries (`go run`, test builds)
+// that would leave a dangling agent behind.
+func autostartExecutable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("resolve executable: %w", err)
+	}
+	exe, err = filepath.EvalSymlinks(exe)
+	if err != nil {
+		return "", fmt.Errorf("resolve executable symlinks: %w", err)
+	}
+	tempDir, err := filepath.EvalSymlinks(os.TempDir())
+	if err != nil {
+		tempDir = os.TempDir()
+	}
+	if strings.HasPrefix(exe, tempDir) || strings.Contains(exe, string(filepath.Separator)+"go-build") {
+		return "", fmt.Errorf("launch at login requires an installed app build (current binary is temporary: %s)", exe)
+	}
+	return exe, nil
+}
+
+func xmlEscape(value string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	return replacer.Replace(value)
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
 const launchAgentLabel = "dev.ixianlabs.ponder"
@@ -19,33 +18,6 @@ func launchAgentPath() (string, error) {
 	return filepath.Join(home, "Library", "LaunchAgents", launchAgentLabel+".plist"), nil
 }
 
-// autostartExecutable resolves the binary a LaunchAgent should point at and
-// rejects transient binaries (`go run`, test builds) that would leave a
-// dangling agent behind.
-func autostartExecutable() (string, error) {
-	exe, err := os.Executable()
-	if err != nil {
-		return "", fmt.Errorf("resolve executable: %w", err)
-	}
-	exe, err = filepath.EvalSymlinks(exe)
-	if err != nil {
-		return "", fmt.Errorf("resolve executable symlinks: %w", err)
-	}
-	tempDir, err := filepath.EvalSymlinks(os.TempDir())
-	if err != nil {
-		tempDir = os.TempDir()
-	}
-	if strings.HasPrefix(exe, tempDir) || strings.Contains(exe, string(filepath.Separator)+"go-build") {
-		return "", fmt.Errorf("launch at login requires an installed app build (current binary is temporary: %s)", exe)
-	}
-	return exe, nil
-}
-
-func xmlEscape(value string) string {
-	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
-	return replacer.Replace(value)
-}
-
 func GetAutostartStatus() AutostartStatus {
 	status := AutostartStatus{Supported: true}
 	agentPath, err := launchAgentPath()
@@ -0,0 +1,168 @@
+package appstate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// A backup is due once the newest one is a day old; the scheduler wakes
+	// hourly so a machine that sleeps overnight catches up soon after waking.
+	backupInterval      = 24 * time.Hour
+	backupCheckInterval = time.Hour
+	defaultBackupKeep   = 7
+
+	backupFilePrefix = "ponder-"
+	backupFileSuffix = ".db"
+	backupTimeLayout = "20060102-150405"
+)
+
+// Notifier surfaces background failures the user would otherwise only find
+// in a log file. The desktop app shows a native dialog; headless serve logs.
+type Notifier interface {
+	Notify(title, message string)
+}
+
+type logNotifier struct{}
+
+func (logNotifier) Notify(title, message string) {
+	log.Printf("%s: %s", title, message)
+}
+
+// BackupResult describes the most recent nightly backup.
+type BackupResult struct {
+	Path        string `json:"path"`
+	SizeBytes   int64  `json:"sizeBytes"`
+	CompletedAt string `json:"completedAt"`
+}
+
+// backupDir resolves where backups go: the configured directory, or a
+// "backups" folder next to the database.
+func (s *Service) backupDir(cfg Config) string {
+	if dir := strings.TrimSpace(cfg.BackupDir); dir != "" {
+		return dir
+	}
+	return filepath.Join(filepath.Dir(s.dbPath), "backups")
+}
+
+// StartNightlyBackups runs RunBackup whenever the newest backup is at least a
+// day old, until ctx is cancelled. A negative BackupKeep disables it.
+func (s *Service) StartNightlyBackups(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(backupCheckInterval)
+		defer ticker.Stop()
+		for {
+			s.mu.RLock()
+			cfg := s.config
+			s.mu.RUnlock()
+			if cfg.BackupKeep >= 0 {
+				latest := latestBackupTime(s.backupDir(cfg))
+				if time.Since(latest) >= backupInterval {
+					if _, err := s.RunBackup(ctx); err != nil && ctx.Err() == nil {
+						s.notifier.Notify("Ponder backup failed", err.Error())
+					}
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunBackup verifies the database with integrity_check, writes a timestamped
+// VACUUM INTO copy, and prunes all but the newest BackupKeep copies. A failed
+// integrity check skips the copy so a corrupt file never rotates out a good
+// backup.
+func (s *Service) RunBackup(ctx context.Context) (BackupResult, error) {
+	s.mu.RLock()
+	cfg := s.config
+	s.mu.RUnlock()
+
+	if err := s.store.IntegrityCheck(ctx); err != nil {
+		s.setLastError(fmt.Sprintf("nightly backup skipped: %v", err))
+		return BackupResult{}, err
+	}
+
+	dir := s.backupDir(cfg)
+	now := time.Now().UTC()
+	path := filepath.Join(dir, backupFilePrefix+now.Format(backupTimeLayout)+backupFileSuffix)
+	if err := s.store.BackupTo(ctx, path); err != nil {
+		s.setLastError(fmt.Sprintf("nightly backup failed: %v", err))
+		return BackupResult{}, err
+	}
+
+	keep := cfg.BackupKeep
+	if keep <= 0 {
+		keep = defaultBackupKeep
+	}
+	if err := pruneBackups(dir, keep); err != nil {
+		s.setLastError(fmt.Sprintf("prune backups: %v", err))
+		return BackupResult{}, err
+	}
+
+	result := BackupResult{Path: path, CompletedAt: formatTime(now)}
+	if info, err := os.Stat(path); err == nil {
+		result.SizeBytes = info.Size()
+	}
+	s.mu.Lock()
+	s.lastBackup = &result
+	s.mu.Unlock()
+	return result, nil
+}
+
+// listBackups returns backup file names in dir, oldest first. The timestamp
+// layout sorts lexically, so no parsing is needed.
+func listBackups(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read backup dir: %w", err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, backupFilePrefix) && strings.HasSuffix(name, backupFileSuffix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func latestBackupTime(dir string) time.Time {
+	names, err := listBackups(dir)
+	if err != nil || len(names) == 0 {
+		return time.Time{}
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(names[len(names)-1], backupFilePrefix), backupFileSuffix)
+	parsed, err := time.Parse(backupTimeLayout, stamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+func pruneBackups(dir string, keep int) error {
+	names, err := listBackups(dir)
+	if err != nil {
+		return err
+	}
+	for len(names) > keep {
+		if err := os.Remove(filepath.Join(dir, names[0])); err != nil {
+			return fmt.Errorf("remove old backup: %w", err)
+		}
+		names = names[1:]
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package appstate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestRunBackupRotatesOldCopies(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "ponder.db")
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	service, err := NewService(Options{Store: db.NewStore(database), DBPath: dbPath, SupportDir: dir})
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	if _, err := service.UpdateConfig(Config{BackupKeep: 2}); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+
+	backupDir := filepath.Join(dir, "backups")
+	if err := os.MkdirAll(backupDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, stale := range []string{"ponder-20250101-000000.db", "ponder-20250102-000000.db"} {
+		if err := os.WriteFile(filepath.Join(backupDir, stale), nil, 0o644); err != nil {
+			t.Fatalf("write stale backup: %v", err)
+		}
+	}
+	if got := latestBackupTime(backupDir); got.Format(backupTimeLayout) != "20250102-000000" {
+		t.Fatalf("latestBackupTime = %v", got)
+	}
+
+	result, err := service.RunBackup(ctx)
+	if err != nil {
+		t.Fatalf("RunBackup: %v", err)
+	}
+	if result.SizeBytes == 0 || filepath.Dir(result.Path) != backupDir {
+		t.Fatalf("result = %+v, want a non-empty file in %s", result, backupDir)
+	}
+
+	names, err := listBackups(backupDir)
+	if err != nil {
+		t.Fatalf("listBackups: %v", err)
+	}
+	if len(names) != 2 || names[0] != "ponder-20250102-000000.db" || filepath.Join(backupDir, names[1]) != result.Path {
+		t.Fatalf("backups after rotation = %v, want newest stale copy plus %s", names, result.Path)
+	}
+	if status := service.Status(); status.LastBackup == nil || status.LastBackup.Path != result.Path {
+		t.Fatalf("status.LastBackup = %+v", status.LastBackup)
+	}
+}
@@ -0,0 +1,156 @@
+package appstate
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// daemonLabel is distinct from launchAgentLabel: the desktop app's
+	// launch-at-login agent and the headless service can be installed
+	// independently.
+	daemonLabel    = "dev.ixianlabs.ponder.daemon"
+	daemonTaskName = "Ponder"
+)
+
+// DaemonStatus describes the login service `ponder service` manages.
+type DaemonStatus struct {
+	Supported bool
+	Installed bool
+	// Path is the launchd plist on macOS and the Task Scheduler task name on
+	// Windows.
+	Path string
+	Note string
+}
+
+// DaemonConfig is what the login service runs: the ponder binary with Args,
+// writing its output to LogPath where the platform supports that.
+type DaemonConfig struct {
+	Executable string
+	Args       []string
+	LogPath    string
+}
+
+// ResolveDaemonExecutable returns the installed ponder binary, refusing
+// temporary `go run` builds that would be gone by the next login.
+func ResolveDaemonExecutable() (string, error) {
+	return autostartExecutable()
+}
+
+// launchdPlist renders a LaunchAgent that starts cfg at login and restarts
+// it if it exits.
+func launchdPlist(cfg DaemonConfig) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+`)
+	fmt.Fprintf(&b, "\t<string>%s</string>\n", daemonLabel)
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{cfg.Executable}, cfg.Args...) {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	b.WriteString("\t</array>\n")
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("\t<key>KeepAlive</key>\n\t<true/>\n")
+	if cfg.LogPath != "" {
+		fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", xmlEscape(cfg.LogPath))
+		fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", xmlEscape(cfg.LogPath))
+	}
+	b.WriteString("\t<key>ProcessType</key>\n\t<string>Background</string>\n")
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// daemonWrapperName is the script the Windows task runs through
+// wscript.exe, which has no console, so the service starts without a
+// window.
+const daemonWrapperName = "ponder-service.vbs"
+
+// daemonWrapperScript renders a VBScript that runs cfg with its window
+// hidden, appending its output to LogPath through cmd.exe, and waits for it
+// so the task sees the service's exit code and can restart it.
+func daemonWrapperScript(cfg DaemonConfig) string {
+	command := make([]string, 0, len(cfg.Args)+1)
+	for _, arg := range append([]string{cfg.Executable}, cfg.Args...) {
+		command = append(command, windowsQuoteArg(arg))
+	}
+	line := strings.Join(command, " ")
+	if cfg.LogPath != "" {
+		// cmd /c strips the outer quotes and runs the rest as typed.
+		line = `cmd.exe /c "` + line + ` >> "` + cfg.LogPath + `" 2>&1"`
+	}
+	var b strings.Builder
+	b.WriteString("' Written by `ponder service install`; runs the Ponder service without a console window.\r\n")
+	b.WriteString("Set shell = CreateObject(\"WScript.Shell\")\r\n")
+	fmt.Fprintf(&b, "WScript.Quit shell.Run(\"%s\", 0, True)\r\n", strings.ReplaceAll(line, `"`, `""`))
+	return b.String()
+}
+
+// taskSchedulerXML renders a Task Scheduler task that runs wrapperPath
+// hidden when userID logs on, restarts it a minute after it fails, and never
+// stops it for running too long. Command and arguments are separate
+// elements, so long paths do not hit schtasks' 261-character /TR limit.
+func taskSchedulerXML(wrapperPath, userID string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-16"?>
+<Task version="1.2" xmlns="http://schemas.microsoft.com/windows/2004/02/mit/task">
+  <RegistrationInfo>
+    <Description>Ponder: tracks MTG Arena and serves the web UI.</Description>
+  </RegistrationInfo>
+  <Triggers>
+    <LogonTrigger>
+      <Enabled>true</Enabled>
+`)
+	fmt.Fprintf(&b, "      <UserId>%s</UserId>\n", xmlEscape(userID))
+	b.WriteString(`    </LogonTrigger>
+  </Triggers>
+  <Principals>
+    <Principal id="Author">
+`)
+	fmt.Fprintf(&b, "      <UserId>%s</UserId>\n", xmlEscape(userID))
+	b.WriteString(`      <LogonType>InteractiveToken</LogonType>
+      <RunLevel>LeastPrivilege</RunLevel>
+    </Principal>
+  </Principals>
+  <Settings>
+    <MultipleInstancesPolicy>IgnoreNew</MultipleInstancesPolicy>
+    <DisallowStartIfOnBatteries>false</DisallowStartIfOnBatteries>
+    <StopIfGoingOnBatteries>false</StopIfGoingOnBatteries>
+    <AllowStartOnDemand>true</AllowStartOnDemand>
+    <Enabled>true</Enabled>
+    <Hidden>true</Hidden>
+    <ExecutionTimeLimit>PT0S</ExecutionTimeLimit>
+    <RestartOnFailure>
+      <Interval>PT1M</Interval>
+      <Count>999</Count>
+    </RestartOnFailure>
+  </Settings>
+  <Actions Context="Author">
+    <Exec>
+      <Command>wscript.exe</Command>
+`)
+	fmt.Fprintf(&b, "      <Arguments>%s</Arguments>\n", xmlEscape("//B //Nologo "+windowsQuoteArg(wrapperPath)))
+	b.WriteString(`    </Exec>
+  </Actions>
+</Task>
+`)
+	return b.String()
+}
+
+// schtasksCreateArgs builds the schtasks.exe arguments that register the
+// task defined in xmlPath, replacing any earlier one.
+func schtasksCreateArgs(xmlPath string) []string {
+	return []string{"/Create", "/TN", daemonTaskName, "/XML", xmlPath, "/F"}
+}
+
+// windowsQuoteArg quotes an argument for a Windows command line when it
+// contains spaces or quotes, as paths under Program Files do.
+func windowsQuoteArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"") {
+		return arg
+	}
+	return `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+}
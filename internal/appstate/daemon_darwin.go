@@ -0,0 +1,78 @@
+//go:build darwin
+
+package appstate
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func daemonPlistPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", daemonLabel+".plist"), nil
+}
+
+func launchdDomain() string {
+	return "gui/" + strconv.Itoa(os.Getuid())
+}
+
+// GetDaemonStatus reports whether the login service's LaunchAgent exists.
+func GetDaemonStatus() DaemonStatus {
+	status := DaemonStatus{Supported: true}
+	plistPath, err := daemonPlistPath()
+	if err != nil {
+		status.Note = err.Error()
+		return status
+	}
+	status.Path = plistPath
+	if _, err := os.Stat(plistPath); err == nil {
+		status.Installed = true
+	}
+	return status
+}
+
+// InstallDaemon writes a LaunchAgent for cfg and loads it, which starts it
+// now as well as at every login. Reinstalling replaces the running copy.
+func InstallDaemon(cfg DaemonConfig) (DaemonStatus, error) {
+	plistPath, err := daemonPlistPath()
+	if err != nil {
+		return GetDaemonStatus(), err
+	}
+	if cfg.LogPath != "" {
+		if err := os.MkdirAll(filepath.Dir(cfg.LogPath), 0o755); err != nil {
+			return GetDaemonStatus(), fmt.Errorf("create service log dir: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(plistPath), 0o755); err != nil {
+		return GetDaemonStatus(), fmt.Errorf("create LaunchAgents dir: %w", err)
+	}
+	// Unload any earlier version first; bootstrap refuses a loaded label.
+	_ = exec.Command("launchctl", "bootout", launchdDomain()+"/"+daemonLabel).Run()
+	if err := os.WriteFile(plistPath, []byte(launchdPlist(cfg)), 0o644); err != nil {
+		return GetDaemonStatus(), fmt.Errorf("write launch agent: %w", err)
+	}
+	if out, err := exec.Command("launchctl", "bootstrap", launchdDomain(), plistPath).CombinedOutput(); err != nil {
+		return GetDaemonStatus(), fmt.Errorf("launchctl bootstrap: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return GetDaemonStatus(), nil
+}
+
+// UninstallDaemon stops the login service and removes its LaunchAgent.
+func UninstallDaemon() (DaemonStatus, error) {
+	plistPath, err := daemonPlistPath()
+	if err != nil {
+		return GetDaemonStatus(), err
+	}
+	_ = exec.Command("launchctl", "bootout", launchdDomain()+"/"+daemonLabel).Run()
+	if err := os.Remove(plistPath); err != nil && !os.IsNotExist(err) {
+		return GetDaemonStatus(), fmt.Errorf("remove launch agent: %w", err)
+	}
+	return GetDaemonStatus(), nil
+}
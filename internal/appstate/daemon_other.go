@@ -0,0 +1,17 @@
+//go:build !darwin && !windows
+
+package appstate
+
+import "fmt"
+
+func GetDaemonStatus() DaemonStatus {
+	return DaemonStatus{Supported: false, Note: "the login service is only implemented on macOS and Windows; run `ponder serve -live` from your init system instead"}
+}
+
+func InstallDaemon(cfg DaemonConfig) (DaemonStatus, error) {
+	return GetDaemonStatus(), fmt.Errorf("service install is not supported on this platform")
+}
+
+func UninstallDaemon() (DaemonStatus, error) {
+	return GetDaemonStatus(), fmt.Errorf("service uninstall is not supported on this platform")
+}
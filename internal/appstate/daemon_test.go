@@ -0,0 +1,63 @@
+package appstate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLaunchdPlistRunsServeAtLogin(t *testing.T) {
+	t.Parallel()
+
+	plist := launchdPlist(DaemonConfig{
+		Executable: "/Applications/Ponder & Co/ponder",
+		Args:       []string{"serve", "-live", "-db", "/Users/me/ponder.db"},
+		LogPath:    "/Users/me/Library/Logs/ponder-service.log",
+	})
+	for _, want := range []string{
+		"<string>" + daemonLabel + "</string>",
+		"<string>/Applications/Ponder &amp; Co/ponder</string>\n\t\t<string>serve</string>\n\t\t<string>-live</string>",
+		"<key>RunAtLoad</key>\n\t<true/>",
+		"<key>KeepAlive</key>\n\t<true/>",
+		"<key>StandardErrorPath</key>\n\t<string>/Users/me/Library/Logs/ponder-service.log</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Fatalf("plist missing %q:\n%s", want, plist)
+		}
+	}
+}
+
+func TestWindowsTaskRunsHiddenWrapper(t *testing.T) {
+	t.Parallel()
+
+	script := daemonWrapperScript(DaemonConfig{
+		Executable: `C:\Program Files\Ponder\ponder.exe`,
+		Args:       []string{"serve", "-live", "-db", `C:\Users\me\AppData\Roaming\Ponder\ponder.db`},
+		LogPath:    `C:\Users\me\AppData\Roaming\Ponder\ponder-service.log`,
+	})
+	want := `WScript.Quit shell.Run("cmd.exe /c """"C:\Program Files\Ponder\ponder.exe"" serve -live -db C:\Users\me\AppData\Roaming\Ponder\ponder.db >> ""C:\Users\me\AppData\Roaming\Ponder\ponder-service.log"" 2>&1""", 0, True)`
+	if !strings.Contains(script, want+"\r\n") {
+		t.Fatalf("wrapper script missing %q:\n%s", want, script)
+	}
+
+	wrapperPath := `C:\Users\Pat O'Brien & Co\AppData\Roaming\ponder\` + daemonWrapperName
+	task := taskSchedulerXML(wrapperPath, `DESKTOP\pat`)
+	for _, want := range []string{
+		"<UserId>DESKTOP\\pat</UserId>",
+		"<Hidden>true</Hidden>",
+		"<ExecutionTimeLimit>PT0S</ExecutionTimeLimit>",
+		"<RestartOnFailure>",
+		"<Command>wscript.exe</Command>",
+		"<Arguments>//B //Nologo &quot;C:\\Users\\Pat O'Brien &amp; Co\\AppData\\Roaming\\ponder\\ponder-service.vbs&quot;</Arguments>",
+	} {
+		if !strings.Contains(task, want) {
+			t.Fatalf("task XML missing %q:\n%s", want, task)
+		}
+	}
+
+	got := schtasksCreateArgs(`C:\Temp\ponder-task.xml`)
+	wantArgs := []string{"/Create", "/TN", "Ponder", "/XML", `C:\Temp\ponder-task.xml`, "/F"}
+	if !reflect.DeepEqual(got, wantArgs) {
+		t.Fatalf("schtasks args = %q, want %q", got, wantArgs)
+	}
+}
@@ -0,0 +1,96 @@
+//go:build windows
+
+package appstate
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// GetDaemonStatus reports whether the login service's scheduled task exists.
+func GetDaemonStatus() DaemonStatus {
+	status := DaemonStatus{Supported: true, Path: daemonTaskName}
+	if err := exec.Command("schtasks", "/Query", "/TN", daemonTaskName).Run(); err == nil {
+		status.Installed = true
+	}
+	return status
+}
+
+// InstallDaemon registers a hidden Task Scheduler task that runs cfg at
+// logon through a wscript wrapper in the support dir, so no console window
+// opens, and starts it now. The wrapper appends the service's output to
+// LogPath.
+func InstallDaemon(cfg DaemonConfig) (DaemonStatus, error) {
+	supportDir, err := DefaultSupportDir()
+	if err != nil {
+		return GetDaemonStatus(), err
+	}
+	if err := os.MkdirAll(supportDir, 0o755); err != nil {
+		return GetDaemonStatus(), fmt.Errorf("create support dir: %w", err)
+	}
+	wrapperPath := filepath.Join(supportDir, daemonWrapperName)
+	if err := os.WriteFile(wrapperPath, utf16File(daemonWrapperScript(cfg)), 0o644); err != nil {
+		return GetDaemonStatus(), fmt.Errorf("write service wrapper: %w", err)
+	}
+
+	userID := os.Getenv("USERNAME")
+	if domain := os.Getenv("USERDOMAIN"); domain != "" {
+		userID = domain + `\` + userID
+	}
+	xmlFile, err := os.CreateTemp("", "ponder-task-*.xml")
+	if err != nil {
+		return GetDaemonStatus(), fmt.Errorf("create task definition: %w", err)
+	}
+	defer os.Remove(xmlFile.Name())
+	if _, err := xmlFile.Write(utf16File(taskSchedulerXML(wrapperPath, userID))); err != nil {
+		xmlFile.Close()
+		return GetDaemonStatus(), fmt.Errorf("write task definition: %w", err)
+	}
+	if err := xmlFile.Close(); err != nil {
+		return GetDaemonStatus(), fmt.Errorf("write task definition: %w", err)
+	}
+
+	if out, err := exec.Command("schtasks", schtasksCreateArgs(xmlFile.Name())...).CombinedOutput(); err != nil {
+		return GetDaemonStatus(), fmt.Errorf("schtasks /Create: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	// Restart so a reinstall picks up new arguments straight away.
+	_ = exec.Command("schtasks", "/End", "/TN", daemonTaskName).Run()
+	if out, err := exec.Command("schtasks", "/Run", "/TN", daemonTaskName).CombinedOutput(); err != nil {
+		return GetDaemonStatus(), fmt.Errorf("schtasks /Run: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return GetDaemonStatus(), nil
+}
+
+// UninstallDaemon stops the login service and deletes its scheduled task
+// and wrapper script.
+func UninstallDaemon() (DaemonStatus, error) {
+	status := GetDaemonStatus()
+	if !status.Installed {
+		return status, nil
+	}
+	_ = exec.Command("schtasks", "/End", "/TN", daemonTaskName).Run()
+	if out, err := exec.Command("schtasks", "/Delete", "/TN", daemonTaskName, "/F").CombinedOutput(); err != nil {
+		return GetDaemonStatus(), fmt.Errorf("schtasks /Delete: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	if supportDir, err := DefaultSupportDir(); err == nil {
+		_ = os.Remove(filepath.Join(supportDir, daemonWrapperName))
+	}
+	return GetDaemonStatus(), nil
+}
+
+// utf16File encodes text as UTF-16LE with a byte order mark, the encoding
+// schtasks expects for task XML and that lets wscript read non-ASCII paths.
+func utf16File(text string) []byte {
+	units := utf16.Encode([]rune(text))
+	out := make([]byte, 2, 2+2*len(units))
+	binary.LittleEndian.PutUint16(out, 0xfeff)
+	for _, unit := range units {
+		out = binary.LittleEndian.AppendUint16(out, unit)
+	}
+	return out
+}
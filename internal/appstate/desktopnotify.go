@@ -0,0 +1,29 @@
+package appstate
+
+import (
+	"log"
+	"strings"
+)
+
+// DesktopNotifier shows each notification natively (Notification Center,
+// Windows toasts, or notify-send), for headless commands such as tail that
+// have no window of their own. A failure to show one falls back to the log.
+type DesktopNotifier struct{}
+
+func (DesktopNotifier) Notify(title, message string) {
+	log.Printf("%s: %s", title, message)
+	if err := showDesktopNotification(title, message); err != nil {
+		log.Printf("desktop notification failed: %v", err)
+	}
+}
+
+// appleScriptString quotes value as an AppleScript string literal.
+func appleScriptString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// powerShellString quotes value as a single-quoted PowerShell literal, in
+// which only the quote itself needs escaping.
+func powerShellString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,17 @@
+//go:build darwin
+
+package appstate
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func showDesktopNotification(title, message string) error {
+	script := "display notification " + appleScriptString(message) + " with title " + appleScriptString(title)
+	if out, err := exec.Command("osascript", "-e", script).CombinedOutput(); err != nil {
+		return fmt.Errorf("osascript: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
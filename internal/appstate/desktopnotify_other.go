@@ -0,0 +1,20 @@
+//go:build !darwin && !windows
+
+package appstate
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func showDesktopNotification(title, message string) error {
+	path, err := exec.LookPath("notify-send")
+	if err != nil {
+		return fmt.Errorf("notify-send not found: %w", err)
+	}
+	if out, err := exec.Command(path, "--app-name=Ponder", title, message).CombinedOutput(); err != nil {
+		return fmt.Errorf("notify-send: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
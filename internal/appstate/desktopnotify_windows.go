@@ -0,0 +1,28 @@
+//go:build windows
+
+package appstate
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// toastAppID borrows PowerShell's registered app id; Windows drops toasts
+// from ids it has no Start menu entry for.
+const toastAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+func showDesktopNotification(title, message string) error {
+	xml := "<toast><visual><binding template=\"ToastGeneric\"><text>" + xmlEscape(title) + "</text><text>" + xmlEscape(message) + "</text></binding></visual></toast>"
+	script := strings.Join([]string{
+		"[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null",
+		"[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] | Out-Null",
+		"$xml = New-Object Windows.Data.Xml.Dom.XmlDocument",
+		"$xml.LoadXml(" + powerShellString(xml) + ")",
+		"[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(" + powerShellString(toastAppID) + ").Show([Windows.UI.Notifications.ToastNotification]::new($xml))",
+	}, "; ")
+	if out, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).CombinedOutput(); err != nil {
+		return fmt.Errorf("powershell toast: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package appstate
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// mtgaRawCardDBEnvVar points at Arena's card database when it is installed
+// somewhere MTGARawCardDBPath does not look.
+const mtgaRawCardDBEnvVar = "MTGA_RAW_CARD_DB"
+
+// staleLogAge is how long Player.log can go unwritten before doctor asks
+// whether Arena is running; a day covers anyone who plays daily.
+const staleLogAge = 24 * time.Hour
+
+// Arena writes one of these near the top of Player.log at launch.
+var (
+	detailedLogsEnabled  = []byte("DETAILED LOGS: ENABLED")
+	detailedLogsDisabled = []byte("DETAILED LOGS: DISABLED")
+)
+
+// Environment check statuses. A warning is worth knowing but does not stop
+// ponder from working.
+const (
+	CheckOK   = "ok"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// EnvironmentCheck is one setup check doctor runs, with the fix to try when
+// it did not pass.
+type EnvironmentCheck struct {
+	Name   string
+	Status string
+	Detail string
+	Fix    string
+}
+
+// CheckEnvironment checks what ponder needs from the machine: that
+// Player.log exists, that Arena writes detailed logs into it, that it has
+// been written recently, and that Arena's card database can be found.
+// Database checks need a store and are left to the caller.
+func CheckEnvironment(logPath string, now time.Time) []EnvironmentCheck {
+	return []EnvironmentCheck{
+		checkLogFile(logPath),
+		checkDetailedLogs(logPath),
+		checkLogFresh(logPath, now),
+		checkRawCardDB(),
+	}
+}
+
+func checkLogFile(logPath string) EnvironmentCheck {
+	check := EnvironmentCheck{Name: "player_log"}
+	info, err := os.Stat(logPath)
+	switch {
+	case err == nil && !info.IsDir():
+		check.Status = CheckOK
+		check.Detail = fmt.Sprintf("%s, %d bytes", logPath, info.Size())
+	case err == nil || errors.Is(err, os.ErrNotExist):
+		check.Status = CheckFail
+		check.Detail = logPath + " not found"
+		check.Fix = "launch Arena once so it creates the log, or pass -log with where your Player.log is"
+	default:
+		check.Status = CheckFail
+		check.Detail = err.Error()
+		check.Fix = "make sure this user can read the Arena log folder"
+	}
+	return check
+}
+
+func checkDetailedLogs(logPath string) EnvironmentCheck {
+	check := EnvironmentCheck{Name: "detailed_logs"}
+	enabled, found := detailedLogsSetting(logPath)
+	switch {
+	case found && enabled:
+		check.Status = CheckOK
+		check.Detail = "Arena is writing detailed logs"
+	case found:
+		check.Status = CheckFail
+		check.Detail = "Arena is not writing detailed logs, so matches, decks, and drafts are missing from Player.log"
+		check.Fix = "in Arena, open Options > Account, check Detailed Logs (Plugin Support), and restart Arena"
+	default:
+		check.Status = CheckWarn
+		check.Detail = "could not tell whether detailed logs are on; Player.log has no DETAILED LOGS line"
+		check.Fix = "restart Arena and run doctor again; if it still says this, check Options > Account > Detailed Logs (Plugin Support)"
+	}
+	return check
+}
+
+// detailedLogsSetting scans the log for the line Arena writes at launch. The
+// last one wins, since the setting can change between launches.
+func detailedLogsSetting(logPath string) (enabled, found bool) {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return false, false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if bytes.Contains(line, detailedLogsEnabled) {
+			enabled, found = true, true
+		} else if bytes.Contains(line, detailedLogsDisabled) {
+			enabled, found = false, true
+		}
+	}
+	return enabled, found
+}
+
+func checkLogFresh(logPath string, now time.Time) EnvironmentCheck {
+	check := EnvironmentCheck{Name: "log_growing"}
+	info, err := os.Stat(logPath)
+	if err != nil {
+		check.Status = CheckWarn
+		check.Detail = "no log to check"
+		return check
+	}
+	age := now.Sub(info.ModTime())
+	check.Detail = fmt.Sprintf("last written %s ago", age.Round(time.Second))
+	if age <= staleLogAge {
+		check.Status = CheckOK
+		return check
+	}
+	check.Status = CheckWarn
+	check.Fix = "Arena has not written to this log in over a day; if Arena is running, it may be logging somewhere else, so compare with the paths listed above and pass -log"
+	return check
+}
+
+func checkRawCardDB() EnvironmentCheck {
+	check := EnvironmentCheck{Name: "card_database"}
+	if path := MTGARawCardDBPath(); path != "" {
+		check.Status = CheckOK
+		check.Detail = path
+		return check
+	}
+	check.Status = CheckWarn
+	check.Detail = "Arena's Raw_CardDatabase file was not found; card names and colors come from Scryfall instead, which needs network access"
+	check.Fix = "set " + mtgaRawCardDBEnvVar + " to the Raw_CardDatabase_*.mtga file in Arena's Downloads/Raw folder, or run `ponder cards sync` while online"
+	return check
+}
+
+// MTGARawCardDBPath finds Arena's bundled card database
+// (Raw_CardDatabase_*.mtga), newest first, or returns "" when Arena is not
+// installed where we look. MTGA_RAW_CARD_DB overrides the search.
+func MTGARawCardDBPath() string {
+	explicit := strings.TrimSpace(os.Getenv(mtgaRawCardDBEnvVar))
+	if explicit != "" {
+		if fi, err := os.Stat(explicit); err == nil && !fi.IsDir() {
+			return explicit
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	patterns := []string{
+		filepath.Join(home, "Library", "Application Support", "com.wizards.mtga", "Downloads", "Raw", "Raw_CardDatabase*.mtga"),
+		filepath.Join(home, "AppData", "LocalLow", "Wizards Of The Coast", "MTGA", "Downloads", "Raw", "Raw_CardDatabase*.mtga"),
+	}
+
+	var newestPath string
+	var newestMod time.Time
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			fi, err := os.Stat(match)
+			if err != nil || fi.IsDir() {
+				continue
+			}
+			if newestPath == "" || fi.ModTime().After(newestMod) {
+				newestPath = match
+				newestMod = fi.ModTime()
+			}
+		}
+	}
+
+	return newestPath
+}
@@ -0,0 +1,56 @@
+package appstate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckEnvironmentReadsDetailedLogsSetting(t *testing.T) {
+	t.Setenv(mtgaRawCardDBEnvVar, "")
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "Player.log")
+	now := time.Now()
+
+	statuses := func() map[string]string {
+		out := map[string]string{}
+		for _, check := range CheckEnvironment(logPath, now) {
+			out[check.Name] = check.Status
+		}
+		return out
+	}
+
+	if got := statuses(); got["player_log"] != CheckFail || got["detailed_logs"] != CheckWarn {
+		t.Fatalf("missing log statuses = %v, want player_log fail and detailed_logs warn", got)
+	}
+
+	// The last launch's setting wins.
+	content := "DETAILED LOGS: ENABLED\nsome lines\nDETAILED LOGS: DISABLED\n"
+	if err := os.WriteFile(logPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	if got := statuses(); got["player_log"] != CheckOK || got["detailed_logs"] != CheckFail || got["log_growing"] != CheckOK {
+		t.Fatalf("disabled statuses = %v, want player_log ok, detailed_logs fail, log_growing ok", got)
+	}
+
+	if err := os.WriteFile(logPath, []byte(content+"DETAILED LOGS: ENABLED\n"), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	old := now.Add(-2 * staleLogAge)
+	if err := os.Chtimes(logPath, old, old); err != nil {
+		t.Fatalf("age log: %v", err)
+	}
+	if got := statuses(); got["detailed_logs"] != CheckOK || got["log_growing"] != CheckWarn {
+		t.Fatalf("enabled statuses = %v, want detailed_logs ok and log_growing warn", got)
+	}
+
+	cardDB := filepath.Join(dir, "Raw_CardDatabase_test.mtga")
+	if err := os.WriteFile(cardDB, nil, 0o644); err != nil {
+		t.Fatalf("write card db: %v", err)
+	}
+	t.Setenv(mtgaRawCardDBEnvVar, cardDB)
+	if got := statuses(); got["card_database"] != CheckOK {
+		t.Fatalf("card_database with %s set = %q, want ok", mtgaRawCardDBEnvVar, got["card_database"])
+	}
+}
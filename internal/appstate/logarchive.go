@@ -0,0 +1,122 @@
+package appstate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/solean/ponder/pkg/ingest"
+)
+
+const (
+	// Arena keeps one previous log: each launch renames Player.log to
+	// Player-prev.log and drops the older one. Archiving both at least once
+	// per game session is enough to keep every line.
+	prevLogName = "Player-prev.log"
+
+	logArchivePrefix     = "Player-"
+	logArchiveSuffix     = ".log"
+	logArchiveTimeLayout = "20060102T150405Z"
+)
+
+// LogArchiveSources lists the logs worth archiving for the log at logPath:
+// the Player-prev.log beside it, when there is one, then logPath itself.
+func LogArchiveSources(logPath string) []string {
+	sources := make([]string, 0, 2)
+	prev := filepath.Join(filepath.Dir(logPath), prevLogName)
+	if prev != logPath {
+		if info, err := os.Stat(prev); err == nil && info.Mode().IsRegular() {
+			sources = append(sources, prev)
+		}
+	}
+	return append(sources, logPath)
+}
+
+// ArchiveLog copies the log at src into dir under a name taken from the
+// log's first timestamp, such as Player-20240301T170000Z.log. A log keeps
+// its name after Arena renames it to Player-prev.log, so archiving again
+// only appends the lines written since the last copy. A log with no
+// timestamp yet is skipped; added is the number of bytes written.
+func ArchiveLog(src, dir string) (dest string, added int64, err error) {
+	startedAt, ok := ingest.LogStartTime(src, time.Local)
+	if !ok {
+		return "", 0, nil
+	}
+	dest = filepath.Join(dir, logArchivePrefix+startedAt.UTC().Format(logArchiveTimeLayout)+logArchiveSuffix)
+
+	in, err := os.Open(src)
+	if err != nil {
+		return dest, 0, fmt.Errorf("open log: %w", err)
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return dest, 0, fmt.Errorf("stat log: %w", err)
+	}
+
+	var have int64
+	if existing, err := os.Stat(dest); err == nil {
+		have = existing.Size()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return dest, 0, fmt.Errorf("stat archive: %w", err)
+	}
+	if have >= info.Size() {
+		return dest, 0, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return dest, 0, fmt.Errorf("create archive dir: %w", err)
+	}
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return dest, 0, fmt.Errorf("open archive: %w", err)
+	}
+	defer out.Close()
+	if _, err := in.Seek(have, io.SeekStart); err != nil {
+		return dest, 0, fmt.Errorf("seek log: %w", err)
+	}
+	added, err = io.Copy(out, io.LimitReader(in, info.Size()-have))
+	if err != nil {
+		return dest, added, fmt.Errorf("copy log: %w", err)
+	}
+	if err := out.Sync(); err != nil {
+		return dest, added, fmt.Errorf("sync archive: %w", err)
+	}
+	return dest, added, out.Close()
+}
+
+// LogArchiver archives logs at most once per Every, for loops that wake far
+// more often than a copy is worth.
+type LogArchiver struct {
+	Dir   string
+	Every time.Duration
+
+	last time.Time
+}
+
+// MaybeArchive calls Archive when Every has passed since the last run.
+func (a *LogArchiver) MaybeArchive(sources []string, now time.Time) ([]string, error) {
+	if !a.last.IsZero() && now.Sub(a.last) < a.Every {
+		return nil, nil
+	}
+	return a.Archive(sources, now)
+}
+
+// Archive archives each source now, returning the archives that grew.
+func (a *LogArchiver) Archive(sources []string, now time.Time) ([]string, error) {
+	a.last = now
+	var grown []string
+	for _, src := range sources {
+		dest, added, err := ArchiveLog(src, a.Dir)
+		if err != nil {
+			return grown, fmt.Errorf("archive %s: %w", src, err)
+		}
+		if added > 0 {
+			grown = append(grown, dest)
+		}
+	}
+	return grown, nil
+}
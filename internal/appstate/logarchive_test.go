@@ -0,0 +1,62 @@
+package appstate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveLogFollowsRotation(t *testing.T) {
+	logDir := t.TempDir()
+	archiveDir := filepath.Join(t.TempDir(), "archive")
+	current := filepath.Join(logDir, "Player.log")
+	first := "[UnityCrossThreadLogger]3/1/2024 9:00:00 AM\nline one\n"
+	if err := os.WriteFile(current, []byte(first), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	archiver := &LogArchiver{Dir: archiveDir, Every: time.Hour}
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	grown, err := archiver.MaybeArchive(LogArchiveSources(current), now)
+	if err != nil || len(grown) != 1 {
+		t.Fatalf("first archive = %q, %v; want one archive", grown, err)
+	}
+	archived := grown[0]
+
+	// Arena writes more, then relaunches: the log becomes Player-prev.log and
+	// a new Player.log starts.
+	second := first + "line two\n"
+	if err := os.WriteFile(current, []byte(second), 0o644); err != nil {
+		t.Fatalf("grow log: %v", err)
+	}
+	if grown, _ := archiver.MaybeArchive(LogArchiveSources(current), now.Add(time.Minute)); len(grown) != 0 {
+		t.Fatalf("archive inside Every = %q, want it throttled", grown)
+	}
+	if err := os.Rename(current, filepath.Join(logDir, "Player-prev.log")); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if err := os.WriteFile(current, []byte("[UnityCrossThreadLogger]3/2/2024 8:00:00 PM\nnew session\n"), 0o644); err != nil {
+		t.Fatalf("write new log: %v", err)
+	}
+
+	grown, err = archiver.Archive(LogArchiveSources(current), now.Add(2*time.Minute))
+	if err != nil || len(grown) != 2 || grown[0] != archived {
+		t.Fatalf("archive after rotation = %q, %v; want %s topped up, then the new log", grown, err, archived)
+	}
+	got, err := os.ReadFile(archived)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	if string(got) != second {
+		t.Fatalf("archive = %q, want %q", got, second)
+	}
+
+	entries, err := os.ReadDir(archiveDir)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("archive dir = %v, %v; want two logs", entries, err)
+	}
+	if grown, _ := archiver.Archive(LogArchiveSources(current), now.Add(3*time.Minute)); len(grown) != 0 {
+		t.Fatalf("archive with nothing new = %q, want nothing copied", grown)
+	}
+}
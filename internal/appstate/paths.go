@@ -5,24 +5,130 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+
+	"github.com/solean/ponder/pkg/ingest"
 )
 
+// DefaultMTGALogPaths returns where Arena writes Player.log and
+// Player-prev.log on this platform: the first candidate directory that holds
+// a log, or the most likely one when none does yet.
 func DefaultMTGALogPaths() (current, prev string, err error) {
-	home, err := os.UserHomeDir()
+	candidates, err := MTGALogDirCandidates()
 	if err != nil {
-		return "", "", fmt.Errorf("resolve user home dir: %w", err)
+		return "", "", err
+	}
+	base := ""
+	for _, candidate := range candidates {
+		if candidate.Found {
+			base = candidate.Dir
+			break
+		}
+		if base == "" && !hasGlobMeta(candidate.Dir) {
+			base = candidate.Dir
+		}
 	}
-	base := filepath.Join(home, "Library", "Logs", "Wizards Of The Coast", "MTGA")
 	current = filepath.Join(base, "Player.log")
 	prev = filepath.Join(base, "Player-prev.log")
 	return current, prev, nil
 }
 
+// arenaSteamAppID is MTG Arena's Steam app id, which names its Proton
+// prefix under steamapps/compatdata.
+const arenaSteamAppID = "2141910"
+
+// LogDirCandidate is one place Arena's logs were looked for. Dir may still
+// be a glob pattern when nothing matched it.
+type LogDirCandidate struct {
+	Dir   string
+	Found bool
+}
+
+// MTGALogDirCandidates lists every directory Arena may log to on this
+// platform, most likely first, and whether each holds a Player.log.
+func MTGALogDirCandidates() ([]LogDirCandidate, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("resolve user home dir: %w", err)
+	}
+	return probeLogDirs(mtgaLogDirPatterns(runtime.GOOS, home, os.Getenv)), nil
+}
+
+// mtgaLogDirPatterns lists where Arena may log on goos, most likely first.
+// On Windows home is %USERPROFILE% and Arena logs under AppData\LocalLow. On
+// Linux Arena runs in a Wine prefix (Steam Proton, Lutris, or plain Wine),
+// where the log sits at the same place under drive_c; Wine prefixes name the
+// user directory after the login, so those patterns glob it.
+func mtgaLogDirPatterns(goos, home string, getenv func(string) string) []string {
+	const arenaLogs = "AppData/LocalLow/Wizards Of The Coast/MTGA"
+	inPrefix := func(prefix, user string) string {
+		return filepath.Join(prefix, "drive_c", "users", user, filepath.FromSlash(arenaLogs))
+	}
+	switch goos {
+	case "windows":
+		return []string{filepath.Join(home, filepath.FromSlash(arenaLogs))}
+	case "linux":
+		var patterns []string
+		if prefix := strings.TrimSpace(getenv("WINEPREFIX")); prefix != "" {
+			patterns = append(patterns, inPrefix(prefix, "*"))
+		}
+		for _, steamRoot := range []string{
+			filepath.Join(home, ".local", "share", "Steam"),
+			filepath.Join(home, ".steam", "steam"),
+			filepath.Join(home, ".var", "app", "com.valvesoftware.Steam", ".local", "share", "Steam"),
+		} {
+			prefix := filepath.Join(steamRoot, "steamapps", "compatdata", arenaSteamAppID, "pfx")
+			patterns = append(patterns, inPrefix(prefix, "steamuser"))
+		}
+		return append(patterns,
+			inPrefix(filepath.Join(home, "Games", "magic-the-gathering-arena"), "*"),
+			inPrefix(filepath.Join(home, ".wine"), "*"),
+		)
+	default:
+		return []string{filepath.Join(home, "Library", "Logs", "Wizards Of The Coast", "MTGA")}
+	}
+}
+
+// probeLogDirs expands patterns in order and marks the directories holding
+// a Player.log. A pattern that matches nothing is kept as-is so a report can
+// show it was checked.
+func probeLogDirs(patterns []string) []LogDirCandidate {
+	var out []LogDirCandidate
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		dirs := []string{pattern}
+		if hasGlobMeta(pattern) {
+			if matches, err := filepath.Glob(pattern); err == nil && len(matches) > 0 {
+				dirs = matches
+			}
+		}
+		for _, dir := range dirs {
+			if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+				if seen[resolved] {
+					continue
+				}
+				seen[resolved] = true
+			}
+			info, err := os.Stat(filepath.Join(dir, "Player.log"))
+			out = append(out, LogDirCandidate{Dir: dir, Found: err == nil && !info.IsDir()})
+		}
+	}
+	return out
+}
+
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// ResolveParseLogPaths lists the logs parse should read. An explicit path may
+// be a single log, a directory of archived Player.log copies, or a glob;
+// directories and globs are read oldest log first. Without one, the platform's
+// Player-prev.log (when includePrev) and Player.log are used.
 func ResolveParseLogPaths(explicitPath string, includePrev bool) ([]string, error) {
 	explicitPath = strings.TrimSpace(explicitPath)
 	if explicitPath != "" {
-		return []string{explicitPath}, nil
+		return resolveExplicitLogPaths(explicitPath)
 	}
 
 	current, prev, err := DefaultMTGALogPaths()
@@ -53,9 +159,68 @@ func ResolveParseLogPaths(explicitPath string, includePrev bool) ([]string, erro
 
 	if len(found) == 0 {
 		return nil, fmt.Errorf(
-			"no default MTGA logs found in ~/Library/Logs/Wizards Of The Coast/MTGA (use a custom log path)",
+			"no default MTGA logs found in %s (use a custom log path)", filepath.Dir(current),
 		)
 	}
 
 	return found, nil
 }
+
+func resolveExplicitLogPaths(path string) ([]string, error) {
+	var matches []string
+	switch info, err := os.Stat(path); {
+	case err == nil && info.IsDir():
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("read log directory: %w", err)
+		}
+		for _, entry := range entries {
+			if isArchivedLogName(entry.Name()) {
+				matches = append(matches, filepath.Join(path, entry.Name()))
+			}
+		}
+	case err == nil:
+		return []string{path}, nil
+	case hasGlobMeta(path):
+		globbed, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("log pattern %s: %w", path, err)
+		}
+		matches = globbed
+	default:
+		// Let the parser report the missing file as before.
+		return []string{path}, nil
+	}
+
+	files := matches[:0]
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+			files = append(files, match)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no log files found in %s", path)
+	}
+	return ingest.SortLogsChronologically(files), nil
+}
+
+// isArchivedLogName accepts the names Arena logs are saved under:
+// Player.log, Player-prev.log, Player-2024-05-01.log, or a rotated
+// Player.log.1. Compressed copies are left out since the parser reads plain
+// text.
+func isArchivedLogName(name string) bool {
+	lower := strings.ToLower(name)
+	if strings.HasSuffix(lower, ".log") {
+		return true
+	}
+	_, suffix, ok := strings.Cut(lower, ".log.")
+	if !ok || suffix == "" {
+		return false
+	}
+	for _, r := range suffix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
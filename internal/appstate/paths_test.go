@@ -0,0 +1,108 @@
+package appstate
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMTGALogDirPatternsPerPlatform(t *testing.T) {
+	home := filepath.Join("home", "player")
+	noEnv := func(string) string { return "" }
+	cases := []struct {
+		goos string
+		want string
+	}{
+		{"darwin", filepath.Join(home, "Library", "Logs", "Wizards Of The Coast", "MTGA")},
+		{"windows", filepath.Join(home, "AppData", "LocalLow", "Wizards Of The Coast", "MTGA")},
+	}
+	for _, tc := range cases {
+		got := mtgaLogDirPatterns(tc.goos, home, noEnv)
+		if len(got) != 1 || got[0] != tc.want {
+			t.Fatalf("mtgaLogDirPatterns(%s) = %q, want [%q]", tc.goos, got, tc.want)
+		}
+	}
+
+	linux := mtgaLogDirPatterns("linux", home, func(key string) string {
+		if key == "WINEPREFIX" {
+			return "/prefixes/arena"
+		}
+		return ""
+	})
+	want := filepath.Join("/prefixes/arena", "drive_c", "users", "*", "AppData", "LocalLow", "Wizards Of The Coast", "MTGA")
+	if len(linux) < 2 || linux[0] != want {
+		t.Fatalf("linux patterns = %q, want $WINEPREFIX first (%q)", linux, want)
+	}
+	proton := filepath.Join(home, ".local", "share", "Steam", "steamapps", "compatdata", arenaSteamAppID, "pfx",
+		"drive_c", "users", "steamuser", "AppData", "LocalLow", "Wizards Of The Coast", "MTGA")
+	if linux[1] != proton {
+		t.Fatalf("linux patterns[1] = %q, want the Steam Proton prefix %q", linux[1], proton)
+	}
+}
+
+func TestProbeLogDirsFindsLogInWinePrefix(t *testing.T) {
+	home := t.TempDir()
+	lutrisLogs := filepath.Join(home, "Games", "magic-the-gathering-arena", "drive_c", "users", "player",
+		"AppData", "LocalLow", "Wizards Of The Coast", "MTGA")
+	if err := os.MkdirAll(lutrisLogs, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(lutrisLogs, "Player.log"), []byte("log"), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	candidates := probeLogDirs(mtgaLogDirPatterns("linux", home, func(string) string { return "" }))
+	var found []string
+	for _, candidate := range candidates {
+		if candidate.Found {
+			found = append(found, candidate.Dir)
+		}
+	}
+	if len(found) != 1 || found[0] != lutrisLogs {
+		t.Fatalf("found = %q, want only the Lutris prefix %q (checked %+v)", found, lutrisLogs, candidates)
+	}
+	// The Steam locations were still checked and reported.
+	if len(candidates) < 4 || candidates[0].Found {
+		t.Fatalf("candidates = %+v, want the Proton prefixes listed ahead of Lutris", candidates)
+	}
+}
+
+func TestResolveParseLogPathsOrdersArchivedLogs(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return path
+	}
+	// Names sort in the opposite order to when the logs were written.
+	march := write("a-Player.log", "Initialize engine\n[UnityCrossThreadLogger]3/1/2024 9:00:00 AM\n")
+	january := write("b-Player.log.1", "[UnityCrossThreadLogger]1/15/2024 11:30:00 PM\n")
+	february := write("c-Player-prev.log", "[UnityCrossThreadLogger]2/2/2024 1:05:00 PM\n")
+	write("d-Player.log.gz", "compressed")
+	write("notes.txt", "not a log")
+
+	want := []string{january, february, march}
+	for _, arg := range []string{dir, filepath.Join(dir, "*Player*")} {
+		got, err := ResolveParseLogPaths(arg, true)
+		if err != nil {
+			t.Fatalf("ResolveParseLogPaths(%s): %v", arg, err)
+		}
+		if arg == dir && !slices.Equal(got, want) {
+			t.Fatalf("directory = %q, want %q", got, want)
+		}
+		if arg != dir && (len(got) != 4 || got[0] != january) {
+			t.Fatalf("glob = %q, want every match, January first", got)
+		}
+	}
+
+	if _, err := ResolveParseLogPaths(filepath.Join(dir, "*.none"), true); err == nil {
+		t.Fatalf("glob matching nothing succeeded, want an error")
+	}
+	if got, err := ResolveParseLogPaths(march, true); err != nil || len(got) != 1 || got[0] != march {
+		t.Fatalf("single file = %q, %v; want it alone", got, err)
+	}
+}
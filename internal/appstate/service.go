@@ -6,14 +6,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/solean/ponder/internal/db"
-	"github.com/solean/ponder/internal/ingest"
-	"github.com/solean/ponder/internal/model"
 	"github.com/solean/ponder/internal/version"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/ingest"
+	"github.com/solean/ponder/pkg/model"
 )
 
 const defaultPollInterval = 2 * time.Second
@@ -29,6 +30,12 @@ type Options struct {
 	DefaultPrevLogPath  string
 	DefaultPollInterval time.Duration
 	Capabilities        Capabilities
+	// Notifier receives background failures such as a failed nightly
+	// backup; nil logs them.
+	Notifier Notifier
+	// EventSink receives what live tracking stores, such as a finished
+	// match, for webhooks; nil reports nothing.
+	EventSink ingest.EventSink
 }
 
 // Capabilities advertises native-shell integrations available to the frontend.
@@ -44,6 +51,15 @@ type Config struct {
 	IncludePrev         bool   `json:"includePrev"`
 	AutoStartLive       bool   `json:"autoStartLive"`
 	AutoCheckUpdates    bool   `json:"autoCheckUpdates"`
+	// BackupDir overrides where nightly backups go (default: "backups" next
+	// to the database). BackupKeep is how many to retain (0 means the
+	// default of 7; negative disables nightly backups).
+	BackupDir  string `json:"backupDir,omitempty"`
+	BackupKeep int    `json:"backupKeep,omitempty"`
+	// DBPath relocates the desktop app's database (default: ponder.db in the
+	// support dir). It is written by `ponder migrate-db` and takes effect on
+	// the next launch, so UpdateConfig keeps the value config.json holds.
+	DBPath string `json:"dbPath,omitempty"`
 }
 
 // UpdateCheck is the outcome of a GitHub release check. CheckedAt lets the UI
@@ -95,6 +111,8 @@ type Status struct {
 	LastError             string           `json:"lastError,omitempty"`
 	Capabilities          Capabilities     `json:"capabilities"`
 	UpdateCheck           *UpdateCheck     `json:"updateCheck,omitempty"`
+	BackupDir             string           `json:"backupDir"`
+	LastBackup            *BackupResult    `json:"lastBackup,omitempty"`
 }
 
 type Service struct {
@@ -106,6 +124,12 @@ type Service struct {
 	defaultPrevLogPath string
 	defaultPoll        time.Duration
 	capabilities       Capabilities
+	notifier           Notifier
+	eventSink          ingest.EventSink
+
+	// configMu serializes the read-modify-write of config.json in
+	// UpdateConfig.
+	configMu sync.Mutex
 
 	mu               sync.RWMutex
 	config           Config
@@ -118,6 +142,7 @@ type Service struct {
 	lastLiveActivity *OperationResult
 	lastError        string
 	lastUpdateCheck  *UpdateCheck
+	lastBackup       *BackupResult
 }
 
 func NewService(opts Options) (*Service, error) {
@@ -153,24 +178,19 @@ func NewService(opts Options) (*Service, error) {
 		}
 	}
 
+	notifier := opts.Notifier
+	if notifier == nil {
+		notifier = logNotifier{}
+	}
+
 	poll := opts.DefaultPollInterval
 	if poll <= 0 {
 		poll = defaultPollInterval
 	}
 
-	cfg := Config{
-		PollIntervalSeconds: max(1, int(poll.Round(time.Second)/time.Second)),
-		IncludePrev:         true,
-		AutoCheckUpdates:    true,
-	}
-
-	if raw, err := os.ReadFile(configPath); err == nil {
-		var saved Config
-		if unmarshalErr := json.Unmarshal(raw, &saved); unmarshalErr == nil {
-			cfg = normalizeConfig(saved, poll)
-		}
-	} else if !os.IsNotExist(err) {
-		return nil, fmt.Errorf("read appstate config: %w", err)
+	cfg, err := readConfig(configPath, poll)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Service{
@@ -182,6 +202,8 @@ func NewService(opts Options) (*Service, error) {
 		defaultPrevLogPath: prevLogPath,
 		defaultPoll:        poll,
 		capabilities:       opts.Capabilities,
+		notifier:           notifier,
+		eventSink:          opts.EventSink,
 		config:             normalizeConfig(cfg, poll),
 	}, nil
 }
@@ -200,6 +222,11 @@ func (s *Service) Status() Status {
 		cloned := *s.lastUpdateCheck
 		lastUpdateCheck = &cloned
 	}
+	var lastBackup *BackupResult
+	if s.lastBackup != nil {
+		cloned := *s.lastBackup
+		lastBackup = &cloned
+	}
 	s.mu.RUnlock()
 
 	activeLogPath := strings.TrimSpace(cfg.LogPath)
@@ -233,6 +260,8 @@ func (s *Service) Status() Status {
 		LastError:             lastError,
 		Capabilities:          s.capabilities,
 		UpdateCheck:           lastUpdateCheck,
+		BackupDir:             s.backupDir(cfg),
+		LastBackup:            lastBackup,
 	}
 }
 
@@ -280,12 +309,13 @@ func (s Status) RevealablePaths() []string {
 		s.PreviousLogPath,
 		s.DefaultLogPath,
 		s.DefaultPrevLogPath,
+		s.BackupDir,
 	}
 }
 
 func (s *Service) UpdateConfig(next Config) (Status, error) {
-	cfg := normalizeConfig(next, s.defaultPoll)
-	if err := s.saveConfig(cfg); err != nil {
+	cfg, err := s.saveConfigKeepingDBPath(next)
+	if err != nil {
 		return s.Status(), err
 	}
 
@@ -375,6 +405,7 @@ func (s *Service) StartLive() (Status, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan struct{})
 	parser := ingest.NewParser(s.store)
+	parser.SetEventSink(s.eventSink)
 	startedAt := time.Now().UTC()
 
 	s.mu.Lock()
@@ -432,7 +463,7 @@ func (s *Service) runLiveLoop(
 	defer close(done)
 
 	runTick := func() bool {
-		stats, err := parser.ParseFile(ctx, activeLogPath, true)
+		results, err := parser.TailFile(ctx, activeLogPath)
 		now := time.Now().UTC()
 
 		s.mu.Lock()
@@ -443,8 +474,12 @@ func (s *Service) runLiveLoop(
 			return false
 		}
 
-		result := summarizeOperation("live", []string{activeLogPath}, []model.ParseStats{stats})
-		result.HasActivity = hasActivity(stats)
+		paths := make([]string, 0, len(results))
+		for _, stats := range results {
+			paths = append(paths, stats.LogPath)
+		}
+		result := summarizeOperation("live", paths, results)
+		result.HasActivity = slices.ContainsFunc(results, hasActivity)
 		if result.HasActivity {
 			s.lastLiveActivity = cloneOperationResult(&result)
 			s.lastError = ""
@@ -453,30 +488,68 @@ func (s *Service) runLiveLoop(
 		return true
 	}
 
+	// Watch before the first parse so nothing written in between is missed.
+	wake, _ := ingest.WatchLog(ctx, activeLogPath, poll)
 	runTick()
 
-	ticker := time.NewTicker(poll)
-	defer ticker.Stop()
-
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-wake:
 			runTick()
 		}
 	}
 }
 
-func (s *Service) saveConfig(cfg Config) error {
-	if err := os.MkdirAll(filepath.Dir(s.configPath), 0o755); err != nil {
+// saveConfigKeepingDBPath writes next with the dbPath config.json holds
+// now rather than the one loaded at startup, so a `ponder migrate-db` run
+// while the app is open is not undone by the next settings save.
+func (s *Service) saveConfigKeepingDBPath(next Config) (Config, error) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	saved, err := readConfig(s.configPath, s.defaultPoll)
+	if err != nil {
+		return Config{}, err
+	}
+	next.DBPath = saved.DBPath
+	cfg := normalizeConfig(next, s.defaultPoll)
+	if err := writeConfig(s.configPath, cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// readConfig loads config.json, falling back to defaults when it is missing
+// or unreadable as JSON.
+func readConfig(configPath string, poll time.Duration) (Config, error) {
+	cfg := Config{
+		PollIntervalSeconds: max(1, int(poll.Round(time.Second)/time.Second)),
+		IncludePrev:         true,
+		AutoCheckUpdates:    true,
+	}
+
+	if raw, err := os.ReadFile(configPath); err == nil {
+		var saved Config
+		if unmarshalErr := json.Unmarshal(raw, &saved); unmarshalErr == nil {
+			cfg = normalizeConfig(saved, poll)
+		}
+	} else if !os.IsNotExist(err) {
+		return Config{}, fmt.Errorf("read appstate config: %w", err)
+	}
+	return cfg, nil
+}
+
+func writeConfig(configPath string, cfg Config) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
 		return fmt.Errorf("create config dir: %w", err)
 	}
 	payload, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal config: %w", err)
 	}
-	if err := os.WriteFile(s.configPath, append(payload, '\n'), 0o644); err != nil {
+	if err := os.WriteFile(configPath, append(payload, '\n'), 0o644); err != nil {
 		return fmt.Errorf("write config: %w", err)
 	}
 	return nil
@@ -509,8 +582,59 @@ func supportDirPath(base string) string {
 	return filepath.Join(base, supportDirName)
 }
 
+// DesktopDBPath returns the database the desktop app opens: the config's
+// dbPath when set, otherwise ponder.db in supportDir.
+func DesktopDBPath(supportDir string) (string, error) {
+	cfg, err := readConfig(filepath.Join(supportDir, "config.json"), defaultPollInterval)
+	if err != nil {
+		return "", err
+	}
+	return desktopDBPath(supportDir, cfg), nil
+}
+
+func desktopDBPath(supportDir string, cfg Config) string {
+	if cfg.DBPath != "" {
+		return cfg.DBPath
+	}
+	return filepath.Join(supportDir, "ponder.db")
+}
+
+// RecordDBMove points the desktop config at a migrated database. The config
+// is only rewritten when it currently resolves to from, so migrating some
+// other file leaves the desktop app alone; the return reports whether it
+// changed.
+func RecordDBMove(supportDir, from, to string) (bool, error) {
+	configPath := filepath.Join(supportDir, "config.json")
+	cfg, err := readConfig(configPath, defaultPollInterval)
+	if err != nil {
+		return false, err
+	}
+	current, err := filepath.Abs(desktopDBPath(supportDir, cfg))
+	if err != nil {
+		return false, fmt.Errorf("resolve desktop db path: %w", err)
+	}
+	fromAbs, err := filepath.Abs(from)
+	if err != nil {
+		return false, fmt.Errorf("resolve source path: %w", err)
+	}
+	if current != fromAbs {
+		return false, nil
+	}
+	toAbs, err := filepath.Abs(to)
+	if err != nil {
+		return false, fmt.Errorf("resolve destination path: %w", err)
+	}
+	cfg.DBPath = toAbs
+	if err := writeConfig(configPath, cfg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func normalizeConfig(cfg Config, poll time.Duration) Config {
 	cfg.LogPath = strings.TrimSpace(cfg.LogPath)
+	cfg.BackupDir = strings.TrimSpace(cfg.BackupDir)
+	cfg.DBPath = strings.TrimSpace(cfg.DBPath)
 	if cfg.PollIntervalSeconds <= 0 {
 		cfg.PollIntervalSeconds = max(1, int(poll.Round(time.Second)/time.Second))
 	}
@@ -1,8 +1,11 @@
 package appstate
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
+
+	"github.com/solean/ponder/pkg/db"
 )
 
 func TestSupportDirPathUsesPonderName(t *testing.T) {
@@ -13,3 +16,57 @@ func TestSupportDirPathUsesPonderName(t *testing.T) {
 		t.Fatalf("support dir = %q, want %q", got, want)
 	}
 }
+
+func TestRecordDBMoveOnlyRepointsTheDesktopDatabase(t *testing.T) {
+	dir := t.TempDir()
+	defaultPath := filepath.Join(dir, "ponder.db")
+	moved := filepath.Join(dir, "elsewhere", "ponder.db")
+
+	updated, err := RecordDBMove(dir, filepath.Join(dir, "scratch.db"), moved)
+	if err != nil || updated {
+		t.Fatalf("RecordDBMove(unrelated) = %v, %v; want no change", updated, err)
+	}
+	if got, err := DesktopDBPath(dir); err != nil || got != defaultPath {
+		t.Fatalf("DesktopDBPath = %q, %v; want %q", got, err, defaultPath)
+	}
+
+	updated, err = RecordDBMove(dir, defaultPath, moved)
+	if err != nil || !updated {
+		t.Fatalf("RecordDBMove(default) = %v, %v; want update", updated, err)
+	}
+	if got, err := DesktopDBPath(dir); err != nil || got != moved {
+		t.Fatalf("DesktopDBPath = %q, %v; want %q", got, err, moved)
+	}
+}
+
+func TestUpdateConfigKeepsDBMoveRecordedWhileRunning(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "ponder.db")
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	service, err := NewService(Options{Store: db.NewStore(database), DBPath: dbPath, SupportDir: dir})
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	// migrate-db runs in another process while the app is open.
+	moved := filepath.Join(dir, "elsewhere", "ponder.db")
+	if updated, err := RecordDBMove(dir, dbPath, moved); err != nil || !updated {
+		t.Fatalf("RecordDBMove = %v, %v; want update", updated, err)
+	}
+
+	if _, err := service.UpdateConfig(Config{BackupKeep: 3}); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if got, err := DesktopDBPath(dir); err != nil || got != moved {
+		t.Fatalf("DesktopDBPath = %q, %v; want %q", got, err, moved)
+	}
+}
@@ -0,0 +1,191 @@
+package appstate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/db"
+)
+
+// Event types tail can raise desktop notifications for, as passed to
+// -notify.
+const (
+	NotifyMatch     = "match"
+	NotifyDraft     = "draft"
+	NotifyMilestone = "milestone"
+)
+
+// NotifyEvents lists every event type in the order usage text shows them.
+var NotifyEvents = []string{NotifyMatch, NotifyDraft, NotifyMilestone}
+
+const (
+	// notifyRecent bounds how old a result can be and still notify, so a
+	// tail that starts behind a long backlog does not replay a session's
+	// worth of notifications.
+	notifyRecent = 15 * time.Minute
+	// notifyRankWait is how long a match notification waits for Arena to
+	// report the new rank, which follows the result by a few seconds.
+	notifyRankWait = 20 * time.Second
+	// notifyScanLimit is how many of the newest matches and drafts each
+	// check looks at; far more than finish between two parse passes.
+	notifyScanLimit = 20
+)
+
+// EventNotifier raises a notification for each match and draft that
+// finishes while tail runs. Check is called after every parse pass.
+type EventNotifier struct {
+	store    *db.Store
+	notifier Notifier
+	events   map[string]bool
+
+	notified map[string]bool
+	waiting  map[int64]time.Time
+	// milestonesAt is the newest finished match milestones were last
+	// computed for; they only change when another match finishes.
+	milestonesAt int64
+}
+
+// NewEventNotifier notifies about the given event types; unknown names are
+// rejected so a typo in -notify is not silently ignored.
+func NewEventNotifier(store *db.Store, notifier Notifier, events []string) (*EventNotifier, error) {
+	enabled := map[string]bool{}
+	for _, event := range events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if event == "" {
+			continue
+		}
+		if !slices.Contains(NotifyEvents, event) {
+			return nil, fmt.Errorf("unknown notification event %q (use %s)", event, strings.Join(NotifyEvents, ", "))
+		}
+		enabled[event] = true
+	}
+	return &EventNotifier{
+		store:    store,
+		notifier: notifier,
+		events:   enabled,
+		notified: map[string]bool{},
+		waiting:  map[int64]time.Time{},
+	}, nil
+}
+
+// Enabled reports whether any event type is turned on.
+func (n *EventNotifier) Enabled() bool {
+	return len(n.events) > 0
+}
+
+// Check notifies about matches and drafts that finished, and milestones
+// reached, since the last call. A match without its new rank yet is held
+// for up to notifyRankWait.
+func (n *EventNotifier) Check(ctx context.Context, now time.Time) error {
+	if n.events[NotifyMatch] {
+		matches, err := n.store.RecentFinishedMatches(ctx, notifyScanLimit)
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			key := fmt.Sprintf("match:%d", match.ID)
+			if n.notified[key] || !recentlyFinished(match.EndedAt, now) {
+				continue
+			}
+			if match.Rank == "" && match.Result != "draw" {
+				firstSeen, seen := n.waiting[match.ID]
+				if !seen {
+					n.waiting[match.ID] = now
+					continue
+				}
+				if now.Sub(firstSeen) < notifyRankWait {
+					continue
+				}
+			}
+			delete(n.waiting, match.ID)
+			n.notified[key] = true
+			n.notifier.Notify(matchNotification(match))
+		}
+	}
+	if n.events[NotifyDraft] {
+		drafts, err := n.store.RecentCompletedDrafts(ctx, notifyScanLimit)
+		if err != nil {
+			return err
+		}
+		for _, draft := range drafts {
+			key := fmt.Sprintf("draft:%d", draft.ID)
+			if n.notified[key] || !recentlyFinished(draft.CompletedAt, now) {
+				continue
+			}
+			n.notified[key] = true
+			n.notifier.Notify(draftNotification(draft))
+		}
+	}
+	if n.events[NotifyMilestone] {
+		if err := n.checkMilestones(ctx, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMilestones notifies about milestones reached by recently finished
+// matches. Opponent archetypes are the manual overrides only; tail has no
+// card metadata resolver to derive the rest.
+func (n *EventNotifier) checkMilestones(ctx context.Context, now time.Time) error {
+	latest, err := n.store.RecentFinishedMatches(ctx, 1)
+	if err != nil {
+		return err
+	}
+	if len(latest) == 0 || latest[0].ID == n.milestonesAt {
+		return nil
+	}
+	stats, err := n.store.ListMilestones(ctx, nil)
+	if err != nil {
+		return err
+	}
+	n.milestonesAt = latest[0].ID
+	for _, milestone := range stats.Milestones {
+		key := "milestone:" + milestone.Key
+		if n.notified[key] || !recentlyFinished(milestone.AchievedAt, now) {
+			continue
+		}
+		n.notified[key] = true
+		n.notifier.Notify(milestone.Title, milestone.Detail)
+	}
+	return nil
+}
+
+// recentlyFinished reports whether ts is within notifyRecent of now. An
+// unparseable time is treated as old.
+func recentlyFinished(ts string, now time.Time) bool {
+	parsed, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(ts))
+	if err != nil {
+		return false
+	}
+	return now.Sub(parsed) <= notifyRecent
+}
+
+func matchNotification(match db.FinishedMatch) (title, message string) {
+	outcome := map[string]string{"win": "Won", "loss": "Lost", "draw": "Drew"}[match.Result]
+	title = outcome
+	if match.Opponent != "" {
+		title = outcome + " vs " + match.Opponent
+	}
+	parts := []string{}
+	if event := eventnames.Display(match.EventName, eventnames.DefaultLanguage, ""); event != "" {
+		parts = append(parts, event)
+	}
+	if match.Rank != "" {
+		parts = append(parts, "Rank: "+match.Rank)
+	}
+	return title, strings.Join(parts, " · ")
+}
+
+func draftNotification(draft db.CompletedDraft) (title, message string) {
+	title = "Draft complete"
+	message = fmt.Sprintf("%d picks", draft.Picks)
+	if event := eventnames.Display(draft.EventName, eventnames.DefaultLanguage, ""); event != "" {
+		message = event + " · " + message
+	}
+	return title, message
+}
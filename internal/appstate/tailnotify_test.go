@@ -0,0 +1,110 @@
+package appstate
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+type recordingNotifier struct {
+	titles, messages []string
+}
+
+func (r *recordingNotifier) Notify(title, message string) {
+	r.titles = append(r.titles, title)
+	r.messages = append(r.messages, message)
+}
+
+func TestEventNotifierWaitsForRankThenNotifiesOnce(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "ponder.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	exec := func(query string) {
+		t.Helper()
+		if _, err := database.ExecContext(ctx, query); err != nil {
+			t.Fatalf("exec: %v", err)
+		}
+	}
+	exec(`INSERT INTO matches (id, arena_match_id, event_name, opponent_name, ended_at, result, created_at, updated_at) VALUES
+		(1, 'old', 'Ladder', 'Yesterday', '2024-03-04T20:00:00Z', 'win', 'x', 'x'),
+		(2, 'new', 'Ladder', 'Rival', '2024-03-05T20:00:00Z', 'loss', 'x', 'x')`)
+	exec(`INSERT INTO draft_sessions (id, event_name, draft_id, is_bot_draft, completed_at, created_at, updated_at)
+		VALUES (1, 'PremierDraft_BLB_20240801', 'd1', 0, '2024-03-05T19:30:00Z', 'x', 'x')`)
+
+	recorder := &recordingNotifier{}
+	if _, err := NewEventNotifier(db.NewStore(database), recorder, []string{"match", "rank"}); err == nil {
+		t.Fatalf("NewEventNotifier accepted an unknown event")
+	}
+	notifier, err := NewEventNotifier(db.NewStore(database), recorder, []string{"match", "draft"})
+	if err != nil {
+		t.Fatalf("NewEventNotifier: %v", err)
+	}
+
+	now := time.Date(2024, 3, 5, 20, 0, 5, 0, time.UTC)
+	if err := notifier.Check(ctx, now); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(recorder.titles) != 0 {
+		t.Fatalf("notified %v before the rank arrived", recorder.titles)
+	}
+
+	exec(`INSERT INTO match_rank_snapshots (match_id, payload_json, constructed_season_ordinal, constructed_rank_class, constructed_level, created_at, updated_at)
+		VALUES (2, '{}', 80, 'Gold', 2, 'x', 'x')`)
+	for i := 0; i < 2; i++ {
+		if err := notifier.Check(ctx, now.Add(time.Second)); err != nil {
+			t.Fatalf("Check: %v", err)
+		}
+	}
+	if len(recorder.titles) != 1 || recorder.titles[0] != "Lost vs Rival" || recorder.messages[0] != "Ranked · Rank: Gold 2" {
+		t.Fatalf("notifications = %q / %q, want one loss with its rank", recorder.titles, recorder.messages)
+	}
+}
+
+func TestEventNotifierAnnouncesRecentMilestonesOnce(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "ponder.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// The first streak of three is a day old; the second, a new best of
+	// four, ends now.
+	if _, err := database.ExecContext(ctx, `INSERT INTO matches (id, arena_match_id, event_name, started_at, ended_at, result, created_at, updated_at) VALUES
+		(1, 'a', 'Ladder', '2024-03-04T19:00:00Z', '2024-03-04T19:10:00Z', 'win', 'x', 'x'),
+		(2, 'b', 'Ladder', '2024-03-04T19:20:00Z', '2024-03-04T19:30:00Z', 'win', 'x', 'x'),
+		(3, 'c', 'Ladder', '2024-03-04T19:40:00Z', '2024-03-04T19:50:00Z', 'win', 'x', 'x'),
+		(4, 'd', 'Ladder', '2024-03-04T20:00:00Z', '2024-03-04T20:10:00Z', 'loss', 'x', 'x'),
+		(5, 'e', 'Ladder', '2024-03-05T19:00:00Z', '2024-03-05T19:10:00Z', 'win', 'x', 'x'),
+		(6, 'f', 'Ladder', '2024-03-05T19:20:00Z', '2024-03-05T19:30:00Z', 'win', 'x', 'x'),
+		(7, 'g', 'Ladder', '2024-03-05T19:40:00Z', '2024-03-05T19:50:00Z', 'win', 'x', 'x'),
+		(8, 'h', 'Ladder', '2024-03-05T20:00:00Z', '2024-03-05T20:10:00Z', 'win', 'x', 'x')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	recorder := &recordingNotifier{}
+	notifier, err := NewEventNotifier(db.NewStore(database), recorder, []string{"milestone"})
+	if err != nil {
+		t.Fatalf("NewEventNotifier: %v", err)
+	}
+	now := time.Date(2024, 3, 5, 20, 10, 5, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if err := notifier.Check(ctx, now); err != nil {
+			t.Fatalf("Check: %v", err)
+		}
+	}
+	if len(recorder.titles) != 1 || recorder.titles[0] != "New best win streak: 4" || recorder.messages[0] != "Ranked" {
+		t.Fatalf("notifications = %q / %q, want only the new best streak", recorder.titles, recorder.messages)
+	}
+}
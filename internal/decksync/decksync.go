@@ -0,0 +1,166 @@
+// Package decksync pushes stored decks to deckbuilding sites, for `ponder
+// deck push` and POST /api/decks/:id/push. A deck is sent as Arena import
+// text, which both Moxfield and Archidekt read, and the id the site assigns
+// is kept so later pushes update the same deck instead of creating copies.
+package decksync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const userAgent = "ponder/0.1 (local tracker)"
+
+// Service is one site decks can be pushed to.
+type Service struct {
+	// Name is how -service and the API name the site.
+	Name string
+	// APIURL is the deck collection endpoint: new decks are POSTed to it and
+	// a pushed deck is updated with a PUT to APIURL/<id>.
+	APIURL string
+	// DeckURL is the page of a pushed deck, with %s for its id.
+	DeckURL string
+	// ImportPage is where import text can be pasted by hand when no token
+	// is saved.
+	ImportPage string
+	// AuthScheme prefixes the token in the Authorization header.
+	AuthScheme string
+}
+
+// Services lists every site decks can be pushed to.
+var Services = []Service{
+	{
+		Name:       "moxfield",
+		APIURL:     "https://api2.moxfield.com/v2/decks",
+		DeckURL:    "https://www.moxfield.com/decks/%s",
+		ImportPage: "https://www.moxfield.com/decks/import",
+		AuthScheme: "Bearer",
+	},
+	{
+		Name:       "archidekt",
+		APIURL:     "https://archidekt.com/api/decks/v2",
+		DeckURL:    "https://archidekt.com/decks/%s",
+		ImportPage: "https://archidekt.com/import",
+		AuthScheme: "JWT",
+	},
+}
+
+// Lookup finds a service by name, ignoring case.
+func Lookup(name string) (Service, bool) {
+	for _, service := range Services {
+		if strings.EqualFold(service.Name, strings.TrimSpace(name)) {
+			return service, true
+		}
+	}
+	return Service{}, false
+}
+
+// Names lists the service names in the order usage text shows them.
+func Names() []string {
+	out := make([]string, 0, len(Services))
+	for _, service := range Services {
+		out = append(out, service.Name)
+	}
+	return out
+}
+
+// Deck is what a push sends.
+type Deck struct {
+	Name string
+	// Format is the deck's Arena format ("Standard", "Historic"), sent in
+	// lowercase; empty leaves the site's default.
+	Format string
+	// Text is the deck as Arena import text.
+	Text string
+}
+
+type pushRequest struct {
+	Name       string `json:"name"`
+	Format     string `json:"format,omitempty"`
+	ImportText string `json:"importText"`
+	Visibility string `json:"visibility"`
+}
+
+// Push creates the deck on the service, or replaces the list of the deck
+// with remoteID when one was pushed before, and returns the deck's id on the
+// service. New decks are created private.
+func Push(ctx context.Context, client *http.Client, service Service, token, remoteID string, deck Deck) (string, error) {
+	if strings.TrimSpace(token) == "" {
+		return "", fmt.Errorf("no %s token", service.Name)
+	}
+	body, err := json.Marshal(pushRequest{
+		Name:       deck.Name,
+		Format:     strings.ToLower(strings.TrimSpace(deck.Format)),
+		ImportText: deck.Text,
+		Visibility: "private",
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode %s deck: %w", service.Name, err)
+	}
+	method, endpoint := http.MethodPost, service.APIURL
+	if remoteID != "" {
+		method, endpoint = http.MethodPut, strings.TrimRight(service.APIURL, "/")+"/"+url.PathEscape(remoteID)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("build %s request: %w", service.Name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Authorization", service.AuthScheme+" "+strings.TrimSpace(token))
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("push to %s: %w", service.Name, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return "", fmt.Errorf("%s status %d: %s", service.Name, res.StatusCode, strings.TrimSpace(string(text)))
+	}
+	if remoteID != "" {
+		return remoteID, nil
+	}
+	var created struct {
+		PublicID string          `json:"publicId"`
+		ID       json.RawMessage `json:"id"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("decode %s response: %w", service.Name, err)
+	}
+	if created.PublicID != "" {
+		return created.PublicID, nil
+	}
+	if id := rawID(created.ID); id != "" {
+		return id, nil
+	}
+	return "", fmt.Errorf("%s response has no deck id", service.Name)
+}
+
+// rawID reads an id sent as either a JSON string or a number.
+func rawID(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var number int64
+	if err := json.Unmarshal(raw, &number); err == nil && number > 0 {
+		return strconv.FormatInt(number, 10)
+	}
+	return ""
+}
+
+// PageURL is the page of a pushed deck on the service.
+func (s Service) PageURL(remoteID string) string {
+	return fmt.Sprintf(s.DeckURL, url.PathEscape(remoteID))
+}
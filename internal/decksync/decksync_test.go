@@ -0,0 +1,61 @@
+package decksync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPushCreatesThenUpdates(t *testing.T) {
+	t.Parallel()
+
+	var methods, paths, auths []string
+	var sent pushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		paths = append(paths, r.URL.Path)
+		auths = append(auths, r.Header.Get("Authorization"))
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"id": 4812, "name": "Burn"}`))
+	}))
+	defer server.Close()
+
+	service, ok := Lookup("Archidekt")
+	if !ok {
+		t.Fatalf("archidekt not found")
+	}
+	service.APIURL = server.URL + "/api/decks/v2"
+	deck := Deck{Name: "Burn", Format: "Standard", Text: "Deck\n4 Lightning Strike (DMU) 137\n"}
+
+	id, err := Push(context.Background(), server.Client(), service, "tok", "", deck)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if id != "4812" {
+		t.Fatalf("created id = %q, want 4812", id)
+	}
+	if sent.Name != "Burn" || sent.Format != "standard" || sent.ImportText != deck.Text || sent.Visibility != "private" {
+		t.Fatalf("sent = %+v", sent)
+	}
+
+	if id, err = Push(context.Background(), server.Client(), service, "tok", id, deck); err != nil || id != "4812" {
+		t.Fatalf("update = %q, %v", id, err)
+	}
+	if methods[0] != http.MethodPost || paths[0] != "/api/decks/v2" || methods[1] != http.MethodPut || paths[1] != "/api/decks/v2/4812" {
+		t.Fatalf("requests = %v %v, want a POST to the collection then a PUT to the deck", methods, paths)
+	}
+	if auths[0] != "JWT tok" {
+		t.Fatalf("authorization = %q, want JWT tok", auths[0])
+	}
+	if got := service.PageURL(id); got != "https://archidekt.com/decks/4812" {
+		t.Fatalf("page url = %q", got)
+	}
+
+	if _, err := Push(context.Background(), server.Client(), service, " ", "", deck); err == nil {
+		t.Fatalf("push without a token succeeded")
+	}
+}
@@ -0,0 +1,151 @@
+// Package eventnames turns raw Arena event identifiers such as
+// "PremierDraft_BLB_20240730" into display names like
+// "Premier Draft: Bloomburrow". Kind labels come from an embedded
+// translations file keyed by language; set names are supplied by the caller
+// from the set catalog.
+package eventnames
+
+import (
+	_ "embed"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// DefaultLanguage is used when a request names no supported language, and
+// fills in any kind a translation leaves out.
+const DefaultLanguage = "en"
+
+//go:embed translations.json
+var translationsJSON []byte
+
+var translations = mustLoadTranslations(translationsJSON)
+
+func mustLoadTranslations(data []byte) map[string]map[string]string {
+	out := map[string]map[string]string{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		panic("eventnames: invalid translations.json: " + err.Error())
+	}
+	return out
+}
+
+var (
+	// Arena event names embed dates as bare YYYYMMDD tokens.
+	dateToken = regexp.MustCompile(`^\d{8}$`)
+	// Set codes are short all-caps alphanumerics (TMT, FIN, Y25); mixed-case
+	// type words like "QuickDraft" never match. Mirrors web/src/lib/events.ts.
+	setCodeToken = regexp.MustCompile(`^[A-Z0-9]{2,5}$`)
+)
+
+// SetCode extracts the set code from an event name like
+// "QuickDraft_TMT_20260313" or "FIN_Quick_Draft"; empty when there is none.
+func SetCode(eventName string) string {
+	for _, token := range strings.Split(eventName, "_") {
+		if dateToken.MatchString(token) {
+			continue
+		}
+		if setCodeToken.MatchString(token) {
+			return token
+		}
+	}
+	return ""
+}
+
+// Kind classifies an event name into a translation key, or "" when the name
+// matches no known event family. Order matters: more specific patterns come
+// before broader ones, as in web/src/lib/events.ts.
+func Kind(eventName string) string {
+	n := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(eventName), "_", ""))
+	switch {
+	case n == "":
+		return ""
+	case strings.Contains(n, "traddraft") || strings.Contains(n, "traditionaldraft"):
+		return "traditional_draft"
+	case strings.Contains(n, "quickdraft"):
+		return "quick_draft"
+	case strings.Contains(n, "premierdraft"):
+		return "premier_draft"
+	case strings.Contains(n, "botdraft"):
+		return "bot_draft"
+	case strings.Contains(n, "playerdraft"):
+		return "player_draft"
+	case strings.Contains(n, "draft"):
+		return "draft"
+	case strings.Contains(n, "tradsealed") || strings.Contains(n, "traditionalsealed"):
+		return "traditional_sealed"
+	case strings.Contains(n, "premiersealed"):
+		return "premier_sealed"
+	case strings.Contains(n, "sealed"):
+		return "sealed"
+	case strings.Contains(n, "jumpin"):
+		return "jump_in"
+	case strings.Contains(n, "tradladder") || strings.Contains(n, "traditionalladder"):
+		return "traditional_ladder"
+	case strings.Contains(n, "ladder"):
+		return "ladder"
+	case strings.Contains(n, "midweek") || strings.HasPrefix(n, "mwm"):
+		return "midweek_magic"
+	case strings.Contains(n, "brawl"):
+		return "brawl"
+	case n == "play":
+		return "play"
+	default:
+		return ""
+	}
+}
+
+// Language picks the supported language for a request: an explicit lang
+// value wins, then the first supported tag in an Accept-Language header.
+func Language(lang, acceptLanguage string) string {
+	if l := supported(lang); l != "" {
+		return l
+	}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		if l := supported(tag); l != "" {
+			return l
+		}
+	}
+	return DefaultLanguage
+}
+
+func supported(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	primary, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	if _, ok := translations[primary]; ok {
+		return primary
+	}
+	return ""
+}
+
+// KindLabel names an event family returned by Kind in lang, or "" for an
+// unknown kind.
+func KindLabel(kind, lang string) string {
+	if label := translations[lang][kind]; label != "" {
+		return label
+	}
+	return translations[DefaultLanguage][kind]
+}
+
+// Display renders eventName in lang. setName is the catalog name for the
+// event's set code; when empty the code itself is shown. Names that match no
+// known event family fall back to the raw name with underscores spaced out.
+func Display(eventName, lang, setName string) string {
+	eventName = strings.TrimSpace(eventName)
+	if eventName == "" {
+		return ""
+	}
+	kind := Kind(eventName)
+	if kind == "" {
+		return strings.ReplaceAll(eventName, "_", " ")
+	}
+	label := KindLabel(kind, lang)
+	set := strings.TrimSpace(setName)
+	if set == "" {
+		set = SetCode(eventName)
+	}
+	if set == "" {
+		return label
+	}
+	return label + ": " + set
+}
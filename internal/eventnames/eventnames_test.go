@@ -0,0 +1,48 @@
+package eventnames
+
+import "testing"
+
+func TestDisplay(t *testing.T) {
+	cases := []struct {
+		raw, lang, setName, want string
+	}{
+		{"PremierDraft_BLB_20240730", "en", "Bloomburrow", "Premier Draft: Bloomburrow"},
+		{"PremierDraft_BLB_20240730", "en", "", "Premier Draft: BLB"},
+		{"FIN_Quick_Draft", "de", "Final Fantasy", "Schneller Draft: Final Fantasy"},
+		{"Traditional_Ladder", "fr", "", "Classé traditionnel"},
+		{"Ladder", "xx", "", "Ranked"},
+		{"Some_New_Event", "en", "", "Some New Event"},
+		{"", "en", "", ""},
+	}
+	for _, tc := range cases {
+		if got := Display(tc.raw, tc.lang, tc.setName); got != tc.want {
+			t.Errorf("Display(%q, %q, %q) = %q, want %q", tc.raw, tc.lang, tc.setName, got, tc.want)
+		}
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	cases := []struct {
+		lang, accept, want string
+	}{
+		{"", "", "en"},
+		{"fr", "de-DE,de;q=0.9", "fr"},
+		{"", "pt-BR, es-419;q=0.8", "es"},
+		{"zz", "ja", "en"},
+	}
+	for _, tc := range cases {
+		if got := Language(tc.lang, tc.accept); got != tc.want {
+			t.Errorf("Language(%q, %q) = %q, want %q", tc.lang, tc.accept, got, tc.want)
+		}
+	}
+}
+
+func TestEveryTranslationCoversEnglishKinds(t *testing.T) {
+	for lang, labels := range translations {
+		for kind := range translations[DefaultLanguage] {
+			if labels[kind] == "" {
+				t.Errorf("%s translation missing %q", lang, kind)
+			}
+		}
+	}
+}
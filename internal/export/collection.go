@@ -0,0 +1,82 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// CollectionFormats are the CSV layouts collection-tracking sites import.
+// They only apply to the collection table.
+var CollectionFormats = []string{"mtggoldfish", "deckbox"}
+
+// IsCollectionFormat reports whether format is one of CollectionFormats.
+func IsCollectionFormat(format string) bool {
+	format = normalizeFormat(format)
+	for _, known := range CollectionFormats {
+		if format == known {
+			return true
+		}
+	}
+	return false
+}
+
+// collectionLayout is one site's import columns. Arena has no foils, so
+// every card is written as a regular printing.
+type collectionLayout struct {
+	header []string
+	record func(row model.CollectionExportRow) []string
+}
+
+var collectionLayouts = map[string]collectionLayout{
+	"mtggoldfish": {
+		header: []string{"Card", "Set ID", "Set Name", "Quantity", "Foil"},
+		record: func(row model.CollectionExportRow) []string {
+			return []string{row.Name, strings.ToUpper(row.SetCode), row.SetName, strconv.FormatInt(row.Quantity, 10), "REGULAR"}
+		},
+	},
+	"deckbox": {
+		header: []string{"Count", "Tradelist Count", "Name", "Edition", "Card Number", "Condition", "Language", "Foil"},
+		record: func(row model.CollectionExportRow) []string {
+			edition := row.SetName
+			if edition == "" {
+				edition = strings.ToUpper(row.SetCode)
+			}
+			return []string{strconv.FormatInt(row.Quantity, 10), "0", row.Name, edition, row.CollectorNumber, "Near Mint", "English", ""}
+		},
+	},
+}
+
+// collectionWriter writes collection rows in one site's layout.
+type collectionWriter struct {
+	w           *csv.Writer
+	layout      collectionLayout
+	wroteHeader bool
+}
+
+func (c *collectionWriter) Write(row any) error {
+	card, ok := row.(model.CollectionExportRow)
+	if !ok {
+		return fmt.Errorf("collection formats only export the collection table, got %T", row)
+	}
+	if !c.wroteHeader {
+		if err := c.w.Write(c.layout.header); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+	return c.w.Write(c.layout.record(card))
+}
+
+func (c *collectionWriter) Close() error {
+	if !c.wroteHeader {
+		if err := c.w.Write(c.layout.header); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
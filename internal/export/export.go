@@ -0,0 +1,206 @@
+// Package export encodes rows for offline analysis as NDJSON, a JSON array,
+// CSV, or Parquet, and the collection in the CSV layouts MTGGoldfish and
+// Deckbox import. It is shared by the /api/export endpoints and `ponder
+// export`.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Formats lists the accepted format names; "" means ndjson.
+var Formats = []string{"ndjson", "json", "csv", "parquet"}
+
+// Writer encodes one row per Write call. Close finishes the document (the
+// closing bracket of a JSON array, buffered CSV) but does not close the
+// underlying io.Writer.
+type Writer interface {
+	Write(row any) error
+	Close() error
+}
+
+// NewWriter returns a Writer for format, or an error naming the accepted
+// formats.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch normalizeFormat(format) {
+	case "ndjson":
+		return &jsonWriter{w: w, enc: json.NewEncoder(w)}, nil
+	case "json":
+		return &jsonWriter{w: w, enc: json.NewEncoder(w), array: true}, nil
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case "parquet":
+		return &parquetWriter{w: w}, nil
+	}
+	if layout, ok := collectionLayouts[normalizeFormat(format)]; ok {
+		return &collectionWriter{w: csv.NewWriter(w), layout: layout}, nil
+	}
+	return nil, fmt.Errorf("invalid format %q (use %s, or %s for the collection)", format, strings.Join(Formats, ", "), strings.Join(CollectionFormats, ", "))
+}
+
+// Extension is the file extension, with its dot, for format.
+func Extension(format string) string {
+	if IsCollectionFormat(format) {
+		return ".csv"
+	}
+	return "." + normalizeFormat(format)
+}
+
+// ContentType is the MIME type for format.
+func ContentType(format string) string {
+	switch normalizeFormat(format) {
+	case "json":
+		return "application/json"
+	case "csv", "mtggoldfish", "deckbox":
+		return "text/csv; charset=utf-8"
+	case "parquet":
+		return "application/vnd.apache.parquet"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+func normalizeFormat(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		return "ndjson"
+	}
+	return format
+}
+
+type jsonWriter struct {
+	w     io.Writer
+	enc   *json.Encoder
+	array bool
+	rows  int
+}
+
+func (j *jsonWriter) Write(row any) error {
+	if j.array {
+		sep := ","
+		if j.rows == 0 {
+			sep = "["
+		}
+		if _, err := io.WriteString(j.w, sep); err != nil {
+			return err
+		}
+	}
+	j.rows++
+	return j.enc.Encode(row)
+}
+
+func (j *jsonWriter) Close() error {
+	if !j.array {
+		return nil
+	}
+	end := "]\n"
+	if j.rows == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// csvWriter takes its header from the first row's struct fields, named by
+// their json tags with embedded structs flattened. Nil pointers are empty
+// cells; slices, maps, and nested structs are written as JSON.
+type csvWriter struct {
+	w      *csv.Writer
+	fields [][]int
+	record []string
+}
+
+func (c *csvWriter) Write(row any) error {
+	value := reflect.Indirect(reflect.ValueOf(row))
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("csv export needs struct rows, got %T", row)
+	}
+	if c.fields == nil {
+		var header []string
+		c.fields, header = csvColumns(value.Type(), nil)
+		if err := c.w.Write(header); err != nil {
+			return err
+		}
+		c.record = make([]string, len(c.fields))
+	}
+	for i, index := range c.fields {
+		cell, err := csvCell(value.FieldByIndex(index))
+		if err != nil {
+			return err
+		}
+		c.record[i] = cell
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func csvColumns(t reflect.Type, prefix []int) ([][]int, []string) {
+	var indexes [][]int
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		index := append(append([]int{}, prefix...), i)
+		name, _, _ := strings.Cut(tag, ",")
+		// Like encoding/json, an embedded struct's exported fields are
+		// promoted even when the embedded type itself is unexported.
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			nested, nestedNames := csvColumns(field.Type, index)
+			indexes = append(indexes, nested...)
+			names = append(names, nestedNames...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		indexes = append(indexes, index)
+		names = append(names, name)
+	}
+	return indexes, names
+}
+
+func csvCell(value reflect.Value) (string, error) {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "", nil
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64), nil
+	default:
+		if (value.Kind() == reflect.Slice || value.Kind() == reflect.Map) && value.IsNil() {
+			return "", nil
+		}
+		encoded, err := json.Marshal(value.Interface())
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	}
+}
@@ -0,0 +1,68 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+type csvInner struct {
+	Turn int `json:"turn"`
+}
+
+type csvRow struct {
+	ID    int64    `json:"id"`
+	Name  *string  `json:"name,omitempty"`
+	Cards []int64  `json:"cards"`
+	Skip  string   `json:"-"`
+	Ratio *float64 `json:"ratio"`
+	csvInner
+}
+
+func TestCSVWriterFlattensRows(t *testing.T) {
+	t.Parallel()
+
+	name := "Mono Red"
+	ratio := 0.5
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, "CSV")
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for _, row := range []csvRow{
+		{ID: 1, Name: &name, Cards: []int64{7, 8}, Skip: "x", Ratio: &ratio, csvInner: csvInner{Turn: 3}},
+		{ID: 2},
+	} {
+		if err := writer.Write(row); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	want := "id,name,cards,ratio,turn\n" +
+		"1,Mono Red,\"[7,8]\",0.5,3\n" +
+		"2,,,,0\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("csv =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNewWriterRejectsUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewWriter(&bytes.Buffer{}, "xlsx"); err == nil {
+		t.Fatalf("NewWriter(xlsx) succeeded, want an error")
+	}
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, "json")
+	if err != nil {
+		t.Fatalf("NewWriter(json): %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if buf.String() != "[]\n" {
+		t.Fatalf("empty json export = %q, want []", buf.String())
+	}
+}
@@ -0,0 +1,432 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// parquetRowGroupRows is how many rows a parquet writer buffers before
+// writing them out as one row group, bounding its memory on long exports.
+const parquetRowGroupRows = 50000
+
+var parquetMagic = []byte("PAR1")
+
+// Parquet physical types, encodings, and other enum values from the format's
+// Thrift definitions.
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetOptional      = 1
+	parquetConvertedUTF8 = 0
+	parquetPlain         = 0
+	parquetRLE           = 3
+	parquetUncompressed  = 0
+	parquetDataPage      = 0
+)
+
+// parquetWriter writes an uncompressed Parquet file with one optional column
+// per CSV column: strings as UTF-8 byte arrays, integers as INT64, floats as
+// DOUBLE, and bools as BOOLEAN. Nil pointers, slices, and maps are nulls;
+// other slices, maps, and nested structs are written as JSON strings. Rows
+// are buffered per column and written a row group at a time, and Close
+// writes the footer that makes the file readable.
+type parquetWriter struct {
+	w       io.Writer
+	offset  int64
+	fields  [][]int
+	columns []*parquetColumn
+	rows    int64
+	total   int64
+	groups  []parquetRowGroup
+}
+
+type parquetColumn struct {
+	name     string
+	physical int32
+	utf8     bool
+	defined  []bool
+	values   bytes.Buffer
+	bools    []bool
+}
+
+type parquetRowGroup struct {
+	rows   int64
+	size   int64
+	chunks []parquetChunk
+}
+
+type parquetChunk struct {
+	offset int64
+	size   int64
+	values int64
+}
+
+func (p *parquetWriter) Write(row any) error {
+	value := reflect.Indirect(reflect.ValueOf(row))
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("parquet export needs struct rows, got %T", row)
+	}
+	if p.fields == nil {
+		var names []string
+		p.fields, names = csvColumns(value.Type(), nil)
+		p.columns = make([]*parquetColumn, len(p.fields))
+		for i, index := range p.fields {
+			p.columns[i] = newParquetColumn(names[i], value.Type().FieldByIndex(index).Type)
+		}
+	}
+	for i, index := range p.fields {
+		if err := p.columns[i].add(value.FieldByIndex(index)); err != nil {
+			return err
+		}
+	}
+	p.rows++
+	if p.rows >= parquetRowGroupRows {
+		return p.flushRowGroup()
+	}
+	return nil
+}
+
+func (p *parquetWriter) Close() error {
+	if p.rows > 0 {
+		if err := p.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+	if err := p.start(); err != nil {
+		return err
+	}
+	footer := p.fileMetaData()
+	if err := p.emit(footer); err != nil {
+		return err
+	}
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	if err := p.emit(length[:]); err != nil {
+		return err
+	}
+	return p.emit(parquetMagic)
+}
+
+// start writes the leading magic number before the first row group.
+func (p *parquetWriter) start() error {
+	if p.offset > 0 {
+		return nil
+	}
+	return p.emit(parquetMagic)
+}
+
+func (p *parquetWriter) emit(data []byte) error {
+	n, err := p.w.Write(data)
+	p.offset += int64(n)
+	return err
+}
+
+// flushRowGroup writes each buffered column as a chunk of one data page.
+func (p *parquetWriter) flushRowGroup() error {
+	if err := p.start(); err != nil {
+		return err
+	}
+	group := parquetRowGroup{rows: p.rows}
+	for _, column := range p.columns {
+		page := column.page()
+		header := parquetPageHeader(len(column.defined), len(page))
+		chunk := parquetChunk{offset: p.offset, size: int64(len(header) + len(page)), values: int64(len(column.defined))}
+		if err := p.emit(header); err != nil {
+			return err
+		}
+		if err := p.emit(page); err != nil {
+			return err
+		}
+		group.size += chunk.size
+		group.chunks = append(group.chunks, chunk)
+		column.reset()
+	}
+	p.groups = append(p.groups, group)
+	p.total += p.rows
+	p.rows = 0
+	return nil
+}
+
+func newParquetColumn(name string, typ reflect.Type) *parquetColumn {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	column := &parquetColumn{name: name}
+	switch typ.Kind() {
+	case reflect.Bool:
+		column.physical = parquetBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		column.physical = parquetInt64
+	case reflect.Float32, reflect.Float64:
+		column.physical = parquetDouble
+	default:
+		column.physical = parquetByteArray
+		column.utf8 = true
+	}
+	return column
+}
+
+// add appends one cell, following csvCell's handling of nils and nested
+// values.
+func (c *parquetColumn) add(value reflect.Value) error {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			c.defined = append(c.defined, false)
+			return nil
+		}
+		value = value.Elem()
+	}
+	if (value.Kind() == reflect.Slice || value.Kind() == reflect.Map) && value.IsNil() {
+		c.defined = append(c.defined, false)
+		return nil
+	}
+	c.defined = append(c.defined, true)
+
+	var scratch [8]byte
+	switch c.physical {
+	case parquetBoolean:
+		c.bools = append(c.bools, value.Bool())
+	case parquetInt64:
+		n := int64(0)
+		if value.CanInt() {
+			n = value.Int()
+		} else {
+			n = int64(value.Uint())
+		}
+		binary.LittleEndian.PutUint64(scratch[:], uint64(n))
+		c.values.Write(scratch[:])
+	case parquetDouble:
+		binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(value.Float()))
+		c.values.Write(scratch[:])
+	default:
+		text := ""
+		if value.Kind() == reflect.String {
+			text = value.String()
+		} else {
+			encoded, err := json.Marshal(value.Interface())
+			if err != nil {
+				return err
+			}
+			text = string(encoded)
+		}
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(len(text)))
+		c.values.Write(scratch[:4])
+		c.values.WriteString(text)
+	}
+	return nil
+}
+
+// page is the column's data page body: definition levels, RLE-encoded with
+// a 4-byte length prefix, then the non-null values PLAIN-encoded.
+func (c *parquetColumn) page() []byte {
+	var levels bytes.Buffer
+	var scratch [binary.MaxVarintLen64]byte
+	for start := 0; start < len(c.defined); {
+		end := start + 1
+		for end < len(c.defined) && c.defined[end] == c.defined[start] {
+			end++
+		}
+		levels.Write(scratch[:binary.PutUvarint(scratch[:], uint64(end-start)<<1)])
+		if c.defined[start] {
+			levels.WriteByte(1)
+		} else {
+			levels.WriteByte(0)
+		}
+		start = end
+	}
+
+	var page bytes.Buffer
+	binary.LittleEndian.PutUint32(scratch[:4], uint32(levels.Len()))
+	page.Write(scratch[:4])
+	page.Write(levels.Bytes())
+	if c.physical == parquetBoolean {
+		packed := make([]byte, (len(c.bools)+7)/8)
+		for i, v := range c.bools {
+			if v {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		page.Write(packed)
+	} else {
+		page.Write(c.values.Bytes())
+	}
+	return page.Bytes()
+}
+
+func (c *parquetColumn) reset() {
+	c.defined = c.defined[:0]
+	c.bools = c.bools[:0]
+	c.values.Reset()
+}
+
+func parquetPageHeader(values, size int) []byte {
+	var t thriftCompact
+	t.i32(1, parquetDataPage)
+	t.i32(2, int32(size))
+	t.i32(3, int32(size))
+	t.structBegin(5)
+	t.i32(1, int32(values))
+	t.i32(2, parquetPlain)
+	t.i32(3, parquetRLE)
+	t.i32(4, parquetRLE)
+	t.structEnd()
+	t.stop()
+	return t.buf.Bytes()
+}
+
+func (p *parquetWriter) fileMetaData() []byte {
+	var t thriftCompact
+	t.i32(1, 1)
+	t.listBegin(2, thriftStruct, len(p.columns)+1)
+	t.elemBegin()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(p.columns)))
+	t.elemEnd()
+	for _, column := range p.columns {
+		t.elemBegin()
+		t.i32(1, column.physical)
+		t.i32(3, parquetOptional)
+		t.binary(4, column.name)
+		if column.utf8 {
+			t.i32(6, parquetConvertedUTF8)
+		}
+		t.elemEnd()
+	}
+	t.i64(3, p.total)
+	t.listBegin(4, thriftStruct, len(p.groups))
+	for _, group := range p.groups {
+		t.elemBegin()
+		t.listBegin(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			column := p.columns[i]
+			t.elemBegin()
+			t.i64(2, chunk.offset)
+			t.structBegin(3)
+			t.i32(1, column.physical)
+			t.listBegin(2, thriftI32, 2)
+			t.elemI32(parquetPlain)
+			t.elemI32(parquetRLE)
+			t.listBegin(3, thriftBinary, 1)
+			t.elemBinary(column.name)
+			t.i32(4, parquetUncompressed)
+			t.i64(5, chunk.values)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset)
+			t.structEnd()
+			t.elemEnd()
+		}
+		t.i64(2, group.size)
+		t.i64(3, group.rows)
+		t.elemEnd()
+	}
+	t.binary(6, "ponder")
+	t.stop()
+	return t.buf.Bytes()
+}
+
+// Thrift compact protocol type ids.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftCompact encodes the Thrift compact protocol, just enough of it for
+// Parquet's page headers and footer. Fields must be written in ascending id
+// order within each struct.
+type thriftCompact struct {
+	buf    bytes.Buffer
+	lastID int16
+	stack  []int16
+}
+
+func (t *thriftCompact) fieldHeader(id int16, typ byte) {
+	if delta := id - t.lastID; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	t.lastID = id
+}
+
+func (t *thriftCompact) uvarint(v uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	t.buf.Write(scratch[:binary.PutUvarint(scratch[:], v)])
+}
+
+// varint writes v zigzag-encoded.
+func (t *thriftCompact) varint(v int64) {
+	t.uvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (t *thriftCompact) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftCompact) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftCompact) binary(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	t.elemBinary(v)
+}
+
+func (t *thriftCompact) structBegin(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.elemBegin()
+}
+
+func (t *thriftCompact) structEnd() {
+	t.elemEnd()
+}
+
+func (t *thriftCompact) listBegin(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	t.buf.WriteByte(0xf0 | elemType)
+	t.uvarint(uint64(size))
+}
+
+// elemBegin and elemEnd bracket a struct written as a list element.
+func (t *thriftCompact) elemBegin() {
+	t.stack = append(t.stack, t.lastID)
+	t.lastID = 0
+}
+
+func (t *thriftCompact) elemEnd() {
+	t.stop()
+	t.lastID = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+func (t *thriftCompact) elemI32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftCompact) elemBinary(v string) {
+	t.uvarint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+func (t *thriftCompact) stop() {
+	t.buf.WriteByte(0)
+}
@@ -0,0 +1,146 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestParquetWriterWritesReadableColumns(t *testing.T) {
+	t.Parallel()
+
+	name := "Mono Red"
+	ratio := 0.5
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, "parquet")
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for _, row := range []csvRow{
+		{ID: 1, Name: &name, Cards: []int64{7, 8}, Ratio: &ratio, csvInner: csvInner{Turn: 3}},
+		{ID: 2},
+	} {
+		if err := writer.Write(row); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	file := buf.Bytes()
+	if !bytes.HasPrefix(file, parquetMagic) || !bytes.HasSuffix(file, parquetMagic) {
+		t.Fatalf("file does not start and end with PAR1")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := thriftReader{data: file[len(file)-8-footerLen : len(file)-8]}
+	meta := footer.readStruct()
+	if footer.pos != footerLen {
+		t.Fatalf("footer decoded %d of %d bytes", footer.pos, footerLen)
+	}
+	if meta[3] != int64(2) {
+		t.Fatalf("num_rows = %v, want 2", meta[3])
+	}
+
+	schema := meta[2].([]any)
+	wantColumns := []struct {
+		name     string
+		physical int64
+	}{{"id", parquetInt64}, {"name", parquetByteArray}, {"cards", parquetByteArray}, {"ratio", parquetDouble}, {"turn", parquetInt64}}
+	if len(schema) != len(wantColumns)+1 || schema[0].(map[int16]any)[5] != int64(len(wantColumns)) {
+		t.Fatalf("schema = %v", schema)
+	}
+	for i, want := range wantColumns {
+		element := schema[i+1].(map[int16]any)
+		if string(element[4].([]byte)) != want.name || element[1] != want.physical {
+			t.Fatalf("column %d = %v, want %s of type %d", i, element, want.name, want.physical)
+		}
+	}
+
+	// Read back the name and ratio columns from their data pages.
+	groups := meta[4].([]any)
+	chunks := groups[0].(map[int16]any)[1].([]any)
+	page := func(column int) (levels []byte, values []byte) {
+		offset := chunks[column].(map[int16]any)[3].(map[int16]any)[9].(int64)
+		reader := thriftReader{data: file[offset:]}
+		header := reader.readStruct()
+		body := file[int(offset)+reader.pos:][:header[3].(int64)]
+		levelLen := int(binary.LittleEndian.Uint32(body))
+		return body[4 : 4+levelLen], body[4+levelLen:]
+	}
+	levels, values := page(1)
+	// One RLE run of one present value, then one of one null.
+	if !bytes.Equal(levels, []byte{2, 1, 2, 0}) {
+		t.Fatalf("name levels = %v", levels)
+	}
+	if !bytes.Equal(values, append([]byte{8, 0, 0, 0}, "Mono Red"...)) {
+		t.Fatalf("name values = %q", values)
+	}
+	if _, values := page(3); math.Float64frombits(binary.LittleEndian.Uint64(values)) != 0.5 || len(values) != 8 {
+		t.Fatalf("ratio values = %v", values)
+	}
+}
+
+// thriftReader decodes the Thrift compact protocol into maps keyed by field
+// id, lists, int64s, and byte slices, enough to check what parquetWriter
+// wrote.
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) readStruct() map[int16]any {
+	out := map[int16]any{}
+	var id int16
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return out
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.varint())
+		}
+		out[id] = r.readValue(header & 0x0f)
+	}
+}
+
+func (r *thriftReader) readValue(typ byte) any {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n := int(r.uvarint())
+		r.pos += n
+		return r.data[r.pos-n : r.pos]
+	case thriftList:
+		header := r.data[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		out := make([]any, size)
+		for i := range out {
+			out[i] = r.readValue(header & 0x0f)
+		}
+		return out
+	case thriftStruct:
+		return r.readStruct()
+	}
+	panic("unexpected thrift type")
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// Tables lists the tables StreamTable can stream.
+var Tables = []string{"matches", "card-plays", "decks", "draft-picks", "collection"}
+
+// TableKnown reports whether table is one of Tables.
+func TableKnown(table string) bool {
+	return slices.Contains(Tables, table)
+}
+
+// StreamTable streams every row of table that filter selects to write; the
+// collection is not tied to matches and ignores filter. It backs both
+// /api/export and `ponder export`, so the two always agree.
+func StreamTable(ctx context.Context, store *db.Store, table string, filter db.MatchFilter, write func(row any) error) error {
+	switch table {
+	case "matches":
+		return store.StreamMatches(ctx, filter, func(row model.MatchRow) error { return write(row) })
+	case "card-plays":
+		return store.StreamCardPlays(ctx, filter, func(row model.CardPlayExportRow) error { return write(row) })
+	case "decks":
+		return store.StreamDeckCards(ctx, filter, func(row model.DeckCardExportRow) error { return write(row) })
+	case "draft-picks":
+		return store.StreamDraftPicks(ctx, filter, func(row model.DraftPickExportRow) error { return write(row) })
+	case "collection":
+		return store.StreamCollection(ctx, func(row model.CollectionExportRow) error { return write(row) })
+	default:
+		return fmt.Errorf("unknown export table %q (use %s)", table, strings.Join(Tables, ", "))
+	}
+}
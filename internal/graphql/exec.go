@@ -0,0 +1,475 @@
+// Package graphql executes read-only GraphQL queries against plain Go
+// values. Object fields are the values' JSON field names, so a type's
+// GraphQL shape matches its REST payload; Schema.Fields adds computed
+// relationship fields on top. Mutations, subscriptions, and introspection
+// are not supported.
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// RootResolver resolves a top-level Query field.
+type RootResolver func(ctx context.Context, args Args) (any, error)
+
+// FieldResolver resolves a computed field on parent, which is the Go value
+// (struct, not pointer) being projected.
+type FieldResolver func(ctx context.Context, parent any, args Args) (any, error)
+
+// Schema maps root field names to resolvers and, per Go struct type, extra
+// fields beyond the struct's own JSON fields.
+type Schema struct {
+	Query  map[string]RootResolver
+	Fields map[reflect.Type]map[string]FieldResolver
+	// MaxDepth and MaxFields bound an operation before it runs: how deeply
+	// its fields may nest and how many it may select once fragments are
+	// expanded. Zero means no limit.
+	MaxDepth  int
+	MaxFields int
+}
+
+// Request is the standard GraphQL-over-HTTP request body.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is the standard GraphQL result envelope.
+type Response struct {
+	Data   any     `json:"data,omitempty"`
+	Errors []Error `json:"errors,omitempty"`
+}
+
+// Error reports one failure; Path locates the field that was nulled.
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// Execute parses and runs req. Syntax and operation-selection errors return
+// no data; field errors null the failing field and are reported alongside
+// the rest of the result.
+func (s *Schema) Execute(ctx context.Context, req Request) Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+	if op.Type != "query" {
+		return Response{Errors: []Error{{Message: fmt.Sprintf("%s operations are not supported", op.Type)}}}
+	}
+	if err := s.checkLimits(doc, op); err != nil {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	variables := map[string]any{}
+	for _, def := range op.Variables {
+		if value, ok := req.Variables[def.Name]; ok {
+			variables[def.Name] = value
+		} else if def.HasDefault {
+			variables[def.Name] = def.Default.Resolve(nil)
+		}
+	}
+
+	e := &executor{schema: s, doc: doc, variables: variables}
+	data := e.executeRoot(ctx, op.Selections)
+	return Response{Data: data, Errors: e.errors}
+}
+
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	if name == "" {
+		if len(doc.Operations) != 1 {
+			return nil, fmt.Errorf("operationName is required when the document has several operations")
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// checkLimits rejects an operation whose fields nest deeper than MaxDepth
+// or number more than MaxFields. Fragments count wherever they are spread,
+// so one spread twice counts twice, and the walk stops at the first limit
+// crossed so a query built from nested spreads costs no more than the budget
+// to check. @skip and @include are not applied; the limits are on the
+// document as written.
+func (s *Schema) checkLimits(doc *Document, op *Operation) error {
+	if s.MaxDepth <= 0 && s.MaxFields <= 0 {
+		return nil
+	}
+	fields := 0
+	spreading := map[string]bool{}
+	var walk func(selections []Selection, depth int) error
+	walk = func(selections []Selection, depth int) error {
+		for _, selection := range selections {
+			switch {
+			case selection.Field != nil:
+				fields++
+				if s.MaxFields > 0 && fields > s.MaxFields {
+					return fmt.Errorf("query selects more than %d fields", s.MaxFields)
+				}
+				if s.MaxDepth > 0 && depth > s.MaxDepth {
+					return fmt.Errorf("query nests deeper than %d levels", s.MaxDepth)
+				}
+				if err := walk(selection.Field.Selections, depth+1); err != nil {
+					return err
+				}
+			case selection.Inline != nil:
+				if err := walk(selection.Inline.Selections, depth); err != nil {
+					return err
+				}
+			default:
+				fragment := doc.Fragments[selection.Spread]
+				if fragment == nil || spreading[selection.Spread] {
+					continue
+				}
+				spreading[selection.Spread] = true
+				err := walk(fragment.Selections, depth)
+				delete(spreading, selection.Spread)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return walk(op.Selections, 1)
+}
+
+type executor struct {
+	schema    *Schema
+	doc       *Document
+	variables map[string]any
+	errors    []Error
+}
+
+func (e *executor) fail(path []any, format string, args ...any) {
+	e.errors = append(e.errors, Error{Message: fmt.Sprintf(format, args...), Path: append([]any(nil), path...)})
+}
+
+func (e *executor) executeRoot(ctx context.Context, selections []Selection) *Object {
+	out := &Object{}
+	for _, group := range e.collectFields("Query", selections) {
+		field := group.fields[0]
+		path := []any{group.key}
+		if field.Name == "__typename" {
+			out.set(group.key, "Query")
+			continue
+		}
+		resolve, ok := e.schema.Query[field.Name]
+		if !ok {
+			e.fail(path, "Cannot query field %q on type \"Query\"", field.Name)
+			out.set(group.key, nil)
+			continue
+		}
+		value, err := resolve(ctx, e.arguments(field))
+		if err != nil {
+			e.fail(path, "%s", err.Error())
+			out.set(group.key, nil)
+			continue
+		}
+		out.set(group.key, e.complete(ctx, path, reflect.ValueOf(value), group.subSelections()))
+	}
+	return out
+}
+
+// complete projects value onto the selection set, recursing through
+// pointers, lists, and structs.
+func (e *executor) complete(ctx context.Context, path []any, value reflect.Value, selections []Selection) any {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		out := make([]any, value.Len())
+		for i := range out {
+			out[i] = e.complete(ctx, append(path[:len(path):len(path)], i), value.Index(i), selections)
+		}
+		return out
+	case reflect.Struct:
+		if len(selections) == 0 {
+			e.fail(path, "Field of type %q must have a selection of subfields", value.Type().Name())
+			return nil
+		}
+		return e.completeObject(ctx, path, value, selections)
+	default:
+		if len(selections) > 0 {
+			e.fail(path, "Field of type %q has no subfields", value.Type().String())
+			return nil
+		}
+		return value.Interface()
+	}
+}
+
+func (e *executor) completeObject(ctx context.Context, path []any, value reflect.Value, selections []Selection) *Object {
+	typ := value.Type()
+	typeName := typ.Name()
+	fields := jsonFields(typ)
+	extra := e.schema.Fields[typ]
+
+	out := &Object{}
+	for _, group := range e.collectFields(typeName, selections) {
+		field := group.fields[0]
+		fieldPath := append(path[:len(path):len(path)], group.key)
+		if field.Name == "__typename" {
+			out.set(group.key, typeName)
+			continue
+		}
+		if resolve, ok := extra[field.Name]; ok {
+			resolved, err := resolve(ctx, value.Interface(), e.arguments(field))
+			if err != nil {
+				e.fail(fieldPath, "%s", err.Error())
+				out.set(group.key, nil)
+				continue
+			}
+			out.set(group.key, e.complete(ctx, fieldPath, reflect.ValueOf(resolved), group.subSelections()))
+			continue
+		}
+		index, ok := fields[field.Name]
+		if !ok {
+			e.fail(fieldPath, "Cannot query field %q on type %q", field.Name, typeName)
+			out.set(group.key, nil)
+			continue
+		}
+		out.set(group.key, e.complete(ctx, fieldPath, value.FieldByIndex(index), group.subSelections()))
+	}
+	return out
+}
+
+type fieldGroup struct {
+	key    string
+	fields []*Field
+}
+
+// subSelections merges the selection sets of every field sharing a response
+// key, as the spec's field collection requires.
+func (g fieldGroup) subSelections() []Selection {
+	if len(g.fields) == 1 {
+		return g.fields[0].Selections
+	}
+	var merged []Selection
+	for _, field := range g.fields {
+		merged = append(merged, field.Selections...)
+	}
+	return merged
+}
+
+// collectFields flattens fragments and applies @skip/@include, grouping
+// fields by response key in first-seen order.
+func (e *executor) collectFields(typeName string, selections []Selection) []fieldGroup {
+	var groups []fieldGroup
+	index := map[string]int{}
+	visited := map[string]bool{}
+
+	var walk func([]Selection)
+	walk = func(selections []Selection) {
+		for _, selection := range selections {
+			if !e.included(selection.Directives) {
+				continue
+			}
+			switch {
+			case selection.Field != nil:
+				key := selection.Field.ResponseKey()
+				if i, ok := index[key]; ok {
+					groups[i].fields = append(groups[i].fields, selection.Field)
+					continue
+				}
+				index[key] = len(groups)
+				groups = append(groups, fieldGroup{key: key, fields: []*Field{selection.Field}})
+			case selection.Inline != nil:
+				if cond := selection.Inline.TypeCondition; cond == "" || cond == typeName {
+					walk(selection.Inline.Selections)
+				}
+			default:
+				fragment := e.doc.Fragments[selection.Spread]
+				if fragment == nil || visited[selection.Spread] || fragment.TypeCondition != typeName {
+					continue
+				}
+				visited[selection.Spread] = true
+				walk(fragment.Selections)
+			}
+		}
+	}
+	walk(selections)
+	return groups
+}
+
+func (e *executor) included(directives []Directive) bool {
+	for _, directive := range directives {
+		var condition bool
+		for _, arg := range directive.Arguments {
+			if arg.Name == "if" {
+				condition, _ = arg.Value.Resolve(e.variables).(bool)
+			}
+		}
+		switch directive.Name {
+		case "skip":
+			if condition {
+				return false
+			}
+		case "include":
+			if !condition {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (e *executor) arguments(field *Field) Args {
+	args := Args{}
+	for _, arg := range field.Arguments {
+		args[arg.Name] = arg.Value.Resolve(e.variables)
+	}
+	return args
+}
+
+// Args holds a field's resolved arguments.
+type Args map[string]any
+
+// Int returns the named argument as an int64, or def when it is absent or
+// not a whole number. JSON variables arrive as float64.
+func (a Args) Int(name string, def int64) int64 {
+	switch v := a[name].(type) {
+	case int64:
+		return v
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// String returns the named argument as a trimmed string, or def.
+func (a Args) String(name, def string) string {
+	if v, ok := a[name].(string); ok {
+		return strings.TrimSpace(v)
+	}
+	return def
+}
+
+// Bool returns the named argument as a bool, or def.
+func (a Args) Bool(name string, def bool) bool {
+	if v, ok := a[name].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// Object is a result map that keeps the query's field order when encoded.
+type Object struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *Object) set(key string, value any) {
+	if o.values == nil {
+		o.values = map[string]any{}
+	}
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// Get returns the value stored under key.
+func (o *Object) Get(key string) any {
+	return o.values[key]
+}
+
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+var jsonFieldCache sync.Map // reflect.Type -> map[string][]int
+
+// jsonFields maps a struct's JSON field names to field indexes, following
+// encoding/json's handling of "-" tags and untagged embedded structs.
+func jsonFields(typ reflect.Type) map[string][]int {
+	if cached, ok := jsonFieldCache.Load(typ); ok {
+		return cached.(map[string][]int)
+	}
+	out := map[string][]int{}
+	// Direct fields are recorded before descending into embedded structs so
+	// shallower names win, as in encoding/json.
+	var walk func(reflect.Type, []int)
+	walk = func(t reflect.Type, prefix []int) {
+		var embedded [][]int
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+			index := append(prefix[:len(prefix):len(prefix)], i)
+			if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+				embedded = append(embedded, index)
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if _, exists := out[name]; !exists {
+				out[name] = index
+			}
+		}
+		for _, index := range embedded {
+			walk(typ.FieldByIndex(index).Type, index)
+		}
+	}
+	walk(typ, nil)
+	jsonFieldCache.Store(typ, out)
+	return out
+}
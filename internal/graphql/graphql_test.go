@@ -0,0 +1,158 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testInner struct {
+	Name string `json:"name"`
+}
+
+type testRow struct {
+	ID     int64       `json:"id"`
+	Label  *string     `json:"label,omitempty"`
+	Tags   []string    `json:"tags"`
+	Items  []testInner `json:"items"`
+	hidden string
+}
+
+type testEmbedded struct {
+	Extra string `json:"extra"`
+	testRow
+}
+
+func testSchema() *Schema {
+	label := "first"
+	rows := []testRow{
+		{ID: 1, Label: &label, Tags: []string{"a"}, Items: []testInner{{Name: "x"}}},
+		{ID: 2},
+	}
+	return &Schema{
+		Query: map[string]RootResolver{
+			"rows": func(_ context.Context, args Args) (any, error) {
+				return rows[:args.Int("limit", int64(len(rows)))], nil
+			},
+			"row": func(_ context.Context, args Args) (any, error) {
+				for _, row := range rows {
+					if row.ID == args.Int("id", 0) {
+						return &row, nil
+					}
+				}
+				return nil, nil
+			},
+			"embedded": func(context.Context, Args) (any, error) {
+				return testEmbedded{Extra: "e", testRow: rows[0]}, nil
+			},
+			"broken": func(context.Context, Args) (any, error) {
+				return nil, errors.New("boom")
+			},
+		},
+		Fields: map[reflect.Type]map[string]FieldResolver{
+			reflect.TypeOf(testRow{}): {
+				"double": func(_ context.Context, parent any, _ Args) (any, error) {
+					return parent.(testRow).ID * 2, nil
+				},
+			},
+		},
+	}
+}
+
+func execute(t *testing.T, req Request) string {
+	t.Helper()
+	out, err := json.Marshal(testSchema().Execute(context.Background(), req))
+	if err != nil {
+		t.Fatalf("marshal response: %v", err)
+	}
+	return string(out)
+}
+
+func TestExecuteProjectsJSONFieldsInQueryOrder(t *testing.T) {
+	got := execute(t, Request{Query: `
+		# comments and commas are insignificant
+		query List($limit: Int = 5) {
+			rows(limit: $limit) { double, id label items { name } __typename }
+		}`, Variables: map[string]any{"limit": float64(1)}})
+	want := `{"data":{"rows":[{"double":2,"id":1,"label":"first","items":[{"name":"x"}],"__typename":"testRow"}]}}`
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestExecuteFragmentsAliasesAndDirectives(t *testing.T) {
+	got := execute(t, Request{Query: `
+		query One($withTags: Boolean!) {
+			first: row(id: 1) { ...Basics tags @include(if: $withTags) }
+			missing: row(id: 9) { id }
+			embedded { extra ... on testEmbedded { id } }
+		}
+		fragment Basics on testRow { id label @skip(if: true) }`,
+		Variables: map[string]any{"withTags": true}})
+	want := `{"data":{"first":{"id":1,"tags":["a"]},"missing":null,"embedded":{"extra":"e","id":1}}}`
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestExecuteReportsFieldErrorsWithPaths(t *testing.T) {
+	got := execute(t, Request{Query: `{ broken rows { id hidden } }`})
+	want := `{"data":{"broken":null,"rows":[{"id":1,"hidden":null},{"id":2,"hidden":null}]},"errors":[` +
+		`{"message":"boom","path":["broken"]},` +
+		`{"message":"Cannot query field \"hidden\" on type \"testRow\"","path":["rows",0,"hidden"]},` +
+		`{"message":"Cannot query field \"hidden\" on type \"testRow\"","path":["rows",1,"hidden"]}]}`
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestExecuteRejectsInvalidRequests(t *testing.T) {
+	cases := map[string]Request{
+		"syntax":            {Query: `{ rows { id }`},
+		"mutation":          {Query: `mutation { rows { id } }`},
+		"ambiguous":         {Query: `query A { rows { id } } query B { rows { id } }`},
+		"missing selection": {Query: `{ rows }`},
+	}
+	for name, req := range cases {
+		resp := testSchema().Execute(context.Background(), req)
+		if len(resp.Errors) == 0 {
+			t.Errorf("%s: expected an error, got %+v", name, resp)
+		}
+	}
+}
+
+func TestExecuteRejectsQueriesOverLimits(t *testing.T) {
+	schema := testSchema()
+	schema.MaxDepth = 2
+	schema.MaxFields = 6
+
+	if resp := schema.Execute(context.Background(), Request{Query: `{ rows { id double } }`}); len(resp.Errors) != 0 {
+		t.Fatalf("query within limits failed: %+v", resp.Errors)
+	}
+	cases := map[string]string{
+		"depth":  `{ rows { items { name } } }`,
+		"fields": `{ rows { id double tags } row(id: 1) { id label } }`,
+		"spreads": `{ rows { ...A ...A } }
+			fragment A on testRow { ...B ...B }
+			fragment B on testRow { id double }`,
+	}
+	for name, query := range cases {
+		resp := schema.Execute(context.Background(), Request{Query: query})
+		if resp.Data != nil || len(resp.Errors) != 1 {
+			t.Errorf("%s: expected a single rejection and no data, got %+v", name, resp)
+		}
+	}
+}
+
+func TestParseStringEscapes(t *testing.T) {
+	doc, err := Parse(`{ row(id: "a\"bé\n") { id } }`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	got := doc.Operations[0].Selections[0].Field.Arguments[0].Value.Resolve(nil)
+	if got != "a\"bé\n" {
+		t.Fatalf("string = %q", got)
+	}
+}
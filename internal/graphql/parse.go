@@ -0,0 +1,715 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document is a parsed GraphQL request: its operations plus the named
+// fragments they may spread.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+type Operation struct {
+	Type       string // "query", "mutation", or "subscription"
+	Name       string
+	Variables  []VariableDefinition
+	Selections []Selection
+}
+
+type VariableDefinition struct {
+	Name       string
+	Default    Value
+	HasDefault bool
+}
+
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Selections    []Selection
+}
+
+// Selection is exactly one of a field, a named fragment spread, or an inline
+// fragment.
+type Selection struct {
+	Field      *Field
+	Spread     string
+	Inline     *InlineFragment
+	Directives []Directive
+}
+
+type Field struct {
+	Alias      string
+	Name       string
+	Arguments  []Argument
+	Selections []Selection
+}
+
+// ResponseKey is the alias when set, otherwise the field name.
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+type InlineFragment struct {
+	TypeCondition string
+	Selections    []Selection
+}
+
+type Directive struct {
+	Name      string
+	Arguments []Argument
+}
+
+type Argument struct {
+	Name  string
+	Value Value
+}
+
+// Value is a literal or variable reference as written in the query.
+type Value struct {
+	Kind     ValueKind
+	Scalar   any // int64, float64, string, bool, or nil for Scalar; name for Variable and Enum
+	List     []Value
+	Object   []Argument
+	Variable string
+}
+
+type ValueKind int
+
+const (
+	ScalarValue ValueKind = iota
+	EnumValue
+	VariableValue
+	ListValue
+	ObjectValue
+)
+
+// Resolve substitutes variables and returns plain Go values: int64,
+// float64, string, bool, nil, []any, or map[string]any. Enums resolve to
+// their name.
+func (v Value) Resolve(variables map[string]any) any {
+	switch v.Kind {
+	case VariableValue:
+		return variables[v.Variable]
+	case ListValue:
+		out := make([]any, 0, len(v.List))
+		for _, item := range v.List {
+			out = append(out, item.Resolve(variables))
+		}
+		return out
+	case ObjectValue:
+		out := make(map[string]any, len(v.Object))
+		for _, field := range v.Object {
+			out[field.Name] = field.Value.Resolve(variables)
+		}
+		return out
+	default:
+		return v.Scalar
+	}
+}
+
+// Parse parses a GraphQL executable document.
+func Parse(source string) (*Document, error) {
+	p := &parser{lex: lexer{src: source}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	doc := &Document{Fragments: map[string]*Fragment{}}
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.tok.is(tokPunct, "{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: selections})
+		case p.tok.is(tokName, "query"), p.tok.is(tokName, "mutation"), p.tok.is(tokName, "subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.tok.is(tokName, "fragment"):
+			fragment, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.Fragments[fragment.Name]; exists {
+				return nil, fmt.Errorf("duplicate fragment %q", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("document contains no operation")
+	}
+	return doc, nil
+}
+
+type parser struct {
+	lex lexer
+	tok token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokEOF {
+		return fmt.Errorf("syntax error: unexpected end of document")
+	}
+	return fmt.Errorf("syntax error: unexpected %q at offset %d", p.tok.text, p.tok.pos)
+}
+
+func (p *parser) expectPunct(text string) error {
+	if !p.tok.is(tokPunct, text) {
+		return fmt.Errorf("syntax error: expected %q, found %q at offset %d", text, p.tok.text, p.tok.pos)
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokName {
+		return "", fmt.Errorf("syntax error: expected name, found %q at offset %d", p.tok.text, p.tok.pos)
+	}
+	name := p.tok.text
+	return name, p.advance()
+}
+
+func (p *parser) parseOperation() (*Operation, error) {
+	op := &Operation{Type: p.tok.text}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokName {
+		op.Name = p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.tok.is(tokPunct, "(") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for !p.tok.is(tokPunct, ")") {
+			def, err := p.parseVariableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = append(op.Variables, def)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.Selections = selections
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinition() (VariableDefinition, error) {
+	def := VariableDefinition{}
+	if err := p.expectPunct("$"); err != nil {
+		return def, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return def, err
+	}
+	def.Name = name
+	if err := p.expectPunct(":"); err != nil {
+		return def, err
+	}
+	if err := p.skipType(); err != nil {
+		return def, err
+	}
+	if p.tok.is(tokPunct, "=") {
+		if err := p.advance(); err != nil {
+			return def, err
+		}
+		value, err := p.parseValue(true)
+		if err != nil {
+			return def, err
+		}
+		def.Default = value
+		def.HasDefault = true
+	}
+	_, err = p.parseDirectives()
+	return def, err
+}
+
+// skipType consumes a type reference such as [Int!]!. Types are not
+// checked; argument coercion happens in the resolvers.
+func (p *parser) skipType() error {
+	if p.tok.is(tokPunct, "[") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return err
+	}
+	if p.tok.is(tokPunct, "!") {
+		return p.advance()
+	}
+	return nil
+}
+
+func (p *parser) parseFragment() (*Fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, fmt.Errorf("syntax error: fragment cannot be named \"on\"")
+	}
+	if !p.tok.is(tokName, "on") {
+		return nil, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	typeCondition, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &Fragment{Name: name, TypeCondition: typeCondition, Selections: selections}, nil
+}
+
+func (p *parser) parseSelectionSet() ([]Selection, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	selections := []Selection{}
+	for !p.tok.is(tokPunct, "}") {
+		if p.tok.kind == tokEOF {
+			return nil, p.unexpected()
+		}
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("syntax error: empty selection set at offset %d", p.tok.pos)
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) parseSelection() (Selection, error) {
+	if p.tok.is(tokPunct, "...") {
+		if err := p.advance(); err != nil {
+			return Selection{}, err
+		}
+		if p.tok.kind == tokName && p.tok.text != "on" {
+			name := p.tok.text
+			if err := p.advance(); err != nil {
+				return Selection{}, err
+			}
+			directives, err := p.parseDirectives()
+			return Selection{Spread: name, Directives: directives}, err
+		}
+		inline := &InlineFragment{}
+		if p.tok.is(tokName, "on") {
+			if err := p.advance(); err != nil {
+				return Selection{}, err
+			}
+			typeCondition, err := p.expectName()
+			if err != nil {
+				return Selection{}, err
+			}
+			inline.TypeCondition = typeCondition
+		}
+		directives, err := p.parseDirectives()
+		if err != nil {
+			return Selection{}, err
+		}
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return Selection{}, err
+		}
+		inline.Selections = selections
+		return Selection{Inline: inline, Directives: directives}, nil
+	}
+
+	field := &Field{}
+	name, err := p.expectName()
+	if err != nil {
+		return Selection{}, err
+	}
+	field.Name = name
+	if p.tok.is(tokPunct, ":") {
+		if err := p.advance(); err != nil {
+			return Selection{}, err
+		}
+		field.Alias = name
+		if field.Name, err = p.expectName(); err != nil {
+			return Selection{}, err
+		}
+	}
+	if p.tok.is(tokPunct, "(") {
+		if field.Arguments, err = p.parseArguments(); err != nil {
+			return Selection{}, err
+		}
+	}
+	directives, err := p.parseDirectives()
+	if err != nil {
+		return Selection{}, err
+	}
+	if p.tok.is(tokPunct, "{") {
+		if field.Selections, err = p.parseSelectionSet(); err != nil {
+			return Selection{}, err
+		}
+	}
+	return Selection{Field: field, Directives: directives}, nil
+}
+
+func (p *parser) parseArguments() ([]Argument, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	args := []Argument{}
+	for !p.tok.is(tokPunct, ")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, Argument{Name: name, Value: value})
+	}
+	return args, p.advance()
+}
+
+func (p *parser) parseDirectives() ([]Directive, error) {
+	var directives []Directive
+	for p.tok.is(tokPunct, "@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		directive := Directive{Name: name}
+		if p.tok.is(tokPunct, "(") {
+			if directive.Arguments, err = p.parseArguments(); err != nil {
+				return nil, err
+			}
+		}
+		directives = append(directives, directive)
+	}
+	return directives, nil
+}
+
+func (p *parser) parseValue(constant bool) (Value, error) {
+	tok := p.tok
+	switch {
+	case tok.is(tokPunct, "$"):
+		if constant {
+			return Value{}, fmt.Errorf("syntax error: variable not allowed in default value at offset %d", tok.pos)
+		}
+		if err := p.advance(); err != nil {
+			return Value{}, err
+		}
+		name, err := p.expectName()
+		return Value{Kind: VariableValue, Variable: name}, err
+	case tok.is(tokPunct, "["):
+		if err := p.advance(); err != nil {
+			return Value{}, err
+		}
+		list := Value{Kind: ListValue, List: []Value{}}
+		for !p.tok.is(tokPunct, "]") {
+			if p.tok.kind == tokEOF {
+				return Value{}, p.unexpected()
+			}
+			item, err := p.parseValue(constant)
+			if err != nil {
+				return Value{}, err
+			}
+			list.List = append(list.List, item)
+		}
+		return list, p.advance()
+	case tok.is(tokPunct, "{"):
+		if err := p.advance(); err != nil {
+			return Value{}, err
+		}
+		object := Value{Kind: ObjectValue}
+		for !p.tok.is(tokPunct, "}") {
+			name, err := p.expectName()
+			if err != nil {
+				return Value{}, err
+			}
+			if err := p.expectPunct(":"); err != nil {
+				return Value{}, err
+			}
+			field, err := p.parseValue(constant)
+			if err != nil {
+				return Value{}, err
+			}
+			object.Object = append(object.Object, Argument{Name: name, Value: field})
+		}
+		return object, p.advance()
+	case tok.kind == tokInt:
+		n, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return Value{}, fmt.Errorf("invalid integer %q", tok.text)
+		}
+		return Value{Scalar: n}, p.advance()
+	case tok.kind == tokFloat:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return Value{}, fmt.Errorf("invalid float %q", tok.text)
+		}
+		return Value{Scalar: f}, p.advance()
+	case tok.kind == tokString:
+		return Value{Scalar: tok.text}, p.advance()
+	case tok.kind == tokName:
+		var value Value
+		switch tok.text {
+		case "true":
+			value = Value{Scalar: true}
+		case "false":
+			value = Value{Scalar: false}
+		case "null":
+			value = Value{Scalar: nil}
+		default:
+			value = Value{Kind: EnumValue, Scalar: tok.text}
+		}
+		return value, p.advance()
+	default:
+		return Value{}, p.unexpected()
+	}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+	start := l.pos
+	c := l.src[l.pos]
+	switch {
+	case strings.IndexByte("!$&()=:@[]{}|", c) >= 0:
+		l.pos++
+		return token{kind: tokPunct, text: string(c), pos: start}, nil
+	case c == '.':
+		if strings.HasPrefix(l.src[l.pos:], "...") {
+			l.pos += 3
+			return token{kind: tokPunct, text: "...", pos: start}, nil
+		}
+		return token{}, fmt.Errorf("syntax error: unexpected \".\" at offset %d", start)
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokName, text: l.src[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			return l.blockString()
+		}
+		return l.string()
+	default:
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+		return token{}, fmt.Errorf("syntax error: unexpected character %q at offset %d", r, start)
+	}
+}
+
+// skipIgnored skips whitespace, commas, the BOM, and # comments, all of
+// which GraphQL treats as insignificant.
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	digits := l.pos
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+	if l.pos == digits {
+		return token{}, fmt.Errorf("syntax error: invalid number at offset %d", start)
+	}
+	kind := tokInt
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokFloat
+		l.pos++
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokFloat
+		l.pos++
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+	}
+	return token{kind: kind, text: l.src[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return token{kind: tokString, text: b.String(), pos: start}, nil
+		case c == '\n' || c == '\r':
+			return token{}, fmt.Errorf("syntax error: unterminated string at offset %d", start)
+		case c == '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, fmt.Errorf("syntax error: unterminated string at offset %d", start)
+			}
+			escape := l.src[l.pos+1]
+			l.pos += 2
+			switch escape {
+			case '"', '\\', '/':
+				b.WriteByte(escape)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return token{}, fmt.Errorf("syntax error: invalid unicode escape at offset %d", l.pos)
+				}
+				code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, fmt.Errorf("syntax error: invalid unicode escape at offset %d", l.pos)
+				}
+				b.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return token{}, fmt.Errorf("syntax error: invalid escape \\%c at offset %d", escape, l.pos-2)
+			}
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return token{}, fmt.Errorf("syntax error: unterminated string at offset %d", start)
+}
+
+// blockString returns the raw contents of a """ string; only the \"""
+// escape is interpreted and common indentation is left as written.
+func (l *lexer) blockString() (token, error) {
+	start := l.pos
+	l.pos += 3
+	end := strings.Index(l.src[l.pos:], `"""`)
+	for end > 0 && l.src[l.pos+end-1] == '\\' {
+		next := strings.Index(l.src[l.pos+end+3:], `"""`)
+		if next < 0 {
+			end = -1
+			break
+		}
+		end += 3 + next
+	}
+	if end < 0 {
+		return token{}, fmt.Errorf("syntax error: unterminated block string at offset %d", start)
+	}
+	text := strings.ReplaceAll(l.src[l.pos:l.pos+end], `\"""`, `"""`)
+	l.pos += end + 3
+	return token{kind: tokString, text: text, pos: start}, nil
+}
+
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
@@ -0,0 +1,384 @@
+// Package importer reads match and draft history exported by other MTG Arena
+// trackers (MTGATracker, Untapped.gg, and 17Lands) as CSV, for `ponder
+// import`. Each source names its columns differently; the aliases below map
+// them onto ponder's matches and draft picks.
+package importer
+
+import (
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// Sources lists the trackers Read understands, as passed to -source.
+var Sources = []string{"mtgatracker", "untapped", "17lands"}
+
+// Result is what Read found in one file. Skipped counts rows that had too
+// little to import, such as a match with no date.
+type Result struct {
+	Matches []model.ImportedMatch
+	Drafts  []model.ImportedDraft
+	Skipped int
+}
+
+// columns maps a field to the header names that may hold it, compared after
+// lowercasing and dropping everything but letters and digits.
+type columns map[string][]string
+
+var commonColumns = columns{
+	"matchID":    {"matchid", "gameid", "arenamatchid"},
+	"event":      {"event", "eventname", "eventid", "queue"},
+	"eventType":  {"eventtype"},
+	"expansion":  {"expansion", "set"},
+	"format":     {"format", "bestof"},
+	"date":       {"date", "datetime", "startedat", "starttime", "gametime", "matchtime", "timestamp", "time"},
+	"endedAt":    {"endedat", "endtime"},
+	"opponent":   {"opponent", "opponentname", "opp", "oppname"},
+	"result":     {"result", "won", "outcome", "matchresult"},
+	"winner":     {"winner"},
+	"hero":       {"hero", "player", "playername"},
+	"turns":      {"turns", "numturns", "turncount", "turnnumber"},
+	"draftID":    {"draftid"},
+	"draftTime":  {"drafttime", "draftdate"},
+	"matchNo":    {"matchnumber"},
+	"packNumber": {"packnumber", "packno"},
+	"pickNumber": {"picknumber", "pickno"},
+	"pick":       {"pick", "picked", "pickedcard", "pickname"},
+	"packCards":  {"packcards", "cardsinpack", "packcontents"},
+}
+
+// sourceInfo holds what differs between trackers beyond column names.
+type sourceInfo struct {
+	// zeroBasedPicks is set when pack and pick numbers start at 0; ponder
+	// numbers them from 1 as Arena does.
+	zeroBasedPicks bool
+	// packCardPrefix names the per-card columns holding how many of that
+	// card were in the pack, as 17Lands writes them.
+	packCardPrefix string
+}
+
+var sources = map[string]sourceInfo{
+	"mtgatracker": {},
+	"untapped":    {},
+	"17lands":     {zeroBasedPicks: true, packCardPrefix: "pack_card_"},
+}
+
+// Read parses one CSV export from source. A file with pack and pick columns
+// is read as draft picks, anything else as matches. Rows for the same match
+// (17Lands writes one per game) are combined into one match whose result is
+// the majority of its games.
+func Read(r io.Reader, source string) (Result, error) {
+	info, ok := sources[source]
+	if !ok {
+		return Result{}, fmt.Errorf("unknown source %q (use %s)", source, strings.Join(Sources, ", "))
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return Result{}, nil
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("read header: %w", err)
+	}
+	t := newTable(header, info)
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Result{}, fmt.Errorf("read csv: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	if t.has("pick") && t.has("pickNumber") {
+		return t.readDrafts(source, records), nil
+	}
+	if !t.has("date") || !(t.has("result") || t.has("winner")) {
+		return Result{}, fmt.Errorf("%s export needs a date and a result column (or pack, pick number, and pick columns for drafts); header was %s", source, strings.Join(header, ", "))
+	}
+	return t.readMatches(source, records), nil
+}
+
+type table struct {
+	info     sourceInfo
+	index    map[string]int
+	packCols map[int]string
+}
+
+func newTable(header []string, info sourceInfo) table {
+	byName := map[string]int{}
+	for i, name := range header {
+		key := normalizeHeader(name)
+		if _, seen := byName[key]; !seen {
+			byName[key] = i
+		}
+	}
+	t := table{info: info, index: map[string]int{}, packCols: map[int]string{}}
+	for field, aliases := range commonColumns {
+		for _, alias := range aliases {
+			if i, ok := byName[alias]; ok {
+				t.index[field] = i
+				break
+			}
+		}
+	}
+	if info.packCardPrefix != "" {
+		for i, name := range header {
+			if card, ok := strings.CutPrefix(name, info.packCardPrefix); ok {
+				t.packCols[i] = card
+			}
+		}
+	}
+	return t
+}
+
+func normalizeHeader(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (t table) has(field string) bool {
+	_, ok := t.index[field]
+	return ok
+}
+
+func (t table) get(record []string, field string) string {
+	i, ok := t.index[field]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// eventName prefers an explicit event column and otherwise builds Arena's
+// "PremierDraft_BLB" form from 17Lands' event type and expansion.
+func (t table) eventName(record []string) string {
+	if event := t.get(record, "event"); event != "" {
+		return event
+	}
+	eventType, expansion := t.get(record, "eventType"), t.get(record, "expansion")
+	if eventType != "" && expansion != "" {
+		return eventType + "_" + strings.ToUpper(expansion)
+	}
+	return eventType
+}
+
+type matchTally struct {
+	match        model.ImportedMatch
+	wins, losses int
+}
+
+func (t table) readMatches(source string, records [][]string) Result {
+	out := Result{}
+	tallies := map[string]*matchTally{}
+	var order []string
+	for _, record := range records {
+		startedAt, ok := parseTime(t.get(record, "date"))
+		if !ok {
+			out.Skipped++
+			continue
+		}
+		event := t.eventName(record)
+		opponent := t.get(record, "opponent")
+		result := parseResult(t.get(record, "result"))
+		if winner := t.get(record, "winner"); winner != "" && result == "unknown" {
+			if hero := t.get(record, "hero"); hero != "" {
+				result = "loss"
+				if strings.EqualFold(winner, hero) {
+					result = "win"
+				}
+			}
+		}
+
+		id := t.get(record, "matchID")
+		if id == "" {
+			if draftID, matchNo := t.get(record, "draftID"), t.get(record, "matchNo"); draftID != "" && matchNo != "" {
+				id = source + ":" + draftID + ":" + matchNo
+			} else {
+				id = source + ":" + rowHash(startedAt, event, opponent)
+			}
+		}
+
+		tally, seen := tallies[id]
+		if !seen {
+			tally = &matchTally{match: model.ImportedMatch{
+				ID:        id,
+				EventName: event,
+				Format:    t.get(record, "format"),
+				Opponent:  opponent,
+				StartedAt: startedAt,
+			}}
+			tallies[id] = tally
+			order = append(order, id)
+		}
+		if startedAt < tally.match.StartedAt {
+			tally.match.StartedAt = startedAt
+		}
+		if endedAt, ok := parseTime(t.get(record, "endedAt")); ok && endedAt > tally.match.EndedAt {
+			tally.match.EndedAt = endedAt
+		}
+		if turns, err := strconv.ParseInt(t.get(record, "turns"), 10, 64); err == nil && turns > 0 {
+			tally.match.TurnCount += turns
+		}
+		switch result {
+		case "win":
+			tally.wins++
+		case "loss":
+			tally.losses++
+		}
+	}
+
+	for _, id := range order {
+		tally := tallies[id]
+		switch {
+		case tally.wins > tally.losses:
+			tally.match.Result = "win"
+		case tally.losses > tally.wins:
+			tally.match.Result = "loss"
+		default:
+			tally.match.Result = "unknown"
+		}
+		out.Matches = append(out.Matches, tally.match)
+	}
+	return out
+}
+
+func (t table) readDrafts(source string, records [][]string) Result {
+	out := Result{}
+	drafts := map[string]*model.ImportedDraft{}
+	var order []string
+	for _, record := range records {
+		pack, packErr := strconv.ParseInt(t.get(record, "packNumber"), 10, 64)
+		pick, pickErr := strconv.ParseInt(t.get(record, "pickNumber"), 10, 64)
+		picked := t.get(record, "pick")
+		if packErr != nil || pickErr != nil || picked == "" {
+			out.Skipped++
+			continue
+		}
+		if t.info.zeroBasedPicks {
+			pack++
+			pick++
+		}
+		event := t.eventName(record)
+		startedAt, _ := parseTime(t.get(record, "draftTime"))
+		pickedAt, _ := parseTime(t.get(record, "date"))
+		if startedAt == "" {
+			startedAt = pickedAt
+		}
+
+		id := t.get(record, "draftID")
+		if id == "" {
+			id = source + ":" + rowHash(startedAt, event)
+		}
+		draft, seen := drafts[id]
+		if !seen {
+			draft = &model.ImportedDraft{DraftID: id, EventName: event, StartedAt: startedAt}
+			drafts[id] = draft
+			order = append(order, id)
+		}
+		draft.Picks = append(draft.Picks, model.ImportedDraftPick{
+			PackNumber: pack,
+			PickNumber: pick,
+			Picked:     []string{picked},
+			Pack:       t.packCards(record),
+			PickedAt:   pickedAt,
+		})
+	}
+	for _, id := range order {
+		out.Drafts = append(out.Drafts, *drafts[id])
+	}
+	return out
+}
+
+// packCards reads the cards still in the pack, from a single list column or
+// from per-card count columns.
+func (t table) packCards(record []string) []string {
+	if list := t.get(record, "packCards"); list != "" {
+		return strings.FieldsFunc(list, func(r rune) bool { return r == '|' || r == ';' })
+	}
+	var cards []string
+	indexes := make([]int, 0, len(t.packCols))
+	for i := range t.packCols {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		if i >= len(record) {
+			continue
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(record[i]))
+		if err != nil {
+			continue
+		}
+		for ; count > 0; count-- {
+			cards = append(cards, t.packCols[i])
+		}
+	}
+	return cards
+}
+
+func parseResult(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "win", "won", "w", "true", "1", "victory":
+		return "win"
+	case "loss", "lost", "lose", "l", "false", "0", "defeat":
+		return "loss"
+	default:
+		return "unknown"
+	}
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"01/02/2006 15:04:05",
+	"1/2/2006 3:04:05 PM",
+	"1/2/2006 3:04 PM",
+	"1/2/2006 15:04",
+	"2006-01-02",
+	"1/2/2006",
+}
+
+// parseTime reads the date formats these exports use. Times without a zone
+// are taken as UTC.
+func parseTime(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", false
+	}
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC().Format(time.RFC3339), true
+		}
+	}
+	return "", false
+}
+
+func rowHash(parts ...string) string {
+	sum := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
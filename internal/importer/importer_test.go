@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestRead17LandsGamesCombinesMatches(t *testing.T) {
+	t.Parallel()
+
+	csv := `expansion,event_type,draft_id,draft_time,game_time,match_number,game_number,on_play,num_turns,won
+BLB,TradDraft,d1,2024-08-01 10:00:00,2024-08-01 11:00:00,1,1,True,9,True
+BLB,TradDraft,d1,2024-08-01 10:00:00,2024-08-01 11:20:00,1,2,False,7,False
+BLB,TradDraft,d1,2024-08-01 10:00:00,2024-08-01 11:40:00,1,3,True,11,True
+BLB,TradDraft,d1,2024-08-01 10:00:00,not a date,2,1,True,5,True
+`
+	got, err := Read(strings.NewReader(csv), "17lands")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	want := []model.ImportedMatch{{
+		ID:        "17lands:d1:1",
+		EventName: "TradDraft_BLB",
+		StartedAt: "2024-08-01T11:00:00Z",
+		Result:    "win",
+		TurnCount: 27,
+	}}
+	if !reflect.DeepEqual(got.Matches, want) || got.Skipped != 1 {
+		t.Fatalf("matches = %+v skipped=%d, want %+v skipped=1", got.Matches, got.Skipped, want)
+	}
+}
+
+func TestRead17LandsDraftPicks(t *testing.T) {
+	t.Parallel()
+
+	csv := `expansion,event_type,draft_id,draft_time,pack_number,pick_number,pick,pack_card_Plains,pack_card_Shock
+BLB,PremierDraft,d2,2024-08-02 09:00:00,0,0,Shock,2,1
+BLB,PremierDraft,d2,2024-08-02 09:00:00,0,1,Plains,1,0
+`
+	got, err := Read(strings.NewReader(csv), "17lands")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	want := []model.ImportedDraft{{
+		DraftID:   "d2",
+		EventName: "PremierDraft_BLB",
+		StartedAt: "2024-08-02T09:00:00Z",
+		Picks: []model.ImportedDraftPick{
+			{PackNumber: 1, PickNumber: 1, Picked: []string{"Shock"}, Pack: []string{"Plains", "Plains", "Shock"}},
+			{PackNumber: 1, PickNumber: 2, Picked: []string{"Plains"}, Pack: []string{"Plains"}},
+		},
+	}}
+	if !reflect.DeepEqual(got.Drafts, want) {
+		t.Fatalf("drafts = %+v, want %+v", got.Drafts, want)
+	}
+}
+
+func TestReadMatchesByWinnerAndStableIDs(t *testing.T) {
+	t.Parallel()
+
+	csv := `Date,Event,Hero,Opponent,Winner,Turns
+2024-03-05T20:15:00Z,Ladder,me,them,me,8
+2024-03-05T20:45:00Z,Ladder,me,other,other,
+`
+	first, err := Read(strings.NewReader(csv), "mtgatracker")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(first.Matches) != 2 || first.Matches[0].Result != "win" || first.Matches[1].Result != "loss" {
+		t.Fatalf("matches = %+v, want a win then a loss", first.Matches)
+	}
+	again, err := Read(strings.NewReader(csv), "mtgatracker")
+	if err != nil {
+		t.Fatalf("Read again: %v", err)
+	}
+	if first.Matches[0].ID != again.Matches[0].ID || !strings.HasPrefix(first.Matches[0].ID, "mtgatracker:") {
+		t.Fatalf("ids = %q then %q, want the same mtgatracker: id", first.Matches[0].ID, again.Matches[0].ID)
+	}
+
+	if _, err := Read(strings.NewReader("Opponent,Deck\nx,y\n"), "untapped"); err == nil {
+		t.Fatalf("Read without date or result succeeded, want an error")
+	}
+	if _, err := Read(strings.NewReader(csv), "arenatutor"); err == nil {
+		t.Fatalf("Read with unknown source succeeded, want an error")
+	}
+}
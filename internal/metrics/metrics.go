@@ -0,0 +1,213 @@
+// Package metrics keeps process-wide counters, gauges, and histograms and
+// renders them in the Prometheus text exposition format for GET /metrics.
+// It is deliberately tiny: just enough for self-hosters to scrape the tracker
+// without pulling the Prometheus client library into the desktop build.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Tracker-wide metrics. Ingest metrics are fed by ingest.Parser.ParseFile;
+// HTTP latency by the api server middleware.
+var (
+	LinesParsed = NewCounter("ponder_ingest_lines_parsed_total",
+		"Player.log lines read by the parser.")
+	RawEventsStored = NewCounter("ponder_ingest_raw_events_stored_total",
+		"Raw log events persisted for later repair passes.")
+	MatchesUpserted = NewCounter("ponder_ingest_matches_upserted_total",
+		"Matches whose completion was written during ingest.")
+	ParseErrors = NewCounter("ponder_ingest_parse_errors_total",
+		"ParseFile calls that failed.")
+	LinesSkipped = NewCounter("ponder_ingest_lines_skipped_total",
+		"Log lines whose handlers failed and were skipped; see parse_errors.")
+	IngestLagBytes = NewGauge("ponder_ingest_lag_bytes",
+		"Bytes of the log file not yet ingested after the last parse.")
+	LastIngestSuccess = NewGauge("ponder_ingest_last_success_timestamp_seconds",
+		"Unix time of the last successful parse; alert on time() minus this.")
+	HTTPRequestDuration = NewHistogramVec("ponder_http_request_duration_seconds",
+		"HTTP request latency by route, method, and status code.",
+		[]float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		"route", "method", "code")
+)
+
+type collector interface {
+	write(w io.Writer)
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []collector
+)
+
+func register(c collector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, c)
+}
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	name, help string
+	value      atomic.Int64
+}
+
+func NewCounter(name, help string) *Counter {
+	c := &Counter{name: name, help: help}
+	register(c)
+	return c
+}
+
+// Add increments the counter; negative deltas are ignored.
+func (c *Counter) Add(delta int64) {
+	if delta > 0 {
+		c.value.Add(delta)
+	}
+}
+
+func (c *Counter) Inc() { c.Add(1) }
+
+func (c *Counter) Value() int64 { return c.value.Load() }
+
+func (c *Counter) write(w io.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	fmt.Fprintf(w, "%s %d\n", c.name, c.Value())
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	name, help string
+	bits       atomic.Uint64
+}
+
+func NewGauge(name, help string) *Gauge {
+	g := &Gauge{name: name, help: help}
+	register(g)
+	return g
+}
+
+func (g *Gauge) Set(value float64) { g.bits.Store(math.Float64bits(value)) }
+
+func (g *Gauge) Value() float64 { return math.Float64frombits(g.bits.Load()) }
+
+func (g *Gauge) write(w io.Writer) {
+	writeHeader(w, g.name, g.help, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.Value()))
+}
+
+// HistogramVec is a set of histograms sharing bucket bounds, one per
+// distinct label value tuple.
+type HistogramVec struct {
+	name, help string
+	buckets    []float64
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*histogram
+}
+
+type histogram struct {
+	labelValues []string
+	counts      []int64 // cumulative: counts[i] holds values <= buckets[i]
+	count       int64
+	sum         float64
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{
+		name:    name,
+		help:    help,
+		buckets: slices.Clone(buckets),
+		labels:  labels,
+		series:  make(map[string]*histogram),
+	}
+	slices.Sort(h.buckets)
+	register(h)
+	return h
+}
+
+// Observe records one value for the given label values, which must match the
+// vec's label names in number and order.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	if len(labelValues) != len(h.labels) {
+		return
+	}
+	key := strings.Join(labelValues, "\xff")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogram{labelValues: slices.Clone(labelValues), counts: make([]int64, len(h.buckets))}
+		h.series[key] = series
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		series := h.series[key]
+		labels := h.labelPairs(series.labelValues)
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket{%s,le=%q} %d\n", h.name, labels, formatFloat(bound), series.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", h.name, labels, series.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", h.name, labels, formatFloat(series.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", h.name, labels, series.count)
+	}
+}
+
+func (h *HistogramVec) labelPairs(values []string) string {
+	pairs := make([]string, len(h.labels))
+	for i, label := range h.labels {
+		pairs[i] = label + "=" + strconv.Quote(values[i])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func writeHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// WriteText renders every registered metric in Prometheus text format.
+func WriteText(w io.Writer) {
+	registryMu.Lock()
+	collectors := slices.Clone(registry)
+	registryMu.Unlock()
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// Handler serves WriteText for scrapers.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteText(w)
+	})
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHistogramVecWritesCumulativeBuckets(t *testing.T) {
+	h := &HistogramVec{
+		name:    "test_latency_seconds",
+		help:    "Test latency.",
+		buckets: []float64{0.1, 1},
+		labels:  []string{"route"},
+		series:  make(map[string]*histogram),
+	}
+	h.Observe(0.05, "/a")
+	h.Observe(0.5, "/a")
+	h.Observe(5, "/a")
+	h.Observe(1, "/b", "extra") // wrong label arity is dropped
+
+	var out strings.Builder
+	h.write(&out)
+	text := out.String()
+	for _, want := range []string{
+		"# TYPE test_latency_seconds histogram",
+		`test_latency_seconds_bucket{route="/a",le="0.1"} 1`,
+		`test_latency_seconds_bucket{route="/a",le="1"} 2`,
+		`test_latency_seconds_bucket{route="/a",le="+Inf"} 3`,
+		`test_latency_seconds_sum{route="/a"} 5.55`,
+		`test_latency_seconds_count{route="/a"} 3`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, `"/b"`) {
+		t.Fatalf("observation with wrong label count was recorded:\n%s", text)
+	}
+}
+
+func TestCounterIgnoresNegativeDeltas(t *testing.T) {
+	c := &Counter{name: "test_total", help: "Test."}
+	c.Add(3)
+	c.Add(-2)
+	c.Inc()
+	if got := c.Value(); got != 4 {
+		t.Fatalf("Value = %d, want 4", got)
+	}
+}
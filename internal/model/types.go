@@ -1,657 +0,0 @@
-package model
-
-import "time"
-
-type ParseStats struct {
-	LogPath          string
-	LinesRead        int64
-	BytesRead        int64
-	RawEventsStored  int64
-	MatchesUpserted  int64
-	RankSnapshots    int64
-	EconomySnapshots int64
-	DecksUpserted    int64
-	DraftPicksAdded  int64
-	StartedAt        time.Time
-	CompletedAt      time.Time
-}
-
-type MatchRow struct {
-	ID                      int64    `json:"id"`
-	ArenaMatchID            string   `json:"arenaMatchId"`
-	EventName               string   `json:"eventName"`
-	BestOf                  string   `json:"bestOf"`
-	PlayDraw                string   `json:"playDraw"`
-	Opponent                string   `json:"opponent"`
-	StartedAt               string   `json:"startedAt"`
-	EndedAt                 string   `json:"endedAt"`
-	Result                  string   `json:"result"`
-	WinReason               string   `json:"winReason"`
-	TurnCount               *int64   `json:"turnCount"`
-	SecondsCount            *int64   `json:"secondsCount"`
-	DeckID                  *int64   `json:"deckId"`
-	DeckName                *string  `json:"deckName"`
-	DeckVersionID           *int64   `json:"deckVersionId,omitempty"`
-	DeckVersionNumber       *int64   `json:"deckVersionNumber,omitempty"`
-	DeckColors              []string `json:"deckColors"`
-	DeckColorsKnown         bool     `json:"deckColorsKnown"`
-	OpponentDeckColors      []string `json:"opponentDeckColors"`
-	OpponentDeckColorsKnown bool     `json:"opponentDeckColorsKnown"`
-}
-
-type OpponentObservedCardRow struct {
-	CardID   int64  `json:"cardId"`
-	Quantity int64  `json:"quantity"`
-	CardName string `json:"cardName,omitempty"`
-}
-
-type MatchCardPlayRow struct {
-	ID              int64  `json:"id"`
-	GameNumber      *int64 `json:"gameNumber,omitempty"`
-	InstanceID      int64  `json:"instanceId"`
-	CardID          int64  `json:"cardId"`
-	CardName        string `json:"cardName,omitempty"`
-	OwnerSeatID     *int64 `json:"ownerSeatId,omitempty"`
-	PlayerSide      string `json:"playerSide"`
-	FirstPublicZone string `json:"firstPublicZone"`
-	TurnNumber      *int64 `json:"turnNumber,omitempty"`
-	Phase           string `json:"phase,omitempty"`
-	PlayedAt        string `json:"playedAt,omitempty"`
-}
-
-type MatchReplayChangeRow struct {
-	InstanceID       int64  `json:"instanceId"`
-	CardID           int64  `json:"cardId"`
-	CardName         string `json:"cardName,omitempty"`
-	OwnerSeatID      *int64 `json:"ownerSeatId,omitempty"`
-	PlayerSide       string `json:"playerSide"`
-	Action           string `json:"action"`
-	FromZoneID       *int64 `json:"fromZoneId,omitempty"`
-	FromZoneType     string `json:"fromZoneType,omitempty"`
-	FromZonePosition *int64 `json:"fromZonePosition,omitempty"`
-	ToZoneID         *int64 `json:"toZoneId,omitempty"`
-	ToZoneType       string `json:"toZoneType,omitempty"`
-	ToZonePosition   *int64 `json:"toZonePosition,omitempty"`
-	IsToken          bool   `json:"isToken"`
-}
-
-type MatchReplayFrameObjectRow struct {
-	ID                   int64  `json:"id"`
-	FrameID              int64  `json:"frameId"`
-	InstanceID           int64  `json:"instanceId"`
-	CardID               int64  `json:"cardId"`
-	CardName             string `json:"cardName,omitempty"`
-	OwnerSeatID          *int64 `json:"ownerSeatId,omitempty"`
-	ControllerSeatID     *int64 `json:"controllerSeatId,omitempty"`
-	PlayerSide           string `json:"playerSide"`
-	ZoneID               *int64 `json:"zoneId,omitempty"`
-	ZoneType             string `json:"zoneType"`
-	ZonePosition         *int64 `json:"zonePosition,omitempty"`
-	Visibility           string `json:"visibility,omitempty"`
-	Power                *int64 `json:"power,omitempty"`
-	Toughness            *int64 `json:"toughness,omitempty"`
-	AttackTargetID       *int64 `json:"attackTargetId,omitempty"`
-	BlockAttackerIDsJSON string `json:"blockAttackerIdsJson,omitempty"`
-	CounterSummaryJSON   string `json:"counterSummaryJson,omitempty"`
-	DetailsJSON          string `json:"detailsJson,omitempty"`
-	AttackState          string `json:"attackState,omitempty"`
-	BlockState           string `json:"blockState,omitempty"`
-	IsToken              bool   `json:"isToken"`
-	IsTapped             bool   `json:"isTapped"`
-	HasSummoningSickness bool   `json:"hasSummoningSickness"`
-}
-
-type MatchReplayFrameRow struct {
-	ID                int64                       `json:"id"`
-	GameNumber        *int64                      `json:"gameNumber,omitempty"`
-	GameStateID       *int64                      `json:"gameStateId,omitempty"`
-	PrevGameStateID   *int64                      `json:"prevGameStateId,omitempty"`
-	GameStateType     string                      `json:"gameStateType,omitempty"`
-	GameStage         string                      `json:"gameStage,omitempty"`
-	TurnNumber        *int64                      `json:"turnNumber,omitempty"`
-	Phase             string                      `json:"phase,omitempty"`
-	SelfLifeTotal     *int64                      `json:"selfLifeTotal,omitempty"`
-	OpponentLifeTotal *int64                      `json:"opponentLifeTotal,omitempty"`
-	WinningPlayerSide string                      `json:"winningPlayerSide,omitempty"`
-	WinReason         string                      `json:"winReason,omitempty"`
-	RecordedAt        string                      `json:"recordedAt,omitempty"`
-	ActionsJSON       string                      `json:"actionsJson,omitempty"`
-	AnnotationsJSON   string                      `json:"annotationsJson,omitempty"`
-	Objects           []MatchReplayFrameObjectRow `json:"objects,omitempty"`
-	Changes           []MatchReplayChangeRow      `json:"changes,omitempty"`
-}
-
-type MatchDetail struct {
-	Match                 MatchRow                  `json:"match"`
-	OpponentObservedCards []OpponentObservedCardRow `json:"opponentObservedCards"`
-	CardPlays             []MatchCardPlayRow        `json:"cardPlays"`
-	Games                 []GameRow                 `json:"games"`
-	Coverage              MatchAnalyticsCoverage    `json:"coverage"`
-}
-
-type OpeningHandCardRow struct {
-	CardID   int64  `json:"cardId"`
-	Quantity int64  `json:"quantity"`
-	CardName string `json:"cardName,omitempty"`
-	Kept     bool   `json:"kept"`
-}
-
-type OpeningHandRow struct {
-	ID              int64                `json:"id"`
-	AttemptNumber   int64                `json:"attemptNumber"`
-	Decision        string               `json:"decision"`
-	OfferedHandSize int64                `json:"offeredHandSize"`
-	KeptHandSize    *int64               `json:"keptHandSize,omitempty"`
-	ObservedAt      string               `json:"observedAt,omitempty"`
-	Source          string               `json:"source"`
-	Confidence      string               `json:"confidence"`
-	Cards           []OpeningHandCardRow `json:"cards"`
-}
-
-type GameRow struct {
-	ID                    int64            `json:"id"`
-	GameNumber            int64            `json:"gameNumber"`
-	Result                string           `json:"result"`
-	WinReason             string           `json:"winReason,omitempty"`
-	PlayDraw              string           `json:"playDraw,omitempty"`
-	StartedAt             string           `json:"startedAt,omitempty"`
-	EndedAt               string           `json:"endedAt,omitempty"`
-	TurnCount             *int64           `json:"turnCount,omitempty"`
-	OpeningLifeTotal      *int64           `json:"openingLifeTotal,omitempty"`
-	EndingLifeTotal       *int64           `json:"endingLifeTotal,omitempty"`
-	MulliganCount         *int64           `json:"mulliganCount,omitempty"`
-	KeptHandSize          *int64           `json:"keptHandSize,omitempty"`
-	MinSelfLife           *int64           `json:"minSelfLife,omitempty"`
-	MinOpponentLife       *int64           `json:"minOpponentLife,omitempty"`
-	ResultSource          string           `json:"resultSource,omitempty"`
-	ResultConfidence      string           `json:"resultConfidence"`
-	PlayDrawSource        string           `json:"playDrawSource,omitempty"`
-	PlayDrawConfidence    string           `json:"playDrawConfidence"`
-	OpeningHandSource     string           `json:"openingHandSource,omitempty"`
-	OpeningHandConfidence string           `json:"openingHandConfidence"`
-	OpeningHands          []OpeningHandRow `json:"openingHands"`
-	TurnStats             []GameTurnStatRow `json:"turnStats"`
-	Flags                 []GameFlagRow    `json:"flags"`
-}
-
-// GameTurnStatRow is one turn's derived shape. Life, hand size, and land-in-hand
-// reflect the last replay frame of the turn; lands/spells count the player's
-// card plays classified by cached type line with a public-zone fallback.
-// Nil pointers mean the underlying data was not observable, never zero.
-type GameTurnStatRow struct {
-	TurnNumber   int64  `json:"turnNumber"`
-	IsPlayerTurn *bool  `json:"isPlayerTurn,omitempty"`
-	SelfLife     *int64 `json:"selfLife,omitempty"`
-	OpponentLife *int64 `json:"opponentLife,omitempty"`
-	SelfHandSize *int64 `json:"selfHandSize,omitempty"`
-	LandsPlayed  int64  `json:"landsPlayed"`
-	SpellsCast   int64  `json:"spellsCast"`
-	LandInHand   *bool  `json:"landInHand,omitempty"`
-}
-
-// GameFlagRow is a descriptive decision-review flag computed from turn stats
-// at read time. Every flag is a heuristic prompt for replay review, not a
-// judgment of the play.
-type GameFlagRow struct {
-	Flag       string `json:"flag"`
-	TurnNumber *int64 `json:"turnNumber,omitempty"`
-	Detail     string `json:"detail,omitempty"`
-	Confidence string `json:"confidence"`
-}
-
-type MatchAnalyticsCoverage struct {
-	ReplayAvailable       bool   `json:"replayAvailable"`
-	ReplayFrameCount      int64  `json:"replayFrameCount"`
-	GameCount             int64  `json:"gameCount"`
-	GamesWithResult       int64  `json:"gamesWithResult"`
-	GamesWithOpeningHand  int64  `json:"gamesWithOpeningHand"`
-	GamesWithPlayDraw     int64  `json:"gamesWithPlayDraw"`
-	GamesWithTurnStats    int64  `json:"gamesWithTurnStats"`
-	DeckSnapshotAvailable bool   `json:"deckSnapshotAvailable"`
-	DeckVersionAvailable  bool   `json:"deckVersionAvailable"`
-	OverallConfidence     string `json:"overallConfidence"`
-	DerivedAt             string `json:"derivedAt,omitempty"`
-}
-
-// RecordAgg is a win/loss/draw tally over games with a known result. Unknown
-// results are excluded and reported separately wherever a RecordAgg appears.
-type RecordAgg struct {
-	Games  int64 `json:"games"`
-	Wins   int64 `json:"wins"`
-	Losses int64 `json:"losses"`
-	Draws  int64 `json:"draws"`
-}
-
-// AnalyticsBucket groups games sharing one integer fact (kept-hand size,
-// mulligan count, kept land count) with the record inside that group.
-type AnalyticsBucket struct {
-	Key            int64     `json:"key"`
-	Record         RecordAgg `json:"record"`
-	UnknownResults int64     `json:"unknownResults"`
-}
-
-type DeckCardPerformance struct {
-	CardID             int64     `json:"cardId"`
-	CardName           string    `json:"cardName,omitempty"`
-	GamesSeen          int64     `json:"gamesSeen"`
-	UnknownResultGames int64     `json:"unknownResultGames"`
-	OpeningHand        RecordAgg `json:"openingHand"`
-	Drawn              RecordAgg `json:"drawn"`
-	InHand             RecordAgg `json:"inHand"`
-	Played             RecordAgg `json:"played"`
-	NotPlayed          RecordAgg `json:"notPlayed"`
-	GameOne            RecordAgg `json:"gameOne"`
-	PostBoard          RecordAgg `json:"postBoard"`
-	OnPlay             RecordAgg `json:"onPlay"`
-	OnDraw             RecordAgg `json:"onDraw"`
-	EndedInHandGames   int64     `json:"endedInHandGames"`
-	MulliganGames      int64     `json:"mulliganGames"`
-	MulliganCopies     int64     `json:"mulliganCopies"`
-	AvgFirstSeenTurn   *float64  `json:"avgFirstSeenTurn,omitempty"`
-	AvgFirstPlayedTurn *float64  `json:"avgFirstPlayedTurn,omitempty"`
-	AvgCopiesSeen      *float64  `json:"avgCopiesSeen,omitempty"`
-	AvgCopiesPlayed    *float64  `json:"avgCopiesPlayed,omitempty"`
-}
-
-type DeckAnalyticsCoverage struct {
-	Matches              int64 `json:"matches"`
-	MatchesWithVersion   int64 `json:"matchesWithVersion"`
-	GameCount            int64 `json:"gameCount"`
-	GamesWithResult      int64 `json:"gamesWithResult"`
-	GamesWithOpeningHand int64 `json:"gamesWithOpeningHand"`
-	GamesWithPlayDraw    int64 `json:"gamesWithPlayDraw"`
-	GamesWithCardStats   int64 `json:"gamesWithCardStats"`
-	GamesWithTurnStats   int64 `json:"gamesWithTurnStats"`
-	GamesWithLandJudged  int64 `json:"gamesWithLandJudged"`
-}
-
-// DeckTurnCurvePoint aggregates game shape at one turn across a deck's games
-// with turn stats, split by result. Lands are cumulative land drops through
-// the turn; spells are casts on that turn. Games are only counted at turns
-// they actually reached, and averages are nil when no game qualifies.
-type DeckTurnCurvePoint struct {
-	Turn           int64    `json:"turn"`
-	WinGames       int64    `json:"winGames"`
-	LossGames      int64    `json:"lossGames"`
-	AvgLandsWins   *float64 `json:"avgLandsWins,omitempty"`
-	AvgLandsLosses *float64 `json:"avgLandsLosses,omitempty"`
-	AvgSpellsWins  *float64 `json:"avgSpellsWins,omitempty"`
-	AvgSpellsLosses *float64 `json:"avgSpellsLosses,omitempty"`
-}
-
-// DeckGameShape is the deck-level game-shape rollup: win rate by ending turn,
-// average winning/losing turn, missed-land-drop record splits (judged games
-// only), and per-turn land/spell curves split by result.
-type DeckGameShape struct {
-	GameLengths     []AnalyticsBucket    `json:"gameLengths"`
-	AvgWinningTurn  *float64             `json:"avgWinningTurn,omitempty"`
-	AvgLosingTurn   *float64             `json:"avgLosingTurn,omitempty"`
-	LowestWinLife   *int64               `json:"lowestWinLife,omitempty"`
-	MissedDropGames RecordAgg            `json:"missedDropGames"`
-	CleanDropGames  RecordAgg            `json:"cleanDropGames"`
-	MissedDropUnknownGames int64         `json:"missedDropUnknownGames"`
-	TurnCurve       []DeckTurnCurvePoint `json:"turnCurve"`
-}
-
-type DeckAnalytics struct {
-	DeckID                int64                 `json:"deckId"`
-	DeckVersionID         *int64                `json:"deckVersionId,omitempty"`
-	Coverage              DeckAnalyticsCoverage `json:"coverage"`
-	MatchRecord           RecordAgg             `json:"matchRecord"`
-	GameRecord            RecordAgg             `json:"gameRecord"`
-	UnknownResultGames    int64                 `json:"unknownResultGames"`
-	GameOne               RecordAgg             `json:"gameOne"`
-	PostBoard             RecordAgg             `json:"postBoard"`
-	OnPlay                RecordAgg             `json:"onPlay"`
-	OnDraw                RecordAgg             `json:"onDraw"`
-	AverageMulligans      *float64              `json:"averageMulligans,omitempty"`
-	HandSizes             []AnalyticsBucket     `json:"handSizes"`
-	MulliganCounts        []AnalyticsBucket     `json:"mulliganCounts"`
-	LandCounts            []AnalyticsBucket     `json:"landCounts"`
-	LandCountUnknownHands int64                 `json:"landCountUnknownHands"`
-	Cards                 []DeckCardPerformance `json:"cards"`
-	Shape                 DeckGameShape         `json:"shape"`
-}
-
-// DeckAnalyticsGameRef links one aggregated statistic back to a concrete game
-// so the UI can navigate to the match detail and replay it came from.
-type DeckAnalyticsGameRef struct {
-	MatchID           int64  `json:"matchId"`
-	GameNumber        int64  `json:"gameNumber"`
-	Result            string `json:"result"`
-	PlayDraw          string `json:"playDraw,omitempty"`
-	StartedAt         string `json:"startedAt,omitempty"`
-	Opponent          string `json:"opponent,omitempty"`
-	EventName         string `json:"eventName,omitempty"`
-	KeptHandSize      *int64 `json:"keptHandSize,omitempty"`
-	MulliganCount     *int64 `json:"mulliganCount,omitempty"`
-	OpeningKeptCopies int64  `json:"openingKeptCopies,omitempty"`
-	DrawnCopies       int64  `json:"drawnCopies,omitempty"`
-	PlayedCopies      int64  `json:"playedCopies,omitempty"`
-	EndInHandCopies   int64  `json:"endInHandCopies,omitempty"`
-	FirstPlayedTurn   *int64 `json:"firstPlayedTurn,omitempty"`
-}
-
-// OpponentClassification is the derived (or manually corrected) read on one
-// match's opponent, built only from locally observed cards.
-type OpponentClassification struct {
-	Colors        []string `json:"colors"`
-	ColorsKnown   bool     `json:"colorsKnown"`
-	Archetype     string   `json:"archetype"`
-	Source        string   `json:"source"` // "derived" or "manual"
-	Confidence    string   `json:"confidence"`
-	DistinctCards int64    `json:"distinctCards"`
-	ObservedCards int64    `json:"observedCards"`
-	PctObserved   float64  `json:"pctObserved"`
-	AvgManaValue  *float64 `json:"avgManaValue,omitempty"`
-	CreatureShare *float64 `json:"creatureShare,omitempty"`
-}
-
-type MatchupObservedCard struct {
-	CardID     int64  `json:"cardId"`
-	CardName   string `json:"cardName,omitempty"`
-	Matches    int64  `json:"matches"`
-	Copies     int64  `json:"copies"`
-	WinMatches int64  `json:"winMatches"`
-	LossMatches int64 `json:"lossMatches"`
-}
-
-type MatchupMatchRef struct {
-	MatchID         int64    `json:"matchId"`
-	Opponent        string   `json:"opponent,omitempty"`
-	EventName       string   `json:"eventName,omitempty"`
-	Result          string   `json:"result"`
-	StartedAt       string   `json:"startedAt,omitempty"`
-	Colors          []string `json:"colors"`
-	Archetype       string   `json:"archetype"`
-	ArchetypeSource string   `json:"archetypeSource"`
-	Confidence      string   `json:"confidence"`
-	PctObserved     float64  `json:"pctObserved"`
-}
-
-// MatchupRow is one (opponent colors × archetype) group for a single deck.
-type MatchupRow struct {
-	ColorsKey          string                `json:"colorsKey"`
-	Colors             []string              `json:"colors"`
-	Archetype          string                `json:"archetype"`
-	Matches            RecordAgg             `json:"matches"`
-	Games              RecordAgg             `json:"games"`
-	UnknownResultGames int64                 `json:"unknownResultGames"`
-	GameOne            RecordAgg             `json:"gameOne"`
-	PostBoard          RecordAgg             `json:"postBoard"`
-	OnPlay             RecordAgg             `json:"onPlay"`
-	OnDraw             RecordAgg             `json:"onDraw"`
-	AvgPctObserved     float64               `json:"avgPctObserved"`
-	Confidence         string                `json:"confidence"`
-	TopObservedCards   []MatchupObservedCard `json:"topObservedCards"`
-	LossSkewedCards    []MatchupObservedCard `json:"lossSkewedCards"`
-	MatchRefs          []MatchupMatchRef     `json:"matchRefs"`
-}
-
-type MatchupDeck struct {
-	DeckID   int64        `json:"deckId"`
-	DeckName string       `json:"deckName"`
-	Format   string       `json:"format"`
-	Matches  RecordAgg    `json:"matches"`
-	Rows     []MatchupRow `json:"rows"`
-}
-
-type MatchupsResponse struct {
-	Decks      []MatchupDeck `json:"decks"`
-	Archetypes []string      `json:"archetypes"`
-}
-
-// DeckMatchupsResponse is one deck's matchup data for the deck detail page;
-// Deck is null when the deck has no deck-linked matches yet.
-type DeckMatchupsResponse struct {
-	Deck       *MatchupDeck `json:"deck"`
-	Archetypes []string     `json:"archetypes"`
-}
-
-// LimitedMatchupColorGroup pools the matches you played with one of your own
-// deck color identities within a set; Rows group by opponent color pair.
-type LimitedMatchupColorGroup struct {
-	ColorsKey   string       `json:"colorsKey"`
-	Colors      []string     `json:"colors"`
-	ColorsKnown bool         `json:"colorsKnown"`
-	Matches     RecordAgg    `json:"matches"`
-	DeckCount   int64        `json:"deckCount"`
-	Rows        []MatchupRow `json:"rows"`
-}
-
-// LimitedMatchupSet pools every limited match of one set; rows group by
-// opponent color pair (Archetype is blank on limited rows), and ColorGroups
-// split the same matches by your own deck's color identity.
-type LimitedMatchupSet struct {
-	SetCode     string                     `json:"setCode"`
-	Matches     RecordAgg                  `json:"matches"`
-	DeckCount   int64                      `json:"deckCount"`
-	Rows        []MatchupRow               `json:"rows"`
-	ColorGroups []LimitedMatchupColorGroup `json:"colorGroups"`
-}
-
-type LimitedMatchupsResponse struct {
-	Sets []LimitedMatchupSet `json:"sets"`
-}
-
-// MatchGameSummary aggregates one match's derived games for matchup rollups.
-type MatchGameSummary struct {
-	Games        RecordAgg
-	UnknownGames int64
-	GameOne      RecordAgg
-	PostBoard    RecordAgg
-	OnPlay       RecordAgg
-	OnDraw       RecordAgg
-}
-
-type DeckSummaryRow struct {
-	DeckID        int64   `json:"deckId"`
-	DeckName      string  `json:"deckName"`
-	Format        string  `json:"format"`
-	EventName     string  `json:"eventName"`
-	Matches       int64   `json:"matches"`
-	Wins          int64   `json:"wins"`
-	Losses        int64   `json:"losses"`
-	WinRate       float64 `json:"winRate"`
-	FirstPlayedAt string  `json:"firstPlayedAt,omitempty"`
-	LastUpdatedAt string  `json:"lastUpdatedAt,omitempty"`
-}
-
-type DeckCardRow struct {
-	Section  string `json:"section"`
-	CardID   int64  `json:"cardId"`
-	Quantity int64  `json:"quantity"`
-	CardName string `json:"cardName,omitempty"`
-}
-
-type DeckDetail struct {
-	DeckID      int64            `json:"deckId"`
-	ArenaDeckID string           `json:"arenaDeckId"`
-	Name        string           `json:"name"`
-	Format      string           `json:"format"`
-	EventName   string           `json:"eventName"`
-	Cards       []DeckCardRow    `json:"cards"`
-	Matches     []MatchRow       `json:"matches"`
-	Versions    []DeckVersionRow `json:"versions"`
-}
-
-type DeckVersionRow struct {
-	ID            int64         `json:"id"`
-	VersionNumber int64         `json:"versionNumber"`
-	CardsHash     string        `json:"cardsHash"`
-	Source        string        `json:"source,omitempty"`
-	EffectiveAt   string        `json:"effectiveAt,omitempty"`
-	Cards         []DeckCardRow `json:"cards,omitempty"`
-}
-
-// DeckPrimer is a cached AI-generated strategy primer for a deck. Stale is
-// computed at read time by comparing CardsHash against the current deck list.
-type DeckPrimer struct {
-	DeckID    int64  `json:"deckId"`
-	CardsHash string `json:"cardsHash"`
-	Model     string `json:"model"`
-	Content   string `json:"content"`
-	CreatedAt string `json:"createdAt"`
-	Stale     bool   `json:"stale"`
-}
-
-type DraftSessionRow struct {
-	ID          int64   `json:"id"`
-	EventName   string  `json:"eventName"`
-	DraftID     *string `json:"draftId"`
-	IsBotDraft  bool    `json:"isBotDraft"`
-	StartedAt   string  `json:"startedAt"`
-	CompletedAt string  `json:"completedAt"`
-	Picks       int64   `json:"picks"`
-	Wins        *int64  `json:"wins,omitempty"`
-	Losses      *int64  `json:"losses,omitempty"`
-}
-
-type DraftPickRow struct {
-	ID            int64           `json:"id"`
-	PackNumber    int64           `json:"packNumber"`
-	PickNumber    int64           `json:"pickNumber"`
-	PickedCardIDs string          `json:"pickedCardIds"`
-	PackCardIDs   string          `json:"packCardIds"`
-	PickTs        string          `json:"pickTs"`
-	PickedCards   []DraftPickCard `json:"pickedCards,omitempty"`
-	PackCards     []DraftPickCard `json:"packCards,omitempty"`
-}
-
-type DraftPickCard struct {
-	CardID   int64  `json:"cardId"`
-	CardName string `json:"cardName,omitempty"`
-}
-
-type LiveMatch struct {
-	Match                 MatchRow                  `json:"match"`
-	OpponentObservedCards []OpponentObservedCardRow `json:"opponentObservedCards"`
-	Deck                  []DeckCardRow             `json:"deck"`
-	DeckTotal             int64                     `json:"deckTotal"`
-	LandCount             int64                     `json:"landCount"`
-	GameNumber            int64                     `json:"gameNumber"`
-	TurnNumber            int64                     `json:"turnNumber"`
-	LibraryEstimate       int64                     `json:"libraryEstimate"`
-}
-
-type SetInfo struct {
-	Code       string `json:"code"`
-	Name       string `json:"name"`
-	IconSvgURI string `json:"iconSvgUri,omitempty"`
-	ReleasedAt string `json:"releasedAt,omitempty"`
-}
-
-type Overview struct {
-	PlayerName   string     `json:"playerName,omitempty"`
-	TotalMatches int64      `json:"totalMatches"`
-	Wins         int64      `json:"wins"`
-	Losses       int64      `json:"losses"`
-	WinRate      float64    `json:"winRate"`
-	Recent       []MatchRow `json:"recent"`
-}
-
-type WildcardBalance struct {
-	Common   int64 `json:"common"`
-	Uncommon int64 `json:"uncommon"`
-	Rare     int64 `json:"rare"`
-	Mythic   int64 `json:"mythic"`
-}
-
-type EconomyBoosterCount struct {
-	SetCode string `json:"setCode"`
-	Count   int64  `json:"count"`
-}
-
-type EconomySnapshot struct {
-	ID                    int64                 `json:"id"`
-	ObservedAt            string                `json:"observedAt"`
-	SequenceID            int64                 `json:"sequenceId"`
-	Gold                  int64                 `json:"gold"`
-	Gems                  int64                 `json:"gems"`
-	VaultProgress         int64                 `json:"vaultProgress"`
-	WildcardTrackPosition int64                 `json:"wildcardTrackPosition"`
-	Wildcards             WildcardBalance       `json:"wildcards"`
-	CustomTokens          map[string]int64      `json:"customTokens"`
-	Boosters              []EconomyBoosterCount `json:"boosters"`
-	Vouchers              map[string]int64      `json:"vouchers"`
-	ChangeSources         []string              `json:"changeSources"`
-}
-
-// EconomyTransaction is one normalized entry of an InventoryInfo Changes
-// array. EventName attributes the change to an event run where derivable;
-// EventLink says how ("source_id" and "event_name" are exact, "proximity" is
-// a time-window heuristic).
-type EconomyTransaction struct {
-	ID                 int64                 `json:"id"`
-	ObservedAt         string                `json:"observedAt"`
-	Source             string                `json:"source"`
-	EventName          string                `json:"eventName"`
-	EventLink          string                `json:"eventLink"`
-	GoldDelta          int64                 `json:"goldDelta"`
-	GemsDelta          int64                 `json:"gemsDelta"`
-	WildcardDeltas     WildcardBalance       `json:"wildcardDeltas"`
-	CardsGranted       int64                 `json:"cardsGranted"`
-	VaultProgressDelta int64                 `json:"vaultProgressDelta"`
-	Boosters           []EconomyBoosterCount `json:"boosters"`
-	CustomTokens       map[string]int64      `json:"customTokens"`
-	Vouchers           map[string]int64      `json:"vouchers"`
-}
-
-// EventRunEconomy is the cost/reward summary of one event run. Entry deltas
-// are negative; net values keep gold and gems separate deliberately.
-type EventRunEconomy struct {
-	EventName           string                `json:"eventName"`
-	EventType           string                `json:"eventType"`
-	SetCode             string                `json:"setCode"`
-	Status              string                `json:"status"`
-	StartedAt           string                `json:"startedAt"`
-	EndedAt             string                `json:"endedAt"`
-	Wins                int64                 `json:"wins"`
-	Losses              int64                 `json:"losses"`
-	EntryCurrencyType   string                `json:"entryCurrencyType"`
-	EntryCurrencyPaid   *int64                `json:"entryCurrencyPaid"`
-	EntryGold           int64                 `json:"entryGold"`
-	EntryGems           int64                 `json:"entryGems"`
-	RewardGold          int64                 `json:"rewardGold"`
-	RewardGems          int64                 `json:"rewardGems"`
-	RewardBoosters      []EconomyBoosterCount `json:"rewardBoosters"`
-	RewardCards         int64                 `json:"rewardCards"`
-	RewardVaultProgress int64                 `json:"rewardVaultProgress"`
-	NetGold             int64                 `json:"netGold"`
-	NetGems             int64                 `json:"netGems"`
-	// exact | inferred | none: whether reward attribution used only exact
-	// links, any proximity heuristics, or found no reward data at all.
-	LinkConfidence string `json:"linkConfidence"`
-}
-
-type EconomyHistory struct {
-	Latest       *EconomySnapshot     `json:"latest"`
-	History      []EconomySnapshot    `json:"history"`
-	Transactions []EconomyTransaction `json:"transactions"`
-	EventRuns    []EventRunEconomy    `json:"eventRuns"`
-}
-
-type RankState struct {
-	SeasonOrdinal *int64 `json:"seasonOrdinal"`
-	RankClass     string `json:"rankClass"`
-	Level         *int64 `json:"level"`
-	Step          *int64 `json:"step"`
-	MatchesWon    *int64 `json:"matchesWon"`
-	MatchesLost   *int64 `json:"matchesLost"`
-}
-
-type RankHistoryPoint struct {
-	MatchID      int64     `json:"matchId"`
-	ArenaMatchID string    `json:"arenaMatchId"`
-	EventName    string    `json:"eventName"`
-	Opponent     string    `json:"opponent"`
-	Result       string    `json:"result"`
-	ObservedAt   string    `json:"observedAt"`
-	EndedAt      string    `json:"endedAt"`
-	Format       string    `json:"format"`
-	SecondsCount *int64    `json:"secondsCount"`
-	DeckID       *int64    `json:"deckId"`
-	DeckName     string    `json:"deckName"`
-	Constructed  RankState `json:"constructed"`
-	Limited      RankState `json:"limited"`
-}
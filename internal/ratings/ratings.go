@@ -0,0 +1,195 @@
+// Package ratings reads 17Lands card ratings, for `ponder ratings import`:
+// either the JSON its card_ratings endpoint serves or the CSV the card data
+// page exports. Draft picks are graded against the GIH WR (win rate when the
+// card was in hand) these carry.
+package ratings
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+// CardRatingsURL is 17Lands' card ratings endpoint, queried by expansion
+// and format.
+const CardRatingsURL = "https://www.17lands.com/card_ratings/data"
+
+// DefaultFormat is the 17Lands format ratings are fetched for when none is
+// given.
+const DefaultFormat = "PremierDraft"
+
+const userAgent = "ponder/0.1 (local tracker)"
+
+// Fetch downloads a set's ratings for format from 17Lands. baseURL is
+// CardRatingsURL when empty.
+func Fetch(ctx context.Context, client *http.Client, baseURL, setCode, format string) ([]db.CardRating, error) {
+	if baseURL == "" {
+		baseURL = CardRatingsURL
+	}
+	query := url.Values{}
+	query.Set("expansion", strings.ToUpper(setCode))
+	query.Set("format", format)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("build 17lands request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request 17lands ratings: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("17lands status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return Read(res.Body)
+}
+
+// Read parses ratings saved from 17Lands, telling the endpoint's JSON from
+// the card data CSV by its first character.
+func Read(r io.Reader) ([]db.CardRating, error) {
+	buffered := bufio.NewReader(r)
+	for {
+		ch, _, err := buffered.ReadRune()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read ratings: %w", err)
+		}
+		if unicode.IsSpace(ch) || ch == '\uFEFF' {
+			continue
+		}
+		if err := buffered.UnreadRune(); err != nil {
+			return nil, fmt.Errorf("read ratings: %w", err)
+		}
+		if ch == '[' {
+			return readJSON(buffered)
+		}
+		return readCSV(buffered)
+	}
+}
+
+// jsonRating is one card as the card_ratings endpoint reports it.
+type jsonRating struct {
+	Name                    string   `json:"name"`
+	MTGAID                  int64    `json:"mtga_id"`
+	EverDrawnGameCount      int64    `json:"ever_drawn_game_count"`
+	EverDrawnWinRate        *float64 `json:"ever_drawn_win_rate"`
+	WinRate                 *float64 `json:"win_rate"`
+	OpeningHandWinRate      *float64 `json:"opening_hand_win_rate"`
+	DrawnImprovementWinRate *float64 `json:"drawn_improvement_win_rate"`
+	AvgSeen                 *float64 `json:"avg_seen"`
+	AvgPick                 *float64 `json:"avg_pick"`
+}
+
+func readJSON(r io.Reader) ([]db.CardRating, error) {
+	var rows []jsonRating
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("decode 17lands ratings: %w", err)
+	}
+	out := make([]db.CardRating, 0, len(rows))
+	for _, row := range rows {
+		rating := db.CardRating{
+			Name:        strings.TrimSpace(row.Name),
+			ArenaID:     row.MTGAID,
+			GamesInHand: row.EverDrawnGameCount,
+			GIHWR:       row.EverDrawnWinRate,
+			GPWR:        row.WinRate,
+			OHWR:        row.OpeningHandWinRate,
+			ALSA:        row.AvgSeen,
+			ATA:         row.AvgPick,
+		}
+		// The endpoint reports IWD as a fraction; the CSV and 17Lands' own
+		// pages show percentage points.
+		if row.DrawnImprovementWinRate != nil {
+			iwd := *row.DrawnImprovementWinRate * 100
+			rating.IWD = &iwd
+		}
+		out = append(out, rating)
+	}
+	return out, nil
+}
+
+func readCSV(r io.Reader) ([]db.CardRating, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read ratings header: %w", err)
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index["NAME"]; !ok {
+		return nil, fmt.Errorf("ratings csv has no Name column; header was %s", strings.Join(header, ", "))
+	}
+	if _, ok := index["GIH WR"]; !ok {
+		return nil, fmt.Errorf("ratings csv has no GIH WR column; header was %s", strings.Join(header, ", "))
+	}
+	field := func(record []string, column string) string {
+		i, ok := index[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var out []db.CardRating
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read ratings csv: %w", err)
+		}
+		gamesInHand, _ := strconv.ParseInt(field(record, "# GIH"), 10, 64)
+		out = append(out, db.CardRating{
+			Name:        field(record, "NAME"),
+			GamesInHand: gamesInHand,
+			GIHWR:       parsePercent(field(record, "GIH WR")),
+			GPWR:        parsePercent(field(record, "GP WR")),
+			OHWR:        parsePercent(field(record, "OH WR")),
+			IWD:         parseNumber(strings.TrimSuffix(field(record, "IWD"), "pp")),
+			ALSA:        parseNumber(field(record, "ALSA")),
+			ATA:         parseNumber(field(record, "ATA")),
+		})
+	}
+	return out, nil
+}
+
+// parsePercent reads a CSV win rate such as "58.3%" as a fraction.
+func parsePercent(raw string) *float64 {
+	value := parseNumber(strings.TrimSuffix(raw, "%"))
+	if value == nil {
+		return nil
+	}
+	fraction := *value / 100
+	return &fraction
+}
+
+func parseNumber(raw string) *float64 {
+	value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
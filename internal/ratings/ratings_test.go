@@ -0,0 +1,43 @@
+package ratings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadParsesCSVExport(t *testing.T) {
+	csv := "\uFEFF\"Name\",\"Color\",\"Rarity\",\"# Seen\",\"ALSA\",\"# Picked\",\"ATA\",\"# GP\",\"GP WR\",\"# OH\",\"OH WR\",\"# GD\",\"GD WR\",\"# GIH\",\"GIH WR\",\"# GNS\",\"GNS WR\",\"IWD\"\n" +
+		"\"Season of the Burrow\",\"W\",\"M\",\"100\",\"1.50\",\"90\",\"1.20\",\"500\",\"60.0%\",\"200\",\"61.2%\",\"150\",\"62.0%\",\"350\",\"61.5%\",\"150\",\"55.0%\",\"6.5pp\"\n" +
+		"\"Barely Played\",\"U\",\"C\",\"3\",\"\",\"0\",\"\",\"0\",\"\",\"0\",\"\",\"0\",\"\",\"0\",\"\",\"0\",\"\",\"\"\n"
+	got, err := Read(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Read returned %d ratings, want 2", len(got))
+	}
+	first := got[0]
+	if first.Name != "Season of the Burrow" || first.GamesInHand != 350 || first.GIHWR == nil || *first.GIHWR != 0.615 {
+		t.Fatalf("first rating = %+v, want Season of the Burrow at 0.615 over 350 games", first)
+	}
+	if first.IWD == nil || *first.IWD != 6.5 || first.ATA == nil || *first.ATA != 1.2 {
+		t.Fatalf("first rating IWD/ATA = %v/%v, want 6.5/1.2", first.IWD, first.ATA)
+	}
+	if got[1].GIHWR != nil {
+		t.Fatalf("unrated card GIH WR = %v, want nil", *got[1].GIHWR)
+	}
+}
+
+func TestReadParsesEndpointJSON(t *testing.T) {
+	body := `[{"name": "Mabel, Heir to Cragflame", "mtga_id": 91234, "ever_drawn_game_count": 1200, "ever_drawn_win_rate": 0.642, "win_rate": 0.6, "drawn_improvement_win_rate": 0.071, "avg_pick": 1.4}]`
+	got, err := Read(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(got) != 1 || got[0].ArenaID != 91234 || got[0].GIHWR == nil || *got[0].GIHWR != 0.642 {
+		t.Fatalf("Read = %+v, want Mabel with mtga id and GIH WR", got)
+	}
+	if got[0].IWD == nil || *got[0].IWD < 7.09 || *got[0].IWD > 7.11 {
+		t.Fatalf("IWD = %v, want 7.1 percentage points", got[0].IWD)
+	}
+}
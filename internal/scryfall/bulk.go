@@ -0,0 +1,241 @@
+// Package scryfall imports Scryfall's bulk card data into the local card
+// caches, so name, type, color, set, and rarity lookups for every Arena card
+// are answered from SQLite instead of per-request API calls. Each import also
+// records the week's paper prices.
+package scryfall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+// DefaultCardsURL describes Scryfall's "Default Cards" bulk file: one object
+// per English printing, which covers every card with an Arena id.
+const DefaultCardsURL = "https://api.scryfall.com/bulk-data/default-cards"
+
+const userAgent = "ponder/0.1 (local tracker)"
+
+// colorOrder is WUBRG, the order card_metadata stores colors in.
+var colorOrder = []string{"W", "U", "B", "R", "G"}
+
+// BulkFile is the bulk-data descriptor Scryfall serves for a bulk type.
+type BulkFile struct {
+	UpdatedAt   string `json:"updated_at"`
+	DownloadURI string `json:"download_uri"`
+	Size        int64  `json:"size"`
+}
+
+// SyncResult reports one Sync: the bulk file version, the Arena cards
+// imported, and whether the import was skipped as already current.
+type SyncResult struct {
+	Version string
+	Cards   int
+	Skipped bool
+}
+
+// Syncer downloads a bulk card file and imports its Arena cards.
+type Syncer struct {
+	// Client makes the requests. The default-cards file is several hundred
+	// megabytes, so it should not carry a short overall timeout.
+	Client *http.Client
+	// BulkURL is the bulk-data descriptor to read; DefaultCardsURL if empty.
+	BulkURL string
+}
+
+// Sync imports the current bulk file into store. Unless force is set, a file
+// whose version matches the last import is not downloaded again.
+func (s Syncer) Sync(ctx context.Context, store *db.Store, force bool) (SyncResult, error) {
+	bulk, err := s.fetchBulkFile(ctx)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	result := SyncResult{Version: bulk.UpdatedAt}
+	if !force {
+		current, err := store.CardCatalogVersion(ctx)
+		if err != nil {
+			return result, err
+		}
+		if current != "" && current == bulk.UpdatedAt {
+			result.Skipped = true
+			return result, nil
+		}
+	}
+
+	body, err := s.get(ctx, bulk.DownloadURI)
+	if err != nil {
+		return result, fmt.Errorf("download bulk cards: %w", err)
+	}
+	defer body.Close()
+	cards, err := DecodeArenaCards(body)
+	if err != nil {
+		return result, err
+	}
+	if err := store.ImportCardCatalog(ctx, cards, bulk.UpdatedAt); err != nil {
+		return result, err
+	}
+	result.Cards = len(cards)
+	return result, nil
+}
+
+func (s Syncer) fetchBulkFile(ctx context.Context) (BulkFile, error) {
+	bulkURL := s.BulkURL
+	if bulkURL == "" {
+		bulkURL = DefaultCardsURL
+	}
+	body, err := s.get(ctx, bulkURL)
+	if err != nil {
+		return BulkFile{}, fmt.Errorf("request bulk data: %w", err)
+	}
+	defer body.Close()
+	var bulk BulkFile
+	if err := json.NewDecoder(body).Decode(&bulk); err != nil {
+		return BulkFile{}, fmt.Errorf("decode bulk data: %w", err)
+	}
+	if strings.TrimSpace(bulk.DownloadURI) == "" {
+		return BulkFile{}, fmt.Errorf("bulk data has no download_uri")
+	}
+	return bulk, nil
+}
+
+func (s Syncer) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		res.Body.Close()
+		return nil, fmt.Errorf("status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return res.Body, nil
+}
+
+type bulkCard struct {
+	ArenaID         int64    `json:"arena_id"`
+	Name            string   `json:"name"`
+	TypeLine        string   `json:"type_line"`
+	Colors          []string `json:"colors"`
+	ColorIdentity   []string `json:"color_identity"`
+	ManaValue       *float64 `json:"cmc"`
+	Set             string   `json:"set"`
+	CollectorNumber string   `json:"collector_number"`
+	Rarity          string   `json:"rarity"`
+	Prices          struct {
+		USD string `json:"usd"`
+		EUR string `json:"eur"`
+	} `json:"prices"`
+	CardFaces []struct {
+		TypeLine string   `json:"type_line"`
+		Colors   []string `json:"colors"`
+	} `json:"card_faces"`
+}
+
+// DecodeArenaCards streams a bulk card array and keeps the cards that have an
+// Arena id, without holding the whole file in memory.
+func DecodeArenaCards(r io.Reader) ([]db.CatalogCard, error) {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("read bulk cards: %w", err)
+	} else if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("read bulk cards: expected an array")
+	}
+
+	var cards []db.CatalogCard
+	seen := make(map[int64]bool)
+	for dec.More() {
+		var card bulkCard
+		if err := dec.Decode(&card); err != nil {
+			return nil, fmt.Errorf("decode bulk card: %w", err)
+		}
+		if card.ArenaID <= 0 || seen[card.ArenaID] {
+			continue
+		}
+		seen[card.ArenaID] = true
+		cards = append(cards, catalogCard(card))
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("read bulk cards: %w", err)
+	}
+	return cards, nil
+}
+
+func catalogCard(card bulkCard) db.CatalogCard {
+	// Double-faced and reversible cards may carry their type lines and
+	// colors only on the faces.
+	typeLine := strings.TrimSpace(card.TypeLine)
+	colors := card.Colors
+	if typeLine == "" || colors == nil {
+		faceTypes := make([]string, 0, len(card.CardFaces))
+		var faceColors []string
+		for _, face := range card.CardFaces {
+			if line := strings.TrimSpace(face.TypeLine); line != "" {
+				faceTypes = append(faceTypes, line)
+			}
+			faceColors = append(faceColors, face.Colors...)
+		}
+		if typeLine == "" {
+			typeLine = strings.Join(faceTypes, " // ")
+		}
+		if colors == nil {
+			colors = faceColors
+		}
+	}
+
+	return db.CatalogCard{
+		ArenaID:  card.ArenaID,
+		Name:     card.Name,
+		TypeLine: typeLine,
+		USD:      parsePrice(card.Prices.USD),
+		EUR:      parsePrice(card.Prices.EUR),
+		Metadata: db.CardMetadata{
+			ColorIdentity:   wubrg(card.ColorIdentity),
+			Colors:          wubrg(colors),
+			ManaValue:       card.ManaValue,
+			SetCode:         strings.ToLower(strings.TrimSpace(card.Set)),
+			CollectorNumber: strings.TrimSpace(card.CollectorNumber),
+			Rarity:          strings.ToLower(strings.TrimSpace(card.Rarity)),
+		},
+	}
+}
+
+// parsePrice reads one of Scryfall's decimal price strings; null (a printing
+// not sold on paper, such as an Alchemy card) is nil.
+func parsePrice(value string) *float64 {
+	price, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || price < 0 {
+		return nil
+	}
+	return &price
+}
+
+// wubrg renders a color list as a WUBRG-ordered subset string ("UG").
+func wubrg(colors []string) string {
+	present := make(map[string]bool, len(colors))
+	for _, color := range colors {
+		present[strings.ToUpper(strings.TrimSpace(color))] = true
+	}
+	var out strings.Builder
+	for _, color := range colorOrder {
+		if present[color] {
+			out.WriteString(color)
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,95 @@
+package scryfall
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+const testBulkCards = `[
+	{"arena_id": 90001, "name": "Lightning Strike", "type_line": "Instant", "color_identity": ["R"], "cmc": 2, "set": "M19", "rarity": "uncommon", "prices": {"usd": "0.25", "usd_foil": "1.10", "eur": "0.19", "eur_foil": null}},
+	{"name": "Paper Only", "type_line": "Creature", "color_identity": [], "cmc": 1, "set": "lea", "rarity": "rare"},
+	{"arena_id": 90002, "name": "Fable of the Mirror-Breaker // Reflection of Kiki-Jiki", "color_identity": ["R"], "cmc": 3, "set": "neo", "rarity": "rare",
+	 "card_faces": [{"type_line": "Enchantment — Saga"}, {"type_line": "Enchantment Creature — Goblin Shaman"}]},
+	{"arena_id": 90003, "name": "Growth Spiral", "type_line": "Instant", "colors": ["G", "U"], "color_identity": ["U", "G"], "cmc": 2, "set": "rna", "collector_number": "178", "rarity": "common"}
+]`
+
+func TestSyncImportsArenaCards(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	store := db.NewStore(database)
+
+	downloads := 0
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bulk-data/default-cards":
+			_, _ = w.Write([]byte(`{"updated_at": "2026-05-01T09:00:00+00:00", "download_uri": "` + srv.URL + `/default-cards.json"}`))
+		case "/default-cards.json":
+			downloads++
+			_, _ = w.Write([]byte(testBulkCards))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	syncer := Syncer{Client: srv.Client(), BulkURL: srv.URL + "/bulk-data/default-cards"}
+	result, err := syncer.Sync(ctx, store, false)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if result.Cards != 3 || result.Skipped {
+		t.Fatalf("result = %+v, want 3 cards imported", result)
+	}
+
+	ids := []int64{90001, 90002, 90003}
+	names, err := store.LookupCardNames(ctx, ids)
+	if err != nil || names[90001] != "Lightning Strike" {
+		t.Fatalf("names = %v, %v", names, err)
+	}
+	typeLines, err := store.LookupCardTypeLines(ctx, ids)
+	if err != nil || typeLines[90002] != "Enchantment — Saga // Enchantment Creature — Goblin Shaman" {
+		t.Fatalf("type lines = %v, %v", typeLines, err)
+	}
+	metadata, err := store.LookupCardMetadata(ctx, ids)
+	if err != nil {
+		t.Fatalf("LookupCardMetadata: %v", err)
+	}
+	if meta := metadata[90003]; meta.ColorIdentity != "UG" || meta.Colors != "UG" || meta.SetCode != "rna" || meta.CollectorNumber != "178" || meta.Rarity != "common" || meta.ManaValue == nil || *meta.ManaValue != 2 {
+		t.Fatalf("Growth Spiral metadata = %+v", meta)
+	}
+
+	prices, err := store.LatestCardPrices(ctx, ids)
+	if err != nil {
+		t.Fatalf("LatestCardPrices: %v", err)
+	}
+	if price, ok := prices[90001]; !ok || price.USD == nil || *price.USD != 0.25 || price.EUR == nil || *price.EUR != 0.19 {
+		t.Fatalf("Lightning Strike price = %+v", prices[90001])
+	}
+	if _, ok := prices[90002]; ok || len(prices) != 1 {
+		t.Fatalf("prices = %v, want only the priced card", prices)
+	}
+
+	// An unchanged bulk file is not downloaded again unless forced.
+	if result, err := syncer.Sync(ctx, store, false); err != nil || !result.Skipped {
+		t.Fatalf("second Sync = %+v, %v; want skipped", result, err)
+	}
+	if _, err := syncer.Sync(ctx, store, true); err != nil {
+		t.Fatalf("forced Sync: %v", err)
+	}
+	if downloads != 2 {
+		t.Fatalf("downloads = %d, want 2", downloads)
+	}
+}
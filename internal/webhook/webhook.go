@@ -0,0 +1,166 @@
+// Package webhook posts ingest events to user-configured URLs as signed
+// JSON, so home automation and custom dashboards can react to a match or a
+// draft pick without polling the API.
+//
+// Each delivery is a POST of
+//
+//	{"id": "...", "event": "match.ended", "sentAt": "...", "data": {...}}
+//
+// with X-Ponder-Event and X-Ponder-Delivery headers and, when a secret is
+// set, X-Ponder-Signature: sha256=<hex HMAC-SHA256 of the body>.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/solean/ponder/pkg/ingest"
+)
+
+const userAgent = "ponder/0.1 (local tracker)"
+
+// SignatureHeader carries the body's HMAC-SHA256 under the shared secret.
+const SignatureHeader = "X-Ponder-Signature"
+
+// queueSize bounds events waiting for delivery; past it new events are
+// dropped, so a dead endpoint cannot grow memory or stall ingest.
+const queueSize = 256
+
+// retryDelays spaces the attempts after a failed delivery.
+var retryDelays = []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}
+
+// Payload is the JSON body of a delivery.
+type Payload struct {
+	ID     string         `json:"id"`
+	Event  string         `json:"event"`
+	SentAt string         `json:"sentAt"`
+	Data   map[string]any `json:"data"`
+}
+
+// Dispatcher delivers events to every URL in order, one at a time, from the
+// goroutine Run starts.
+type Dispatcher struct {
+	urls   []string
+	secret []byte
+	client *http.Client
+	queue  chan ingest.Event
+	now    func() time.Time
+	delays []time.Duration
+}
+
+// New returns a dispatcher for urls, signing with secret when it is set.
+func New(urls []string, secret string) *Dispatcher {
+	return &Dispatcher{
+		urls:   urls,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: 10 * time.Second},
+		queue:  make(chan ingest.Event, queueSize),
+		now:    time.Now,
+		delays: retryDelays,
+	}
+}
+
+// Send queues event for delivery without blocking; it has the shape of an
+// ingest.EventSink.
+func (d *Dispatcher) Send(event ingest.Event) {
+	select {
+	case d.queue <- event:
+	default:
+		log.Printf("webhook queue full; dropped %s event", event.Type)
+	}
+}
+
+// Run delivers queued events until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-d.queue:
+			payload := Payload{
+				ID:     newDeliveryID(),
+				Event:  event.Type,
+				SentAt: d.now().UTC().Format(time.RFC3339Nano),
+				Data:   event.Data,
+			}
+			body, err := json.Marshal(payload)
+			if err != nil {
+				log.Printf("encode %s webhook: %v", event.Type, err)
+				continue
+			}
+			for _, url := range d.urls {
+				if err := d.deliver(ctx, url, payload, body); err != nil && ctx.Err() == nil {
+					log.Printf("webhook %s to %s failed: %v", payload.Event, url, err)
+				}
+			}
+		}
+	}
+}
+
+// deliver posts body to url, retrying network errors, 429s, and 5xx
+// responses; any other status is final.
+func (d *Dispatcher) deliver(ctx context.Context, url string, payload Payload, body []byte) error {
+	for attempt := 0; ; attempt++ {
+		retry, err := d.post(ctx, url, payload.Event, payload.ID, body)
+		if err == nil || !retry || attempt >= len(d.delays) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d.delays[attempt]):
+		}
+	}
+}
+
+func (d *Dispatcher) post(ctx context.Context, url, eventType, deliveryID string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Ponder-Event", eventType)
+	req.Header.Set("X-Ponder-Delivery", deliveryID)
+	if len(d.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(d.secret, body))
+	}
+	res, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return false, nil
+	}
+	excerpt, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	err = fmt.Errorf("status %d: %s", res.StatusCode, strings.TrimSpace(string(excerpt)))
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500, err
+}
+
+// Sign returns the signature header value for body: "sha256=" and the hex
+// HMAC-SHA256 of body under secret. Receivers recompute it to check a
+// delivery came from ponder.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newDeliveryID() string {
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/solean/ponder/pkg/ingest"
+)
+
+func TestDispatcherSignsAndRetriesDeliveries(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	delivered := make(chan Payload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		attempts++
+		first := attempts == 1
+		mu.Unlock()
+		if first {
+			http.Error(w, "warming up", http.StatusServiceUnavailable)
+			return
+		}
+		if got, want := r.Header.Get(SignatureHeader), Sign([]byte("s3cret"), body); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if got := r.Header.Get("X-Ponder-Event"); got != ingest.EventMatchEnded {
+			t.Errorf("X-Ponder-Event = %q", got)
+		}
+		var payload Payload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		delivered <- payload
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := New([]string{server.URL}, "s3cret")
+	dispatcher.delays = []time.Duration{time.Millisecond}
+	go dispatcher.Run(ctx)
+	dispatcher.Send(ingest.Event{Type: ingest.EventMatchEnded, Data: map[string]any{"arenaMatchId": "m1", "result": "win"}})
+
+	select {
+	case payload := <-delivered:
+		if payload.Event != ingest.EventMatchEnded || payload.ID == "" || payload.Data["result"] != "win" {
+			t.Fatalf("payload = %+v, want a match.ended win", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("webhook was not delivered after a retry")
+	}
+}
+
+func TestDispatcherDoesNotRetryClientErrors(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		http.Error(w, "bad", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	dispatcher := New([]string{server.URL}, "")
+	dispatcher.delays = []time.Duration{time.Millisecond, time.Millisecond}
+	err := dispatcher.deliver(context.Background(), server.URL, Payload{ID: "d1", Event: ingest.EventDraftPick}, []byte(`{}`))
+	if err == nil {
+		t.Fatalf("deliver succeeded on a 400")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+}
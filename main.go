@@ -2,23 +2,19 @@ package main
 
 import (
 	"context"
-	"embed"
-	"io/fs"
 	"log"
 
+	"github.com/solean/ponder/web"
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
 )
 
-//go:embed all:web/dist
-var embeddedAssets embed.FS
-
 // App display name — change here to rebrand. Keep web/src/lib/branding.ts in sync.
 const appDisplayName = "Ponder"
 
 func main() {
-	assets, err := fs.Sub(embeddedAssets, "web/dist")
+	assets, err := web.Dist()
 	if err != nil {
 		log.Fatalf("prepare embedded web assets: %v", err)
 	}
@@ -24,8 +24,9 @@ func migrateAnalyticsTables(ctx context.Context, conn dbConn) error {
 		return fmt.Errorf("index match deck versions: %w", err)
 	}
 
-	// Game-shape columns arrived with turn-stat analytics; older databases
-	// already have the tables, so add the columns in place.
+	// Game-shape columns arrived with turn-stat analytics (queue wait with
+	// matchmaking stats, card set codes with the card lookup API); older
+	// databases already have the tables, so add the columns in place.
 	shapeColumns := []struct {
 		table  string
 		column string
@@ -35,6 +36,12 @@ func migrateAnalyticsTables(ctx context.Context, conn dbConn) error {
 		{"games", "min_opponent_life", `ALTER TABLE games ADD COLUMN min_opponent_life INTEGER`},
 		{"match_analytics_coverage", "games_with_turn_stats",
 			`ALTER TABLE match_analytics_coverage ADD COLUMN games_with_turn_stats INTEGER NOT NULL DEFAULT 0`},
+		{"matches", "queue_seconds", `ALTER TABLE matches ADD COLUMN queue_seconds INTEGER`},
+		{"games", "self_spells_cast", `ALTER TABLE games ADD COLUMN self_spells_cast INTEGER`},
+		{"games", "opponent_spells_cast", `ALTER TABLE games ADD COLUMN opponent_spells_cast INTEGER`},
+		{"games", "self_attackers_declared", `ALTER TABLE games ADD COLUMN self_attackers_declared INTEGER`},
+		{"games", "opponent_attackers_declared", `ALTER TABLE games ADD COLUMN opponent_attackers_declared INTEGER`},
+		{"card_metadata", "set_code", `ALTER TABLE card_metadata ADD COLUMN set_code TEXT NOT NULL DEFAULT ''`},
 	}
 	for _, migration := range shapeColumns {
 		hasColumn, err := tableHasColumn(ctx, conn, migration.table, migration.column)
@@ -57,6 +64,8 @@ const cardStatsBackfillMetadataKey = "card_stats_backfill_v2"
 
 const gameShapeBackfillMetadataKey = "game_shape_backfill_v1"
 
+const gameActionCountsBackfillMetadataKey = "game_action_counts_backfill_v1"
+
 // v2: v1 counted simultaneous instances across every zone, which still
 // overcounted a recast card whose stale instances linger in graveyard/exile/
 // limbo; v2 restricts the count to the clean battlefield/hand zones.
@@ -70,6 +79,12 @@ func prepareGameShapeBackfill(ctx context.Context, conn dbConn) error {
 	return invalidateAnalyticsCoverageOnce(ctx, conn, gameShapeBackfillMetadataKey)
 }
 
+// prepareGameActionCountsBackfill invalidates analytics coverage once so every
+// match re-derives the per-game spell and attacker counts.
+func prepareGameActionCountsBackfill(ctx context.Context, conn dbConn) error {
+	return invalidateAnalyticsCoverageOnce(ctx, conn, gameActionCountsBackfillMetadataKey)
+}
+
 // prepareOpponentCopiesBackfill invalidates analytics coverage once so every
 // match re-derives and fills match_opponent_card_counts from archived replays.
 func prepareOpponentCopiesBackfill(ctx context.Context, conn dbConn) error {
@@ -208,7 +223,7 @@ func backfillDeckVersions(ctx context.Context, conn dbConn) error {
 		}
 		cardRows.Close()
 
-		versionID, err := upsertDeckVersion(ctx, tx, seed.id, seed.source, seed.effectiveAt, cards)
+		versionID, err := upsertDeckVersion(ctx, tx, seed.id, seed.source, seed.effectiveAt, nowUTC(), cards)
 		if err != nil {
 			return err
 		}
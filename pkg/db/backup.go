@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// IntegrityCheck runs PRAGMA integrity_check and fails with the reported
+// problems when SQLite finds any.
+func (s *Store) IntegrityCheck(ctx context.Context) error {
+	problems, err := s.integrityProblems(ctx)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("integrity check failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// integrityProblems returns every line of PRAGMA integrity_check other than
+// "ok"; empty means the file is sound.
+func (s *Store) integrityProblems(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		return nil, fmt.Errorf("integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	problems := []string{}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("scan integrity check: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate integrity check: %w", err)
+	}
+	return problems, nil
+}
+
+// BackupTo writes a consistent, compacted copy of the database to path with
+// VACUUM INTO. Live ingest keeps running; the copy reflects one snapshot.
+// path must not exist yet.
+func (s *Store) BackupTo(ctx context.Context, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create backup dir: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("vacuum into %s: %w", path, err)
+	}
+	return nil
+}
+
+// RestoreDatabase replaces the database at dbPath with a copy of the backup at
+// backupPath, which must pass integrity_check. The current file, if any, is
+// checkpointed and kept beside it as <name>.pre-restore-<timestamp>, whose
+// path is returned so a mistaken restore can be undone. Nothing else may have
+// dbPath open: tail, serve, and the desktop app must be stopped first.
+func RestoreDatabase(ctx context.Context, backupPath, dbPath string) (string, error) {
+	backupAbs, err := filepath.Abs(backupPath)
+	if err != nil {
+		return "", fmt.Errorf("resolve backup path: %w", err)
+	}
+	dbAbs, err := filepath.Abs(dbPath)
+	if err != nil {
+		return "", fmt.Errorf("resolve database path: %w", err)
+	}
+	if backupAbs == dbAbs {
+		return "", fmt.Errorf("backup and database are the same file")
+	}
+	if err := verifyBackup(ctx, backupAbs); err != nil {
+		return "", err
+	}
+
+	staged := dbAbs + ".restore-tmp"
+	if err := copyFile(backupAbs, staged); err != nil {
+		_ = os.Remove(staged)
+		return "", fmt.Errorf("stage backup: %w", err)
+	}
+
+	var aside string
+	if _, err := os.Stat(dbAbs); err == nil {
+		// Fold the WAL into the main file so the kept copy is complete on
+		// its own.
+		current, err := Open(dbAbs)
+		if err != nil {
+			_ = os.Remove(staged)
+			return "", err
+		}
+		_, err = current.ExecContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`)
+		_ = current.Close()
+		if err != nil {
+			_ = os.Remove(staged)
+			return "", fmt.Errorf("checkpoint current database: %w", err)
+		}
+		aside = dbAbs + ".pre-restore-" + time.Now().UTC().Format("20060102-150405")
+		if err := os.Rename(dbAbs, aside); err != nil {
+			_ = os.Remove(staged)
+			return "", fmt.Errorf("move current database aside: %w", err)
+		}
+		for _, suffix := range []string{"-wal", "-shm"} {
+			_ = os.Remove(dbAbs + suffix)
+		}
+	} else if !os.IsNotExist(err) {
+		_ = os.Remove(staged)
+		return "", fmt.Errorf("check current database: %w", err)
+	}
+
+	if err := os.Rename(staged, dbAbs); err != nil {
+		return aside, fmt.Errorf("move restored database into place: %w", err)
+	}
+	return aside, nil
+}
+
+// verifyBackup checks that path is an intact ponder database without
+// modifying it.
+func verifyBackup(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	backup, err := OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer backup.Close()
+	if err := NewStore(backup).IntegrityCheck(ctx); err != nil {
+		return fmt.Errorf("backup %w", err)
+	}
+	var tables int
+	if err := backup.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('app_metadata', 'matches')
+	`).Scan(&tables); err != nil {
+		return fmt.Errorf("inspect backup: %w", err)
+	}
+	if tables != 2 {
+		return fmt.Errorf("%s is not a ponder database", path)
+	}
+	return nil
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(to, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupToWritesOpenableCopy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	database, err := Open(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	store := NewStore(database)
+
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	if _, err := store.UpsertMatchStart(ctx, tx, "match-1", "Ladder", 1, "2026-07-12T18:00:00Z"); err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("insert match: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	if err := store.IntegrityCheck(ctx); err != nil {
+		t.Fatalf("IntegrityCheck: %v", err)
+	}
+	backupPath := filepath.Join(dir, "backups", "copy.db")
+	if err := store.BackupTo(ctx, backupPath); err != nil {
+		t.Fatalf("BackupTo: %v", err)
+	}
+	if err := store.BackupTo(ctx, backupPath); err == nil {
+		t.Fatalf("BackupTo over an existing file succeeded, want error")
+	}
+
+	backup, err := Open(backupPath)
+	if err != nil {
+		t.Fatalf("open backup: %v", err)
+	}
+	defer backup.Close()
+	var matches int64
+	if err := backup.QueryRowContext(ctx, `SELECT COUNT(*) FROM matches`).Scan(&matches); err != nil {
+		t.Fatalf("count backup matches: %v", err)
+	}
+	if matches != 1 {
+		t.Fatalf("backup matches = %d, want 1", matches)
+	}
+}
+
+func TestRestoreDatabaseReplacesDatabaseAndKeepsPrevious(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "test.db")
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	store := NewStore(database)
+	insertMatch := func(eventID string) {
+		t.Helper()
+		tx, err := store.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("begin tx: %v", err)
+		}
+		if _, err := store.UpsertMatchStart(ctx, tx, eventID, "Ladder", 1, "2026-07-12T18:00:00Z"); err != nil {
+			_ = tx.Rollback()
+			t.Fatalf("insert match: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+	}
+	insertMatch("match-1")
+	backupPath := filepath.Join(dir, "copy.db")
+	if err := store.BackupTo(ctx, backupPath); err != nil {
+		t.Fatalf("BackupTo: %v", err)
+	}
+	insertMatch("match-2")
+	if err := database.Close(); err != nil {
+		t.Fatalf("close db: %v", err)
+	}
+
+	notPonder := filepath.Join(dir, "other.db")
+	if err := os.WriteFile(notPonder, []byte("not a database"), 0o644); err != nil {
+		t.Fatalf("write other file: %v", err)
+	}
+	if _, err := RestoreDatabase(ctx, notPonder, dbPath); err == nil {
+		t.Fatalf("RestoreDatabase from a non-database file succeeded, want error")
+	}
+
+	aside, err := RestoreDatabase(ctx, backupPath, dbPath)
+	if err != nil {
+		t.Fatalf("RestoreDatabase: %v", err)
+	}
+	if _, err := os.Stat(aside); err != nil {
+		t.Fatalf("previous database not kept at %q: %v", aside, err)
+	}
+
+	restored, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("open restored db: %v", err)
+	}
+	defer restored.Close()
+	var matches int64
+	if err := restored.QueryRowContext(ctx, `SELECT COUNT(*) FROM matches`).Scan(&matches); err != nil {
+		t.Fatalf("count restored matches: %v", err)
+	}
+	if matches != 1 {
+		t.Fatalf("restored matches = %d, want 1", matches)
+	}
+}
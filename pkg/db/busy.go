@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// BusyRetry is how a Store retries a write SQLite refused because another
+// process held the lock past busy_timeout, as happens when tail and serve
+// share a database file and one of them is in a long ingest batch. Waits
+// grow from BaseDelay, doubling up to MaxDelay, each shortened by a random
+// jitter so two waiting processes do not retry in lockstep. Only the start
+// of a transaction and single-statement writes are retried: SQLite keeps
+// nothing of a write it refused there, so a retry cannot apply it twice.
+type BusyRetry struct {
+	// Attempts is how many times a write is tried in all; 1 or less never
+	// retries.
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultBusyRetry is what NewStore uses. On top of the 5s busy_timeout of
+// each attempt it waits out a writer holding the lock for about half a
+// minute before giving up.
+var DefaultBusyRetry = BusyRetry{Attempts: 5, BaseDelay: 250 * time.Millisecond, MaxDelay: 4 * time.Second}
+
+// IsBusy reports whether err is SQLite refusing a lock another connection
+// holds (SQLITE_BUSY or SQLITE_LOCKED, with any extended code).
+func IsBusy(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	switch sqliteErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	}
+	return false
+}
+
+// Do calls fn until it returns an error IsBusy rejects, or Attempts run
+// out. It gives up early with ctx's error when ctx is done while waiting.
+func (r BusyRetry) Do(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.Attempts || !IsBusy(err) {
+			return err
+		}
+		timer := time.NewTimer(r.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// delay is the wait after the attempt'th try: BaseDelay doubled per attempt
+// up to MaxDelay, less up to half of it at random.
+func (r BusyRetry) delay(attempt int) time.Duration {
+	d := r.BaseDelay
+	for i := 1; i < attempt && d < r.MaxDelay; i++ {
+		d *= 2
+	}
+	if r.MaxDelay > 0 && d > r.MaxDelay {
+		d = r.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// SetBusyRetry replaces the store's BusyRetry.
+func (s *Store) SetBusyRetry(retry BusyRetry) {
+	s.busyRetry = retry
+}
+
+// exec runs a single-statement write outside any transaction, retrying it
+// while the database is busy.
+func (s *Store) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var res sql.Result
+	err := s.busyRetry.Do(ctx, func() error {
+		var err error
+		res, err = s.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBeginTxRetriesWhileAnotherWriterHoldsTheLock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "busy.db")
+	holder, err := Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer holder.Close()
+	if err := Init(ctx, holder); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	// A second handle that gives up on the lock at once, so the store's
+	// retries rather than busy_timeout do the waiting.
+	impatient, err := sql.Open("sqlite", fileDSN(path, "_txlock=immediate&_pragma=busy_timeout(0)"))
+	if err != nil {
+		t.Fatalf("open impatient: %v", err)
+	}
+	defer impatient.Close()
+	store := NewStore(impatient)
+
+	lock, err := holder.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("hold write lock: %v", err)
+	}
+
+	store.SetBusyRetry(BusyRetry{Attempts: 1})
+	if _, err := store.BeginTx(ctx); !IsBusy(err) {
+		t.Fatalf("BeginTx without retries = %v, want busy", err)
+	}
+
+	store.SetBusyRetry(BusyRetry{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond})
+	if _, err := store.RecordDeckPush(ctx, 1, "test", "remote"); !IsBusy(err) {
+		t.Fatalf("write with retries exhausted = %v, want busy", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	store.SetBusyRetry(BusyRetry{Attempts: 100, BaseDelay: time.Hour, MaxDelay: time.Hour})
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := store.BeginTx(canceled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("BeginTx after cancel = %v, want context.Canceled", err)
+	}
+
+	store.SetBusyRetry(BusyRetry{Attempts: 50, BaseDelay: 5 * time.Millisecond, MaxDelay: 20 * time.Millisecond})
+	time.AfterFunc(30*time.Millisecond, func() { _ = lock.Rollback() })
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx once the lock is released: %v", err)
+	}
+	_ = tx.Rollback()
+}
+
+func TestBusyRetryDelayGrowsWithJitter(t *testing.T) {
+	t.Parallel()
+
+	retry := BusyRetry{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for range 50 {
+		if d := retry.delay(1); d < 50*time.Millisecond || d > 100*time.Millisecond {
+			t.Fatalf("delay(1) = %v, want 50ms..100ms", d)
+		}
+		if d := retry.delay(2); d < 100*time.Millisecond || d > 200*time.Millisecond {
+			t.Fatalf("delay(2) = %v, want 100ms..200ms", d)
+		}
+		if d := retry.delay(6); d < 150*time.Millisecond || d > 300*time.Millisecond {
+			t.Fatalf("delay(6) = %v, want capped at 300ms", d)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package db
+
+import "time"
+
+// Clock supplies the current time for timestamps the store writes itself
+// (created_at/updated_at, staleness cutoffs). Tests swap in a fixed clock so
+// stored rows and time-based queries are deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a plain function to Clock.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock reads the wall clock.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock always reports t. Its location is also the one the parser reads
+// local log timestamps in, so tests control the timezone as well.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
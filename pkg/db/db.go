@@ -26,7 +26,22 @@ const dsnOptions = "_txlock=immediate" +
 	"&_pragma=journal_mode(WAL)" +
 	"&_pragma=synchronous(NORMAL)"
 
+// readerDSNOptions configure OpenReader's pool. query_only makes an
+// accidental write fail loudly instead of taking the write lock; WAL mode is
+// a property of the file, already set by the writer handle.
+const readerDSNOptions = "_pragma=busy_timeout(5000)" +
+	"&_pragma=foreign_keys(1)" +
+	"&_pragma=query_only(1)"
+
+// readerPoolSize bounds OpenReader's connections; API requests fan out a few
+// queries each, and WAL readers never wait on each other.
+const readerPoolSize = 8
+
 func dsn(path string) string {
+	return fileDSN(path, dsnOptions)
+}
+
+func fileDSN(path, options string) string {
 	// url.URL renders a relative path as file://<first-segment>/..., which
 	// SQLite reads as an authority, so the path must be absolute.
 	if abs, err := filepath.Abs(path); err == nil {
@@ -36,10 +51,12 @@ func dsn(path string) string {
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
-	u := url.URL{Scheme: "file", Path: path, RawQuery: dsnOptions}
+	u := url.URL{Scheme: "file", Path: path, RawQuery: options}
 	return u.String()
 }
 
+// Open opens (creating if needed) the SQLite database at path. Call Init on
+// the handle before handing it to NewStore.
 func Open(path string) (*sql.DB, error) {
 	db, err := sql.Open("sqlite", dsn(path))
 	if err != nil {
@@ -47,7 +64,8 @@ func Open(path string) (*sql.DB, error) {
 	}
 
 	// WAL allows readers to run alongside the single writer; a small pool
-	// keeps API reads from queueing behind ingest write batches.
+	// keeps reads on this handle from queueing behind ingest write batches.
+	// Servers also give their store a dedicated pool from OpenReader.
 	db.SetMaxOpenConns(4)
 	db.SetMaxIdleConns(4)
 
@@ -59,6 +77,25 @@ func Open(path string) (*sql.DB, error) {
 	return db, nil
 }
 
+// OpenReader opens a read-only connection pool on a database that Open and
+// Init have already set up. Handing it to Store.SetReader moves the store's
+// plain reads off the writer pool, so API requests keep their own
+// connections and snapshots while ingest holds the write lock.
+func OpenReader(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", fileDSN(path, readerDSNOptions))
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite reader: %w", err)
+	}
+	db.SetMaxOpenConns(readerPoolSize)
+	db.SetMaxIdleConns(readerPoolSize)
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping sqlite reader: %w", err)
+	}
+	return db, nil
+}
+
 // dbConn abstracts *sql.DB and *sql.Conn so migrations can run on a dedicated
 // connection whose pragmas differ from the pool's.
 type dbConn interface {
@@ -68,6 +105,13 @@ type dbConn interface {
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
+// SchemaVersion is stamped into PRAGMA user_version once Init has applied the
+// baseline schema and its in-place upgrades. New schema changes are numbered
+// migrations (schema_migrations.go) rather than bumps here.
+const SchemaVersion = 3
+
+// Init applies the schema and any pending migrations. It is safe to call on
+// every start.
 func Init(ctx context.Context, db *sql.DB) error {
 	schema, err := schemaFS.ReadFile("schema.sql")
 	if err != nil {
@@ -119,6 +163,10 @@ func Init(ctx context.Context, db *sql.DB) error {
 		return err
 	}
 
+	if err := prepareGameActionCountsBackfill(ctx, conn); err != nil {
+		return err
+	}
+
 	if err := backfillDeckVersions(ctx, conn); err != nil {
 		return err
 	}
@@ -139,6 +187,14 @@ func Init(ctx context.Context, db *sql.DB) error {
 		return err
 	}
 
+	if _, err := migrateTo(ctx, conn, LatestMigration()); err != nil {
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, SchemaVersion)); err != nil {
+		return fmt.Errorf("stamp schema version: %w", err)
+	}
+
 	return nil
 }
 
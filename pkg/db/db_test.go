@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestMigrateMatchObservationTablesRepairsReplayObjectForeignKey(t *testing.T) {
@@ -391,6 +392,55 @@ func TestMigrateMatchObservationTablesAddsReplayResultColumnsWithoutTouchingObje
 	})
 }
 
+func TestOpenReaderServesStoreReadsWhileWriting(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.db")
+	writer, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = writer.Close() })
+	if err := Init(ctx, writer); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	// One writer connection makes any read that needs it wait on the open
+	// transaction below.
+	writer.SetMaxOpenConns(1)
+	reader, err := OpenReader(path)
+	if err != nil {
+		t.Fatalf("OpenReader: %v", err)
+	}
+	t.Cleanup(func() { _ = reader.Close() })
+	store := NewStore(writer)
+	store.SetReader(reader)
+
+	if err := store.UpsertCardNames(ctx, map[int64]string{1: "Opt"}); err != nil {
+		t.Fatalf("UpsertCardNames: %v", err)
+	}
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(ctx, `UPDATE card_catalog SET name = 'Consider' WHERE arena_id = 1`); err != nil {
+		t.Fatalf("update in tx: %v", err)
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	names, err := store.LookupCardNames(readCtx, []int64{1})
+	if err != nil {
+		t.Fatalf("LookupCardNames during write: %v", err)
+	}
+	if names[1] != "Opt" {
+		t.Fatalf("name = %q, want the committed %q", names[1], "Opt")
+	}
+
+	if _, err := reader.ExecContext(ctx, `DELETE FROM card_catalog`); err == nil {
+		t.Fatal("write on the reader pool succeeded, want query_only error")
+	}
+}
+
 func openTempSQLiteDB(t *testing.T) *sql.DB {
 	t.Helper()
 
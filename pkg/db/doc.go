@@ -0,0 +1,17 @@
+// Package db is the tracker's SQLite store. Open and Init set up a
+// database, and NewStore wraps it for the parser (package ingest) and for
+// reads:
+//
+//	database, err := db.Open("ponder.db")
+//	if err != nil {
+//		return err
+//	}
+//	defer database.Close()
+//	if err := db.Init(ctx, database); err != nil {
+//		return err
+//	}
+//	store := db.NewStore(database)
+//	overview, err := store.Overview(ctx, 20, db.OverviewScope{})
+//
+// Results are the types in package model.
+package db
@@ -0,0 +1,362 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// doctorGracePeriod keeps a match or draft that may still be in progress out
+// of the "without results" and "without picks" checks.
+const doctorGracePeriod = 24 * time.Hour
+
+// doctorSampleLimit caps how many example rows each issue lists.
+const doctorSampleLimit = 5
+
+// DoctorIssue is one kind of problem Doctor found, with the repair it plans.
+// Fixable issues are repaired by Doctor(ctx, true); Fixed says how many rows
+// that changed.
+type DoctorIssue struct {
+	Check   string
+	Found   int
+	Fixed   int
+	Samples []string
+	Repair  string
+	Fixable bool
+}
+
+// DoctorReport lists every issue Doctor found; empty means healthy.
+type DoctorReport struct {
+	Issues []DoctorIssue
+}
+
+// Healthy reports whether no check found anything.
+func (r DoctorReport) Healthy() bool {
+	return len(r.Issues) == 0
+}
+
+// doctorRow is one offending row: its id for the fix, and how to show it.
+// Foreign key rows also carry the violation, since the id alone does not say
+// which table it is in.
+type doctorRow struct {
+	id    int64
+	label string
+	fk    *foreignKeyViolation
+}
+
+// doctorCheck finds one kind of problem and, when it can be repaired
+// automatically, fixes the rows it found in tx.
+type doctorCheck struct {
+	name   string
+	repair string
+	find   func(ctx context.Context, s *Store) ([]doctorRow, error)
+	fix    func(ctx context.Context, s *Store, tx *sql.Tx, rows []doctorRow) (int, error)
+}
+
+// Doctor checks the database file and the data in it: SQLite's
+// integrity_check, dangling foreign keys, matches that never got a result,
+// decks without cards, and draft sessions without picks. With fix, every
+// fixable issue is repaired in one transaction. A file that fails
+// integrity_check is reported alone, since nothing read from it can be
+// trusted.
+func (s *Store) Doctor(ctx context.Context, fix bool) (DoctorReport, error) {
+	report := DoctorReport{Issues: []DoctorIssue{}}
+
+	problems, err := s.integrityProblems(ctx)
+	if err != nil {
+		return report, err
+	}
+	if len(problems) > 0 {
+		report.Issues = append(report.Issues, DoctorIssue{
+			Check:   "integrity",
+			Found:   len(problems),
+			Samples: sampleLabels(problems),
+			Repair:  "the file itself is damaged; restore a backup with `ponder restore`, or rebuild by parsing your logs into a new database",
+		})
+		return report, nil
+	}
+
+	type finding struct {
+		check doctorCheck
+		rows  []doctorRow
+	}
+	var findings []finding
+	for _, check := range doctorChecks {
+		rows, err := check.find(ctx, s)
+		if err != nil {
+			return report, fmt.Errorf("doctor %s: %w", check.name, err)
+		}
+		if len(rows) > 0 {
+			findings = append(findings, finding{check: check, rows: rows})
+		}
+	}
+
+	var tx *sql.Tx
+	if fix && len(findings) > 0 {
+		tx, err = s.BeginTx(ctx)
+		if err != nil {
+			return report, fmt.Errorf("begin doctor fix: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+	}
+	for _, found := range findings {
+		labels := make([]string, 0, len(found.rows))
+		for _, row := range found.rows {
+			labels = append(labels, row.label)
+		}
+		issue := DoctorIssue{
+			Check:   found.check.name,
+			Found:   len(found.rows),
+			Samples: sampleLabels(labels),
+			Repair:  found.check.repair,
+			Fixable: found.check.fix != nil,
+		}
+		if tx != nil && issue.Fixable {
+			issue.Fixed, err = found.check.fix(ctx, s, tx, found.rows)
+			if err != nil {
+				return report, fmt.Errorf("doctor fix %s: %w", found.check.name, err)
+			}
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+	if tx != nil {
+		if err := tx.Commit(); err != nil {
+			return report, fmt.Errorf("commit doctor fix: %w", err)
+		}
+	}
+	return report, nil
+}
+
+func sampleLabels(labels []string) []string {
+	if len(labels) > doctorSampleLimit {
+		return labels[:doctorSampleLimit]
+	}
+	return labels
+}
+
+var doctorChecks = []doctorCheck{
+	{
+		name:   "foreign_keys",
+		repair: "-fix clears each dangling reference the way its ON DELETE rule would have (NULL for SET NULL, otherwise the row is deleted)",
+		find:   findForeignKeyViolations,
+		fix:    fixForeignKeyViolations,
+	},
+	{
+		name:   "matches_without_result",
+		repair: "re-run `ponder parse` on the logs you still have to recover results; -fix archives the rest so they stop counting in stats (unarchive to restore)",
+		find:   findMatchesWithoutResult,
+		fix:    fixMatchesWithoutResult,
+	},
+	{
+		name:   "decks_without_cards",
+		repair: "run `ponder reprocess` to reload deck lists from stored raw events; -fix deletes the ones no match uses",
+		find:   findDecksWithoutCards,
+		fix:    fixDecksWithoutCards,
+	},
+	{
+		name:   "drafts_without_picks",
+		repair: "-fix deletes the empty draft sessions; `ponder reprocess` recreates any whose picks are still in raw events",
+		find:   findDraftsWithoutPicks,
+		fix:    fixDraftsWithoutPicks,
+	},
+}
+
+// queryDoctorRows runs query, which must select an id and a label.
+func (s *Store) queryDoctorRows(ctx context.Context, query string, args ...any) ([]doctorRow, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []doctorRow
+	for rows.Next() {
+		var row doctorRow
+		if err := rows.Scan(&row.id, &row.label); err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+// foreignKeyViolation is one PRAGMA foreign_key_check row.
+type foreignKeyViolation struct {
+	table  string
+	rowID  sql.NullInt64
+	parent string
+	fkID   int64
+}
+
+func (s *Store) foreignKeyViolations(ctx context.Context) ([]foreignKeyViolation, error) {
+	rows, err := s.db.QueryContext(ctx, `PRAGMA foreign_key_check`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []foreignKeyViolation
+	for rows.Next() {
+		var violation foreignKeyViolation
+		if err := rows.Scan(&violation.table, &violation.rowID, &violation.parent, &violation.fkID); err != nil {
+			return nil, err
+		}
+		out = append(out, violation)
+	}
+	return out, rows.Err()
+}
+
+func findForeignKeyViolations(ctx context.Context, s *Store) ([]doctorRow, error) {
+	violations, err := s.foreignKeyViolations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]doctorRow, 0, len(violations))
+	for _, violation := range violations {
+		out = append(out, doctorRow{
+			id:    violation.rowID.Int64,
+			label: fmt.Sprintf("%s row %d -> missing %s", violation.table, violation.rowID.Int64, violation.parent),
+			fk:    &violation,
+		})
+	}
+	return out, nil
+}
+
+func fixForeignKeyViolations(ctx context.Context, _ *Store, tx *sql.Tx, rows []doctorRow) (int, error) {
+	fixed := 0
+	for _, row := range rows {
+		violation := row.fk
+		if violation == nil || !violation.rowID.Valid {
+			continue // WITHOUT ROWID table; nothing to address the row by
+		}
+		var columns []string
+		var onDelete string
+		fkRows, err := tx.QueryContext(ctx, `
+			SELECT "from", on_delete FROM pragma_foreign_key_list(?) WHERE id = ? ORDER BY seq
+		`, violation.table, violation.fkID)
+		if err != nil {
+			return fixed, err
+		}
+		for fkRows.Next() {
+			var column string
+			if err := fkRows.Scan(&column, &onDelete); err != nil {
+				fkRows.Close()
+				return fixed, err
+			}
+			columns = append(columns, quoteIdent(column)+" = NULL")
+		}
+		fkRows.Close()
+		if err := fkRows.Err(); err != nil {
+			return fixed, err
+		}
+
+		var stmt string
+		if strings.EqualFold(onDelete, "SET NULL") && len(columns) > 0 {
+			stmt = `UPDATE ` + quoteIdent(violation.table) + ` SET ` + strings.Join(columns, ", ") + ` WHERE rowid = ?`
+		} else {
+			stmt = `DELETE FROM ` + quoteIdent(violation.table) + ` WHERE rowid = ?`
+		}
+		result, err := tx.ExecContext(ctx, stmt, violation.rowID.Int64)
+		if err != nil {
+			return fixed, fmt.Errorf("%s row %d: %w", violation.table, violation.rowID.Int64, err)
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			fixed += int(n)
+		}
+	}
+	return fixed, nil
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func findMatchesWithoutResult(ctx context.Context, s *Store) ([]doctorRow, error) {
+	cutoff := s.now().UTC().Add(-doctorGracePeriod).Format(time.RFC3339)
+	return s.queryDoctorRows(ctx, `
+		SELECT id, 'match ' || id || ' (' || arena_match_id || ', ' || COALESCE(sort_at, '?') || ')'
+		FROM matches
+		WHERE archived = 0
+		  AND COALESCE(result, 'unknown') = 'unknown'
+		  AND sort_at < ?
+		ORDER BY id
+	`, cutoff)
+}
+
+func fixMatchesWithoutResult(ctx context.Context, s *Store, tx *sql.Tx, rows []doctorRow) (int, error) {
+	now := s.nowUTC()
+	fixed := 0
+	for _, row := range rows {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE matches SET archived = 1, archived_at = ? WHERE id = ? AND archived = 0
+		`, now, row.id)
+		if err != nil {
+			return fixed, err
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			fixed += int(n)
+		}
+	}
+	return fixed, nil
+}
+
+func findDecksWithoutCards(ctx context.Context, s *Store) ([]doctorRow, error) {
+	return s.queryDoctorRows(ctx, `
+		SELECT d.id,
+			'deck ' || d.id || ' (' || COALESCE(NULLIF(d.name, ''), d.arena_deck_id) || ', ' ||
+			(SELECT COUNT(*) FROM match_decks md WHERE md.deck_id = d.id) || ' matches)'
+		FROM decks d
+		WHERE NOT EXISTS (SELECT 1 FROM deck_cards dc WHERE dc.deck_id = d.id)
+		ORDER BY d.id
+	`)
+}
+
+func fixDecksWithoutCards(ctx context.Context, _ *Store, tx *sql.Tx, rows []doctorRow) (int, error) {
+	fixed := 0
+	for _, row := range rows {
+		result, err := tx.ExecContext(ctx, `
+			DELETE FROM decks
+			WHERE id = ?
+			  AND NOT EXISTS (SELECT 1 FROM deck_cards WHERE deck_id = decks.id)
+			  AND NOT EXISTS (SELECT 1 FROM match_decks WHERE deck_id = decks.id)
+		`, row.id)
+		if err != nil {
+			return fixed, err
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			fixed += int(n)
+		}
+	}
+	return fixed, nil
+}
+
+func findDraftsWithoutPicks(ctx context.Context, s *Store) ([]doctorRow, error) {
+	cutoff := s.now().UTC().Add(-doctorGracePeriod).Format(time.RFC3339)
+	return s.queryDoctorRows(ctx, `
+		SELECT ds.id, 'draft ' || ds.id || ' (' || COALESCE(ds.event_name, '?') || ', ' || ds.created_at || ')'
+		FROM draft_sessions ds
+		WHERE NOT EXISTS (SELECT 1 FROM draft_picks dp WHERE dp.draft_session_id = ds.id)
+		  AND ds.created_at < ?
+		ORDER BY ds.id
+	`, cutoff)
+}
+
+func fixDraftsWithoutPicks(ctx context.Context, _ *Store, tx *sql.Tx, rows []doctorRow) (int, error) {
+	fixed := 0
+	for _, row := range rows {
+		result, err := tx.ExecContext(ctx, `
+			DELETE FROM draft_sessions
+			WHERE id = ?
+			  AND NOT EXISTS (SELECT 1 FROM draft_picks WHERE draft_session_id = draft_sessions.id)
+		`, row.id)
+		if err != nil {
+			return fixed, err
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			fixed += int(n)
+		}
+	}
+	return fixed, nil
+}
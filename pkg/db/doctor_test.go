@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDoctorFindsAndFixesInconsistentRows(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	store := NewStore(database)
+	store.SetClock(FixedClock(time.Date(2026, 4, 10, 12, 0, 0, 0, time.UTC)))
+
+	const ts = "2026-04-01T00:00:00Z"
+	for _, stmt := range []string{
+		`INSERT INTO decks (id, arena_deck_id, name, created_at, updated_at) VALUES
+			(1, 'deck-empty', 'Empty', '` + ts + `', '` + ts + `'),
+			(2, 'deck-used', 'Used', '` + ts + `', '` + ts + `'),
+			(3, 'deck-ok', 'Fine', '` + ts + `', '` + ts + `')`,
+		`INSERT INTO deck_cards (deck_id, section, card_id, quantity) VALUES (3, 'main', 100, 4)`,
+		`INSERT INTO matches (id, arena_match_id, started_at, result, created_at, updated_at) VALUES
+			(1, 'm-stale', '2026-04-01T10:00:00Z', NULL, '` + ts + `', '` + ts + `'),
+			(2, 'm-live', '2026-04-10T11:00:00Z', NULL, '` + ts + `', '` + ts + `'),
+			(3, 'm-won', '2026-04-02T10:00:00Z', 'win', '` + ts + `', '` + ts + `')`,
+		`INSERT INTO match_decks (match_id, deck_id, snapshot_reason, created_at) VALUES (1, 2, 'test', '` + ts + `')`,
+		`INSERT INTO draft_sessions (id, event_name, is_bot_draft, created_at, updated_at) VALUES
+			(1, 'QuickDraft_TMT', 1, '` + ts + `', '` + ts + `'),
+			(2, 'QuickDraft_TMT', 1, '2026-04-10T11:00:00Z', '2026-04-10T11:00:00Z')`,
+	} {
+		mustExec(t, database, stmt)
+	}
+
+	// Dangling references can only be written with enforcement off, which
+	// is per connection.
+	conn, err := database.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+	for _, stmt := range []string{
+		`PRAGMA foreign_keys = OFF`,
+		`INSERT INTO deck_cards (deck_id, section, card_id, quantity) VALUES (99, 'main', 100, 1)`,
+		`INSERT INTO match_decks (match_id, deck_id, deck_version_id, snapshot_reason, created_at) VALUES (3, 3, 999, 'test', '` + ts + `')`,
+		`PRAGMA foreign_keys = ON`,
+	} {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("exec %s: %v", stmt, err)
+		}
+	}
+	_ = conn.Close()
+
+	found := func(report DoctorReport) map[string][2]int {
+		out := map[string][2]int{}
+		for _, issue := range report.Issues {
+			out[issue.Check] = [2]int{issue.Found, issue.Fixed}
+		}
+		return out
+	}
+	assertFound := func(label string, got, want map[string][2]int) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: issues = %v, want %v", label, got, want)
+		}
+		for check, counts := range want {
+			if got[check] != counts {
+				t.Fatalf("%s: %s found/fixed = %v, want %v (all: %v)", label, check, got[check], counts, got)
+			}
+		}
+	}
+
+	report, err := store.Doctor(ctx, false)
+	if err != nil {
+		t.Fatalf("Doctor: %v", err)
+	}
+	assertFound("dry run", found(report), map[string][2]int{
+		"foreign_keys":           {2, 0},
+		"matches_without_result": {1, 0},
+		"decks_without_cards":    {2, 0},
+		"drafts_without_picks":   {1, 0},
+	})
+
+	report, err = store.Doctor(ctx, true)
+	if err != nil {
+		t.Fatalf("Doctor(fix): %v", err)
+	}
+	assertFound("fix", found(report), map[string][2]int{
+		"foreign_keys":           {2, 2},
+		"matches_without_result": {1, 1},
+		"decks_without_cards":    {2, 1},
+		"drafts_without_picks":   {1, 1},
+	})
+
+	// Only the deck a match still uses is left, for reprocess to refill.
+	report, err = store.Doctor(ctx, false)
+	if err != nil {
+		t.Fatalf("Doctor after fix: %v", err)
+	}
+	assertFound("after fix", found(report), map[string][2]int{
+		"decks_without_cards": {1, 0},
+	})
+
+	var archived, versionless int
+	if err := database.QueryRowContext(ctx, `SELECT archived FROM matches WHERE id = 1`).Scan(&archived); err != nil {
+		t.Fatalf("read archived: %v", err)
+	}
+	if archived != 1 {
+		t.Fatalf("stale match archived = %d, want 1", archived)
+	}
+	if err := database.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM match_decks WHERE match_id = 3 AND deck_version_id IS NULL
+	`).Scan(&versionless); err != nil {
+		t.Fatalf("read match_decks: %v", err)
+	}
+	if versionless != 1 {
+		t.Fatalf("match_decks with cleared version = %d, want 1 (SET NULL keeps the link)", versionless)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 // EconomyChange is one decoded entry of an InventoryInfo Changes array.
@@ -162,7 +162,7 @@ func (s *Store) DeriveEconomyTransactions(
 			change.WildcardDeltas.Common, change.WildcardDeltas.Uncommon,
 			change.WildcardDeltas.Rare, change.WildcardDeltas.Mythic,
 			change.CardsGranted, change.VaultProgressDelta,
-			string(boostersJSON), string(customTokensJSON), string(vouchersJSON), nowUTC())
+			string(boostersJSON), string(customTokensJSON), string(vouchersJSON), s.nowUTC())
 		if err != nil {
 			return inserted, fmt.Errorf("insert economy transaction: %w", err)
 		}
@@ -179,7 +179,7 @@ func (s *Store) DeriveEconomyTransactions(
 				UPDATE event_runs
 				SET pay_source_id = COALESCE(pay_source_id, ?), updated_at = ?
 				WHERE event_name = ?
-			`, change.SourceID, nowUTC(), eventName); err != nil {
+			`, change.SourceID, s.nowUTC(), eventName); err != nil {
 				return inserted, fmt.Errorf("record event pay source id: %w", err)
 			}
 		}
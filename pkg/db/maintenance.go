@@ -24,8 +24,8 @@ func (r MaintenanceResult) reclaimedAnything() bool {
 
 // RunMaintenance performs the periodic space and hygiene work in one pass:
 // compacts finished-match replay rows into archives, recompresses archives
-// written at the old zstd level (once), prunes raw events nothing reads,
-// backfills draft metadata, and — when anything was reclaimed — VACUUMs and
+// written at the old zstd level (once), prunes raw events nothing reads
+// (unless rows were stored at RawEventsFull), backfills draft metadata, and — when anything was reclaimed — VACUUMs and
 // truncates the WAL so the space returns to the filesystem.
 func (s *Store) RunMaintenance(ctx context.Context) (MaintenanceResult, error) {
 	result := MaintenanceResult{}
@@ -42,11 +42,19 @@ func (s *Store) RunMaintenance(ctx context.Context) (MaintenanceResult, error) {
 		return result, err
 	}
 
-	pruned, err := s.PruneRawEvents(ctx)
-	result.RawEventsPruned = pruned
+	// Rows stored at RawEventsFull are unread by design; only an explicit
+	// prune removes them.
+	keepFull, err := s.keepsFullRawEvents(ctx)
 	if err != nil {
 		return result, err
 	}
+	if !keepFull {
+		pruned, err := s.PruneRawEvents(ctx)
+		result.RawEventsPruned = pruned
+		if err != nil {
+			return result, err
+		}
+	}
 
 	if err := s.RepairDraftDataFromRawEvents(ctx); err != nil {
 		return result, err
@@ -59,7 +67,7 @@ func (s *Store) RunMaintenance(ctx context.Context) (MaintenanceResult, error) {
 	}
 
 	if result.reclaimedAnything() {
-		if _, err := s.db.ExecContext(ctx, `VACUUM`); err != nil {
+		if _, err := s.exec(ctx, `VACUUM`); err != nil {
 			return result, fmt.Errorf("vacuum after maintenance: %w", err)
 		}
 		// Hand freed WAL pages back to the filesystem too; busy is fine, the
@@ -121,21 +129,21 @@ func (s *Store) recompressReplayArchivesOnce(ctx context.Context) (int, error) {
 		if len(reencoded) >= len(compressed) {
 			continue
 		}
-		if _, err := s.db.ExecContext(ctx, `
+		if _, err := s.exec(ctx, `
 			UPDATE match_replay_archives SET payload_zstd = ?, updated_at = ? WHERE match_id = ?
-		`, reencoded, nowUTC(), matchID); err != nil {
+		`, reencoded, s.nowUTC(), matchID); err != nil {
 			return recompressed, fmt.Errorf("update replay archive %d: %w", matchID, err)
 		}
 		recompressed++
 	}
 
-	if _, err := s.db.ExecContext(ctx, `
+	if _, err := s.exec(ctx, `
 		INSERT INTO app_metadata (key, value, updated_at)
 		VALUES (?, ?, ?)
 		ON CONFLICT(key) DO UPDATE SET
 			value = excluded.value,
 			updated_at = excluded.updated_at
-	`, appMetadataReplayEncoderLevelKey, replayEncoderLevelBest, nowUTC()); err != nil {
+	`, appMetadataReplayEncoderLevelKey, replayEncoderLevelBest, s.nowUTC()); err != nil {
 		return recompressed, fmt.Errorf("record replay encoder level: %w", err)
 	}
 
@@ -0,0 +1,320 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func newFastCompressed(t *testing.T, raw []byte) []byte {
+	t.Helper()
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+	if err != nil {
+		t.Fatalf("init fast zstd encoder: %v", err)
+	}
+	defer enc.Close()
+	return enc.EncodeAll(raw, nil)
+}
+
+func TestInsertRawEventFiltersUnreadEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	store := NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	cases := []struct {
+		name    string
+		kind    string
+		method  string
+		payload string
+		want    bool
+	}{
+		{"draft pick business event", "outgoing", "LogBusinessEvents", `{"EventType":24,"DraftId":"d1"}`, true},
+		{"other business event", "outgoing", "LogBusinessEvents", `{"EventType":7}`, false},
+		{"player draft pick", "outgoing", "EventPlayerDraftMakePick", `{"DraftId":"d1","Pack":1,"Pick":1}`, true},
+		{"draft complete", "outgoing", "DraftCompleteDraft", `{"EventName":"E","IsBotDraft":false}`, true},
+		{"set deck v2", "outgoing", "EventSetDeckV2", `{"EventName":"PremierDraft_X"}`, true},
+		{"unread outgoing method", "outgoing", "DeckUpsertDeckV2", `{"Deck":{}}`, false},
+		{"method complete marker", "method_complete", "LogBusinessEvents", ``, false},
+		{"room state marker", "room_state", "matchGameRoomStateChangedEvent", ``, false},
+		{"rank method result", "method_result", "RankGetCombinedRankInfo", `{"ConstructedClass":"Gold"}`, false},
+	}
+
+	for _, tc := range cases {
+		stored, err := store.InsertRawEvent(ctx, tx, "Player.log", 1, 1, "", tc.kind, tc.method, "", []byte(tc.payload), "")
+		if err != nil {
+			t.Fatalf("%s: InsertRawEvent: %v", tc.name, err)
+		}
+		if stored != tc.want {
+			t.Errorf("%s: stored = %v, want %v", tc.name, stored, tc.want)
+		}
+	}
+}
+
+func TestInsertRawEventStoresOverlappingLogsOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	store := NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	payload := []byte(`{"DraftId":"d1","Pack":1,"Pick":1}`)
+	cases := []struct {
+		name      string
+		logPath   string
+		requestID string
+		payload   []byte
+		want      bool
+	}{
+		{"first copy", "Player-prev.log", "req-1", payload, true},
+		{"same request in the next log", "Player.log", "req-1", payload, false},
+		{"new request id", "Player.log", "req-2", payload, true},
+		{"same id, different payload", "Player.log", "req-1", []byte(`{"DraftId":"d1","Pack":1,"Pick":2}`), true},
+	}
+	for _, tc := range cases {
+		stored, err := store.InsertRawEvent(ctx, tx, tc.logPath, 1, 1, "", "outgoing", "EventPlayerDraftMakePick", tc.requestID, tc.payload, "")
+		if err != nil {
+			t.Fatalf("%s: InsertRawEvent: %v", tc.name, err)
+		}
+		if stored != tc.want {
+			t.Errorf("%s: stored = %v, want %v", tc.name, stored, tc.want)
+		}
+	}
+
+	var rows int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM events_raw`).Scan(&rows); err != nil {
+		t.Fatalf("count events_raw: %v", err)
+	}
+	if rows != 3 {
+		t.Fatalf("events_raw rows = %d, want 3", rows)
+	}
+}
+
+func TestInsertRawEventFollowsRawEventLevel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	store := NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	draftPick := []byte(`{"DraftId":"d1","Pack":1,"Pick":1}`)
+	cases := []struct {
+		name    string
+		level   RawEventLevel
+		kind    string
+		payload []byte
+		rawText string
+		want    bool
+	}{
+		{"none drops read-back requests", RawEventsNone, "outgoing", draftPick, "", false},
+		{"minimal keeps read-back requests", RawEventsMinimal, "outgoing", draftPick, "", true},
+		{"minimal drops room states", RawEventsMinimal, "room_state", nil, `{"a":1}`, false},
+		{"full keeps room states", RawEventsFull, "room_state", nil, `{"a":1}`, true},
+		{"full keeps each distinct line", RawEventsFull, "room_state", nil, `{"a":2}`, true},
+		{"full still dedupes a repeated line", RawEventsFull, "room_state", nil, `{"a":2}`, false},
+	}
+	for _, tc := range cases {
+		store.SetRawEventLevel(tc.level)
+		stored, err := store.InsertRawEvent(ctx, tx, "Player.log", 1, 1, "", tc.kind, "EventPlayerDraftMakePick", "", tc.payload, tc.rawText)
+		if err != nil {
+			t.Fatalf("%s: InsertRawEvent: %v", tc.name, err)
+		}
+		if stored != tc.want {
+			t.Errorf("%s: stored = %v, want %v", tc.name, stored, tc.want)
+		}
+	}
+
+	var texts int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM events_raw WHERE raw_text != ''`).Scan(&texts); err != nil {
+		t.Fatalf("count raw text rows: %v", err)
+	}
+	if texts != 2 {
+		t.Fatalf("rows with raw text = %d, want 2", texts)
+	}
+
+	for _, name := range []string{"", "minimal", "None", " full "} {
+		if _, err := ParseRawEventLevel(name); err != nil {
+			t.Errorf("ParseRawEventLevel(%q): %v", name, err)
+		}
+	}
+	if _, err := ParseRawEventLevel("everything"); err == nil {
+		t.Errorf("ParseRawEventLevel(everything) succeeded, want an error")
+	}
+}
+
+func TestPruneRawEventsKeepsOnlyRepairInputs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	// Insert legacy-style rows directly, bypassing the InsertRawEvent filter.
+	mustExec(t, database, `
+		INSERT INTO events_raw (log_path, line_no, byte_offset, kind, method_name, request_id, payload_json, raw_text, created_at) VALUES
+		('p', 1, 1, 'outgoing', 'LogBusinessEvents', '', '{"EventType":24,"DraftId":"d1"}', '', '2026-01-01T00:00:00Z'),
+		('p', 2, 2, 'outgoing', 'LogBusinessEvents', '', '{"EventType":7}', '', '2026-01-01T00:00:00Z'),
+		('p', 3, 3, 'outgoing', 'EventPlayerDraftMakePick', '', '{"DraftId":"d1"}', '', '2026-01-01T00:00:00Z'),
+		('p', 4, 4, 'outgoing', 'DeckUpsertDeckV2', '', '{"Deck":{}}', '', '2026-01-01T00:00:00Z'),
+		('p', 5, 5, 'method_complete', 'QuestGetQuests', 'r5', '', '', '2026-01-01T00:00:00Z'),
+		('p', 6, 6, 'room_state', 'matchGameRoomStateChangedEvent', '', '', '', '2026-01-01T00:00:00Z'),
+		('p', 7, 7, 'method_result', 'RankGetCombinedRankInfo', 'r7', '{"ConstructedClass":"Gold"}', '', '2026-01-01T00:00:00Z')
+	`)
+
+	store := NewStore(database)
+	pruned, err := store.PruneRawEvents(ctx)
+	if err != nil {
+		t.Fatalf("PruneRawEvents: %v", err)
+	}
+	if pruned != 5 {
+		t.Fatalf("pruned = %d, want 5", pruned)
+	}
+
+	var remaining int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM events_raw`).Scan(&remaining); err != nil {
+		t.Fatalf("count events_raw: %v", err)
+	}
+	if remaining != 2 {
+		t.Fatalf("remaining rows = %d, want 2", remaining)
+	}
+}
+
+func TestPruneAppliesRetentionAndCompressesOldPayloads(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	mustExec(t, database, `
+		INSERT INTO events_raw (log_path, line_no, byte_offset, kind, method_name, request_id, payload_json, raw_text, created_at) VALUES
+		('p', 1, 1, 'outgoing', 'EventPlayerDraftMakePick', '', '{"DraftId":"d0"}', '', '2026-01-01T00:00:00Z'),
+		('p', 2, 2, 'outgoing', 'EventPlayerDraftMakePick', '', '{"DraftId":"d1"}', '', '2026-09-01T00:00:00Z'),
+		('p', 3, 3, 'outgoing', 'EventPlayerDraftMakePick', '', '{"DraftId":"d2"}', '', '2026-09-20T00:00:00Z'),
+		('p', 4, 4, 'outgoing', 'EventPlayerDraftMakePick', '', '{"DraftId":"d3"}', '', '2026-10-15T00:00:00Z'),
+		('p', 5, 5, 'outgoing', 'DeckUpsertDeckV2', '', '{"Deck":{}}', '', '2026-10-15T00:00:00Z')
+	`)
+
+	store := NewStore(database)
+	store.SetClock(FixedClock(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)))
+	result, err := store.Prune(ctx, RawEventPolicy{
+		MaxAge:        90 * 24 * time.Hour,
+		MaxRows:       2,
+		CompressAfter: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if result.Unread != 1 || result.Expired != 1 || result.OverLimit != 1 || result.Compressed != 1 {
+		t.Fatalf("result = %+v, want one row each unread, expired, over limit, and compressed", result)
+	}
+
+	var payloadJSON sql.NullString
+	var payloadZstd []byte
+	if err := database.QueryRow(`SELECT payload_json, payload_zstd FROM events_raw WHERE line_no = 3`).Scan(&payloadJSON, &payloadZstd); err != nil {
+		t.Fatalf("load compressed row: %v", err)
+	}
+	if payloadJSON.Valid || len(payloadZstd) == 0 {
+		t.Fatalf("row 3 payload_json = %v, payload_zstd = %d bytes; want compressed", payloadJSON, len(payloadZstd))
+	}
+	raw, err := rawEventPayload(payloadJSON, payloadZstd)
+	if err != nil || string(raw) != `{"DraftId":"d2"}` {
+		t.Fatalf("decoded payload = %q, %v", raw, err)
+	}
+	var plain string
+	if err := database.QueryRow(`SELECT payload_json FROM events_raw WHERE line_no = 4`).Scan(&plain); err != nil || plain != `{"DraftId":"d3"}` {
+		t.Fatalf("recent payload = %q, %v; want left uncompressed", plain, err)
+	}
+}
+
+func TestRunMaintenanceRecompressesArchivesOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	mustExec(t, database, `INSERT INTO matches (arena_match_id, created_at, updated_at) VALUES ('m1', '2026-01-01T00:00:00Z', '2026-01-01T00:00:00Z')`)
+
+	// Store a payload compressed at the fastest level so best level can win.
+	raw := make([]byte, 0, 64*1024)
+	for i := 0; i < 2048; i++ {
+		raw = append(raw, []byte(`{"instanceId":123,"zoneType":"battlefield"}`)...)
+	}
+	fast := newFastCompressed(t, raw)
+	if _, err := database.Exec(`
+		INSERT INTO match_replay_archives (match_id, schema_version, frame_count, object_count, payload_zstd, created_at, updated_at)
+		VALUES (1, 1, 1, 1, ?, '2026-01-01T00:00:00Z', '2026-01-01T00:00:00Z')
+	`, fast); err != nil {
+		t.Fatalf("insert archive: %v", err)
+	}
+
+	store := NewStore(database)
+	result, err := store.RunMaintenance(ctx)
+	if err != nil {
+		t.Fatalf("RunMaintenance: %v", err)
+	}
+	if result.ArchivesRecompressed != 1 {
+		t.Fatalf("ArchivesRecompressed = %d, want 1", result.ArchivesRecompressed)
+	}
+
+	var stored []byte
+	if err := database.QueryRow(`SELECT payload_zstd FROM match_replay_archives WHERE match_id = 1`).Scan(&stored); err != nil {
+		t.Fatalf("load recompressed archive: %v", err)
+	}
+	if len(stored) >= len(fast) {
+		t.Fatalf("recompressed archive is %d bytes, want smaller than %d", len(stored), len(fast))
+	}
+	roundTripped, err := getZstdDecoder().DecodeAll(stored, nil)
+	if err != nil {
+		t.Fatalf("decompress recompressed archive: %v", err)
+	}
+	if string(roundTripped) != string(raw) {
+		t.Fatal("recompressed archive does not round-trip to the original payload")
+	}
+
+	// A second pass must skip the recompress entirely.
+	result, err = store.RunMaintenance(ctx)
+	if err != nil {
+		t.Fatalf("RunMaintenance second pass: %v", err)
+	}
+	if result.ArchivesRecompressed != 0 {
+		t.Fatalf("second pass ArchivesRecompressed = %d, want 0", result.ArchivesRecompressed)
+	}
+}
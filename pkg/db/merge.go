@@ -0,0 +1,448 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MergeResult counts what MergeFrom added to the database.
+type MergeResult struct {
+	Matches       int64
+	Decks         int64
+	DecksUpdated  int64
+	DeckVersions  int64
+	DraftSessions int64
+	DraftPicks    int64
+	RawEvents     int64
+}
+
+// mergeMapTables hold, per merged table, each source row id and the id it
+// has here; is_new marks rows that were copied rather than matched.
+var mergeMapTables = []string{"merge_decks", "merge_deck_versions", "merge_matches", "merge_replay_frames", "merge_rank_snapshots", "merge_draft_sessions", "merge_newer_decks"}
+
+// MergeFrom imports matches, decks, drafts, and raw events from another
+// ponder database, such as one from a second machine. Rows are matched on
+// arena_match_id, arena_deck_id, and draft_id: a match or draft session
+// already here is kept as is (a session gains any picks it lacks), a deck
+// takes the other copy's details and cards when that copy was updated more
+// recently, and everything else is copied under new ids. Economy snapshots
+// and event runs are per machine and are not merged. The other database is
+// read from a staged copy and never modified.
+func (s *Store) MergeFrom(ctx context.Context, fromPath string) (MergeResult, error) {
+	result := MergeResult{}
+	fromAbs, err := filepath.Abs(fromPath)
+	if err != nil {
+		return result, fmt.Errorf("resolve source path: %w", err)
+	}
+	if _, err := os.Stat(fromAbs); err != nil {
+		return result, fmt.Errorf("source database: %w", err)
+	}
+	if mainPath, err := s.mainDatabasePath(ctx); err != nil {
+		return result, err
+	} else if mainPath == fromAbs {
+		return result, fmt.Errorf("cannot merge a database into itself")
+	}
+
+	stageDir, err := os.MkdirTemp("", "ponder-merge-")
+	if err != nil {
+		return result, fmt.Errorf("create merge staging dir: %w", err)
+	}
+	defer os.RemoveAll(stageDir)
+	staged := filepath.Join(stageDir, "source.db")
+	if err := stageMergeSource(ctx, fromAbs, staged); err != nil {
+		return result, err
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return result, fmt.Errorf("acquire merge connection: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS merge_src`, staged); err != nil {
+		return result, fmt.Errorf("attach merge source: %w", err)
+	}
+	defer func() {
+		for _, table := range mergeMapTables {
+			_, _ = conn.ExecContext(context.Background(), `DROP TABLE IF EXISTS temp.`+table)
+		}
+		_, _ = conn.ExecContext(context.Background(), `DETACH DATABASE merge_src`)
+	}()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("begin merge: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, table := range mergeMapTables {
+		if _, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS temp.`+table); err != nil {
+			return result, fmt.Errorf("reset %s: %w", table, err)
+		}
+		if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE `+table+` (src_id INTEGER PRIMARY KEY, dst_id INTEGER NOT NULL, is_new INTEGER NOT NULL)`); err != nil {
+			return result, fmt.Errorf("create %s: %w", table, err)
+		}
+	}
+
+	if err := mergeDecks(ctx, tx, &result); err != nil {
+		return result, err
+	}
+	if err := mergeMatches(ctx, tx, &result); err != nil {
+		return result, err
+	}
+	if err := mergeDrafts(ctx, tx, &result); err != nil {
+		return result, err
+	}
+	if result.RawEvents, err = mergeInsert(ctx, tx, "events_raw", map[string]string{"id": ""}, `
+		WHERE NOT EXISTS (
+			SELECT 1 FROM main.events_raw e
+			WHERE e.kind = s.kind
+			  AND COALESCE(e.method_name, '') = COALESCE(s.method_name, '')
+			  AND COALESCE(e.request_id, '') = COALESCE(s.request_id, '')
+			  AND e.payload_hash IS s.payload_hash
+		)
+		ORDER BY s.id
+	`, ""); err != nil {
+		return result, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("commit merge: %w", err)
+	}
+
+	// Merged matches arrive without derived analytics, and merged raw events
+	// may complete draft sessions that were missing details.
+	if _, err := s.RefreshPendingMatchAnalytics(ctx); err != nil {
+		return result, err
+	}
+	if err := s.RepairDraftDataFromRawEvents(ctx); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func mergeDecks(ctx context.Context, tx *sql.Tx, result *MergeResult) error {
+	if err := mergeMapRows(ctx, tx, "merge_decks", "decks", `
+		SELECT d.id FROM main.decks d WHERE d.arena_deck_id = s.arena_deck_id
+	`, ""); err != nil {
+		return err
+	}
+	var err error
+	if result.Decks, err = mergeInsert(ctx, tx, "decks", map[string]string{"id": "m.dst_id"}, `
+		JOIN temp.merge_decks m ON m.src_id = s.id
+		WHERE m.is_new
+	`, ""); err != nil {
+		return err
+	}
+
+	// A deck already here takes the other copy's details and current cards
+	// when that copy saw the deck more recently.
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO temp.merge_newer_decks (src_id, dst_id, is_new)
+		SELECT m.src_id, m.dst_id, 0
+		FROM temp.merge_decks m
+		JOIN merge_src.decks s ON s.id = m.src_id
+		JOIN main.decks d ON d.id = m.dst_id
+		WHERE NOT m.is_new AND s.updated_at > d.updated_at
+	`); err != nil {
+		return fmt.Errorf("find newer merged decks: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `
+		UPDATE main.decks
+		SET (event_name, name, format, source, last_updated, updated_at) = (
+			SELECT
+				COALESCE(s.event_name, decks.event_name),
+				COALESCE(s.name, decks.name),
+				COALESCE(s.format, decks.format),
+				COALESCE(s.source, decks.source),
+				COALESCE(s.last_updated, decks.last_updated),
+				s.updated_at
+			FROM temp.merge_newer_decks n
+			JOIN merge_src.decks s ON s.id = n.src_id
+			WHERE n.dst_id = decks.id
+		)
+		WHERE id IN (SELECT dst_id FROM temp.merge_newer_decks)
+	`)
+	if err != nil {
+		return fmt.Errorf("update newer merged decks: %w", err)
+	}
+	result.DecksUpdated, _ = res.RowsAffected()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM main.deck_cards WHERE deck_id IN (SELECT dst_id FROM temp.merge_newer_decks)`); err != nil {
+		return fmt.Errorf("clear newer merged deck cards: %w", err)
+	}
+	if _, err := mergeInsert(ctx, tx, "deck_cards", map[string]string{"id": "", "deck_id": "m.dst_id"}, `
+		JOIN (
+			SELECT src_id, dst_id FROM temp.merge_decks WHERE is_new
+			UNION ALL
+			SELECT src_id, dst_id FROM temp.merge_newer_decks
+		) m ON m.src_id = s.deck_id
+	`, ""); err != nil {
+		return err
+	}
+
+	// Versions are matched on their card hash; unseen ones are numbered after
+	// the deck's existing versions.
+	if err := mergeMapRows(ctx, tx, "merge_deck_versions", "deck_versions", `
+		SELECT v.id FROM main.deck_versions v
+		JOIN temp.merge_decks m ON m.src_id = s.deck_id
+		WHERE v.deck_id = m.dst_id AND v.cards_hash = s.cards_hash
+	`, `WHERE s.deck_id IN (SELECT src_id FROM temp.merge_decks)`); err != nil {
+		return err
+	}
+	if result.DeckVersions, err = mergeInsert(ctx, tx, "deck_versions", map[string]string{
+		"id":      "mv.dst_id",
+		"deck_id": "m.dst_id",
+		"version_number": `COALESCE((SELECT MAX(x.version_number) FROM main.deck_versions x WHERE x.deck_id = m.dst_id), 0)
+			+ ROW_NUMBER() OVER (PARTITION BY m.dst_id ORDER BY s.version_number)`,
+	}, `
+		JOIN temp.merge_deck_versions mv ON mv.src_id = s.id
+		JOIN temp.merge_decks m ON m.src_id = s.deck_id
+		WHERE mv.is_new
+	`, ""); err != nil {
+		return err
+	}
+	if _, err := mergeInsert(ctx, tx, "deck_version_cards", map[string]string{"id": "", "deck_version_id": "mv.dst_id"}, `
+		JOIN temp.merge_deck_versions mv ON mv.src_id = s.deck_version_id
+		WHERE mv.is_new
+	`, ""); err != nil {
+		return err
+	}
+	if _, err := mergeInsert(ctx, tx, "deck_ai_primers", map[string]string{"deck_id": "m.dst_id"}, `
+		JOIN temp.merge_decks m ON m.src_id = s.deck_id
+		WHERE m.is_new
+	`, ""); err != nil {
+		return err
+	}
+	return nil
+}
+
+func mergeMatches(ctx context.Context, tx *sql.Tx, result *MergeResult) error {
+	if err := mergeMapRows(ctx, tx, "merge_matches", "matches", `
+		SELECT d.id FROM main.matches d WHERE d.arena_match_id = s.arena_match_id
+	`, ""); err != nil {
+		return err
+	}
+	var err error
+	if result.Matches, err = mergeInsert(ctx, tx, "matches", map[string]string{"id": "m.dst_id"}, `
+		JOIN temp.merge_matches m ON m.src_id = s.id
+		WHERE m.is_new
+	`, ""); err != nil {
+		return err
+	}
+
+	newMatchRows := `
+		JOIN temp.merge_matches m ON m.src_id = s.match_id
+		WHERE m.is_new
+	`
+	for _, table := range []string{"match_opponent_archetype_overrides", "match_opponent_card_instances", "match_card_plays", "match_replay_archives"} {
+		if _, err := mergeInsert(ctx, tx, table, map[string]string{"id": "", "match_id": "m.dst_id"}, newMatchRows, ""); err != nil {
+			return err
+		}
+	}
+	if _, err := mergeInsert(ctx, tx, "match_decks", map[string]string{
+		"id":              "",
+		"match_id":        "m.dst_id",
+		"deck_id":         "md.dst_id",
+		"deck_version_id": "(SELECT mv.dst_id FROM temp.merge_deck_versions mv WHERE mv.src_id = s.deck_version_id)",
+	}, `
+		JOIN temp.merge_matches m ON m.src_id = s.match_id
+		JOIN temp.merge_decks md ON md.src_id = s.deck_id
+		WHERE m.is_new
+	`, ""); err != nil {
+		return err
+	}
+
+	newMatchFilter := `WHERE s.match_id IN (SELECT src_id FROM temp.merge_matches WHERE is_new)`
+	if err := mergeMapRows(ctx, tx, "merge_replay_frames", "match_replay_frames", `SELECT NULL`, newMatchFilter); err != nil {
+		return err
+	}
+	if _, err := mergeInsert(ctx, tx, "match_replay_frames", map[string]string{"id": "mf.dst_id", "match_id": "m.dst_id"}, `
+		JOIN temp.merge_replay_frames mf ON mf.src_id = s.id
+		JOIN temp.merge_matches m ON m.src_id = s.match_id
+	`, ""); err != nil {
+		return err
+	}
+	if _, err := mergeInsert(ctx, tx, "match_replay_frame_objects", map[string]string{"id": "", "frame_id": "mf.dst_id"}, `
+		JOIN temp.merge_replay_frames mf ON mf.src_id = s.frame_id
+	`, ""); err != nil {
+		return err
+	}
+
+	if err := mergeMapRows(ctx, tx, "merge_rank_snapshots", "match_rank_snapshots", `SELECT NULL`, newMatchFilter); err != nil {
+		return err
+	}
+	if _, err := mergeInsert(ctx, tx, "match_rank_snapshots", map[string]string{
+		"id":               "mr.dst_id",
+		"match_id":         "m.dst_id",
+		"prev_snapshot_id": "(SELECT p.dst_id FROM temp.merge_rank_snapshots p WHERE p.src_id = s.prev_snapshot_id)",
+	}, `
+		JOIN temp.merge_rank_snapshots mr ON mr.src_id = s.id
+		JOIN temp.merge_matches m ON m.src_id = s.match_id
+	`, ""); err != nil {
+		return err
+	}
+	return nil
+}
+
+func mergeDrafts(ctx context.Context, tx *sql.Tx, result *MergeResult) error {
+	// Bot drafts have no draft_id; they match on event and start time.
+	if err := mergeMapRows(ctx, tx, "merge_draft_sessions", "draft_sessions", `
+		SELECT d.id FROM main.draft_sessions d
+		WHERE d.is_bot_draft = s.is_bot_draft
+		  AND (
+			d.draft_id = s.draft_id
+			OR (s.draft_id IS NULL AND d.draft_id IS NULL AND d.event_name IS s.event_name AND d.started_at IS s.started_at)
+		  )
+	`, ""); err != nil {
+		return err
+	}
+	var err error
+	if result.DraftSessions, err = mergeInsert(ctx, tx, "draft_sessions", map[string]string{"id": "m.dst_id"}, `
+		JOIN temp.merge_draft_sessions m ON m.src_id = s.id
+		WHERE m.is_new
+	`, ""); err != nil {
+		return err
+	}
+	if result.DraftPicks, err = mergeInsert(ctx, tx, "draft_picks", map[string]string{"id": "", "draft_session_id": "m.dst_id"}, `
+		JOIN temp.merge_draft_sessions m ON m.src_id = s.draft_session_id
+		WHERE true
+	`, "ON CONFLICT(draft_session_id, pack_number, pick_number) DO NOTHING"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// mergeMapRows fills mapTable with every merge_src.<table> row passing
+// filter: existing is a subquery over the source row s returning the id of
+// the same row here, or no row, in which case the row gets a new id past
+// every id in use.
+func mergeMapRows(ctx context.Context, tx *sql.Tx, mapTable, table, existing, filter string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO temp.`+mapTable+` (src_id, dst_id, is_new)
+		SELECT src_id, COALESCE(existing_id, src_id + (SELECT COALESCE(MAX(id), 0) FROM main.`+table+`)), existing_id IS NULL
+		FROM (
+			SELECT s.id AS src_id, (`+existing+` LIMIT 1) AS existing_id
+			FROM merge_src.`+table+` s
+			`+filter+`
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("map merged %s: %w", table, err)
+	}
+	return nil
+}
+
+// mergeInsert copies merge_src.<table> rows (aliased s) selected by from
+// into main.<table>, column for column. overrides replaces a column's value
+// with an SQL expression, or leaves the column to its default when the
+// expression is empty. Returns rows inserted.
+func mergeInsert(ctx context.Context, tx *sql.Tx, table string, overrides map[string]string, from, conflict string) (int64, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM pragma_table_info(?, 'main') ORDER BY cid`, table)
+	if err != nil {
+		return 0, fmt.Errorf("list %s columns: %w", table, err)
+	}
+	var columns, values []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan %s column: %w", table, err)
+		}
+		value, ok := overrides[name]
+		if !ok {
+			value = "s." + name
+		}
+		if value == "" {
+			continue
+		}
+		columns = append(columns, name)
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("iterate %s columns: %w", table, err)
+	}
+	rows.Close()
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("table %s not found", table)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO main.`+table+` (`+strings.Join(columns, ", ")+`)
+		SELECT `+strings.Join(values, ", ")+`
+		FROM merge_src.`+table+` s
+		`+from+`
+		`+conflict)
+	if err != nil {
+		return 0, fmt.Errorf("merge %s: %w", table, err)
+	}
+	inserted, _ := res.RowsAffected()
+	return inserted, nil
+}
+
+// stageMergeSource copies the database at fromPath to staged and brings the
+// copy to this build's schema, so the merge reads matching tables without
+// touching the original.
+func stageMergeSource(ctx context.Context, fromPath, staged string) error {
+	source, err := Open(fromPath)
+	if err != nil {
+		return err
+	}
+	store := NewStore(source)
+	if moved, err := store.MovedTo(ctx); err == nil && moved != "" {
+		source.Close()
+		return fmt.Errorf("%s was migrated to %s; merge that file instead", fromPath, moved)
+	}
+	err = store.BackupTo(ctx, staged)
+	source.Close()
+	if err != nil {
+		return err
+	}
+
+	copied, err := Open(staged)
+	if err != nil {
+		return err
+	}
+	defer copied.Close()
+	if err := NewStore(copied).IntegrityCheck(ctx); err != nil {
+		return fmt.Errorf("merge source %w", err)
+	}
+	var hasMatches int64
+	if err := copied.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('matches', 'decks')`).Scan(&hasMatches); err != nil {
+		return fmt.Errorf("inspect merge source: %w", err)
+	}
+	if hasMatches != 2 {
+		return fmt.Errorf("%s is not a ponder database", fromPath)
+	}
+	if err := Init(ctx, copied); err != nil {
+		return fmt.Errorf("upgrade merge source: %w", err)
+	}
+	return nil
+}
+
+// mainDatabasePath is the absolute path of the store's database file.
+func (s *Store) mainDatabasePath(ctx context.Context) (string, error) {
+	rows, err := s.db.QueryContext(ctx, `PRAGMA database_list`)
+	if err != nil {
+		return "", fmt.Errorf("list databases: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var seq int64
+		var name, file string
+		if err := rows.Scan(&seq, &name, &file); err != nil {
+			return "", fmt.Errorf("scan database: %w", err)
+		}
+		if name == "main" {
+			return file, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("iterate databases: %w", err)
+	}
+	return "", errors.New("main database not found")
+}
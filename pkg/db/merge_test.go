@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openMergeTestStore(t *testing.T, path string, now time.Time) (*sql.DB, *Store) {
+	t.Helper()
+	database, err := Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	if err := Init(context.Background(), database); err != nil {
+		t.Fatalf("init %s: %v", path, err)
+	}
+	store := NewStore(database)
+	store.SetClock(FixedClock(now))
+	return database, store
+}
+
+func seedMergeTestStore(t *testing.T, store *Store, matchID, deckID string, deckCard int64, picks []int64, requestIDs []string) {
+	t.Helper()
+	ctx := context.Background()
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("begin tx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, arenaDeckID := range []string{"deck-shared", deckID} {
+		if _, err := store.UpsertDeck(ctx, tx, arenaDeckID, "Ladder", arenaDeckID, "Standard", "event_set_deck", "", []DeckCard{{Section: "main", CardID: deckCard, Quantity: 4}}); err != nil {
+			t.Fatalf("UpsertDeck: %v", err)
+		}
+	}
+	for _, arenaMatchID := range []string{"match-shared", matchID} {
+		if _, err := store.UpsertMatchStart(ctx, tx, arenaMatchID, "Ladder", 1, "2026-07-12T18:00:00Z"); err != nil {
+			t.Fatalf("UpsertMatchStart: %v", err)
+		}
+	}
+	if _, err := store.LinkMatchToDeckByArenaDeckID(ctx, tx, matchID, deckID, "event_deck"); err != nil {
+		t.Fatalf("LinkMatchToDeckByArenaDeckID: %v", err)
+	}
+	draftID := "draft-1"
+	sessionID, err := store.EnsureDraftSession(ctx, tx, "PremierDraft_TST", &draftID, false, "2026-07-12T17:00:00Z")
+	if err != nil {
+		t.Fatalf("EnsureDraftSession: %v", err)
+	}
+	for index, cardID := range picks {
+		if err := store.InsertDraftPick(ctx, tx, sessionID, 1, int64(index+1), []int64{cardID}, nil, ""); err != nil {
+			t.Fatalf("InsertDraftPick: %v", err)
+		}
+	}
+	for _, requestID := range requestIDs {
+		if _, err := store.InsertRawEvent(ctx, tx, "Player.log", 1, 1, "", "outgoing", "EventPlayerDraftMakePick", requestID, []byte(`{"DraftId":"draft-1"}`), ""); err != nil {
+			t.Fatalf("InsertRawEvent: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+}
+
+func TestMergeFromImportsOtherDatabaseWithoutDuplicates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	laptopDB, laptop := openMergeTestStore(t, filepath.Join(dir, "laptop.db"), time.Date(2026, 7, 12, 20, 0, 0, 0, time.UTC))
+	desktopPath := filepath.Join(dir, "desktop.db")
+	_, desktop := openMergeTestStore(t, desktopPath, time.Date(2026, 7, 13, 20, 0, 0, 0, time.UTC))
+
+	seedMergeTestStore(t, laptop, "match-laptop", "deck-laptop", 11, []int64{101}, []string{"req-1"})
+	seedMergeTestStore(t, desktop, "match-desktop", "deck-desktop", 22, []int64{101, 102}, []string{"req-1", "req-2"})
+
+	result, err := laptop.MergeFrom(ctx, desktopPath)
+	if err != nil {
+		t.Fatalf("MergeFrom: %v", err)
+	}
+	want := MergeResult{Matches: 1, Decks: 1, DecksUpdated: 1, DeckVersions: 2, DraftSessions: 0, DraftPicks: 1, RawEvents: 1}
+	if result != want {
+		t.Fatalf("result = %+v, want %+v", result, want)
+	}
+
+	count := func(query string) int64 {
+		t.Helper()
+		var n int64
+		if err := laptopDB.QueryRowContext(ctx, query).Scan(&n); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return n
+	}
+	if n := count(`SELECT COUNT(*) FROM matches`); n != 3 {
+		t.Fatalf("matches = %d, want 3", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM draft_picks`); n != 2 {
+		t.Fatalf("draft picks = %d, want 2", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM events_raw`); n != 2 {
+		t.Fatalf("raw events = %d, want 2", n)
+	}
+	// The desktop saw the shared deck later, so its card list wins.
+	if n := count(`SELECT dc.card_id FROM deck_cards dc JOIN decks d ON d.id = dc.deck_id WHERE d.arena_deck_id = 'deck-shared'`); n != 22 {
+		t.Fatalf("deck-shared card = %d, want 22", n)
+	}
+	if n := count(`
+		SELECT COUNT(*)
+		FROM match_decks md
+		JOIN matches m ON m.id = md.match_id
+		JOIN decks d ON d.id = md.deck_id
+		JOIN deck_versions v ON v.id = md.deck_version_id AND v.deck_id = d.id
+		WHERE m.arena_match_id = 'match-desktop' AND d.arena_deck_id = 'deck-desktop'
+	`); n != 1 {
+		t.Fatalf("match-desktop deck links = %d, want 1 with its deck version", n)
+	}
+
+	again, err := laptop.MergeFrom(ctx, desktopPath)
+	if err != nil {
+		t.Fatalf("second MergeFrom: %v", err)
+	}
+	if again != (MergeResult{}) {
+		t.Fatalf("second merge = %+v, want nothing new", again)
+	}
+	if _, err := laptop.MergeFrom(ctx, filepath.Join(dir, "laptop.db")); err == nil {
+		t.Fatal("merging a database into itself succeeded, want error")
+	}
+}
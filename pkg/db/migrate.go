@@ -0,0 +1,277 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// movedToMetadataKey is the tombstone MigrateDatabase leaves in the source
+// database; its value is the absolute path of the copy.
+const movedToMetadataKey = "moved_to"
+
+// TableChecksum summarizes one table's contents. Checksum is independent of
+// row order, since VACUUM INTO may renumber rows of tables without an
+// INTEGER PRIMARY KEY.
+type TableChecksum struct {
+	Table    string
+	Rows     int64
+	Checksum string
+}
+
+// MigrateDatabase copies the database at fromPath to toPath and verifies
+// every table's row count and checksum against the source. The source's
+// write lock is held from snapshot to verification, so a running tail or
+// serve blocks instead of committing rows the copy would miss. On success a
+// tombstone pointing at toPath is written into the source; on failure the
+// partial copy is removed and the source is left untouched.
+func MigrateDatabase(ctx context.Context, fromPath, toPath string) ([]TableChecksum, error) {
+	fromAbs, err := filepath.Abs(fromPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolve source path: %w", err)
+	}
+	toAbs, err := filepath.Abs(toPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolve destination path: %w", err)
+	}
+	if fromAbs == toAbs {
+		return nil, fmt.Errorf("source and destination are the same file")
+	}
+	if _, err := os.Stat(fromAbs); err != nil {
+		return nil, fmt.Errorf("source database: %w", err)
+	}
+	if _, err := os.Stat(toAbs); err == nil {
+		return nil, fmt.Errorf("destination %s already exists", toAbs)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("check destination: %w", err)
+	}
+
+	source, err := Open(fromAbs)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	store := NewStore(source)
+	if moved, err := store.MovedTo(ctx); err != nil {
+		return nil, err
+	} else if moved != "" {
+		return nil, fmt.Errorf("%s was already migrated to %s", fromAbs, moved)
+	}
+	if err := store.IntegrityCheck(ctx); err != nil {
+		return nil, err
+	}
+
+	// _txlock=immediate makes this BEGIN IMMEDIATE: other writers wait on the
+	// busy handler while readers, including VACUUM INTO, carry on.
+	lock, err := source.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("lock source database: %w", err)
+	}
+	defer func() {
+		_ = lock.Rollback()
+	}()
+
+	if err := store.BackupTo(ctx, toAbs); err != nil {
+		return nil, err
+	}
+	sums, err := verifyMigratedCopy(ctx, lock, toAbs)
+	if err != nil {
+		removeDatabaseFiles(toAbs)
+		return nil, err
+	}
+
+	if _, err := lock.ExecContext(ctx, `
+		INSERT INTO app_metadata (key, value, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, movedToMetadataKey, toAbs, nowUTC()); err != nil {
+		removeDatabaseFiles(toAbs)
+		return nil, fmt.Errorf("write tombstone: %w", err)
+	}
+	if err := lock.Commit(); err != nil {
+		removeDatabaseFiles(toAbs)
+		return nil, fmt.Errorf("commit tombstone: %w", err)
+	}
+	return sums, nil
+}
+
+// MovedTo returns where this database was migrated, or "" when it is still
+// the live copy.
+func (s *Store) MovedTo(ctx context.Context) (string, error) {
+	var path string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM app_metadata WHERE key = ?`, movedToMetadataKey).Scan(&path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read tombstone: %w", err)
+	}
+	return path, nil
+}
+
+func verifyMigratedCopy(ctx context.Context, source *sql.Tx, copyPath string) ([]TableChecksum, error) {
+	want, err := tableChecksums(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("checksum source: %w", err)
+	}
+
+	copied, err := Open(copyPath)
+	if err != nil {
+		return nil, err
+	}
+	defer copied.Close()
+	if err := NewStore(copied).IntegrityCheck(ctx); err != nil {
+		return nil, fmt.Errorf("copy %w", err)
+	}
+	got, err := tableChecksums(ctx, copied)
+	if err != nil {
+		return nil, fmt.Errorf("checksum copy: %w", err)
+	}
+
+	if len(got) != len(want) {
+		return nil, fmt.Errorf("copy has %d tables, source has %d", len(got), len(want))
+	}
+	for index := range want {
+		if got[index] != want[index] {
+			return nil, fmt.Errorf("table %s differs: source %d rows (%s), copy %d rows (%s)",
+				want[index].Table, want[index].Rows, want[index].Checksum, got[index].Rows, got[index].Checksum)
+		}
+	}
+	return want, nil
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// tableChecksums hashes every user table, sorted by name.
+func tableChecksums(ctx context.Context, conn queryer) ([]TableChecksum, error) {
+	rows, err := conn.QueryContext(ctx, `
+		SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tables: %w", err)
+	}
+
+	out := make([]TableChecksum, 0, len(tables))
+	for _, table := range tables {
+		sum, err := tableChecksum(ctx, conn, table)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sum)
+	}
+	return out, nil
+}
+
+// tableChecksum adds up per-row SHA-256 digests as four 64-bit lanes, which
+// makes the result independent of row order.
+func tableChecksum(ctx context.Context, conn queryer, table string) (TableChecksum, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT * FROM "`+strings.ReplaceAll(table, `"`, `""`)+`"`)
+	if err != nil {
+		return TableChecksum{}, fmt.Errorf("read %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return TableChecksum{}, fmt.Errorf("columns of %s: %w", table, err)
+	}
+	values := make([]any, len(columns))
+	targets := make([]any, len(columns))
+	for index := range values {
+		targets[index] = &values[index]
+	}
+
+	var (
+		lanes [4]uint64
+		count int64
+		buf   []byte
+	)
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return TableChecksum{}, fmt.Errorf("scan %s: %w", table, err)
+		}
+		buf = buf[:0]
+		for _, value := range values {
+			buf = appendChecksumValue(buf, value)
+		}
+		digest := sha256.Sum256(buf)
+		for lane := range lanes {
+			lanes[lane] += binary.BigEndian.Uint64(digest[lane*8:])
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return TableChecksum{}, fmt.Errorf("iterate %s: %w", table, err)
+	}
+
+	var sum [32]byte
+	for lane, value := range lanes {
+		binary.BigEndian.PutUint64(sum[lane*8:], value)
+	}
+	return TableChecksum{Table: table, Rows: count, Checksum: hex.EncodeToString(sum[:])}, nil
+}
+
+// appendChecksumValue encodes one column with a type tag and length so that
+// adjacent values cannot run together.
+func appendChecksumValue(buf []byte, value any) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, 'N')
+	case int64:
+		return binary.BigEndian.AppendUint64(append(buf, 'I'), uint64(v))
+	case float64:
+		return binary.BigEndian.AppendUint64(append(buf, 'F'), math.Float64bits(v))
+	case bool:
+		if v {
+			return append(buf, 'T')
+		}
+		return append(buf, 'f')
+	case string:
+		buf = binary.BigEndian.AppendUint64(append(buf, 'S'), uint64(len(v)))
+		return append(buf, v...)
+	case []byte:
+		buf = binary.BigEndian.AppendUint64(append(buf, 'B'), uint64(len(v)))
+		return append(buf, v...)
+	case time.Time:
+		text := v.UTC().Format(time.RFC3339Nano)
+		buf = binary.BigEndian.AppendUint64(append(buf, 'D'), uint64(len(text)))
+		return append(buf, text...)
+	default:
+		text := fmt.Sprint(v)
+		buf = binary.BigEndian.AppendUint64(append(buf, 'X'), uint64(len(text)))
+		return append(buf, text...)
+	}
+}
+
+func removeDatabaseFiles(path string) {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		_ = os.Remove(path + suffix)
+	}
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateDatabaseCopiesVerifiesAndLeavesTombstone(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	fromPath := filepath.Join(dir, "old.db")
+	toPath := filepath.Join(dir, "moved", "new.db")
+
+	database, err := Open(fromPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	store := NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	if _, err := store.UpsertMatchStart(ctx, tx, "match-1", "PremierDraft_BLB_20240730", 1, "2026-10-02T18:00:00Z"); err != nil {
+		t.Fatalf("UpsertMatchStart: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if err := database.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(toPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	sums, err := MigrateDatabase(ctx, fromPath, toPath)
+	if err != nil {
+		t.Fatalf("MigrateDatabase: %v", err)
+	}
+	var sawMatches bool
+	for _, sum := range sums {
+		if sum.Table == "matches" {
+			sawMatches = sum.Rows == 1
+		}
+	}
+	if !sawMatches {
+		t.Fatalf("checksums = %+v, want matches with one row", sums)
+	}
+
+	source, err := Open(fromPath)
+	if err != nil {
+		t.Fatalf("Open(source): %v", err)
+	}
+	defer source.Close()
+	movedTo, err := NewStore(source).MovedTo(ctx)
+	if err != nil {
+		t.Fatalf("MovedTo: %v", err)
+	}
+	if movedTo != toPath {
+		t.Fatalf("MovedTo = %q, want %q", movedTo, toPath)
+	}
+
+	copied, err := Open(toPath)
+	if err != nil {
+		t.Fatalf("Open(copy): %v", err)
+	}
+	defer copied.Close()
+	if movedTo, err := NewStore(copied).MovedTo(ctx); err != nil || movedTo != "" {
+		t.Fatalf("copy MovedTo = %q, %v; want no tombstone", movedTo, err)
+	}
+	var count int
+	if err := copied.QueryRowContext(ctx, `SELECT COUNT(*) FROM matches`).Scan(&count); err != nil || count != 1 {
+		t.Fatalf("copied matches = %d, %v; want 1", count, err)
+	}
+
+	if _, err := MigrateDatabase(ctx, fromPath, filepath.Join(dir, "again.db")); err == nil || !strings.Contains(err.Error(), "already migrated") {
+		t.Fatalf("second migration err = %v, want already migrated", err)
+	}
+}
+
+func TestMigrateDatabaseRefusesExistingDestination(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	fromPath := filepath.Join(dir, "old.db")
+	toPath := filepath.Join(dir, "new.db")
+
+	database, err := Open(fromPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	_ = database.Close()
+	if err := os.WriteFile(toPath, []byte("keep me"), 0o644); err != nil {
+		t.Fatalf("write destination: %v", err)
+	}
+
+	if _, err := MigrateDatabase(ctx, fromPath, toPath); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("err = %v, want destination already exists", err)
+	}
+	if raw, err := os.ReadFile(toPath); err != nil || string(raw) != "keep me" {
+		t.Fatalf("destination = %q, %v; want untouched", raw, err)
+	}
+	if _, err := MigrateDatabase(ctx, fromPath, fromPath); err == nil {
+		t.Fatalf("migrating onto itself succeeded")
+	}
+}
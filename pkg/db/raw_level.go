@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// appMetadataRawEventLevelKey records that events_raw holds rows stored at
+// RawEventsFull, so maintenance passes run by other commands keep them.
+const appMetadataRawEventLevelKey = "raw_event_level"
+
+// RawEventLevel is how much InsertRawEvent keeps in events_raw.
+type RawEventLevel int
+
+const (
+	// RawEventsMinimal keeps the outgoing draft and deck requests that
+	// reprocess and draft repair read back. It is the zero value.
+	RawEventsMinimal RawEventLevel = iota
+	// RawEventsNone keeps nothing, for users who only want stats. Draft
+	// repair and reprocess then have nothing to work from.
+	RawEventsNone
+	// RawEventsFull keeps every event the parser handles, with the log line
+	// for events that have no decoded payload, for debugging the parser.
+	RawEventsFull
+)
+
+// RawEventLevels are the names ParseRawEventLevel accepts.
+var RawEventLevels = []string{"none", "minimal", "full"}
+
+func (l RawEventLevel) String() string {
+	switch l {
+	case RawEventsNone:
+		return "none"
+	case RawEventsFull:
+		return "full"
+	default:
+		return "minimal"
+	}
+}
+
+// ParseRawEventLevel reads a level by name; "" is RawEventsMinimal.
+func ParseRawEventLevel(name string) (RawEventLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "minimal":
+		return RawEventsMinimal, nil
+	case "none":
+		return RawEventsNone, nil
+	case "full":
+		return RawEventsFull, nil
+	}
+	return RawEventsMinimal, fmt.Errorf("unknown raw event level %q (want %s)", name, strings.Join(RawEventLevels, ", "))
+}
+
+// SetRawEventLevel sets what InsertRawEvent keeps from now on. Rows already
+// stored are left alone.
+func (s *Store) SetRawEventLevel(level RawEventLevel) {
+	s.rawLevel = level
+}
+
+// RawEventLevel reports what InsertRawEvent keeps.
+func (s *Store) RawEventLevel() RawEventLevel {
+	return s.rawLevel
+}
+
+// NoteFullRawEvents records, in tx, that rows were stored at RawEventsFull.
+// From then on RunMaintenance leaves unread rows alone, whatever level the
+// store running it has; an explicit Prune without KeepUnread clears it.
+func (s *Store) NoteFullRawEvents(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO app_metadata (key, value, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, appMetadataRawEventLevelKey, RawEventsFull.String(), s.nowUTC()); err != nil {
+		return fmt.Errorf("record raw event level: %w", err)
+	}
+	return nil
+}
+
+// keepsFullRawEvents reports whether events_raw holds rows stored at
+// RawEventsFull, or this store stores them.
+func (s *Store) keepsFullRawEvents(ctx context.Context) (bool, error) {
+	if s.rawLevel == RawEventsFull {
+		return true, nil
+	}
+	return s.HoldsFullRawEvents(ctx)
+}
+
+// HoldsFullRawEvents reports whether events_raw holds rows stored at
+// RawEventsFull, as recorded by NoteFullRawEvents.
+func (s *Store) HoldsFullRawEvents(ctx context.Context) (bool, error) {
+	var level string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM app_metadata WHERE key = ?`, appMetadataRawEventLevelKey).Scan(&level)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read raw event level: %w", err)
+	}
+	return level == RawEventsFull.String(), nil
+}
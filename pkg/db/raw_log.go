@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// KeepsRawEvent reports whether InsertRawEvent stores an event of this
+// kind, method, and payload, so a log can be checked against what should
+// have been kept from it.
+func KeepsRawEvent(kind, method string, payload []byte) bool {
+	return shouldPersistRawEvent(kind, method, payload)
+}
+
+// FindRawEvent reports whether an event with this content is stored, read
+// from any log file, and the log timestamp it was stored with (empty for
+// rows stored before timestamps were kept). It uses the same key that
+// dedupes events_raw.
+func (s *Store) FindRawEvent(ctx context.Context, kind, method, requestID string, payload []byte) (observedAt string, found bool, err error) {
+	err = s.reader().QueryRowContext(ctx, `
+		SELECT COALESCE(observed_at, '') FROM events_raw
+		WHERE kind = ? AND COALESCE(method_name, '') = ? AND COALESCE(request_id, '') = ? AND payload_hash = ?
+		ORDER BY id
+		LIMIT 1
+	`, kind, method, requestID, rawEventPayloadHash(payload)).Scan(&observedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("look up raw event: %w", err)
+	}
+	return observedAt, true, nil
+}
+
+// RawEventLogCoverage is what events_raw holds from one source log.
+// FirstAt and LastAt are the earliest and latest log timestamps stored with
+// its events; empty when none of them has one.
+type RawEventLogCoverage struct {
+	LogPath   string
+	Events    int64
+	FirstLine int64
+	LastLine  int64
+	FirstAt   string
+	LastAt    string
+}
+
+// RawEventCoverage summarizes stored raw events per source log, in the
+// order the logs were first read.
+func (s *Store) RawEventCoverage(ctx context.Context) ([]RawEventLogCoverage, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT log_path, COUNT(*), MIN(line_no), MAX(line_no), COALESCE(MIN(observed_at), ''), COALESCE(MAX(observed_at), '')
+		FROM events_raw
+		GROUP BY log_path
+		ORDER BY MIN(id) ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("raw event coverage: %w", err)
+	}
+	defer rows.Close()
+
+	var out []RawEventLogCoverage
+	for rows.Next() {
+		var row RawEventLogCoverage
+		if err := rows.Scan(&row.LogPath, &row.Events, &row.FirstLine, &row.LastLine, &row.FirstAt, &row.LastAt); err != nil {
+			return nil, fmt.Errorf("scan raw event coverage: %w", err)
+		}
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate raw event coverage: %w", err)
+	}
+	return out, nil
+}
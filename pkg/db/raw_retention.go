@@ -0,0 +1,196 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RawEventPolicy bounds events_raw beyond the filtering InsertRawEvent
+// already does. Zero values keep everything uncompressed, forever.
+type RawEventPolicy struct {
+	// MaxAge deletes rows stored longer ago than this.
+	MaxAge time.Duration
+	// MaxRows keeps only the newest MaxRows rows.
+	MaxRows int64
+	// CompressAfter moves payloads of rows stored longer ago than this into
+	// zstd. Draft repair reads payloads in SQL and skips compressed rows, so
+	// this should leave at least the last few days in plain text.
+	CompressAfter time.Duration
+	// KeepUnread skips deleting rows nothing reads back, which is what a
+	// database parsed at RawEventsFull is kept for.
+	KeepUnread bool
+}
+
+// PruneResult reports one Prune: rows removed or compressed and the bytes
+// the database file shrank by after the VACUUM.
+type PruneResult struct {
+	Unread         int64
+	Expired        int64
+	OverLimit      int64
+	Compressed     int64
+	BytesBefore    int64
+	BytesAfter     int64
+	BytesReclaimed int64
+}
+
+// Prune applies policy to events_raw, after dropping rows no reader
+// consumes, then VACUUMs so the space returns to the filesystem.
+func (s *Store) Prune(ctx context.Context, policy RawEventPolicy) (PruneResult, error) {
+	result := PruneResult{}
+	before, err := s.databaseBytes(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.BytesBefore = before
+
+	if !policy.KeepUnread {
+		if result.Unread, err = s.PruneRawEvents(ctx); err != nil {
+			return result, err
+		}
+		// The full rows are gone, so maintenance may prune again.
+		if _, err := s.exec(ctx, `DELETE FROM app_metadata WHERE key = ?`, appMetadataRawEventLevelKey); err != nil {
+			return result, fmt.Errorf("clear raw event level: %w", err)
+		}
+	}
+	if policy.MaxAge > 0 {
+		cutoff := s.now().Add(-policy.MaxAge).Format(time.RFC3339Nano)
+		res, err := s.exec(ctx, `DELETE FROM events_raw WHERE created_at < ?`, cutoff)
+		if err != nil {
+			return result, fmt.Errorf("expire events_raw: %w", err)
+		}
+		result.Expired, _ = res.RowsAffected()
+	}
+	if policy.MaxRows > 0 {
+		res, err := s.exec(ctx, `
+			DELETE FROM events_raw
+			WHERE id <= (SELECT id FROM events_raw ORDER BY id DESC LIMIT 1 OFFSET ?)
+		`, policy.MaxRows)
+		if err != nil {
+			return result, fmt.Errorf("trim events_raw: %w", err)
+		}
+		result.OverLimit, _ = res.RowsAffected()
+	}
+	if policy.CompressAfter > 0 {
+		cutoff := s.now().Add(-policy.CompressAfter).Format(time.RFC3339Nano)
+		if result.Compressed, err = s.compressRawEvents(ctx, cutoff); err != nil {
+			return result, err
+		}
+	}
+
+	if _, err := s.exec(ctx, `VACUUM`); err != nil {
+		return result, fmt.Errorf("vacuum after prune: %w", err)
+	}
+	var busy, logFrames, checkpointed int64
+	_ = s.db.QueryRowContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`).Scan(&busy, &logFrames, &checkpointed)
+
+	after, err := s.databaseBytes(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.BytesAfter = after
+	if before > after {
+		result.BytesReclaimed = before - after
+	}
+	return result, nil
+}
+
+// compressRawEvents moves payload_json of rows stored before cutoff into
+// payload_zstd, a batch per transaction so tail is never blocked for long.
+func (s *Store) compressRawEvents(ctx context.Context, cutoff string) (int64, error) {
+	const batchSize = 500
+	var compressed int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return compressed, err
+		}
+		n, err := s.compressRawEventBatch(ctx, cutoff, batchSize)
+		compressed += n
+		if err != nil {
+			return compressed, err
+		}
+		if n < batchSize {
+			return compressed, nil
+		}
+	}
+}
+
+func (s *Store) compressRawEventBatch(ctx context.Context, cutoff string, limit int) (int64, error) {
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, payload_json
+		FROM events_raw
+		WHERE created_at < ? AND payload_zstd IS NULL AND COALESCE(payload_json, '') != ''
+		ORDER BY id ASC
+		LIMIT ?
+	`, cutoff, limit)
+	if err != nil {
+		return 0, fmt.Errorf("list raw events to compress: %w", err)
+	}
+	type pending struct {
+		id      int64
+		payload string
+	}
+	var batch []pending
+	for rows.Next() {
+		var row pending
+		if err := rows.Scan(&row.id, &row.payload); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan raw event to compress: %w", err)
+		}
+		batch = append(batch, row)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("iterate raw events to compress: %w", err)
+	}
+	rows.Close()
+
+	for _, row := range batch {
+		packed := getZstdEncoder().EncodeAll([]byte(row.payload), nil)
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE events_raw SET payload_json = NULL, payload_zstd = ? WHERE id = ?
+		`, packed, row.id); err != nil {
+			return 0, fmt.Errorf("compress raw event %d: %w", row.id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit raw event compression: %w", err)
+	}
+	return int64(len(batch)), nil
+}
+
+// rawEventPayload returns a stored raw event's payload, whichever column
+// holds it.
+func rawEventPayload(payloadJSON sql.NullString, payloadZstd []byte) ([]byte, error) {
+	if len(payloadZstd) > 0 {
+		raw, err := getZstdDecoder().DecodeAll(payloadZstd, nil)
+		if err != nil {
+			return nil, fmt.Errorf("decompress raw event payload: %w", err)
+		}
+		return raw, nil
+	}
+	if !payloadJSON.Valid {
+		return nil, nil
+	}
+	return []byte(payloadJSON.String), nil
+}
+
+// databaseBytes is the size of the main database file in pages, which is
+// what VACUUM shrinks.
+func (s *Store) databaseBytes(ctx context.Context) (int64, error) {
+	var pageCount, pageSize int64
+	if err := s.db.QueryRowContext(ctx, `PRAGMA page_count`).Scan(&pageCount); err != nil {
+		return 0, fmt.Errorf("read page count: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `PRAGMA page_size`).Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("read page size: %w", err)
+	}
+	return pageCount * pageSize, nil
+}
@@ -13,7 +13,7 @@ import (
 
 	"github.com/klauspost/compress/zstd"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 // Replay frames are written as relational rows while a match is live so the
@@ -167,7 +167,7 @@ func (s *Store) archiveMatchReplayRows(ctx context.Context, q querier, matchID i
 		objectCount += int64(len(frames[i].Objects))
 	}
 
-	now := nowUTC()
+	now := s.nowUTC()
 	if _, err := q.ExecContext(ctx, `
 		INSERT INTO match_replay_archives (
 			match_id, schema_version, frame_count, object_count, payload_zstd, created_at, updated_at
@@ -470,7 +470,7 @@ func (s *Store) loadArchivedMatchReplayFrames(ctx context.Context, matchID int64
 	}
 
 	var playerSeatID sql.NullInt64
-	err = s.db.QueryRowContext(ctx, `SELECT player_seat_id FROM matches WHERE id = ?`, matchID).Scan(&playerSeatID)
+	err = s.reader().QueryRowContext(ctx, `SELECT player_seat_id FROM matches WHERE id = ?`, matchID).Scan(&playerSeatID)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("lookup match seat for replay archive: %w", err)
 	}
@@ -567,8 +567,8 @@ func replayObjectPlayerSide(controllerSeatID, ownerSeatID, selfSeatID *int64) st
 // that never received an end event. Each match is archived in its own
 // transaction. Returns the number of matches archived.
 func (s *Store) CompactMatchReplays(ctx context.Context) (int, error) {
-	cutoff := time.Now().UTC().Add(-replayArchiveStaleMatchAge).Format(time.RFC3339)
-	rows, err := s.db.QueryContext(ctx, `
+	cutoff := s.now().Add(-replayArchiveStaleMatchAge).Format(time.RFC3339)
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT DISTINCT m.id
 		FROM matches m
 		JOIN match_replay_frames f ON f.match_id = m.id
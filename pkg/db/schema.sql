@@ -1,5 +1,16 @@
 -- journal_mode, foreign_keys, and the other connection pragmas are set on the
 -- DSN in db.Open so they apply to every pooled connection.
+--
+-- This file is the baseline schema. Later changes are numbered migrations in
+-- schema_migrations.go, which Init applies on top of it; don't edit tables
+-- here to change their shape.
+
+-- One row per numbered migration applied to this database.
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  applied_at TEXT NOT NULL
+);
 
 CREATE TABLE IF NOT EXISTS ingest_state (
   log_path TEXT PRIMARY KEY,
@@ -119,13 +130,16 @@ CREATE TABLE IF NOT EXISTS card_types (
 -- Friendly metadata for MTG sets, keyed by the lowercase set code embedded in
 -- Arena event names (e.g. "tmt" in "QuickDraft_TMT_20260313"). Resolved on
 -- demand from Scryfall and cached here so set names/symbols work offline.
--- Card color identity and mana value, resolved on demand from the local MTGA
--- raw card database (preferred) or Scryfall, and cached for offline matchup
--- classification. color_identity is a WUBRG-ordered subset string ("UB").
+-- Card color identity, mana value, and set, resolved on demand from the local
+-- MTGA raw card database (preferred) or Scryfall, and cached for offline
+-- matchup classification and /api/cards. color_identity is a WUBRG-ordered
+-- subset string ("UB"); set_code is lowercase, '' until resolved. Migration 1
+-- adds colors, collector_number, and rarity, and the card_info view.
 CREATE TABLE IF NOT EXISTS card_metadata (
   arena_id INTEGER PRIMARY KEY,
   color_identity TEXT NOT NULL DEFAULT '',
   mana_value REAL,
+  set_code TEXT NOT NULL DEFAULT '',
   updated_at TEXT NOT NULL
 );
 
@@ -161,6 +175,7 @@ CREATE TABLE IF NOT EXISTS matches (
   win_reason TEXT,
   turn_count INTEGER,
   seconds_count INTEGER,
+  queue_seconds INTEGER,
   created_at TEXT NOT NULL,
   updated_at TEXT NOT NULL
 );
@@ -205,6 +220,10 @@ CREATE TABLE IF NOT EXISTS games (
   opening_hand_confidence TEXT NOT NULL DEFAULT 'unknown',
   min_self_life INTEGER,
   min_opponent_life INTEGER,
+  self_spells_cast INTEGER,
+  opponent_spells_cast INTEGER,
+  self_attackers_declared INTEGER,
+  opponent_attackers_declared INTEGER,
   derived_at TEXT NOT NULL,
   UNIQUE(match_id, game_number),
   FOREIGN KEY(match_id) REFERENCES matches(id) ON DELETE CASCADE
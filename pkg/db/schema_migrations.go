@@ -0,0 +1,907 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// schemaMigration is one numbered schema change. schema.sql plus the in-place
+// upgrades in Init are the baseline (version 0); every later change to a
+// table's shape is added here with the next version, an up step, and a down
+// step that undoes it. Each step runs in a transaction together with its
+// schema_migrations bookkeeping, so a failed step leaves nothing behind.
+type schemaMigration struct {
+	version int
+	name    string
+	up      func(ctx context.Context, tx *sql.Tx) error
+	down    func(ctx context.Context, tx *sql.Tx) error
+}
+
+var schemaMigrations = []schemaMigration{
+	{1, "card_metadata_details", upCardMetadataDetails, downCardMetadataDetails},
+	{2, "events_raw_payload_zstd", upEventsRawPayloadZstd, downEventsRawPayloadZstd},
+	{3, "match_query_indexes", upMatchQueryIndexes, downMatchQueryIndexes},
+	{4, "daily_stats", upDailyStats, downDailyStats},
+	{5, "events_raw_payload_hash", upEventsRawPayloadHash, downEventsRawPayloadHash},
+	{6, "parse_errors", upParseErrors, downParseErrors},
+	{7, "match_archived", upMatchArchived, downMatchArchived},
+	{8, "settings", upSettings, downSettings},
+	{9, "import_source", upImportSource, downImportSource},
+	{10, "card_ratings", upCardRatings, downCardRatings},
+	{11, "deck_pushes", upDeckPushes, downDeckPushes},
+	{12, "card_price_snapshots", upCardPriceSnapshots, downCardPriceSnapshots},
+	{13, "turn_spells_in_hand", upTurnSpellsInHand, downTurnSpellsInHand},
+	{14, "match_opponent_rank", upMatchOpponentRank, downMatchOpponentRank},
+	{15, "events_raw_observed_at", upEventsRawObservedAt, downEventsRawObservedAt},
+}
+
+// MigrationStatus describes one known migration and, if applied, when.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt string
+}
+
+// LatestMigration is the highest migration version this build knows.
+func LatestMigration() int {
+	if len(schemaMigrations) == 0 {
+		return 0
+	}
+	return schemaMigrations[len(schemaMigrations)-1].version
+}
+
+// MigrationStatuses lists every migration this build knows, oldest first,
+// with AppliedAt empty for those not applied to db.
+func MigrationStatuses(ctx context.Context, db *sql.DB) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MigrationStatus, 0, len(schemaMigrations))
+	for _, migration := range schemaMigrations {
+		out = append(out, MigrationStatus{
+			Version:   migration.version,
+			Name:      migration.name,
+			AppliedAt: applied[migration.version],
+		})
+	}
+	return out, nil
+}
+
+// MigrateTo applies or reverts migrations until db is at version target:
+// up steps oldest first when target is ahead, down steps newest first when it
+// is behind. Init always migrates to LatestMigration, so reverting is only
+// useful right before running an older build. Returns the versions stepped
+// through, in order.
+func MigrateTo(ctx context.Context, db *sql.DB, target int) ([]int, error) {
+	if target < 0 || target > LatestMigration() {
+		return nil, fmt.Errorf("migration version %d out of range 0-%d", target, LatestMigration())
+	}
+	// Same footing as Init's migrations: a dedicated connection with foreign
+	// key enforcement off, since table rebuilds drop referenced tables.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire migration connection: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return nil, fmt.Errorf("disable foreign keys for migration: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(ctx, `PRAGMA foreign_keys = ON`)
+	}()
+	return migrateTo(ctx, conn, target)
+}
+
+func migrateTo(ctx context.Context, conn dbConn, target int) ([]int, error) {
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var stepped []int
+	for _, migration := range schemaMigrations {
+		if migration.version > target {
+			break
+		}
+		if _, ok := applied[migration.version]; ok {
+			continue
+		}
+		if err := runMigrationStep(ctx, conn, migration, true); err != nil {
+			return stepped, err
+		}
+		stepped = append(stepped, migration.version)
+	}
+	for index := len(schemaMigrations) - 1; index >= 0; index-- {
+		migration := schemaMigrations[index]
+		if migration.version <= target {
+			break
+		}
+		if _, ok := applied[migration.version]; !ok {
+			continue
+		}
+		if err := runMigrationStep(ctx, conn, migration, false); err != nil {
+			return stepped, err
+		}
+		stepped = append(stepped, migration.version)
+	}
+	return stepped, nil
+}
+
+func runMigrationStep(ctx context.Context, conn dbConn, migration schemaMigration, up bool) error {
+	direction, step := "up", migration.up
+	if !up {
+		direction, step = "down", migration.down
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration %d %s: %w", migration.version, direction, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := step(ctx, tx); err != nil {
+		return fmt.Errorf("migration %d (%s) %s: %w", migration.version, migration.name, direction, err)
+	}
+	if up {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO schema_migrations (version, name, applied_at)
+			VALUES (?, ?, ?)
+		`, migration.version, migration.name, nowUTC())
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, migration.version)
+	}
+	if err != nil {
+		return fmt.Errorf("record migration %d %s: %w", migration.version, direction, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %d %s: %w", migration.version, direction, err)
+	}
+	return nil
+}
+
+// appliedMigrations maps each applied version to when it was applied.
+func appliedMigrations(ctx context.Context, conn dbConn) (map[int]string, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("list schema migrations: %w", err)
+	}
+	defer rows.Close()
+	out := map[int]string{}
+	for rows.Next() {
+		var version int
+		var appliedAt string
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("scan schema migration: %w", err)
+		}
+		out[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schema migrations: %w", err)
+	}
+	return out, nil
+}
+
+// cardInfoViewSQL joins the card caches into one row per card for analytics
+// in SQL.
+const cardInfoViewSQL = `
+	CREATE VIEW card_info AS
+	SELECT
+		m.arena_id,
+		COALESCE(n.name, '') AS name,
+		COALESCE(t.type_line, '') AS type_line,
+		m.set_code,
+		m.collector_number,
+		m.rarity,
+		m.mana_value,
+		m.colors,
+		m.color_identity
+	FROM card_metadata m
+	LEFT JOIN card_catalog n ON n.arena_id = m.arena_id
+	LEFT JOIN card_types t ON t.arena_id = m.arena_id
+`
+
+// upCardMetadataDetails adds the card's own colors (WUBRG subset string),
+// collector number, and lowercase rarity ("mythic") to card_metadata, plus
+// the card_info view. Rows cached before have a set code, so lookups would
+// never revisit them; they are dropped, along with the bulk sync marker, so
+// the next lookup or `cards sync` fills every column.
+func upCardMetadataDetails(ctx context.Context, tx *sql.Tx) error {
+	for _, column := range []struct{ name, ddl string }{
+		{"colors", `ALTER TABLE card_metadata ADD COLUMN colors TEXT NOT NULL DEFAULT ''`},
+		{"collector_number", `ALTER TABLE card_metadata ADD COLUMN collector_number TEXT NOT NULL DEFAULT ''`},
+		{"rarity", `ALTER TABLE card_metadata ADD COLUMN rarity TEXT NOT NULL DEFAULT ''`},
+	} {
+		// Databases from development builds may already carry the column.
+		hasColumn, err := tableHasColumnInTx(ctx, tx, "card_metadata", column.name)
+		if err != nil {
+			return err
+		}
+		if hasColumn {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, column.ddl); err != nil {
+			return err
+		}
+	}
+	for _, stmt := range []string{
+		`DELETE FROM card_metadata`,
+		`DELETE FROM app_metadata WHERE key = '` + appMetadataCardCatalogVersionKey + `'`,
+		`DROP VIEW IF EXISTS card_info`,
+		cardInfoViewSQL,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downCardMetadataDetails(ctx context.Context, tx *sql.Tx) error {
+	for _, stmt := range []string{
+		`DROP VIEW IF EXISTS card_info`,
+		`ALTER TABLE card_metadata DROP COLUMN rarity`,
+		`ALTER TABLE card_metadata DROP COLUMN collector_number`,
+		`ALTER TABLE card_metadata DROP COLUMN colors`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upEventsRawPayloadZstd adds payload_zstd, where Prune moves old raw event
+// payloads; a compressed row has payload_json NULL.
+func upEventsRawPayloadZstd(ctx context.Context, tx *sql.Tx) error {
+	hasColumn, err := tableHasColumnInTx(ctx, tx, "events_raw", "payload_zstd")
+	if err != nil || hasColumn {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `ALTER TABLE events_raw ADD COLUMN payload_zstd BLOB`)
+	return err
+}
+
+// downEventsRawPayloadZstd decompresses payloads back into payload_json so
+// older builds, which only know that column, still see them.
+func downEventsRawPayloadZstd(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id, payload_zstd FROM events_raw WHERE payload_zstd IS NOT NULL`)
+	if err != nil {
+		return err
+	}
+	payloads := map[int64][]byte{}
+	for rows.Next() {
+		var id int64
+		var packed []byte
+		if err := rows.Scan(&id, &packed); err != nil {
+			rows.Close()
+			return err
+		}
+		payloads[id] = packed
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	for id, packed := range payloads {
+		raw, err := rawEventPayload(sql.NullString{}, packed)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE events_raw SET payload_json = ? WHERE id = ?`, string(raw), id); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `ALTER TABLE events_raw DROP COLUMN payload_zstd`)
+	return err
+}
+
+// matchQueryIndexes back the match list filters (event, result) ordered
+// newest first, and the deck pages that reach matches through match_decks.
+// deck_cards(deck_id) and draft_picks(draft_session_id) are already indexed
+// by schema.sql.
+var matchQueryIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_matches_sort_at ON matches(sort_at)`,
+	`CREATE INDEX IF NOT EXISTS idx_matches_event_sort_at ON matches(event_name, sort_at)`,
+	`CREATE INDEX IF NOT EXISTS idx_matches_result_sort_at ON matches(result, sort_at)`,
+	`CREATE INDEX IF NOT EXISTS idx_match_decks_deck_id ON match_decks(deck_id)`,
+}
+
+// upMatchQueryIndexes adds matches.sort_at, a virtual column holding the
+// timestamp match lists sort by, so ORDER BY can walk an index instead of
+// sorting every row. Generated columns are hidden from PRAGMA table_info, so
+// the column is looked up in table_xinfo.
+func upMatchQueryIndexes(ctx context.Context, tx *sql.Tx) error {
+	var hasColumn bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) > 0 FROM pragma_table_xinfo('matches') WHERE name = 'sort_at'
+	`).Scan(&hasColumn); err != nil {
+		return err
+	}
+	if !hasColumn {
+		if _, err := tx.ExecContext(ctx, `
+			ALTER TABLE matches ADD COLUMN sort_at TEXT
+			GENERATED ALWAYS AS (COALESCE(started_at, ended_at, updated_at)) VIRTUAL
+		`); err != nil {
+			return err
+		}
+	}
+	for _, stmt := range matchQueryIndexes {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downMatchQueryIndexes(ctx context.Context, tx *sql.Tx) error {
+	for _, stmt := range []string{
+		`DROP INDEX IF EXISTS idx_match_decks_deck_id`,
+		`DROP INDEX IF EXISTS idx_matches_result_sort_at`,
+		`DROP INDEX IF EXISTS idx_matches_event_sort_at`,
+		`DROP INDEX IF EXISTS idx_matches_sort_at`,
+		`ALTER TABLE matches DROP COLUMN sort_at`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchStatsDay is the UTC day a match counts toward in the daily stats
+// tables: the date part of the same timestamp match lists sort by.
+const matchStatsDay = `substr(COALESCE(%[1]s.started_at, %[1]s.ended_at, %[1]s.updated_at), 1, 10)`
+
+// refreshMatchDailyStatsSQL recomputes the match_daily_stats row for one
+// (day, event, format) key from matches. The sort_at range keeps the rebuild
+// on idx_matches_sort_at, so it reads one day of matches rather than all.
+const refreshMatchDailyStatsSQL = `
+	DELETE FROM match_daily_stats
+	WHERE day = %[1]s AND event_name = %[2]s AND format = %[3]s;
+	INSERT INTO match_daily_stats (day, event_name, format, matches, wins, losses)
+	SELECT %[1]s, %[2]s, %[3]s, COUNT(*),
+		SUM(CASE WHEN m.result = 'win' THEN 1 ELSE 0 END),
+		SUM(CASE WHEN m.result = 'loss' THEN 1 ELSE 0 END)
+	FROM matches m
+	WHERE m.sort_at >= %[1]s AND m.sort_at < %[1]s || '~'
+	  AND COALESCE(m.event_name, '') = %[2]s
+	  AND COALESCE(m.format, '') = %[3]s%[4]s
+	HAVING COUNT(*) > 0;
+`
+
+// refreshDeckDailyStatsSQL recomputes every deck_daily_stats row for the decks
+// selected by %[1]s. A deck's matches are few and reached through
+// idx_match_decks_deck_id, so a whole-deck rebuild is cheap and stays right
+// whichever order a match and its match_decks rows are deleted in.
+const refreshDeckDailyStatsSQL = `
+	DELETE FROM deck_daily_stats WHERE deck_id IN (%[1]s);
+	INSERT INTO deck_daily_stats (deck_id, day, matches, wins, losses, first_played_at)
+	SELECT md.deck_id, substr(m.sort_at, 1, 10), COUNT(*),
+		SUM(CASE WHEN m.result = 'win' THEN 1 ELSE 0 END),
+		SUM(CASE WHEN m.result = 'loss' THEN 1 ELSE 0 END),
+		MIN(COALESCE(m.started_at, m.ended_at))
+	FROM match_decks md
+	JOIN matches m ON m.id = md.match_id
+	WHERE md.deck_id IN (%[1]s)%[2]s
+	GROUP BY md.deck_id, substr(m.sort_at, 1, 10);
+`
+
+// dailyStats builds the statements that maintain match_daily_stats and
+// deck_daily_stats. Schema versions from match_archived on leave archived
+// matches out of both; earlier ones have no archived column to test.
+type dailyStats struct {
+	excludeArchived bool
+}
+
+func (d dailyStats) matchFilter() string {
+	if d.excludeArchived {
+		return "\n\t  AND m.archived = 0"
+	}
+	return ""
+}
+
+func (d dailyStats) refreshMatchRow(row string) string {
+	return fmt.Sprintf(refreshMatchDailyStatsSQL,
+		fmt.Sprintf(matchStatsDay, row),
+		fmt.Sprintf("COALESCE(%s.event_name, '')", row),
+		fmt.Sprintf("COALESCE(%s.format, '')", row),
+		d.matchFilter())
+}
+
+func (d dailyStats) refreshDecks(deckIDs string) string {
+	return fmt.Sprintf(refreshDeckDailyStatsSQL, deckIDs, d.matchFilter())
+}
+
+// triggers keep both tables current inside whatever transaction writes
+// matches or match_decks, so ingest, merges, and deletes all update them
+// without a separate refresh pass.
+func (d dailyStats) triggers() []struct{ name, ddl string } {
+	updateColumns := "started_at, ended_at, updated_at, event_name, format, result"
+	updateWhen := ""
+	if d.excludeArchived {
+		updateColumns += ", archived"
+		updateWhen = "\n\t\t  OR OLD.archived IS NOT NEW.archived"
+	}
+	return []struct{ name, ddl string }{
+		{"trg_matches_daily_stats_insert", `
+		CREATE TRIGGER trg_matches_daily_stats_insert AFTER INSERT ON matches
+		BEGIN` + d.refreshMatchRow("NEW") +
+			d.refreshDecks(`SELECT deck_id FROM match_decks WHERE match_id = NEW.id`) + `
+		END`},
+		{"trg_matches_daily_stats_update", `
+		CREATE TRIGGER trg_matches_daily_stats_update
+		AFTER UPDATE OF ` + updateColumns + ` ON matches
+		WHEN OLD.result IS NOT NEW.result
+		  OR OLD.event_name IS NOT NEW.event_name
+		  OR OLD.format IS NOT NEW.format
+		  OR OLD.started_at IS NOT NEW.started_at
+		  OR OLD.ended_at IS NOT NEW.ended_at
+		  OR ` + fmt.Sprintf(matchStatsDay, "OLD") + ` IS NOT ` + fmt.Sprintf(matchStatsDay, "NEW") + updateWhen + `
+		BEGIN` + d.refreshMatchRow("OLD") + d.refreshMatchRow("NEW") +
+			d.refreshDecks(`SELECT deck_id FROM match_decks WHERE match_id = NEW.id`) + `
+		END`},
+		{"trg_matches_daily_stats_delete", `
+		CREATE TRIGGER trg_matches_daily_stats_delete AFTER DELETE ON matches
+		BEGIN` + d.refreshMatchRow("OLD") +
+			d.refreshDecks(`SELECT deck_id FROM match_decks WHERE match_id = OLD.id`) + `
+		END`},
+		{"trg_match_decks_daily_stats_insert", `
+		CREATE TRIGGER trg_match_decks_daily_stats_insert AFTER INSERT ON match_decks
+		BEGIN` + d.refreshDecks(`NEW.deck_id`) + `
+		END`},
+		{"trg_match_decks_daily_stats_update", `
+		CREATE TRIGGER trg_match_decks_daily_stats_update
+		AFTER UPDATE OF match_id, deck_id ON match_decks
+		BEGIN` + d.refreshDecks(`OLD.deck_id, NEW.deck_id`) + `
+		END`},
+		{"trg_match_decks_daily_stats_delete", `
+		CREATE TRIGGER trg_match_decks_daily_stats_delete AFTER DELETE ON match_decks
+		BEGIN` + d.refreshDecks(`OLD.deck_id`) + `
+		END`},
+		{"trg_decks_daily_stats_delete", `
+		CREATE TRIGGER trg_decks_daily_stats_delete AFTER DELETE ON decks
+		BEGIN
+			DELETE FROM deck_daily_stats WHERE deck_id = OLD.id;
+		END`},
+	}
+}
+
+// dropTriggers removes the maintaining triggers; their names do not depend
+// on the schema version.
+func (d dailyStats) dropTriggers(ctx context.Context, tx *sql.Tx) error {
+	for _, trigger := range d.triggers() {
+		if _, err := tx.ExecContext(ctx, `DROP TRIGGER IF EXISTS `+trigger.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// install refills both tables from matches and (re)creates the triggers.
+func (d dailyStats) install(ctx context.Context, tx *sql.Tx) error {
+	if err := d.dropTriggers(ctx, tx); err != nil {
+		return err
+	}
+	where := ""
+	if d.excludeArchived {
+		where = "WHERE m.archived = 0"
+	}
+	stmts := []string{
+		`DELETE FROM match_daily_stats`,
+		`INSERT INTO match_daily_stats (day, event_name, format, matches, wins, losses)
+		SELECT substr(m.sort_at, 1, 10), COALESCE(m.event_name, ''), COALESCE(m.format, ''), COUNT(*),
+			SUM(CASE WHEN m.result = 'win' THEN 1 ELSE 0 END),
+			SUM(CASE WHEN m.result = 'loss' THEN 1 ELSE 0 END)
+		FROM matches m
+		` + where + `
+		GROUP BY 1, 2, 3`,
+		d.refreshDecks(`SELECT id FROM decks`),
+	}
+	for _, trigger := range d.triggers() {
+		stmts = append(stmts, trigger.ddl)
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upDailyStats adds the daily aggregate tables Overview and the deck list
+// read instead of grouping every match per request, fills them from the
+// existing matches, and installs the triggers that maintain them.
+func upDailyStats(ctx context.Context, tx *sql.Tx) error {
+	for _, stmt := range []string{
+		`CREATE TABLE IF NOT EXISTS match_daily_stats (
+			day TEXT NOT NULL,
+			event_name TEXT NOT NULL,
+			format TEXT NOT NULL,
+			matches INTEGER NOT NULL,
+			wins INTEGER NOT NULL,
+			losses INTEGER NOT NULL,
+			PRIMARY KEY (day, event_name, format)
+		)`,
+		`CREATE TABLE IF NOT EXISTS deck_daily_stats (
+			deck_id INTEGER NOT NULL,
+			day TEXT NOT NULL,
+			matches INTEGER NOT NULL,
+			wins INTEGER NOT NULL,
+			losses INTEGER NOT NULL,
+			first_played_at TEXT,
+			PRIMARY KEY (deck_id, day)
+		)`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return dailyStats{}.install(ctx, tx)
+}
+
+func downDailyStats(ctx context.Context, tx *sql.Tx) error {
+	if err := (dailyStats{}).dropTriggers(ctx, tx); err != nil {
+		return err
+	}
+	for _, stmt := range []string{
+		`DROP TABLE IF EXISTS deck_daily_stats`,
+		`DROP TABLE IF EXISTS match_daily_stats`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upEventsRawPayloadHash adds payload_hash and a unique index on the raw
+// event content key, so overlapping logs store each request once. Existing
+// rows are hashed (decompressing payloads Prune moved to zstd) and their
+// duplicates dropped, keeping the first copy, before the index goes on.
+// Rows written without a hash keep it NULL and never collide.
+func upEventsRawPayloadHash(ctx context.Context, tx *sql.Tx) error {
+	hasColumn, err := tableHasColumnInTx(ctx, tx, "events_raw", "payload_hash")
+	if err != nil {
+		return err
+	}
+	if !hasColumn {
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE events_raw ADD COLUMN payload_hash TEXT`); err != nil {
+			return err
+		}
+	}
+
+	const batchSize = 500
+	var afterID int64
+	for {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id, payload_json, payload_zstd
+			FROM events_raw
+			WHERE id > ? AND payload_hash IS NULL
+			ORDER BY id
+			LIMIT ?
+		`, afterID, batchSize)
+		if err != nil {
+			return err
+		}
+		hashes := map[int64]string{}
+		for rows.Next() {
+			var id int64
+			var payloadJSON sql.NullString
+			var payloadZstd []byte
+			if err := rows.Scan(&id, &payloadJSON, &payloadZstd); err != nil {
+				rows.Close()
+				return err
+			}
+			raw, err := rawEventPayload(payloadJSON, payloadZstd)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			hashes[id] = rawEventPayloadHash(raw)
+			afterID = id
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return err
+		}
+		rows.Close()
+		if len(hashes) == 0 {
+			break
+		}
+		for id, hash := range hashes {
+			if _, err := tx.ExecContext(ctx, `UPDATE events_raw SET payload_hash = ? WHERE id = ?`, hash, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, stmt := range []string{
+		`DELETE FROM events_raw
+		WHERE id NOT IN (
+			SELECT MIN(id) FROM events_raw
+			GROUP BY kind, COALESCE(method_name, ''), COALESCE(request_id, ''), payload_hash
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_events_raw_content
+		ON events_raw(kind, COALESCE(method_name, ''), COALESCE(request_id, ''), payload_hash)`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downEventsRawPayloadHash(ctx context.Context, tx *sql.Tx) error {
+	for _, stmt := range []string{
+		`DROP INDEX IF EXISTS idx_events_raw_content`,
+		`ALTER TABLE events_raw DROP COLUMN payload_hash`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upParseErrors adds parse_errors, where ParseFile records lines it skipped
+// after their handlers failed.
+func upParseErrors(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS parse_errors (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			log_path TEXT NOT NULL,
+			line_no INTEGER NOT NULL,
+			byte_offset INTEGER NOT NULL,
+			error TEXT NOT NULL,
+			line_excerpt TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			UNIQUE(log_path, byte_offset)
+		)
+	`)
+	return err
+}
+
+func downParseErrors(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS parse_errors`)
+	return err
+}
+
+// upMatchArchived adds the archived flag that hides a match (a test game, a
+// bot match) from lists and stats without deleting it, and rebuilds the daily
+// stats so archived matches stay out of them.
+func upMatchArchived(ctx context.Context, tx *sql.Tx) error {
+	for _, column := range []struct{ name, ddl string }{
+		{"archived", `ALTER TABLE matches ADD COLUMN archived INTEGER NOT NULL DEFAULT 0`},
+		{"archived_at", `ALTER TABLE matches ADD COLUMN archived_at TEXT`},
+	} {
+		hasColumn, err := tableHasColumnInTx(ctx, tx, "matches", column.name)
+		if err != nil {
+			return err
+		}
+		if hasColumn {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, column.ddl); err != nil {
+			return err
+		}
+	}
+	return dailyStats{excludeArchived: true}.install(ctx, tx)
+}
+
+func downMatchArchived(ctx context.Context, tx *sql.Tx) error {
+	if err := (dailyStats{}).dropTriggers(ctx, tx); err != nil {
+		return err
+	}
+	for _, stmt := range []string{
+		`ALTER TABLE matches DROP COLUMN archived_at`,
+		`ALTER TABLE matches DROP COLUMN archived`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return dailyStats{}.install(ctx, tx)
+}
+
+// upSettings adds the key/value table behind /api/settings. Values are stored
+// as JSON text so each key keeps its type.
+func upSettings(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		)
+	`)
+	return err
+}
+
+func downSettings(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS settings`)
+	return err
+}
+
+// upImportSource records where each match and draft session came from: "log"
+// for everything parsed from Player.log, or the tracker an `import` read it
+// from.
+func upImportSource(ctx context.Context, tx *sql.Tx) error {
+	for _, table := range []string{"matches", "draft_sessions"} {
+		hasColumn, err := tableHasColumnInTx(ctx, tx, table, "source")
+		if err != nil {
+			return err
+		}
+		if hasColumn {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE `+table+` ADD COLUMN source TEXT NOT NULL DEFAULT 'log'`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downImportSource(ctx context.Context, tx *sql.Tx) error {
+	for _, stmt := range []string{
+		`ALTER TABLE draft_sessions DROP COLUMN source`,
+		`ALTER TABLE matches DROP COLUMN source`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upCardRatings adds card_ratings, the 17Lands card ratings `ratings import`
+// loads per set and draft picks are graded against.
+func upCardRatings(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS card_ratings (
+			set_code TEXT NOT NULL,
+			card_name TEXT NOT NULL,
+			arena_id INTEGER,
+			format TEXT NOT NULL,
+			games_in_hand INTEGER NOT NULL DEFAULT 0,
+			gih_wr REAL,
+			gp_wr REAL,
+			oh_wr REAL,
+			iwd REAL,
+			alsa REAL,
+			ata REAL,
+			imported_at TEXT NOT NULL,
+			PRIMARY KEY (set_code, card_name)
+		)
+	`)
+	return err
+}
+
+func downCardRatings(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS card_ratings`)
+	return err
+}
+
+// upDeckPushes adds deck_pushes, which remembers the id each deck got on a
+// deckbuilding site so `deck push` updates it rather than making a copy.
+func upDeckPushes(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS deck_pushes (
+			deck_id INTEGER NOT NULL REFERENCES decks(id) ON DELETE CASCADE,
+			service TEXT NOT NULL,
+			remote_id TEXT NOT NULL,
+			pushed_at TEXT NOT NULL,
+			PRIMARY KEY (deck_id, service)
+		)
+	`)
+	return err
+}
+
+func downDeckPushes(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS deck_pushes`)
+	return err
+}
+
+// upCardPriceSnapshots adds card_price_snapshots, the Scryfall paper prices
+// `cards sync` records once per week (keyed by the week's Monday, UTC) for
+// collection value over time and deck paper value.
+func upCardPriceSnapshots(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS card_price_snapshots (
+			week TEXT NOT NULL,
+			arena_id INTEGER NOT NULL,
+			usd REAL,
+			eur REAL,
+			updated_at TEXT NOT NULL,
+			PRIMARY KEY (week, arena_id)
+		)
+	`)
+	return err
+}
+
+func downCardPriceSnapshots(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS card_price_snapshots`)
+	return err
+}
+
+// upTurnSpellsInHand adds game_turn_stats.spells_in_hand, the nonland cards
+// held at the end of each turn, behind the mana screw and flood stats. NULL
+// when the turn has no frame or an unresolved type line leaves it ambiguous.
+// Analytics coverage is cleared so every match re-derives its turn stats and
+// fills the column from archived replays.
+func upTurnSpellsInHand(ctx context.Context, tx *sql.Tx) error {
+	// Databases from development builds may already carry the column.
+	hasColumn, err := tableHasColumnInTx(ctx, tx, "game_turn_stats", "spells_in_hand")
+	if err != nil {
+		return err
+	}
+	if !hasColumn {
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE game_turn_stats ADD COLUMN spells_in_hand INTEGER`); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM match_analytics_coverage`)
+	return err
+}
+
+func downTurnSpellsInHand(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE game_turn_stats DROP COLUMN spells_in_hand`)
+	return err
+}
+
+// opponentRankColumns hold the opponent's rank as announced when the match
+// was created; NULL when the log never carried it.
+var opponentRankColumns = []struct{ name, ddl string }{
+	{"opponent_rank_class", `ALTER TABLE matches ADD COLUMN opponent_rank_class TEXT`},
+	{"opponent_rank_level", `ALTER TABLE matches ADD COLUMN opponent_rank_level INTEGER`},
+	{"opponent_mythic_percentile", `ALTER TABLE matches ADD COLUMN opponent_mythic_percentile REAL`},
+	{"opponent_mythic_place", `ALTER TABLE matches ADD COLUMN opponent_mythic_place INTEGER`},
+}
+
+// upMatchOpponentRank adds the opponent rank columns behind the rank-weighted
+// deck win rates.
+func upMatchOpponentRank(ctx context.Context, tx *sql.Tx) error {
+	for _, column := range opponentRankColumns {
+		// Databases from development builds may already carry the column.
+		hasColumn, err := tableHasColumnInTx(ctx, tx, "matches", column.name)
+		if err != nil {
+			return err
+		}
+		if hasColumn {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, column.ddl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downMatchOpponentRank(ctx context.Context, tx *sql.Tx) error {
+	for index := len(opponentRankColumns) - 1; index >= 0; index-- {
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE matches DROP COLUMN `+opponentRankColumns[index].name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upEventsRawObservedAt adds events_raw.observed_at, the Unity log timestamp
+// in force when the event's line was read, so a synthetic log can carry the
+// original times and rawlog check can compare periods. Rows stored before
+// it keep NULL.
+func upEventsRawObservedAt(ctx context.Context, tx *sql.Tx) error {
+	// Databases from development builds may already carry the column.
+	hasColumn, err := tableHasColumnInTx(ctx, tx, "events_raw", "observed_at")
+	if err != nil || hasColumn {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `ALTER TABLE events_raw ADD COLUMN observed_at TEXT`)
+	return err
+}
+
+func downEventsRawObservedAt(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE events_raw DROP COLUMN observed_at`)
+	return err
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateToRevertsAndReappliesMigrations(t *testing.T) {
+	ctx := context.Background()
+	database, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	statuses, err := MigrationStatuses(ctx, database)
+	if err != nil {
+		t.Fatalf("MigrationStatuses: %v", err)
+	}
+	if len(statuses) != LatestMigration() {
+		t.Fatalf("statuses = %+v, want one per migration", statuses)
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == "" {
+			t.Fatalf("migration %d not applied by Init", status.Version)
+		}
+	}
+
+	hasRarity := func() bool {
+		t.Helper()
+		ok, err := tableHasColumn(ctx, database, "card_metadata", "rarity")
+		if err != nil {
+			t.Fatalf("tableHasColumn: %v", err)
+		}
+		return ok
+	}
+	if !hasRarity() {
+		t.Fatal("card_metadata.rarity missing after Init")
+	}
+
+	stepped, err := MigrateTo(ctx, database, 0)
+	if err != nil {
+		t.Fatalf("MigrateTo(0): %v", err)
+	}
+	if len(stepped) != LatestMigration() || stepped[0] != LatestMigration() {
+		t.Fatalf("reverted %v, want every migration newest first", stepped)
+	}
+	if hasRarity() {
+		t.Fatal("card_metadata.rarity still present after reverting")
+	}
+	if _, err := database.ExecContext(ctx, `SELECT * FROM card_info`); err == nil {
+		t.Fatal("card_info view still present after reverting")
+	}
+
+	// Init brings a reverted database back to the latest version.
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init after revert: %v", err)
+	}
+	if !hasRarity() {
+		t.Fatal("card_metadata.rarity missing after re-applying")
+	}
+	if _, err := MigrateTo(ctx, database, LatestMigration()+1); err == nil {
+		t.Fatal("MigrateTo past the latest migration succeeded, want error")
+	}
+}
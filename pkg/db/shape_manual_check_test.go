@@ -2,7 +2,7 @@ package db
 
 // Temporary manual harness: verifies the turn-stat backfill against a copy of
 // a real database. Run with:
-//   PONDER_SHAPE_CHECK_DB=/path/to/snapshot.db go test ./internal/db -run TestManualShapeBackfill -v
+//   PONDER_SHAPE_CHECK_DB=/path/to/snapshot.db go test ./pkg/db -run TestManualShapeBackfill -v
 
 import (
 	"context"
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// txStmtCacheSize bounds how many transactions keep cached statements.
+// Writes take the write lock with BEGIN IMMEDIATE, so only a handful are
+// ever open at once; dropping the oldest just means preparing again.
+const txStmtCacheSize = 8
+
+// txStmtCache holds statements prepared inside a transaction, so the ingest
+// hot path parses each statement once per transaction instead of once per
+// row. database/sql closes them when the transaction ends.
+type txStmtCache struct {
+	mu    sync.Mutex
+	order []*sql.Tx
+	byTx  map[*sql.Tx]map[string]*sql.Stmt
+}
+
+// txStmt returns query prepared on tx, preparing it on first use.
+func (s *Store) txStmt(ctx context.Context, tx *sql.Tx, query string) (*sql.Stmt, error) {
+	c := &s.stmts
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stmts, ok := c.byTx[tx]
+	if !ok {
+		if c.byTx == nil {
+			c.byTx = make(map[*sql.Tx]map[string]*sql.Stmt)
+		}
+		if len(c.order) >= txStmtCacheSize {
+			delete(c.byTx, c.order[0])
+			c.order = c.order[1:]
+		}
+		stmts = make(map[string]*sql.Stmt)
+		c.byTx[tx] = stmts
+		c.order = append(c.order, tx)
+	}
+	if stmt, ok := stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare statement: %w", err)
+	}
+	stmts[query] = stmt
+	return stmt, nil
+}
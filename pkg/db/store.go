@@ -0,0 +1,463 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Store reads and writes everything the tracker keeps: matches, decks,
+// drafts, economy, and the stats built from them.
+type Store struct {
+	db     *sql.DB
+	readDB *sql.DB
+	clock  Clock
+	stmts  txStmtCache
+	// rawLevel is what InsertRawEvent keeps; see SetRawEventLevel.
+	rawLevel  RawEventLevel
+	busyRetry BusyRetry
+}
+
+type IngestState struct {
+	Offset int64
+	LineNo int64
+	Found  bool
+}
+
+const sqliteInClauseBatchSize = 900
+const appMetadataPlayerNameKey = "player_name"
+
+// NewStore wraps a database set up by Open and Init. The store does not own
+// db; the caller closes it.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db, clock: SystemClock, busyRetry: DefaultBusyRetry}
+}
+
+// SetReader routes the store's reads outside a transaction to reader, a
+// pool from OpenReader; writes and transactions stay on the handle passed to
+// NewStore. nil sends reads back to that handle.
+func (s *Store) SetReader(reader *sql.DB) {
+	s.readDB = reader
+}
+
+// reader returns the pool for reads that need no transaction.
+func (s *Store) reader() *sql.DB {
+	if s.readDB != nil {
+		return s.readDB
+	}
+	return s.db
+}
+
+// SetClock replaces the clock the store stamps rows with; nil restores the
+// system clock.
+func (s *Store) SetClock(clock Clock) {
+	if clock == nil {
+		clock = SystemClock
+	}
+	s.clock = clock
+}
+
+// Clock returns the clock the store stamps rows with.
+func (s *Store) Clock() Clock {
+	return s.clock
+}
+
+func (s *Store) now() time.Time {
+	return s.clock.Now().UTC()
+}
+
+func (s *Store) nowUTC() string {
+	return s.now().Format(time.RFC3339Nano)
+}
+
+// nowUTC stamps rows written by schema migrations, which run before any Store
+// (and therefore any injected clock) exists.
+func nowUTC() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+func uniquePositiveInt64(values []int64) []int64 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	out := make([]int64, 0, len(values))
+	seen := make(map[int64]struct{}, len(values))
+	for _, value := range values {
+		if value <= 0 {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		out = append(out, value)
+	}
+	return out
+}
+
+func int64Batches(values []int64, batchSize int) [][]int64 {
+	values = uniquePositiveInt64(values)
+	if len(values) == 0 {
+		return nil
+	}
+	if batchSize <= 0 {
+		batchSize = sqliteInClauseBatchSize
+	}
+
+	out := make([][]int64, 0, (len(values)+batchSize-1)/batchSize)
+	for start := 0; start < len(values); start += batchSize {
+		end := min(start+batchSize, len(values))
+		out = append(out, values[start:end])
+	}
+	return out
+}
+
+func normalizeTS(ts string) string {
+	ts = strings.TrimSpace(ts)
+	if ts == "" {
+		return ""
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ts
+	}
+	return parsed.UTC().Format(time.RFC3339Nano)
+}
+
+// BeginTx starts a write transaction, waiting per the store's BusyRetry
+// while another connection holds the write lock.
+func (s *Store) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	var tx *sql.Tx
+	err := s.busyRetry.Do(ctx, func() error {
+		var err error
+		tx, err = s.db.BeginTx(ctx, nil)
+		return err
+	})
+	return tx, err
+}
+
+func (s *Store) GetIngestState(ctx context.Context, logPath string) (IngestState, error) {
+	state := IngestState{}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT byte_offset, line_no
+		FROM ingest_state
+		WHERE log_path = ?
+	`, logPath).Scan(&state.Offset, &state.LineNo)
+	if errors.Is(err, sql.ErrNoRows) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("get ingest_state: %w", err)
+	}
+	state.Found = true
+	return state, nil
+}
+
+func (s *Store) SaveIngestState(ctx context.Context, tx *sql.Tx, logPath string, offset, lineNo int64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO ingest_state (log_path, byte_offset, line_no, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(log_path) DO UPDATE SET
+			byte_offset = excluded.byte_offset,
+			line_no = excluded.line_no,
+			updated_at = excluded.updated_at
+	`, logPath, offset, lineNo, s.nowUTC())
+	if err != nil {
+		return fmt.Errorf("save ingest_state: %w", err)
+	}
+	return nil
+}
+
+// parseErrorExcerptBytes caps how much of a failed line parse_errors keeps;
+// GRE lines run to megabytes.
+const parseErrorExcerptBytes = 2048
+
+// RecordParseError notes a log line whose handlers failed and were rolled
+// back, so the rest of the file could still be ingested. Re-reading the same
+// line replaces its earlier entry.
+func (s *Store) RecordParseError(ctx context.Context, tx *sql.Tx, logPath string, lineNo, byteOffset int64, cause error, line []byte) error {
+	if len(line) > parseErrorExcerptBytes {
+		line = line[:parseErrorExcerptBytes]
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO parse_errors (log_path, line_no, byte_offset, error, line_excerpt, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(log_path, byte_offset) DO UPDATE SET
+			line_no = excluded.line_no,
+			error = excluded.error,
+			line_excerpt = excluded.line_excerpt,
+			created_at = excluded.created_at
+	`, logPath, lineNo, byteOffset, cause.Error(), strings.ToValidUTF8(string(line), "\uFFFD"), s.nowUTC())
+	if err != nil {
+		return fmt.Errorf("record parse error: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) SavePlayerName(ctx context.Context, tx *sql.Tx, playerName string) error {
+	playerName = strings.TrimSpace(playerName)
+	if playerName == "" {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO app_metadata (key, value, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			value = excluded.value,
+			updated_at = excluded.updated_at
+	`, appMetadataPlayerNameKey, playerName, s.nowUTC())
+	if err != nil {
+		return fmt.Errorf("save player name: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) PlayerName(ctx context.Context) (string, error) {
+	var playerName string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT value
+		FROM app_metadata
+		WHERE key = ?
+	`, appMetadataPlayerNameKey).Scan(&playerName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get player name: %w", err)
+	}
+	return strings.TrimSpace(playerName), nil
+}
+
+// rawEventPersistMethods lists the outgoing methods whose payloads
+// RepairDraftDataFromRawEvents reads back. Everything else parses inline
+// during ingest and would only occupy space, so it is never stored.
+var rawEventPersistMethods = map[string]bool{
+	"LogBusinessEvents":        true,
+	"EventPlayerDraftMakePick": true,
+	"DraftCompleteDraft":       true,
+	"EventSetDeckV2":           true,
+	"EventSetDeckV3":           true,
+}
+
+// logBusinessEventTypeDraftPick is the LogBusinessEvents EventType carrying
+// draft pick data; it is the only business event draft repair consumes.
+const logBusinessEventTypeDraftPick = 24
+
+// shouldPersistRawEvent reports whether a raw event is worth storing: only
+// outgoing draft/deck methods are ever read back, and of LogBusinessEvents
+// only the draft pick events.
+func shouldPersistRawEvent(kind, method string, payload []byte) bool {
+	if kind != "outgoing" || !rawEventPersistMethods[method] {
+		return false
+	}
+	if method != "LogBusinessEvents" {
+		return true
+	}
+	var probe struct {
+		EventType int64 `json:"EventType"`
+	}
+	if err := json.Unmarshal(payload, &probe); err != nil {
+		return false
+	}
+	return probe.EventType == logBusinessEventTypeDraftPick
+}
+
+// insertRawEventSQL skips a row whose content key (kind, method, request id,
+// payload hash) is already stored, which is how the same request read from
+// both Player-prev.log and Player.log ends up stored once. Rows go in one at
+// a time through the transaction's cached statement rather than as
+// multi-row batches: the parser counts each stored row, and a line that
+// fails must be able to roll back its rows with its savepoint.
+const insertRawEventSQL = `
+	INSERT INTO events_raw (
+		log_path, line_no, byte_offset, observed_at, kind, method_name, request_id, payload_json, payload_hash, raw_text, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT DO NOTHING
+`
+
+// rawEventPayloadHash is the hex SHA-256 of a raw event payload, the content
+// part of events_raw's dedupe key.
+func rawEventPayloadHash(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// InsertRawEvent stores a raw log event as the store's RawEventLevel allows:
+// by default only when a later repair pass can use it (see
+// shouldPersistRawEvent), and rawText only at RawEventsFull. observedAt is
+// the log timestamp the line was read under, empty when there was none.
+// Returns whether a row was written; an event already stored from another
+// log file is not written again.
+func (s *Store) InsertRawEvent(ctx context.Context, tx *sql.Tx, logPath string, lineNo, byteOffset int64, observedAt, kind, method, requestID string, payload []byte, rawText string) (bool, error) {
+	switch s.rawLevel {
+	case RawEventsNone:
+		return false, nil
+	case RawEventsMinimal:
+		if !shouldPersistRawEvent(kind, method, payload) {
+			return false, nil
+		}
+		rawText = ""
+	}
+	payloadText := ""
+	if len(payload) > 0 {
+		payloadText = string(payload)
+	}
+	stmt, err := s.txStmt(ctx, tx, insertRawEventSQL)
+	if err != nil {
+		return false, err
+	}
+	// Events with no payload are told apart by their line instead, so
+	// full storage keeps each room state rather than the first.
+	hashed := payload
+	if len(hashed) == 0 {
+		hashed = []byte(rawText)
+	}
+	res, err := stmt.ExecContext(ctx, logPath, lineNo, byteOffset, nullIfEmpty(observedAt), kind, method, requestID, payloadText, rawEventPayloadHash(hashed), rawText, s.nowUTC())
+	if err != nil {
+		return false, fmt.Errorf("insert events_raw: %w", err)
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("insert events_raw: %w", err)
+	}
+	return inserted > 0, nil
+}
+
+// PruneRawEvents deletes stored raw events that no reader consumes — rows
+// written before InsertRawEvent started filtering. Returns rows deleted.
+func (s *Store) PruneRawEvents(ctx context.Context) (int64, error) {
+	res, err := s.exec(ctx, `
+		DELETE FROM events_raw
+		WHERE NOT (
+			kind = 'outgoing'
+			AND method_name IN ('LogBusinessEvents', 'EventPlayerDraftMakePick', 'DraftCompleteDraft', 'EventSetDeckV2', 'EventSetDeckV3')
+			AND (
+				method_name != 'LogBusinessEvents'
+				OR (json_valid(payload_json) AND json_extract(payload_json, '$.EventType') = ?)
+			)
+		)
+	`, logBusinessEventTypeDraftPick)
+	if err != nil {
+		return 0, fmt.Errorf("prune events_raw: %w", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, nil
+	}
+	return deleted, nil
+}
+
+// RawEvent is one stored events_raw row with its payload decompressed.
+type RawEvent struct {
+	ID        int64
+	LogPath   string
+	LineNo    int64
+	Kind      string
+	Method    string
+	RequestID string
+	Payload   []byte
+	// RawText is the log line, kept only at RawEventsFull for events with
+	// no decoded payload.
+	RawText string
+	// ObservedAt is the log timestamp the line was read under; empty for
+	// rows stored before it was recorded.
+	ObservedAt string
+	CreatedAt  string
+}
+
+// ListRawEvents returns up to limit stored raw events with ids after
+// afterID, oldest first, for paging through the table in insert order.
+func (s *Store) ListRawEvents(ctx context.Context, afterID int64, limit int) ([]RawEvent, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT id, log_path, line_no, kind, COALESCE(method_name, ''), COALESCE(request_id, ''), payload_json, payload_zstd,
+			COALESCE(raw_text, ''), COALESCE(observed_at, ''), created_at
+		FROM events_raw
+		WHERE id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list raw events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []RawEvent
+	for rows.Next() {
+		var event RawEvent
+		var payloadJSON sql.NullString
+		var payloadZstd []byte
+		if err := rows.Scan(&event.ID, &event.LogPath, &event.LineNo, &event.Kind, &event.Method, &event.RequestID, &payloadJSON, &payloadZstd, &event.RawText, &event.ObservedAt, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan raw event: %w", err)
+		}
+		if event.Payload, err = rawEventPayload(payloadJSON, payloadZstd); err != nil {
+			return nil, fmt.Errorf("raw event %d: %w", event.ID, err)
+		}
+		out = append(out, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate raw events: %w", err)
+	}
+	return out, nil
+}
+
+func nullableInt(v int64) any {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
+
+func nullableIntPtr(v *int64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	out := v.Int64
+	return &out
+}
+
+func nullIfEmpty(v string) any {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil
+	}
+	return v
+}
+
+func nullableInt64Ptr(value int64) *int64 {
+	out := value
+	return &out
+}
+
+func parseStoredTime(raw string) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
+func boolToInt(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -7,13 +7,13 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 // GetDeckPrimer returns the cached AI primer for a deck, or (nil, nil) when
 // none has been generated yet.
 func (s *Store) GetDeckPrimer(ctx context.Context, deckID int64) (*model.DeckPrimer, error) {
-	row := s.db.QueryRowContext(ctx, `
+	row := s.reader().QueryRowContext(ctx, `
 		SELECT deck_id, cards_hash, model, content, created_at
 		FROM deck_ai_primers
 		WHERE deck_id = ?
@@ -33,8 +33,8 @@ func (s *Store) GetDeckPrimer(ctx context.Context, deckID int64) (*model.DeckPri
 // UpsertDeckPrimer stores (or replaces) the AI primer for a deck and returns
 // the stored row.
 func (s *Store) UpsertDeckPrimer(ctx context.Context, deckID int64, cardsHash, modelName, content string) (*model.DeckPrimer, error) {
-	createdAt := time.Now().UTC().Format(time.RFC3339)
-	_, err := s.db.ExecContext(ctx, `
+	createdAt := s.now().Format(time.RFC3339)
+	_, err := s.exec(ctx, `
 		INSERT INTO deck_ai_primers (deck_id, cards_hash, model, content, created_at)
 		VALUES (?, ?, ?, ?, ?)
 		ON CONFLICT(deck_id) DO UPDATE SET
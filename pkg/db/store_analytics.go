@@ -9,7 +9,7 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 type derivedOpeningCard struct {
@@ -47,6 +47,10 @@ type derivedGame struct {
 	OpeningHandConfidence string
 	MinSelfLife           *int64
 	MinOpponentLife       *int64
+	SelfSpellsCast        *int64
+	OpponentSpellsCast    *int64
+	SelfAttackers         *int64
+	OpponentAttackers     *int64
 	OpeningHands          []derivedOpeningHand
 	CardStats             map[int64]*derivedCardStat
 	TurnStats             []derivedTurnStat
@@ -80,8 +84,10 @@ type openingHandDerivation struct {
 }
 
 type cardPlayGameFact struct {
-	TurnCount *int64
-	PlayDraw  string
+	TurnCount          *int64
+	PlayDraw           string
+	SelfSpellsCast     *int64
+	OpponentSpellsCast *int64
 }
 
 type replayHandSnapshot struct {
@@ -436,6 +442,7 @@ func deriveReplayGames(frames []model.MatchReplayFrameRow) []derivedGame {
 			game.OpeningHandConfidence = "derived"
 		}
 		game.CardStats = deriveGameCardStats(gameFrames, opening)
+		game.SelfAttackers, game.OpponentAttackers = deriveAttackersDeclared(gameFrames)
 		game.frames = gameFrames
 		out = append(out, game)
 	}
@@ -443,7 +450,7 @@ func deriveReplayGames(frames []model.MatchReplayFrameRow) []derivedGame {
 }
 
 func (s *Store) loadCardPlayGameFacts(ctx context.Context, matchID int64) (map[int64]cardPlayGameFact, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			cp.game_number,
 			MAX(cp.turn_number),
@@ -461,7 +468,10 @@ func (s *Store) loadCardPlayGameFacts(ctx context.Context, matchID int64) (map[i
 				  AND first.turn_number IS NOT NULL
 				ORDER BY first.turn_number, COALESCE(first.played_at, ''), first.id
 				LIMIT 1
-			), '')
+			), ''),
+			SUM(CASE WHEN cp.first_public_zone = 'stack' AND cp.owner_seat_id = m.player_seat_id THEN 1 ELSE 0 END),
+			SUM(CASE WHEN cp.first_public_zone = 'stack' AND cp.owner_seat_id != m.player_seat_id THEN 1 ELSE 0 END),
+			MAX(m.player_seat_id IS NOT NULL)
 		FROM match_card_plays cp
 		JOIN matches m ON m.id = cp.match_id
 		WHERE cp.match_id = ?
@@ -476,13 +486,19 @@ func (s *Store) loadCardPlayGameFacts(ctx context.Context, matchID int64) (map[i
 	for rows.Next() {
 		var gameNumber int64
 		var maxTurn sql.NullInt64
+		var selfSpells, opponentSpells, seatKnown int64
 		var fact cardPlayGameFact
-		if err := rows.Scan(&gameNumber, &maxTurn, &fact.PlayDraw); err != nil {
+		if err := rows.Scan(&gameNumber, &maxTurn, &fact.PlayDraw, &selfSpells, &opponentSpells, &seatKnown); err != nil {
 			return nil, fmt.Errorf("scan game card-play facts: %w", err)
 		}
 		if maxTurn.Valid {
 			fact.TurnCount = pointerInt64(maxTurn.Int64)
 		}
+		// Without the player's seat, plays can't be split by side.
+		if seatKnown != 0 {
+			fact.SelfSpellsCast = pointerInt64(selfSpells)
+			fact.OpponentSpellsCast = pointerInt64(opponentSpells)
+		}
 		out[gameNumber] = fact
 	}
 	if err := rows.Err(); err != nil {
@@ -499,7 +515,7 @@ type cardPlayCardFact struct {
 // loadCardPlayCardFacts returns, per game and card, how many copies the player
 // cast or played (first public zone stack/battlefield) and the earliest turn.
 func (s *Store) loadCardPlayCardFacts(ctx context.Context, matchID int64) (map[int64]map[int64]cardPlayCardFact, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT cp.game_number, cp.card_id, COUNT(*), MIN(cp.turn_number)
 		FROM match_card_plays cp
 		JOIN matches m ON m.id = cp.match_id
@@ -580,6 +596,8 @@ func mergeGameFacts(games []derivedGame, facts map[int64]cardPlayGameFact) []der
 		if games[index].TurnCount == nil {
 			games[index].TurnCount = fact.TurnCount
 		}
+		games[index].SelfSpellsCast = fact.SelfSpellsCast
+		games[index].OpponentSpellsCast = fact.OpponentSpellsCast
 		if fact.PlayDraw != "" {
 			games[index].PlayDraw = fact.PlayDraw
 			games[index].PlayDrawSource = "first_observed_play"
@@ -648,7 +666,7 @@ func (s *Store) RefreshMatchAnalytics(ctx context.Context, matchID int64) error
 	}
 
 	var matchResult, matchReason string
-	if err := s.db.QueryRowContext(ctx, `
+	if err := s.reader().QueryRowContext(ctx, `
 		SELECT COALESCE(result, 'unknown'), COALESCE(win_reason, '')
 		FROM matches WHERE id = ?
 	`, matchID).Scan(&matchResult, &matchReason); err != nil {
@@ -669,7 +687,7 @@ func (s *Store) RefreshMatchAnalytics(ctx context.Context, matchID int64) error
 		return err
 	}
 	defer func() { _ = tx.Rollback() }()
-	now := nowUTC()
+	now := s.nowUTC()
 	gamesWithResult := int64(0)
 	gamesWithOpeningHand := int64(0)
 	gamesWithPlayDraw := int64(0)
@@ -684,10 +702,11 @@ func (s *Store) RefreshMatchAnalytics(ctx context.Context, matchID int64) error
 				match_id, game_number, result, win_reason, play_draw,
 				started_at, ended_at, turn_count, opening_life_total, ending_life_total,
 				mulligan_count, kept_hand_size, min_self_life, min_opponent_life,
-				result_source, result_confidence,
+				self_spells_cast, opponent_spells_cast, self_attackers_declared,
+				opponent_attackers_declared, result_source, result_confidence,
 				play_draw_source, play_draw_confidence, opening_hand_source,
 				opening_hand_confidence, derived_at
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 			ON CONFLICT(match_id, game_number) DO UPDATE SET
 				result = excluded.result,
 				win_reason = excluded.win_reason,
@@ -701,6 +720,10 @@ func (s *Store) RefreshMatchAnalytics(ctx context.Context, matchID int64) error
 				kept_hand_size = excluded.kept_hand_size,
 				min_self_life = excluded.min_self_life,
 				min_opponent_life = excluded.min_opponent_life,
+				self_spells_cast = excluded.self_spells_cast,
+				opponent_spells_cast = excluded.opponent_spells_cast,
+				self_attackers_declared = excluded.self_attackers_declared,
+				opponent_attackers_declared = excluded.opponent_attackers_declared,
 				result_source = excluded.result_source,
 				result_confidence = excluded.result_confidence,
 				play_draw_source = excluded.play_draw_source,
@@ -713,6 +736,8 @@ func (s *Store) RefreshMatchAnalytics(ctx context.Context, matchID int64) error
 			nullableDerivedInt(game.OpeningLifeTotal), nullableDerivedInt(game.EndingLifeTotal),
 			nullableDerivedInt(game.MulliganCount), nullableDerivedInt(game.KeptHandSize),
 			nullableDerivedInt(game.MinSelfLife), nullableDerivedInt(game.MinOpponentLife),
+			nullableDerivedInt(game.SelfSpellsCast), nullableDerivedInt(game.OpponentSpellsCast),
+			nullableDerivedInt(game.SelfAttackers), nullableDerivedInt(game.OpponentAttackers),
 			nullIfEmpty(game.ResultSource), game.ResultConfidence, nullIfEmpty(game.PlayDrawSource),
 			game.PlayDrawConfidence, nullIfEmpty(game.OpeningHandSource), game.OpeningHandConfidence, now)
 		if err != nil {
@@ -785,12 +810,12 @@ func (s *Store) RefreshMatchAnalytics(ctx context.Context, matchID int64) error
 				INSERT INTO game_turn_stats (
 					game_id, match_id, turn_number, is_player_turn, self_life,
 					opponent_life, self_hand_size, lands_played, spells_cast,
-					land_in_hand, source, confidence
-				) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'replay_frames_card_plays', 'derived')
+					land_in_hand, spells_in_hand, source, confidence
+				) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'replay_frames_card_plays', 'derived')
 			`, gameID, matchID, stat.TurnNumber, nullableDerivedBool(stat.IsPlayerTurn),
 				nullableDerivedInt(stat.SelfLife), nullableDerivedInt(stat.OpponentLife),
 				nullableDerivedInt(stat.SelfHandSize), stat.LandsPlayed, stat.SpellsCast,
-				nullableDerivedBool(stat.LandInHand)); err != nil {
+				nullableDerivedBool(stat.LandInHand), nullableDerivedInt(stat.SpellsInHand)); err != nil {
 				return fmt.Errorf("insert derived turn stat: %w", err)
 			}
 		}
@@ -894,7 +919,7 @@ func (s *Store) RefreshMatchAnalytics(ctx context.Context, matchID int64) error
 }
 
 func (s *Store) RefreshPendingMatchAnalytics(ctx context.Context) (int, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT m.id
 		FROM matches m
 		LEFT JOIN match_analytics_coverage c ON c.match_id = m.id
@@ -937,7 +962,7 @@ func (s *Store) RefreshPendingMatchAnalytics(ctx context.Context) (int, error) {
 
 func (s *Store) EnsureMatchAnalytics(ctx context.Context, matchID int64) error {
 	var refreshNeeded int64
-	err := s.db.QueryRowContext(ctx, `
+	err := s.reader().QueryRowContext(ctx, `
 		SELECT CASE
 			WHEN c.match_id IS NULL THEN 1
 			WHEN julianday(COALESCE(a.updated_at, '')) > julianday(c.derived_at) THEN 1
@@ -976,12 +1001,14 @@ func (s *Store) EnsureMatchAnalytics(ctx context.Context, matchID int64) error {
 }
 
 func (s *Store) ListMatchGames(ctx context.Context, matchID int64) ([]model.GameRow, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			id, game_number, result, COALESCE(win_reason, ''), COALESCE(play_draw, ''),
 			COALESCE(started_at, ''), COALESCE(ended_at, ''), turn_count,
 			opening_life_total, ending_life_total, mulligan_count, kept_hand_size,
 			min_self_life, min_opponent_life,
+			self_spells_cast, opponent_spells_cast,
+			self_attackers_declared, opponent_attackers_declared,
 			COALESCE(result_source, ''), result_confidence,
 			COALESCE(play_draw_source, ''), play_draw_confidence,
 			COALESCE(opening_hand_source, ''), opening_hand_confidence
@@ -1002,6 +1029,8 @@ func (s *Store) ListMatchGames(ctx context.Context, matchID int64) ([]model.Game
 			&game.StartedAt, &game.EndedAt, &game.TurnCount, &game.OpeningLifeTotal,
 			&game.EndingLifeTotal, &game.MulliganCount, &game.KeptHandSize,
 			&game.MinSelfLife, &game.MinOpponentLife,
+			&game.SelfSpellsCast, &game.OpponentSpellsCast,
+			&game.SelfAttackersDeclared, &game.OpponentAttackersDeclared,
 			&game.ResultSource, &game.ResultConfidence, &game.PlayDrawSource,
 			&game.PlayDrawConfidence, &game.OpeningHandSource, &game.OpeningHandConfidence,
 		); err != nil {
@@ -1017,7 +1046,7 @@ func (s *Store) ListMatchGames(ctx context.Context, matchID int64) ([]model.Game
 		// Hands are collected before their cards are fetched so no two result
 		// sets are open at once; a single-connection pool would deadlock.
 		hands := make([]model.OpeningHandRow, 0)
-		handRows, err := s.db.QueryContext(ctx, `
+		handRows, err := s.reader().QueryContext(ctx, `
 			SELECT id, attempt_number, decision, offered_hand_size, kept_hand_size,
 				COALESCE(observed_at, ''), source, confidence
 			FROM game_opening_hands
@@ -1045,7 +1074,7 @@ func (s *Store) ListMatchGames(ctx context.Context, matchID int64) ([]model.Game
 
 		for handIndex := range hands {
 			hand := &hands[handIndex]
-			cardRows, err := s.db.QueryContext(ctx, `
+			cardRows, err := s.reader().QueryContext(ctx, `
 				SELECT c.card_id, c.quantity, COALESCE(cc.name, ''), c.kept
 				FROM game_opening_hand_cards c
 				LEFT JOIN card_catalog cc ON cc.arena_id = c.card_id
@@ -1087,7 +1116,7 @@ func (s *Store) ListMatchGames(ctx context.Context, matchID int64) ([]model.Game
 func (s *Store) GetMatchAnalyticsCoverage(ctx context.Context, matchID int64) (model.MatchAnalyticsCoverage, error) {
 	var out model.MatchAnalyticsCoverage
 	var replayAvailable, deckSnapshotAvailable, deckVersionAvailable int64
-	err := s.db.QueryRowContext(ctx, `
+	err := s.reader().QueryRowContext(ctx, `
 		SELECT replay_available, replay_frame_count, game_count, games_with_result,
 			games_with_opening_hand, games_with_play_draw, games_with_turn_stats,
 			deck_snapshot_available,
@@ -6,7 +6,7 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 // derivedTurnStat is one turn's game shape from the player's perspective.
@@ -20,6 +20,7 @@ type derivedTurnStat struct {
 	LandsPlayed  int64
 	SpellsCast   int64
 	LandInHand   *bool
+	SpellsInHand *int64
 }
 
 // selfCardPlay is one of the player's own card plays with the fields needed to
@@ -46,7 +47,7 @@ func nullableDerivedBool(value *bool) any {
 // loadSelfCardPlays returns the player's own card plays with a known turn,
 // ordered by game and turn.
 func (s *Store) loadSelfCardPlays(ctx context.Context, matchID int64) ([]selfCardPlay, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT cp.game_number, cp.turn_number, cp.card_id, COALESCE(cp.first_public_zone, '')
 		FROM match_card_plays cp
 		JOIN matches m ON m.id = cp.match_id
@@ -171,9 +172,37 @@ func deriveOpponentCardCopies(frames []model.MatchReplayFrameRow) map[int64]int6
 	return maxByCard
 }
 
+// deriveAttackersDeclared counts, per side, the creatures declared as
+// attackers in one game: each (turn, instance) pair with a GRE attack state
+// counts once, however many frames of combat it spans. Both counts are nil
+// when the game has no replay frames to observe combat in.
+func deriveAttackersDeclared(frames []model.MatchReplayFrameRow) (self, opponent *int64) {
+	if len(frames) == 0 {
+		return nil, nil
+	}
+	type attack struct{ turn, instanceID int64 }
+	seen := map[string]map[attack]struct{}{
+		"self":     {},
+		"opponent": {},
+	}
+	for _, frame := range frames {
+		if frame.TurnNumber == nil {
+			continue
+		}
+		for _, object := range frame.Objects {
+			attackers, ok := seen[object.PlayerSide]
+			if !ok || object.AttackState == "" || object.InstanceID <= 0 {
+				continue
+			}
+			attackers[attack{*frame.TurnNumber, object.InstanceID}] = struct{}{}
+		}
+	}
+	return pointerInt64(int64(len(seen["self"]))), pointerInt64(int64(len(seen["opponent"])))
+}
+
 // deriveGameTurnStats reduces one game's replay frames and self card plays to
-// per-turn shape rows. Life, hand size, and land-in-hand come from the last
-// frame observed in each turn; a turn with plays but no frame still gets a row
+// per-turn shape rows. Life, hand size, land-in-hand, and spells-in-hand come
+// from the last frame observed in each turn; a turn with plays but no frame still gets a row
 // with those fields nil.
 func deriveGameTurnStats(
 	frames []model.MatchReplayFrameRow,
@@ -208,6 +237,7 @@ func deriveGameTurnStats(
 		hand := replaySelfHand(frame)
 		stat.SelfHandSize = pointerInt64(int64(len(hand.ByInstance)))
 		stat.LandInHand = landInHand(hand, landByCard)
+		stat.SpellsInHand = spellsInHand(hand, landByCard)
 	}
 
 	for _, play := range plays {
@@ -265,6 +295,22 @@ func landInHand(hand replayHandSnapshot, landByCard map[int64]bool) *bool {
 	return nil
 }
 
+// spellsInHand counts the known nonland cards in hand, or nil when an
+// unresolved type line leaves the count ambiguous.
+func spellsInHand(hand replayHandSnapshot, landByCard map[int64]bool) *int64 {
+	var spells int64
+	for _, cardID := range hand.ByInstance {
+		isLand, known := landByCard[cardID]
+		if !known {
+			return nil
+		}
+		if !isLand {
+			spells++
+		}
+	}
+	return pointerInt64(spells)
+}
+
 const flagMissedLandDrop = "missed_land_drop"
 
 // deriveGameFlags computes descriptive decision-review flags from turn stats.
@@ -302,7 +348,7 @@ func deriveGameFlags(turnStats []model.GameTurnStatRow) []model.GameFlagRow {
 // held in hand, so callers can resolve their type lines before analytics
 // classify land drops and lands in hand.
 func (s *Store) ListMatchAnalyticsCardIDs(ctx context.Context, matchID int64) ([]int64, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT s.card_id FROM game_card_stats s WHERE s.match_id = ?
 		UNION
 		SELECT cp.card_id FROM match_card_plays cp WHERE cp.match_id = ?
@@ -328,9 +374,9 @@ func (s *Store) ListMatchAnalyticsCardIDs(ctx context.Context, matchID int64) ([
 
 // ListGameTurnStats returns the stored per-turn shape rows for one game.
 func (s *Store) listGameTurnStats(ctx context.Context, gameID int64) ([]model.GameTurnStatRow, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT turn_number, is_player_turn, self_life, opponent_life,
-			self_hand_size, lands_played, spells_cast, land_in_hand
+			self_hand_size, lands_played, spells_cast, land_in_hand, spells_in_hand
 		FROM game_turn_stats
 		WHERE game_id = ?
 		ORDER BY turn_number
@@ -345,7 +391,7 @@ func (s *Store) listGameTurnStats(ctx context.Context, gameID int64) ([]model.Ga
 		var stat model.GameTurnStatRow
 		var isPlayerTurn, landInHand *int64
 		if err := rows.Scan(&stat.TurnNumber, &isPlayerTurn, &stat.SelfLife, &stat.OpponentLife,
-			&stat.SelfHandSize, &stat.LandsPlayed, &stat.SpellsCast, &landInHand); err != nil {
+			&stat.SelfHandSize, &stat.LandsPlayed, &stat.SpellsCast, &landInHand, &stat.SpellsInHand); err != nil {
 			return nil, fmt.Errorf("scan game turn stat: %w", err)
 		}
 		if isPlayerTurn != nil {
@@ -4,7 +4,7 @@ import (
 	"context"
 	"testing"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 func testShapeFrame(gameStateID, turnNumber int64, selfLife, oppLife int64, handCards map[int64]int64) model.MatchReplayFrameRow {
@@ -158,6 +158,45 @@ func TestDeriveOpponentCardCopiesUsesSimultaneousMaximum(t *testing.T) {
 	}
 }
 
+func TestDeriveAttackersDeclaredCountsEachAttackOnce(t *testing.T) {
+	t.Parallel()
+
+	attacker := func(instanceID int64, side, state string) model.MatchReplayFrameObjectRow {
+		return model.MatchReplayFrameObjectRow{
+			InstanceID:  instanceID,
+			CardID:      100 + instanceID,
+			PlayerSide:  side,
+			ZoneType:    "battlefield",
+			AttackState: state,
+		}
+	}
+	frame := func(turn int64, objects ...model.MatchReplayFrameObjectRow) model.MatchReplayFrameRow {
+		return model.MatchReplayFrameRow{TurnNumber: pointerInt64(turn), Objects: objects}
+	}
+	frames := []model.MatchReplayFrameRow{
+		// Turn 3: two self attackers, the first seen across two combat frames.
+		frame(3, attacker(1, "self", "declared"), attacker(2, "self", "attacking")),
+		frame(3, attacker(1, "self", "attacking"), attacker(5, "opponent", "")),
+		// Turn 4: the opponent swings back with one creature.
+		frame(4, attacker(5, "opponent", "attacking"), attacker(1, "self", "")),
+		// Turn 5: the same self creature attacks again and counts again.
+		frame(5, attacker(1, "self", "attacking")),
+		// Frames without a turn can't be placed and are skipped.
+		{Objects: []model.MatchReplayFrameObjectRow{attacker(9, "self", "attacking")}},
+	}
+
+	self, opponent := deriveAttackersDeclared(frames)
+	if self == nil || *self != 3 {
+		t.Fatalf("self attackers = %v, want 3", self)
+	}
+	if opponent == nil || *opponent != 1 {
+		t.Fatalf("opponent attackers = %v, want 1", opponent)
+	}
+	if self, opponent := deriveAttackersDeclared(nil); self != nil || opponent != nil {
+		t.Fatalf("no frames should leave counts unknown, got %v/%v", self, opponent)
+	}
+}
+
 func TestDeriveGameFlagsSkipsFinalTurnAndUnknownTurns(t *testing.T) {
 	t.Parallel()
 
@@ -284,6 +323,13 @@ func TestRefreshMatchAnalyticsStoresTurnStatsAndMinLife(t *testing.T) {
 		game.Flags[0].TurnNumber == nil || *game.Flags[0].TurnNumber != 3 {
 		t.Fatalf("flags = %#v, want missed_land_drop on turn 3", game.Flags)
 	}
+	if game.SelfSpellsCast == nil || *game.SelfSpellsCast != 1 ||
+		game.OpponentSpellsCast == nil || *game.OpponentSpellsCast != 0 {
+		t.Fatalf("spells cast = %v/%v, want 1 self and 0 opponent", game.SelfSpellsCast, game.OpponentSpellsCast)
+	}
+	if game.SelfAttackersDeclared == nil || *game.SelfAttackersDeclared != 0 {
+		t.Fatalf("self attackers = %v, want 0 (replay observed, no combat)", game.SelfAttackersDeclared)
+	}
 
 	coverage, err := store.GetMatchAnalyticsCoverage(ctx, matchID)
 	if err != nil {
@@ -4,7 +4,7 @@ import (
 	"context"
 	"testing"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 func testHandFrame(gameStateID int64, turnNumber *int64, stage string, cards map[int64]int64) model.MatchReplayFrameRow {
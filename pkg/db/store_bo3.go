@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// addRecordAgg adds one tally into another.
+func addRecordAgg(target *model.RecordAgg, add model.RecordAgg) {
+	target.Games += add.Games
+	target.Wins += add.Wins
+	target.Losses += add.Losses
+	target.Draws += add.Draws
+}
+
+// Bo3Records tallies best-of-three matches twice, by match result and by the
+// results of their games, with game one kept apart from the games after
+// sideboarding. Totals cover every such match and per deck (a match counts
+// toward its first linked deck; busiest deck first). Archived matches are
+// left out, and matches with no game of known result only enter the match
+// record. The win rates and intervals are left for the caller to fill in.
+func (s *Store) Bo3Records(ctx context.Context) (model.Bo3Stats, error) {
+	out := model.Bo3Stats{Decks: []model.DeckBo3Split{}}
+	rows, err := s.reader().QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			m.result,
+			COALESCE(md.deck_id, 0),
+			COALESCE(d.name, ''),
+			COALESCE(d.format, ''),
+			%s,
+			%s,
+			%s
+		FROM matches m
+		LEFT JOIN games g ON g.match_id = m.id
+		LEFT JOIN (
+			SELECT match_id, MIN(deck_id) AS deck_id
+			FROM match_decks
+			GROUP BY match_id
+		) md ON md.match_id = m.id
+		LEFT JOIN decks d ON d.id = md.deck_id
+		WHERE m.archived = 0
+		  AND m.result IN ('win', 'loss', 'draw')
+		  AND (%s) = 'bo3'
+		GROUP BY m.id
+		ORDER BY m.id
+	`, resultRecordColumns("1=1"), resultRecordColumns("g.game_number = 1"),
+		resultRecordColumns("g.game_number > 1"), matchBestOfSQL))
+	if err != nil {
+		return out, fmt.Errorf("list bo3 records: %w", err)
+	}
+	defer rows.Close()
+
+	decks := make(map[int64]*model.DeckBo3Split)
+	for rows.Next() {
+		var result string
+		var deckID int64
+		var deckName, format string
+		var games, gameOne, postBoard recordScanner
+		dests := []any{&result, &deckID, &deckName, &format}
+		dests = append(dests, games.dests()...)
+		dests = append(dests, gameOne.dests()...)
+		dests = append(dests, postBoard.dests()...)
+		if err := rows.Scan(dests...); err != nil {
+			return out, fmt.Errorf("scan bo3 record: %w", err)
+		}
+		var match model.RecordAgg
+		switch result {
+		case "win":
+			match.Wins = 1
+		case "loss":
+			match.Losses = 1
+		case "draw":
+			match.Draws = 1
+		}
+		match.Games = 1
+		if games.agg().Games == 0 {
+			out.MatchesWithoutGames++
+		}
+
+		splits := []*model.Bo3Split{&out.Overall}
+		if deckID > 0 {
+			deck, ok := decks[deckID]
+			if !ok {
+				deck = &model.DeckBo3Split{DeckID: deckID, DeckName: deckName, Format: format}
+				decks[deckID] = deck
+			}
+			splits = append(splits, &deck.Bo3Split)
+		}
+		for _, split := range splits {
+			addRecordAgg(&split.Matches.Record, match)
+			addRecordAgg(&split.Games.Record, games.agg())
+			addRecordAgg(&split.GameOne.Record, gameOne.agg())
+			addRecordAgg(&split.PostBoard.Record, postBoard.agg())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return out, fmt.Errorf("iterate bo3 records: %w", err)
+	}
+
+	for _, deck := range decks {
+		out.Decks = append(out.Decks, *deck)
+	}
+	sort.Slice(out.Decks, func(i, j int) bool {
+		a, b := out.Decks[i], out.Decks[j]
+		if a.Matches.Record.Games != b.Matches.Record.Games {
+			return a.Matches.Record.Games > b.Matches.Record.Games
+		}
+		return a.DeckID < b.DeckID
+	})
+	return out, nil
+}
@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// appMetadataCardCatalogVersionKey records the updated_at of the last bulk
+// card file imported, so a sync can skip an unchanged download.
+const appMetadataCardCatalogVersionKey = "card_catalog_bulk_version"
+
+// CatalogCard is one card from a bulk catalog import: everything the name,
+// type line, and metadata caches hold for it, plus its paper prices (nil
+// when the printing has none).
+type CatalogCard struct {
+	ArenaID  int64
+	Name     string
+	TypeLine string
+	Metadata CardMetadata
+	USD      *float64
+	EUR      *float64
+}
+
+// ImportCardCatalog fills the card name, type line, and metadata caches from
+// a bulk download in one transaction, so per-request lookups find every
+// Arena card locally, and records version as the catalog's source version.
+// Prices go into this week's snapshot, replacing any taken earlier in the
+// week.
+func (s *Store) ImportCardCatalog(ctx context.Context, cards []CatalogCard, version string) error {
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	nameStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO card_catalog (arena_id, name, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(arena_id) DO UPDATE SET
+			name = excluded.name,
+			updated_at = excluded.updated_at
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare card catalog import: %w", err)
+	}
+	defer nameStmt.Close()
+	typeStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO card_types (arena_id, type_line, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(arena_id) DO UPDATE SET
+			type_line = excluded.type_line,
+			updated_at = excluded.updated_at
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare card types import: %w", err)
+	}
+	defer typeStmt.Close()
+	metaStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO card_metadata (arena_id, color_identity, colors, mana_value, set_code, collector_number, rarity, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(arena_id) DO UPDATE SET
+			color_identity = excluded.color_identity,
+			colors = excluded.colors,
+			mana_value = excluded.mana_value,
+			set_code = excluded.set_code,
+			collector_number = excluded.collector_number,
+			rarity = excluded.rarity,
+			updated_at = excluded.updated_at
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare card metadata import: %w", err)
+	}
+	defer metaStmt.Close()
+	priceStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO card_price_snapshots (week, arena_id, usd, eur, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(week, arena_id) DO UPDATE SET
+			usd = excluded.usd,
+			eur = excluded.eur,
+			updated_at = excluded.updated_at
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare card price import: %w", err)
+	}
+	defer priceStmt.Close()
+
+	now := s.nowUTC()
+	week := priceWeek(s.now())
+	for _, card := range cards {
+		if card.ArenaID <= 0 {
+			continue
+		}
+		if name := strings.TrimSpace(card.Name); name != "" {
+			if _, err := nameStmt.ExecContext(ctx, card.ArenaID, name, now); err != nil {
+				return fmt.Errorf("import card name %d: %w", card.ArenaID, err)
+			}
+		}
+		if typeLine := strings.TrimSpace(card.TypeLine); typeLine != "" {
+			if _, err := typeStmt.ExecContext(ctx, card.ArenaID, typeLine, now); err != nil {
+				return fmt.Errorf("import card type line %d: %w", card.ArenaID, err)
+			}
+		}
+		meta := card.Metadata
+		if _, err := metaStmt.ExecContext(ctx, card.ArenaID, meta.ColorIdentity, meta.Colors, meta.ManaValue, meta.SetCode, meta.CollectorNumber, meta.Rarity, now); err != nil {
+			return fmt.Errorf("import card metadata %d: %w", card.ArenaID, err)
+		}
+		if card.USD != nil || card.EUR != nil {
+			if _, err := priceStmt.ExecContext(ctx, week, card.ArenaID, card.USD, card.EUR, now); err != nil {
+				return fmt.Errorf("import card price %d: %w", card.ArenaID, err)
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO app_metadata (key, value, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			value = excluded.value,
+			updated_at = excluded.updated_at
+	`, appMetadataCardCatalogVersionKey, version, now); err != nil {
+		return fmt.Errorf("record card catalog version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit card catalog import: %w", err)
+	}
+	return nil
+}
+
+// CardCatalogVersion returns the source version recorded by the last
+// ImportCardCatalog, or "" if no bulk catalog has been imported.
+func (s *Store) CardCatalogVersion(ctx context.Context) (string, error) {
+	var version string
+	err := s.reader().QueryRowContext(ctx, `SELECT value FROM app_metadata WHERE key = ?`, appMetadataCardCatalogVersionKey).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get card catalog version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestImportCardCatalogFillsCardInfoView(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	store := NewStore(database)
+
+	manaValue := 3.0
+	if err := store.ImportCardCatalog(ctx, []CatalogCard{{
+		ArenaID:  90001,
+		Name:     "Fable of the Mirror-Breaker",
+		TypeLine: "Enchantment — Saga",
+		Metadata: CardMetadata{ColorIdentity: "R", Colors: "R", ManaValue: &manaValue, SetCode: "neo", CollectorNumber: "141", Rarity: "rare"},
+	}}, "2026-05-01T09:00:00+00:00"); err != nil {
+		t.Fatalf("ImportCardCatalog: %v", err)
+	}
+	if version, err := store.CardCatalogVersion(ctx); err != nil || version != "2026-05-01T09:00:00+00:00" {
+		t.Fatalf("CardCatalogVersion = %q, %v", version, err)
+	}
+
+	// A later lookup from a source without rarity or collector numbers keeps them.
+	if err := store.UpsertCardMetadata(ctx, map[int64]CardMetadata{90001: {ColorIdentity: "R", Colors: "R", ManaValue: &manaValue, SetCode: "neo"}}); err != nil {
+		t.Fatalf("UpsertCardMetadata: %v", err)
+	}
+
+	var name, typeLine, rarity, collectorNumber, colors string
+	var gotManaValue float64
+	if err := database.QueryRowContext(ctx, `
+		SELECT name, type_line, rarity, collector_number, colors, mana_value
+		FROM card_info
+		WHERE arena_id = 90001
+	`).Scan(&name, &typeLine, &rarity, &collectorNumber, &colors, &gotManaValue); err != nil {
+		t.Fatalf("query card_info: %v", err)
+	}
+	if name != "Fable of the Mirror-Breaker" || typeLine != "Enchantment — Saga" || rarity != "rare" || collectorNumber != "141" || colors != "R" || gotManaValue != 3 {
+		t.Fatalf("card_info = %q %q %q %q %q %v", name, typeLine, rarity, collectorNumber, colors, gotManaValue)
+	}
+}
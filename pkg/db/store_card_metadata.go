@@ -6,11 +6,17 @@ import (
 	"strings"
 )
 
-// CardMetadata is the cached per-card classification input: color identity as
-// a WUBRG-ordered subset string ("UB") and mana value when known.
+// CardMetadata is the cached per-card classification input: color identity
+// and the card's own colors as WUBRG-ordered subset strings ("UB"), mana
+// value when known, the lowercase set code ("" for rows cached before sets
+// were tracked), collector number, and lowercase rarity ("mythic").
 type CardMetadata struct {
-	ColorIdentity string
-	ManaValue     *float64
+	ColorIdentity   string
+	Colors          string
+	ManaValue       *float64
+	SetCode         string
+	CollectorNumber string
+	Rarity          string
 }
 
 // LookupCardMetadata returns cached metadata for the given card IDs. Missing
@@ -27,8 +33,8 @@ func (s *Store) LookupCardMetadata(ctx context.Context, cardIDs []int64) (map[in
 			placeholders = append(placeholders, "?")
 			args = append(args, cardID)
 		}
-		rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
-			SELECT arena_id, color_identity, mana_value
+		rows, err := s.reader().QueryContext(ctx, fmt.Sprintf(`
+			SELECT arena_id, color_identity, colors, mana_value, set_code, collector_number, rarity
 			FROM card_metadata
 			WHERE arena_id IN (%s)
 		`, strings.Join(placeholders, ",")), args...)
@@ -38,7 +44,7 @@ func (s *Store) LookupCardMetadata(ctx context.Context, cardIDs []int64) (map[in
 		for rows.Next() {
 			var cardID int64
 			var meta CardMetadata
-			if err := rows.Scan(&cardID, &meta.ColorIdentity, &meta.ManaValue); err != nil {
+			if err := rows.Scan(&cardID, &meta.ColorIdentity, &meta.Colors, &meta.ManaValue, &meta.SetCode, &meta.CollectorNumber, &meta.Rarity); err != nil {
 				rows.Close()
 				return nil, fmt.Errorf("scan card metadata: %w", err)
 			}
@@ -53,7 +59,8 @@ func (s *Store) LookupCardMetadata(ctx context.Context, cardIDs []int64) (map[in
 	return out, nil
 }
 
-// UpsertCardMetadata caches resolved card metadata.
+// UpsertCardMetadata caches resolved card metadata. A known collector number
+// or rarity is kept when the new row doesn't carry one.
 func (s *Store) UpsertCardMetadata(ctx context.Context, metadata map[int64]CardMetadata) error {
 	if len(metadata) == 0 {
 		return nil
@@ -63,19 +70,23 @@ func (s *Store) UpsertCardMetadata(ctx context.Context, metadata map[int64]CardM
 		return err
 	}
 	defer func() { _ = tx.Rollback() }()
-	now := nowUTC()
+	now := s.nowUTC()
 	for cardID, meta := range metadata {
 		if cardID <= 0 {
 			continue
 		}
 		if _, err := tx.ExecContext(ctx, `
-			INSERT INTO card_metadata (arena_id, color_identity, mana_value, updated_at)
-			VALUES (?, ?, ?, ?)
+			INSERT INTO card_metadata (arena_id, color_identity, colors, mana_value, set_code, collector_number, rarity, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 			ON CONFLICT(arena_id) DO UPDATE SET
 				color_identity = excluded.color_identity,
+				colors = excluded.colors,
 				mana_value = excluded.mana_value,
+				set_code = excluded.set_code,
+				collector_number = CASE WHEN excluded.collector_number <> '' THEN excluded.collector_number ELSE card_metadata.collector_number END,
+				rarity = CASE WHEN excluded.rarity <> '' THEN excluded.rarity ELSE card_metadata.rarity END,
 				updated_at = excluded.updated_at
-		`, cardID, meta.ColorIdentity, meta.ManaValue, now); err != nil {
+		`, cardID, meta.ColorIdentity, meta.Colors, meta.ManaValue, meta.SetCode, meta.CollectorNumber, meta.Rarity, now); err != nil {
 			return fmt.Errorf("upsert card metadata: %w", err)
 		}
 	}
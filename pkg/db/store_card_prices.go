@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// priceWeek names the price snapshot week t falls in by its Monday (UTC),
+// as "2026-10-12", which sorts in time order.
+func priceWeek(t time.Time) string {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	monday := time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+	return monday.Format("2006-01-02")
+}
+
+// CardPrice is a card's paper price in one snapshot; nil when Scryfall had
+// none in that currency.
+type CardPrice struct {
+	USD *float64
+	EUR *float64
+}
+
+// LatestCardPrices returns each card's price from the newest snapshot that
+// has it. Cards never priced are absent from the result.
+func (s *Store) LatestCardPrices(ctx context.Context, cardIDs []int64) (map[int64]CardPrice, error) {
+	out := make(map[int64]CardPrice, len(cardIDs))
+	for _, batch := range int64Batches(cardIDs, sqliteInClauseBatchSize) {
+		placeholders := make([]string, 0, len(batch))
+		args := make([]any, 0, len(batch))
+		for _, cardID := range batch {
+			placeholders = append(placeholders, "?")
+			args = append(args, cardID)
+		}
+		rows, err := s.reader().QueryContext(ctx, fmt.Sprintf(`
+			SELECT cps.arena_id, cps.usd, cps.eur
+			FROM card_price_snapshots cps
+			WHERE cps.arena_id IN (%s)
+				AND cps.week = (SELECT MAX(latest.week) FROM card_price_snapshots latest WHERE latest.arena_id = cps.arena_id)
+		`, strings.Join(placeholders, ",")), args...)
+		if err != nil {
+			return nil, fmt.Errorf("lookup card prices: %w", err)
+		}
+		for rows.Next() {
+			var cardID int64
+			var price CardPrice
+			if err := rows.Scan(&cardID, &price.USD, &price.EUR); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan card price: %w", err)
+			}
+			out[cardID] = price
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("iterate card prices: %w", err)
+		}
+		rows.Close()
+	}
+	return out, nil
+}
+
+// CollectionValueHistory prices the collection, as the collection export
+// estimates it, at each weekly snapshot, oldest first. The card quantities
+// are today's, so the series shows how prices moved rather than when cards
+// were acquired.
+func (s *Store) CollectionValueHistory(ctx context.Context) (model.CollectionValueHistory, error) {
+	out := model.CollectionValueHistory{Points: []model.CollectionValuePoint{}}
+	quantities := map[int64]int64{}
+	if err := s.StreamCollection(ctx, func(row model.CollectionExportRow) error {
+		quantities[row.CardID] = row.Quantity
+		out.Cards += row.Quantity
+		return nil
+	}); err != nil {
+		return out, err
+	}
+	if len(quantities) == 0 {
+		return out, nil
+	}
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT week, arena_id, usd, eur
+		FROM card_price_snapshots
+		ORDER BY week ASC
+	`)
+	if err != nil {
+		return out, fmt.Errorf("list card price snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var week string
+		var cardID int64
+		var price CardPrice
+		if err := rows.Scan(&week, &cardID, &price.USD, &price.EUR); err != nil {
+			return out, fmt.Errorf("scan card price snapshot: %w", err)
+		}
+		quantity := quantities[cardID]
+		if quantity == 0 {
+			continue
+		}
+		if len(out.Points) == 0 || out.Points[len(out.Points)-1].Week != week {
+			out.Points = append(out.Points, model.CollectionValuePoint{Week: week})
+		}
+		point := &out.Points[len(out.Points)-1]
+		point.PricedCards += quantity
+		if price.USD != nil {
+			point.USD += *price.USD * float64(quantity)
+		}
+		if price.EUR != nil {
+			point.EUR += *price.EUR * float64(quantity)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return out, fmt.Errorf("iterate card price snapshots: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// CardRating is one card's 17Lands ratings for a set. Win rates are
+// fractions (0.573) and IWD is in percentage points; a nil rate is one
+// 17Lands had too few games to report.
+type CardRating struct {
+	SetCode     string
+	Name        string
+	ArenaID     int64
+	Format      string
+	GamesInHand int64
+	GIHWR       *float64
+	GPWR        *float64
+	OHWR        *float64
+	IWD         *float64
+	ALSA        *float64
+	ATA         *float64
+}
+
+// ReplaceCardRatings swaps in a set's ratings in one transaction, dropping
+// whatever an earlier import stored for it, and returns how many were
+// stored. Cards without a name are skipped.
+func (s *Store) ReplaceCardRatings(ctx context.Context, setCode, format string, ratings []CardRating) (int, error) {
+	setCode = strings.ToUpper(strings.TrimSpace(setCode))
+	if setCode == "" {
+		return 0, fmt.Errorf("replace card ratings: set code is empty")
+	}
+
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM card_ratings WHERE set_code = ?`, setCode); err != nil {
+		return 0, fmt.Errorf("clear card ratings: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO card_ratings (
+			set_code, card_name, arena_id, format, games_in_hand,
+			gih_wr, gp_wr, oh_wr, iwd, alsa, ata, imported_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(set_code, card_name) DO NOTHING
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("prepare card rating insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := s.nowUTC()
+	stored := 0
+	for _, rating := range ratings {
+		name := strings.TrimSpace(rating.Name)
+		if name == "" {
+			continue
+		}
+		var arenaID any
+		if rating.ArenaID > 0 {
+			arenaID = rating.ArenaID
+		}
+		res, err := stmt.ExecContext(ctx,
+			setCode, name, arenaID, format, rating.GamesInHand,
+			rating.GIHWR, rating.GPWR, rating.OHWR, rating.IWD, rating.ALSA, rating.ATA, now,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("insert card rating %q: %w", name, err)
+		}
+		if inserted, _ := res.RowsAffected(); inserted > 0 {
+			stored++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit card ratings: %w", err)
+	}
+	return stored, nil
+}
+
+// ListCardRatings returns the ratings stored for a set, or none when it has
+// not been imported.
+func (s *Store) ListCardRatings(ctx context.Context, setCode string) ([]CardRating, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT set_code, card_name, COALESCE(arena_id, 0), format, games_in_hand,
+			gih_wr, gp_wr, oh_wr, iwd, alsa, ata
+		FROM card_ratings
+		WHERE set_code = ?
+		ORDER BY card_name
+	`, strings.ToUpper(strings.TrimSpace(setCode)))
+	if err != nil {
+		return nil, fmt.Errorf("list card ratings: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CardRating
+	for rows.Next() {
+		var row CardRating
+		var gihWR, gpWR, ohWR, iwd, alsa, ata sql.NullFloat64
+		if err := rows.Scan(&row.SetCode, &row.Name, &row.ArenaID, &row.Format, &row.GamesInHand,
+			&gihWR, &gpWR, &ohWR, &iwd, &alsa, &ata); err != nil {
+			return nil, fmt.Errorf("scan card rating: %w", err)
+		}
+		row.GIHWR = nullableFloat(gihWR)
+		row.GPWR = nullableFloat(gpWR)
+		row.OHWR = nullableFloat(ohWR)
+		row.IWD = nullableFloat(iwd)
+		row.ALSA = nullableFloat(alsa)
+		row.ATA = nullableFloat(ata)
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate card ratings: %w", err)
+	}
+	return out, nil
+}
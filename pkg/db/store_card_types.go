@@ -27,7 +27,7 @@ func (s *Store) LookupCardTypeLines(ctx context.Context, cardIDs []int64) (map[i
 		WHERE arena_id IN (%s)
 	`, strings.Join(placeholders, ","))
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.reader().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("lookup card type lines: %w", err)
 	}
@@ -54,7 +54,7 @@ func (s *Store) UpsertCardTypeLines(ctx context.Context, typeLines map[int64]str
 		return nil
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.BeginTx(ctx)
 	if err != nil {
 		return fmt.Errorf("begin card types tx: %w", err)
 	}
@@ -72,7 +72,7 @@ func (s *Store) UpsertCardTypeLines(ctx context.Context, typeLines map[int64]str
 	}
 	defer stmt.Close()
 
-	now := nowUTC()
+	now := s.nowUTC()
 	for id, typeLine := range typeLines {
 		if strings.TrimSpace(typeLine) == "" {
 			continue
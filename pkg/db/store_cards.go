@@ -25,7 +25,7 @@ func (s *Store) LookupCardNames(ctx context.Context, cardIDs []int64) (map[int64
 		WHERE arena_id IN (%s)
 	`, strings.Join(placeholders, ","))
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.reader().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("lookup card names: %w", err)
 	}
@@ -51,7 +51,7 @@ func (s *Store) UpsertCardNames(ctx context.Context, names map[int64]string) err
 		return nil
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.BeginTx(ctx)
 	if err != nil {
 		return fmt.Errorf("begin card catalog tx: %w", err)
 	}
@@ -69,7 +69,7 @@ func (s *Store) UpsertCardNames(ctx context.Context, names map[int64]string) err
 	}
 	defer stmt.Close()
 
-	now := nowUTC()
+	now := s.nowUTC()
 	for id, name := range names {
 		if strings.TrimSpace(name) == "" {
 			continue
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// DeleteMatch removes a match. Its games, card plays, replay frames and
+// archive, analytics rows, deck links, and rank snapshot go with it through
+// foreign-key cascades. Returns false when no such match exists.
+func (s *Store) DeleteMatch(ctx context.Context, matchID int64) (bool, error) {
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin delete match: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// The next rank snapshot points at this match's snapshot; bridge the
+	// chain over it so the foreign key doesn't block the delete.
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE match_rank_snapshots
+		SET prev_snapshot_id = (
+			SELECT prev_snapshot_id FROM match_rank_snapshots WHERE match_id = ?
+		)
+		WHERE prev_snapshot_id IN (
+			SELECT id FROM match_rank_snapshots WHERE match_id = ?
+		)
+	`, matchID, matchID); err != nil {
+		return false, fmt.Errorf("relink rank snapshots: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM matches WHERE id = ?`, matchID)
+	if err != nil {
+		return false, fmt.Errorf("delete match: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete match: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit delete match: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// SetMatchArchived archives or restores a match. An archived match keeps all
+// its data but is left out of match lists (unless asked for), the overview,
+// and deck stats. Returns false when no such match exists.
+func (s *Store) SetMatchArchived(ctx context.Context, matchID int64, archived bool) (bool, error) {
+	var archivedAt any
+	if archived {
+		archivedAt = s.nowUTC()
+	}
+	result, err := s.exec(ctx, `
+		UPDATE matches
+		SET archived = ?, archived_at = ?
+		WHERE id = ?
+	`, archived, archivedAt, matchID)
+	if err != nil {
+		return false, fmt.Errorf("set match archived: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("set match archived: %w", err)
+	}
+	return updated > 0, nil
+}
+
+// DeleteDeck removes a deck with its cards, versions, and primer. Matches
+// played with it are kept but no longer linked to a deck. Returns false when
+// no such deck exists.
+func (s *Store) DeleteDeck(ctx context.Context, deckID int64) (bool, error) {
+	result, err := s.exec(ctx, `DELETE FROM decks WHERE id = ?`, deckID)
+	if err != nil {
+		return false, fmt.Errorf("delete deck: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete deck: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// MergeDecks folds each source deck into the target: source versions are
+// added to the target's history (or matched to an identical list it already
+// has), match links move over with their version, and the source decks are
+// deleted. The target keeps its own name and current list. A missing deck
+// fails the whole merge with an error wrapping sql.ErrNoRows.
+func (s *Store) MergeDecks(ctx context.Context, targetID int64, sourceIDs []int64) (model.DeckMergeResult, error) {
+	out := model.DeckMergeResult{TargetID: targetID}
+	sourceIDs = uniquePositiveInt64(sourceIDs)
+
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		return out, fmt.Errorf("begin merge decks: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, deckID := range append([]int64{targetID}, sourceIDs...) {
+		var exists int64
+		err := tx.QueryRowContext(ctx, `SELECT 1 FROM decks WHERE id = ?`, deckID).Scan(&exists)
+		if err != nil {
+			return out, fmt.Errorf("merge decks: deck %d: %w", deckID, err)
+		}
+	}
+
+	now := s.nowUTC()
+	for _, sourceID := range sourceIDs {
+		if sourceID == targetID {
+			continue
+		}
+		versionMap, err := copyDeckVersions(ctx, tx, sourceID, targetID, now)
+		if err != nil {
+			return out, err
+		}
+
+		// A match already linked to the target keeps that link.
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM match_decks
+			WHERE deck_id = ? AND match_id IN (SELECT match_id FROM match_decks WHERE deck_id = ?)
+		`, sourceID, targetID); err != nil {
+			return out, fmt.Errorf("drop duplicate match links: %w", err)
+		}
+		for sourceVersionID, targetVersionID := range versionMap {
+			result, err := tx.ExecContext(ctx, `
+				UPDATE match_decks SET deck_id = ?, deck_version_id = ?
+				WHERE deck_id = ? AND deck_version_id = ?
+			`, targetID, targetVersionID, sourceID, sourceVersionID)
+			if err != nil {
+				return out, fmt.Errorf("move match links: %w", err)
+			}
+			moved, _ := result.RowsAffected()
+			out.MovedMatches += moved
+		}
+		result, err := tx.ExecContext(ctx, `
+			UPDATE match_decks SET deck_id = ?, deck_version_id = NULL WHERE deck_id = ?
+		`, targetID, sourceID)
+		if err != nil {
+			return out, fmt.Errorf("move match links: %w", err)
+		}
+		moved, _ := result.RowsAffected()
+		out.MovedMatches += moved
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM decks WHERE id = ?`, sourceID); err != nil {
+			return out, fmt.Errorf("delete merged deck: %w", err)
+		}
+		out.MergedDecks++
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE decks SET updated_at = ? WHERE id = ?`, now, targetID); err != nil {
+		return out, fmt.Errorf("touch merged deck: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return out, fmt.Errorf("commit merge decks: %w", err)
+	}
+	return out, nil
+}
+
+// copyDeckVersions adds each of the source deck's versions to the target
+// and maps source version ids to the target's.
+func copyDeckVersions(ctx context.Context, tx *sql.Tx, sourceID, targetID int64, now string) (map[int64]int64, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT dv.id, COALESCE(dv.source, ''), COALESCE(dv.effective_at, ''), dvc.section, dvc.card_id, dvc.quantity
+		FROM deck_versions dv
+		JOIN deck_version_cards dvc ON dvc.deck_version_id = dv.id
+		WHERE dv.deck_id = ?
+		ORDER BY dv.version_number, dvc.id
+	`, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("list merged deck versions: %w", err)
+	}
+	type sourceVersion struct {
+		id          int64
+		source      string
+		effectiveAt string
+		cards       []DeckCard
+	}
+	var versions []*sourceVersion
+	for rows.Next() {
+		var versionID int64
+		var source, effectiveAt string
+		var card DeckCard
+		if err := rows.Scan(&versionID, &source, &effectiveAt, &card.Section, &card.CardID, &card.Quantity); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan merged deck version: %w", err)
+		}
+		if len(versions) == 0 || versions[len(versions)-1].id != versionID {
+			versions = append(versions, &sourceVersion{id: versionID, source: source, effectiveAt: effectiveAt})
+		}
+		current := versions[len(versions)-1]
+		current.cards = append(current.cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate merged deck versions: %w", err)
+	}
+	rows.Close()
+
+	out := make(map[int64]int64, len(versions))
+	for _, version := range versions {
+		targetVersionID, err := upsertDeckVersion(ctx, tx, targetID, version.source, version.effectiveAt, now, version.cards)
+		if err != nil {
+			return nil, err
+		}
+		if targetVersionID > 0 {
+			out[version.id] = targetVersionID
+		}
+	}
+	return out, nil
+}
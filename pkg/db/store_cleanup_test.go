@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestDeleteMatchAndMergeDecks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	store := NewStore(database)
+
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	targetID, err := store.UpsertDeck(ctx, tx, "deck-a", "Ladder", "Mono Red", "Standard", "test", "2026-05-01T10:00:00Z", []DeckCard{{Section: "main", CardID: 1, Quantity: 4}})
+	if err != nil {
+		t.Fatalf("UpsertDeck(a): %v", err)
+	}
+	sourceID, err := store.UpsertDeck(ctx, tx, "deck-b", "Ladder", "Mono Red copy", "Standard", "test", "2026-05-01T11:00:00Z", []DeckCard{{Section: "main", CardID: 2, Quantity: 4}})
+	if err != nil {
+		t.Fatalf("UpsertDeck(b): %v", err)
+	}
+	for index, match := range []struct{ arenaID, deck, start string }{
+		{"match-1", "deck-a", "2026-05-01T12:00:00Z"},
+		{"match-2", "deck-b", "2026-05-01T13:00:00Z"},
+		{"match-3", "deck-b", "2026-05-01T14:00:00Z"},
+	} {
+		if _, err := store.UpsertMatchStart(ctx, tx, match.arenaID, "Ladder", 1, match.start); err != nil {
+			t.Fatalf("UpsertMatchStart(%s): %v", match.arenaID, err)
+		}
+		if _, err := store.LinkMatchToDeckByArenaDeckID(ctx, tx, match.arenaID, match.deck, "test"); err != nil {
+			t.Fatalf("LinkMatchToDeckByArenaDeckID(%s): %v", match.arenaID, err)
+		}
+		level := int64(index + 1)
+		if err := store.UpsertMatchRankSnapshot(ctx, tx, match.arenaID, MatchRankSnapshot{ObservedAt: match.start, PayloadJSON: "{}", ConstructedLevel: &level}); err != nil {
+			t.Fatalf("UpsertMatchRankSnapshot(%s): %v", match.arenaID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	matchID := func(arenaID string) int64 {
+		var id int64
+		if err := database.QueryRowContext(ctx, `SELECT id FROM matches WHERE arena_match_id = ?`, arenaID).Scan(&id); err != nil {
+			t.Fatalf("lookup %s: %v", arenaID, err)
+		}
+		return id
+	}
+
+	// Deleting the middle match bridges the rank snapshot chain over it.
+	deleted, err := store.DeleteMatch(ctx, matchID("match-2"))
+	if err != nil || !deleted {
+		t.Fatalf("DeleteMatch = %v, %v", deleted, err)
+	}
+	var prevMatchID int64
+	if err := database.QueryRowContext(ctx, `
+		SELECT prev.match_id
+		FROM match_rank_snapshots cur
+		JOIN match_rank_snapshots prev ON prev.id = cur.prev_snapshot_id
+		WHERE cur.match_id = ?
+	`, matchID("match-3")).Scan(&prevMatchID); err != nil || prevMatchID != matchID("match-1") {
+		t.Fatalf("match-3 previous snapshot = %d, %v; want match-1's", prevMatchID, err)
+	}
+	if deleted, err := store.DeleteMatch(ctx, 999); err != nil || deleted {
+		t.Fatalf("DeleteMatch(missing) = %v, %v", deleted, err)
+	}
+
+	if _, err := store.MergeDecks(ctx, targetID, []int64{999}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("MergeDecks(missing) err = %v, want sql.ErrNoRows", err)
+	}
+	result, err := store.MergeDecks(ctx, targetID, []int64{sourceID})
+	if err != nil {
+		t.Fatalf("MergeDecks: %v", err)
+	}
+	if result.MergedDecks != 1 || result.MovedMatches != 1 {
+		t.Fatalf("merge result = %+v, want one deck and one match", result)
+	}
+
+	detail, err := store.GetDeckDetail(ctx, targetID, 10)
+	if err != nil {
+		t.Fatalf("GetDeckDetail: %v", err)
+	}
+	if len(detail.Matches) != 2 || len(detail.Versions) != 2 {
+		t.Fatalf("merged deck has %d matches and %d versions, want 2 and 2", len(detail.Matches), len(detail.Versions))
+	}
+	// match-3 keeps the list it was played with.
+	quantities, err := store.ListMatchDeckCardQuantities(ctx, []int64{matchID("match-3")})
+	if err != nil || quantities[matchID("match-3")][2] != 4 {
+		t.Fatalf("match-3 deck cards = %+v, %v; want card 2 x4", quantities, err)
+	}
+	if deleted, err := store.DeleteDeck(ctx, sourceID); err != nil || deleted {
+		t.Fatalf("DeleteDeck(merged source) = %v, %v; want already gone", deleted, err)
+	}
+
+	if deleted, err := store.DeleteDeck(ctx, targetID); err != nil || !deleted {
+		t.Fatalf("DeleteDeck = %v, %v", deleted, err)
+	}
+	var matchCount int64
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM matches`).Scan(&matchCount); err != nil || matchCount != 2 {
+		t.Fatalf("matches after deck delete = %d, %v; want 2 kept", matchCount, err)
+	}
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// collectionSightingsQuery lists every dated sighting of one of our cards:
+// a deck version containing it, a draft pick, a card we played, or a card
+// granted by an inventory change. Opponent plays are excluded, so a card
+// only shows up here once it has been in our hands.
+const collectionSightingsQuery = `
+	WITH sightings(card_id, seen_at, source) AS (
+		SELECT dvc.card_id, COALESCE(dv.effective_at, dv.created_at), 'deck'
+		FROM deck_version_cards dvc
+		JOIN deck_versions dv ON dv.id = dvc.deck_version_id
+
+		UNION ALL
+
+		SELECT CAST(picked.value AS INTEGER), COALESCE(dp.pick_ts, ds.started_at, dp.created_at), 'draft'
+		FROM draft_picks dp
+		JOIN draft_sessions ds ON ds.id = dp.draft_session_id,
+			json_each(CASE WHEN json_valid(dp.picked_card_ids) THEN dp.picked_card_ids ELSE '[]' END) picked
+
+		UNION ALL
+
+		SELECT cp.card_id, COALESCE(cp.played_at, m.started_at), 'game'
+		FROM match_card_plays cp
+		JOIN matches m ON m.id = cp.match_id
+		WHERE m.player_seat_id IS NOT NULL AND cp.owner_seat_id = m.player_seat_id
+
+		UNION ALL
+
+		SELECT CAST(json_extract(granted.value, '$.GrpId') AS INTEGER), es.observed_at, 'grant'
+		FROM economy_snapshots es,
+			json_each(CASE WHEN json_valid(es.changes_json) THEN es.changes_json ELSE '[]' END) change,
+			json_each(change.value, '$.GrantedCards') granted
+		WHERE json_type(change.value, '$.GrantedCards') = 'array'
+	)
+	SELECT s.card_id, COALESCE(cc.name, ''), s.seen_at, s.source
+	FROM sightings s
+	LEFT JOIN card_catalog cc ON cc.arena_id = s.card_id
+	WHERE s.card_id > 0 AND COALESCE(s.seen_at, '') != ''
+	ORDER BY s.card_id ASC, s.seen_at ASC
+`
+
+// CollectionInsights reports when each card we have held first and last
+// appeared in our decks, drafts, games, or inventory grants. Cards first seen
+// at or after since count as new; a zero since means the start of the
+// current UTC month.
+func (s *Store) CollectionInsights(ctx context.Context, since time.Time) (model.CollectionInsights, error) {
+	if since.IsZero() {
+		now := s.now()
+		since = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	sinceTS := since.UTC().Format(time.RFC3339Nano)
+
+	rows, err := s.reader().QueryContext(ctx, collectionSightingsQuery)
+	if err != nil {
+		return model.CollectionInsights{}, fmt.Errorf("list collection sightings: %w", err)
+	}
+	defer rows.Close()
+
+	out := model.CollectionInsights{Since: sinceTS, Cards: make([]model.CollectionCardInsight, 0)}
+	var current *model.CollectionCardInsight
+	for rows.Next() {
+		var (
+			cardID int64
+			name   string
+			seenAt sql.NullString
+			source string
+		)
+		if err := rows.Scan(&cardID, &name, &seenAt, &source); err != nil {
+			return model.CollectionInsights{}, fmt.Errorf("scan collection sighting: %w", err)
+		}
+		if current == nil || current.CardID != cardID {
+			out.Cards = append(out.Cards, model.CollectionCardInsight{
+				CardID:          cardID,
+				CardName:        name,
+				FirstSeenAt:     seenAt.String,
+				FirstSeenSource: source,
+				Sources:         []string{},
+			})
+			current = &out.Cards[len(out.Cards)-1]
+		}
+		current.LastSeenAt = seenAt.String
+		if !slices.Contains(current.Sources, source) {
+			current.Sources = append(current.Sources, source)
+		}
+		if source == "game" {
+			current.TimesPlayed++
+			current.LastPlayedAt = seenAt.String
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return model.CollectionInsights{}, fmt.Errorf("iterate collection sightings: %w", err)
+	}
+
+	for _, card := range out.Cards {
+		if card.TimesPlayed == 0 {
+			out.NeverPlayedCount++
+		}
+		if card.FirstSeenAt >= sinceTS {
+			out.NewCount++
+		}
+	}
+	out.TotalCards = int64(len(out.Cards))
+	return out, nil
+}
+
+// maxCollectionCopies is the most copies of a card Arena's collection holds;
+// extra copies turn into vault progress.
+const maxCollectionCopies = 4
+
+// collectionHeldQuery lists, per card, the copies seen together in one deck
+// version, one draft, or across inventory grants, with the card's name, set,
+// and printing. Basic lands are left out: Arena hands them out without limit.
+// Cards whose name was never resolved are left out too, since the sites the
+// export is for match cards by name.
+const collectionHeldQuery = `
+	WITH held(card_id, copies) AS (
+		SELECT dvc.card_id, SUM(dvc.quantity)
+		FROM deck_version_cards dvc
+		GROUP BY dvc.deck_version_id, dvc.card_id
+
+		UNION ALL
+
+		SELECT CAST(picked.value AS INTEGER), COUNT(*)
+		FROM draft_picks dp,
+			json_each(CASE WHEN json_valid(dp.picked_card_ids) THEN dp.picked_card_ids ELSE '[]' END) picked
+		GROUP BY dp.draft_session_id, CAST(picked.value AS INTEGER)
+
+		UNION ALL
+
+		SELECT CAST(json_extract(granted.value, '$.GrpId') AS INTEGER), COUNT(*)
+		FROM economy_snapshots es,
+			json_each(CASE WHEN json_valid(es.changes_json) THEN es.changes_json ELSE '[]' END) change,
+			json_each(change.value, '$.GrantedCards') granted
+		WHERE json_type(change.value, '$.GrantedCards') = 'array'
+		GROUP BY CAST(json_extract(granted.value, '$.GrpId') AS INTEGER)
+	)
+	SELECT
+		h.card_id,
+		cc.name,
+		COALESCE(cm.set_code, ''),
+		COALESCE(sc.name, ''),
+		COALESCE(cm.collector_number, ''),
+		COALESCE(cm.rarity, ''),
+		MAX(h.copies)
+	FROM held h
+	JOIN card_catalog cc ON cc.arena_id = h.card_id
+	LEFT JOIN card_metadata cm ON cm.arena_id = h.card_id
+	LEFT JOIN set_catalog sc ON sc.code = cm.set_code
+	WHERE h.card_id > 0
+		AND cc.name NOT IN ('Plains', 'Island', 'Swamp', 'Mountain', 'Forest', 'Wastes')
+	GROUP BY h.card_id
+	ORDER BY cc.name ASC, h.card_id ASC
+`
+
+// StreamCollection calls fn for every card we have held, by name. Each
+// printing (Arena id) is its own row.
+func (s *Store) StreamCollection(ctx context.Context, fn func(model.CollectionExportRow) error) error {
+	rows, err := s.reader().QueryContext(ctx, collectionHeldQuery)
+	if err != nil {
+		return fmt.Errorf("stream collection: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.CollectionExportRow
+		if err := rows.Scan(&row.CardID, &row.Name, &row.SetCode, &row.SetName, &row.CollectorNumber, &row.Rarity, &row.Quantity); err != nil {
+			return fmt.Errorf("scan collection row: %w", err)
+		}
+		row.Quantity = min(row.Quantity, maxCollectionCopies)
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate collection: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCollectionInsightsTracksFirstAndLastSightings(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	store := NewStore(database)
+	store.SetClock(FixedClock(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)))
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+
+	// 1001 is drafted in September, built, and played in October.
+	sessionID, err := store.EnsureDraftSession(ctx, tx, "PremierDraft_BLB_20240730", ptrString("draft-1"), false, "2026-09-20T10:00:00Z")
+	if err != nil {
+		t.Fatalf("EnsureDraftSession: %v", err)
+	}
+	if err := store.InsertDraftPick(ctx, tx, sessionID, 1, 1, []int64{1001}, nil, "2026-09-20T10:01:00Z"); err != nil {
+		t.Fatalf("InsertDraftPick: %v", err)
+	}
+	// 1002 only ever sits in a deck.
+	cards := []DeckCard{{Section: "main", CardID: 1001, Quantity: 1}, {Section: "main", CardID: 1002, Quantity: 2}}
+	if _, err := store.UpsertDeck(ctx, tx, "deck-1", "PremierDraft_BLB_20240730", "Draft Deck", "Draft", "test", "2026-09-20T11:00:00Z", cards); err != nil {
+		t.Fatalf("UpsertDeck: %v", err)
+	}
+	if _, err := store.UpsertMatchStart(ctx, tx, "match-1", "PremierDraft_BLB_20240730", 1, "2026-10-02T18:00:00Z"); err != nil {
+		t.Fatalf("UpsertMatchStart: %v", err)
+	}
+	if err := store.UpsertMatchCardPlay(ctx, tx, "match-1", 1, 101, 1001, 1, 2, "main1", "battlefield", "2026-10-02T18:05:00Z", "test"); err != nil {
+		t.Fatalf("UpsertMatchCardPlay(self): %v", err)
+	}
+	// An opponent's card is never ours.
+	if err := store.UpsertMatchCardPlay(ctx, tx, "match-1", 1, 201, 9999, 2, 2, "main1", "battlefield", "2026-10-02T18:06:00Z", "test"); err != nil {
+		t.Fatalf("UpsertMatchCardPlay(opponent): %v", err)
+	}
+	// 1003 arrives from a pack this month.
+	grant := `[{"Source":"BoosterOpen","GrantedCards":[{"GrpId":1003,"CardAdded":true}]}]`
+	if _, _, err := store.InsertEconomySnapshot(ctx, tx, "Player.log", 10, EconomySnapshotRecord{ObservedAt: "2026-10-05T09:00:00Z", ChangesJSON: grant}); err != nil {
+		t.Fatalf("InsertEconomySnapshot: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	out, err := store.CollectionInsights(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("CollectionInsights: %v", err)
+	}
+	if out.Since != "2026-10-01T00:00:00Z" {
+		t.Fatalf("Since = %q, want start of October", out.Since)
+	}
+	if out.TotalCards != 3 || out.NeverPlayedCount != 2 || out.NewCount != 1 {
+		t.Fatalf("counts = %d total / %d never played / %d new, want 3 / 2 / 1", out.TotalCards, out.NeverPlayedCount, out.NewCount)
+	}
+
+	drafted := out.Cards[0]
+	if drafted.CardID != 1001 || drafted.FirstSeenAt != "2026-09-20T10:01:00Z" || drafted.FirstSeenSource != "draft" {
+		t.Fatalf("drafted first sighting = %+v", drafted)
+	}
+	if drafted.LastSeenAt != "2026-10-02T18:05:00Z" || drafted.TimesPlayed != 1 || drafted.LastPlayedAt != "2026-10-02T18:05:00Z" {
+		t.Fatalf("drafted last sighting = %+v", drafted)
+	}
+	if len(drafted.Sources) != 3 {
+		t.Fatalf("drafted sources = %v, want draft, deck, game", drafted.Sources)
+	}
+	if deckOnly := out.Cards[1]; deckOnly.CardID != 1002 || deckOnly.FirstSeenSource != "deck" || deckOnly.TimesPlayed != 0 {
+		t.Fatalf("deck-only card = %+v", deckOnly)
+	}
+	if granted := out.Cards[2]; granted.CardID != 1003 || granted.FirstSeenSource != "grant" || granted.FirstSeenAt != "2026-10-05T09:00:00Z" {
+		t.Fatalf("granted card = %+v", granted)
+	}
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// matchRecordColumns emits win/loss/draw tallies of m.result.
+const matchRecordColumns = `
+	SUM(CASE WHEN m.result = 'win' THEN 1 ELSE 0 END),
+	SUM(CASE WHEN m.result = 'loss' THEN 1 ELSE 0 END),
+	SUM(CASE WHEN m.result = 'draw' THEN 1 ELSE 0 END)`
+
+// matchIsBrawlSQL reports whether match m was played in a Brawl event.
+const matchIsBrawlSQL = `(LOWER(COALESCE(m.event_name, '')) LIKE '%brawl%' OR LOWER(COALESCE(m.format, '')) LIKE '%brawl%')`
+
+// CompanionRecords tallies match results by companion and by Brawl
+// commander, for the player's decks and for opponents'. The player's come
+// from the companion and command sections of the deck version each match was
+// played with (or the deck itself for matches without a version), counting
+// a match toward its first linked deck. An opponent's is a card of theirs in
+// the command zone before turn two, where a companion or commander starts the
+// game; it is a commander in Brawl and a companion otherwise. Opponent
+// records are still the player's results. Archived and undecided matches are
+// left out; each list is busiest first. The win rates and intervals are left
+// for the caller to fill in.
+func (s *Store) CompanionRecords(ctx context.Context) (model.CompanionStats, error) {
+	out := model.CompanionStats{
+		Companions:         []model.CompanionRecord{},
+		Commanders:         []model.CompanionRecord{},
+		OpponentCompanions: []model.CompanionRecord{},
+		OpponentCommanders: []model.CompanionRecord{},
+	}
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT c.section, c.card_id, COALESCE(cc.name, ''),`+matchRecordColumns+`
+		FROM matches m
+		JOIN (
+			SELECT match_id, MIN(deck_id) AS deck_id
+			FROM match_decks
+			GROUP BY match_id
+		) fd ON fd.match_id = m.id
+		JOIN match_decks md ON md.match_id = fd.match_id AND md.deck_id = fd.deck_id
+		JOIN (
+			SELECT dvc.deck_version_id, NULL AS deck_id, dvc.section, dvc.card_id
+			FROM deck_version_cards dvc
+			UNION ALL
+			SELECT NULL, dc.deck_id, dc.section, dc.card_id
+			FROM deck_cards dc
+		) c ON (md.deck_version_id IS NOT NULL AND c.deck_version_id = md.deck_version_id)
+			OR (md.deck_version_id IS NULL AND c.deck_id = md.deck_id)
+		LEFT JOIN card_catalog cc ON cc.arena_id = c.card_id
+		WHERE m.archived = 0
+		  AND m.result IN ('win', 'loss', 'draw')
+		  AND c.section IN ('companion', 'command')
+		GROUP BY c.section, c.card_id
+		ORDER BY COUNT(*) DESC, c.card_id
+	`)
+	if err != nil {
+		return out, fmt.Errorf("list companion records: %w", err)
+	}
+	if err := scanCompanionRecords(rows, &out.Companions, &out.Commanders); err != nil {
+		return out, err
+	}
+
+	rows, err = s.reader().QueryContext(ctx, `
+		SELECT
+			CASE WHEN `+matchIsBrawlSQL+` THEN 'command' ELSE 'companion' END AS section,
+			oc.card_id,
+			COALESCE(cc.name, ''),`+matchRecordColumns+`
+		FROM matches m
+		JOIN (
+			SELECT DISTINCT f.match_id, o.card_id
+			FROM match_replay_frame_objects o
+			JOIN match_replay_frames f ON f.id = o.frame_id
+			JOIN matches om ON om.id = f.match_id
+			WHERE o.zone_type = 'command'
+			  AND o.is_token = 0
+			  AND o.owner_seat_id IS NOT NULL
+			  AND o.owner_seat_id != COALESCE(om.player_seat_id, 0)
+			  AND COALESCE(f.turn_number, 0) <= 1
+		) oc ON oc.match_id = m.id
+		LEFT JOIN card_catalog cc ON cc.arena_id = oc.card_id
+		WHERE m.archived = 0
+		  AND m.result IN ('win', 'loss', 'draw')
+		GROUP BY section, oc.card_id
+		ORDER BY COUNT(*) DESC, oc.card_id
+	`)
+	if err != nil {
+		return out, fmt.Errorf("list opponent companion records: %w", err)
+	}
+	if err := scanCompanionRecords(rows, &out.OpponentCompanions, &out.OpponentCommanders); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// scanCompanionRecords reads (section, card, name, record) rows into the
+// companion or commander list, closing rows.
+func scanCompanionRecords(rows *sql.Rows, companions, commanders *[]model.CompanionRecord) error {
+	defer rows.Close()
+	for rows.Next() {
+		var section string
+		var entry model.CompanionRecord
+		var record recordScanner
+		dests := append([]any{&section, &entry.CardID, &entry.CardName}, record.dests()...)
+		if err := rows.Scan(dests...); err != nil {
+			return fmt.Errorf("scan companion record: %w", err)
+		}
+		entry.Rate.Record = record.agg()
+		if section == "command" {
+			*commanders = append(*commanders, entry)
+		} else {
+			*companions = append(*companions, entry)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate companion records: %w", err)
+	}
+	return nil
+}
@@ -7,17 +7,22 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
+// activeMatchDeckSQL keeps a match_decks row (alias md) only when its match
+// is not archived; not every analytics query joins matches itself.
+const activeMatchDeckSQL = "md.match_id IN (SELECT id FROM matches WHERE archived = 0)"
+
 // deckScopeClause returns the SQL filter that limits games/matches to one
 // deck (and optionally one immutable deck version) via match_decks, plus the
-// matching args. The clause assumes match_decks is joined with alias md.
+// matching args. Archived matches are left out. The clause assumes
+// match_decks is joined with alias md.
 func deckScopeClause(deckID, deckVersionID int64) (string, []any) {
 	if deckVersionID > 0 {
-		return "md.deck_id = ? AND md.deck_version_id = ?", []any{deckID, deckVersionID}
+		return "md.deck_id = ? AND md.deck_version_id = ? AND " + activeMatchDeckSQL, []any{deckID, deckVersionID}
 	}
-	return "md.deck_id = ?", []any{deckID}
+	return "md.deck_id = ? AND " + activeMatchDeckSQL, []any{deckID}
 }
 
 // resultRecordColumns emits win/loss/draw tallies for rows matching cond.
@@ -118,24 +123,34 @@ func (s *Store) loadDeckGameShape(ctx context.Context, out *model.DeckAnalytics,
 	})
 
 	var avgWinningTurn, avgLosingTurn sql.NullFloat64
+	var avgSpells, avgOpponentSpells, avgAttackers, avgOpponentAttackers sql.NullFloat64
 	var lowestWinLife, gamesWithTurnStats sql.NullInt64
 	query := fmt.Sprintf(`
 		SELECT
 			AVG(CASE WHEN g.result = 'win' AND g.turn_count IS NOT NULL THEN CAST(g.turn_count AS REAL) END),
 			AVG(CASE WHEN g.result = 'loss' AND g.turn_count IS NOT NULL THEN CAST(g.turn_count AS REAL) END),
 			MIN(CASE WHEN g.result = 'win' THEN g.min_self_life END),
-			SUM(CASE WHEN EXISTS (SELECT 1 FROM game_turn_stats ts WHERE ts.game_id = g.id) THEN 1 ELSE 0 END)
+			SUM(CASE WHEN EXISTS (SELECT 1 FROM game_turn_stats ts WHERE ts.game_id = g.id) THEN 1 ELSE 0 END),
+			AVG(CAST(g.self_spells_cast AS REAL)),
+			AVG(CAST(g.opponent_spells_cast AS REAL)),
+			AVG(CAST(g.self_attackers_declared AS REAL)),
+			AVG(CAST(g.opponent_attackers_declared AS REAL))
 		FROM games g
 		JOIN match_decks md ON md.match_id = g.match_id
 		WHERE %s
 	`, scope)
-	if err := s.db.QueryRowContext(ctx, query, scopeArgs...).Scan(
+	if err := s.reader().QueryRowContext(ctx, query, scopeArgs...).Scan(
 		&avgWinningTurn, &avgLosingTurn, &lowestWinLife, &gamesWithTurnStats,
+		&avgSpells, &avgOpponentSpells, &avgAttackers, &avgOpponentAttackers,
 	); err != nil {
 		return fmt.Errorf("load deck game shape summary: %w", err)
 	}
 	out.Shape.AvgWinningTurn = nullableFloat(avgWinningTurn)
 	out.Shape.AvgLosingTurn = nullableFloat(avgLosingTurn)
+	out.Shape.AvgSpellsCast = nullableFloat(avgSpells)
+	out.Shape.AvgOpponentSpellsCast = nullableFloat(avgOpponentSpells)
+	out.Shape.AvgAttackers = nullableFloat(avgAttackers)
+	out.Shape.AvgOpponentAttackers = nullableFloat(avgOpponentAttackers)
 	if lowestWinLife.Valid {
 		out.Shape.LowestWinLife = pointerInt64(lowestWinLife.Int64)
 	}
@@ -165,7 +180,7 @@ func (s *Store) loadDeckMissedDropSplit(ctx context.Context, out *model.DeckAnal
 		GROUP BY g.id, g.result
 	`, missedDropTurnCond, scope)
 
-	rows, err := s.db.QueryContext(ctx, query, scopeArgs...)
+	rows, err := s.reader().QueryContext(ctx, query, scopeArgs...)
 	if err != nil {
 		return fmt.Errorf("load deck missed-drop split: %w", err)
 	}
@@ -228,7 +243,7 @@ func (s *Store) loadDeckTurnCurve(ctx context.Context, out *model.DeckAnalytics,
 		ORDER BY g.id, ts.turn_number
 	`, scope)
 
-	rows, err := s.db.QueryContext(ctx, query, scopeArgs...)
+	rows, err := s.reader().QueryContext(ctx, query, scopeArgs...)
 	if err != nil {
 		return fmt.Errorf("load deck turn curve: %w", err)
 	}
@@ -330,7 +345,7 @@ func (s *Store) loadDeckMatchRecord(ctx context.Context, out *model.DeckAnalytic
 		JOIN match_decks md ON md.match_id = m.id
 		WHERE %s
 	`, scope)
-	if err := s.db.QueryRowContext(ctx, query, scopeArgs...).Scan(
+	if err := s.reader().QueryRowContext(ctx, query, scopeArgs...).Scan(
 		&matches, &matchesWithVersion, &record.wins, &record.losses, &record.draws,
 	); err != nil {
 		return fmt.Errorf("load deck match record: %w", err)
@@ -381,7 +396,7 @@ func (s *Store) loadDeckGameRecord(ctx context.Context, out *model.DeckAnalytics
 	dests = append(dests, onDraw.dests()...)
 	dests = append(dests, &averageMulligans, &withResult, &withOpeningHand, &withPlayDraw, &withCardStats)
 
-	if err := s.db.QueryRowContext(ctx, query, scopeArgs...).Scan(dests...); err != nil {
+	if err := s.reader().QueryRowContext(ctx, query, scopeArgs...).Scan(dests...); err != nil {
 		return fmt.Errorf("load deck game record: %w", err)
 	}
 
@@ -415,7 +430,7 @@ func (s *Store) loadDeckGameBuckets(ctx context.Context, column, scope string, s
 		ORDER BY %[1]s DESC
 	`, column, resultRecordColumns("1=1"), scope)
 
-	rows, err := s.db.QueryContext(ctx, query, scopeArgs...)
+	rows, err := s.reader().QueryContext(ctx, query, scopeArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("load deck game buckets (%s): %w", column, err)
 	}
@@ -460,7 +475,7 @@ func (s *Store) loadDeckLandBuckets(ctx context.Context, out *model.DeckAnalytic
 		GROUP BY g.id, g.result
 	`, scope)
 
-	rows, err := s.db.QueryContext(ctx, query, scopeArgs...)
+	rows, err := s.reader().QueryContext(ctx, query, scopeArgs...)
 	if err != nil {
 		return fmt.Errorf("load deck land buckets: %w", err)
 	}
@@ -567,7 +582,7 @@ func (s *Store) loadDeckCardPerformance(ctx context.Context, scope string, scope
 		resultRecordColumns(involved+" AND g.play_draw = 'draw'"),
 		scope)
 
-	rows, err := s.db.QueryContext(ctx, query, scopeArgs...)
+	rows, err := s.reader().QueryContext(ctx, query, scopeArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("load deck card performance: %w", err)
 	}
@@ -628,7 +643,7 @@ func (s *Store) loadDeckCardPerformance(ctx context.Context, scope string, scope
 // opening hand for the deck, so callers can resolve their type lines before
 // computing land distributions.
 func (s *Store) ListDeckKeptHandCardIDs(ctx context.Context, deckID int64) ([]int64, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT DISTINCT s.card_id
 		FROM game_card_stats s
 		JOIN match_decks md ON md.match_id = s.match_id
@@ -785,11 +800,11 @@ func (s *Store) ListDeckAnalyticsGames(ctx context.Context, q DeckAnalyticsGames
 		JOIN match_decks md ON md.match_id = g.match_id
 		%s
 		WHERE %s
-		ORDER BY COALESCE(m.started_at, m.ended_at, m.updated_at) DESC, g.game_number ASC
+		ORDER BY m.sort_at DESC, g.game_number ASC
 		LIMIT ?
 	`, statColumns, joinStats, strings.Join(conditions, " AND "))
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.reader().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list deck analytics games: %w", err)
 	}
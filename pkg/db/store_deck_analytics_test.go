@@ -4,7 +4,7 @@ import (
 	"context"
 	"testing"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 func testHandObjects(cards map[int64]int64) []model.MatchReplayFrameObjectRow {
@@ -192,6 +192,36 @@ func TestDeckAnalyticsAggregatesCardAndHandFacts(t *testing.T) {
 	}
 }
 
+func TestDeckCardDrawImpactSplitsGamesByCardInHand(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, deckID, _ := setupDeckAnalyticsFixture(t)
+
+	out, err := store.GetDeckCardDrawImpact(ctx, deckID, 0)
+	if err != nil {
+		t.Fatalf("GetDeckCardDrawImpact: %v", err)
+	}
+	if out.Games.Games != 2 || out.Games.Wins != 1 || out.Games.Losses != 1 {
+		t.Fatalf("games = %+v, want 1-1 over 2 games", out.Games)
+	}
+	byCard := map[int64]model.DeckCardDrawImpact{}
+	for _, card := range out.Cards {
+		byCard[card.CardID] = card
+	}
+	if card := byCard[101]; card.Drawn.Record.Wins != 1 || card.Drawn.Record.Games != 1 ||
+		card.NotDrawn.Record.Losses != 1 || card.NotDrawn.Record.Games != 1 {
+		t.Fatalf("card 101 = drawn %+v not drawn %+v, want the win drawn and the loss not", card.Drawn.Record, card.NotDrawn.Record)
+	}
+	if card := byCard[301]; card.Drawn.Record.Losses != 1 || card.NotDrawn.Record.Wins != 1 {
+		t.Fatalf("card 301 = drawn %+v not drawn %+v, want the loss drawn and the win not", card.Drawn.Record, card.NotDrawn.Record)
+	}
+	// A card only seen in a mulliganed hand was never drawn.
+	if card := byCard[201]; card.Drawn.Record.Games != 0 || card.NotDrawn.Record.Games != 2 {
+		t.Fatalf("card 201 = drawn %+v not drawn %+v, want both games not drawn", card.Drawn.Record, card.NotDrawn.Record)
+	}
+}
+
 func TestDeckAnalyticsLandBucketsUseCachedTypeLines(t *testing.T) {
 	t.Parallel()
 
@@ -281,3 +311,43 @@ func TestListDeckAnalyticsGamesFacetsAndFilters(t *testing.T) {
 		t.Fatalf("facet without card id should be rejected")
 	}
 }
+
+func TestGetDeckCastTurnsCountsOwnStackCastsByRound(t *testing.T) {
+	store, deckID, _ := setupDeckAnalyticsFixture(t)
+	ctx := context.Background()
+
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	// Game turn 3 is round 2 and game turn 6 round 3. Card 500 is not in the
+	// list but was cast with the deck; the opponent's cast is ignored.
+	for _, play := range []struct{ instanceID, cardID, seat, turn int64 }{
+		{40, 101, 1, 3},
+		{41, 500, 1, 6},
+		{42, 101, 2, 4},
+	} {
+		if err := store.UpsertMatchCardPlay(ctx, tx, "match-analytics", 1, play.instanceID, play.cardID, play.seat, play.turn,
+			"main1", "stack", "2026-07-02T00:00:04Z", "test"); err != nil {
+			t.Fatalf("UpsertMatchCardPlay(%d): %v", play.instanceID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	out, err := store.GetDeckCastTurns(ctx, deckID, 0)
+	if err != nil {
+		t.Fatalf("GetDeckCastTurns: %v", err)
+	}
+	if out.Games != 1 || len(out.Cards) != 2 {
+		t.Fatalf("cast turns = %+v, want one game and two cards", out)
+	}
+	listed, extra := out.Cards[0], out.Cards[1]
+	if listed.CardID != 101 || listed.Copies != 4 || listed.Casts != 1 || len(listed.CastTurns) != 2 || listed.CastTurns[1] != 1 {
+		t.Fatalf("card 101 = %+v, want 4 copies cast once on turn 2", listed)
+	}
+	if extra.CardID != 500 || extra.Copies != 0 || extra.Casts != 1 || len(extra.CastTurns) != 3 || extra.CastTurns[2] != 1 {
+		t.Fatalf("card 500 = %+v, want no copies and a cast on turn 3", extra)
+	}
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// GetDeckCastTurns lists, for every main-deck card of one deck (or deck
+// version) and every other card the player cast with it, how often it was
+// cast on each of the player's turns. A turn here is the round of the game,
+// (game turn + 1) / 2, so it lines up with mana value whether the player
+// was on the play or the draw. Only spells the player cast from the stack
+// count. Games counts the deck's games with any recorded cast. Mana values,
+// names, and the curve comparison are left for the caller to fill in.
+func (s *Store) GetDeckCastTurns(ctx context.Context, deckID, deckVersionID int64) (model.DeckCastCurve, error) {
+	out := model.DeckCastCurve{DeckID: deckID, Curve: []model.DeckCastCurveBucket{}, Cards: []model.DeckCardCastTurns{}}
+	if deckVersionID > 0 {
+		out.DeckVersionID = pointerInt64(deckVersionID)
+	}
+
+	listQuery, listArgs := `
+		SELECT dc.card_id, COALESCE(cc.name, ''), SUM(dc.quantity)
+		FROM deck_cards dc
+		LEFT JOIN card_catalog cc ON cc.arena_id = dc.card_id
+		WHERE dc.deck_id = ? AND dc.section = 'main'
+		GROUP BY dc.card_id
+		ORDER BY dc.card_id`, []any{deckID}
+	if deckVersionID > 0 {
+		listQuery, listArgs = `
+		SELECT dvc.card_id, COALESCE(cc.name, ''), SUM(dvc.quantity)
+		FROM deck_version_cards dvc
+		LEFT JOIN card_catalog cc ON cc.arena_id = dvc.card_id
+		WHERE dvc.deck_version_id = ? AND dvc.section = 'main'
+		GROUP BY dvc.card_id
+		ORDER BY dvc.card_id`, []any{deckVersionID}
+	}
+	rows, err := s.reader().QueryContext(ctx, listQuery, listArgs...)
+	if err != nil {
+		return out, fmt.Errorf("load deck cast turn cards: %w", err)
+	}
+	defer rows.Close()
+	index := make(map[int64]int)
+	for rows.Next() {
+		var card model.DeckCardCastTurns
+		if err := rows.Scan(&card.CardID, &card.CardName, &card.Copies); err != nil {
+			return out, fmt.Errorf("scan deck cast turn card: %w", err)
+		}
+		card.CastTurns = []int64{}
+		index[card.CardID] = len(out.Cards)
+		out.Cards = append(out.Cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		return out, fmt.Errorf("iterate deck cast turn cards: %w", err)
+	}
+
+	scope, scopeArgs := deckScopeClause(deckID, deckVersionID)
+	casts := fmt.Sprintf(`
+		FROM match_card_plays cp
+		JOIN matches m ON m.id = cp.match_id
+		WHERE cp.first_public_zone = 'stack'
+		  AND cp.owner_seat_id IS NOT NULL
+		  AND cp.owner_seat_id = m.player_seat_id
+		  AND cp.turn_number > 0
+		  AND EXISTS (SELECT 1 FROM match_decks md WHERE md.match_id = cp.match_id AND %s)`, scope)
+	if err := s.reader().QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (SELECT DISTINCT cp.match_id, cp.game_number `+casts+`)
+	`, scopeArgs...).Scan(&out.Games); err != nil {
+		return out, fmt.Errorf("count deck cast turn games: %w", err)
+	}
+
+	rows, err = s.reader().QueryContext(ctx, `
+		SELECT cp.card_id, (cp.turn_number + 1) / 2 AS turn, COUNT(*)
+		`+casts+`
+		GROUP BY cp.card_id, turn
+		ORDER BY cp.card_id, turn
+	`, scopeArgs...)
+	if err != nil {
+		return out, fmt.Errorf("load deck cast turns: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cardID, turn, count int64
+		if err := rows.Scan(&cardID, &turn, &count); err != nil {
+			return out, fmt.Errorf("scan deck cast turn: %w", err)
+		}
+		position, ok := index[cardID]
+		if !ok {
+			position = len(out.Cards)
+			index[cardID] = position
+			out.Cards = append(out.Cards, model.DeckCardCastTurns{CardID: cardID, CastTurns: []int64{}})
+		}
+		card := &out.Cards[position]
+		for int64(len(card.CastTurns)) < turn {
+			card.CastTurns = append(card.CastTurns, 0)
+		}
+		card.CastTurns[turn-1] += count
+		card.Casts += count
+	}
+	if err := rows.Err(); err != nil {
+		return out, fmt.Errorf("iterate deck cast turns: %w", err)
+	}
+	return out, nil
+}
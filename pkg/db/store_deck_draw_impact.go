@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// GetDeckCardDrawImpact tallies, for every card seen in one deck's games,
+// the game record when the card was in hand (kept in the opening hand or
+// drawn) and when it was not. Only decided games with derived card facts
+// count, since the rest cannot say whether a card was drawn. deckVersionID
+// optionally restricts to one immutable deck version.
+func (s *Store) GetDeckCardDrawImpact(ctx context.Context, deckID, deckVersionID int64) (model.DeckCardDrawStats, error) {
+	out := model.DeckCardDrawStats{DeckID: deckID, Cards: []model.DeckCardDrawImpact{}}
+	if deckVersionID > 0 {
+		out.DeckVersionID = pointerInt64(deckVersionID)
+	}
+	scope, scopeArgs := deckScopeClause(deckID, deckVersionID)
+	// EXISTS rather than a join so a match linked to the deck more than once
+	// still counts each game once.
+	games := fmt.Sprintf(`g.result IN ('win', 'loss', 'draw')
+		AND EXISTS (SELECT 1 FROM match_decks md WHERE md.match_id = g.match_id AND %s)`, scope)
+
+	var total recordScanner
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM games g
+		WHERE %s
+		  AND EXISTS (SELECT 1 FROM game_card_stats s WHERE s.game_id = g.id)
+	`, resultRecordColumns("1=1"), games)
+	if err := s.reader().QueryRowContext(ctx, query, scopeArgs...).Scan(total.dests()...); err != nil {
+		return out, fmt.Errorf("load deck draw impact games: %w", err)
+	}
+	out.Games = total.agg()
+
+	query = fmt.Sprintf(`
+		SELECT
+			s.card_id,
+			COALESCE(cc.name, ''),
+			%s
+		FROM game_card_stats s
+		JOIN games g ON g.id = s.game_id
+		LEFT JOIN card_catalog cc ON cc.arena_id = s.card_id
+		WHERE %s
+		GROUP BY s.card_id
+		ORDER BY s.card_id ASC
+	`, resultRecordColumns("(s.opening_kept_copies > 0 OR s.drawn_copies > 0)"), games)
+	rows, err := s.reader().QueryContext(ctx, query, scopeArgs...)
+	if err != nil {
+		return out, fmt.Errorf("load deck draw impact: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var card model.DeckCardDrawImpact
+		var drawn recordScanner
+		dests := append([]any{&card.CardID, &card.CardName}, drawn.dests()...)
+		if err := rows.Scan(dests...); err != nil {
+			return out, fmt.Errorf("scan deck draw impact: %w", err)
+		}
+		card.Drawn.Record = drawn.agg()
+		card.NotDrawn.Record = model.RecordAgg{
+			Games:  out.Games.Games - card.Drawn.Record.Games,
+			Wins:   out.Games.Wins - card.Drawn.Record.Wins,
+			Losses: out.Games.Losses - card.Drawn.Record.Losses,
+			Draws:  out.Games.Draws - card.Drawn.Record.Draws,
+		}
+		out.Cards = append(out.Cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		return out, fmt.Errorf("iterate deck draw impact: %w", err)
+	}
+	return out, nil
+}
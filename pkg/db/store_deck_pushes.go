@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// DeckPushID returns the id a deck was given on service by its last push,
+// or "" when it has not been pushed there.
+func (s *Store) DeckPushID(ctx context.Context, deckID int64, service string) (string, error) {
+	var remoteID string
+	err := s.reader().QueryRowContext(ctx, `
+		SELECT remote_id FROM deck_pushes WHERE deck_id = ? AND service = ?
+	`, deckID, service).Scan(&remoteID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get deck push: %w", err)
+	}
+	return remoteID, nil
+}
+
+// RecordDeckPush remembers the id a deck has on service and returns the
+// time of the push.
+func (s *Store) RecordDeckPush(ctx context.Context, deckID int64, service, remoteID string) (string, error) {
+	now := s.nowUTC()
+	if _, err := s.exec(ctx, `
+		INSERT INTO deck_pushes (deck_id, service, remote_id, pushed_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(deck_id, service) DO UPDATE SET
+			remote_id = excluded.remote_id,
+			pushed_at = excluded.pushed_at
+	`, deckID, service, remoteID, now); err != nil {
+		return "", fmt.Errorf("record deck push: %w", err)
+	}
+	return now, nil
+}
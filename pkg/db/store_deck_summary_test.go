@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestListDecksByScopeIncludesDeckActivityTimestamps(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	store := NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+
+	lastUpdated := "2026-04-04T00:49:51.310561Z"
+	if _, err := store.UpsertDeck(
+		ctx,
+		tx,
+		"draft-deck-1",
+		"PremierDraft_TMT_20260303",
+		"Draft Deck",
+		"Draft",
+		"test",
+		lastUpdated,
+		nil,
+	); err != nil {
+		t.Fatalf("UpsertDeck: %v", err)
+	}
+
+	firstPlayedAt := "2026-04-04T00:50:21.247Z"
+	if _, err := store.UpsertMatchStart(ctx, tx, "match-1", "PremierDraft_TMT_20260303", 1, firstPlayedAt); err != nil {
+		t.Fatalf("UpsertMatchStart: %v", err)
+	}
+	if err := store.LinkMatchToLatestDeckByEvent(ctx, tx, "match-1", "PremierDraft_TMT_20260303", "test"); err != nil {
+		t.Fatalf("LinkMatchToLatestDeckByEvent: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	rows, err := store.ListDecksByScope(ctx, "draft")
+	if err != nil {
+		t.Fatalf("ListDecksByScope: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("len(ListDecksByScope) = %d, want 1", len(rows))
+	}
+
+	row := rows[0]
+	if row.FirstPlayedAt != firstPlayedAt {
+		t.Fatalf("FirstPlayedAt = %q, want %q", row.FirstPlayedAt, firstPlayedAt)
+	}
+	if row.LastUpdatedAt != lastUpdated {
+		t.Fatalf("LastUpdatedAt = %q, want %q", row.LastUpdatedAt, lastUpdated)
+	}
+}
+
+func TestDailyStatsFollowMatchAndDeckChanges(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	store := NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	deckID, err := store.UpsertDeck(ctx, tx, "deck-1", "Ladder", "Mono Red", "Standard", "test", "2026-04-01T00:00:00Z", nil)
+	if err != nil {
+		t.Fatalf("UpsertDeck: %v", err)
+	}
+	for _, match := range []struct {
+		id, startedAt string
+		winningTeam   int64
+		linkDeck      bool
+	}{
+		{"match-1", "2026-04-01T10:00:00Z", 1, true},
+		{"match-2", "2026-04-02T10:00:00Z", 2, true},
+		{"match-3", "2026-04-02T11:00:00Z", 1, false},
+	} {
+		if _, err := store.UpsertMatchStart(ctx, tx, match.id, "Ladder", 1, match.startedAt); err != nil {
+			t.Fatalf("UpsertMatchStart(%s): %v", match.id, err)
+		}
+		if match.linkDeck {
+			if _, err := store.LinkMatchToDeckByArenaDeckID(ctx, tx, match.id, "deck-1", "event_deck"); err != nil {
+				t.Fatalf("LinkMatchToDeckByArenaDeckID(%s): %v", match.id, err)
+			}
+		}
+		if _, _, _, err := store.UpdateMatchEnd(ctx, tx, match.id, 1, match.winningTeam, 8, 600, "Game", match.startedAt); err != nil {
+			t.Fatalf("UpdateMatchEnd(%s): %v", match.id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	assertStats := func(wantTotal, wantWins, wantLosses, wantDeckMatches, wantDeckWins int64) {
+		t.Helper()
+		overview, err := store.Overview(ctx, 1, OverviewScope{})
+		if err != nil {
+			t.Fatalf("Overview: %v", err)
+		}
+		if overview.TotalMatches != wantTotal || overview.Wins != wantWins || overview.Losses != wantLosses {
+			t.Fatalf("overview = %d/%d/%d, want %d/%d/%d",
+				overview.TotalMatches, overview.Wins, overview.Losses, wantTotal, wantWins, wantLosses)
+		}
+		decks, err := store.ListDecksByScope(ctx, "constructed")
+		if err != nil {
+			t.Fatalf("ListDecksByScope: %v", err)
+		}
+		var deckMatches, deckWins int64
+		for _, deck := range decks {
+			if deck.DeckID == deckID {
+				deckMatches, deckWins = deck.Matches, deck.Wins
+			}
+		}
+		if deckMatches != wantDeckMatches || deckWins != wantDeckWins {
+			t.Fatalf("deck stats = %d matches/%d wins, want %d/%d", deckMatches, deckWins, wantDeckMatches, wantDeckWins)
+		}
+	}
+	assertStats(3, 2, 1, 2, 1)
+
+	var days int64
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM deck_daily_stats WHERE deck_id = ?`, deckID).Scan(&days); err != nil {
+		t.Fatalf("count deck days: %v", err)
+	}
+	if days != 2 {
+		t.Fatalf("deck_daily_stats days = %d, want 2", days)
+	}
+
+	var matchID int64
+	if err := database.QueryRowContext(ctx, `SELECT id FROM matches WHERE arena_match_id = 'match-1'`).Scan(&matchID); err != nil {
+		t.Fatalf("lookup match-1: %v", err)
+	}
+
+	// Archiving hides a match from the stats and the default match list;
+	// restoring it brings both back.
+	if found, err := store.SetMatchArchived(ctx, matchID, true); err != nil || !found {
+		t.Fatalf("SetMatchArchived(true) = %v, %v", found, err)
+	}
+	assertStats(2, 1, 1, 1, 0)
+	for _, includeArchived := range []bool{false, true} {
+		rows, err := store.ListMatches(ctx, 10, "", "", includeArchived)
+		if err != nil {
+			t.Fatalf("ListMatches(includeArchived=%v): %v", includeArchived, err)
+		}
+		var listed bool
+		for _, row := range rows {
+			if row.ID == matchID {
+				listed = true
+				if !row.Archived {
+					t.Fatalf("archived match listed with Archived = false")
+				}
+			}
+		}
+		if listed != includeArchived {
+			t.Fatalf("ListMatches(includeArchived=%v) listed archived match = %v", includeArchived, listed)
+		}
+	}
+	if found, err := store.SetMatchArchived(ctx, matchID, false); err != nil || !found {
+		t.Fatalf("SetMatchArchived(false) = %v, %v", found, err)
+	}
+	assertStats(3, 2, 1, 2, 1)
+
+	if _, err := store.DeleteMatch(ctx, matchID); err != nil {
+		t.Fatalf("DeleteMatch: %v", err)
+	}
+	assertStats(2, 1, 1, 1, 0)
+
+	if _, err := store.DeleteDeck(ctx, deckID); err != nil {
+		t.Fatalf("DeleteDeck: %v", err)
+	}
+	assertStats(2, 1, 1, 0, 0)
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM deck_daily_stats`).Scan(&days); err != nil {
+		t.Fatalf("count deck days: %v", err)
+	}
+	if days != 0 {
+		t.Fatalf("deck_daily_stats rows after DeleteDeck = %d, want 0", days)
+	}
+}
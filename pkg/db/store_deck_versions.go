@@ -61,7 +61,7 @@ func upsertDeckVersion(
 	ctx context.Context,
 	tx *sql.Tx,
 	deckID int64,
-	source, effectiveAt string,
+	source, effectiveAt, createdAt string,
 	cards []DeckCard,
 ) (int64, error) {
 	normalized := normalizedDeckCards(cards)
@@ -70,7 +70,6 @@ func upsertDeckVersion(
 	}
 
 	cardsHash := deckCardsHash(normalized)
-	createdAt := nowUTC()
 	effectiveAt = normalizeTS(effectiveAt)
 	if effectiveAt == "" {
 		effectiveAt = createdAt
@@ -6,8 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 type DeckCard struct {
@@ -55,11 +56,26 @@ func deckInScope(scope, format, eventName string) bool {
 	}
 }
 
+// UpsertDeck stores Arena's current decklist for arenaDeckID and records it
+// as a deck version. A list older than the stored one (by Arena's
+// last_updated), as an archived log parsed after a newer one sends, is kept
+// as a version only: the deck's name, format, and cards stay as they are.
 func (s *Store) UpsertDeck(ctx context.Context, tx *sql.Tx, arenaDeckID, eventName, name, format, source, lastUpdated string, cards []DeckCard) (int64, error) {
-	now := nowUTC()
+	now := s.nowUTC()
 	lastUpdated = normalizeTS(lastUpdated)
 
-	_, err := tx.ExecContext(ctx, `
+	deckID, stale, err := staleDeckUpdate(ctx, tx, arenaDeckID, lastUpdated)
+	if err != nil {
+		return 0, err
+	}
+	if stale {
+		if _, err := upsertDeckVersion(ctx, tx, deckID, source, lastUpdated, now, cards); err != nil {
+			return 0, err
+		}
+		return deckID, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO decks (
 			arena_deck_id, event_name, name, format, source, last_updated, created_at, updated_at
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
@@ -75,13 +91,12 @@ func (s *Store) UpsertDeck(ctx context.Context, tx *sql.Tx, arenaDeckID, eventNa
 		return 0, fmt.Errorf("upsert deck: %w", err)
 	}
 
-	var deckID int64
 	err = tx.QueryRowContext(ctx, `SELECT id FROM decks WHERE arena_deck_id = ?`, arenaDeckID).Scan(&deckID)
 	if err != nil {
 		return 0, fmt.Errorf("fetch deck id: %w", err)
 	}
 
-	versionID, err := upsertDeckVersion(ctx, tx, deckID, source, lastUpdated, cards)
+	versionID, err := upsertDeckVersion(ctx, tx, deckID, source, lastUpdated, now, cards)
 	if err != nil {
 		return 0, err
 	}
@@ -118,6 +133,32 @@ func (s *Store) UpsertDeck(ctx context.Context, tx *sql.Tx, arenaDeckID, eventNa
 	return deckID, nil
 }
 
+// staleDeckUpdate reports whether a decklist last updated at lastUpdated is
+// older than the one stored for arenaDeckID, with the stored deck's id. Lists
+// without a parseable timestamp on either side are never stale.
+func staleDeckUpdate(ctx context.Context, tx *sql.Tx, arenaDeckID, lastUpdated string) (int64, bool, error) {
+	incoming, err := time.Parse(time.RFC3339Nano, lastUpdated)
+	if err != nil {
+		return 0, false, nil
+	}
+	var deckID int64
+	var stored sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, last_updated FROM decks WHERE arena_deck_id = ?
+	`, arenaDeckID).Scan(&deckID, &stored)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read stored deck update: %w", err)
+	}
+	storedAt, err := time.Parse(time.RFC3339Nano, stored.String)
+	if err != nil {
+		return 0, false, nil
+	}
+	return deckID, incoming.Before(storedAt), nil
+}
+
 // linkReasonRank orders match-deck link sources by confidence: exact deck IDs
 // reported by Arena beat room-state event-name guesses, which beat pre-match
 // guesses and everything else.
@@ -192,7 +233,7 @@ func (s *Store) writeMatchDeckLink(ctx context.Context, tx *sql.Tx, matchID, dec
 		INSERT INTO match_decks (match_id, deck_id, deck_version_id, snapshot_reason, created_at)
 		VALUES (?, ?, ?, ?, ?)
 		ON CONFLICT(match_id, deck_id) DO NOTHING
-	`, matchID, deckID, versionID, reason, nowUTC())
+	`, matchID, deckID, versionID, reason, s.nowUTC())
 	if err != nil {
 		return fmt.Errorf("link match_deck: %w", err)
 	}
@@ -320,21 +361,28 @@ func (s *Store) ListDecks(ctx context.Context) ([]model.DeckSummaryRow, error) {
 func (s *Store) ListDecksByScope(ctx context.Context, scope string) ([]model.DeckSummaryRow, error) {
 	scope = normalizeDeckScope(scope)
 
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			d.id,
 			COALESCE(d.name, d.arena_deck_id) AS deck_name,
 			COALESCE(d.format, ''),
 			COALESCE(d.event_name, ''),
-			COUNT(m.id) AS matches,
-			SUM(CASE WHEN m.result = 'win' THEN 1 ELSE 0 END) AS wins,
-			SUM(CASE WHEN m.result = 'loss' THEN 1 ELSE 0 END) AS losses,
-			COALESCE(MIN(COALESCE(m.started_at, m.ended_at)), '') AS first_played_at,
+			COALESCE(s.matches, 0) AS matches,
+			COALESCE(s.wins, 0) AS wins,
+			COALESCE(s.losses, 0) AS losses,
+			COALESCE(s.first_played_at, '') AS first_played_at,
 			COALESCE(d.last_updated, d.created_at, '') AS last_updated_at
 		FROM decks d
-		LEFT JOIN match_decks md ON md.deck_id = d.id
-		LEFT JOIN matches m ON m.id = md.match_id
-		GROUP BY d.id, d.name, d.arena_deck_id, d.format, d.event_name, d.last_updated, d.created_at
+		LEFT JOIN (
+			SELECT
+				deck_id,
+				SUM(matches) AS matches,
+				SUM(wins) AS wins,
+				SUM(losses) AS losses,
+				MIN(first_played_at) AS first_played_at
+			FROM deck_daily_stats
+			GROUP BY deck_id
+		) s ON s.deck_id = d.id
 		ORDER BY matches DESC, deck_name ASC
 	`)
 	if err != nil {
@@ -378,7 +426,7 @@ func (s *Store) GetDeckDetail(ctx context.Context, deckID int64, matchLimit int6
 		matchLimit = 50
 	}
 
-	err := s.db.QueryRowContext(ctx, `
+	err := s.reader().QueryRowContext(ctx, `
 		SELECT id, arena_deck_id, COALESCE(name, ''), COALESCE(format, ''), COALESCE(event_name, '')
 		FROM decks
 		WHERE id = ?
@@ -396,7 +444,7 @@ func (s *Store) GetDeckDetail(ctx context.Context, deckID int64, matchLimit int6
 		return out, err
 	}
 
-	matchRows, err := s.db.QueryContext(ctx, `
+	matchRows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			m.id,
 			m.arena_match_id,
@@ -431,8 +479,8 @@ func (s *Store) GetDeckDetail(ctx context.Context, deckID int64, matchLimit int6
 		FROM matches m
 		JOIN match_decks md ON md.match_id = m.id
 		LEFT JOIN deck_versions dv ON dv.id = md.deck_version_id
-		WHERE md.deck_id = ?
-		ORDER BY COALESCE(m.started_at, m.ended_at, m.updated_at) DESC
+		WHERE md.deck_id = ? AND m.archived = 0
+		ORDER BY m.sort_at DESC
 		LIMIT ?
 	`, deckID, matchLimit)
 	if err != nil {
@@ -468,7 +516,7 @@ func (s *Store) GetDeckDetail(ctx context.Context, deckID int64, matchLimit int6
 }
 
 func (s *Store) ListDeckVersions(ctx context.Context, deckID int64) ([]model.DeckVersionRow, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT id, version_number, cards_hash, COALESCE(source, ''), COALESCE(effective_at, '')
 		FROM deck_versions
 		WHERE deck_id = ?
@@ -493,7 +541,7 @@ func (s *Store) ListDeckVersions(ctx context.Context, deckID int64) ([]model.Dec
 	}
 
 	for index := range versions {
-		cardRows, err := s.db.QueryContext(ctx, `
+		cardRows, err := s.reader().QueryContext(ctx, `
 			SELECT c.section, c.card_id, c.quantity, COALESCE(cc.name, '')
 			FROM deck_version_cards c
 			LEFT JOIN card_catalog cc ON cc.arena_id = c.card_id
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUpsertDeckKeepsNewerDecklist(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	store := NewStore(database)
+
+	upsert := func(name, lastUpdated string, cardID int64) {
+		t.Helper()
+		tx, err := store.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		cards := []DeckCard{{Section: "main", CardID: cardID, Quantity: 4}}
+		if _, err := store.UpsertDeck(ctx, tx, "deck-1", "Ladder", name, "Standard", "test", lastUpdated, cards); err != nil {
+			t.Fatalf("UpsertDeck(%s): %v", name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+
+	// The newer list lands first, as when an older archive finishes parsing
+	// after a newer one.
+	upsert("Mono Red v2", "2026-05-02T10:00:00Z", 2)
+	upsert("Mono Red v1", "2026-05-01T10:00:00.5Z", 1)
+
+	var name, lastUpdated string
+	var cardID int64
+	if err := database.QueryRowContext(ctx, `
+		SELECT d.name, d.last_updated, dc.card_id
+		FROM decks d
+		JOIN deck_cards dc ON dc.deck_id = d.id
+		WHERE d.arena_deck_id = 'deck-1'
+	`).Scan(&name, &lastUpdated, &cardID); err != nil {
+		t.Fatalf("read deck: %v", err)
+	}
+	if name != "Mono Red v2" || lastUpdated != "2026-05-02T10:00:00Z" || cardID != 2 {
+		t.Fatalf("deck = (%q, %q, card %d), want the newer list", name, lastUpdated, cardID)
+	}
+
+	var versions int
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM deck_versions`).Scan(&versions); err != nil {
+		t.Fatalf("count versions: %v", err)
+	}
+	if versions != 2 {
+		t.Fatalf("deck versions = %d, want both lists kept as versions", versions)
+	}
+
+	upsert("Mono Red v3", "2026-05-03T10:00:00Z", 3)
+	if err := database.QueryRowContext(ctx, `
+		SELECT d.name FROM decks d WHERE d.arena_deck_id = 'deck-1'
+	`).Scan(&name); err != nil {
+		t.Fatalf("read deck: %v", err)
+	}
+	if name != "Mono Red v3" {
+		t.Fatalf("deck name = %q, want the newest list to replace it", name)
+	}
+}
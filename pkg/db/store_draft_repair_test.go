@@ -29,7 +29,7 @@ func TestRepairDraftDataBackfillsPlayerDraftMetadataFromRawEvents(t *testing.T)
 	}
 
 	rawEvent := `{"DraftId":"draft-123","EventId":"PremierDraft_TMT_20260303","PackNumber":1,"PickNumber":1,"PickGrpId":1001,"CardsInPack":[1001,1002,1003],"EventType":24,"EventTime":"2026-04-04T00:33:13.720644Z"}`
-	if stored, err := store.InsertRawEvent(ctx, tx, "Player.log", 10, 100, "outgoing", "LogBusinessEvents", "req-1", []byte(rawEvent), ""); err != nil {
+	if stored, err := store.InsertRawEvent(ctx, tx, "Player.log", 10, 100, "", "outgoing", "LogBusinessEvents", "req-1", []byte(rawEvent), ""); err != nil {
 		t.Fatalf("InsertRawEvent: %v", err)
 	} else if !stored {
 		t.Fatal("InsertRawEvent skipped a draft pick business event")
@@ -143,14 +143,14 @@ func TestRepairDraftDataBackfillsPlayerDraftFromPickAndCompleteEvents(t *testing
 	}
 
 	rawPick := `{"DraftId":"draft-789","GrpIds":[100508],"Pack":3,"Pick":14}`
-	if stored, err := store.InsertRawEvent(ctx, tx, "Player.log", 20, 200, "outgoing", "EventPlayerDraftMakePick", "req-pick", []byte(rawPick), ""); err != nil {
+	if stored, err := store.InsertRawEvent(ctx, tx, "Player.log", 20, 200, "", "outgoing", "EventPlayerDraftMakePick", "req-pick", []byte(rawPick), ""); err != nil {
 		t.Fatalf("InsertRawEvent(pick): %v", err)
 	} else if !stored {
 		t.Fatal("InsertRawEvent skipped a player draft pick event")
 	}
 
 	rawComplete := `{"EventName":"PremierDraft_TMT_20260303","IsBotDraft":false}`
-	if stored, err := store.InsertRawEvent(ctx, tx, "Player.log", 21, 220, "outgoing", "DraftCompleteDraft", "req-complete", []byte(rawComplete), ""); err != nil {
+	if stored, err := store.InsertRawEvent(ctx, tx, "Player.log", 21, 220, "", "outgoing", "DraftCompleteDraft", "req-complete", []byte(rawComplete), ""); err != nil {
 		t.Fatalf("InsertRawEvent(complete): %v", err)
 	} else if !stored {
 		t.Fatal("InsertRawEvent skipped a draft complete event")
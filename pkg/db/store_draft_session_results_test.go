@@ -74,4 +74,15 @@ func TestListDraftSessionsIncludesMatchedDeckResults(t *testing.T) {
 	if rows[0].Losses == nil || *rows[0].Losses != 1 {
 		t.Fatalf("Losses = %v, want 1", rows[0].Losses)
 	}
+
+	runs, err := store.ListLimitedDraftRuns(ctx)
+	if err != nil {
+		t.Fatalf("ListLimitedDraftRuns: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("len(ListLimitedDraftRuns) = %d, want 1", len(runs))
+	}
+	if run := runs[0]; run.FirstPickCardID != 1001 || run.DeckID == 0 || run.LastMatchID == 0 || run.Wins != 2 || run.Losses != 1 {
+		t.Fatalf("run = %+v, want first pick 1001 and a 2-1 deck", run)
+	}
 }
@@ -9,7 +9,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 func (s *Store) EnsureDraftSession(ctx context.Context, tx *sql.Tx, eventName string, draftID *string, isBot bool, ts string) (int64, error) {
@@ -27,7 +27,7 @@ func (s *Store) EnsureDraftSession(ctx context.Context, tx *sql.Tx, eventName st
 				UPDATE draft_sessions
 				SET event_name = COALESCE(?, event_name), started_at = COALESCE(started_at, ?), updated_at = ?
 				WHERE id = ?
-			`, nullIfEmpty(eventName), nullIfEmpty(ts), nowUTC(), sessionID)
+			`, nullIfEmpty(eventName), nullIfEmpty(ts), s.nowUTC(), sessionID)
 			return sessionID, nil
 		}
 		if !errors.Is(err, sql.ErrNoRows) {
@@ -49,7 +49,7 @@ func (s *Store) EnsureDraftSession(ctx context.Context, tx *sql.Tx, eventName st
 				UPDATE draft_sessions
 				SET started_at = COALESCE(started_at, ?), updated_at = ?
 				WHERE id = ?
-			`, nullIfEmpty(ts), nowUTC(), sessionID)
+			`, nullIfEmpty(ts), s.nowUTC(), sessionID)
 			return sessionID, nil
 		}
 		if !errors.Is(err, sql.ErrNoRows) {
@@ -60,7 +60,7 @@ func (s *Store) EnsureDraftSession(ctx context.Context, tx *sql.Tx, eventName st
 	_, err := tx.ExecContext(ctx, `
 		INSERT INTO draft_sessions (event_name, draft_id, is_bot_draft, started_at, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?)
-	`, nullIfEmpty(eventName), nullDraftID(draftID), isBotInt, nullIfEmpty(ts), nowUTC(), nowUTC())
+	`, nullIfEmpty(eventName), nullDraftID(draftID), isBotInt, nullIfEmpty(ts), s.nowUTC(), s.nowUTC())
 	if err != nil {
 		return 0, fmt.Errorf("insert draft_session: %w", err)
 	}
@@ -98,12 +98,12 @@ func (s *Store) InsertDraftPick(ctx context.Context, tx *sql.Tx, sessionID int64
 			picked_card_ids = excluded.picked_card_ids,
 			pack_card_ids = excluded.pack_card_ids,
 			pick_ts = COALESCE(excluded.pick_ts, draft_picks.pick_ts)
-	`, sessionID, packNo, pickNo, string(pickedJSON), string(packJSON), nullIfEmpty(normalizeTS(ts)), nowUTC())
+	`, sessionID, packNo, pickNo, string(pickedJSON), string(packJSON), nullIfEmpty(normalizeTS(ts)), s.nowUTC())
 	if err != nil {
 		return fmt.Errorf("insert draft_pick: %w", err)
 	}
 
-	_, _ = tx.ExecContext(ctx, `UPDATE draft_sessions SET updated_at = ? WHERE id = ?`, nowUTC(), sessionID)
+	_, _ = tx.ExecContext(ctx, `UPDATE draft_sessions SET updated_at = ? WHERE id = ?`, s.nowUTC(), sessionID)
 	return nil
 }
 
@@ -119,7 +119,7 @@ func (s *Store) CompleteDraftSession(ctx context.Context, tx *sql.Tx, eventName
 			UPDATE draft_sessions
 			SET completed_at = COALESCE(completed_at, ?), updated_at = ?
 			WHERE draft_id = ? AND is_bot_draft = ?
-		`, nullIfEmpty(ts), nowUTC(), strings.TrimSpace(*draftID), isBotInt)
+		`, nullIfEmpty(ts), s.nowUTC(), strings.TrimSpace(*draftID), isBotInt)
 		if err != nil {
 			return fmt.Errorf("complete draft session by draft_id: %w", err)
 		}
@@ -147,7 +147,7 @@ func (s *Store) CompleteDraftSession(ctx context.Context, tx *sql.Tx, eventName
 				id DESC
 				LIMIT 1
 			)
-		`, nullIfEmpty(eventName), nullIfEmpty(ts), nowUTC(), isBotInt, eventName, eventName)
+		`, nullIfEmpty(eventName), nullIfEmpty(ts), s.nowUTC(), isBotInt, eventName, eventName)
 		if err != nil {
 			return fmt.Errorf("complete draft session by event_name: %w", err)
 		}
@@ -160,9 +160,9 @@ func (s *Store) CompleteDraftSession(ctx context.Context, tx *sql.Tx, eventName
 }
 
 func (s *Store) RepairDraftDataFromRawEvents(ctx context.Context) error {
-	now := nowUTC()
+	now := s.nowUTC()
 
-	_, err := s.db.ExecContext(ctx, `
+	_, err := s.exec(ctx, `
 		UPDATE draft_sessions
 		SET
 			event_name = COALESCE(
@@ -280,7 +280,7 @@ func (s *Store) RepairDraftDataFromRawEvents(ctx context.Context) error {
 		return fmt.Errorf("repair draft sessions from raw events: %w", err)
 	}
 
-	_, err = s.db.ExecContext(ctx, `
+	_, err = s.exec(ctx, `
 		UPDATE draft_picks
 		SET
 			pick_ts = COALESCE(
@@ -366,7 +366,7 @@ func (s *Store) RepairDraftDataFromRawEvents(ctx context.Context) error {
 // ListDraftSessions reads current draft rows; RepairDraftDataFromRawEvents
 // runs after ingest and during startup maintenance, not on this read path.
 func (s *Store) ListDraftSessions(ctx context.Context) ([]model.DraftSessionRow, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			ds.id,
 			COALESCE(ds.event_name, ''),
@@ -407,6 +407,8 @@ func (s *Store) ListDraftSessions(ctx context.Context) ([]model.DraftSessionRow,
 }
 
 type draftDeckCandidate struct {
+	DeckID        int64
+	LastMatchID   int64
 	DeckTS        time.Time
 	FirstPlayedAt time.Time
 	LastPlayedAt  time.Time
@@ -484,13 +486,32 @@ func chooseDraftDeckCandidate(candidates []draftDeckCandidate, startedAt, comple
 }
 
 func (s *Store) resolveDraftSessionDeckResults(ctx context.Context, eventName, startedAt, completedAt string) (int64, int64, bool, error) {
+	candidate, ok, err := s.resolveDraftSessionDeck(ctx, eventName, startedAt, completedAt)
+	if err != nil || !ok {
+		return 0, 0, false, err
+	}
+	return candidate.Wins, candidate.Losses, true, nil
+}
+
+// resolveDraftSessionDeck picks the deck built from a draft session among the
+// event's draft decks, by closeness to the draft's completion time.
+func (s *Store) resolveDraftSessionDeck(ctx context.Context, eventName, startedAt, completedAt string) (draftDeckCandidate, bool, error) {
 	eventName = strings.TrimSpace(eventName)
 	if eventName == "" {
-		return 0, 0, false, nil
+		return draftDeckCandidate{}, false, nil
 	}
 
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT
+			d.id,
+			COALESCE((
+				SELECT lm.id
+				FROM match_decks lmd
+				JOIN matches lm ON lm.id = lmd.match_id
+				WHERE lmd.deck_id = d.id
+				ORDER BY COALESCE(lm.started_at, lm.ended_at) DESC, lm.id DESC
+				LIMIT 1
+			), 0),
 			COALESCE(d.last_updated, d.created_at, ''),
 			COALESCE(MIN(COALESCE(m.started_at, m.ended_at)), ''),
 			COALESCE(MAX(COALESCE(m.started_at, m.ended_at)), ''),
@@ -507,7 +528,7 @@ func (s *Store) resolveDraftSessionDeckResults(ctx context.Context, eventName, s
 		GROUP BY d.id, d.last_updated, d.created_at
 	`, eventName)
 	if err != nil {
-		return 0, 0, false, fmt.Errorf("resolve draft session deck results: %w", err)
+		return draftDeckCandidate{}, false, fmt.Errorf("resolve draft session deck: %w", err)
 	}
 	defer rows.Close()
 
@@ -515,8 +536,8 @@ func (s *Store) resolveDraftSessionDeckResults(ctx context.Context, eventName, s
 	for rows.Next() {
 		var deckTSRaw, firstPlayedRaw, lastPlayedRaw string
 		var candidate draftDeckCandidate
-		if err := rows.Scan(&deckTSRaw, &firstPlayedRaw, &lastPlayedRaw, &candidate.Wins, &candidate.Losses); err != nil {
-			return 0, 0, false, fmt.Errorf("scan draft deck candidate: %w", err)
+		if err := rows.Scan(&candidate.DeckID, &candidate.LastMatchID, &deckTSRaw, &firstPlayedRaw, &lastPlayedRaw, &candidate.Wins, &candidate.Losses); err != nil {
+			return draftDeckCandidate{}, false, fmt.Errorf("scan draft deck candidate: %w", err)
 		}
 		if parsed, ok := parseStoredTime(deckTSRaw); ok {
 			candidate.DeckTS = parsed
@@ -530,18 +551,15 @@ func (s *Store) resolveDraftSessionDeckResults(ctx context.Context, eventName, s
 		candidates = append(candidates, candidate)
 	}
 	if err := rows.Err(); err != nil {
-		return 0, 0, false, fmt.Errorf("iterate draft deck candidates: %w", err)
+		return draftDeckCandidate{}, false, fmt.Errorf("iterate draft deck candidates: %w", err)
 	}
 
 	candidate, ok := chooseDraftDeckCandidate(candidates, startedAt, completedAt)
-	if !ok {
-		return 0, 0, false, nil
-	}
-	return candidate.Wins, candidate.Losses, true, nil
+	return candidate, ok, nil
 }
 
 func (s *Store) ListDraftPicks(ctx context.Context, draftSessionID int64) ([]model.DraftPickRow, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT id, pack_number, pick_number, picked_card_ids, COALESCE(pack_card_ids, '[]'), COALESCE(pick_ts, '')
 		FROM draft_picks
 		WHERE draft_session_id = ?
@@ -566,3 +584,19 @@ func (s *Store) ListDraftPicks(ctx context.Context, draftSessionID int64) ([]mod
 
 	return out, nil
 }
+
+// DraftSessionEventName returns a draft session's event name, or
+// sql.ErrNoRows when there is no such session.
+func (s *Store) DraftSessionEventName(ctx context.Context, draftSessionID int64) (string, error) {
+	var eventName string
+	err := s.reader().QueryRowContext(ctx, `
+		SELECT COALESCE(event_name, '') FROM draft_sessions WHERE id = ?
+	`, draftSessionID).Scan(&eventName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", err
+		}
+		return "", fmt.Errorf("get draft session event: %w", err)
+	}
+	return eventName, nil
+}
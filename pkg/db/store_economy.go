@@ -8,7 +8,7 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 type EconomySnapshotRecord struct {
@@ -65,7 +65,7 @@ func (s *Store) InsertEconomySnapshot(
 		snapshot.WildcardCommons, snapshot.WildcardUncommons, snapshot.WildcardRares,
 		snapshot.WildcardMythics, jsonOrDefault(snapshot.CustomTokensJSON, "{}"),
 		jsonOrDefault(snapshot.BoostersJSON, "[]"), jsonOrDefault(snapshot.VouchersJSON, "{}"),
-		jsonOrDefault(snapshot.ChangesJSON, "[]"), nowUTC())
+		jsonOrDefault(snapshot.ChangesJSON, "[]"), s.nowUTC())
 	if err != nil {
 		return 0, false, fmt.Errorf("insert economy snapshot: %w", err)
 	}
@@ -91,7 +91,7 @@ func (s *Store) InsertEconomySnapshot(
 }
 
 func (s *Store) ListEconomyHistory(ctx context.Context) ([]model.EconomySnapshot, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			id,
 			COALESCE(observed_at, ''),
@@ -155,7 +155,7 @@ func (s *Store) ListEconomyHistory(ctx context.Context) ([]model.EconomySnapshot
 // ListEconomyTransactions returns the normalized inventory-change ledger in
 // chronological order.
 func (s *Store) ListEconomyTransactions(ctx context.Context) ([]model.EconomyTransaction, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			id,
 			COALESCE(observed_at, ''),
@@ -221,7 +221,7 @@ func (s *Store) ListEconomyTransactions(ctx context.Context) ([]model.EconomyTra
 // and open play) are omitted. SetCode is left for the API layer to derive
 // from the event name.
 func (s *Store) ListEventRunEconomies(ctx context.Context) ([]model.EventRunEconomy, error) {
-	runRows, err := s.db.QueryContext(ctx, `
+	runRows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			event_name,
 			COALESCE(event_type, 'other'),
@@ -289,7 +289,7 @@ func (s *Store) ListEventRunEconomies(ctx context.Context) ([]model.EventRunEcon
 		return entry
 	}
 
-	txnRows, err := s.db.QueryContext(ctx, `
+	txnRows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			event_name,
 			COALESCE(event_link, ''),
@@ -81,6 +81,10 @@ func (s *Store) resolveEventNameAlias(ctx context.Context, tx *sql.Tx, eventName
 	return eventName, nil
 }
 
+// UpsertEventRunJoin records joining eventName. Logs parsed concurrently can
+// store a run's matches or prize claim before its join, so the join fills in
+// the row they created: the entry fee, and the earlier start time, while the
+// record and status stay as they are.
 func (s *Store) UpsertEventRunJoin(ctx context.Context, tx *sql.Tx, eventName, currencyType string, currencyPaid int64, ts string) error {
 	eventType := detectEventType(eventName)
 	ts = normalizeTS(ts)
@@ -92,23 +96,27 @@ func (s *Store) UpsertEventRunJoin(ctx context.Context, tx *sql.Tx, eventName, c
 			event_type = excluded.event_type,
 			entry_currency_type = COALESCE(excluded.entry_currency_type, event_runs.entry_currency_type),
 			entry_currency_paid = COALESCE(excluded.entry_currency_paid, event_runs.entry_currency_paid),
+			started_at = COALESCE(MIN(excluded.started_at, event_runs.started_at), excluded.started_at, event_runs.started_at),
 			updated_at = excluded.updated_at
-	`, eventName, eventType, nullIfEmpty(currencyType), nullableInt(currencyPaid), nullIfEmpty(ts), nowUTC())
+	`, eventName, eventType, nullIfEmpty(currencyType), nullableInt(currencyPaid), nullIfEmpty(ts), s.nowUTC())
 	if err != nil {
 		return fmt.Errorf("upsert event_runs join: %w", err)
 	}
 	return nil
 }
 
+// MarkEventRunClaimed marks the run claimed, creating its row when the join
+// has not been stored yet.
 func (s *Store) MarkEventRunClaimed(ctx context.Context, tx *sql.Tx, eventName, ts string) error {
 	ts = normalizeTS(ts)
 	_, err := tx.ExecContext(ctx, `
-		UPDATE event_runs
-		SET status = 'claimed',
-			ended_at = COALESCE(ended_at, ?),
-			updated_at = ?
-		WHERE event_name = ?
-	`, nullIfEmpty(ts), nowUTC(), eventName)
+		INSERT INTO event_runs (event_name, event_type, status, ended_at, updated_at)
+		VALUES (?, ?, 'claimed', ?, ?)
+		ON CONFLICT(event_name) DO UPDATE SET
+			status = 'claimed',
+			ended_at = COALESCE(event_runs.ended_at, excluded.ended_at),
+			updated_at = excluded.updated_at
+	`, eventName, detectEventType(eventName), nullIfEmpty(ts), s.nowUTC())
 	if err != nil {
 		return fmt.Errorf("mark event run claimed: %w", err)
 	}
@@ -116,6 +124,13 @@ func (s *Store) MarkEventRunClaimed(ctx context.Context, tx *sql.Tx, eventName,
 }
 
 func (s *Store) BumpEventRunRecord(ctx context.Context, tx *sql.Tx, eventName, result string) error {
+	return s.adjustEventRunRecord(ctx, tx, eventName, result, 1)
+}
+
+// adjustEventRunRecord adds delta to the run's wins or losses, never taking
+// the count below zero. The run's row is created when missing, so a result
+// stored before the join is kept rather than dropped.
+func (s *Store) adjustEventRunRecord(ctx context.Context, tx *sql.Tx, eventName, result string, delta int64) error {
 	if eventName == "" || (result != "win" && result != "loss") {
 		return nil
 	}
@@ -124,11 +139,12 @@ func (s *Store) BumpEventRunRecord(ctx context.Context, tx *sql.Tx, eventName, r
 		col = "losses"
 	}
 	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
-		UPDATE event_runs
-		SET %s = %s + 1,
-			updated_at = ?
-		WHERE event_name = ?
-	`, col, col), nowUTC(), eventName)
+		INSERT INTO event_runs (event_name, event_type, status, %[1]s, updated_at)
+		VALUES (?, ?, 'active', MAX(?, 0), ?)
+		ON CONFLICT(event_name) DO UPDATE SET
+			%[1]s = MAX(event_runs.%[1]s + ?, 0),
+			updated_at = excluded.updated_at
+	`, col), eventName, detectEventType(eventName), delta, s.nowUTC(), delta)
 	if err != nil {
 		return fmt.Errorf("bump event run record: %w", err)
 	}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEventRunJoinFillsRunStoredBeforeIt(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	store := NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// A newer log's results and claim land before the older log's join.
+	const event = "PremierDraft_TMT_20260303"
+	if err := store.BumpEventRunRecord(ctx, tx, event, "win"); err != nil {
+		t.Fatalf("BumpEventRunRecord(win): %v", err)
+	}
+	if err := store.BumpEventRunRecord(ctx, tx, event, "loss"); err != nil {
+		t.Fatalf("BumpEventRunRecord(loss): %v", err)
+	}
+	if err := store.MarkEventRunClaimed(ctx, tx, event, "2026-03-05T12:00:00Z"); err != nil {
+		t.Fatalf("MarkEventRunClaimed: %v", err)
+	}
+	if err := store.UpsertEventRunJoin(ctx, tx, event, "Gem", 1500, "2026-03-04T09:00:00Z"); err != nil {
+		t.Fatalf("UpsertEventRunJoin: %v", err)
+	}
+
+	var wins, losses, paid int64
+	var status, startedAt, endedAt string
+	if err := tx.QueryRowContext(ctx, `
+		SELECT wins, losses, status, entry_currency_paid, started_at, ended_at
+		FROM event_runs WHERE event_name = ?
+	`, event).Scan(&wins, &losses, &status, &paid, &startedAt, &endedAt); err != nil {
+		t.Fatalf("read event run: %v", err)
+	}
+	if wins != 1 || losses != 1 || status != "claimed" || paid != 1500 {
+		t.Fatalf("event run = %d-%d %s paid %d, want 1-1 claimed paid 1500", wins, losses, status, paid)
+	}
+	if startedAt != "2026-03-04T09:00:00Z" || endedAt != "2026-03-05T12:00:00Z" {
+		t.Fatalf("event run ran %s to %s, want the join and claim times", startedAt, endedAt)
+	}
+}
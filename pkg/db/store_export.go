@@ -0,0 +1,216 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// StreamMatches calls fn for every match filter selects, newest first,
+// reading rows straight off the cursor so exports never hold the full result
+// set in memory. An error from fn stops the scan and is returned as-is.
+func (s *Store) StreamMatches(ctx context.Context, filter MatchFilter, fn func(model.MatchRow) error) error {
+	where, args := filter.where()
+	rows, err := s.reader().QueryContext(ctx, matchRowSelectSQL+where+`
+		ORDER BY m.sort_at DESC, m.id DESC
+	`, args...)
+	if err != nil {
+		return fmt.Errorf("stream matches: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := scanMatchRow(rows)
+		if err != nil {
+			return fmt.Errorf("scan match row: %w", err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate matches: %w", err)
+	}
+	return nil
+}
+
+// StreamCardPlays calls fn for every recorded card play in the matches filter
+// selects, ordered by match then play order. Card names come from the local
+// catalog only; exports never trigger remote lookups.
+func (s *Store) StreamCardPlays(ctx context.Context, filter MatchFilter, fn func(model.CardPlayExportRow) error) error {
+	where, args := filter.where()
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT
+			m.id,
+			m.arena_match_id,
+			cp.id,
+			cp.game_number,
+			cp.instance_id,
+			cp.card_id,
+			COALESCE(cc.name, ''),
+			cp.owner_seat_id,
+			CASE
+				WHEN m.player_seat_id IS NOT NULL AND cp.owner_seat_id = m.player_seat_id THEN 'self'
+				WHEN cp.owner_seat_id IS NOT NULL AND cp.owner_seat_id > 0 THEN 'opponent'
+				ELSE 'unknown'
+			END AS player_side,
+			COALESCE(cp.first_public_zone, ''),
+			cp.turn_number,
+			COALESCE(cp.phase, ''),
+			COALESCE(cp.played_at, '')
+		FROM match_card_plays cp
+		JOIN matches m ON m.id = cp.match_id
+		LEFT JOIN card_catalog cc ON cc.arena_id = cp.card_id
+		`+where+`
+		ORDER BY cp.match_id ASC, cp.game_number ASC, COALESCE(cp.turn_number, 1000000) ASC, COALESCE(cp.played_at, '') ASC, cp.id ASC
+	`, args...)
+	if err != nil {
+		return fmt.Errorf("stream card plays: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.CardPlayExportRow
+		var gameNo, ownerSeat, turnNo sql.NullInt64
+		if err := rows.Scan(
+			&row.MatchID,
+			&row.ArenaMatchID,
+			&row.ID,
+			&gameNo,
+			&row.InstanceID,
+			&row.CardID,
+			&row.CardName,
+			&ownerSeat,
+			&row.PlayerSide,
+			&row.FirstPublicZone,
+			&turnNo,
+			&row.Phase,
+			&row.PlayedAt,
+		); err != nil {
+			return fmt.Errorf("scan card play export row: %w", err)
+		}
+		row.GameNumber = nullInt64Ptr(gameNo)
+		row.OwnerSeatID = nullInt64Ptr(ownerSeat)
+		row.TurnNumber = nullInt64Ptr(turnNo)
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate card plays: %w", err)
+	}
+	return nil
+}
+
+// StreamDeckCards calls fn for every card line of every deck's current list,
+// ordered by deck then section. Only filter.EventName applies to decks.
+func (s *Store) StreamDeckCards(ctx context.Context, filter MatchFilter, fn func(model.DeckCardExportRow) error) error {
+	where := ""
+	args := []any{}
+	if filter.EventName != "" {
+		where = "WHERE d.event_name = ?"
+		args = append(args, filter.EventName)
+	}
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT
+			d.id,
+			d.arena_deck_id,
+			COALESCE(d.name, ''),
+			COALESCE(d.format, ''),
+			COALESCE(d.event_name, ''),
+			dc.section,
+			dc.card_id,
+			COALESCE(cc.name, ''),
+			dc.quantity
+		FROM deck_cards dc
+		JOIN decks d ON d.id = dc.deck_id
+		LEFT JOIN card_catalog cc ON cc.arena_id = dc.card_id
+		`+where+`
+		ORDER BY d.id ASC, dc.section ASC, dc.id ASC
+	`, args...)
+	if err != nil {
+		return fmt.Errorf("stream deck cards: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.DeckCardExportRow
+		if err := rows.Scan(
+			&row.DeckID,
+			&row.ArenaDeckID,
+			&row.DeckName,
+			&row.Format,
+			&row.EventName,
+			&row.Section,
+			&row.CardID,
+			&row.CardName,
+			&row.Quantity,
+		); err != nil {
+			return fmt.Errorf("scan deck card export row: %w", err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate deck cards: %w", err)
+	}
+	return nil
+}
+
+// StreamDraftPicks calls fn for every draft pick, ordered by session then
+// pick. Only filter.EventName applies to drafts.
+func (s *Store) StreamDraftPicks(ctx context.Context, filter MatchFilter, fn func(model.DraftPickExportRow) error) error {
+	where := ""
+	args := []any{}
+	if filter.EventName != "" {
+		where = "WHERE ds.event_name = ?"
+		args = append(args, filter.EventName)
+	}
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT
+			ds.id,
+			COALESCE(ds.draft_id, ''),
+			COALESCE(ds.event_name, ''),
+			ds.is_bot_draft != 0,
+			dp.pack_number,
+			dp.pick_number,
+			dp.picked_card_ids,
+			COALESCE(dp.pack_card_ids, ''),
+			COALESCE(dp.pick_ts, '')
+		FROM draft_picks dp
+		JOIN draft_sessions ds ON ds.id = dp.draft_session_id
+		`+where+`
+		ORDER BY ds.id ASC, dp.pack_number ASC, dp.pick_number ASC
+	`, args...)
+	if err != nil {
+		return fmt.Errorf("stream draft picks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.DraftPickExportRow
+		if err := rows.Scan(
+			&row.DraftSessionID,
+			&row.DraftID,
+			&row.EventName,
+			&row.IsBotDraft,
+			&row.PackNumber,
+			&row.PickNumber,
+			&row.PickedCardIDs,
+			&row.PackCardIDs,
+			&row.PickTs,
+		); err != nil {
+			return fmt.Errorf("scan draft pick export row: %w", err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate draft picks: %w", err)
+	}
+	return nil
+}
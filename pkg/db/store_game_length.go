@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// gameLengthAcc sums one group's match lengths.
+type gameLengthAcc struct {
+	key, label                   string
+	matches                      int64
+	turnMatches, turns           int64
+	winMatches, winTurns         int64
+	lossMatches, lossTurns       int64
+	secondsMatches, totalSeconds int64
+}
+
+func (a *gameLengthAcc) add(result string, turns, seconds sql.NullInt64) {
+	a.matches++
+	if turns.Valid && turns.Int64 > 0 {
+		a.turnMatches++
+		a.turns += turns.Int64
+		switch result {
+		case "win":
+			a.winMatches++
+			a.winTurns += turns.Int64
+		case "loss":
+			a.lossMatches++
+			a.lossTurns += turns.Int64
+		}
+	}
+	if seconds.Valid && seconds.Int64 > 0 {
+		a.secondsMatches++
+		a.totalSeconds += seconds.Int64
+	}
+}
+
+func (a *gameLengthAcc) group() model.GameLengthGroup {
+	mean := func(sum, count int64) *float64 {
+		if count == 0 {
+			return nil
+		}
+		value := float64(sum) / float64(count)
+		return &value
+	}
+	return model.GameLengthGroup{
+		Key:              a.key,
+		Label:            a.label,
+		Matches:          a.matches,
+		TurnMatches:      a.turnMatches,
+		AverageTurns:     mean(a.turns, a.turnMatches),
+		WinAverageTurns:  mean(a.winTurns, a.winMatches),
+		LossAverageTurns: mean(a.lossTurns, a.lossMatches),
+		SecondsMatches:   a.secondsMatches,
+		AverageSeconds:   mean(a.totalSeconds, a.secondsMatches),
+	}
+}
+
+// gameLengthGroups keeps groups in first-seen order.
+type gameLengthGroups struct {
+	order []*gameLengthAcc
+	byKey map[string]*gameLengthAcc
+}
+
+func (g *gameLengthGroups) get(key, label string) *gameLengthAcc {
+	if acc, ok := g.byKey[key]; ok {
+		return acc
+	}
+	if g.byKey == nil {
+		g.byKey = make(map[string]*gameLengthAcc)
+	}
+	acc := &gameLengthAcc{key: key, label: label}
+	g.byKey[key] = acc
+	g.order = append(g.order, acc)
+	return acc
+}
+
+// list returns the groups busiest first, or in first-seen order when
+// busiestFirst is false.
+func (g *gameLengthGroups) list(busiestFirst bool) []model.GameLengthGroup {
+	out := make([]model.GameLengthGroup, 0, len(g.order))
+	for _, acc := range g.order {
+		out = append(out, acc.group())
+	}
+	if busiestFirst {
+		sort.SliceStable(out, func(i, j int) bool { return out[i].Matches > out[j].Matches })
+	}
+	return out
+}
+
+// GameLengthStats averages the recorded turn count and duration of every
+// decided match, overall and per deck, format, set, and UTC month started.
+// A match counts toward its first linked deck; matches with no deck, format,
+// or set are only in the groupings they have. Archived matches are left out.
+func (s *Store) GameLengthStats(ctx context.Context) (model.GameLengthStats, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT
+			m.result,
+			m.turn_count,
+			m.seconds_count,
+			COALESCE(m.format, ''),
+			COALESCE(m.event_name, ''),
+			COALESCE(strftime('%Y-%m', m.started_at), ''),
+			COALESCE(md.deck_id, 0),
+			COALESCE(d.name, '')
+		FROM matches m
+		LEFT JOIN (
+			SELECT match_id, MIN(deck_id) AS deck_id
+			FROM match_decks
+			GROUP BY match_id
+		) md ON md.match_id = m.id
+		LEFT JOIN decks d ON d.id = md.deck_id
+		WHERE m.archived = 0
+		  AND m.result IN ('win', 'loss', 'draw')
+		ORDER BY julianday(m.started_at), m.id
+	`)
+	if err != nil {
+		return model.GameLengthStats{}, fmt.Errorf("list match lengths: %w", err)
+	}
+	defer rows.Close()
+
+	var overall gameLengthAcc
+	var decks, formats, sets, months gameLengthGroups
+	for rows.Next() {
+		var result, format, eventName, month, deckName string
+		var turns, seconds sql.NullInt64
+		var deckID int64
+		if err := rows.Scan(&result, &turns, &seconds, &format, &eventName, &month, &deckID, &deckName); err != nil {
+			return model.GameLengthStats{}, fmt.Errorf("scan match length: %w", err)
+		}
+		overall.add(result, turns, seconds)
+		if deckID > 0 {
+			decks.get(strconv.FormatInt(deckID, 10), deckName).add(result, turns, seconds)
+		}
+		if format = strings.TrimSpace(format); format != "" {
+			formats.get(format, format).add(result, turns, seconds)
+		}
+		if setCode := strings.ToUpper(eventnames.SetCode(eventName)); setCode != "" {
+			sets.get(setCode, setCode).add(result, turns, seconds)
+		}
+		if month != "" {
+			months.get(month, month).add(result, turns, seconds)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return model.GameLengthStats{}, fmt.Errorf("iterate match lengths: %w", err)
+	}
+
+	overall.key, overall.label = "all", "All matches"
+	out := model.GameLengthStats{
+		Overall: overall.group(),
+		Decks:   decks.list(true),
+		Formats: formats.list(true),
+		Sets:    sets.list(true),
+		// Rows come oldest first, so first-seen order is month order.
+		Months: months.list(false),
+	}
+	return out, nil
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// Ping reports whether the database still answers queries.
+func (s *Store) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping database: %w", err)
+	}
+	return nil
+}
+
+// CheckWritable takes the write lock and writes a row, then rolls it back, so
+// a read-only file, a full disk, or another process holding the lock shows up
+// before a parse fails halfway.
+func (s *Store) CheckWritable(ctx context.Context) error {
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin write check: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(ctx, `
+		INSERT OR REPLACE INTO app_metadata (key, value, updated_at) VALUES ('doctor_write_check', '', ?)
+	`, s.nowUTC()); err != nil {
+		return fmt.Errorf("write check: %w", err)
+	}
+	return nil
+}
+
+// SchemaVersion returns the schema version Init stamped into user_version.
+func (s *Store) SchemaVersion(ctx context.Context) (int64, error) {
+	var version int64
+	if err := s.reader().QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// MigrationVersion returns the newest numbered migration applied, 0 if none.
+func (s *Store) MigrationVersion(ctx context.Context) (int64, error) {
+	var version int64
+	if err := s.reader().QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("read migration version: %w", err)
+	}
+	return version, nil
+}
+
+// IngestLogStates lists the saved read position for every log ingested so
+// far, most recently advanced first. File sizes are left for the caller to
+// fill in since the store never touches the filesystem.
+func (s *Store) IngestLogStates(ctx context.Context) ([]model.IngestLogStatus, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT log_path, byte_offset, line_no, updated_at
+		FROM ingest_state
+		ORDER BY updated_at DESC, log_path
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list ingest_state: %w", err)
+	}
+	defer rows.Close()
+
+	out := []model.IngestLogStatus{}
+	for rows.Next() {
+		var row model.IngestLogStatus
+		if err := rows.Scan(&row.Path, &row.Offset, &row.LineNo, &row.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan ingest_state: %w", err)
+		}
+		row.UpdatedAt = normalizeTS(row.UpdatedAt)
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ingest_state: %w", err)
+	}
+	return out, nil
+}
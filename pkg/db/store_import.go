@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// ImportResult counts what Import added. Matches and draft sessions already
+// here, whether parsed from the log or imported before, are kept as they are
+// and counted as skipped.
+type ImportResult struct {
+	Matches         int64
+	MatchesSkipped  int64
+	DraftSessions   int64
+	DraftsSkipped   int64
+	DraftPicks      int64
+	PicksUnresolved int64
+}
+
+// Import writes matches and drafts read from another tracker's export, with
+// source recorded on every row, in one transaction. Card names in picks are
+// resolved through card_catalog; a pick whose card is not found there is
+// dropped and counted in PicksUnresolved, and is added by a later import of
+// the same draft once the catalog knows the card.
+func (s *Store) Import(ctx context.Context, source string, matches []model.ImportedMatch, drafts []model.ImportedDraft) (ImportResult, error) {
+	result := ImportResult{}
+
+	cardIDs, err := s.cardIDsByName(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		return result, fmt.Errorf("begin import: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := s.nowUTC()
+	for _, match := range matches {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO matches (
+				arena_match_id, event_name, format, opponent_name, started_at, ended_at,
+				result, turn_count, source, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(arena_match_id) DO NOTHING
+		`, match.ID, nullIfEmpty(match.EventName), nullIfEmpty(match.Format), nullIfEmpty(match.Opponent),
+			nullIfEmpty(normalizeTS(match.StartedAt)), nullIfEmpty(normalizeTS(match.EndedAt)),
+			match.Result, nullableInt(match.TurnCount), source, now, now)
+		if err != nil {
+			return result, fmt.Errorf("import match %s: %w", match.ID, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			result.Matches++
+		} else {
+			result.MatchesSkipped++
+		}
+	}
+
+	for _, draft := range drafts {
+		isBot := boolToInt(isBotDraftEvent(draft.EventName))
+		var sessionID int64
+		var existingSource string
+		err := tx.QueryRowContext(ctx, `
+			SELECT id, source FROM draft_sessions WHERE draft_id = ? AND is_bot_draft = ?
+		`, draft.DraftID, isBot).Scan(&sessionID, &existingSource)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			res, err := tx.ExecContext(ctx, `
+				INSERT INTO draft_sessions (
+					event_name, draft_id, is_bot_draft, started_at, completed_at, source, created_at, updated_at
+				) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			`, nullIfEmpty(draft.EventName), draft.DraftID, isBot,
+				nullIfEmpty(normalizeTS(draft.StartedAt)), nullIfEmpty(normalizeTS(draft.StartedAt)), source, now, now)
+			if err != nil {
+				return result, fmt.Errorf("import draft %s: %w", draft.DraftID, err)
+			}
+			if sessionID, err = res.LastInsertId(); err != nil {
+				return result, fmt.Errorf("import draft %s: %w", draft.DraftID, err)
+			}
+			result.DraftSessions++
+		case err != nil:
+			return result, fmt.Errorf("find draft %s: %w", draft.DraftID, err)
+		case existingSource != source:
+			// Parsed from the log (or another tracker): keep that copy.
+			result.DraftsSkipped++
+			continue
+		default:
+			// Imported from this source before; fill in picks that could
+			// not be resolved then.
+			result.DraftsSkipped++
+		}
+
+		for _, pick := range draft.Picks {
+			picked, ok := resolveImportedCards(pick.Picked, cardIDs)
+			if !ok || len(picked) == 0 {
+				result.PicksUnresolved++
+				continue
+			}
+			// Pack contents are best effort: an unknown card there only
+			// shortens the pack, it does not lose the pick.
+			pack, _ := resolveImportedCards(pick.Pack, cardIDs)
+			if err := s.InsertDraftPick(ctx, tx, sessionID, pick.PackNumber, pick.PickNumber, picked, pack, pick.PickedAt); err != nil {
+				return result, err
+			}
+			result.DraftPicks++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("commit import: %w", err)
+	}
+	return result, nil
+}
+
+// cardIDsByName maps lowercased card names to an Arena id. Printings share a
+// name, so the lowest id stands in for all of them.
+func (s *Store) cardIDsByName(ctx context.Context) (map[string]int64, error) {
+	rows, err := s.reader().QueryContext(ctx, `SELECT name, MIN(arena_id) FROM card_catalog GROUP BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("load card names: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string]int64{}
+	for rows.Next() {
+		var name string
+		var id int64
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, fmt.Errorf("scan card name: %w", err)
+		}
+		key := strings.ToLower(strings.TrimSpace(name))
+		if existing, ok := out[key]; !ok || id < existing {
+			out[key] = id
+		}
+	}
+	return out, rows.Err()
+}
+
+// resolveImportedCards turns Arena ids or card names into ids; ok is false
+// if any of them could not be resolved.
+func resolveImportedCards(cards []string, byName map[string]int64) ([]int64, bool) {
+	ids := make([]int64, 0, len(cards))
+	ok := true
+	for _, card := range cards {
+		card = strings.TrimSpace(card)
+		if card == "" {
+			continue
+		}
+		if id, err := strconv.ParseInt(card, 10, 64); err == nil && id > 0 {
+			ids = append(ids, id)
+			continue
+		}
+		id, found := byName[strings.ToLower(card)]
+		if !found {
+			ok = false
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, ok
+}
+
+// isBotDraftEvent reports whether eventName is a draft against bots, which
+// Arena names QuickDraft.
+func isBotDraftEvent(eventName string) bool {
+	return strings.Contains(strings.ToLower(eventName), "quickdraft")
+}
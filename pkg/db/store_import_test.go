@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestImportKeepsExistingRowsAndFillsPicksLater(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	store := NewStore(database)
+
+	mustExec(t, database, `
+		INSERT INTO matches (arena_match_id, event_name, started_at, result, created_at, updated_at)
+		VALUES ('arena-1', 'Ladder', '2024-03-05T20:15:00Z', 'loss', '2024-03-05T20:15:00Z', '2024-03-05T20:15:00Z')
+	`)
+	mustExec(t, database, `INSERT INTO card_catalog (arena_id, name, updated_at) VALUES (9001, 'Shock', '2024-01-01T00:00:00Z')`)
+
+	matches := []model.ImportedMatch{
+		{ID: "arena-1", EventName: "Ladder", StartedAt: "2024-03-05T20:15:00Z", Result: "win"},
+		{ID: "17lands:d1:1", EventName: "TradDraft_BLB", StartedAt: "2024-08-01T11:00:00Z", Result: "win", TurnCount: 27},
+	}
+	drafts := []model.ImportedDraft{{
+		DraftID:   "d1",
+		EventName: "TradDraft_BLB",
+		StartedAt: "2024-08-01T10:00:00Z",
+		Picks: []model.ImportedDraftPick{
+			{PackNumber: 1, PickNumber: 1, Picked: []string{"Shock"}, Pack: []string{"Shock", "Plains"}},
+			{PackNumber: 1, PickNumber: 2, Picked: []string{"Plains"}},
+		},
+	}}
+
+	result, err := store.Import(ctx, "17lands", matches, drafts)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	want := ImportResult{Matches: 1, MatchesSkipped: 1, DraftSessions: 1, DraftPicks: 1, PicksUnresolved: 1}
+	if result != want {
+		t.Fatalf("first import = %+v, want %+v", result, want)
+	}
+
+	var logResult, importedSource string
+	if err := database.QueryRowContext(ctx, `SELECT result FROM matches WHERE arena_match_id = 'arena-1'`).Scan(&logResult); err != nil {
+		t.Fatalf("read logged match: %v", err)
+	}
+	if logResult != "loss" {
+		t.Fatalf("logged match result = %q, want the log's loss kept", logResult)
+	}
+	if err := database.QueryRowContext(ctx, `SELECT source FROM matches WHERE arena_match_id = '17lands:d1:1'`).Scan(&importedSource); err != nil {
+		t.Fatalf("read imported match: %v", err)
+	}
+	if importedSource != "17lands" {
+		t.Fatalf("imported match source = %q, want 17lands", importedSource)
+	}
+
+	mustExec(t, database, `INSERT INTO card_catalog (arena_id, name, updated_at) VALUES (9002, 'Plains', '2024-01-01T00:00:00Z')`)
+	result, err = store.Import(ctx, "17lands", matches, drafts)
+	if err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	want = ImportResult{MatchesSkipped: 2, DraftsSkipped: 1, DraftPicks: 2}
+	if result != want {
+		t.Fatalf("second import = %+v, want %+v", result, want)
+	}
+	var picks int
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM draft_picks`).Scan(&picks); err != nil {
+		t.Fatalf("count picks: %v", err)
+	}
+	if picks != 2 {
+		t.Fatalf("draft picks = %d, want 2 once Plains resolves", picks)
+	}
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/solean/ponder/internal/eventnames"
+)
+
+// limitedSplashMaxCards is the most cards of a color a limited deck can
+// play and still only be splashing it, the same cut the match list uses for
+// a deck's colors.
+const limitedSplashMaxCards = 2
+
+var limitedColorOrder = []string{"W", "U", "B", "R", "G"}
+
+// LimitedDeckColors is the final build of one draft run with the colors
+// derived from its main deck. A color with more than limitedSplashMaxCards
+// cards is a main color; one with fewer is a splash. Lands are colorless,
+// so a dual land does not count toward the color it fixes for.
+type LimitedDeckColors struct {
+	SessionID int64
+	EventName string
+	DeckID    int64
+	Wins      int64
+	Losses    int64
+	Colors    []string
+	Splash    []string
+	// Known is false when none of the deck's cards has cached metadata.
+	Known bool
+	// UnresolvedCardIDs are main-deck cards with no cached metadata, which
+	// the caller can resolve and cache before asking again.
+	UnresolvedCardIDs []int64
+}
+
+// ListLimitedDeckColors derives the colors of every draft run of setCode
+// (case-insensitive) that produced a deck with a recorded match, oldest
+// first, from the cached colors of the deck's main-deck cards.
+func (s *Store) ListLimitedDeckColors(ctx context.Context, setCode string) ([]LimitedDeckColors, error) {
+	setCode = strings.ToUpper(strings.TrimSpace(setCode))
+	runs, err := s.ListLimitedDraftRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var selected []LimitedDraftRun
+	matchIDs := make([]int64, 0, len(runs))
+	for _, run := range runs {
+		if run.LastMatchID <= 0 || strings.ToUpper(eventnames.SetCode(run.EventName)) != setCode {
+			continue
+		}
+		selected = append(selected, run)
+		matchIDs = append(matchIDs, run.LastMatchID)
+	}
+	quantitiesByMatch, err := s.ListMatchDeckCardQuantities(ctx, matchIDs)
+	if err != nil {
+		return nil, err
+	}
+	var cardIDs []int64
+	for _, quantities := range quantitiesByMatch {
+		for cardID := range quantities {
+			cardIDs = append(cardIDs, cardID)
+		}
+	}
+	metadata, err := s.LookupCardMetadata(ctx, cardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]LimitedDeckColors, 0, len(selected))
+	for _, run := range selected {
+		deck := LimitedDeckColors{
+			SessionID: run.SessionID,
+			EventName: run.EventName,
+			DeckID:    run.DeckID,
+			Wins:      run.Wins,
+			Losses:    run.Losses,
+		}
+		totals := make(map[string]int64, len(limitedColorOrder))
+		for cardID, quantity := range quantitiesByMatch[run.LastMatchID] {
+			meta, ok := metadata[cardID]
+			if !ok {
+				deck.UnresolvedCardIDs = append(deck.UnresolvedCardIDs, cardID)
+				continue
+			}
+			deck.Known = true
+			for _, color := range strings.Split(strings.ToUpper(meta.Colors), "") {
+				totals[color] += quantity
+			}
+		}
+		for _, color := range limitedColorOrder {
+			switch count := totals[color]; {
+			case count > limitedSplashMaxCards:
+				deck.Colors = append(deck.Colors, color)
+			case count > 0:
+				deck.Splash = append(deck.Splash, color)
+			}
+		}
+		// A deck whose every color is thin, such as a pile of artifacts,
+		// is not splashing; its colors are all it has.
+		if len(deck.Colors) == 0 {
+			deck.Colors, deck.Splash = deck.Splash, nil
+		}
+		out = append(out, deck)
+	}
+	return out, nil
+}
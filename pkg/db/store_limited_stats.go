@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/solean/ponder/internal/eventnames"
+)
+
+// LimitedTrophyWins is the win count that earns a trophy: three for
+// best-of-three Traditional Draft, seven for the best-of-one formats.
+func LimitedTrophyWins(eventName string) int64 {
+	if eventnames.Kind(eventName) == "traditional_draft" {
+		return 3
+	}
+	return 7
+}
+
+// LimitedDraftRun is one draft session joined to the deck built from it.
+// DeckID and LastMatchID are zero when no deck could be matched; the last
+// match's deck version is the final build.
+type LimitedDraftRun struct {
+	SessionID       int64
+	EventName       string
+	StartedAt       string
+	CompletedAt     string
+	FirstPickCardID int64
+	DeckID          int64
+	LastMatchID     int64
+	Wins            int64
+	Losses          int64
+}
+
+// ListLimitedDraftRuns lists every draft session, oldest first, with its
+// first pick and the record of the deck it produced.
+func (s *Store) ListLimitedDraftRuns(ctx context.Context) ([]LimitedDraftRun, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT
+			ds.id,
+			COALESCE(ds.event_name, ''),
+			COALESCE(ds.started_at, ''),
+			COALESCE(ds.completed_at, ''),
+			COALESCE((
+				SELECT dp.picked_card_ids
+				FROM draft_picks dp
+				WHERE dp.draft_session_id = ds.id
+				ORDER BY dp.pack_number, dp.pick_number
+				LIMIT 1
+			), '')
+		FROM draft_sessions ds
+		ORDER BY COALESCE(ds.started_at, ds.created_at), ds.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list limited draft runs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []LimitedDraftRun
+	for rows.Next() {
+		var run LimitedDraftRun
+		var firstPickJSON string
+		if err := rows.Scan(&run.SessionID, &run.EventName, &run.StartedAt, &run.CompletedAt, &firstPickJSON); err != nil {
+			return nil, fmt.Errorf("scan limited draft run: %w", err)
+		}
+		var picked []int64
+		if err := json.Unmarshal([]byte(firstPickJSON), &picked); err == nil && len(picked) > 0 {
+			run.FirstPickCardID = picked[0]
+		}
+		out = append(out, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate limited draft runs: %w", err)
+	}
+
+	for i := range out {
+		candidate, ok, err := s.resolveDraftSessionDeck(ctx, out[i].EventName, out[i].StartedAt, out[i].CompletedAt)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		out[i].DeckID = candidate.DeckID
+		out[i].LastMatchID = candidate.LastMatchID
+		out[i].Wins = candidate.Wins
+		out[i].Losses = candidate.Losses
+	}
+	return out, nil
+}
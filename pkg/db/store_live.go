@@ -5,7 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 // GetLiveMatchID returns the id of the match currently in progress, if any. A
@@ -14,7 +14,7 @@ import (
 // game (closed Arena mid-match) from resurfacing days later.
 func (s *Store) GetLiveMatchID(ctx context.Context) (int64, bool, error) {
 	var id int64
-	err := s.db.QueryRowContext(ctx, `
+	err := s.reader().QueryRowContext(ctx, `
 		SELECT id
 		FROM matches
 		WHERE result IS NULL
@@ -38,7 +38,7 @@ func (s *Store) GetLiveMatchID(ctx context.Context) (int64, bool, error) {
 // values are 0 when nothing has been observed yet.
 func (s *Store) GetLiveProgress(ctx context.Context, matchID int64) (gameNumber, turnNumber int64, err error) {
 	var game, turn sql.NullInt64
-	err = s.db.QueryRowContext(ctx, `
+	err = s.reader().QueryRowContext(ctx, `
 		SELECT
 			MAX(game_number),
 			MAX(CASE WHEN game_number = (SELECT MAX(game_number) FROM match_card_plays WHERE match_id = ?) THEN turn_number END)
@@ -55,7 +55,7 @@ func (s *Store) GetLiveProgress(ctx context.Context, matchID int64) (gameNumber,
 // resolved from the local catalog. Shared by GetDeckDetail and the live match
 // assembler.
 func (s *Store) ListDeckCards(ctx context.Context, deckID int64) ([]model.DeckCardRow, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT dc.section, dc.card_id, dc.quantity, COALESCE(cc.name, '')
 		FROM deck_cards dc
 		LEFT JOIN card_catalog cc ON cc.arena_id = dc.card_id
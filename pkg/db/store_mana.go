@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+const (
+	// manaStuckSpellTurn is the player's own turn at whose end spells still
+	// in hand count as stuck.
+	manaStuckSpellTurn = 6
+	// manaScrewMissedDrops is how many missed land drops make a game screwed.
+	manaScrewMissedDrops = 2
+)
+
+// manaAcc sums one group's per-game mana facts.
+type manaAcc struct {
+	deckID              int64
+	deckName            string
+	games               int64
+	openingHandGames    int64
+	openingLands        int64
+	turnStatGames       int64
+	missedDrops         int64
+	screwed, notScrewed model.RecordAgg
+	stuckSpellGames     int64
+	stuckSpells         int64
+}
+
+func (a *manaAcc) add(result string, openingLands, missedDrops, stuckSpells sql.NullInt64) {
+	a.games++
+	if openingLands.Valid {
+		a.openingHandGames++
+		a.openingLands += openingLands.Int64
+	}
+	if missedDrops.Valid {
+		a.turnStatGames++
+		a.missedDrops += missedDrops.Int64
+		record := &a.notScrewed
+		if missedDrops.Int64 >= manaScrewMissedDrops {
+			record = &a.screwed
+		}
+		switch result {
+		case "win":
+			record.Wins++
+			record.Games++
+		case "loss":
+			record.Losses++
+			record.Games++
+		case "draw":
+			record.Draws++
+			record.Games++
+		}
+	}
+	if stuckSpells.Valid {
+		a.stuckSpellGames++
+		a.stuckSpells += stuckSpells.Int64
+	}
+}
+
+func (a *manaAcc) group() model.ManaGroup {
+	mean := func(sum, count int64) *float64 {
+		if count == 0 {
+			return nil
+		}
+		value := float64(sum) / float64(count)
+		return &value
+	}
+	return model.ManaGroup{
+		DeckID:              a.deckID,
+		DeckName:            a.deckName,
+		Games:               a.games,
+		OpeningHandGames:    a.openingHandGames,
+		AverageOpeningLands: mean(a.openingLands, a.openingHandGames),
+		TurnStatGames:       a.turnStatGames,
+		AverageMissedDrops:  mean(a.missedDrops, a.turnStatGames),
+		Screwed:             a.screwed,
+		NotScrewed:          a.notScrewed,
+		StuckSpellGames:     a.stuckSpellGames,
+		AverageStuckSpells:  mean(a.stuckSpells, a.stuckSpellGames),
+	}
+}
+
+// ManaStats measures mana screw and flood per game and averages it overall
+// and per deck: lands kept in the opening hand, own turns that ended with no
+// land played (the final turn skipped, as for missed-drop flags), and nonland
+// cards still in hand at the end of the player's sixth turn. Each fact is
+// averaged only over games where it was observable; a game with an unresolved
+// type line in its opening hand has no land count. A match counts toward its
+// first linked deck, and archived matches are left out.
+func (s *Store) ManaStats(ctx context.Context) (model.ManaStats, error) {
+	// The player's sixth turn is game turn 11 on the play and 12 on the draw.
+	rows, err := s.reader().QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			g.result,
+			COALESCE(md.deck_id, 0),
+			COALESCE(d.name, ''),
+			(SELECT CASE WHEN COUNT(*) = 0 OR SUM(CASE WHEN ct.type_line IS NULL THEN 1 ELSE 0 END) > 0 THEN NULL
+				ELSE SUM(CASE WHEN LOWER(ct.type_line) LIKE '%%land%%' THEN s.opening_kept_copies ELSE 0 END) END
+			 FROM game_card_stats s
+			 LEFT JOIN card_types ct ON ct.arena_id = s.card_id
+			 WHERE s.game_id = g.id AND s.opening_kept_copies > 0),
+			(SELECT CASE WHEN COUNT(*) = 0 THEN NULL ELSE SUM(CASE WHEN ts.lands_played = 0 THEN 1 ELSE 0 END) END
+			 FROM game_turn_stats ts
+			 WHERE ts.game_id = g.id AND %s),
+			(SELECT ts.spells_in_hand
+			 FROM game_turn_stats ts
+			 WHERE ts.game_id = g.id
+			   AND ts.turn_number = CASE g.play_draw WHEN 'play' THEN %d WHEN 'draw' THEN %d END)
+		FROM games g
+		JOIN matches m ON m.id = g.match_id
+		LEFT JOIN (
+			SELECT match_id, MIN(deck_id) AS deck_id
+			FROM match_decks
+			GROUP BY match_id
+		) md ON md.match_id = g.match_id
+		LEFT JOIN decks d ON d.id = md.deck_id
+		WHERE m.archived = 0
+		ORDER BY g.id
+	`, missedDropTurnCond, 2*manaStuckSpellTurn-1, 2*manaStuckSpellTurn))
+	if err != nil {
+		return model.ManaStats{}, fmt.Errorf("list game mana facts: %w", err)
+	}
+	defer rows.Close()
+
+	var overall manaAcc
+	decks := make(map[int64]*manaAcc)
+	for rows.Next() {
+		var result, deckName string
+		var deckID int64
+		var openingLands, missedDrops, stuckSpells sql.NullInt64
+		if err := rows.Scan(&result, &deckID, &deckName, &openingLands, &missedDrops, &stuckSpells); err != nil {
+			return model.ManaStats{}, fmt.Errorf("scan game mana facts: %w", err)
+		}
+		overall.add(result, openingLands, missedDrops, stuckSpells)
+		if deckID == 0 {
+			continue
+		}
+		acc, ok := decks[deckID]
+		if !ok {
+			acc = &manaAcc{deckID: deckID, deckName: deckName}
+			decks[deckID] = acc
+		}
+		acc.add(result, openingLands, missedDrops, stuckSpells)
+	}
+	if err := rows.Err(); err != nil {
+		return model.ManaStats{}, fmt.Errorf("iterate game mana facts: %w", err)
+	}
+
+	out := model.ManaStats{
+		StuckSpellTurn:   manaStuckSpellTurn,
+		ScrewMissedDrops: manaScrewMissedDrops,
+		Overall:          overall.group(),
+		Decks:            make([]model.ManaGroup, 0, len(decks)),
+	}
+	for _, acc := range decks {
+		out.Decks = append(out.Decks, acc.group())
+	}
+	sort.Slice(out.Decks, func(i, j int) bool {
+		if out.Decks[i].Games != out.Decks[j].Games {
+			return out.Decks[i].Games > out.Decks[j].Games
+		}
+		return out.Decks[i].DeckID < out.Decks[j].DeckID
+	})
+	return out, nil
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestManaStatsAveragesOpeningLandsMissedDropsAndStuckSpells(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, deckID, matchID := setupDeckAnalyticsFixture(t)
+	if err := store.UpsertCardTypeLines(ctx, map[int64]string{
+		101: "Instant", 102: "Basic Land — Island", 103: "Basic Land — Island",
+		104: "Creature — Bird", 105: "Sorcery", 106: "Land", 107: "Instant",
+		301: "Basic Land — Swamp", 302: "Instant", 303: "Instant",
+		304: "Instant", 305: "Instant", 306: "Instant",
+	}); err != nil {
+		t.Fatalf("UpsertCardTypeLines: %v", err)
+	}
+	if err := store.RefreshMatchAnalytics(ctx, matchID); err != nil {
+		t.Fatalf("RefreshMatchAnalytics: %v", err)
+	}
+
+	// Game one ends on turn three holding 101 and 107, both spells.
+	var gameID int64
+	if err := store.reader().QueryRowContext(ctx, `
+		SELECT id FROM games WHERE match_id = ? AND game_number = 1
+	`, matchID).Scan(&gameID); err != nil {
+		t.Fatalf("load game one: %v", err)
+	}
+	turnStats, err := store.listGameTurnStats(ctx, gameID)
+	if err != nil {
+		t.Fatalf("listGameTurnStats: %v", err)
+	}
+	if last := turnStats[len(turnStats)-1]; last.SpellsInHand == nil || *last.SpellsInHand != 2 {
+		t.Fatalf("turn %d spells in hand = %#v, want 2", last.TurnNumber, last.SpellsInHand)
+	}
+	// The fixture game is over by turn three; stand in a sixth own turn on
+	// the play (game turn 11) to exercise the stuck-spell count.
+	if _, err := store.db.ExecContext(ctx, `
+		INSERT INTO game_turn_stats (game_id, match_id, turn_number, is_player_turn, spells_in_hand)
+		VALUES (?, ?, 11, 1, 4)
+	`, gameID, matchID); err != nil {
+		t.Fatalf("insert turn 11: %v", err)
+	}
+
+	out, err := store.ManaStats(ctx)
+	if err != nil {
+		t.Fatalf("ManaStats: %v", err)
+	}
+	if len(out.Decks) != 1 || out.Decks[0].DeckID != deckID || out.Decks[0].Games != 2 {
+		t.Fatalf("decks = %+v, want the fixture deck with two games", out.Decks)
+	}
+	deck := out.Decks[0]
+	// Keeps of three lands (102, 103, 106) and one (301).
+	if deck.OpeningHandGames != 2 || deck.AverageOpeningLands == nil || *deck.AverageOpeningLands != 2 {
+		t.Fatalf("opening lands = %#v over %d games, want 2 over 2", deck.AverageOpeningLands, deck.OpeningHandGames)
+	}
+	// Turn one passed without a land; game two has no own turn before its last.
+	if deck.TurnStatGames != 1 || deck.AverageMissedDrops == nil || *deck.AverageMissedDrops != 1 {
+		t.Fatalf("missed drops = %#v over %d games, want 1 over 1", deck.AverageMissedDrops, deck.TurnStatGames)
+	}
+	if deck.NotScrewed.Wins != 1 || deck.Screwed.Games != 0 {
+		t.Fatalf("screwed %+v not screwed %+v, want the single missed drop short of screwed", deck.Screwed, deck.NotScrewed)
+	}
+	if deck.StuckSpellGames != 1 || deck.AverageStuckSpells == nil || *deck.AverageStuckSpells != 4 {
+		t.Fatalf("stuck spells = %#v over %d games, want 4 over 1", deck.AverageStuckSpells, deck.StuckSpellGames)
+	}
+	if out.Overall.Games != 2 || out.Overall.StuckSpellGames != 1 {
+		t.Fatalf("overall = %+v, want the same two games", out.Overall)
+	}
+}
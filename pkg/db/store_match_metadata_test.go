@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 )
@@ -41,7 +42,7 @@ func TestOverviewIncludesPlayerName(t *testing.T) {
 		t.Fatalf("Commit: %v", err)
 	}
 
-	overview, err := store.Overview(ctx, 10)
+	overview, err := store.Overview(ctx, 10, OverviewScope{})
 	if err != nil {
 		t.Fatalf("Overview: %v", err)
 	}
@@ -93,7 +94,7 @@ func TestOverviewWinRateIgnoresUnknownResults(t *testing.T) {
 		t.Fatalf("Commit: %v", err)
 	}
 
-	overview, err := store.Overview(ctx, 10)
+	overview, err := store.Overview(ctx, 10, OverviewScope{})
 	if err != nil {
 		t.Fatalf("Overview: %v", err)
 	}
@@ -142,7 +143,7 @@ func TestMatchListDerivesBestOfAndPlayDraw(t *testing.T) {
 		t.Fatalf("Commit: %v", err)
 	}
 
-	rows, err := store.ListMatches(ctx, 10, "", "")
+	rows, err := store.ListMatches(ctx, 10, "", "", false)
 	if err != nil {
 		t.Fatalf("ListMatches: %v", err)
 	}
@@ -240,7 +241,7 @@ func TestLinkMatchToLatestDeckByEventPrefersMostRecentlyObservedDeck(t *testing.
 		t.Fatalf("Commit: %v", err)
 	}
 
-	rows, err := store.ListMatches(ctx, 10, "", "")
+	rows, err := store.ListMatches(ctx, 10, "", "", false)
 	if err != nil {
 		t.Fatalf("ListMatches: %v", err)
 	}
@@ -331,7 +332,7 @@ func TestLinkMatchToLatestDeckByEventRoomStateOverridesPreMatchOnlyOnce(t *testi
 		t.Fatalf("Commit: %v", err)
 	}
 
-	rows, err := store.ListMatches(ctx, 10, "", "")
+	rows, err := store.ListMatches(ctx, 10, "", "", false)
 	if err != nil {
 		t.Fatalf("ListMatches: %v", err)
 	}
@@ -413,7 +414,7 @@ func TestLinkMatchToDeckByArenaDeckIDOverridesEventNameGuess(t *testing.T) {
 		t.Fatalf("Commit: %v", err)
 	}
 
-	rows, err := store.ListMatches(ctx, 10, "", "")
+	rows, err := store.ListMatches(ctx, 10, "", "", false)
 	if err != nil {
 		t.Fatalf("ListMatches: %v", err)
 	}
@@ -432,3 +433,116 @@ func TestLinkMatchToDeckByArenaDeckIDOverridesEventNameGuess(t *testing.T) {
 		t.Fatalf("match_decks rows = %d, want 1", links)
 	}
 }
+
+func TestListMatchesOrdersBySortIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	store := NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	for _, match := range []struct{ id, event, startedAt string }{
+		{"match-old", "Traditional_Ladder", "2026-03-12T19:06:52Z"},
+		{"match-new", "Traditional_Ladder", "2026-03-12T21:06:52Z"},
+		{"match-other", "Play", "2026-03-12T22:06:52Z"},
+	} {
+		if _, err := store.UpsertMatchStart(ctx, tx, match.id, match.event, 1, match.startedAt); err != nil {
+			t.Fatalf("UpsertMatchStart(%s): %v", match.id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	rows, err := store.ListMatches(ctx, 10, "Traditional_Ladder", "", false)
+	if err != nil {
+		t.Fatalf("ListMatches: %v", err)
+	}
+	if len(rows) != 2 || rows[0].ArenaMatchID != "match-new" || rows[1].ArenaMatchID != "match-old" {
+		t.Fatalf("ListMatches = %+v, want match-new then match-old", rows)
+	}
+
+	for _, query := range []string{
+		`SELECT id FROM matches ORDER BY sort_at DESC LIMIT 10`,
+		`SELECT id FROM matches WHERE event_name = 'Play' ORDER BY sort_at DESC LIMIT 10`,
+		`SELECT id FROM matches WHERE result = 'win' ORDER BY sort_at DESC LIMIT 10`,
+	} {
+		plan, err := database.QueryContext(ctx, `EXPLAIN QUERY PLAN `+query)
+		if err != nil {
+			t.Fatalf("EXPLAIN %s: %v", query, err)
+		}
+		var details []string
+		for plan.Next() {
+			var id, parent, notUsed int
+			var detail string
+			if err := plan.Scan(&id, &parent, &notUsed, &detail); err != nil {
+				t.Fatalf("scan plan: %v", err)
+			}
+			details = append(details, detail)
+		}
+		plan.Close()
+		for _, detail := range details {
+			if strings.Contains(detail, "TEMP B-TREE") || !strings.Contains(detail, "INDEX") {
+				t.Fatalf("plan for %q = %v, want an index walk", query, details)
+			}
+		}
+	}
+}
+
+func TestUpdateMatchEndCountsEventRunOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	store := NewStore(database)
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const eventName = "PremierDraft_TMT_20260303"
+	if err := store.UpsertEventRunJoin(ctx, tx, eventName, "Gems", 1500, "2026-03-12T18:00:00Z"); err != nil {
+		t.Fatalf("UpsertEventRunJoin: %v", err)
+	}
+	if _, err := store.UpsertMatchStart(ctx, tx, "match-1", eventName, 1, "2026-03-12T19:06:52Z"); err != nil {
+		t.Fatalf("UpsertMatchStart: %v", err)
+	}
+
+	record := func() (wins, losses int64) {
+		t.Helper()
+		if err := tx.QueryRowContext(ctx, `SELECT wins, losses FROM event_runs WHERE event_name = ?`, eventName).Scan(&wins, &losses); err != nil {
+			t.Fatalf("load event run: %v", err)
+		}
+		return wins, losses
+	}
+
+	// The end event arrives from Player-prev.log, again without team ids,
+	// then in full from Player.log.
+	for _, end := range []struct{ teamID, winningTeamID int64 }{{1, 1}, {0, 0}, {1, 1}} {
+		if _, _, _, err := store.UpdateMatchEnd(ctx, tx, "match-1", end.teamID, end.winningTeamID, 9, 420, "Concede", "2026-03-12T19:13:52Z"); err != nil {
+			t.Fatalf("UpdateMatchEnd: %v", err)
+		}
+	}
+	if wins, losses := record(); wins != 1 || losses != 0 {
+		t.Fatalf("record = %d-%d, want 1-0", wins, losses)
+	}
+
+	if _, _, _, err := store.UpdateMatchEnd(ctx, tx, "match-1", 1, 2, 9, 420, "Concede", "2026-03-12T19:13:52Z"); err != nil {
+		t.Fatalf("UpdateMatchEnd(corrected): %v", err)
+	}
+	if wins, losses := record(); wins != 0 || losses != 1 {
+		t.Fatalf("record after correction = %d-%d, want 0-1", wins, losses)
+	}
+}
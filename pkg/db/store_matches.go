@@ -8,7 +8,8 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/model"
 )
 
 var basicLandNames = map[string]struct{}{
@@ -82,7 +83,7 @@ func (s *Store) UpsertMatchStart(ctx context.Context, tx *sql.Tx, arenaMatchID,
 	}
 
 	startedAt = normalizeTS(startedAt)
-	now := nowUTC()
+	now := s.nowUTC()
 	_, err := tx.ExecContext(ctx, `
 		INSERT INTO matches (
 			arena_match_id, event_name, player_seat_id, started_at, created_at, updated_at
@@ -123,13 +124,51 @@ func (s *Store) UpdateMatchOpponent(ctx context.Context, tx *sql.Tx, arenaMatchI
 			opponent_user_id = COALESCE(NULLIF(?, ''), opponent_user_id),
 			updated_at = ?
 		WHERE arena_match_id = ?
-	`, strings.TrimSpace(opponentName), strings.TrimSpace(opponentUserID), nowUTC(), arenaMatchID)
+	`, strings.TrimSpace(opponentName), strings.TrimSpace(opponentUserID), s.nowUTC(), arenaMatchID)
 	if err != nil {
 		return fmt.Errorf("update match opponent: %w", err)
 	}
 	return nil
 }
 
+// UpdateMatchOpponentRank records the opponent's rank as the match-created
+// message announced it. An empty or "None" class (unranked queues) leaves the
+// stored rank alone.
+func (s *Store) UpdateMatchOpponentRank(ctx context.Context, tx *sql.Tx, arenaMatchID, rankClass string, level int64, mythicPercentile float64, mythicPlace int64) error {
+	rankClass = strings.TrimSpace(rankClass)
+	if rankClass == "" || strings.EqualFold(rankClass, "None") {
+		return nil
+	}
+	var percentile any
+	if rankClass == "Mythic" && mythicPercentile > 0 {
+		percentile = mythicPercentile
+	}
+	_, err := tx.ExecContext(ctx, `
+		UPDATE matches
+		SET opponent_rank_class = ?,
+			opponent_rank_level = ?,
+			opponent_mythic_percentile = ?,
+			opponent_mythic_place = ?,
+			updated_at = ?
+		WHERE arena_match_id = ?
+	`, rankClass, nullableInt(level), percentile, nullableInt(mythicPlace), s.nowUTC(), arenaMatchID)
+	if err != nil {
+		return fmt.Errorf("update match opponent rank: %w", err)
+	}
+	return nil
+}
+
+const upsertMatchOpponentCardInstanceSQL = `
+	INSERT INTO match_opponent_card_instances (
+		match_id, game_number, instance_id, card_id, source, first_seen_at, created_at
+	)
+	SELECT
+		m.id, ?, ?, ?, ?, ?, ?
+	FROM matches m
+	WHERE m.arena_match_id = ?
+	ON CONFLICT(match_id, game_number, instance_id) DO NOTHING
+`
+
 func (s *Store) UpsertMatchOpponentCardInstance(ctx context.Context, tx *sql.Tx, arenaMatchID string, gameNumber, instanceID, cardID int64, firstSeenAt, source string) error {
 	arenaMatchID = strings.TrimSpace(arenaMatchID)
 	if arenaMatchID == "" || instanceID <= 0 || cardID <= 0 {
@@ -139,22 +178,39 @@ func (s *Store) UpsertMatchOpponentCardInstance(ctx context.Context, tx *sql.Tx,
 		gameNumber = 1
 	}
 
-	_, err := tx.ExecContext(ctx, `
-		INSERT INTO match_opponent_card_instances (
-			match_id, game_number, instance_id, card_id, source, first_seen_at, created_at
-		)
-		SELECT
-			m.id, ?, ?, ?, ?, ?, ?
-		FROM matches m
-		WHERE m.arena_match_id = ?
-		ON CONFLICT(match_id, game_number, instance_id) DO NOTHING
-	`, gameNumber, instanceID, cardID, nullIfEmpty(source), nullIfEmpty(normalizeTS(firstSeenAt)), nowUTC(), arenaMatchID)
+	stmt, err := s.txStmt(ctx, tx, upsertMatchOpponentCardInstanceSQL)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, gameNumber, instanceID, cardID, nullIfEmpty(source), nullIfEmpty(normalizeTS(firstSeenAt)), s.nowUTC(), arenaMatchID)
 	if err != nil {
 		return fmt.Errorf("upsert match opponent card instance: %w", err)
 	}
 	return nil
 }
 
+const upsertMatchCardPlaySQL = `
+	INSERT INTO match_card_plays (
+		match_id, game_number, instance_id, card_id, owner_seat_id, first_public_zone, turn_number, phase, source, played_at, created_at
+	)
+	SELECT
+		m.id, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+	FROM matches m
+	WHERE m.arena_match_id = ?
+	ON CONFLICT(match_id, game_number, instance_id) DO UPDATE SET
+		owner_seat_id = COALESCE(match_card_plays.owner_seat_id, excluded.owner_seat_id),
+		turn_number = COALESCE(match_card_plays.turn_number, excluded.turn_number),
+		phase = COALESCE(match_card_plays.phase, excluded.phase),
+		source = COALESCE(match_card_plays.source, excluded.source),
+		played_at = COALESCE(match_card_plays.played_at, excluded.played_at)
+	WHERE
+		match_card_plays.owner_seat_id IS NULL
+		OR match_card_plays.turn_number IS NULL
+		OR match_card_plays.phase IS NULL
+		OR match_card_plays.source IS NULL
+		OR match_card_plays.played_at IS NULL
+`
+
 func (s *Store) UpsertMatchCardPlay(ctx context.Context, tx *sql.Tx, arenaMatchID string, gameNumber, instanceID, cardID, ownerSeatID, turnNumber int64, phase, firstPublicZone, playedAt, source string) error {
 	arenaMatchID = strings.TrimSpace(arenaMatchID)
 	firstPublicZone = strings.TrimSpace(firstPublicZone)
@@ -165,33 +221,34 @@ func (s *Store) UpsertMatchCardPlay(ctx context.Context, tx *sql.Tx, arenaMatchI
 		gameNumber = 1
 	}
 
-	_, err := tx.ExecContext(ctx, `
-		INSERT INTO match_card_plays (
-			match_id, game_number, instance_id, card_id, owner_seat_id, first_public_zone, turn_number, phase, source, played_at, created_at
-		)
-		SELECT
-			m.id, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
-		FROM matches m
-		WHERE m.arena_match_id = ?
-		ON CONFLICT(match_id, game_number, instance_id) DO UPDATE SET
-			owner_seat_id = COALESCE(match_card_plays.owner_seat_id, excluded.owner_seat_id),
-			turn_number = COALESCE(match_card_plays.turn_number, excluded.turn_number),
-			phase = COALESCE(match_card_plays.phase, excluded.phase),
-			source = COALESCE(match_card_plays.source, excluded.source),
-			played_at = COALESCE(match_card_plays.played_at, excluded.played_at)
-		WHERE
-			match_card_plays.owner_seat_id IS NULL
-			OR match_card_plays.turn_number IS NULL
-			OR match_card_plays.phase IS NULL
-			OR match_card_plays.source IS NULL
-			OR match_card_plays.played_at IS NULL
-	`, gameNumber, instanceID, cardID, nullableInt(ownerSeatID), firstPublicZone, nullableInt(turnNumber), nullIfEmpty(phase), nullIfEmpty(source), nullIfEmpty(normalizeTS(playedAt)), nowUTC(), arenaMatchID)
+	stmt, err := s.txStmt(ctx, tx, upsertMatchCardPlaySQL)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, gameNumber, instanceID, cardID, nullableInt(ownerSeatID), firstPublicZone, nullableInt(turnNumber), nullIfEmpty(phase), nullIfEmpty(source), nullIfEmpty(normalizeTS(playedAt)), s.nowUTC(), arenaMatchID)
 	if err != nil {
 		return fmt.Errorf("upsert match card play: %w", err)
 	}
 	return nil
 }
 
+// SetMatchQueueSeconds records how long matchmaking took before the match
+// started. The first measurement wins; later room updates never overwrite it.
+func (s *Store) SetMatchQueueSeconds(ctx context.Context, tx *sql.Tx, arenaMatchID string, seconds int64) error {
+	if seconds < 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+		UPDATE matches
+		SET queue_seconds = ?, updated_at = ?
+		WHERE arena_match_id = ? AND queue_seconds IS NULL
+	`, seconds, s.nowUTC(), arenaMatchID)
+	if err != nil {
+		return fmt.Errorf("set match queue seconds: %w", err)
+	}
+	return nil
+}
+
 func (s *Store) UpdateMatchEnd(ctx context.Context, tx *sql.Tx, arenaMatchID string, teamID, winningTeamID, turnCount, secondsCount int64, winReason, endedAt string) (string, string, bool, error) {
 	endedAt = normalizeTS(endedAt)
 
@@ -206,7 +263,7 @@ func (s *Store) UpdateMatchEnd(ctx context.Context, tx *sql.Tx, arenaMatchID str
 		if _, err := tx.ExecContext(ctx, `
 			INSERT INTO matches (arena_match_id, ended_at, created_at, updated_at)
 			VALUES (?, ?, ?, ?)
-		`, arenaMatchID, endedAt, nowUTC(), nowUTC()); err != nil {
+		`, arenaMatchID, endedAt, s.nowUTC(), s.nowUTC()); err != nil {
 			return "", "", false, fmt.Errorf("create ended-only match: %w", err)
 		}
 		eventName = ""
@@ -222,6 +279,11 @@ func (s *Store) UpdateMatchEnd(ctx context.Context, tx *sql.Tx, arenaMatchID str
 			result = "loss"
 		}
 	}
+	// An end event replayed without team ids (overlapping logs) must not
+	// erase a known result, or the next full copy would count it again.
+	if result == "unknown" && (priorResult == "win" || priorResult == "loss") {
+		result = priorResult
+	}
 
 	_, err = tx.ExecContext(ctx, `
 		UPDATE matches
@@ -232,7 +294,7 @@ func (s *Store) UpdateMatchEnd(ctx context.Context, tx *sql.Tx, arenaMatchID str
 			seconds_count = COALESCE(?, seconds_count),
 			updated_at = ?
 		WHERE arena_match_id = ?
-	`, nullIfEmpty(endedAt), result, nullIfEmpty(winReason), nullableInt(turnCount), nullableInt(secondsCount), nowUTC(), arenaMatchID)
+	`, nullIfEmpty(endedAt), result, nullIfEmpty(winReason), nullableInt(turnCount), nullableInt(secondsCount), s.nowUTC(), arenaMatchID)
 	if err != nil {
 		return "", "", false, fmt.Errorf("update match end: %w", err)
 	}
@@ -244,12 +306,46 @@ func (s *Store) UpdateMatchEnd(ctx context.Context, tx *sql.Tx, arenaMatchID str
 		if err := s.BumpEventRunRecord(ctx, tx, eventName, result); err != nil {
 			return "", "", false, err
 		}
+		// A corrected result moves the match between columns rather than
+		// counting it twice.
+		if priorResult == "win" || priorResult == "loss" {
+			if err := s.adjustEventRunRecord(ctx, tx, eventName, priorResult, -1); err != nil {
+				return "", "", false, err
+			}
+		}
 	}
 
 	return eventName, result, terminalChange, nil
 }
 
-func (s *Store) Overview(ctx context.Context, recentLimit int64) (model.Overview, error) {
+// OverviewScope narrows Overview to a range of UTC days (From and To are
+// inclusive YYYY-MM-DD bounds; either may be empty), a match format, and a
+// queue. Queue is an event name, an event kind such as "ladder" or
+// "premier_draft", or "ranked" for both constructed ladders. The zero value
+// covers everything.
+type OverviewScope struct {
+	From   string
+	To     string
+	Format string
+	Queue  string
+}
+
+// matchesQueue reports whether eventName falls in the scope's queue.
+func (sc OverviewScope) matchesQueue(eventName string) bool {
+	queue := strings.ToLower(strings.TrimSpace(sc.Queue))
+	if queue == "" || strings.EqualFold(eventName, sc.Queue) {
+		return true
+	}
+	kind := eventnames.Kind(eventName)
+	if queue == "ranked" {
+		return kind == "ladder" || kind == "traditional_ladder"
+	}
+	return kind == queue
+}
+
+// Overview totals matches and lists the most recent ones within scope. The
+// limited summary honors the scope's days and queue; runs have no format.
+func (s *Store) Overview(ctx context.Context, recentLimit int64, scope OverviewScope) (model.Overview, error) {
 	out := model.Overview{}
 	if recentLimit <= 0 {
 		recentLimit = 20
@@ -261,104 +357,275 @@ func (s *Store) Overview(ctx context.Context, recentLimit int64) (model.Overview
 	}
 	out.PlayerName = playerName
 
-	err = s.db.QueryRowContext(ctx, `
-		SELECT
-			COUNT(*) AS total,
-			COALESCE(SUM(CASE WHEN result = 'win' THEN 1 ELSE 0 END), 0) AS wins,
-			COALESCE(SUM(CASE WHEN result = 'loss' THEN 1 ELSE 0 END), 0) AS losses
-		FROM matches
-	`).Scan(&out.TotalMatches, &out.Wins, &out.Losses)
+	// match_daily_stats is kept current by triggers on matches, so the
+	// totals sum a row per day instead of scanning every match. Its rows are
+	// keyed by day, event, and format, so the scope applies to them directly;
+	// queues are resolved to event names here since kinds are not in SQL.
+	var conditions []string
+	args := []any{}
+	if scope.From != "" {
+		conditions = append(conditions, "day >= ?")
+		args = append(args, scope.From)
+	}
+	if scope.To != "" {
+		conditions = append(conditions, "day <= ?")
+		args = append(args, scope.To)
+	}
+	if scope.Format != "" {
+		conditions = append(conditions, "format = ? COLLATE NOCASE")
+		args = append(args, scope.Format)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT event_name, SUM(matches), SUM(wins), SUM(losses)
+		FROM match_daily_stats
+		`+where+`
+		GROUP BY event_name
+	`, args...)
 	if err != nil {
 		return out, fmt.Errorf("overview aggregate: %w", err)
 	}
+	defer rows.Close()
+	eventNames := []string{}
+	for rows.Next() {
+		var eventName string
+		var matches, wins, losses int64
+		if err := rows.Scan(&eventName, &matches, &wins, &losses); err != nil {
+			return out, fmt.Errorf("scan overview aggregate: %w", err)
+		}
+		if !scope.matchesQueue(eventName) {
+			continue
+		}
+		eventNames = append(eventNames, eventName)
+		out.TotalMatches += matches
+		out.Wins += wins
+		out.Losses += losses
+	}
+	if err := rows.Err(); err != nil {
+		return out, fmt.Errorf("iterate overview aggregate: %w", err)
+	}
 	// Win rate is over decided matches only; unknown results don't count
 	// against the player.
 	if decided := out.Wins + out.Losses; decided > 0 {
 		out.WinRate = float64(out.Wins) / float64(decided)
 	}
 
-	recent, err := s.ListMatches(ctx, recentLimit, "", "")
+	filter := MatchFilter{From: scope.From, To: scope.To, Format: scope.Format}
+	if strings.TrimSpace(scope.Queue) != "" {
+		filter.EventNames = eventNames
+	}
+	recent, err := s.listMatches(ctx, recentLimit, filter)
 	if err != nil {
 		return out, err
 	}
 	out.Recent = recent
+
+	runs, err := s.ListLimitedRuns(ctx)
+	if err != nil {
+		return out, err
+	}
+	scoped := make([]model.LimitedRun, 0, len(runs))
+	for _, run := range runs {
+		day := run.StartedAt
+		if len(day) > 10 {
+			day = day[:10]
+		}
+		if (scope.From != "" && day < scope.From) || (scope.To != "" && day > scope.To) || !scope.matchesQueue(run.EventName) {
+			continue
+		}
+		scoped = append(scoped, run)
+	}
+	out.Limited = summarizeLimitedRuns(scoped)
 	return out, nil
 }
 
-func (s *Store) ListMatches(ctx context.Context, limit int64, eventName, result string) ([]model.MatchRow, error) {
+// matchRowSelectSQL selects the columns scanMatchRow reads, aliasing matches
+// as m so callers can append their own WHERE/ORDER BY/LIMIT.
+var matchRowSelectSQL = fmt.Sprintf(`
+	SELECT
+		m.id,
+		m.arena_match_id,
+		COALESCE(m.event_name, ''),
+		%s,
+		%s,
+		COALESCE(m.opponent_name, ''),
+		COALESCE(m.started_at, ''),
+		COALESCE(m.ended_at, ''),
+		COALESCE(m.result, 'unknown'),
+		COALESCE(m.win_reason, ''),
+		COALESCE(
+			m.turn_count,
+			(
+				SELECT SUM(game_turns)
+				FROM (
+					SELECT MAX(cp.turn_number) AS game_turns
+					FROM match_card_plays cp
+					WHERE cp.match_id = m.id AND cp.turn_number IS NOT NULL
+					GROUP BY cp.game_number
+				)
+			)
+		),
+		COALESCE(
+			m.seconds_count,
+			CASE
+				WHEN m.started_at IS NOT NULL AND m.ended_at IS NOT NULL THEN
+					CAST(ROUND((julianday(m.ended_at) - julianday(m.started_at)) * 86400.0) AS INTEGER)
+				ELSE NULL
+			END
+		),
+		m.queue_seconds,
+		m.archived != 0,
+		m.source,
+		(
+			SELECT d.id
+			FROM match_decks md
+			JOIN decks d ON d.id = md.deck_id
+			WHERE md.match_id = m.id
+			ORDER BY md.id ASC
+			LIMIT 1
+		),
+		(
+			SELECT d.name
+			FROM match_decks md
+			JOIN decks d ON d.id = md.deck_id
+			WHERE md.match_id = m.id
+			ORDER BY md.id ASC
+			LIMIT 1
+		),
+		(
+			SELECT md.deck_version_id
+			FROM match_decks md
+			WHERE md.match_id = m.id
+			ORDER BY md.id ASC
+			LIMIT 1
+		),
+		(
+			SELECT dv.version_number
+			FROM match_decks md
+			JOIN deck_versions dv ON dv.id = md.deck_version_id
+			WHERE md.match_id = m.id
+			ORDER BY md.id ASC
+			LIMIT 1
+		)
+	FROM matches m
+`, matchBestOfSQL, matchPlayDrawSQL)
+
+func scanMatchRow(rows *sql.Rows) (model.MatchRow, error) {
+	var r model.MatchRow
+	err := rows.Scan(
+		&r.ID,
+		&r.ArenaMatchID,
+		&r.EventName,
+		&r.BestOf,
+		&r.PlayDraw,
+		&r.Opponent,
+		&r.StartedAt,
+		&r.EndedAt,
+		&r.Result,
+		&r.WinReason,
+		&r.TurnCount,
+		&r.SecondsCount,
+		&r.QueueSeconds,
+		&r.Archived,
+		&r.Source,
+		&r.DeckID,
+		&r.DeckName,
+		&r.DeckVersionID,
+		&r.DeckVersionNumber,
+	)
+	return r, err
+}
+
+// MatchFilter narrows match lists and exports to one event and/or result.
+// Archived matches are left out unless IncludeArchived is set. From and To
+// are inclusive UTC days (YYYY-MM-DD). A non-nil EventNames keeps only
+// matches from those events, so an empty one matches nothing.
+type MatchFilter struct {
+	EventName       string
+	Result          string
+	IncludeArchived bool
+	From            string
+	To              string
+	Format          string
+	EventNames      []string
+}
+
+// where renders the filter as a WHERE clause over matches aliased m, or ""
+// when nothing is filtered. Only the filters in use go into the clause so
+// SQLite can pick the matching (event_name, sort_at) or (result, sort_at)
+// index.
+func (f MatchFilter) where() (string, []any) {
+	var conditions []string
+	args := []any{}
+	if !f.IncludeArchived {
+		conditions = append(conditions, "m.archived = 0")
+	}
+	if f.EventName != "" {
+		conditions = append(conditions, "m.event_name = ?")
+		args = append(args, f.EventName)
+	}
+	if f.Result != "" {
+		conditions = append(conditions, "m.result = ?")
+		args = append(args, f.Result)
+	}
+	// sort_at begins with its day, so day bounds stay on idx_matches_sort_at.
+	if f.From != "" {
+		conditions = append(conditions, "m.sort_at >= ?")
+		args = append(args, f.From)
+	}
+	if f.To != "" {
+		conditions = append(conditions, "m.sort_at < ? || '~'")
+		args = append(args, f.To)
+	}
+	if f.Format != "" {
+		conditions = append(conditions, "m.format = ? COLLATE NOCASE")
+		args = append(args, f.Format)
+	}
+	if f.EventNames != nil {
+		if len(f.EventNames) == 0 {
+			conditions = append(conditions, "0")
+		} else {
+			placeholders := make([]string, 0, len(f.EventNames))
+			for _, eventName := range f.EventNames {
+				placeholders = append(placeholders, "?")
+				args = append(args, eventName)
+			}
+			conditions = append(conditions, "COALESCE(m.event_name, '') IN ("+strings.Join(placeholders, ", ")+")")
+		}
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ListMatches returns matches newest first, optionally filtered by event and
+// result. Archived matches are left out unless includeArchived is set.
+func (s *Store) ListMatches(ctx context.Context, limit int64, eventName, result string, includeArchived bool) ([]model.MatchRow, error) {
+	return s.ListMatchesFiltered(ctx, limit, MatchFilter{EventName: eventName, Result: result, IncludeArchived: includeArchived})
+}
+
+// ListMatchesFiltered returns up to limit matches passing filter, newest
+// first; a limit of 0 or less lists 200.
+func (s *Store) ListMatchesFiltered(ctx context.Context, limit int64, filter MatchFilter) ([]model.MatchRow, error) {
 	if limit <= 0 {
 		limit = 200
 	}
-	query := fmt.Sprintf(`
-		SELECT
-			m.id,
-			m.arena_match_id,
-			COALESCE(m.event_name, ''),
-			%s,
-			%s,
-			COALESCE(m.opponent_name, ''),
-			COALESCE(m.started_at, ''),
-			COALESCE(m.ended_at, ''),
-			COALESCE(m.result, 'unknown'),
-			COALESCE(m.win_reason, ''),
-			COALESCE(
-				m.turn_count,
-				(
-					SELECT SUM(game_turns)
-					FROM (
-						SELECT MAX(cp.turn_number) AS game_turns
-						FROM match_card_plays cp
-						WHERE cp.match_id = m.id AND cp.turn_number IS NOT NULL
-						GROUP BY cp.game_number
-					)
-				)
-			),
-			COALESCE(
-				m.seconds_count,
-				CASE
-					WHEN m.started_at IS NOT NULL AND m.ended_at IS NOT NULL THEN
-						CAST(ROUND((julianday(m.ended_at) - julianday(m.started_at)) * 86400.0) AS INTEGER)
-					ELSE NULL
-				END
-			),
-			(
-				SELECT d.id
-				FROM match_decks md
-				JOIN decks d ON d.id = md.deck_id
-				WHERE md.match_id = m.id
-				ORDER BY md.id ASC
-				LIMIT 1
-			),
-			(
-				SELECT d.name
-				FROM match_decks md
-				JOIN decks d ON d.id = md.deck_id
-				WHERE md.match_id = m.id
-				ORDER BY md.id ASC
-				LIMIT 1
-			),
-			(
-				SELECT md.deck_version_id
-				FROM match_decks md
-				WHERE md.match_id = m.id
-				ORDER BY md.id ASC
-				LIMIT 1
-			),
-			(
-				SELECT dv.version_number
-				FROM match_decks md
-				JOIN deck_versions dv ON dv.id = md.deck_version_id
-				WHERE md.match_id = m.id
-				ORDER BY md.id ASC
-				LIMIT 1
-			)
-		FROM matches m
-		WHERE (? = '' OR m.event_name = ?)
-		  AND (? = '' OR m.result = ?)
-		ORDER BY COALESCE(m.started_at, m.ended_at, m.updated_at) DESC
+	return s.listMatches(ctx, limit, filter)
+}
+
+// listMatches returns up to limit matches passing filter, newest first.
+func (s *Store) listMatches(ctx context.Context, limit int64, filter MatchFilter) ([]model.MatchRow, error) {
+	where, args := filter.where()
+	args = append(args, limit)
+	query := matchRowSelectSQL + where + `
+		ORDER BY m.sort_at DESC
 		LIMIT ?
-	`, matchBestOfSQL, matchPlayDrawSQL)
-	rows, err := s.db.QueryContext(ctx, query, eventName, eventName, result, result, limit)
+	`
+	rows, err := s.reader().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list matches: %w", err)
 	}
@@ -366,25 +633,8 @@ func (s *Store) ListMatches(ctx context.Context, limit int64, eventName, result
 
 	resultRows := make([]model.MatchRow, 0, limit)
 	for rows.Next() {
-		var r model.MatchRow
-		if err := rows.Scan(
-			&r.ID,
-			&r.ArenaMatchID,
-			&r.EventName,
-			&r.BestOf,
-			&r.PlayDraw,
-			&r.Opponent,
-			&r.StartedAt,
-			&r.EndedAt,
-			&r.Result,
-			&r.WinReason,
-			&r.TurnCount,
-			&r.SecondsCount,
-			&r.DeckID,
-			&r.DeckName,
-			&r.DeckVersionID,
-			&r.DeckVersionNumber,
-		); err != nil {
+		r, err := scanMatchRow(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan match row: %w", err)
 		}
 		resultRows = append(resultRows, r)
@@ -435,7 +685,7 @@ func (s *Store) ListMatchDeckCardQuantities(ctx context.Context, matchIDs []int6
 			GROUP BY m.id, sc.card_id
 		`, strings.Join(placeholders, ","))
 
-		rows, err := s.db.QueryContext(ctx, query, args...)
+		rows, err := s.reader().QueryContext(ctx, query, args...)
 		if err != nil {
 			return nil, fmt.Errorf("list match deck card quantities: %w", err)
 		}
@@ -492,7 +742,7 @@ func (s *Store) ListMatchOpponentCardQuantities(ctx context.Context, matchIDs []
 			GROUP BY match_id, card_id
 		`, strings.Join(placeholders, ","))
 
-		rows, err := s.db.QueryContext(ctx, query, args...)
+		rows, err := s.reader().QueryContext(ctx, query, args...)
 		if err != nil {
 			return nil, fmt.Errorf("list opponent observed card quantities: %w", err)
 		}
@@ -591,7 +841,7 @@ func (s *Store) GetMatchDetail(ctx context.Context, matchID int64) (model.MatchD
 		LIMIT 1
 	`, matchBestOfSQL, matchPlayDrawSQL)
 
-	err := s.db.QueryRowContext(ctx, query, matchID).Scan(
+	err := s.reader().QueryRowContext(ctx, query, matchID).Scan(
 		&out.Match.ID,
 		&out.Match.ArenaMatchID,
 		&out.Match.EventName,
@@ -616,7 +866,32 @@ func (s *Store) GetMatchDetail(ctx context.Context, matchID int64) (model.MatchD
 		return out, fmt.Errorf("get match detail: %w", err)
 	}
 
-	rows, err := s.db.QueryContext(ctx, `
+	out.OpponentObservedCards, err = s.ListOpponentObservedCards(ctx, matchID)
+	if err != nil {
+		return out, err
+	}
+
+	out.CardPlays, err = s.ListMatchCardPlays(ctx, matchID)
+	if err != nil {
+		return out, err
+	}
+	out.Games, err = s.ListMatchGames(ctx, matchID)
+	if err != nil {
+		return out, err
+	}
+	out.Coverage, err = s.GetMatchAnalyticsCoverage(ctx, matchID)
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// ListOpponentObservedCards estimates the opponent's decklist for a match
+// from the cards they revealed, most copies first.
+func (s *Store) ListOpponentObservedCards(ctx context.Context, matchID int64) ([]model.OpponentObservedCardRow, error) {
+	var cards []model.OpponentObservedCardRow
+	rows, err := s.reader().QueryContext(ctx, `
 		WITH per_game AS (
 			SELECT
 				oc.card_id,
@@ -645,14 +920,14 @@ func (s *Store) GetMatchDetail(ctx context.Context, matchID int64) (model.MatchD
 		ORDER BY quantity DESC, cc.name ASC, f.card_id ASC
 	`, matchID, matchID)
 	if err != nil {
-		return out, fmt.Errorf("get observed opponent cards: %w", err)
+		return nil, fmt.Errorf("get observed opponent cards: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var card model.OpponentObservedCardRow
 		if err := rows.Scan(&card.CardID, &card.Quantity, &card.CardName); err != nil {
-			return out, fmt.Errorf("scan observed opponent card: %w", err)
+			return nil, fmt.Errorf("scan observed opponent card: %w", err)
 		}
 		// Only basic lands can legally appear more than four times; cap everything
 		// else so a frameless match's inflated distinct-instance fallback still
@@ -660,13 +935,13 @@ func (s *Store) GetMatchDetail(ctx context.Context, matchID int64) (model.MatchD
 		if card.Quantity > 4 && !isBasicLandName(card.CardName) {
 			card.Quantity = 4
 		}
-		out.OpponentObservedCards = append(out.OpponentObservedCards, card)
+		cards = append(cards, card)
 	}
 	if err := rows.Err(); err != nil {
-		return out, fmt.Errorf("iterate observed opponent cards: %w", err)
+		return nil, fmt.Errorf("iterate observed opponent cards: %w", err)
 	}
-	sort.SliceStable(out.OpponentObservedCards, func(i, j int) bool {
-		a, b := out.OpponentObservedCards[i], out.OpponentObservedCards[j]
+	sort.SliceStable(cards, func(i, j int) bool {
+		a, b := cards[i], cards[j]
 		if a.Quantity != b.Quantity {
 			return a.Quantity > b.Quantity
 		}
@@ -675,25 +950,11 @@ func (s *Store) GetMatchDetail(ctx context.Context, matchID int64) (model.MatchD
 		}
 		return a.CardID < b.CardID
 	})
-
-	out.CardPlays, err = s.ListMatchCardPlays(ctx, matchID)
-	if err != nil {
-		return out, err
-	}
-	out.Games, err = s.ListMatchGames(ctx, matchID)
-	if err != nil {
-		return out, err
-	}
-	out.Coverage, err = s.GetMatchAnalyticsCoverage(ctx, matchID)
-	if err != nil {
-		return out, err
-	}
-
-	return out, nil
+	return cards, nil
 }
 
 func (s *Store) ListMatchCardPlays(ctx context.Context, matchID int64) ([]model.MatchCardPlayRow, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			cp.id,
 			cp.game_number,
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 // MatchupMatchRow is one deck-linked match plus the fields matchup
@@ -24,15 +24,15 @@ type MatchupMatchRow struct {
 
 // ListMatchupMatchRows returns matches linked to a deck, newest first; a
 // positive deckID restricts to that deck. Matches without a deck link are
-// excluded: matchup aggregation is deck-scoped.
+// excluded: matchup aggregation is deck-scoped. Archived matches are too.
 func (s *Store) ListMatchupMatchRows(ctx context.Context, deckID int64) ([]MatchupMatchRow, error) {
-	where := ""
+	where := "WHERE m.archived = 0"
 	args := []any{}
 	if deckID > 0 {
-		where = "WHERE md.deck_id = ?"
+		where += " AND md.deck_id = ?"
 		args = append(args, deckID)
 	}
-	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+	rows, err := s.reader().QueryContext(ctx, fmt.Sprintf(`
 		SELECT
 			m.id, md.deck_id, COALESCE(d.name, d.arena_deck_id), COALESCE(d.format, ''),
 			COALESCE(m.event_name, ''), COALESCE(m.opponent_name, ''),
@@ -41,7 +41,7 @@ func (s *Store) ListMatchupMatchRows(ctx context.Context, deckID int64) ([]Match
 		JOIN match_decks md ON md.match_id = m.id
 		JOIN decks d ON d.id = md.deck_id
 		%s
-		ORDER BY COALESCE(m.started_at, m.ended_at, m.updated_at) DESC, m.id DESC
+		ORDER BY m.sort_at DESC, m.id DESC
 	`, where), args...)
 	if err != nil {
 		return nil, fmt.Errorf("list matchup matches: %w", err)
@@ -84,7 +84,7 @@ func (s *Store) ListMatchGameSummaries(ctx context.Context) (map[int64]model.Mat
 		resultRecordColumns("g.play_draw = 'play'"),
 		resultRecordColumns("g.play_draw = 'draw'"))
 
-	rows, err := s.db.QueryContext(ctx, query)
+	rows, err := s.reader().QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("list match game summaries: %w", err)
 	}
@@ -122,7 +122,7 @@ func (s *Store) ListMatchGameSummaries(ctx context.Context) (map[int64]model.Mat
 // ListMatchOpponentArchetypeOverrides returns every manual archetype
 // correction, keyed by match id.
 func (s *Store) ListMatchOpponentArchetypeOverrides(ctx context.Context) (map[int64]string, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT match_id, archetype FROM match_opponent_archetype_overrides
 	`)
 	if err != nil {
@@ -150,15 +150,15 @@ func (s *Store) ListMatchOpponentArchetypeOverrides(ctx context.Context) (map[in
 func (s *Store) SetMatchOpponentArchetypeOverride(ctx context.Context, matchID int64, archetype string) error {
 	archetype = strings.ToLower(strings.TrimSpace(archetype))
 	if archetype == "" {
-		if _, err := s.db.ExecContext(ctx, `
+		if _, err := s.exec(ctx, `
 			DELETE FROM match_opponent_archetype_overrides WHERE match_id = ?
 		`, matchID); err != nil {
 			return fmt.Errorf("clear opponent archetype override: %w", err)
 		}
 		return nil
 	}
-	now := nowUTC()
-	if _, err := s.db.ExecContext(ctx, `
+	now := s.nowUTC()
+	if _, err := s.exec(ctx, `
 		INSERT INTO match_opponent_archetype_overrides (match_id, archetype, created_at, updated_at)
 		VALUES (?, ?, ?, ?)
 		ON CONFLICT(match_id) DO UPDATE SET
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// Milestone kinds, as reported in model.Milestone.Kind.
+const (
+	MilestoneWinStreak         = "win_streak"
+	MilestoneArchetypeFirstWin = "archetype_first_win"
+	MilestoneTrophy            = "trophy"
+	MilestoneWins              = "wins"
+)
+
+const (
+	// milestoneMinWinStreak is the shortest win streak worth a milestone;
+	// a new personal best below it is too common to celebrate.
+	milestoneMinWinStreak = 3
+)
+
+// milestoneWinCounts are the career win totals that count as milestones.
+var milestoneWinCounts = []int64{10, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// ListMilestones computes the current and best win streaks and every
+// milestone reached, newest first: each new best win streak of at least
+// milestoneMinWinStreak, the first win against each opponent archetype,
+// draft trophies, and career win totals. A draw ends a streak.
+//
+// archetypes labels each match's opponent; "unknown" and unlabeled matches
+// are skipped. With nil archetypes only manual overrides count, since
+// derived labels need card metadata the store may not have cached.
+// Archived matches are left out.
+func (s *Store) ListMilestones(ctx context.Context, archetypes map[int64]string) (model.MilestoneStats, error) {
+	out := model.MilestoneStats{Milestones: []model.Milestone{}}
+	if archetypes == nil {
+		overrides, err := s.ListMatchOpponentArchetypeOverrides(ctx)
+		if err != nil {
+			return out, err
+		}
+		archetypes = overrides
+	}
+	runs, err := s.ListLimitedDraftRuns(ctx)
+	if err != nil {
+		return out, err
+	}
+	trophiesByMatch := make(map[int64][]LimitedDraftRun)
+	for _, run := range runs {
+		if run.LastMatchID > 0 && run.Wins >= LimitedTrophyWins(run.EventName) {
+			trophiesByMatch[run.LastMatchID] = append(trophiesByMatch[run.LastMatchID], run)
+		}
+	}
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT m.id, COALESCE(m.event_name, ''), COALESCE(m.opponent_name, ''), m.result,
+			COALESCE(NULLIF(m.ended_at, ''), m.started_at, '')
+		FROM matches m
+		WHERE m.archived = 0
+		  AND m.result IN ('win', 'loss', 'draw')
+		ORDER BY julianday(COALESCE(m.started_at, m.ended_at)), m.id
+	`)
+	if err != nil {
+		return out, fmt.Errorf("list milestone matches: %w", err)
+	}
+	defer rows.Close()
+
+	var wins int64
+	beaten := map[string]bool{}
+	for rows.Next() {
+		var matchID int64
+		var eventName, opponent, result, achievedAt string
+		if err := rows.Scan(&matchID, &eventName, &opponent, &result, &achievedAt); err != nil {
+			return out, fmt.Errorf("scan milestone match: %w", err)
+		}
+		milestone := func(key, kind, title string, value int64) model.Milestone {
+			var details []string
+			if opponent != "" {
+				details = append(details, "vs "+opponent)
+			}
+			if event := eventnames.Display(eventName, eventnames.DefaultLanguage, ""); event != "" {
+				details = append(details, event)
+			}
+			return model.Milestone{
+				Key:        key,
+				Kind:       kind,
+				Title:      title,
+				Detail:     strings.Join(details, " · "),
+				Value:      value,
+				MatchID:    matchID,
+				EventName:  eventName,
+				AchievedAt: achievedAt,
+			}
+		}
+
+		if result != "win" {
+			out.CurrentWinStreak = 0
+		} else {
+			wins++
+			out.CurrentWinStreak++
+			if out.CurrentWinStreak > out.BestWinStreak {
+				out.BestWinStreak = out.CurrentWinStreak
+				if out.BestWinStreak >= milestoneMinWinStreak {
+					out.Milestones = append(out.Milestones, milestone(
+						fmt.Sprintf("%s:%d", MilestoneWinStreak, out.BestWinStreak), MilestoneWinStreak,
+						fmt.Sprintf("New best win streak: %d", out.BestWinStreak), out.BestWinStreak))
+				}
+			}
+			if archetype := archetypes[matchID]; archetype != "" && archetype != "unknown" && !beaten[archetype] {
+				beaten[archetype] = true
+				out.Milestones = append(out.Milestones, milestone(
+					MilestoneArchetypeFirstWin+":"+archetype, MilestoneArchetypeFirstWin,
+					"First win vs "+archetype, 0))
+			}
+			if slices.Contains(milestoneWinCounts, wins) {
+				out.Milestones = append(out.Milestones, milestone(
+					fmt.Sprintf("%s:%d", MilestoneWins, wins), MilestoneWins,
+					fmt.Sprintf("%d wins", wins), wins))
+			}
+		}
+		for _, run := range trophiesByMatch[matchID] {
+			out.Milestones = append(out.Milestones, milestone(
+				fmt.Sprintf("%s:%d", MilestoneTrophy, run.SessionID), MilestoneTrophy,
+				fmt.Sprintf("%d-win trophy", run.Wins), run.Wins))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return out, fmt.Errorf("iterate milestone matches: %w", err)
+	}
+	slices.Reverse(out.Milestones)
+	return out, nil
+}
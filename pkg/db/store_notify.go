@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// FinishedMatch is a match with a result, as tail's desktop notifications
+// describe it. Rank is the ladder rank recorded after the match ("Gold 2"),
+// empty until Arena has reported it.
+type FinishedMatch struct {
+	ID        int64
+	EventName string
+	Opponent  string
+	Result    string
+	EndedAt   string
+	Rank      string
+}
+
+// CompletedDraft is a draft session Arena has marked complete.
+type CompletedDraft struct {
+	ID          int64
+	EventName   string
+	CompletedAt string
+	Picks       int64
+}
+
+// RecentFinishedMatches returns the last limit matches with a result,
+// newest first.
+func (s *Store) RecentFinishedMatches(ctx context.Context, limit int) ([]FinishedMatch, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT
+			m.id,
+			COALESCE(m.event_name, ''),
+			COALESCE(m.opponent_name, ''),
+			m.result,
+			COALESCE(m.ended_at, ''),
+			mrs.id IS NOT NULL,
+			mrs.constructed_season_ordinal,
+			COALESCE(mrs.constructed_rank_class, ''),
+			mrs.constructed_level,
+			mrs.limited_season_ordinal,
+			COALESCE(mrs.limited_rank_class, ''),
+			mrs.limited_level
+		FROM matches m
+		LEFT JOIN match_rank_snapshots mrs ON mrs.match_id = m.id
+		WHERE m.result IN ('win', 'loss', 'draw')
+		ORDER BY m.id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list recent finished matches: %w", err)
+	}
+	defer rows.Close()
+
+	var out []FinishedMatch
+	for rows.Next() {
+		var row FinishedMatch
+		var hasRank bool
+		var constructedSeason, constructedLevel, limitedSeason, limitedLevel sql.NullInt64
+		var constructedClass, limitedClass string
+		if err := rows.Scan(
+			&row.ID, &row.EventName, &row.Opponent, &row.Result, &row.EndedAt,
+			&hasRank,
+			&constructedSeason, &constructedClass, &constructedLevel,
+			&limitedSeason, &limitedClass, &limitedLevel,
+		); err != nil {
+			return nil, fmt.Errorf("scan finished match: %w", err)
+		}
+		if hasRank {
+			rank := model.RankState{RankClass: constructedClass, SeasonOrdinal: nullInt64Ptr(constructedSeason), Level: nullInt64Ptr(constructedLevel)}
+			if eventUsesLimitedLadder(row.EventName) {
+				rank = model.RankState{RankClass: limitedClass, SeasonOrdinal: nullInt64Ptr(limitedSeason), Level: nullInt64Ptr(limitedLevel)}
+			}
+			row.Rank = rankLabel(rank)
+		}
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate finished matches: %w", err)
+	}
+	return out, nil
+}
+
+// RecentCompletedDrafts returns the last limit completed draft sessions,
+// newest first.
+func (s *Store) RecentCompletedDrafts(ctx context.Context, limit int) ([]CompletedDraft, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT
+			ds.id,
+			COALESCE(ds.event_name, ''),
+			ds.completed_at,
+			(SELECT COUNT(*) FROM draft_picks dp WHERE dp.draft_session_id = ds.id)
+		FROM draft_sessions ds
+		WHERE COALESCE(ds.completed_at, '') <> ''
+		ORDER BY ds.id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list recent completed drafts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CompletedDraft
+	for rows.Next() {
+		var row CompletedDraft
+		if err := rows.Scan(&row.ID, &row.EventName, &row.CompletedAt, &row.Picks); err != nil {
+			return nil, fmt.Errorf("scan completed draft: %w", err)
+		}
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate completed drafts: %w", err)
+	}
+	return out, nil
+}
+
+// eventUsesLimitedLadder reports whether a match moves the limited rank
+// rather than the constructed one.
+func eventUsesLimitedLadder(eventName string) bool {
+	lower := strings.ToLower(eventName)
+	return strings.Contains(lower, "draft") || strings.Contains(lower, "sealed")
+}
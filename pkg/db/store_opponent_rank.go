@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// opponentRankTiers is the scale opponent ranks are weighed on, bottom up.
+// Limited opponents use the same names without Spark.
+var opponentRankTiers = rankLadders[0].tiers
+
+// opponentRankAcc sums one group's matches against ranked opponents. A win
+// adds the opponent's weight (tier position + 1) to won; a loss adds the
+// mirrored weight to lost, so beating strong opponents and losing to weak
+// ones both count for more.
+type opponentRankAcc struct {
+	deckID    int64
+	deckName  string
+	format    string
+	record    model.RecordAgg
+	tierSum   float64
+	won, lost float64
+	byTier    []model.RecordAgg
+}
+
+func newOpponentRankAcc(deckID int64, deckName, format string) *opponentRankAcc {
+	return &opponentRankAcc{deckID: deckID, deckName: deckName, format: format, byTier: make([]model.RecordAgg, len(opponentRankTiers))}
+}
+
+func (a *opponentRankAcc) add(result string, tier int, level sql.NullInt64) {
+	var match model.RecordAgg
+	weight := float64(tier + 1)
+	switch result {
+	case "win":
+		match.Wins = 1
+		a.won += weight
+	case "loss":
+		match.Losses = 1
+		a.lost += float64(len(opponentRankTiers)) + 1 - weight
+	case "draw":
+		match.Draws = 1
+	}
+	match.Games = 1
+	addRecordAgg(&a.record, match)
+	addRecordAgg(&a.byTier[tier], match)
+	// Levels run from 4 at the bottom of a tier to 1 at the top.
+	position := float64(tier)
+	if level.Valid && opponentRankTiers[tier] != "Mythic" {
+		position += float64(4-min(max(level.Int64, 1), 4)) / 4
+	}
+	a.tierSum += position
+}
+
+func (a *opponentRankAcc) group() model.OpponentRankGroup {
+	out := model.OpponentRankGroup{
+		DeckID:   a.deckID,
+		DeckName: a.deckName,
+		Format:   a.format,
+		Matches:  model.WinRateInterval{Record: a.record},
+		ByTier:   []model.OpponentTierRecord{},
+	}
+	if a.record.Games > 0 {
+		average := a.tierSum / float64(a.record.Games)
+		out.AverageOpponentTier = &average
+		out.AverageOpponentLabel = opponentRankTiers[min(int(math.Floor(average)), len(opponentRankTiers)-1)]
+	}
+	if a.won+a.lost > 0 {
+		weighted := a.won / (a.won + a.lost)
+		out.WeightedWinRate = &weighted
+	}
+	for tier, record := range a.byTier {
+		if record.Games > 0 {
+			out.ByTier = append(out.ByTier, model.OpponentTierRecord{Tier: opponentRankTiers[tier], Record: record})
+		}
+	}
+	return out
+}
+
+// OpponentRankRecords weighs match results by the opponent's announced rank,
+// overall and per deck (a match counts toward its first linked deck; busiest
+// deck first): the plain record, the average opponent tier, the record
+// against each tier, and a rank-weighted win rate in which a win counts the
+// opponent's weight (1 for Spark up to 7 for Mythic) and a loss the mirrored
+// weight (7 for Spark down to 1 for Mythic). Against Gold opponents the
+// weighted rate equals the plain one; farming lower tiers pulls it down and
+// holding up against higher ones pushes it up. Archived and undecided matches
+// are left out, and matches without a known opponent rank are only counted.
+// The win rate intervals are left for the caller to fill in.
+func (s *Store) OpponentRankRecords(ctx context.Context) (model.OpponentRankStats, error) {
+	out := model.OpponentRankStats{Tiers: opponentRankTiers, Decks: []model.OpponentRankGroup{}}
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT
+			m.result,
+			COALESCE(md.deck_id, 0),
+			COALESCE(d.name, ''),
+			COALESCE(d.format, ''),
+			COALESCE(m.opponent_rank_class, ''),
+			m.opponent_rank_level
+		FROM matches m
+		LEFT JOIN (
+			SELECT match_id, MIN(deck_id) AS deck_id
+			FROM match_decks
+			GROUP BY match_id
+		) md ON md.match_id = m.id
+		LEFT JOIN decks d ON d.id = md.deck_id
+		WHERE m.archived = 0
+		  AND m.result IN ('win', 'loss', 'draw')
+		ORDER BY m.id
+	`)
+	if err != nil {
+		return out, fmt.Errorf("list opponent rank records: %w", err)
+	}
+	defer rows.Close()
+
+	overall := newOpponentRankAcc(0, "", "")
+	decks := make(map[int64]*opponentRankAcc)
+	for rows.Next() {
+		var result, deckName, format, rankClass string
+		var deckID int64
+		var level sql.NullInt64
+		if err := rows.Scan(&result, &deckID, &deckName, &format, &rankClass, &level); err != nil {
+			return out, fmt.Errorf("scan opponent rank record: %w", err)
+		}
+		tier := slices.Index(opponentRankTiers, rankClass)
+		if tier < 0 {
+			out.MatchesWithoutRank++
+			continue
+		}
+		overall.add(result, tier, level)
+		if deckID == 0 {
+			continue
+		}
+		acc, ok := decks[deckID]
+		if !ok {
+			acc = newOpponentRankAcc(deckID, deckName, format)
+			decks[deckID] = acc
+		}
+		acc.add(result, tier, level)
+	}
+	if err := rows.Err(); err != nil {
+		return out, fmt.Errorf("iterate opponent rank records: %w", err)
+	}
+
+	out.Overall = overall.group()
+	for _, acc := range decks {
+		out.Decks = append(out.Decks, acc.group())
+	}
+	sort.Slice(out.Decks, func(i, j int) bool {
+		a, b := out.Decks[i], out.Decks[j]
+		if a.Matches.Record.Games != b.Matches.Record.Games {
+			return a.Matches.Record.Games > b.Matches.Record.Games
+		}
+		return a.DeckID < b.DeckID
+	})
+	return out, nil
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Record is a win/loss/draw tally, as the stream overlay shows it.
+type Record struct {
+	Wins   int64 `json:"wins"`
+	Losses int64 `json:"losses"`
+	Draws  int64 `json:"draws"`
+}
+
+// RecordSince tallies the results of matches that ended at or after since,
+// leaving out archived ones.
+func (s *Store) RecordSince(ctx context.Context, since time.Time) (Record, error) {
+	var out Record
+	err := s.reader().QueryRowContext(ctx, `
+		SELECT
+			COALESCE(SUM(CASE WHEN result = 'win' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN result = 'loss' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN result = 'draw' THEN 1 ELSE 0 END), 0)
+		FROM matches
+		WHERE result IN ('win', 'loss', 'draw')
+		  AND archived = 0
+		  AND julianday(ended_at) >= julianday(?)
+	`, since.UTC().Format(time.RFC3339Nano)).Scan(&out.Wins, &out.Losses, &out.Draws)
+	if err != nil {
+		return Record{}, fmt.Errorf("record since %s: %w", since.Format(time.RFC3339), err)
+	}
+	return out, nil
+}
+
+// LatestDraft is the most recently started draft session.
+type LatestDraft struct {
+	ID          int64
+	EventName   string
+	CompletedAt string
+}
+
+// LatestDraftSession returns the newest draft session; found is false when
+// nothing has been drafted.
+func (s *Store) LatestDraftSession(ctx context.Context) (draft LatestDraft, found bool, err error) {
+	err = s.reader().QueryRowContext(ctx, `
+		SELECT id, COALESCE(event_name, ''), COALESCE(completed_at, '')
+		FROM draft_sessions
+		ORDER BY id DESC
+		LIMIT 1
+	`).Scan(&draft.ID, &draft.EventName, &draft.CompletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return LatestDraft{}, false, nil
+	}
+	if err != nil {
+		return LatestDraft{}, false, fmt.Errorf("get latest draft session: %w", err)
+	}
+	return draft, true, nil
+}
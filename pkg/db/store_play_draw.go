@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// PlayDrawRecords tallies game results on the play and on the draw, across
+// every game and per deck (decks with no game of known play/draw are left
+// out, busiest deck first). Archived matches are left out. The win rates
+// and intervals are left for the caller to fill in.
+func (s *Store) PlayDrawRecords(ctx context.Context) (model.PlayDrawStats, error) {
+	out := model.PlayDrawStats{Decks: []model.DeckPlayDrawSplit{}}
+
+	var onPlay, onDraw recordScanner
+	var unknown int64
+	query := fmt.Sprintf(`
+		SELECT
+			%s,
+			%s,
+			COALESCE(SUM(CASE WHEN COALESCE(g.play_draw, '') NOT IN ('play', 'draw') THEN 1 ELSE 0 END), 0)
+		FROM games g
+		JOIN matches m ON m.id = g.match_id
+		WHERE m.archived = 0
+	`, resultRecordColumns("g.play_draw = 'play'"), resultRecordColumns("g.play_draw = 'draw'"))
+	dests := append(onPlay.dests(), onDraw.dests()...)
+	dests = append(dests, &unknown)
+	if err := s.reader().QueryRowContext(ctx, query).Scan(dests...); err != nil {
+		return out, fmt.Errorf("load play/draw record: %w", err)
+	}
+	out.Overall.OnPlay.Record = onPlay.agg()
+	out.Overall.OnDraw.Record = onDraw.agg()
+	out.GamesWithoutPlayDraw = unknown
+
+	rows, err := s.reader().QueryContext(ctx, fmt.Sprintf(`
+		SELECT d.id, COALESCE(d.name, ''), COALESCE(d.format, ''),
+			%s,
+			%s
+		FROM games g
+		JOIN matches m ON m.id = g.match_id
+		JOIN match_decks md ON md.match_id = g.match_id
+		JOIN decks d ON d.id = md.deck_id
+		WHERE m.archived = 0
+		  AND g.play_draw IN ('play', 'draw')
+		GROUP BY d.id
+		ORDER BY COUNT(*) DESC, d.id
+	`, resultRecordColumns("g.play_draw = 'play'"), resultRecordColumns("g.play_draw = 'draw'")))
+	if err != nil {
+		return out, fmt.Errorf("list deck play/draw records: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var deck model.DeckPlayDrawSplit
+		var play, draw recordScanner
+		dests := []any{&deck.DeckID, &deck.DeckName, &deck.Format}
+		dests = append(dests, play.dests()...)
+		dests = append(dests, draw.dests()...)
+		if err := rows.Scan(dests...); err != nil {
+			return out, fmt.Errorf("scan deck play/draw record: %w", err)
+		}
+		deck.OnPlay.Record = play.agg()
+		deck.OnDraw.Record = draw.agg()
+		out.Decks = append(out.Decks, deck)
+	}
+	if err := rows.Err(); err != nil {
+		return out, fmt.Errorf("iterate deck play/draw records: %w", err)
+	}
+	return out, nil
+}
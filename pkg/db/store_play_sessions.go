@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// PlaySession is a block of matches with no more than the session gap
+// between one ending and the next starting.
+type PlaySession struct {
+	Start   time.Time
+	End     time.Time
+	Matches int64
+	Wins    int64
+	Losses  int64
+	Draws   int64
+	// Events are the distinct raw event names played, in order of first
+	// appearance.
+	Events []string
+	// MatchIDs are the session's matches in the order played.
+	MatchIDs []int64
+	// LongestLossStreak is the most losses in a row, which began with the
+	// match started at LossStreakStart; EndingLossStreak is how many the
+	// session ended on. A draw ends a streak.
+	LongestLossStreak int64
+	LossStreakStart   time.Time
+	EndingLossStreak  int64
+}
+
+// PlaySessions groups the matches started at or after since into sessions,
+// oldest first, starting a new one whenever more than gap passes between
+// matches. A match with no recorded end is taken to end when it started,
+// plus its game clock when that is known. Archived matches are left out.
+func (s *Store) PlaySessions(ctx context.Context, since time.Time, gap time.Duration) ([]PlaySession, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT m.id, m.started_at, COALESCE(m.ended_at, ''), COALESCE(m.seconds_count, 0),
+			COALESCE(m.result, ''), COALESCE(m.event_name, '')
+		FROM matches m
+		WHERE m.archived = 0
+		  AND m.started_at IS NOT NULL
+		  AND julianday(m.started_at) >= julianday(?)
+		ORDER BY julianday(m.started_at), m.id
+	`, since.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, fmt.Errorf("list play session matches: %w", err)
+	}
+	defer rows.Close()
+
+	var out []PlaySession
+	// streakStart is when the session's current loss streak began.
+	var streakStart time.Time
+	for rows.Next() {
+		var matchID, seconds int64
+		var startedAt, endedAt, result, eventName string
+		if err := rows.Scan(&matchID, &startedAt, &endedAt, &seconds, &result, &eventName); err != nil {
+			return nil, fmt.Errorf("scan play session match: %w", err)
+		}
+		start, ok := parseStoredTime(startedAt)
+		if !ok {
+			continue
+		}
+		end, ok := parseStoredTime(endedAt)
+		if !ok || end.Before(start) {
+			end = start.Add(time.Duration(seconds) * time.Second)
+		}
+
+		if len(out) == 0 || start.Sub(out[len(out)-1].End) > gap {
+			out = append(out, PlaySession{Start: start, End: end})
+		}
+		session := &out[len(out)-1]
+		if end.After(session.End) {
+			session.End = end
+		}
+		session.Matches++
+		session.MatchIDs = append(session.MatchIDs, matchID)
+		switch result {
+		case "win":
+			session.Wins++
+			session.EndingLossStreak = 0
+		case "loss":
+			session.Losses++
+			if session.EndingLossStreak == 0 {
+				streakStart = start
+			}
+			session.EndingLossStreak++
+			if session.EndingLossStreak > session.LongestLossStreak {
+				session.LongestLossStreak = session.EndingLossStreak
+				session.LossStreakStart = streakStart
+			}
+		case "draw":
+			session.Draws++
+			session.EndingLossStreak = 0
+		}
+		if eventName != "" && !slices.Contains(session.Events, eventName) {
+			session.Events = append(session.Events, eventName)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate play session matches: %w", err)
+	}
+	return out, nil
+}
+
+// PlaySessionRankChanges reports, for each session in order, how each
+// ladder's rank moved from the snapshot before the session's first match to
+// the one after its last. Ladders the session did not touch, and sessions
+// with no snapshot on either side, have no changes.
+func (s *Store) PlaySessionRankChanges(ctx context.Context, sessions []PlaySession) ([][]model.SessionRankChange, error) {
+	history, err := s.ListRankHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	indexByMatch := make(map[int64]int, len(history))
+	for index, point := range history {
+		indexByMatch[point.MatchID] = index
+	}
+
+	out := make([][]model.SessionRankChange, len(sessions))
+	for i, session := range sessions {
+		first, last := -1, -1
+		for _, matchID := range session.MatchIDs {
+			index, ok := indexByMatch[matchID]
+			if !ok {
+				continue
+			}
+			if first < 0 || index < first {
+				first = index
+			}
+			last = max(last, index)
+		}
+		if first <= 0 {
+			continue
+		}
+		before, after := history[first-1], history[last]
+		for _, ladder := range rankLadders {
+			from, to := before.Constructed, after.Constructed
+			if ladder.format == "limited" {
+				from, to = before.Limited, after.Limited
+			}
+			if from.SeasonOrdinal == nil || to.SeasonOrdinal == nil || sameRankState(from, to) {
+				continue
+			}
+			change := model.SessionRankChange{Format: ladder.format, From: rankLabel(from), To: rankLabel(to)}
+			fromPips, fromOK := rankPips(from, ladder.format, ladder.tiers)
+			toPips, toOK := rankPips(to, ladder.format, ladder.tiers)
+			if fromOK && toOK && *from.SeasonOrdinal == *to.SeasonOrdinal {
+				steps := toPips - fromPips
+				change.Steps = &steps
+			}
+			out[i] = append(out[i], change)
+		}
+	}
+	return out, nil
+}
+
+// rankPips counts the pips from the bottom of a ladder to rank; Mythic is
+// the top of the count.
+func rankPips(rank model.RankState, format string, tiers []string) (int64, bool) {
+	if rank.Level == nil {
+		return 0, false
+	}
+	rankClass := normalizeRankClass(rank.RankClass)
+	tierIndex := slices.Index(tiers, rankClass)
+	if tierIndex < 0 {
+		return 0, false
+	}
+	var pips int64
+	for _, tier := range tiers[:tierIndex] {
+		pips += 4 * rankStepsPerLevel(format, tier)
+	}
+	if rankClass == "Mythic" {
+		return pips, true
+	}
+	steps := rankStepsPerLevel(format, rankClass)
+	pips += (4 - min(max(*rank.Level, 1), 4)) * steps
+	if rank.Step != nil {
+		pips += min(max(*rank.Step, 0), steps)
+	}
+	return pips, true
+}
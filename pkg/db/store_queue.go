@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// QueueTimeStats aggregates recorded matchmaking waits per event and per
+// hour of day. Hours are local to the store's clock so "when are queues
+// fastest" matches the player's evenings, not UTC.
+func (s *Store) QueueTimeStats(ctx context.Context) (model.QueueTimeStats, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT COALESCE(event_name, ''), COALESCE(started_at, ''), queue_seconds
+		FROM matches
+		WHERE queue_seconds IS NOT NULL
+	`)
+	if err != nil {
+		return model.QueueTimeStats{}, fmt.Errorf("list queue times: %w", err)
+	}
+	defer rows.Close()
+
+	loc := s.clock.Now().Location()
+	out := model.QueueTimeStats{}
+	byEvent := make(map[string][]int64)
+	byHour := make(map[int][]int64)
+	for rows.Next() {
+		var eventName, startedAt string
+		var seconds int64
+		if err := rows.Scan(&eventName, &startedAt, &seconds); err != nil {
+			return model.QueueTimeStats{}, fmt.Errorf("scan queue time: %w", err)
+		}
+		out.Matches++
+		if eventName == "" {
+			eventName = "unknown"
+		}
+		byEvent[eventName] = append(byEvent[eventName], seconds)
+		if started, ok := parseStoredTime(startedAt); ok {
+			hour := started.In(loc).Hour()
+			byHour[hour] = append(byHour[hour], seconds)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return model.QueueTimeStats{}, fmt.Errorf("iterate queue times: %w", err)
+	}
+
+	out.ByEvent = make([]model.QueueTimeBucket, 0, len(byEvent))
+	for eventName, waits := range byEvent {
+		bucket := queueTimeBucket(eventName, waits)
+		bucket.EventType = detectEventType(eventName)
+		out.ByEvent = append(out.ByEvent, bucket)
+	}
+	slices.SortFunc(out.ByEvent, func(a, b model.QueueTimeBucket) int {
+		if a.Matches != b.Matches {
+			return int(b.Matches - a.Matches)
+		}
+		if a.Key < b.Key {
+			return -1
+		}
+		if a.Key > b.Key {
+			return 1
+		}
+		return 0
+	})
+
+	out.ByHour = make([]model.QueueTimeBucket, 0, len(byHour))
+	for hour := 0; hour < 24; hour++ {
+		waits, ok := byHour[hour]
+		if !ok {
+			continue
+		}
+		bucket := queueTimeBucket(strconv.Itoa(hour), waits)
+		bucket.Hour = nullableInt64Ptr(int64(hour))
+		out.ByHour = append(out.ByHour, bucket)
+	}
+	return out, nil
+}
+
+func queueTimeBucket(key string, waits []int64) model.QueueTimeBucket {
+	slices.Sort(waits)
+	var total int64
+	for _, wait := range waits {
+		total += wait
+	}
+	return model.QueueTimeBucket{
+		Key:           key,
+		Matches:       int64(len(waits)),
+		AvgSeconds:    float64(total) / float64(len(waits)),
+		MedianSeconds: waits[len(waits)/2],
+	}
+}
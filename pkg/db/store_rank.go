@@ -7,7 +7,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 type MatchRankSnapshot struct {
@@ -93,7 +93,7 @@ func (s *Store) UpsertMatchRankSnapshot(ctx context.Context, tx *sql.Tx, arenaMa
 	}
 
 	snapshot.ObservedAt = normalizeTS(snapshot.ObservedAt)
-	now := nowUTC()
+	now := s.nowUTC()
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO match_rank_snapshots (
 			match_id,
@@ -148,7 +148,7 @@ func (s *Store) UpsertMatchRankSnapshot(ctx context.Context, tx *sql.Tx, arenaMa
 }
 
 func (s *Store) ListRankHistory(ctx context.Context) ([]model.RankHistoryPoint, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			m.id,
 			m.arena_match_id,
@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// rankLadders lists each ladder's tiers from the bottom up; Spark only
+// exists on the constructed ladder.
+var rankLadders = []struct {
+	format string
+	tiers  []string
+}{
+	{"constructed", []string{"Spark", "Bronze", "Silver", "Gold", "Platinum", "Diamond", "Mythic"}},
+	{"limited", []string{"Bronze", "Silver", "Gold", "Platinum", "Diamond", "Mythic"}},
+}
+
+// RankHistorySeries splits rank history into one chartable series per
+// ladder, with season boundaries marked.
+func (s *Store) RankHistorySeries(ctx context.Context) (model.RankHistorySeries, error) {
+	history, err := s.ListRankHistory(ctx)
+	if err != nil {
+		return model.RankHistorySeries{}, fmt.Errorf("rank history series: %w", err)
+	}
+	return buildRankHistorySeries(history), nil
+}
+
+func buildRankHistorySeries(history []model.RankHistoryPoint) model.RankHistorySeries {
+	out := model.RankHistorySeries{Series: make([]model.RankSeries, 0, len(rankLadders))}
+	for _, ladder := range rankLadders {
+		series := model.RankSeries{
+			Format:  ladder.format,
+			Tiers:   ladder.tiers,
+			Points:  []model.RankSeriesPoint{},
+			Seasons: []model.RankSeason{},
+		}
+
+		// Every rank snapshot carries both ladders, so only the matches that
+		// moved this ladder's state become points.
+		var previous *model.RankState
+		for _, row := range history {
+			rank := row.Constructed
+			if ladder.format == "limited" {
+				rank = row.Limited
+			}
+			if rank.SeasonOrdinal == nil || (previous != nil && sameRankState(*previous, rank)) {
+				continue
+			}
+			previous = &rank
+
+			observedAt := row.ObservedAt
+			if observedAt == "" {
+				observedAt = row.EndedAt
+			}
+			point := model.RankSeriesPoint{
+				MatchID:       row.MatchID,
+				EventName:     row.EventName,
+				Result:        row.Result,
+				ObservedAt:    observedAt,
+				SeasonOrdinal: *rank.SeasonOrdinal,
+				RankClass:     rank.RankClass,
+				Level:         rank.Level,
+				Step:          rank.Step,
+				Label:         rankLabel(rank),
+				Score:         rankScore(rank, ladder.format, ladder.tiers),
+				MatchesWon:    rank.MatchesWon,
+				MatchesLost:   rank.MatchesLost,
+			}
+
+			index := len(series.Points)
+			if len(series.Seasons) == 0 || series.Seasons[len(series.Seasons)-1].SeasonOrdinal != point.SeasonOrdinal {
+				point.SeasonStart = true
+				series.Seasons = append(series.Seasons, model.RankSeason{
+					SeasonOrdinal: point.SeasonOrdinal,
+					StartIndex:    index,
+					StartedAt:     observedAt,
+				})
+			}
+			season := &series.Seasons[len(series.Seasons)-1]
+			season.EndIndex = index
+			season.EndedAt = observedAt
+			season.FinalLabel = point.Label
+			season.MatchesWon = rank.MatchesWon
+			season.MatchesLost = rank.MatchesLost
+
+			series.Points = append(series.Points, point)
+		}
+		out.Series = append(out.Series, series)
+	}
+	return out
+}
+
+func sameRankState(a, b model.RankState) bool {
+	sameInt := func(x, y *int64) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return *x == *y
+	}
+	return sameInt(a.SeasonOrdinal, b.SeasonOrdinal) &&
+		normalizeRankClass(a.RankClass) == normalizeRankClass(b.RankClass) &&
+		sameInt(a.Level, b.Level) &&
+		sameInt(a.Step, b.Step) &&
+		sameInt(a.MatchesWon, b.MatchesWon) &&
+		sameInt(a.MatchesLost, b.MatchesLost)
+}
+
+// normalizeRankClass treats a missing class as Bronze, matching the labels
+// on the Ranked page.
+func normalizeRankClass(rankClass string) string {
+	if trimmed := strings.TrimSpace(rankClass); trimmed != "" {
+		return trimmed
+	}
+	return "Bronze"
+}
+
+func rankLabel(rank model.RankState) string {
+	if rank.Level == nil || rank.SeasonOrdinal == nil {
+		return "Unranked"
+	}
+	rankClass := normalizeRankClass(rank.RankClass)
+	if rankClass == "Mythic" {
+		return rankClass
+	}
+	return fmt.Sprintf("%s %d", rankClass, *rank.Level)
+}
+
+// rankStepsPerLevel is how many pips fill one level of a tier.
+func rankStepsPerLevel(format, rankClass string) int64 {
+	switch {
+	case rankClass == "Mythic":
+		return 1
+	case format == "constructed" && rankClass == "Spark":
+		return 5
+	case format == "constructed":
+		return 6
+	case rankClass == "Bronze":
+		return 4
+	default:
+		return 5
+	}
+}
+
+// rankScore matches the Ranked page's chart scale. Mythic sits just under
+// the top of its band, since movement within Mythic is percentile-based.
+func rankScore(rank model.RankState, format string, tiers []string) *float64 {
+	if rank.Level == nil || rank.SeasonOrdinal == nil {
+		return nil
+	}
+	rankClass := normalizeRankClass(rank.RankClass)
+	tierIndex := slices.Index(tiers, rankClass)
+	if tierIndex < 0 {
+		return nil
+	}
+	score := float64(tierIndex) + 0.92
+	if rankClass != "Mythic" {
+		level := min(max(*rank.Level, 1), 4)
+		steps := rankStepsPerLevel(format, rankClass)
+		progress := 0.0
+		if rank.Step != nil {
+			progress = float64(min(max(*rank.Step, 0), steps)) / float64(steps)
+		}
+		score = float64(tierIndex) + (float64(4-level)+progress)/4
+	}
+	return &score
+}
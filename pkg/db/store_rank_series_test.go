@@ -0,0 +1,51 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestBuildRankHistorySeriesSplitsLaddersAndMarksSeasons(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(v int64) *int64 { return &v }
+	rank := func(season int64, class string, level, step, won int64) model.RankState {
+		return model.RankState{SeasonOrdinal: ptr(season), RankClass: class, Level: ptr(level), Step: ptr(step), MatchesWon: ptr(won), MatchesLost: ptr(0)}
+	}
+	limitedGold := rank(80, "Gold", 2, 1, 3)
+	history := []model.RankHistoryPoint{
+		{MatchID: 1, ObservedAt: "2026-08-30T10:00:00Z", Constructed: rank(80, "Silver", 1, 3, 5), Limited: limitedGold},
+		// A limited match: only the limited ladder moves.
+		{MatchID: 2, ObservedAt: "2026-08-30T11:00:00Z", Constructed: rank(80, "Silver", 1, 3, 5), Limited: rank(80, "Gold", 2, 2, 4)},
+		{MatchID: 3, EndedAt: "2026-09-02T09:00:00Z", Constructed: rank(81, "Bronze", 4, 0, 1), Limited: rank(81, "Mythic", 1, 0, 1)},
+	}
+
+	out := buildRankHistorySeries(history)
+	if len(out.Series) != 2 || out.Series[0].Format != "constructed" || out.Series[1].Format != "limited" {
+		t.Fatalf("series = %+v", out.Series)
+	}
+
+	constructed := out.Series[0]
+	if len(constructed.Points) != 2 || constructed.Points[0].MatchID != 1 || constructed.Points[1].MatchID != 3 {
+		t.Fatalf("constructed points = %+v, want matches 1 and 3", constructed.Points)
+	}
+	if !constructed.Points[1].SeasonStart || constructed.Points[1].ObservedAt != "2026-09-02T09:00:00Z" {
+		t.Fatalf("season-opening point = %+v", constructed.Points[1])
+	}
+	// Silver is tier 2 on the constructed ladder; level 1, step 3 of 6.
+	if score := constructed.Points[0].Score; score == nil || *score != 2+(3+0.5)/4 || constructed.Points[0].Label != "Silver 1" {
+		t.Fatalf("silver point = %+v", constructed.Points[0])
+	}
+	if len(constructed.Seasons) != 2 || constructed.Seasons[0].EndIndex != 0 || constructed.Seasons[1].StartIndex != 1 {
+		t.Fatalf("constructed seasons = %+v", constructed.Seasons)
+	}
+
+	limited := out.Series[1]
+	if len(limited.Points) != 3 || limited.Seasons[0].FinalLabel != "Gold 2" || *limited.Seasons[0].MatchesWon != 4 {
+		t.Fatalf("limited series = %+v", limited)
+	}
+	if score := limited.Points[2].Score; score == nil || *score != 5.92 {
+		t.Fatalf("mythic score = %v, want 5.92", score)
+	}
+}
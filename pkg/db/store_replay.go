@@ -10,9 +10,153 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
+const upsertMatchReplayFrameSQL = `
+	INSERT INTO match_replay_frames (
+		match_id,
+		game_number,
+		game_state_id,
+		prev_game_state_id,
+		game_state_type,
+		game_stage,
+		turn_number,
+		phase,
+		player_life_totals_json,
+		winning_player_side,
+		win_reason,
+		source,
+		recorded_at,
+		actions_json,
+		annotations_json,
+		created_at
+	)
+	SELECT
+		m.id, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+	FROM matches m
+	WHERE m.arena_match_id = ?
+	ON CONFLICT(match_id, game_number, game_state_id) DO UPDATE SET
+		prev_game_state_id = COALESCE(excluded.prev_game_state_id, match_replay_frames.prev_game_state_id),
+		game_state_type = COALESCE(excluded.game_state_type, match_replay_frames.game_state_type),
+		game_stage = COALESCE(excluded.game_stage, match_replay_frames.game_stage),
+		turn_number = COALESCE(excluded.turn_number, match_replay_frames.turn_number),
+		phase = COALESCE(excluded.phase, match_replay_frames.phase),
+		player_life_totals_json = COALESCE(excluded.player_life_totals_json, match_replay_frames.player_life_totals_json),
+		winning_player_side = COALESCE(excluded.winning_player_side, match_replay_frames.winning_player_side),
+		win_reason = COALESCE(excluded.win_reason, match_replay_frames.win_reason),
+		source = COALESCE(excluded.source, match_replay_frames.source),
+		recorded_at = COALESCE(excluded.recorded_at, match_replay_frames.recorded_at),
+		actions_json = COALESCE(excluded.actions_json, match_replay_frames.actions_json),
+		annotations_json = COALESCE(excluded.annotations_json, match_replay_frames.annotations_json)
+`
+
+const lookupMatchReplayFrameSQL = `
+	SELECT f.id
+	FROM match_replay_frames f
+	JOIN matches m ON m.id = f.match_id
+	WHERE m.arena_match_id = ?
+		AND f.game_number = ?
+		AND f.game_state_id = ?
+	LIMIT 1
+`
+
+const clearMatchReplayFrameObjectsSQL = `DELETE FROM match_replay_frame_objects WHERE frame_id = ?`
+
+// replayObjectBatchSizes are the row counts of the multi-row INSERTs frame
+// objects are written with, largest first. Any object count splits into a
+// few of them, so four statements cached per transaction cover every frame;
+// 64 rows of 21 columns stays far below SQLite's bound-parameter limit.
+var replayObjectBatchSizes = []int{64, 16, 4, 1}
+
+const replayObjectColumns = 21
+
+var insertReplayObjectsSQLBySize = func() map[int]string {
+	out := make(map[int]string, len(replayObjectBatchSizes))
+	for _, rows := range replayObjectBatchSizes {
+		out[rows] = insertReplayObjectsSQL(rows)
+	}
+	return out
+}()
+
+func insertReplayObjectsSQL(rows int) string {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", replayObjectColumns), ", ") + ")"
+	return `
+	INSERT INTO match_replay_frame_objects (
+		frame_id,
+		instance_id,
+		card_id,
+		owner_seat_id,
+		controller_seat_id,
+		zone_id,
+		zone_type,
+		zone_position,
+		visibility,
+		power,
+		toughness,
+		is_tapped,
+		has_summoning_sickness,
+		attack_state,
+		attack_target_id,
+		block_state,
+		block_attacker_ids_json,
+		counter_summary_json,
+		details_json,
+		is_token,
+		created_at
+	) VALUES ` + strings.TrimSuffix(strings.Repeat(row+", ", rows), ", ")
+}
+
+// insertReplayFrameObjects writes a frame's objects with as few multi-row
+// INSERTs from replayObjectBatchSizes as fit.
+func (s *Store) insertReplayFrameObjects(ctx context.Context, tx *sql.Tx, frameID int64, objects []model.MatchReplayFrameObjectRow) error {
+	now := s.nowUTC()
+	args := make([]any, 0, len(objects)*replayObjectColumns)
+	for _, obj := range objects {
+		if obj.InstanceID <= 0 || obj.CardID <= 0 || strings.TrimSpace(obj.ZoneType) == "" {
+			continue
+		}
+		args = append(args,
+			frameID,
+			obj.InstanceID,
+			obj.CardID,
+			nullableReplayInt(obj.OwnerSeatID),
+			nullableReplayInt(obj.ControllerSeatID),
+			nullableReplayInt(obj.ZoneID),
+			strings.TrimSpace(obj.ZoneType),
+			nullableReplayInt(obj.ZonePosition),
+			nullIfEmpty(strings.TrimSpace(obj.Visibility)),
+			nullableReplayInt(obj.Power),
+			nullableReplayInt(obj.Toughness),
+			boolToInt(obj.IsTapped),
+			boolToInt(obj.HasSummoningSickness),
+			nullIfEmpty(strings.TrimSpace(obj.AttackState)),
+			nullableReplayInt(obj.AttackTargetID),
+			nullIfEmpty(strings.TrimSpace(obj.BlockState)),
+			nullIfEmpty(strings.TrimSpace(obj.BlockAttackerIDsJSON)),
+			nullIfEmpty(strings.TrimSpace(obj.CounterSummaryJSON)),
+			nullIfEmpty(strings.TrimSpace(obj.DetailsJSON)),
+			boolToInt(obj.IsToken),
+			now,
+		)
+	}
+
+	for _, rows := range replayObjectBatchSizes {
+		width := rows * replayObjectColumns
+		for len(args) >= width {
+			stmt, err := s.txStmt(ctx, tx, insertReplayObjectsSQLBySize[rows])
+			if err != nil {
+				return err
+			}
+			if _, err := stmt.ExecContext(ctx, args[:width]...); err != nil {
+				return fmt.Errorf("insert match replay frame objects: %w", err)
+			}
+			args = args[width:]
+		}
+	}
+	return nil
+}
+
 func (s *Store) ReplaceMatchReplayFrame(
 	ctx context.Context,
 	tx *sql.Tx,
@@ -34,43 +178,11 @@ func (s *Store) ReplaceMatchReplayFrame(
 	annotationsText := strings.TrimSpace(string(annotationsJSON))
 	playerLifeTotalsText := strings.TrimSpace(string(playerLifeTotalsJSON))
 
-	_, err := tx.ExecContext(ctx, `
-		INSERT INTO match_replay_frames (
-			match_id,
-			game_number,
-			game_state_id,
-			prev_game_state_id,
-			game_state_type,
-			game_stage,
-			turn_number,
-			phase,
-			player_life_totals_json,
-			winning_player_side,
-			win_reason,
-			source,
-			recorded_at,
-			actions_json,
-			annotations_json,
-			created_at
-		)
-		SELECT
-			m.id, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
-		FROM matches m
-		WHERE m.arena_match_id = ?
-		ON CONFLICT(match_id, game_number, game_state_id) DO UPDATE SET
-			prev_game_state_id = COALESCE(excluded.prev_game_state_id, match_replay_frames.prev_game_state_id),
-			game_state_type = COALESCE(excluded.game_state_type, match_replay_frames.game_state_type),
-			game_stage = COALESCE(excluded.game_stage, match_replay_frames.game_stage),
-			turn_number = COALESCE(excluded.turn_number, match_replay_frames.turn_number),
-			phase = COALESCE(excluded.phase, match_replay_frames.phase),
-			player_life_totals_json = COALESCE(excluded.player_life_totals_json, match_replay_frames.player_life_totals_json),
-			winning_player_side = COALESCE(excluded.winning_player_side, match_replay_frames.winning_player_side),
-			win_reason = COALESCE(excluded.win_reason, match_replay_frames.win_reason),
-			source = COALESCE(excluded.source, match_replay_frames.source),
-			recorded_at = COALESCE(excluded.recorded_at, match_replay_frames.recorded_at),
-			actions_json = COALESCE(excluded.actions_json, match_replay_frames.actions_json),
-			annotations_json = COALESCE(excluded.annotations_json, match_replay_frames.annotations_json)
-	`,
+	upsert, err := s.txStmt(ctx, tx, upsertMatchReplayFrameSQL)
+	if err != nil {
+		return 0, err
+	}
+	_, err = upsert.ExecContext(ctx,
 		gameNumber,
 		gameStateID,
 		nullableInt(prevGameStateID),
@@ -85,86 +197,32 @@ func (s *Store) ReplaceMatchReplayFrame(
 		nullIfEmpty(normalizeTS(recordedAt)),
 		nullIfEmpty(actionsText),
 		nullIfEmpty(annotationsText),
-		nowUTC(),
+		s.nowUTC(),
 		arenaMatchID,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("upsert match replay frame: %w", err)
 	}
 
-	var frameID int64
-	err = tx.QueryRowContext(ctx, `
-		SELECT f.id
-		FROM match_replay_frames f
-		JOIN matches m ON m.id = f.match_id
-		WHERE m.arena_match_id = ?
-			AND f.game_number = ?
-			AND f.game_state_id = ?
-		LIMIT 1
-	`, arenaMatchID, gameNumber, gameStateID).Scan(&frameID)
+	lookup, err := s.txStmt(ctx, tx, lookupMatchReplayFrameSQL)
 	if err != nil {
+		return 0, err
+	}
+	var frameID int64
+	if err := lookup.QueryRowContext(ctx, arenaMatchID, gameNumber, gameStateID).Scan(&frameID); err != nil {
 		return 0, fmt.Errorf("lookup match replay frame: %w", err)
 	}
 
-	if _, err := tx.ExecContext(ctx, `DELETE FROM match_replay_frame_objects WHERE frame_id = ?`, frameID); err != nil {
+	clear, err := s.txStmt(ctx, tx, clearMatchReplayFrameObjectsSQL)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := clear.ExecContext(ctx, frameID); err != nil {
 		return 0, fmt.Errorf("clear match replay frame objects: %w", err)
 	}
-
-	for _, obj := range objects {
-		if obj.InstanceID <= 0 || obj.CardID <= 0 || strings.TrimSpace(obj.ZoneType) == "" {
-			continue
-		}
-		if _, err := tx.ExecContext(ctx, `
-			INSERT INTO match_replay_frame_objects (
-				frame_id,
-				instance_id,
-				card_id,
-				owner_seat_id,
-				controller_seat_id,
-				zone_id,
-				zone_type,
-				zone_position,
-				visibility,
-				power,
-				toughness,
-				is_tapped,
-				has_summoning_sickness,
-				attack_state,
-				attack_target_id,
-				block_state,
-				block_attacker_ids_json,
-				counter_summary_json,
-				details_json,
-				is_token,
-				created_at
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`,
-			frameID,
-			obj.InstanceID,
-			obj.CardID,
-			nullableReplayInt(obj.OwnerSeatID),
-			nullableReplayInt(obj.ControllerSeatID),
-			nullableReplayInt(obj.ZoneID),
-			strings.TrimSpace(obj.ZoneType),
-			nullableReplayInt(obj.ZonePosition),
-			nullIfEmpty(strings.TrimSpace(obj.Visibility)),
-			nullableReplayInt(obj.Power),
-			nullableReplayInt(obj.Toughness),
-			boolToInt(obj.IsTapped),
-			boolToInt(obj.HasSummoningSickness),
-			nullIfEmpty(strings.TrimSpace(obj.AttackState)),
-			nullableReplayInt(obj.AttackTargetID),
-			nullIfEmpty(strings.TrimSpace(obj.BlockState)),
-			nullIfEmpty(strings.TrimSpace(obj.BlockAttackerIDsJSON)),
-			nullIfEmpty(strings.TrimSpace(obj.CounterSummaryJSON)),
-			nullIfEmpty(strings.TrimSpace(obj.DetailsJSON)),
-			boolToInt(obj.IsToken),
-			nowUTC(),
-		); err != nil {
-			return 0, fmt.Errorf("insert match replay frame object: %w", err)
-		}
+	if err := s.insertReplayFrameObjects(ctx, tx, frameID, objects); err != nil {
+		return 0, err
 	}
-
 	return frameID, nil
 }
 
@@ -360,7 +418,7 @@ func mergeReplayFrameRows(archived, live []model.MatchReplayFrameRow) []model.Ma
 }
 
 func (s *Store) listLiveMatchReplayFrames(ctx context.Context, matchID int64) ([]model.MatchReplayFrameRow, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			f.id,
 			f.game_number,
@@ -436,7 +494,7 @@ func (s *Store) listLiveMatchReplayFrames(ctx context.Context, matchID int64) ([
 		return frames, nil
 	}
 
-	objectRows, err := s.db.QueryContext(ctx, `
+	objectRows, err := s.reader().QueryContext(ctx, `
 		SELECT
 			o.id,
 			o.frame_id,
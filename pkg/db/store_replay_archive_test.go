@@ -7,7 +7,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 func newReplayArchiveTestStore(t *testing.T) *Store {
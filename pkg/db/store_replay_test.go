@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestReplaceMatchReplayFrameBatchesObjectsWithCachedStatements(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := openTempSQLiteDB(t)
+	if err := Init(ctx, database); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	store := NewStore(database)
+
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := store.UpsertMatchStart(ctx, tx, "batch-match", "Ladder", 1, "2026-07-18T00:00:00Z"); err != nil {
+		t.Fatalf("UpsertMatchStart: %v", err)
+	}
+
+	// 87 objects split into every batch size (64+16+4+1+1+1), plus one
+	// invalid object that must be skipped.
+	objects := make([]model.MatchReplayFrameObjectRow, 0, 88)
+	for i := int64(1); i <= 87; i++ {
+		objects = append(objects, model.MatchReplayFrameObjectRow{InstanceID: i, CardID: 1000 + i, ZoneType: "battlefield", IsTapped: i%2 == 0})
+	}
+	objects = append(objects, model.MatchReplayFrameObjectRow{InstanceID: 88, CardID: 1088})
+	frameID, err := store.ReplaceMatchReplayFrame(ctx, tx, "batch-match", 1, 1, 0, 1,
+		"GameStateType_Full", "GameStage_Play", "main1", "", "", "2026-07-18T00:00:01Z", "test",
+		nil, nil, nil, objects)
+	if err != nil {
+		t.Fatalf("ReplaceMatchReplayFrame: %v", err)
+	}
+
+	var rows, tapped, lastCard int64
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), SUM(is_tapped), MAX(card_id)
+		FROM match_replay_frame_objects
+		WHERE frame_id = ?
+	`, frameID).Scan(&rows, &tapped, &lastCard); err != nil {
+		t.Fatalf("count frame objects: %v", err)
+	}
+	if rows != 87 || tapped != 43 || lastCard != 1087 {
+		t.Fatalf("objects = %d (tapped %d, max card %d), want 87 (43, 1087)", rows, tapped, lastCard)
+	}
+
+	// Replacing the frame reuses the cached statements and swaps the objects.
+	if _, err := store.ReplaceMatchReplayFrame(ctx, tx, "batch-match", 1, 1, 0, 1,
+		"GameStateType_Full", "GameStage_Play", "main1", "", "", "2026-07-18T00:00:01Z", "test",
+		nil, nil, nil, objects[:3]); err != nil {
+		t.Fatalf("ReplaceMatchReplayFrame again: %v", err)
+	}
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM match_replay_frame_objects WHERE frame_id = ?`, frameID).Scan(&rows); err != nil {
+		t.Fatalf("recount frame objects: %v", err)
+	}
+	if rows != 3 {
+		t.Fatalf("objects after replace = %d, want 3", rows)
+	}
+
+	first, err := store.txStmt(ctx, tx, clearMatchReplayFrameObjectsSQL)
+	if err != nil {
+		t.Fatalf("txStmt: %v", err)
+	}
+	again, err := store.txStmt(ctx, tx, clearMatchReplayFrameObjectsSQL)
+	if err != nil || again != first {
+		t.Fatalf("txStmt returned %p then %p (%v), want the cached statement", first, again, err)
+	}
+}
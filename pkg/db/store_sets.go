@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 // LookupSets returns cached set metadata for the given lowercase set codes.
@@ -30,7 +30,7 @@ func (s *Store) LookupSets(ctx context.Context, codes []string) (map[string]mode
 		WHERE code IN (%s)
 	`, strings.Join(placeholders, ","))
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.reader().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("lookup sets: %w", err)
 	}
@@ -62,7 +62,7 @@ func (s *Store) UpsertSets(ctx context.Context, sets map[string]model.SetInfo) e
 		return nil
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.BeginTx(ctx)
 	if err != nil {
 		return fmt.Errorf("begin set catalog tx: %w", err)
 	}
@@ -82,7 +82,7 @@ func (s *Store) UpsertSets(ctx context.Context, sets map[string]model.SetInfo) e
 	}
 	defer stmt.Close()
 
-	now := nowUTC()
+	now := s.nowUTC()
 	for code, info := range sets {
 		normalized := strings.ToLower(strings.TrimSpace(code))
 		if normalized == "" || strings.TrimSpace(info.Name) == "" {
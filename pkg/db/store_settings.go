@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// Archetype signature sources accepted in model.Settings.
+const (
+	ArchetypeSourceDerived = "derived"
+	ArchetypeSourceManual  = "manual"
+)
+
+// maxRetentionDays bounds the retentionDays setting to something a prune
+// can turn into a duration without overflowing.
+const maxRetentionDays = 100 * 365
+
+// DefaultSettings are the preferences of a database with nothing saved.
+func DefaultSettings() model.Settings {
+	return model.Settings{
+		LookupsEnabled:           true,
+		ArchetypeSignatureSource: ArchetypeSourceDerived,
+	}
+}
+
+// ValidateSettings rejects values no consumer of the settings understands.
+func ValidateSettings(settings model.Settings) error {
+	if settings.RetentionDays < 0 || settings.RetentionDays > maxRetentionDays {
+		return fmt.Errorf("retentionDays must be between 0 and %d", maxRetentionDays)
+	}
+	switch settings.ArchetypeSignatureSource {
+	case ArchetypeSourceDerived, ArchetypeSourceManual:
+	default:
+		return fmt.Errorf("archetypeSignatureSource must be %q or %q", ArchetypeSourceDerived, ArchetypeSourceManual)
+	}
+	if strings.TrimSpace(settings.DefaultFormat) != settings.DefaultFormat {
+		return fmt.Errorf("defaultFormat must not have surrounding spaces")
+	}
+	return nil
+}
+
+// GetSettings reads the saved preferences over DefaultSettings. Keys this
+// build does not know are ignored, so an older binary can open a database a
+// newer one wrote to.
+func (s *Store) GetSettings(ctx context.Context) (model.Settings, error) {
+	rows, err := s.reader().QueryContext(ctx, `SELECT key, value FROM settings`)
+	if err != nil {
+		return model.Settings{}, fmt.Errorf("get settings: %w", err)
+	}
+	defer rows.Close()
+
+	saved := make(map[string]json.RawMessage)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return model.Settings{}, fmt.Errorf("scan setting: %w", err)
+		}
+		saved[key] = json.RawMessage(value)
+	}
+	if err := rows.Err(); err != nil {
+		return model.Settings{}, fmt.Errorf("iterate settings: %w", err)
+	}
+
+	out := DefaultSettings()
+	if len(saved) == 0 {
+		return out, nil
+	}
+	encoded, err := json.Marshal(saved)
+	if err != nil {
+		return model.Settings{}, fmt.Errorf("decode settings: %w", err)
+	}
+	if err := json.Unmarshal(encoded, &out); err != nil {
+		return model.Settings{}, fmt.Errorf("decode settings: %w", err)
+	}
+	return out, nil
+}
+
+// SaveSettings validates settings and stores every key in one transaction.
+func (s *Store) SaveSettings(ctx context.Context, settings model.Settings) error {
+	if err := ValidateSettings(settings); err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &values); err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin settings tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := s.nowUTC()
+	for key, value := range values {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO settings (key, value, updated_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT(key) DO UPDATE SET
+				value = excluded.value,
+				updated_at = excluded.updated_at
+			WHERE settings.value != excluded.value
+		`, key, string(value), now); err != nil {
+			return fmt.Errorf("save setting %s: %w", key, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MatchStart is when a match began and how it ended.
+type MatchStart struct {
+	StartedAt time.Time
+	Result    string
+}
+
+// ListMatchStarts returns the start time and result of every decided match
+// started at or after since, oldest first. Archived matches are left out.
+func (s *Store) ListMatchStarts(ctx context.Context, since time.Time) ([]MatchStart, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT m.started_at, m.result
+		FROM matches m
+		WHERE m.archived = 0
+		  AND m.started_at IS NOT NULL
+		  AND m.result IN ('win', 'loss', 'draw')
+		  AND julianday(m.started_at) >= julianday(?)
+		ORDER BY julianday(m.started_at), m.id
+	`, since.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, fmt.Errorf("list match starts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []MatchStart
+	for rows.Next() {
+		var startedAt, result string
+		if err := rows.Scan(&startedAt, &result); err != nil {
+			return nil, fmt.Errorf("scan match start: %w", err)
+		}
+		start, ok := parseStoredTime(startedAt)
+		if !ok {
+			continue
+		}
+		out = append(out, MatchStart{StartedAt: start, Result: result})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate match starts: %w", err)
+	}
+	return out, nil
+}
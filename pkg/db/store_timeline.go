@@ -0,0 +1,243 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// timelineAnnotationTypes are the Arena annotation types worth showing on a
+// turn-by-turn timeline. Bookkeeping types (ObjectIdChanged, tapping, phase
+// changes) are left to the full replay frames; ModifiedLife is covered by
+// the life changes derived from player totals.
+var timelineAnnotationTypes = map[string]bool{
+	"ZoneTransfer":   true,
+	"DamageDealt":    true,
+	"TokenCreated":   true,
+	"CounterAdded":   true,
+	"CounterRemoved": true,
+	"Scry":           true,
+}
+
+type timelineAnnotationPayload struct {
+	Annotations []struct {
+		AffectorID  int64    `json:"affectorId"`
+		AffectedIDs []int64  `json:"affectedIds"`
+		Type        []string `json:"type"`
+		Details     []struct {
+			Key         string   `json:"key"`
+			ValueInt32  []int64  `json:"valueInt32"`
+			ValueString []string `json:"valueString"`
+		} `json:"details"`
+	} `json:"annotations"`
+}
+
+// MatchTimeline assembles a match's card plays and replay frames into games
+// and turns. It returns sql.ErrNoRows (wrapped) when the match is unknown.
+func (s *Store) MatchTimeline(ctx context.Context, matchID int64) (model.MatchTimeline, error) {
+	var selfSeat sql.NullInt64
+	if err := s.reader().QueryRowContext(ctx, `SELECT player_seat_id FROM matches WHERE id = ?`, matchID).Scan(&selfSeat); err != nil {
+		return model.MatchTimeline{}, fmt.Errorf("get match timeline: %w", err)
+	}
+	plays, err := s.ListMatchCardPlays(ctx, matchID)
+	if err != nil {
+		return model.MatchTimeline{}, err
+	}
+	frames, err := s.ListMatchReplayFrames(ctx, matchID)
+	if err != nil {
+		return model.MatchTimeline{}, err
+	}
+	return buildMatchTimeline(matchID, replayPtrFromNullInt64(selfSeat), plays, frames), nil
+}
+
+// buildMatchTimeline groups plays and frames by game and turn. Frames must be
+// in game-state order, as ListMatchReplayFrames returns them.
+func buildMatchTimeline(matchID int64, selfSeat *int64, plays []model.MatchCardPlayRow, frames []model.MatchReplayFrameRow) model.MatchTimeline {
+	type gameState struct {
+		game         model.MatchTimelineGame
+		turns        map[int64]*model.MatchTimelineTurn
+		instances    map[int64]model.MatchTimelineCardRef
+		selfLife     *int64
+		opponentLife *int64
+	}
+	games := map[int64]*gameState{}
+	gameFor := func(gameNumber *int64) *gameState {
+		number := int64(1)
+		if gameNumber != nil && *gameNumber > 0 {
+			number = *gameNumber
+		}
+		game, ok := games[number]
+		if !ok {
+			game = &gameState{
+				game:      model.MatchTimelineGame{GameNumber: number},
+				turns:     map[int64]*model.MatchTimelineTurn{},
+				instances: map[int64]model.MatchTimelineCardRef{},
+			}
+			games[number] = game
+		}
+		return game
+	}
+	turnFor := func(game *gameState, turnNumber *int64) *model.MatchTimelineTurn {
+		number := int64(0)
+		if turnNumber != nil && *turnNumber > 0 {
+			number = *turnNumber
+		}
+		turn, ok := game.turns[number]
+		if !ok {
+			turn = &model.MatchTimelineTurn{
+				TurnNumber:  number,
+				Plays:       []model.MatchCardPlayRow{},
+				LifeChanges: []model.MatchTimelineLifeChange{},
+				Annotations: []model.MatchTimelineAnnotation{},
+			}
+			game.turns[number] = turn
+		}
+		return turn
+	}
+	sideForSeat := func(seat int64) string {
+		if selfSeat != nil && seat == *selfSeat {
+			return "self"
+		}
+		return "opponent"
+	}
+
+	for _, play := range plays {
+		game := gameFor(play.GameNumber)
+		turn := turnFor(game, play.TurnNumber)
+		turn.Plays = append(turn.Plays, play)
+		game.instances[play.InstanceID] = model.MatchTimelineCardRef{
+			InstanceID: play.InstanceID,
+			CardID:     play.CardID,
+			CardName:   play.CardName,
+			PlayerSide: play.PlayerSide,
+		}
+	}
+
+	for _, frame := range frames {
+		game := gameFor(frame.GameNumber)
+		turn := turnFor(game, frame.TurnNumber)
+		if turn.StartedAt == "" {
+			turn.StartedAt = frame.RecordedAt
+		}
+		if frame.WinningPlayerSide != "" {
+			game.game.WinningPlayerSide = frame.WinningPlayerSide
+			game.game.WinReason = frame.WinReason
+		}
+
+		// trackLife records a change against the game's last known total and
+		// returns the total to carry forward.
+		trackLife := func(side string, previous, current *int64) *int64 {
+			if current == nil {
+				return previous
+			}
+			if previous != nil && *previous != *current {
+				turn.LifeChanges = append(turn.LifeChanges, model.MatchTimelineLifeChange{
+					PlayerSide:  side,
+					From:        *previous,
+					To:          *current,
+					Delta:       *current - *previous,
+					Phase:       frame.Phase,
+					GameStateID: frame.GameStateID,
+					RecordedAt:  frame.RecordedAt,
+				})
+			}
+			return current
+		}
+		game.selfLife = trackLife("self", game.selfLife, frame.SelfLifeTotal)
+		game.opponentLife = trackLife("opponent", game.opponentLife, frame.OpponentLifeTotal)
+		if frame.SelfLifeTotal != nil {
+			turn.SelfLifeTotal = frame.SelfLifeTotal
+		}
+		if frame.OpponentLifeTotal != nil {
+			turn.OpponentLifeTotal = frame.OpponentLifeTotal
+		}
+
+		for _, object := range frame.Objects {
+			game.instances[object.InstanceID] = model.MatchTimelineCardRef{
+				InstanceID: object.InstanceID,
+				CardID:     object.CardID,
+				CardName:   object.CardName,
+				PlayerSide: object.PlayerSide,
+			}
+		}
+		turn.Annotations = append(turn.Annotations, timelineAnnotations(frame, game.instances, sideForSeat)...)
+	}
+
+	out := model.MatchTimeline{MatchID: matchID, Games: make([]model.MatchTimelineGame, 0, len(games))}
+	for _, game := range games {
+		game.game.Turns = make([]model.MatchTimelineTurn, 0, len(game.turns))
+		for _, turn := range game.turns {
+			game.game.Turns = append(game.game.Turns, *turn)
+		}
+		sort.Slice(game.game.Turns, func(i, j int) bool {
+			return game.game.Turns[i].TurnNumber < game.game.Turns[j].TurnNumber
+		})
+		out.Games = append(out.Games, game.game)
+	}
+	sort.Slice(out.Games, func(i, j int) bool { return out.Games[i].GameNumber < out.Games[j].GameNumber })
+	return out
+}
+
+// timelineAnnotations decodes a frame's transient annotations, resolving
+// instance ids through the cards seen so far in the game. Small ids that are
+// not card instances are seat numbers (a player took damage, for example).
+func timelineAnnotations(frame model.MatchReplayFrameRow, instances map[int64]model.MatchTimelineCardRef, sideForSeat func(int64) string) []model.MatchTimelineAnnotation {
+	if strings.TrimSpace(frame.AnnotationsJSON) == "" {
+		return nil
+	}
+	var payload timelineAnnotationPayload
+	if err := json.Unmarshal([]byte(frame.AnnotationsJSON), &payload); err != nil {
+		return nil
+	}
+
+	var out []model.MatchTimelineAnnotation
+	for _, raw := range payload.Annotations {
+		annotationType := ""
+		for _, value := range raw.Type {
+			if name := strings.TrimPrefix(strings.TrimSpace(value), "AnnotationType_"); timelineAnnotationTypes[name] {
+				annotationType = name
+				break
+			}
+		}
+		if annotationType == "" {
+			continue
+		}
+
+		annotation := model.MatchTimelineAnnotation{
+			Type:        annotationType,
+			Phase:       frame.Phase,
+			GameStateID: frame.GameStateID,
+			RecordedAt:  frame.RecordedAt,
+		}
+		for _, detail := range raw.Details {
+			switch detail.Key {
+			case "category":
+				if len(detail.ValueString) > 0 {
+					annotation.Category = detail.ValueString[0]
+				}
+			case "damage", "transaction_amount":
+				if len(detail.ValueInt32) > 0 {
+					amount := detail.ValueInt32[0]
+					annotation.Amount = &amount
+				}
+			}
+		}
+		if ref, ok := instances[raw.AffectorID]; ok && raw.AffectorID > 0 {
+			annotation.Source = &ref
+		}
+		for _, id := range raw.AffectedIDs {
+			if ref, ok := instances[id]; ok {
+				annotation.Targets = append(annotation.Targets, ref)
+			} else if id > 0 && id <= 4 {
+				annotation.TargetPlayers = append(annotation.TargetPlayers, sideForSeat(id))
+			}
+		}
+		out = append(out, annotation)
+	}
+	return out
+}
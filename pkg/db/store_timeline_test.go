@@ -0,0 +1,76 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestBuildMatchTimelineGroupsTurnsWithLifeAndAnnotations(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(v int64) *int64 { return &v }
+	selfSeat := ptr(1)
+	plays := []model.MatchCardPlayRow{
+		{ID: 1, GameNumber: ptr(1), InstanceID: 301, CardID: 9001, CardName: "Shock", PlayerSide: "self", TurnNumber: ptr(3)},
+		{ID: 2, GameNumber: ptr(1), InstanceID: 302, CardID: 9002, PlayerSide: "opponent", TurnNumber: ptr(2)},
+		{ID: 3, GameNumber: ptr(2), InstanceID: 401, CardID: 9003, PlayerSide: "self", TurnNumber: ptr(1)},
+	}
+	frames := []model.MatchReplayFrameRow{
+		{GameNumber: ptr(1), GameStateID: ptr(1), Phase: "Beginning", SelfLifeTotal: ptr(20), OpponentLifeTotal: ptr(20), RecordedAt: "2026-07-12T18:00:00Z"},
+		{GameNumber: ptr(1), GameStateID: ptr(5), TurnNumber: ptr(2), Phase: "Combat", SelfLifeTotal: ptr(18), OpponentLifeTotal: ptr(20), RecordedAt: "2026-07-12T18:02:00Z"},
+		{
+			GameNumber: ptr(1), GameStateID: ptr(9), TurnNumber: ptr(3), Phase: "Main1",
+			SelfLifeTotal: ptr(18), OpponentLifeTotal: ptr(18), RecordedAt: "2026-07-12T18:04:00Z",
+			AnnotationsJSON: `{"annotations":[` +
+				`{"affectorId":301,"affectedIds":[2],"type":["AnnotationType_DamageDealt"],"details":[{"key":"damage","valueInt32":[2]}]},` +
+				`{"affectedIds":[301],"type":["AnnotationType_ZoneTransfer"],"details":[{"key":"category","valueString":["Resolve"]}]},` +
+				`{"affectedIds":[301],"type":["AnnotationType_ObjectIdChanged"]}` +
+				`]}`,
+		},
+		{GameNumber: ptr(1), GameStateID: ptr(12), TurnNumber: ptr(3), WinningPlayerSide: "self", WinReason: "Concede"},
+	}
+
+	timeline := buildMatchTimeline(7, selfSeat, plays, frames)
+	if timeline.MatchID != 7 || len(timeline.Games) != 2 {
+		t.Fatalf("timeline = %+v, want match 7 with two games", timeline)
+	}
+
+	game := timeline.Games[0]
+	if game.GameNumber != 1 || game.WinningPlayerSide != "self" || game.WinReason != "Concede" {
+		t.Fatalf("game 1 = %+v", game)
+	}
+	if len(game.Turns) != 3 || game.Turns[0].TurnNumber != 0 || game.Turns[1].TurnNumber != 2 || game.Turns[2].TurnNumber != 3 {
+		t.Fatalf("game 1 turns = %+v, want 0, 2, 3", game.Turns)
+	}
+
+	turnTwo := game.Turns[1]
+	if len(turnTwo.Plays) != 1 || turnTwo.Plays[0].CardID != 9002 {
+		t.Fatalf("turn 2 plays = %+v", turnTwo.Plays)
+	}
+	if len(turnTwo.LifeChanges) != 1 || turnTwo.LifeChanges[0].PlayerSide != "self" || turnTwo.LifeChanges[0].Delta != -2 {
+		t.Fatalf("turn 2 life changes = %+v", turnTwo.LifeChanges)
+	}
+
+	turnThree := game.Turns[2]
+	if *turnThree.SelfLifeTotal != 18 || *turnThree.OpponentLifeTotal != 18 || turnThree.StartedAt != "2026-07-12T18:04:00Z" {
+		t.Fatalf("turn 3 summary = %+v", turnThree)
+	}
+	if len(turnThree.Annotations) != 2 {
+		t.Fatalf("turn 3 annotations = %+v, want damage and zone transfer only", turnThree.Annotations)
+	}
+	damage := turnThree.Annotations[0]
+	if damage.Type != "DamageDealt" || damage.Amount == nil || *damage.Amount != 2 ||
+		damage.Source == nil || damage.Source.CardName != "Shock" ||
+		len(damage.TargetPlayers) != 1 || damage.TargetPlayers[0] != "opponent" {
+		t.Fatalf("damage annotation = %+v", damage)
+	}
+	transfer := turnThree.Annotations[1]
+	if transfer.Type != "ZoneTransfer" || transfer.Category != "Resolve" || len(transfer.Targets) != 1 || transfer.Targets[0].CardID != 9001 {
+		t.Fatalf("zone transfer annotation = %+v", transfer)
+	}
+
+	if second := timeline.Games[1]; second.GameNumber != 2 || len(second.Turns) != 1 || len(second.Turns[0].Plays) != 1 {
+		t.Fatalf("game 2 = %+v", second)
+	}
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/solean/ponder/internal/eventnames"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// isLimitedEventKind reports whether an eventnames kind is a draft or sealed
+// event, the runs that can end in a trophy.
+func isLimitedEventKind(kind string) bool {
+	return strings.Contains(kind, "draft") || strings.Contains(kind, "sealed")
+}
+
+// ListLimitedRuns lists every draft and sealed event run with its record,
+// newest first. A run is finished once its rewards are claimed or it reached
+// the trophy win count; until then its record is not final.
+func (s *Store) ListLimitedRuns(ctx context.Context) ([]model.LimitedRun, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT event_name, status, COALESCE(started_at, ''), COALESCE(ended_at, ''), wins, losses
+		FROM event_runs
+		ORDER BY COALESCE(started_at, updated_at) DESC, id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list limited runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]model.LimitedRun, 0)
+	for rows.Next() {
+		var run model.LimitedRun
+		if err := rows.Scan(&run.EventName, &run.Status, &run.StartedAt, &run.EndedAt, &run.Wins, &run.Losses); err != nil {
+			return nil, fmt.Errorf("scan limited run: %w", err)
+		}
+		run.Kind = eventnames.Kind(run.EventName)
+		if !isLimitedEventKind(run.Kind) {
+			continue
+		}
+		run.SetCode = strings.ToUpper(eventnames.SetCode(run.EventName))
+		run.TrophyWins = LimitedTrophyWins(run.EventName)
+		run.Trophy = run.Wins >= run.TrophyWins
+		run.Finished = run.Status != "active" || run.Trophy
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate limited runs: %w", err)
+	}
+	return runs, nil
+}
+
+// summarizeLimitedRuns counts trophies and averages wins over finished runs.
+func summarizeLimitedRuns(runs []model.LimitedRun) model.TrophySummary {
+	var out model.TrophySummary
+	var wins int64
+	for _, run := range runs {
+		if !run.Finished {
+			out.ActiveRuns++
+			continue
+		}
+		out.Runs++
+		wins += run.Wins
+		if !run.Trophy {
+			continue
+		}
+		out.Trophies++
+		// Runs come newest first, so the first trophy is the latest.
+		if out.LastTrophyAt == "" {
+			out.LastTrophyAt = run.EndedAt
+			if out.LastTrophyAt == "" {
+				out.LastTrophyAt = run.StartedAt
+			}
+		}
+	}
+	if out.Runs > 0 {
+		average := float64(wins) / float64(out.Runs)
+		rate := float64(out.Trophies) / float64(out.Runs)
+		out.AverageWins, out.TrophyRate = &average, &rate
+	}
+	return out
+}
+
+// TrophyStats lists every limited run and summarizes them overall and per
+// set and event kind, busiest group first. Kind labels are left for the
+// caller to fill in.
+func (s *Store) TrophyStats(ctx context.Context) (model.TrophyStats, error) {
+	runs, err := s.ListLimitedRuns(ctx)
+	if err != nil {
+		return model.TrophyStats{}, err
+	}
+	out := model.TrophyStats{
+		Summary: summarizeLimitedRuns(runs),
+		Groups:  []model.TrophyGroup{},
+		Runs:    runs,
+	}
+	byGroup := make(map[[2]string][]model.LimitedRun)
+	for _, run := range runs {
+		key := [2]string{run.SetCode, run.Kind}
+		byGroup[key] = append(byGroup[key], run)
+	}
+	for key, groupRuns := range byGroup {
+		out.Groups = append(out.Groups, model.TrophyGroup{
+			SetCode:       key[0],
+			Kind:          key[1],
+			TrophySummary: summarizeLimitedRuns(groupRuns),
+		})
+	}
+	sort.Slice(out.Groups, func(i, j int) bool {
+		a, b := out.Groups[i], out.Groups[j]
+		if a.Runs != b.Runs {
+			return a.Runs > b.Runs
+		}
+		if a.SetCode != b.SetCode {
+			return a.SetCode < b.SetCode
+		}
+		return a.Kind < b.Kind
+	})
+	return out, nil
+}
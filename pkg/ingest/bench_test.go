@@ -0,0 +1,203 @@
+package ingest
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+// The ingest benchmarks parse a synthetic Player.log of about benchLogMB
+// megabytes, shaped like a long play session: matches of full and diff
+// game-state messages between the room-state lines, deck submissions, and
+// the client chatter the parser skips. Run them with, for example,
+//
+//	go test ./pkg/ingest -run '^$' -bench Ingest -benchtime 3x -bench-log-mb 512
+//
+// and compare runs with benchstat. -cpuprofile and -memprofile work as usual.
+var benchLogMB = flag.Int("bench-log-mb", 256, "size in MB of the synthetic log the ingest benchmarks parse")
+
+// writeSyntheticLog writes a log of at least size bytes, and at least one
+// match, to path and returns its size and the number of matches in it.
+func writeSyntheticLog(tb testing.TB, path string, size int64) (int64, int) {
+	tb.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		tb.Fatalf("create synthetic log: %v", err)
+	}
+	defer file.Close()
+	w := bufio.NewWriterSize(file, 1<<20)
+
+	written := int64(0)
+	line := func(format string, args ...any) {
+		n, _ := fmt.Fprintf(w, format+"\n", args...)
+		written += int64(n)
+	}
+
+	line(`{"clientId":"self-user","screenName":"Self"}`)
+	filler := strings.Repeat("x", 160)
+	matches := 0
+	ts := int64(1772330782000)
+	for matches == 0 || written < size {
+		matches++
+		matchID := fmt.Sprintf("bench-match-%06d", matches)
+		deckID := fmt.Sprintf("bench-deck-%03d", matches%40)
+		ts += 60_000
+
+		line(`[UnityCrossThreadLogger]3/1/2026 12:%02d:%02d PM`, matches%60, matches%60)
+		line(`[UnityCrossThreadLogger]==> EventSetDeckV3 {"id":"req-%d","request":"{\"EventName\":\"Traditional_Ladder\",\"Summary\":{\"DeckId\":\"%s\",\"Name\":\"Bench Deck\",\"Attributes\":[{\"name\":\"Format\",\"value\":\"Standard\"}]},\"Deck\":{\"MainDeck\":[{\"cardId\":90001,\"quantity\":4},{\"cardId\":90002,\"quantity\":4},{\"cardId\":90003,\"quantity\":24}],\"Sideboard\":[{\"cardId\":90004,\"quantity\":2}]}}"}`, matches, deckID)
+		line(`{"timestamp":"%d","matchGameRoomStateChangedEvent":{"gameRoomInfo":{"gameRoomConfig":{"reservedPlayers":[{"userId":"opp-user","playerName":"Opp","systemSeatId":1,"teamId":1,"eventId":"Traditional_Ladder"},{"userId":"self-user","playerName":"Self","systemSeatId":2,"teamId":2,"eventId":"Traditional_Ladder"}],"matchId":"%s"},"stateType":"MatchGameRoomStateType_Playing"}}}`, ts, matchID)
+		line(`{"timestamp":"%d","greToClientEvent":{"greToClientMessages":[{"type":"GREMessageType_GameStateMessage","systemSeatIds":[2],"gameStateMessage":{"type":"GameStateType_Full","gameStateId":1,"gameInfo":{"matchID":"%s","gameNumber":1},"turnInfo":{"phase":"Phase_Beginning","turnNumber":1,"activePlayer":1},"zones":[{"zoneId":27,"type":"ZoneType_Stack","visibility":"Visibility_Public","objectInstanceIds":[]},{"zoneId":28,"type":"ZoneType_Battlefield","visibility":"Visibility_Public","objectInstanceIds":[]}],"gameObjects":[]}}]}}`, ts, matchID)
+
+		onBattlefield := []string{}
+		for state := 2; state <= 160; state++ {
+			ts += 500
+			instanceID := 300 + state
+			onBattlefield = append(onBattlefield, fmt.Sprint(instanceID))
+			if len(onBattlefield) > 24 {
+				onBattlefield = onBattlefield[1:]
+			}
+			objects := make([]string, 0, 6)
+			for k := range 6 {
+				objects = append(objects, fmt.Sprintf(`{"instanceId":%d,"grpId":%d,"type":"GameObjectType_Card","zoneId":28,"visibility":"Visibility_Public","ownerSeatId":%d,"controllerSeatId":%d,"cardTypes":["CardType_Creature"],"subtypes":["SubType_Elf"],"color":["CardColor_Green"],"power":{"value":2},"toughness":{"value":2},"name":%d,"abilities":[1005,99866],"overlayGrpId":%d}`,
+					instanceID-k, 90001+(instanceID-k)%3, 1+k%2, 1+k%2, 700000+k, 90001+(instanceID-k)%3))
+			}
+			line(`{"timestamp":"%d","greToClientEvent":{"greToClientMessages":[{"type":"GREMessageType_GameStateMessage","systemSeatIds":[2],"msgId":%d,"gameStateId":%d,"gameStateMessage":{"type":"GameStateType_Diff","gameStateId":%d,"prevGameStateId":%d,"turnInfo":{"phase":"Phase_Main1","step":"Step_Upkeep","turnNumber":%d,"activePlayer":%d,"priorityPlayer":%d,"decisionPlayer":%d},"zones":[{"zoneId":28,"type":"ZoneType_Battlefield","visibility":"Visibility_Public","objectInstanceIds":[%s]}],"gameObjects":[%s],"annotations":[{"id":%d,"affectorId":%d,"affectedIds":[%d],"type":["AnnotationType_ZoneTransfer"],"details":[{"key":"zone_src","type":"KeyValuePairValueType_int32","valueInt32":[31]},{"key":"zone_dest","type":"KeyValuePairValueType_int32","valueInt32":[28]}]}],"timers":[{"timerId":1,"type":"TimerType_ActivePlayer","durationSec":60,"elapsedMs":1200,"behavior":"TimerBehavior_TakeControl"}],"update":"GameStateUpdate_SendAndRecord"}},{"type":"GREMessageType_UIMessage","systemSeatIds":[1,2],"msgId":%d,"uiMessage":{"seatIds":[1],"onHover":{"objectId":%d}}}]}}`,
+				ts, state*2, state, state, state-1, 1+state/12, 1+state%2, 1+state%2, 1+state%2,
+				strings.Join(onBattlefield, ","), strings.Join(objects, ","), state, instanceID, instanceID, state*2+1, instanceID)
+			if state%4 == 0 {
+				line(`[UnityCrossThreadLogger]Client.SceneChange chatter %d.%d %s`, matches, state, filler)
+			}
+		}
+
+		ts += 1000
+		line(`{"timestamp":"%d","matchGameRoomStateChangedEvent":{"gameRoomInfo":{"gameRoomConfig":{"matchId":"%s","reservedPlayers":[{"userId":"opp-user","playerName":"Opp","systemSeatId":1,"teamId":1,"eventId":"Traditional_Ladder"},{"userId":"self-user","playerName":"Self","systemSeatId":2,"teamId":2,"eventId":"Traditional_Ladder"}]},"stateType":"MatchGameRoomStateType_MatchCompleted","finalMatchResult":{"matchId":"%s","matchCompletedReason":"MatchCompletedReasonType_Success","resultList":[{"scope":"MatchScope_Match","result":"ResultType_WinLoss","winningTeamId":%d,"reason":"ResultReason_Game"}]}}}}`, ts, matchID, matchID, 1+matches%2)
+	}
+	if err := w.Flush(); err != nil {
+		tb.Fatalf("write synthetic log: %v", err)
+	}
+	return written, matches
+}
+
+// benchmarkParse parses logPath into a fresh database b.N times, with the
+// parser set up by configure.
+func benchmarkParse(b *testing.B, logPath string, size int64, matches int, configure func(*db.Store, *Parser)) {
+	ctx := context.Background()
+	dir := b.TempDir()
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		database, err := db.Open(filepath.Join(dir, fmt.Sprintf("bench-%d.db", i)))
+		if err != nil {
+			b.Fatalf("open db: %v", err)
+		}
+		if err := db.Init(ctx, database); err != nil {
+			b.Fatalf("init db: %v", err)
+		}
+		store := db.NewStore(database)
+		parser := NewParser(store)
+		configure(store, parser)
+		b.StartTimer()
+
+		stats, err := parser.ParseFile(ctx, logPath, false)
+		if err != nil {
+			b.Fatalf("parse: %v", err)
+		}
+
+		b.StopTimer()
+		if stats.BytesRead != size || stats.MatchesUpserted < int64(matches) {
+			b.Fatalf("parsed %d bytes and %d matches, want %d and %d", stats.BytesRead, stats.MatchesUpserted, size, matches)
+		}
+		database.Close()
+		b.StartTimer()
+	}
+}
+
+func BenchmarkIngestParseFile(b *testing.B) {
+	logPath := filepath.Join(b.TempDir(), "Player.log")
+	size, matches := writeSyntheticLog(b, logPath, int64(*benchLogMB)<<20)
+
+	b.Run("decode-workers=1", func(b *testing.B) {
+		benchmarkParse(b, logPath, size, matches, func(_ *db.Store, p *Parser) { p.SetDecodeWorkers(1) })
+	})
+	if workers := runtime.GOMAXPROCS(0); workers > 1 {
+		b.Run(fmt.Sprintf("decode-workers=%d", workers), func(b *testing.B) {
+			benchmarkParse(b, logPath, size, matches, func(_ *db.Store, p *Parser) { p.SetDecodeWorkers(workers) })
+		})
+	}
+	b.Run("raw-level=full", func(b *testing.B) {
+		benchmarkParse(b, logPath, size, matches, func(s *db.Store, _ *Parser) { s.SetRawEventLevel(db.RawEventsFull) })
+	})
+}
+
+func BenchmarkIngestDecodeGRE(b *testing.B) {
+	logPath := filepath.Join(b.TempDir(), "Player.log")
+	writeSyntheticLog(b, logPath, 1)
+	contents, err := os.ReadFile(logPath)
+	if err != nil {
+		b.Fatalf("read synthetic log: %v", err)
+	}
+	var line []byte
+	for _, l := range strings.Split(string(contents), "\n") {
+		if strings.Contains(l, "GameStateType_Diff") {
+			line = []byte(l)
+			break
+		}
+	}
+
+	b.SetBytes(int64(len(line)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if decodeGRE(line) == nil {
+			b.Fatal("decodeGRE failed on a synthetic diff")
+		}
+	}
+}
+
+func TestSyntheticLogParses(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+	size, matches := writeSyntheticLog(t, logPath, 1<<20)
+
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	store := db.NewStore(database)
+	stats, err := NewParser(store).ParseFile(ctx, logPath, false)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if stats.BytesRead != size || stats.LinesSkipped != 0 {
+		t.Fatalf("stats = %+v, want %d bytes and no skipped lines", stats, size)
+	}
+	rows, err := store.ListMatches(ctx, 0, "", "", true)
+	if err != nil {
+		t.Fatalf("list matches: %v", err)
+	}
+	if len(rows) != matches {
+		t.Fatalf("matches = %d, want %d", len(rows), matches)
+	}
+	for _, row := range rows {
+		if row.Result != "win" && row.Result != "loss" {
+			t.Fatalf("match %s result = %q, want win or loss", row.ArenaMatchID, row.Result)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package ingest parses MTG Arena's Player.log into a db.Store. ParseFile
+// reads a log once (resuming from the saved offset when asked); TailFile
+// follows a live log across Arena's restarts; ParseFiles backfills several.
+//
+// This is the parser the ponder binary runs, so a Go program can embed it
+// instead of shelling out to the CLI.
+package ingest
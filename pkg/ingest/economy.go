@@ -5,8 +5,8 @@ import (
 	"database/sql"
 	"encoding/json"
 
-	"github.com/solean/ponder/internal/db"
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
 )
 
 type inventoryInfoPayload struct {
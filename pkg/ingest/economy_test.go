@@ -5,7 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
-	"github.com/solean/ponder/internal/db"
+	"github.com/solean/ponder/pkg/db"
 )
 
 func TestParserTracksEconomySnapshotsFromInventoryInfo(t *testing.T) {
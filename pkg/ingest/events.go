@@ -0,0 +1,157 @@
+package ingest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+// Event types the parser reports to an EventSink.
+const (
+	EventMatchStarted = "match.started"
+	EventMatchEnded   = "match.ended"
+	EventDraftPick    = "draft.pick"
+	EventDeckUpdated  = "deck.updated"
+)
+
+// EventTypes lists every event type, in the order usage text shows them.
+var EventTypes = []string{EventMatchStarted, EventMatchEnded, EventDraftPick, EventDeckUpdated}
+
+// Event is something the parser has just stored. Data is its JSON body, keyed
+// by Arena's ids (arenaMatchId, draftId, arenaDeckId) since those are what an
+// outside listener can match against the log.
+type Event struct {
+	Type string
+	Data map[string]any
+
+	// key identifies the event for de-duplication: Arena repeats a match's
+	// room state and can log one pick in more than one form.
+	key string
+}
+
+// EventSink receives events once the transaction that stored them commits.
+// It runs on the parsing goroutine, so it should hand events off rather than
+// block.
+type EventSink func(Event)
+
+// SetEventSink has the parser report what it stores to sink; nil stops it.
+// Only tailing should set one, or parsing a backlog replays its history.
+func (p *Parser) SetEventSink(sink EventSink) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	p.eventSink = sink
+}
+
+func (p *Parser) sink() EventSink {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.eventSink
+}
+
+// emit records an event for the line being processed. It is dropped if the
+// line's writes are rolled back, and sent only after they commit.
+func (p *Parser) emit(state *parseState, key, eventType string, data map[string]any) {
+	if p.sink() == nil {
+		return
+	}
+	state.lineEvents = append(state.lineEvents, Event{Type: eventType, Data: data, key: eventType + ":" + key})
+}
+
+// keepLineEvents moves the events of a line whose writes were kept into the
+// current batch, skipping any already sent or batched; discardLineEvents
+// forgets those of a line that was rolled back.
+func (s *parseState) keepLineEvents() {
+	for _, event := range s.lineEvents {
+		if s.sentEvents[event.key] || s.oldSentEvents[event.key] || slices.ContainsFunc(s.batchEvents, func(batched Event) bool { return batched.key == event.key }) {
+			continue
+		}
+		s.batchEvents = append(s.batchEvents, event)
+	}
+	s.lineEvents = nil
+}
+
+func (s *parseState) discardLineEvents() {
+	s.lineEvents = nil
+}
+
+// sentEventsMax bounds the keys of sent events a parse state remembers. Once
+// sentEvents fills, it becomes oldSentEvents and a new one starts, so an
+// event is still recognized for at least sentEventsMax sends after it went
+// out, which covers a match's room states repeating across its games.
+const sentEventsMax = 4096
+
+// flushEvents sends the batch's events after its transaction commits.
+func (p *Parser) flushEvents(state *parseState) {
+	events := state.batchEvents
+	state.batchEvents = nil
+	sink := p.sink()
+	if sink == nil {
+		return
+	}
+	if state.sentEvents == nil || len(state.sentEvents) >= sentEventsMax {
+		state.oldSentEvents = state.sentEvents
+		state.sentEvents = make(map[string]bool)
+	}
+	for _, event := range events {
+		state.sentEvents[event.key] = true
+		sink(event)
+	}
+}
+
+// EventCardIDs lists the distinct cards a draft.pick or deck.updated event
+// involves, so a listener can warm caches for them; nil for other events.
+func EventCardIDs(event Event) []int64 {
+	var ids []int64
+	for _, key := range []string{"cardIds", "packCardIds", "pickedCardIds"} {
+		if values, ok := event.Data[key].([]int64); ok {
+			ids = append(ids, values...)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// deckCardIDs is the distinct cards across a deck's sections.
+func deckCardIDs(cards []db.DeckCard) []int64 {
+	ids := make([]int64, 0, len(cards))
+	for _, card := range cards {
+		ids = append(ids, card.CardID)
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+func (p *Parser) emitMatchStarted(state *parseState, arenaMatchID, eventName, opponent, startedAt string) {
+	arenaMatchID = strings.TrimSpace(arenaMatchID)
+	p.emit(state, arenaMatchID, EventMatchStarted, map[string]any{
+		"arenaMatchId": arenaMatchID,
+		"eventName":    eventName,
+		"opponent":     opponent,
+		"startedAt":    startedAt,
+	})
+}
+
+func (p *Parser) emitMatchEnded(state *parseState, arenaMatchID, eventName, result, reason, endedAt string) {
+	arenaMatchID = strings.TrimSpace(arenaMatchID)
+	p.emit(state, arenaMatchID, EventMatchEnded, map[string]any{
+		"arenaMatchId": arenaMatchID,
+		"eventName":    eventName,
+		"result":       result,
+		"reason":       reason,
+		"endedAt":      endedAt,
+	})
+}
+
+func (p *Parser) emitDraftPick(state *parseState, sessionID int64, draftID, eventName string, packNumber, pickNumber int64, pickedCardIDs, packCardIDs []int64, pickedAt string) {
+	p.emit(state, fmt.Sprintf("%d:%d:%d", sessionID, packNumber, pickNumber), EventDraftPick, map[string]any{
+		"draftId":       draftID,
+		"eventName":     eventName,
+		"packNumber":    packNumber,
+		"pickNumber":    pickNumber,
+		"pickedCardIds": pickedCardIDs,
+		"packCardIds":   packCardIDs,
+		"pickedAt":      pickedAt,
+	})
+}
@@ -0,0 +1,85 @@
+package ingest
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestEventSinkReceivesEachMatchEventOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tempDir, "ponder.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	parser := NewParser(db.NewStore(database))
+	var events []Event
+	parser.SetEventSink(func(event Event) { events = append(events, event) })
+
+	logPath := filepath.Join(tempDir, "Player.log")
+	if err := writeLogLines(logPath, []string{`{"PersonaId":"SELF123"}`, completedMatchLine("match-1")}, false); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	if _, err := parser.TailFile(ctx, logPath); err != nil {
+		t.Fatalf("TailFile: %v", err)
+	}
+	// Arena repeats the completed room state; it is not news the second time.
+	if err := writeLogLines(logPath, []string{completedMatchLine("match-1")}, true); err != nil {
+		t.Fatalf("append log: %v", err)
+	}
+	if _, err := parser.TailFile(ctx, logPath); err != nil {
+		t.Fatalf("TailFile: %v", err)
+	}
+
+	if len(events) != 2 || events[0].Type != EventMatchStarted || events[1].Type != EventMatchEnded {
+		t.Fatalf("events = %+v, want match.started then match.ended", events)
+	}
+	if events[1].Data["arenaMatchId"] != "match-1" || events[1].Data["result"] != "win" {
+		t.Fatalf("match.ended data = %v, want a win for match-1", events[1].Data)
+	}
+}
+
+func TestDeckUpdatedEventCarriesCardIDs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tempDir, "ponder.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	parser := NewParser(db.NewStore(database))
+	var events []Event
+	parser.SetEventSink(func(event Event) { events = append(events, event) })
+
+	logPath := filepath.Join(tempDir, "Player.log")
+	if err := writeLogLines(logPath, []string{setDeckLogLine(t, "EventSetDeckV2",
+		`{"EventName":"Ladder","Summary":{"DeckId":"deck-1","Name":"Mono Red","Attributes":[]},"Deck":{"MainDeck":[{"cardId":30,"quantity":4},{"cardId":10,"quantity":20}],"Sideboard":[{"cardId":30,"quantity":1},{"cardId":20,"quantity":2}],"CommandZone":[],"Companions":[]}}`),
+	}, false); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	if _, err := parser.TailFile(ctx, logPath); err != nil {
+		t.Fatalf("TailFile: %v", err)
+	}
+
+	if len(events) != 1 || events[0].Type != EventDeckUpdated {
+		t.Fatalf("events = %+v, want one deck.updated", events)
+	}
+	if ids := EventCardIDs(events[0]); !slices.Equal(ids, []int64{10, 20, 30}) {
+		t.Fatalf("EventCardIDs = %v, want each card once", ids)
+	}
+}
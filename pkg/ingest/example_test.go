@@ -0,0 +1,38 @@
+package ingest_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/ingest"
+)
+
+func Example() {
+	ctx := context.Background()
+	database, err := db.Open("ponder.db")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		log.Fatal(err)
+	}
+	store := db.NewStore(database)
+
+	parser := ingest.NewParser(store)
+	stats, err := parser.ParseFile(ctx, "Player.log", true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%d lines, %d matches\n", stats.LinesRead, stats.MatchesUpserted)
+
+	matches, err := store.ListMatches(ctx, 10, "", "", false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, match := range matches {
+		fmt.Println(match.EventName, match.Result)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 type replayCardState struct {
@@ -108,10 +108,12 @@ func (s *parseState) rememberReplayState(matchID string, gameNumber int64, repla
 }
 
 type greEnvelope struct {
-	Timestamp        string `json:"timestamp"`
-	GREToClientEvent *struct {
-		Messages []greMessage `json:"greToClientMessages"`
-	} `json:"greToClientEvent"`
+	Timestamp        string            `json:"timestamp"`
+	GREToClientEvent *greToClientEvent `json:"greToClientEvent"`
+}
+
+type greToClientEvent struct {
+	Messages []greMessage `json:"greToClientMessages"`
 }
 
 type greMessage struct {
@@ -356,12 +358,138 @@ func fallbackGREZoneType(zoneID int64) string {
 	}
 }
 
-func (p *Parser) handleGREJSON(ctx context.Context, tx *sql.Tx, line string, state *parseState) error {
+// decodeGRE decodes a greToClientEvent line, returning nil when it isn't
+// one or is malformed. Only the timestamp and each message's seats and game
+// state go through json.Unmarshal; the rest of the line is skipped by
+// jsonScanner. Decode workers call it ahead of the writer; see
+// decodePipeline.
+func decodeGRE(line []byte) *greEnvelope {
 	var env greEnvelope
-	if err := json.Unmarshal([]byte(line), &env); err != nil {
+	s := jsonScanner{data: line}
+	if !s.enter('{') {
+		return nil
+	}
+	for s.more('}') {
+		switch string(s.key()) {
+		case "timestamp":
+			s.unmarshal(&env.Timestamp)
+		case "greToClientEvent":
+			env.GREToClientEvent = decodeGREEvent(&s)
+		default:
+			s.value()
+		}
+	}
+	if s.err != nil || env.GREToClientEvent == nil {
+		return nil
+	}
+	return &env
+}
+
+func decodeGREEvent(s *jsonScanner) *greToClientEvent {
+	if !s.enter('{') {
+		return nil
+	}
+	event := &greToClientEvent{}
+	for s.more('}') {
+		if string(s.key()) != "greToClientMessages" {
+			s.value()
+			continue
+		}
+		if !s.enter('[') {
+			continue
+		}
+		for s.more(']') {
+			event.Messages = append(event.Messages, decodeGREMessage(s))
+		}
+	}
+	return event
+}
+
+func decodeGREMessage(s *jsonScanner) greMessage {
+	var msg greMessage
+	if !s.enter('{') {
+		return msg
+	}
+	for s.more('}') {
+		switch string(s.key()) {
+		case "systemSeatIds":
+			s.unmarshal(&msg.SystemSeatIDs)
+		case "gameStateMessage":
+			msg.GameStateMessage = decodeGameState(s)
+		default:
+			s.value()
+		}
+	}
+	return msg
+}
+
+// decodeGameState decodes a gameStateMessage field by field, so its game
+// objects go straight to greGameObject.UnmarshalJSON and the annotation
+// payloads are copied rather than decoded.
+func decodeGameState(s *jsonScanner) *greGameStateMsg {
+	if !s.enter('{') {
+		return nil
+	}
+	msg := &greGameStateMsg{}
+	for s.more('}') {
+		switch string(s.key()) {
+		case "type":
+			s.unmarshal(&msg.Type)
+		case "gameStateId":
+			s.unmarshal(&msg.GameStateID)
+		case "prevGameStateId":
+			s.unmarshal(&msg.PrevGameStateID)
+		case "gameInfo":
+			s.unmarshal(&msg.GameInfo)
+		case "turnInfo":
+			s.unmarshal(&msg.TurnInfo)
+		case "players":
+			s.unmarshal(&msg.Players)
+		case "zones":
+			s.unmarshal(&msg.Zones)
+		case "gameObjects":
+			msg.GameObjects = decodeGameObjects(s)
+		case "diffDeletedInstanceIds":
+			s.unmarshal(&msg.DiffDeletedInstanceIDs)
+		case "actions":
+			msg.Actions = s.rawValue()
+		case "annotations":
+			msg.Annotations = s.rawValue()
+		case "persistentAnnotations":
+			msg.PersistentAnnotations = s.rawValue()
+		default:
+			s.value()
+		}
+	}
+	return msg
+}
+
+func decodeGameObjects(s *jsonScanner) []greGameObject {
+	if !s.enter('[') {
 		return nil
 	}
-	if env.GREToClientEvent == nil {
+	var objects []greGameObject
+	for s.more(']') {
+		raw := s.value()
+		if s.err != nil {
+			return nil
+		}
+		var obj greGameObject
+		if err := obj.UnmarshalJSON(raw); err != nil {
+			s.err = err
+			return nil
+		}
+		objects = append(objects, obj)
+	}
+	return objects
+}
+
+func (p *Parser) handleGREJSON(ctx context.Context, tx *sql.Tx, line []byte, state *parseState) error {
+	env := state.decodedGRE
+	if env == nil {
+		env = decodeGRE(line)
+	}
+	if env == nil {
 		return nil
 	}
 
@@ -389,6 +517,7 @@ func (p *Parser) handleGREJSON(ctx context.Context, tx *sql.Tx, line string, sta
 				return err
 			}
 			state.activeMatchID = matchID
+			state.startMatch(matchID, eventTS)
 			state.rememberSelfSeat(matchID, selfSeat)
 		}
 		if matchID == "" {
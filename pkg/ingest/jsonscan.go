@@ -0,0 +1,195 @@
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var errJSONSyntax = errors.New("malformed JSON")
+
+// jsonScanner walks JSON one value at a time without decoding it, so the
+// parts of a GRE line nothing reads (action prompts, timers, annotations
+// of messages that carry no game state) are stepped over at memory speed
+// instead of going through encoding/json. It checks only as much syntax as
+// it needs to find where values end; the values it hands to json.Unmarshal
+// are checked in full there. The first error sticks, and every method is a
+// no-op after it.
+type jsonScanner struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (s *jsonScanner) fail() {
+	if s.err == nil {
+		s.err = errJSONSyntax
+	}
+}
+
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// enter steps into the object or array opened by open. It reports false,
+// having stepped past the value, when the value is null.
+func (s *jsonScanner) enter(open byte) bool {
+	if s.err != nil {
+		return false
+	}
+	s.skipSpace()
+	if s.pos < len(s.data) && s.data[s.pos] == open {
+		s.pos++
+		return true
+	}
+	if v := s.value(); string(v) != "null" {
+		s.fail()
+	}
+	return false
+}
+
+// more reports whether the container being walked has another member,
+// stepping over the comma before it, or past close after the last one.
+func (s *jsonScanner) more(close byte) bool {
+	if s.err != nil {
+		return false
+	}
+	s.skipSpace()
+	if s.pos >= len(s.data) {
+		s.fail()
+		return false
+	}
+	switch s.data[s.pos] {
+	case close:
+		s.pos++
+		return false
+	case ',':
+		s.pos++
+	}
+	return true
+}
+
+// key returns the next object key, unquoted but with any escapes left in,
+// and steps past its colon.
+func (s *jsonScanner) key() []byte {
+	if s.err != nil {
+		return nil
+	}
+	s.skipSpace()
+	if s.pos >= len(s.data) || s.data[s.pos] != '"' {
+		s.fail()
+		return nil
+	}
+	end := jsonStringEnd(s.data, s.pos)
+	if end < 0 {
+		s.fail()
+		return nil
+	}
+	key := s.data[s.pos+1 : end-1]
+	s.pos = end
+	s.skipSpace()
+	if s.pos >= len(s.data) || s.data[s.pos] != ':' {
+		s.fail()
+		return nil
+	}
+	s.pos++
+	return key
+}
+
+// value returns the bytes of the next value and steps past it.
+func (s *jsonScanner) value() []byte {
+	if s.err != nil {
+		return nil
+	}
+	s.skipSpace()
+	end := jsonValueEnd(s.data, s.pos)
+	if end < 0 {
+		s.fail()
+		return nil
+	}
+	v := s.data[s.pos:end]
+	s.pos = end
+	return v
+}
+
+// unmarshal decodes the next value into v.
+func (s *jsonScanner) unmarshal(v any) {
+	raw := s.value()
+	if s.err != nil {
+		return
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		s.err = err
+	}
+}
+
+// rawValue returns a copy of the next value's bytes, for fields kept as
+// json.RawMessage.
+func (s *jsonScanner) rawValue() json.RawMessage {
+	raw := s.value()
+	if s.err != nil {
+		return nil
+	}
+	return append(json.RawMessage(nil), raw...)
+}
+
+// jsonStringEnd returns the index just past the string opening at
+// data[start], or -1 if it is unterminated.
+func jsonStringEnd(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// jsonValueEnd returns the index just past the value starting at
+// data[start], or -1 if it is cut off.
+func jsonValueEnd(data []byte, start int) int {
+	if start >= len(data) {
+		return -1
+	}
+	switch data[start] {
+	case '"':
+		return jsonStringEnd(data, start)
+	case '{', '[':
+		depth := 0
+		for i := start; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				end := jsonStringEnd(data, i)
+				if end < 0 {
+					return -1
+				}
+				i = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return -1
+	case ',', ':', '}', ']':
+		return -1
+	}
+	for i := start; i < len(data); i++ {
+		switch data[i] {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			return i
+		}
+	}
+	return len(data)
+}
@@ -0,0 +1,39 @@
+package ingest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeGREMatchesFullUnmarshal(t *testing.T) {
+	lines := []string{
+		`{"timestamp":"1772330782309","greToClientEvent":{"greToClientMessages":[{"type":"GREMessageType_GameStateMessage","systemSeatIds":[2],"gameStateMessage":{"type":"GameStateType_Full","gameStateId":1,"gameInfo":{"matchID":"m-1","gameNumber":1},"turnInfo":{"phase":"Phase_Main1","turnNumber":1,"activePlayer":2},"players":[{"lifeTotal":20,"systemSeatNumber":2,"teamId":2}],"zones":[{"zoneId":27,"type":"ZoneType_Stack","visibility":"Visibility_Public","objectInstanceIds":[]}],"gameObjects":[{"instanceId":501,"grpId":9501,"type":"GameObjectType_Card","zoneId":27,"power":{"value":2},"isTapped":true,"name":"say \"}]{\" twice"}],"annotations":[{"id":1,"type":["AnnotationType_ZoneTransfer"]}],"persistentAnnotations":null}}]}}`,
+		// Messages without game state, and keys nothing reads, are skipped.
+		` { "transactionId" : "x\\\"y" , "requestId" : 7, "timestamp" : "1772330782310", "greToClientEvent" : { "extra": [1, {"a": "]"}], "greToClientMessages" : [ {"type":"GREMessageType_ActionsAvailableReq","systemSeatIds":[1],"actionsAvailableReq":{"actions":[{"actionType":"ActionType_Pass"}]}}, null, {"systemSeatIds":[2],"gameStateMessage":{"type":"GameStateType_Diff","diffDeletedInstanceIds":[3,4],"actions":[{"seatId":2}]}} ] } } `,
+		`{"timestamp":"1","greToClientEvent":{"greToClientMessages":null}}`,
+	}
+	for _, line := range lines {
+		var want greEnvelope
+		if err := json.Unmarshal([]byte(line), &want); err != nil {
+			t.Fatalf("unmarshal %s: %v", line, err)
+		}
+		got := decodeGRE([]byte(line))
+		if got == nil || !reflect.DeepEqual(*got, want) {
+			t.Fatalf("decodeGRE(%s) =\n%+v\nwant\n%+v", line, got, want)
+		}
+	}
+
+	for _, line := range []string{
+		`{"timestamp":"1"}`,
+		`{"greToClientEvent":null}`,
+		`{"greToClientEvent":{"greToClientMessages":[{"gameStateMessage":{"gameObjects":[{"instanceId":"x"}]}}]}}`,
+		`{"greToClientEvent":{"greToClientMessages":[{"gameStateMessage":{"type":"cut off`,
+		`{"greToClientEvent":{"greToClientMessages":[}}`,
+		`[1,2]`,
+	} {
+		if got := decodeGRE([]byte(line)); got != nil {
+			t.Fatalf("decodeGRE(%s) = %+v, want nil", line, got)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"sort"
+	"time"
+)
+
+// logStartScanLimit bounds how far LogStartTime reads looking for a
+// timestamp; Arena writes one within the first few hundred lines.
+const logStartScanLimit = 4 << 20
+
+// LogStartTime returns the time of the first timestamped logger line in the
+// log at path, read in loc. ok is false when none is found near the start.
+func LogStartTime(path string, loc *time.Location) (time.Time, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), logStartScanLimit)
+	read := 0
+	for scanner.Scan() && read < logStartScanLimit {
+		line := scanner.Bytes()
+		read += len(line) + 1
+		if ts := unityLogTimestamp(bytes.TrimSpace(line), loc); ts != "" {
+			parsed, err := time.Parse(time.RFC3339Nano, ts)
+			return parsed, err == nil
+		}
+	}
+	return time.Time{}, false
+}
+
+// SortLogsChronologically orders archived copies of Player.log oldest first
+// by the first timestamp in each, so matches and drafts that span two files
+// are replayed in the order they happened. A file without one falls back to
+// its modification time; ties keep name order.
+func SortLogsChronologically(paths []string) []string {
+	type dated struct {
+		path string
+		at   time.Time
+	}
+	logs := make([]dated, 0, len(paths))
+	for _, path := range paths {
+		at, ok := LogStartTime(path, time.Local)
+		if !ok {
+			if info, err := os.Stat(path); err == nil {
+				at = info.ModTime()
+			}
+		}
+		logs = append(logs, dated{path: path, at: at})
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		if !logs[i].at.Equal(logs[j].at) {
+			return logs[i].at.Before(logs[j].at)
+		}
+		return logs[i].path < logs[j].path
+	})
+	out := make([]string, 0, len(logs))
+	for _, log := range logs {
+		out = append(out, log.path)
+	}
+	return out
+}
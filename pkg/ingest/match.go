@@ -8,7 +8,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/model"
 )
 
 type logBusinessEvent struct {
@@ -75,6 +75,91 @@ func (p *Parser) archiveCompletedMatchReplay(ctx context.Context, tx *sql.Tx, ar
 	return err
 }
 
+// maxQueueWait bounds plausible matchmaking waits; anything longer means the
+// pairing request and the room we're seeing don't belong together (a
+// cancelled queue, or a log that skipped the match start).
+const maxQueueWait = 30 * time.Minute
+
+// recordQueueWait stores the time between the last EventEnterPairing request
+// and the first room-state event of the match it produced, then forgets the
+// pending queue entry so later room updates don't re-measure it.
+func (p *Parser) recordQueueWait(ctx context.Context, tx *sql.Tx, state *parseState, matchID, eventName, matchTS string) error {
+	if state.queueEnteredAt == "" || strings.TrimSpace(matchID) == state.activeMatchID {
+		return nil
+	}
+	enteredAt, queueEvent := state.queueEnteredAt, state.queueEventName
+	state.queueEnteredAt, state.queueEventName = "", ""
+
+	if queueEvent != "" && eventName != "" && !strings.EqualFold(queueEvent, eventName) {
+		return nil
+	}
+	foundAt := matchTS
+	if foundAt == "" {
+		foundAt = state.lastUnityLogTimestamp
+	}
+	entered, err := time.Parse(time.RFC3339Nano, enteredAt)
+	if err != nil {
+		return nil
+	}
+	found, err := time.Parse(time.RFC3339Nano, foundAt)
+	if err != nil {
+		return nil
+	}
+	wait := found.Sub(entered)
+	if wait < 0 || wait > maxQueueWait {
+		return nil
+	}
+	return p.store.SetMatchQueueSeconds(ctx, tx, matchID, int64(wait.Round(time.Second)/time.Second))
+}
+
+// matchCreatedPayload is the match-created message, the only one that
+// announces the opponent's rank.
+type matchCreatedPayload struct {
+	MatchID                        string  `json:"matchId"`
+	EventID                        string  `json:"eventId"`
+	OpponentScreenName             string  `json:"opponentScreenName"`
+	OpponentRankingClass           string  `json:"opponentRankingClass"`
+	OpponentRankingTier            int64   `json:"opponentRankingTier"`
+	OpponentMythicPercentile       float64 `json:"opponentMythicPercentile"`
+	OpponentMythicLeaderboardPlace int64   `json:"opponentMythicLeaderboardPlace"`
+}
+
+// handleMatchCreatedJSON stores the opponent's rank from a match-created
+// message, creating the match row if the room state has not arrived yet. The
+// JSON may follow a logger prefix on the same line.
+func (p *Parser) handleMatchCreatedJSON(ctx context.Context, tx *sql.Tx, stats *model.ParseStats, logPath string, lineNo, byteOffset int64, line string, state *parseState) error {
+	start := strings.IndexByte(line, '{')
+	if start < 0 {
+		return nil
+	}
+	var payload matchCreatedPayload
+	if err := json.Unmarshal([]byte(line[start:]), &payload); err != nil {
+		return nil
+	}
+	matchID := strings.TrimSpace(payload.MatchID)
+	if matchID == "" {
+		return nil
+	}
+
+	if _, err := p.store.UpsertMatchStart(ctx, tx, matchID, strings.TrimSpace(payload.EventID), 0, state.lastUnityLogTimestamp); err != nil {
+		return err
+	}
+	if err := p.store.UpdateMatchOpponent(ctx, tx, matchID, payload.OpponentScreenName, ""); err != nil {
+		return err
+	}
+	if err := p.store.UpdateMatchOpponentRank(ctx, tx, matchID, payload.OpponentRankingClass, payload.OpponentRankingTier,
+		payload.OpponentMythicPercentile, payload.OpponentMythicLeaderboardPlace); err != nil {
+		return err
+	}
+
+	if stored, err := p.store.InsertRawEvent(ctx, tx, logPath, lineNo, byteOffset, state.lastUnityLogTimestamp, "match_created", "matchCreated", "", nil, line); err != nil {
+		return err
+	} else if stored {
+		stats.RawEventsStored++
+	}
+	return nil
+}
+
 func parseRoomTimestamp(raw string) string {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
@@ -214,7 +299,12 @@ func (p *Parser) handleRoomStateJSON(ctx context.Context, tx *sql.Tx, stats *mod
 	if _, err := p.store.UpsertMatchStart(ctx, tx, config.MatchID, eventName, selfSeatID, matchTS); err != nil {
 		return err
 	}
+	p.emitMatchStarted(state, config.MatchID, eventName, opponentName, matchTS)
+	if err := p.recordQueueWait(ctx, tx, state, config.MatchID, eventName, matchTS); err != nil {
+		return err
+	}
 	state.activeMatchID = strings.TrimSpace(config.MatchID)
+	state.startMatch(config.MatchID, matchTS)
 	state.rememberSelfSeat(config.MatchID, selfSeatID)
 	if eventName != "" {
 		linked := false
@@ -232,20 +322,25 @@ func (p *Parser) handleRoomStateJSON(ctx context.Context, tx *sql.Tx, stats *mod
 		}
 	}
 
+	if strings.EqualFold(strings.TrimSpace(info.StateType), "MatchGameRoomStateType_MatchCompleted") {
+		state.endMatch(config.MatchID)
+	}
 	if strings.EqualFold(strings.TrimSpace(info.StateType), "MatchGameRoomStateType_MatchCompleted") && selfTeamID > 0 && info.FinalMatchResult != nil {
 		winningTeamID, reason := chooseMatchResult(info.FinalMatchResult.ResultList)
 		if winningTeamID > 0 {
-			if _, result, changed, err := p.store.UpdateMatchEnd(ctx, tx, config.MatchID, selfTeamID, winningTeamID, 0, 0, reason, matchTS); err != nil {
+			if endedEventName, result, changed, err := p.store.UpdateMatchEnd(ctx, tx, config.MatchID, selfTeamID, winningTeamID, 0, 0, reason, matchTS); err != nil {
 				return err
 			} else if err := p.queueCompletedMatchIfRankPending(ctx, tx, config.MatchID, result, changed); err != nil {
 				return err
 			} else if err := p.archiveCompletedMatchReplay(ctx, tx, config.MatchID, result); err != nil {
 				return err
+			} else if changed {
+				p.emitMatchEnded(state, config.MatchID, endedEventName, result, reason, matchTS)
 			}
 		}
 	}
 
-	if stored, err := p.store.InsertRawEvent(ctx, tx, logPath, lineNo, byteOffset, "room_state", "matchGameRoomStateChangedEvent", "", nil, ""); err != nil {
+	if stored, err := p.store.InsertRawEvent(ctx, tx, logPath, lineNo, byteOffset, state.lastUnityLogTimestamp, "room_state", "matchGameRoomStateChangedEvent", "", nil, line); err != nil {
 		return err
 	} else if stored {
 		stats.RawEventsStored++
@@ -0,0 +1,143 @@
+package ingest
+
+import (
+	"strings"
+	"time"
+)
+
+// Per-match parse state (seats, turn, zones, replay state) is only read
+// while its match is being played, but a tailing daemon keeps one parseState
+// for days. When a match starts, the state of every other match that has
+// ended, started more than matchStateMaxAge before it, or never showed its
+// start in this log (the log began mid-match) is dropped, so memory stays at
+// about one match however long the session runs.
+const matchStateMaxAge = 6 * time.Hour
+
+type matchLife struct {
+	// startedAt is the log time of the match's first room state or start
+	// event; zero when that had no timestamp.
+	startedAt time.Time
+	ended     bool
+}
+
+// startMatch notes that matchID has started at startedAt (an RFC 3339 time,
+// or "" when unknown), evicting older matches when it is new. Room states
+// repeat for every game of a match, so later calls only fill in startedAt.
+func (s *parseState) startMatch(matchID, startedAt string) {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" {
+		return
+	}
+	at, _ := time.Parse(time.RFC3339Nano, startedAt)
+	if life, ok := s.matchLives[matchID]; ok {
+		if life.startedAt.IsZero() {
+			life.startedAt = at
+		}
+		return
+	}
+	s.evictMatches(matchID, at)
+	if s.matchLives == nil {
+		s.matchLives = make(map[string]*matchLife)
+	}
+	s.matchLives[matchID] = &matchLife{startedAt: at}
+}
+
+// endMatch marks matchID ended; its state goes when the next match starts,
+// since the last lines of a match can follow its result.
+func (s *parseState) endMatch(matchID string) {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" {
+		return
+	}
+	if life, ok := s.matchLives[matchID]; ok {
+		life.ended = true
+		return
+	}
+	if s.matchLives == nil {
+		s.matchLives = make(map[string]*matchLife)
+	}
+	s.matchLives[matchID] = &matchLife{ended: true}
+}
+
+// evictMatches drops the state of matches other than current that are done
+// as of current starting at now (zero when unknown, which spares unended
+// matches whose start is known).
+func (s *parseState) evictMatches(current string, now time.Time) {
+	cutoff := time.Time{}
+	if !now.IsZero() {
+		cutoff = now.Add(-matchStateMaxAge)
+	}
+	for _, matchID := range s.trackedMatches() {
+		if matchID == current {
+			continue
+		}
+		life, ok := s.matchLives[matchID]
+		if ok && !life.ended && (life.startedAt.IsZero() || !life.startedAt.Before(cutoff)) {
+			continue
+		}
+		s.forgetMatch(matchID)
+	}
+}
+
+// trackedMatches lists every match any per-match map holds state for.
+func (s *parseState) trackedMatches() []string {
+	seen := make(map[string]bool)
+	var matchIDs []string
+	add := func(matchID string) {
+		if !seen[matchID] {
+			seen[matchID] = true
+			matchIDs = append(matchIDs, matchID)
+		}
+	}
+	for matchID := range s.matchLives {
+		add(matchID)
+	}
+	for matchID := range s.selfSeatByMatch {
+		add(matchID)
+	}
+	for matchID := range s.turnByMatch {
+		add(matchID)
+	}
+	for matchID := range s.activePlayerByMatch {
+		add(matchID)
+	}
+	for matchID := range s.phaseByMatch {
+		add(matchID)
+	}
+	for matchID := range s.zoneTypeByMatch {
+		add(matchID)
+	}
+	for matchID := range s.zoneVisibilityByMatch {
+		add(matchID)
+	}
+	for matchID := range s.zoneOwnerSeatByMatch {
+		add(matchID)
+	}
+	for matchID := range s.gameNumberByMatch {
+		add(matchID)
+	}
+	for key := range s.replayByMatchGame {
+		if i := strings.LastIndexByte(key, ':'); i > 0 {
+			add(key[:i])
+		}
+	}
+	return matchIDs
+}
+
+// forgetMatch drops everything the parse state holds for matchID.
+func (s *parseState) forgetMatch(matchID string) {
+	delete(s.matchLives, matchID)
+	delete(s.selfSeatByMatch, matchID)
+	delete(s.turnByMatch, matchID)
+	delete(s.activePlayerByMatch, matchID)
+	delete(s.phaseByMatch, matchID)
+	delete(s.zoneTypeByMatch, matchID)
+	delete(s.zoneVisibilityByMatch, matchID)
+	delete(s.zoneOwnerSeatByMatch, matchID)
+	delete(s.gameNumberByMatch, matchID)
+	for key := range s.replayByMatchGame {
+		if i := strings.LastIndexByte(key, ':'); i > 0 && key[:i] == matchID {
+			delete(s.replayByMatchGame, key)
+		}
+	}
+}
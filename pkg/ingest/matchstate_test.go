@@ -0,0 +1,81 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestParseStateKeepsOnlyTheCurrentMatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+	_, matches := writeSyntheticLog(t, logPath, 1<<20)
+	if matches < 2 {
+		t.Fatalf("synthetic log has %d matches, want several", matches)
+	}
+
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	parser := NewParser(db.NewStore(database))
+	if _, err := parser.ParseFile(ctx, logPath, false); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	state := parser.stateForLog(logPath, false)
+	last := fmt.Sprintf("bench-match-%06d", matches)
+	if tracked := state.trackedMatches(); len(tracked) != 1 || tracked[0] != last {
+		t.Fatalf("tracked matches = %v, want only %s", tracked, last)
+	}
+	if len(state.replayByMatchGame) != 1 || state.replayState(last, 1) == nil {
+		t.Fatalf("replay states = %d, want only %s's", len(state.replayByMatchGame), last)
+	}
+}
+
+func TestEvictMatchesDropsEndedAndStaleMatches(t *testing.T) {
+	t.Parallel()
+
+	state := &parseState{}
+	state.startMatch("ended", "2026-03-01T10:00:00Z")
+	state.rememberTurn("ended", 7)
+	state.endMatch("ended")
+	state.startMatch("stale", "2026-03-01T05:00:00Z")
+	state.rememberZoneType("stale", 28, "ZoneType_Battlefield")
+	state.startMatch("recent", "2026-03-01T11:00:00Z")
+	state.rememberReplayState("recent", 2, &replayPublicState{})
+	state.rememberSelfSeat("mid-log", 2)
+	state.rememberReplayState("mid-log", 1, &replayPublicState{})
+
+	state.startMatch("next", "2026-03-01T11:30:00Z")
+	state.rememberPhase("next", "Phase_Main1")
+
+	tracked := state.trackedMatches()
+	slices.Sort(tracked)
+	if !slices.Equal(tracked, []string{"next", "recent"}) {
+		t.Fatalf("tracked matches = %v, want next and recent", tracked)
+	}
+	if state.turn("ended") != 0 || state.zoneType("stale", 28) != "" || state.selfSeat("mid-log") != 0 {
+		t.Fatal("evicted match state is still readable")
+	}
+	if state.replayState("recent", 2) == nil || state.replayState("mid-log", 1) != nil {
+		t.Fatal("replay state not evicted with its match")
+	}
+
+	// A match's later games do not evict anything.
+	state.startMatch("recent", "2026-03-01T12:00:00Z")
+	if len(state.trackedMatches()) != 2 {
+		t.Fatalf("tracked matches after a repeat start = %v", state.trackedMatches())
+	}
+}
@@ -0,0 +1,307 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+// OutgoingRequest is one client request (a `==>` line in Player.log) handed
+// to the handler registered for its method.
+type OutgoingRequest struct {
+	// Method is the Arena RPC name, e.g. "EventJoin".
+	Method string
+	// Payload is the decoded request JSON.
+	Payload []byte
+	// ObservedAt is the log timestamp of the request. It is empty when
+	// stored raw events are reprocessed.
+	ObservedAt string
+	// Tx is the transaction of the batch the line belongs to (committed
+	// every 500 lines); handlers should write through it so that, if they
+	// fail, the line's savepoint undoes their writes.
+	Tx    *sql.Tx
+	Store *db.Store
+	Stats *model.ParseStats
+}
+
+// OutgoingHandler applies one client request. An error undoes only that
+// line's writes, as a store error in a built-in handler would: the line is
+// recorded in parse_errors and parsing carries on with the next one.
+// (Reprocess, which replays stored requests, stops at the error instead.)
+// Requests the handler cannot make sense of should be skipped with a nil
+// error.
+type OutgoingHandler func(ctx context.Context, req OutgoingRequest) error
+
+// outgoingHandler is the form of the built-in handlers, which also see the
+// parse state of the log the request came from.
+type outgoingHandler func(p *Parser, ctx context.Context, state *parseState, req OutgoingRequest) error
+
+var builtinOutgoing = map[string]outgoingHandler{
+	"EventJoin":                (*Parser).applyEventJoin,
+	"EventEnterPairing":        (*Parser).applyEventEnterPairing,
+	"EventClaimPrize":          (*Parser).applyEventClaimPrize,
+	"EventSetDeckV2":           (*Parser).applyEventSetDeck,
+	"EventSetDeckV3":           (*Parser).applyEventSetDeck,
+	"EventPlayerDraftMakePick": (*Parser).applyPlayerDraftPick,
+	"BotDraftDraftPick":        (*Parser).applyBotDraftPick,
+	"DraftCompleteDraft":       (*Parser).applyDraftComplete,
+	"LogBusinessEvents":        (*Parser).applyBusinessEvent,
+}
+
+// RegisterOutgoing makes p pass requests for method to handler, so an
+// embedding application can support an Arena RPC the parser does not know.
+// Registering a method the parser already handles replaces the built-in
+// handler; a nil handler makes p ignore the method. Raw events are stored
+// either way. Register handlers before parsing: the registry is not guarded
+// and is shared with the parsers ParseFiles forks.
+func (p *Parser) RegisterOutgoing(method string, handler OutgoingHandler) {
+	if p.outgoing == nil {
+		p.outgoing = make(map[string]outgoingHandler)
+	}
+	if handler == nil {
+		p.outgoing[method] = nil
+		return
+	}
+	p.outgoing[method] = func(_ *Parser, ctx context.Context, _ *parseState, req OutgoingRequest) error {
+		return handler(ctx, req)
+	}
+}
+
+// outgoingHandlerFor returns the handler for method, registered or built in,
+// or nil when p does nothing with it.
+func (p *Parser) outgoingHandlerFor(method string) outgoingHandler {
+	if handler, ok := p.outgoing[method]; ok {
+		return handler
+	}
+	return builtinOutgoing[method]
+}
+
+// handlesOutgoing reports whether applyOutgoing acts on method.
+func (p *Parser) handlesOutgoing(method string) bool {
+	return p.outgoingHandlerFor(method) != nil
+}
+
+// applyOutgoing applies one decoded outgoing request to the store. Reprocess
+// calls it directly for stored raw events, with no observedAt.
+func (p *Parser) applyOutgoing(ctx context.Context, tx *sql.Tx, stats *model.ParseStats, state *parseState, method string, requestPayload []byte, observedAt string) error {
+	handler := p.outgoingHandlerFor(method)
+	if handler == nil {
+		return nil
+	}
+	return handler(p, ctx, state, OutgoingRequest{
+		Method:     method,
+		Payload:    requestPayload,
+		ObservedAt: observedAt,
+		Tx:         tx,
+		Store:      p.store,
+		Stats:      stats,
+	})
+}
+
+func (p *Parser) applyEventJoin(ctx context.Context, _ *parseState, r OutgoingRequest) error {
+	var req eventJoinRequest
+	if err := json.Unmarshal(r.Payload, &req); err != nil {
+		return nil
+	}
+	if req.EventName == "" {
+		return nil
+	}
+	return p.store.UpsertEventRunJoin(ctx, r.Tx, req.EventName, req.EntryCurrencyType, req.EntryCurrencyPaid, r.ObservedAt)
+}
+
+func (p *Parser) applyEventEnterPairing(_ context.Context, state *parseState, r OutgoingRequest) error {
+	var req eventEnterPairingRequest
+	_ = json.Unmarshal(r.Payload, &req)
+	state.queueEnteredAt = r.ObservedAt
+	state.queueEventName = strings.TrimSpace(req.EventName)
+	return nil
+}
+
+func (p *Parser) applyEventClaimPrize(ctx context.Context, _ *parseState, r OutgoingRequest) error {
+	var req eventClaimPrizeRequest
+	if err := json.Unmarshal(r.Payload, &req); err != nil {
+		return nil
+	}
+	if req.EventName == "" {
+		return nil
+	}
+	return p.store.MarkEventRunClaimed(ctx, r.Tx, req.EventName, r.ObservedAt)
+}
+
+func (p *Parser) applyEventSetDeck(ctx context.Context, state *parseState, r OutgoingRequest) error {
+	var req eventSetDeckRequest
+	if err := json.Unmarshal(r.Payload, &req); err != nil {
+		return nil
+	}
+	if req.Summary.DeckID == "" {
+		return nil
+	}
+	cards := make([]db.DeckCard, 0, len(req.Deck.MainDeck)+len(req.Deck.Sideboard)+len(req.Deck.CommandZone)+len(req.Deck.Companions))
+	cards = append(cards, cardSectionCards("main", req.Deck.MainDeck)...)
+	cards = append(cards, cardSectionCards("sideboard", req.Deck.Sideboard)...)
+	cards = append(cards, cardSectionCards("command", req.Deck.CommandZone)...)
+	cards = append(cards, cardSectionCards("companion", req.Deck.Companions)...)
+
+	format := formatFromAttributes(req.Summary.Attributes)
+	lastUpdated := ""
+	for _, a := range req.Summary.Attributes {
+		if strings.EqualFold(strings.TrimSpace(a.Name), "LastUpdated") {
+			lastUpdated = strings.Trim(strings.TrimSpace(a.Value), `"`)
+			break
+		}
+	}
+
+	_, err := p.store.UpsertDeck(ctx, r.Tx, req.Summary.DeckID, req.EventName, req.Summary.Name, format, "event_set_deck", lastUpdated, cards)
+	if err != nil {
+		return err
+	}
+	state.rememberEventDeck(req.EventName, req.Summary.DeckID)
+	r.Stats.DecksUpserted++
+	p.emit(state, req.Summary.DeckID+"@"+r.ObservedAt, EventDeckUpdated, map[string]any{
+		"arenaDeckId": req.Summary.DeckID,
+		"name":        req.Summary.Name,
+		"eventName":   req.EventName,
+		"format":      format,
+		"cards":       len(cards),
+		"cardIds":     deckCardIDs(cards),
+		"updatedAt":   r.ObservedAt,
+	})
+	return nil
+}
+
+func (p *Parser) applyPlayerDraftPick(ctx context.Context, state *parseState, r OutgoingRequest) error {
+	var req playerDraftPickRequest
+	if err := json.Unmarshal(r.Payload, &req); err != nil {
+		return nil
+	}
+	if req.DraftID == "" {
+		return nil
+	}
+	draftID := req.DraftID
+	sessionID, err := p.store.EnsureDraftSession(ctx, r.Tx, "", &draftID, false, r.ObservedAt)
+	if err != nil {
+		return err
+	}
+	if err := p.store.InsertDraftPick(ctx, r.Tx, sessionID, req.Pack, req.Pick, req.GrpIDs, nil, r.ObservedAt); err != nil {
+		return err
+	}
+	r.Stats.DraftPicksAdded++
+	p.emitDraftPick(state, sessionID, draftID, "", req.Pack, req.Pick, req.GrpIDs, nil, r.ObservedAt)
+	return nil
+}
+
+func (p *Parser) applyBotDraftPick(ctx context.Context, state *parseState, r OutgoingRequest) error {
+	var req botDraftPickRequest
+	if err := json.Unmarshal(r.Payload, &req); err != nil {
+		return nil
+	}
+	if req.EventName == "" {
+		return nil
+	}
+	sessionID, err := p.store.EnsureDraftSession(ctx, r.Tx, req.EventName, nil, true, r.ObservedAt)
+	if err != nil {
+		return err
+	}
+	picked := parseStringIDsToInt64(req.PickInfo.CardIDs)
+	if err := p.store.InsertDraftPick(ctx, r.Tx, sessionID, req.PickInfo.PackNumber, req.PickInfo.PickNumber, picked, nil, r.ObservedAt); err != nil {
+		return err
+	}
+	r.Stats.DraftPicksAdded++
+	p.emitDraftPick(state, sessionID, "", req.EventName, req.PickInfo.PackNumber, req.PickInfo.PickNumber, picked, nil, r.ObservedAt)
+	return nil
+}
+
+func (p *Parser) applyDraftComplete(ctx context.Context, _ *parseState, r OutgoingRequest) error {
+	var req draftCompleteRequest
+	if err := json.Unmarshal(r.Payload, &req); err != nil {
+		return nil
+	}
+	return p.store.CompleteDraftSession(ctx, r.Tx, req.EventName, nil, req.IsBotDraft, r.ObservedAt)
+}
+
+// applyBusinessEvent handles the LogBusinessEvents kinds the parser reads:
+// 24 is a human draft pick, 3 a match start and 4 a match end.
+func (p *Parser) applyBusinessEvent(ctx context.Context, state *parseState, r OutgoingRequest) error {
+	var evt logBusinessEvent
+	if err := json.Unmarshal(r.Payload, &evt); err != nil {
+		return nil
+	}
+	tx := r.Tx
+	switch evt.EventType {
+	case 24:
+		if evt.DraftID == "" || evt.PackNumber <= 0 || evt.PickNumber <= 0 {
+			return nil
+		}
+
+		eventName := evt.EventID
+		if eventName == "" {
+			eventName = evt.EventName
+		}
+		draftTS := evt.EventTime
+		if strings.TrimSpace(draftTS) == "" {
+			draftTS = r.ObservedAt
+		}
+
+		draftID := evt.DraftID
+		sessionID, err := p.store.EnsureDraftSession(ctx, tx, eventName, &draftID, false, draftTS)
+		if err != nil {
+			return err
+		}
+
+		var picked []int64
+		if evt.PickGrpID > 0 {
+			picked = []int64{evt.PickGrpID}
+		}
+		if err := p.store.InsertDraftPick(ctx, tx, sessionID, evt.PackNumber, evt.PickNumber, picked, evt.CardsInPack, draftTS); err != nil {
+			return err
+		}
+		p.emitDraftPick(state, sessionID, draftID, eventName, evt.PackNumber, evt.PickNumber, picked, evt.CardsInPack, draftTS)
+	case 3:
+		if evt.MatchID == "" {
+			return nil
+		}
+		eventName := evt.EventID
+		if eventName == "" {
+			eventName = evt.EventName
+		}
+		_, err := p.store.UpsertMatchStart(ctx, tx, evt.MatchID, eventName, evt.SeatID, evt.EventTime)
+		if err != nil {
+			return err
+		}
+		p.emitMatchStarted(state, evt.MatchID, eventName, "", evt.EventTime)
+		state.activeMatchID = strings.TrimSpace(evt.MatchID)
+		state.startMatch(evt.MatchID, evt.EventTime)
+		state.rememberSelfSeat(evt.MatchID, evt.SeatID)
+		linked := false
+		if arenaDeckID := state.eventDeck(eventName); arenaDeckID != "" {
+			linked, _ = p.store.LinkMatchToDeckByArenaDeckID(ctx, tx, evt.MatchID, arenaDeckID, "event_deck")
+		}
+		if !linked {
+			_ = p.store.LinkMatchToLatestDeckByEvent(ctx, tx, evt.MatchID, eventName, "pre_match")
+		}
+		r.Stats.MatchesUpserted++
+	case 4:
+		if evt.MatchID == "" {
+			return nil
+		}
+		endedEventName, result, changed, err := p.store.UpdateMatchEnd(ctx, tx, evt.MatchID, evt.TeamID, evt.WinningTeamID, evt.TurnCount, evt.SecondsCount, evt.WinningReason, evt.EventTime)
+		if err != nil {
+			return err
+		}
+		if err := p.queueCompletedMatchIfRankPending(ctx, tx, evt.MatchID, result, changed); err != nil {
+			return err
+		}
+		if err := p.archiveCompletedMatchReplay(ctx, tx, evt.MatchID, result); err != nil {
+			return err
+		}
+		state.endMatch(evt.MatchID)
+		if changed {
+			p.emitMatchEnded(state, evt.MatchID, endedEventName, result, evt.WinningReason, evt.EventTime)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// ParseFiles parses each log in paths, oldest first, and returns their
+// stats in the same order. With workers above 1, every file but the last is
+// parsed concurrently, each by its own parser in its own transactions, and
+// the last (usually the live Player.log) is parsed after them so its decks
+// and offsets are the ones left standing. The concurrent files finish in any
+// order, so the writes where the last one wins are ordered by the data
+// instead: UpsertDeck keeps the decklist with the newest Arena timestamp,
+// the player name is rewritten from the newest file that carried one, and
+// an event run's results and claim create its row when they land before
+// the join, which then fills in the entry fee. SQLite still takes one
+// writer at a time; the gain is in reading and decoding lines while another
+// file holds the lock, and in running draft repair once rather than per file.
+func (p *Parser) ParseFiles(ctx context.Context, paths []string, resume bool, workers int) ([]model.ParseStats, error) {
+	out := make([]model.ParseStats, 0, len(paths))
+	if workers <= 1 || len(paths) <= 2 {
+		for _, path := range paths {
+			stats, err := p.ParseFile(ctx, path, resume)
+			if interrupted(ctx, err) {
+				out = append(out, stats)
+			}
+			if err != nil {
+				return out, fmt.Errorf("parse %s: %w", path, err)
+			}
+			out = append(out, stats)
+		}
+		return out, nil
+	}
+
+	earlier := paths[:len(paths)-1]
+	stats, err := p.parseConcurrently(ctx, earlier, resume, workers)
+	out = append(out, stats...)
+	if err != nil {
+		return out, err
+	}
+
+	var repair bool
+	for _, s := range stats {
+		repair = repair || s.RawEventsStored > 0 || s.DraftPicksAdded > 0
+	}
+	if repair {
+		if err := p.store.RepairDraftDataFromRawEvents(ctx); err != nil {
+			return out, fmt.Errorf("repair draft data after ingest: %w", err)
+		}
+	}
+
+	last := paths[len(paths)-1]
+	lastStats, err := p.ParseFile(ctx, last, resume)
+	if interrupted(ctx, err) {
+		out = append(out, lastStats)
+	}
+	if err != nil {
+		return out, fmt.Errorf("parse %s: %w", last, err)
+	}
+	return append(out, lastStats), nil
+}
+
+// interrupted reports whether err is ParseFile stopping for a canceled ctx,
+// in which case the lines it read were committed and its stats count them.
+func interrupted(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err())
+}
+
+// parseConcurrently parses paths with up to workers forks of p. The first
+// failure cancels the files not yet finished. When the caller's ctx is
+// canceled instead, the stats of every file started so far are returned,
+// in path order, along with ctx's error.
+func (p *Parser) parseConcurrently(parent context.Context, paths []string, resume bool, workers int) ([]model.ParseStats, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	stats := make([]model.ParseStats, len(paths))
+	forks := make([]*Parser, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for range min(workers, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				forks[i] = p.fork()
+				var err error
+				stats[i], err = forks[i].ParseFile(ctx, paths[i], resume)
+				if err != nil {
+					// Files cancelled because of this one report that
+					// instead; only the first failure is the cause.
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("parse %s: %w", paths[i], err)
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+feed:
+	for i := range paths {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := parent.Err(); err != nil {
+		// Interrupted files committed what they read, so their stats stand.
+		var started []model.ParseStats
+		for i := range paths {
+			if forks[i] != nil {
+				started = append(started, stats[i])
+			}
+		}
+		return started, err
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Carry forward who the player is from the newest file that said, as a
+	// sequential parse would have. The forks saved names in the order they
+	// finished, so the stored name is rewritten from the newest one too.
+	for i := len(forks) - 1; i >= 0; i-- {
+		if forks[i] == nil {
+			continue
+		}
+		forks[i].stateMu.Lock()
+		personaID, playerName := forks[i].personaID, forks[i].playerName
+		forks[i].stateMu.Unlock()
+		if personaID != "" {
+			p.rememberPersonaID(personaID)
+			p.rememberPlayerName(playerName)
+			if err := p.saveNewestPlayerName(ctx, playerName); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return stats, nil
+}
+
+func (p *Parser) saveNewestPlayerName(ctx context.Context, playerName string) error {
+	if playerName == "" {
+		return nil
+	}
+	tx, err := p.store.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin player name: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := p.store.SavePlayerName(ctx, tx, playerName); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit player name: %w", err)
+	}
+	return nil
+}
+
+// fork returns a parser for one more file: it shares p's store and what p
+// knows about the player, but keeps its own per-log and pending-rank state.
+func (p *Parser) fork() *Parser {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return &Parser{
+		store:           p.store,
+		stateByLog:      make(map[string]*parseState),
+		personaID:       p.personaID,
+		playerName:      p.playerName,
+		countEventKinds: p.countEventKinds,
+		skipDraftRepair: true,
+		decodeWorkers:   p.decodeWorkers,
+		outgoing:        p.outgoing,
+	}
+}
@@ -0,0 +1,160 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestParseFilesParsesArchivesConcurrently(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tempDir, "ponder.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	parser := NewParser(db.NewStore(database))
+
+	var paths []string
+	for i := 1; i <= 5; i++ {
+		path := filepath.Join(tempDir, fmt.Sprintf("Player-2024030%dT170000Z.log", i))
+		contents := fmt.Sprintf(`{"PersonaId":"SELF123"}
+{"timestamp":"1773367612385","matchGameRoomStateChangedEvent":{"gameRoomInfo":{"gameRoomConfig":{"matchId":"match-%d","reservedPlayers":[{"userId":"OPP456","playerName":"Opponent","systemSeatId":1,"teamId":1,"eventId":"Traditional_Ladder"},{"userId":"SELF123","playerName":"Self","systemSeatId":2,"teamId":2,"eventId":"Traditional_Ladder"}]},"stateType":"MatchGameRoomStateType_MatchCompleted","finalMatchResult":{"matchId":"match-%d","matchCompletedReason":"MatchCompletedReasonType_Success","resultList":[{"scope":"MatchScope_Match","result":"ResultType_WinLoss","winningTeamId":2,"reason":"ResultReason_Game"}]}}}}
+`, i, i)
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatalf("write log: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	stats, err := parser.ParseFiles(ctx, paths, false, 3)
+	if err != nil {
+		t.Fatalf("ParseFiles: %v", err)
+	}
+	if len(stats) != len(paths) {
+		t.Fatalf("stats = %d entries, want %d", len(stats), len(paths))
+	}
+	for i, path := range paths {
+		if stats[i].LogPath != path {
+			t.Fatalf("stats[%d].LogPath = %q, want %q", i, stats[i].LogPath, path)
+		}
+	}
+
+	var wins int
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM matches WHERE result = 'win'`).Scan(&wins); err != nil {
+		t.Fatalf("count matches: %v", err)
+	}
+	if wins != len(paths) {
+		t.Fatalf("won matches = %d, want %d", wins, len(paths))
+	}
+}
+
+func TestParseFilesReturnsStatsOfInterruptedFile(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tempDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tempDir, "ponder.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	parser := NewParser(db.NewStore(database))
+	parser.RegisterOutgoing("GetPlayerCardsV3", func(_ context.Context, _ OutgoingRequest) error {
+		cancel()
+		return nil
+	})
+
+	prevPath := filepath.Join(tempDir, "Player-prev.log")
+	if err := writeLogLines(prevPath, []string{
+		setDeckLogLine(t, "EventJoin", `{"EventName":"Ladder_A"}`),
+	}, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+	logPath := filepath.Join(tempDir, "Player.log")
+	if err := writeLogLines(logPath, []string{
+		setDeckLogLine(t, "EventJoin", `{"EventName":"Ladder_B"}`),
+		setDeckLogLine(t, "GetPlayerCardsV3", `{"cards":3}`),
+		setDeckLogLine(t, "EventJoin", `{"EventName":"Ladder_C"}`),
+	}, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+
+	stats, err := parser.ParseFiles(ctx, []string{prevPath, logPath}, true, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ParseFiles error = %v, want context.Canceled", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("stats = %d entries, want both files", len(stats))
+	}
+	if stats[1].LogPath != logPath || stats[1].LinesRead != 2 {
+		t.Fatalf("stats[1] = %s with %d lines, want %s with the 2 lines committed", stats[1].LogPath, stats[1].LinesRead, logPath)
+	}
+}
+
+func TestEventRunKeepsRecordWhenMatchesFinishBeforeJoin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tempDir, "ponder.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	store := db.NewStore(database)
+
+	joinPath := filepath.Join(tempDir, "Player-20240301T170000Z.log")
+	if err := writeLogLines(joinPath, []string{
+		setDeckLogLine(t, "EventJoin", `{"EventName":"QuickDraft_TMT_20260301","EntryCurrencyType":"Gem","EntryCurrencyPaid":750}`),
+	}, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+	matchPath := filepath.Join(tempDir, "Player-20240302T170000Z.log")
+	if err := writeLogLines(matchPath, []string{
+		`{"PersonaId":"SELF123"}`,
+		`{"timestamp":"1773367612385","matchGameRoomStateChangedEvent":{"gameRoomInfo":{"gameRoomConfig":{"matchId":"match-1","reservedPlayers":[{"userId":"OPP456","playerName":"Opponent","systemSeatId":1,"teamId":1,"eventId":"QuickDraft_TMT_20260301"},{"userId":"SELF123","playerName":"Self","systemSeatId":2,"teamId":2,"eventId":"QuickDraft_TMT_20260301"}]},"stateType":"MatchGameRoomStateType_MatchCompleted","finalMatchResult":{"matchId":"match-1","matchCompletedReason":"MatchCompletedReasonType_Success","resultList":[{"scope":"MatchScope_Match","result":"ResultType_WinLoss","winningTeamId":2,"reason":"ResultReason_Game"}]}}}}`,
+		setDeckLogLine(t, "EventClaimPrize", `{"EventName":"QuickDraft_TMT_20260301"}`),
+	}, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+
+	// The newer file, with the run's matches and claim, commits before the
+	// older one with its join, as when ParseFiles' workers finish out of
+	// order.
+	for _, path := range []string{matchPath, joinPath} {
+		if _, err := NewParser(store).ParseFile(ctx, path, false); err != nil {
+			t.Fatalf("ParseFile(%s): %v", filepath.Base(path), err)
+		}
+	}
+
+	var wins, losses, paid int64
+	var status, currency string
+	if err := database.QueryRowContext(ctx, `
+		SELECT wins, losses, status, COALESCE(entry_currency_type, ''), COALESCE(entry_currency_paid, 0)
+		FROM event_runs WHERE event_name = 'QuickDraft_TMT_20260301'
+	`).Scan(&wins, &losses, &status, &currency, &paid); err != nil {
+		t.Fatalf("read event run: %v", err)
+	}
+	if wins != 1 || losses != 0 || status != "claimed" || currency != "Gem" || paid != 750 {
+		t.Fatalf("event run = %d-%d %s, entry %d %s; want 1-0 claimed, entry 750 Gem", wins, losses, status, paid, currency)
+	}
+}
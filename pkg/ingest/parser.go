@@ -0,0 +1,1100 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/solean/ponder/internal/metrics"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+var (
+	rePersonaPlain   = regexp.MustCompile(`"PersonaId":"([A-Za-z0-9_\-]+)"`)
+	rePersonaEscaped = regexp.MustCompile(`\\\"PersonaId\\\":\\\"([A-Za-z0-9_\-]+)\\\"`)
+	rePersonaMatchTo = regexp.MustCompile(`Match to ([A-Za-z0-9_\-]+):`)
+	reClientID       = regexp.MustCompile(`"clientId"\s*:\s*"([A-Za-z0-9_\-]+)"`)
+	reScreenName     = regexp.MustCompile(`"screenName"\s*:\s*"([^"]+)"`)
+)
+
+// Parser reads MTG Arena's Player.log into a Store. One Parser may parse
+// several logs; each log's position and match state is kept separately.
+type Parser struct {
+	store                   *db.Store
+	stateMu                 sync.Mutex
+	stateByLog              map[string]*parseState
+	personaID               string
+	playerName              string
+	pendingCompletedMatches []string
+	countEventKinds         bool
+	// skipDraftRepair leaves draft repair to ParseFiles, which runs it once
+	// after parsing files concurrently instead of once per file.
+	skipDraftRepair bool
+	// tailed remembers which file each TailFile path was when last parsed,
+	// so a rotation is seen even once the new log outgrows the old offset.
+	tailed map[string]os.FileInfo
+	// eventSink receives what tailing stores; see SetEventSink.
+	eventSink EventSink
+	// decodeWorkers is how many goroutines decode GRE messages ahead of
+	// the writer; see SetDecodeWorkers.
+	decodeWorkers int
+	// outgoing holds handlers set with RegisterOutgoing, ahead of the
+	// built-in ones.
+	outgoing map[string]outgoingHandler
+}
+
+// NewParser returns a parser writing to store.
+func NewParser(store *db.Store) *Parser {
+	parser := &Parser{
+		store:         store,
+		stateByLog:    make(map[string]*parseState),
+		tailed:        make(map[string]os.FileInfo),
+		decodeWorkers: runtime.GOMAXPROCS(0),
+	}
+
+	if store != nil {
+		if playerName, err := store.PlayerName(context.Background()); err == nil {
+			parser.playerName = playerName
+		}
+	}
+
+	return parser
+}
+
+// SetDecodeWorkers sets how many goroutines decode game-state JSON ahead of
+// the writer when ParseFile reads a megabyte or more. The default is
+// GOMAXPROCS; 1 or less decodes on the writer, as tailing always does for
+// small reads.
+func (p *Parser) SetDecodeWorkers(n int) {
+	p.decodeWorkers = n
+}
+
+// CountEventKinds makes ParseFile fill ParseStats.EventKinds and
+// UnrecognizedKinds, for `parse -dry-run`.
+func (p *Parser) CountEventKinds() {
+	p.countEventKinds = true
+}
+
+// countEventKind tallies one dispatched line by kind. Lines of a kind the
+// parser has no handler for are also tallied as unrecognized; they are still
+// stored as raw events.
+func (p *Parser) countEventKind(stats *model.ParseStats, kind string, recognized bool) {
+	if !p.countEventKinds {
+		return
+	}
+	if stats.EventKinds == nil {
+		stats.EventKinds = map[string]int64{}
+		stats.UnrecognizedKinds = map[string]int64{}
+	}
+	stats.EventKinds[kind]++
+	if !recognized {
+		stats.UnrecognizedKinds[kind]++
+	}
+}
+
+// now reads the store's clock so tests that freeze the store also freeze
+// parse stats and the timezone log timestamps are read in.
+func (p *Parser) now() time.Time {
+	if p.store == nil {
+		return db.SystemClock.Now()
+	}
+	return p.store.Clock().Now()
+}
+
+func (p *Parser) stateForLog(logPath string, reset bool) *parseState {
+	key := strings.TrimSpace(logPath)
+	if key == "" {
+		return &parseState{
+			personaID:  p.personaID,
+			playerName: p.playerName,
+		}
+	}
+
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	if reset {
+		state := &parseState{
+			personaID:  p.personaID,
+			playerName: p.playerName,
+		}
+		p.stateByLog[key] = state
+		return state
+	}
+
+	state, ok := p.stateByLog[key]
+	if !ok || state == nil {
+		state = &parseState{
+			personaID:  p.personaID,
+			playerName: p.playerName,
+		}
+		p.stateByLog[key] = state
+	}
+	return state
+}
+
+type parseState struct {
+	personaID             string
+	playerName            string
+	activeMatchID         string
+	selfSeatByMatch       map[string]int64
+	turnByMatch           map[string]int64
+	activePlayerByMatch   map[string]int64
+	phaseByMatch          map[string]string
+	zoneTypeByMatch       map[string]map[int64]string
+	zoneVisibilityByMatch map[string]map[int64]string
+	zoneOwnerSeatByMatch  map[string]map[int64]int64
+	gameNumberByMatch     map[string]int64
+	deckByEvent           map[string]string
+	replayByMatchGame     map[string]*replayPublicState
+	// matchLives says which per-match state evictMatches may drop.
+	matchLives                map[string]*matchLife
+	lastUnityLogTimestamp     string
+	pendingResponseMethod     string
+	pendingResponseRequestID  string
+	pendingResponseObservedAt string
+	queueEnteredAt            string
+	queueEventName            string
+	// decodedGRE is the line being processed, already decoded by a decode
+	// worker when it is a GRE message; nil when parsing without workers.
+	decodedGRE *greEnvelope
+	// lineEvents are emitted by the line being processed, batchEvents by
+	// the lines kept since the last commit; sentEvents and oldSentEvents
+	// de-duplicate them.
+	lineEvents    []Event
+	batchEvents   []Event
+	sentEvents    map[string]bool
+	oldSentEvents map[string]bool
+	// lineTx is the transaction of the line being processed, set while
+	// processLineInSavepoint runs; lineSavepoint reports whether the line
+	// has opened its savepoint yet.
+	lineTx        *sql.Tx
+	lineSavepoint bool
+}
+
+// beginLineWrites opens the line's savepoint before its first write. Most
+// lines write nothing, so they never pay for one.
+func (s *parseState) beginLineWrites(ctx context.Context) error {
+	if s.lineTx == nil || s.lineSavepoint {
+		return nil
+	}
+	if _, err := s.lineTx.ExecContext(ctx, `SAVEPOINT parse_line`); err != nil {
+		return fmt.Errorf("open line savepoint: %w", err)
+	}
+	s.lineSavepoint = true
+	return nil
+}
+
+func (s *parseState) rememberEventDeck(eventName, arenaDeckID string) {
+	eventName = strings.TrimSpace(eventName)
+	arenaDeckID = strings.TrimSpace(arenaDeckID)
+	if eventName == "" || arenaDeckID == "" {
+		return
+	}
+	if s.deckByEvent == nil {
+		s.deckByEvent = make(map[string]string)
+	}
+	s.deckByEvent[eventName] = arenaDeckID
+}
+
+func (s *parseState) eventDeck(eventName string) string {
+	eventName = strings.TrimSpace(eventName)
+	if eventName == "" || s.deckByEvent == nil {
+		return ""
+	}
+	return s.deckByEvent[eventName]
+}
+
+func (s *parseState) rememberSelfSeat(matchID string, seatID int64) {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || seatID <= 0 {
+		return
+	}
+	if s.selfSeatByMatch == nil {
+		s.selfSeatByMatch = make(map[string]int64)
+	}
+	s.selfSeatByMatch[matchID] = seatID
+}
+
+func (s *parseState) selfSeat(matchID string) int64 {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || s.selfSeatByMatch == nil {
+		return 0
+	}
+	return s.selfSeatByMatch[matchID]
+}
+
+func (s *parseState) rememberTurn(matchID string, turnNumber int64) {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || turnNumber <= 0 {
+		return
+	}
+	if s.turnByMatch == nil {
+		s.turnByMatch = make(map[string]int64)
+	}
+	s.turnByMatch[matchID] = turnNumber
+}
+
+func (s *parseState) turn(matchID string) int64 {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || s.turnByMatch == nil {
+		return 0
+	}
+	return s.turnByMatch[matchID]
+}
+
+func (s *parseState) rememberActivePlayer(matchID string, seatID int64) {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || seatID <= 0 {
+		return
+	}
+	if s.activePlayerByMatch == nil {
+		s.activePlayerByMatch = make(map[string]int64)
+	}
+	s.activePlayerByMatch[matchID] = seatID
+}
+
+func (s *parseState) activePlayer(matchID string) int64 {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || s.activePlayerByMatch == nil {
+		return 0
+	}
+	return s.activePlayerByMatch[matchID]
+}
+
+func (s *parseState) rememberPhase(matchID, phase string) {
+	matchID = strings.TrimSpace(matchID)
+	phase = normalizeGREPhase(phase)
+	if matchID == "" || phase == "" {
+		return
+	}
+	if s.phaseByMatch == nil {
+		s.phaseByMatch = make(map[string]string)
+	}
+	s.phaseByMatch[matchID] = phase
+}
+
+func (s *parseState) phase(matchID string) string {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || s.phaseByMatch == nil {
+		return ""
+	}
+	return s.phaseByMatch[matchID]
+}
+
+func (s *parseState) rememberZoneType(matchID string, zoneID int64, zoneType string) {
+	matchID = strings.TrimSpace(matchID)
+	zoneType = normalizeGREZoneType(zoneType)
+	if matchID == "" || zoneID <= 0 || zoneType == "" {
+		return
+	}
+	if s.zoneTypeByMatch == nil {
+		s.zoneTypeByMatch = make(map[string]map[int64]string)
+	}
+	byZone, ok := s.zoneTypeByMatch[matchID]
+	if !ok {
+		byZone = make(map[int64]string)
+		s.zoneTypeByMatch[matchID] = byZone
+	}
+	byZone[zoneID] = zoneType
+}
+
+func (s *parseState) zoneType(matchID string, zoneID int64) string {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || zoneID <= 0 || s.zoneTypeByMatch == nil {
+		return ""
+	}
+	byZone := s.zoneTypeByMatch[matchID]
+	if byZone == nil {
+		return ""
+	}
+	return byZone[zoneID]
+}
+
+func (s *parseState) rememberZoneVisibility(matchID string, zoneID int64, visibility string) {
+	matchID = strings.TrimSpace(matchID)
+	visibility = normalizeGREVisibility(visibility)
+	if matchID == "" || zoneID <= 0 || visibility == "" {
+		return
+	}
+	if s.zoneVisibilityByMatch == nil {
+		s.zoneVisibilityByMatch = make(map[string]map[int64]string)
+	}
+	byZone, ok := s.zoneVisibilityByMatch[matchID]
+	if !ok {
+		byZone = make(map[int64]string)
+		s.zoneVisibilityByMatch[matchID] = byZone
+	}
+	byZone[zoneID] = visibility
+}
+
+func (s *parseState) zoneVisibility(matchID string, zoneID int64) string {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || zoneID <= 0 || s.zoneVisibilityByMatch == nil {
+		return ""
+	}
+	byZone := s.zoneVisibilityByMatch[matchID]
+	if byZone == nil {
+		return ""
+	}
+	return byZone[zoneID]
+}
+
+func (s *parseState) rememberZoneOwnerSeat(matchID string, zoneID, ownerSeatID int64) {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || zoneID <= 0 || ownerSeatID <= 0 {
+		return
+	}
+	if s.zoneOwnerSeatByMatch == nil {
+		s.zoneOwnerSeatByMatch = make(map[string]map[int64]int64)
+	}
+	byZone, ok := s.zoneOwnerSeatByMatch[matchID]
+	if !ok {
+		byZone = make(map[int64]int64)
+		s.zoneOwnerSeatByMatch[matchID] = byZone
+	}
+	byZone[zoneID] = ownerSeatID
+}
+
+func (s *parseState) zoneOwnerSeat(matchID string, zoneID int64) int64 {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || zoneID <= 0 || s.zoneOwnerSeatByMatch == nil {
+		return 0
+	}
+	byZone := s.zoneOwnerSeatByMatch[matchID]
+	if byZone == nil {
+		return 0
+	}
+	return byZone[zoneID]
+}
+
+func (s *parseState) rememberGameNumber(matchID string, gameNumber int64) {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || gameNumber <= 0 {
+		return
+	}
+	if s.gameNumberByMatch == nil {
+		s.gameNumberByMatch = make(map[string]int64)
+	}
+	s.gameNumberByMatch[matchID] = gameNumber
+}
+
+func (s *parseState) gameNumber(matchID string) int64 {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" || s.gameNumberByMatch == nil {
+		return 0
+	}
+	return s.gameNumberByMatch[matchID]
+}
+
+func (s *parseState) clearPendingResponse() {
+	s.pendingResponseMethod = ""
+	s.pendingResponseRequestID = ""
+	s.pendingResponseObservedAt = ""
+}
+
+func (p *Parser) rememberPersonaID(personaID string) {
+	personaID = strings.TrimSpace(personaID)
+	if personaID == "" {
+		return
+	}
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	p.personaID = personaID
+}
+
+func (p *Parser) rememberPlayerName(playerName string) bool {
+	playerName = strings.TrimSpace(playerName)
+	if playerName == "" {
+		return false
+	}
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	if p.playerName == playerName {
+		return false
+	}
+	p.playerName = playerName
+	return true
+}
+
+type outgoingEnvelope struct {
+	ID      string          `json:"id"`
+	Request json.RawMessage `json:"request"`
+}
+
+type eventJoinRequest struct {
+	EventName         string `json:"EventName"`
+	EntryCurrencyType string `json:"EntryCurrencyType"`
+	EntryCurrencyPaid int64  `json:"EntryCurrencyPaid"`
+}
+
+type eventEnterPairingRequest struct {
+	EventName string `json:"EventName"`
+}
+
+type eventClaimPrizeRequest struct {
+	EventName string `json:"EventName"`
+}
+
+type eventSetDeckRequest struct {
+	EventName string `json:"EventName"`
+	Summary   struct {
+		DeckID     string `json:"DeckId"`
+		Name       string `json:"Name"`
+		Attributes []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"Attributes"`
+	} `json:"Summary"`
+	Deck struct {
+		MainDeck []struct {
+			CardID   int64 `json:"cardId"`
+			Quantity int64 `json:"quantity"`
+		} `json:"MainDeck"`
+		Sideboard []struct {
+			CardID   int64 `json:"cardId"`
+			Quantity int64 `json:"quantity"`
+		} `json:"Sideboard"`
+		CommandZone []struct {
+			CardID   int64 `json:"cardId"`
+			Quantity int64 `json:"quantity"`
+		} `json:"CommandZone"`
+		Companions []struct {
+			CardID   int64 `json:"cardId"`
+			Quantity int64 `json:"quantity"`
+		} `json:"Companions"`
+	} `json:"Deck"`
+}
+
+// ParseFile parses logPath from the start, or with resume from where the
+// last parse of it stopped. When ctx is canceled, ParseFile commits the lines
+// read so far and their offset, then returns their stats with ctx's error.
+func (p *Parser) ParseFile(ctx context.Context, logPath string, resume bool) (model.ParseStats, error) {
+	stats, err := p.parseFile(ctx, logPath, resume)
+	recordParseMetrics(ctx, p.store, stats, err)
+	return stats, err
+}
+
+// recordParseMetrics feeds the /metrics counters from one ParseFile call and
+// refreshes ingest lag: how far the saved offset trails the file's size.
+func recordParseMetrics(ctx context.Context, store *db.Store, stats model.ParseStats, err error) {
+	metrics.LinesParsed.Add(stats.LinesRead)
+	metrics.RawEventsStored.Add(stats.RawEventsStored)
+	metrics.MatchesUpserted.Add(stats.MatchesUpserted)
+	metrics.LinesSkipped.Add(stats.LinesSkipped)
+	if err != nil {
+		// A canceled parse saved what it read; it did not fail.
+		if ctx.Err() == nil {
+			metrics.ParseErrors.Inc()
+		}
+		return
+	}
+	metrics.LastIngestSuccess.Set(float64(stats.CompletedAt.UnixMilli()) / 1000)
+	info, statErr := os.Stat(stats.LogPath)
+	if statErr != nil {
+		return
+	}
+	if state, stateErr := store.GetIngestState(ctx, stats.LogPath); stateErr == nil {
+		metrics.IngestLagBytes.Set(float64(max(info.Size()-state.Offset, 0)))
+	}
+}
+
+func (p *Parser) parseFile(ctx context.Context, logPath string, resume bool) (model.ParseStats, error) {
+	stats := model.ParseStats{LogPath: logPath, StartedAt: p.now().UTC()}
+
+	startOffset := int64(0)
+	startLine := int64(0)
+	resetState := !resume
+	if resume {
+		ingestState, err := p.store.GetIngestState(ctx, logPath)
+		if err != nil {
+			return stats, err
+		}
+		if ingestState.Found {
+			startOffset = ingestState.Offset
+			startLine = ingestState.LineNo
+			if startOffset == 0 && startLine == 0 {
+				resetState = true
+			}
+		}
+	}
+
+	file, err := os.Open(logPath)
+	if err != nil {
+		return stats, fmt.Errorf("open log file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return stats, fmt.Errorf("stat log file: %w", err)
+	}
+
+	// MTGA rotates/truncates Player.log. If our saved offset points past EOF,
+	// restart from the beginning of the current file so tailing can recover.
+	if startOffset > info.Size() {
+		startOffset = 0
+		startLine = 0
+		resetState = true
+	}
+
+	state := p.stateForLog(logPath, resetState)
+	// Events of a batch an earlier call failed to commit were never stored.
+	state.batchEvents = nil
+
+	if startOffset > 0 {
+		if _, err := file.Seek(startOffset, io.SeekStart); err != nil {
+			return stats, fmt.Errorf("seek to offset %d: %w", startOffset, err)
+		}
+	}
+
+	reader := bufio.NewReaderSize(file, 4*1024*1024)
+
+	// Canceling ctx stops the parse between lines rather than inside one:
+	// the lines read so far are committed with their offset, so a resumed
+	// parse carries on from the next line. Writes therefore run on writeCtx,
+	// which ctx's cancellation does not reach; database/sql would otherwise
+	// roll the open transaction back the moment ctx is canceled.
+	writeCtx := context.WithoutCancel(ctx)
+	tx, err := p.store.BeginTx(writeCtx)
+	if err != nil {
+		return stats, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	const batchSize = int64(500)
+	lineNo := startLine
+	byteOffset := startOffset
+	linesSinceCommit := int64(0)
+
+	commit := func() error {
+		if err := p.saveProgress(writeCtx, tx, &stats, logPath, byteOffset, lineNo); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit tx: %w", err)
+		}
+		p.flushEvents(state)
+		tx, err = p.store.BeginTx(writeCtx)
+		if err != nil {
+			return fmt.Errorf("begin new tx: %w", err)
+		}
+		linesSinceCommit = 0
+		return nil
+	}
+
+	var lines lineSource = &lineReader{reader: reader}
+	if p.decodeWorkers > 1 && info.Size()-startOffset >= pipelineMinBytes {
+		pipeline := startDecodePipeline(ctx, reader, p.decodeWorkers)
+		defer pipeline.close()
+		lines = pipeline
+	}
+	interrupted := false
+	for {
+		if ctx.Err() != nil {
+			interrupted = true
+			break
+		}
+		line, err := lines.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				interrupted = true
+				break
+			}
+			return stats, err
+		}
+
+		lineStartOffset := byteOffset
+		lineNo++
+		byteOffset += int64(len(line.text))
+		stats.LinesRead++
+		stats.BytesRead += int64(len(line.text))
+		linesSinceCommit++
+
+		state.decodedGRE = line.gre
+		err = p.processLineInSavepoint(writeCtx, tx, &stats, state, logPath, lineNo, lineStartOffset, line.text)
+		state.decodedGRE = nil
+		if err != nil {
+			return stats, fmt.Errorf("process line %d: %w", lineNo, err)
+		}
+
+		if linesSinceCommit >= batchSize {
+			if err := commit(); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	if err := p.saveProgress(writeCtx, tx, &stats, logPath, byteOffset, lineNo); err != nil {
+		return stats, err
+	}
+	if err := tx.Commit(); err != nil {
+		return stats, fmt.Errorf("commit final tx: %w", err)
+	}
+	p.flushEvents(state)
+	if interrupted {
+		// Draft repair is left to the next parse or maintenance run.
+		stats.CompletedAt = p.now().UTC()
+		return stats, ctx.Err()
+	}
+
+	// Raw events are only stored when draft repair can consume them, so their
+	// presence is the trigger to backfill draft metadata. Running here keeps
+	// the repair scans off the API read path.
+	if !p.skipDraftRepair && (stats.RawEventsStored > 0 || stats.DraftPicksAdded > 0) {
+		if err := p.store.RepairDraftDataFromRawEvents(ctx); err != nil {
+			return stats, fmt.Errorf("repair draft data after ingest: %w", err)
+		}
+	}
+
+	stats.CompletedAt = p.now().UTC()
+	return stats, nil
+}
+
+// saveProgress saves the log position in the batch's transaction and, once
+// rows have been stored at RawEventsFull, records that alongside them so
+// maintenance run by another command does not prune them.
+func (p *Parser) saveProgress(ctx context.Context, tx *sql.Tx, stats *model.ParseStats, logPath string, byteOffset, lineNo int64) error {
+	if stats.RawEventsStored > 0 && p.store.RawEventLevel() == db.RawEventsFull {
+		if err := p.store.NoteFullRawEvents(ctx, tx); err != nil {
+			return err
+		}
+	}
+	return p.store.SaveIngestState(ctx, tx, logPath, byteOffset, lineNo)
+}
+
+// Byte prefixes and markers processLine checks before running a regexp or
+// converting a line to a string. Most log lines match none of them.
+var (
+	unityLoggerPrefix  = []byte("[UnityCrossThreadLogger]")
+	outgoingPrefix     = []byte("[UnityCrossThreadLogger]==>")
+	completePrefix     = []byte("<==")
+	personaIDMarker    = []byte("PersonaId")
+	matchToMarker      = []byte("Match to ")
+	clientIDMarker     = []byte(`"clientId"`)
+	screenNameMarker   = []byte(`"screenName"`)
+	inventoryMarker    = []byte(`"InventoryInfo"`)
+	inventoryDTOMarker = []byte(`"DTO_InventoryInfo"`)
+	roomStateMarker    = []byte(`"matchGameRoomStateChangedEvent"`)
+	greEventMarker     = []byte(`"greToClientEvent"`)
+	opponentRankMarker = []byte(`"opponentRankingClass"`)
+)
+
+// processLineInSavepoint runs processLine inside a savepoint, which
+// processLine opens through beginLineWrites once it knows the line is one it
+// writes for. When the line's handlers fail, their writes are rolled back,
+// the line is recorded in parse_errors and counted in LinesSkipped, and
+// parsing carries on with the rest of the batch. Only failures of the
+// transaction itself, or a canceled ctx, are returned.
+func (p *Parser) processLineInSavepoint(ctx context.Context, tx *sql.Tx, stats *model.ParseStats, state *parseState, logPath string, lineNo, byteOffset int64, line []byte) error {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return nil
+	}
+	state.lineTx, state.lineSavepoint = tx, false
+	lineErr := p.processLine(ctx, tx, stats, state, logPath, lineNo, byteOffset, line)
+	opened := state.lineSavepoint
+	state.lineTx, state.lineSavepoint = nil, false
+	if lineErr == nil {
+		if opened {
+			if _, err := tx.ExecContext(ctx, `RELEASE parse_line`); err != nil {
+				return fmt.Errorf("release line savepoint: %w", err)
+			}
+		}
+		state.keepLineEvents()
+		return nil
+	}
+	state.discardLineEvents()
+	if ctx.Err() != nil {
+		return lineErr
+	}
+	if opened {
+		if _, err := tx.ExecContext(ctx, `ROLLBACK TO parse_line`); err != nil {
+			return fmt.Errorf("%w (roll back line savepoint: %v)", lineErr, err)
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE parse_line`); err != nil {
+			return fmt.Errorf("release line savepoint: %w", err)
+		}
+	}
+	if err := p.store.RecordParseError(ctx, tx, logPath, lineNo, byteOffset, lineErr, bytes.TrimSpace(line)); err != nil {
+		return err
+	}
+	stats.LinesSkipped++
+	return nil
+}
+
+// processLine dispatches one log line. line may alias the reader's buffer,
+// so it is only valid for the duration of the call; handlers receive string
+// copies, made only once a line is known to be interesting.
+func (p *Parser) processLine(ctx context.Context, tx *sql.Tx, stats *model.ParseStats, state *parseState, logPath string, lineNo, byteOffset int64, line []byte) error {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+
+	if ts := unityLogTimestamp(line, p.now().Location()); ts != "" {
+		state.lastUnityLogTimestamp = ts
+	}
+
+	// Game-state messages are most of a log's bytes and carry none of the
+	// markers checked below, so they skip those scans of the whole line.
+	if state.pendingResponseMethod == "" && isGREHead(line) {
+		p.countEventKind(stats, "game state (GRE)", true)
+		if err := state.beginLineWrites(ctx); err != nil {
+			return err
+		}
+		if err := p.storeRawLine(ctx, tx, stats, logPath, lineNo, byteOffset, state.lastUnityLogTimestamp, "game_state", "greToClientEvent", line); err != nil {
+			return err
+		}
+		return p.handleGREJSON(ctx, tx, line, state)
+	}
+
+	if state.personaID == "" {
+		if bytes.Contains(line, personaIDMarker) {
+			match := rePersonaPlain.FindSubmatch(line)
+			if len(match) != 2 {
+				match = rePersonaEscaped.FindSubmatch(line)
+			}
+			if len(match) == 2 && !bytes.HasPrefix(match[1], []byte("NoInstallID")) {
+				state.personaID = string(match[1])
+			}
+		}
+		if state.personaID == "" && bytes.Contains(line, matchToMarker) {
+			if m := rePersonaMatchTo.FindSubmatch(line); len(m) == 2 {
+				state.personaID = string(bytes.TrimSpace(m[1]))
+			}
+		}
+		if state.personaID == "" && bytes.Contains(line, clientIDMarker) {
+			if m := reClientID.FindSubmatch(line); len(m) == 2 {
+				state.personaID = string(bytes.TrimSpace(m[1]))
+			}
+		}
+		// Once found, the persona is never looked for again in this log.
+		if state.personaID != "" {
+			p.rememberPersonaID(state.personaID)
+		}
+	}
+	if bytes.Contains(line, screenNameMarker) {
+		if m := reScreenName.FindSubmatch(line); len(m) == 2 {
+			playerName := bytes.TrimSpace(m[1])
+			if len(playerName) > 0 && string(playerName) != state.playerName {
+				state.playerName = string(playerName)
+				if p.rememberPlayerName(state.playerName) {
+					if err := state.beginLineWrites(ctx); err != nil {
+						return err
+					}
+					if err := p.store.SavePlayerName(ctx, tx, state.playerName); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	isJSON := line[0] == '{'
+	if isJSON && (bytes.Contains(line, inventoryMarker) || bytes.Contains(line, inventoryDTOMarker)) {
+		p.countEventKind(stats, "inventory", true)
+		if err := state.beginLineWrites(ctx); err != nil {
+			return err
+		}
+		if err := p.storeRawLine(ctx, tx, stats, logPath, lineNo, byteOffset, state.lastUnityLogTimestamp, "inventory", "InventoryInfo", line); err != nil {
+			return err
+		}
+		if err := p.handleEconomyJSON(ctx, tx, stats, state, logPath, lineNo, string(line)); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if state.pendingResponseMethod != "" && isJSON {
+		p.countEventKind(stats, "response "+state.pendingResponseMethod, true)
+		if err := state.beginLineWrites(ctx); err != nil {
+			return err
+		}
+		if err := p.handleMethodResponse(ctx, tx, stats, state, logPath, lineNo, byteOffset, string(line)); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if bytes.HasPrefix(line, outgoingPrefix) {
+		if method, envelope, ok := splitOutgoing(line); ok {
+			p.countEventKind(stats, "request "+string(method), p.handlesOutgoing(string(method)))
+			if err := state.beginLineWrites(ctx); err != nil {
+				return err
+			}
+			if err := p.handleOutgoing(ctx, tx, stats, state, logPath, lineNo, byteOffset, string(method), string(envelope)); err != nil {
+				return err
+			}
+			return nil
+		}
+	}
+
+	if bytes.HasPrefix(line, completePrefix) {
+		if m, id, ok := splitComplete(line); ok {
+			method, requestID := string(m), string(id)
+			p.countEventKind(stats, "complete "+method, method == "RankGetCombinedRankInfo")
+			if err := state.beginLineWrites(ctx); err != nil {
+				return err
+			}
+			if stored, err := p.store.InsertRawEvent(ctx, tx, logPath, lineNo, byteOffset, state.lastUnityLogTimestamp, "method_complete", method, requestID, nil, p.rawLine(line)); err != nil {
+				return err
+			} else if stored {
+				stats.RawEventsStored++
+			}
+			if method == "RankGetCombinedRankInfo" {
+				state.pendingResponseMethod = method
+				state.pendingResponseRequestID = requestID
+				state.pendingResponseObservedAt = state.lastUnityLogTimestamp
+			} else {
+				state.clearPendingResponse()
+			}
+			return nil
+		}
+	}
+
+	if state.pendingResponseMethod != "" {
+		state.clearPendingResponse()
+	}
+
+	if bytes.Contains(line, opponentRankMarker) {
+		p.countEventKind(stats, "match created", true)
+		if err := state.beginLineWrites(ctx); err != nil {
+			return err
+		}
+		return p.handleMatchCreatedJSON(ctx, tx, stats, logPath, lineNo, byteOffset, string(line), state)
+	}
+
+	if isJSON {
+		if bytes.Contains(line, roomStateMarker) {
+			p.countEventKind(stats, "match room state", true)
+			if err := state.beginLineWrites(ctx); err != nil {
+				return err
+			}
+			if err := p.handleRoomStateJSON(ctx, tx, stats, logPath, lineNo, byteOffset, string(line), state); err != nil {
+				return err
+			}
+			return nil
+		}
+		if bytes.Contains(line, greEventMarker) {
+			p.countEventKind(stats, "game state (GRE)", true)
+			if err := state.beginLineWrites(ctx); err != nil {
+				return err
+			}
+			if err := p.storeRawLine(ctx, tx, stats, logPath, lineNo, byteOffset, state.lastUnityLogTimestamp, "game_state", "greToClientEvent", line); err != nil {
+				return err
+			}
+			if err := p.handleGREJSON(ctx, tx, line, state); err != nil {
+				return err
+			}
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// rawLine is line as raw text for InsertRawEvent, which only keeps the text
+// at db.RawEventsFull; at other levels the line is not copied.
+func (p *Parser) rawLine(line []byte) string {
+	if p.store == nil || p.store.RawEventLevel() != db.RawEventsFull {
+		return ""
+	}
+	return string(line)
+}
+
+// storeRawLine keeps a line whose handler stores no raw event of its own,
+// when the store keeps everything.
+func (p *Parser) storeRawLine(ctx context.Context, tx *sql.Tx, stats *model.ParseStats, logPath string, lineNo, byteOffset int64, observedAt, kind, method string, line []byte) error {
+	text := p.rawLine(line)
+	if text == "" {
+		return nil
+	}
+	if stored, err := p.store.InsertRawEvent(ctx, tx, logPath, lineNo, byteOffset, observedAt, kind, method, "", nil, text); err != nil {
+		return err
+	} else if stored {
+		stats.RawEventsStored++
+	}
+	return nil
+}
+
+// unityLogTimestamp reads the local-time prefix Unity writes on logger
+// lines, interpreting it in loc (the clock's location, normally time.Local).
+// line must already be trimmed.
+func unityLogTimestamp(line []byte, loc *time.Location) string {
+	rest, ok := bytes.CutPrefix(line, unityLoggerPrefix)
+	if !ok || len(rest) == 0 || rest[0] < '0' || rest[0] > '9' {
+		return ""
+	}
+	// The stamp ends at the first M, of AM or PM, within its 22 bytes;
+	// parsing checks the rest.
+	end := bytes.IndexByte(rest[:min(len(rest), len("12/31/2024 12:59:59 PM"))], 'M')
+	if end < 1 || (rest[end-1] != 'A' && rest[end-1] != 'P') {
+		return ""
+	}
+	parsed, err := time.ParseInLocation("1/2/2006 3:04:05 PM", string(rest[:end+1]), loc)
+	if err != nil {
+		return ""
+	}
+	return parsed.UTC().Format(time.RFC3339Nano)
+}
+
+// greHeadBytes is how far into a JSON line isGREHead looks. Arena puts the
+// greToClientEvent key after only the transaction ID, request ID and
+// timestamp.
+const greHeadBytes = 256
+
+// isGREHead reports whether line is a JSON object naming greToClientEvent
+// near its start. Lines with the key further in are still found by
+// processLine's full scan.
+func isGREHead(line []byte) bool {
+	return len(line) > 0 && line[0] == '{' && bytes.Contains(line[:min(len(line), greHeadBytes)], greEventMarker)
+}
+
+// splitOutgoing splits a request line, `[UnityCrossThreadLogger]==> Method
+// {...}`, into its method and envelope JSON. line must already be trimmed.
+func splitOutgoing(line []byte) (method, envelope []byte, ok bool) {
+	rest, ok := bytes.CutPrefix(line, outgoingPrefix)
+	if !ok {
+		return nil, nil, false
+	}
+	method, rest, ok = cutMethodName(rest)
+	if !ok {
+		return nil, nil, false
+	}
+	envelope = bytes.TrimLeft(rest, " \t\f\r\n")
+	if len(envelope) == len(rest) {
+		return nil, nil, false
+	}
+	return method, envelope, true
+}
+
+// splitComplete splits a completion line, `<== Method(requestID)`, into its
+// method and request ID.
+func splitComplete(line []byte) (method, requestID []byte, ok bool) {
+	rest, ok := bytes.CutPrefix(line, completePrefix)
+	if !ok {
+		return nil, nil, false
+	}
+	method, rest, ok = cutMethodName(rest)
+	if !ok || len(rest) == 0 || rest[0] != '(' {
+		return nil, nil, false
+	}
+	end := bytes.IndexByte(rest, ')')
+	if end < 0 {
+		return nil, nil, false
+	}
+	return method, rest[1:end], true
+}
+
+// cutMethodName cuts the whitespace and then the method name
+// ([A-Za-z0-9_]+) off the front of rest.
+func cutMethodName(rest []byte) (method, after []byte, ok bool) {
+	trimmed := bytes.TrimLeft(rest, " \t\f\r\n")
+	if len(trimmed) == len(rest) {
+		return nil, nil, false
+	}
+	n := 0
+	for n < len(trimmed) {
+		c := trimmed[n]
+		if c != '_' && (c < '0' || c > '9') && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			break
+		}
+		n++
+	}
+	if n == 0 {
+		return nil, nil, false
+	}
+	return trimmed[:n], trimmed[n:], true
+}
+
+func decodeRawRequest(raw json.RawMessage) ([]byte, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(trimmed, "\"") {
+		var inner string
+		if err := json.Unmarshal([]byte(trimmed), &inner); err != nil {
+			return nil, fmt.Errorf("decode string request: %w", err)
+		}
+		inner = strings.TrimSpace(inner)
+		if inner == "" {
+			return nil, nil
+		}
+		if strings.HasPrefix(inner, "{") || strings.HasPrefix(inner, "[") {
+			return []byte(inner), nil
+		}
+		return []byte(strconv.Quote(inner)), nil
+	}
+
+	return []byte(trimmed), nil
+}
+
+func formatFromAttributes(attrs []struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}) string {
+	for _, a := range attrs {
+		if strings.EqualFold(strings.TrimSpace(a.Name), "Format") {
+			return strings.Trim(strings.TrimSpace(a.Value), `"`)
+		}
+	}
+	return ""
+}
+
+func cardSectionCards(section string, in []struct {
+	CardID   int64 `json:"cardId"`
+	Quantity int64 `json:"quantity"`
+}) []db.DeckCard {
+	out := make([]db.DeckCard, 0, len(in))
+	for _, c := range in {
+		if c.Quantity <= 0 {
+			continue
+		}
+		out = append(out, db.DeckCard{Section: section, CardID: c.CardID, Quantity: c.Quantity})
+	}
+	return out
+}
+
+func (p *Parser) handleOutgoing(ctx context.Context, tx *sql.Tx, stats *model.ParseStats, state *parseState, logPath string, lineNo, byteOffset int64, method, envelopeJSON string) error {
+	var env outgoingEnvelope
+	if err := json.Unmarshal([]byte(envelopeJSON), &env); err != nil {
+		if stored, err := p.store.InsertRawEvent(ctx, tx, logPath, lineNo, byteOffset, state.lastUnityLogTimestamp, "outgoing_unparsed", method, "", nil, envelopeJSON); err != nil {
+			return err
+		} else if stored {
+			stats.RawEventsStored++
+		}
+		return nil
+	}
+
+	requestPayload, err := decodeRawRequest(env.Request)
+	if err != nil {
+		return fmt.Errorf("decode raw request for %s: %w", method, err)
+	}
+
+	if stored, err := p.store.InsertRawEvent(ctx, tx, logPath, lineNo, byteOffset, state.lastUnityLogTimestamp, "outgoing", method, env.ID, requestPayload, ""); err != nil {
+		return err
+	} else if stored {
+		stats.RawEventsStored++
+	}
+	return p.applyOutgoing(ctx, tx, stats, state, method, requestPayload, state.lastUnityLogTimestamp)
+}
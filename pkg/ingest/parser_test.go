@@ -4,13 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
-	"github.com/solean/ponder/internal/db"
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
 )
 
 func TestChooseGameResultUsesLastMatchingScope(t *testing.T) {
@@ -1086,3 +1088,662 @@ func writeLogLines(path string, lines []string, appendMode bool) error {
 	}
 	return os.WriteFile(path, []byte(payload), 0o644)
 }
+
+func TestParserUsesStoreClockForTimestamps(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	logPath := filepath.Join(tmpDir, "Player.log")
+	lines := []string{
+		`[UnityCrossThreadLogger]7/12/2026 11:40:38 AM`,
+		`{"InventoryInfo":{"SeqId":1,"Changes":[],"Gems":10,"Gold":20}}`,
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	frozen := time.Date(2026, 7, 12, 12, 0, 0, 0, time.FixedZone("UTC-4", -4*60*60))
+	store := db.NewStore(database)
+	store.SetClock(db.FixedClock(frozen))
+	stats, err := NewParser(store).ParseFile(ctx, logPath, false)
+	if err != nil {
+		t.Fatalf("parse file: %v", err)
+	}
+	if !stats.StartedAt.Equal(frozen) || !stats.CompletedAt.Equal(frozen) {
+		t.Fatalf("stats times = %s..%s, want %s", stats.StartedAt, stats.CompletedAt, frozen)
+	}
+
+	history, err := store.ListEconomyHistory(ctx)
+	if err != nil {
+		t.Fatalf("ListEconomyHistory: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("history length = %d, want 1", len(history))
+	}
+	if got, want := history[0].ObservedAt, "2026-07-12T15:40:38Z"; got != want {
+		t.Fatalf("ObservedAt = %q, want %q", got, want)
+	}
+}
+
+func TestParserRecordsQueueWaitFromPairingToRoomState(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	room := func(ts string) string {
+		return `{"timestamp":"` + ts + `","matchGameRoomStateChangedEvent":{"gameRoomInfo":{"gameRoomConfig":{"reservedPlayers":[{"userId":"opp-user","playerName":"Opp","systemSeatId":1,"teamId":1,"eventId":"Ladder"},{"userId":"self-user","playerName":"Self","systemSeatId":2,"teamId":2,"eventId":"Ladder"}],"matchId":"match-queue"},"stateType":"MatchGameRoomStateType_Playing"}}}`
+	}
+	logPath := filepath.Join(tmpDir, "Player.log")
+	lines := []string{
+		`{"clientId":"self-user","screenName":"Self"}`,
+		`[UnityCrossThreadLogger]7/12/2026 11:40:00 AM`,
+		setDeckLogLine(t, "EventEnterPairing", `{"EventName":"Ladder"}`),
+		room("1783870842000"),
+		room("1783870902000"),
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	store := db.NewStore(database)
+	store.SetClock(db.FixedClock(time.Date(2026, 7, 12, 12, 0, 0, 0, time.FixedZone("UTC-4", -4*60*60))))
+	if _, err := NewParser(store).ParseFile(ctx, logPath, false); err != nil {
+		t.Fatalf("parse file: %v", err)
+	}
+
+	matches, err := store.ListMatches(ctx, 10, "", "", false)
+	if err != nil {
+		t.Fatalf("ListMatches: %v", err)
+	}
+	if len(matches) != 1 || matches[0].QueueSeconds == nil || *matches[0].QueueSeconds != 42 {
+		t.Fatalf("matches = %+v, want one match queued 42s", matches)
+	}
+
+	stats, err := store.QueueTimeStats(ctx)
+	if err != nil {
+		t.Fatalf("QueueTimeStats: %v", err)
+	}
+	if stats.Matches != 1 || len(stats.ByEvent) != 1 || stats.ByEvent[0].Key != "Ladder" || stats.ByEvent[0].EventType != "ladder" {
+		t.Fatalf("ByEvent = %+v", stats.ByEvent)
+	}
+	if len(stats.ByHour) != 1 || stats.ByHour[0].Hour == nil || *stats.ByHour[0].Hour != 11 || stats.ByHour[0].MedianSeconds != 42 {
+		t.Fatalf("ByHour = %+v, want local hour 11 with a 42s median", stats.ByHour)
+	}
+}
+
+func TestParserStoresOpponentRankFromMatchCreated(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	// The match-created message precedes the room state, so it has to create
+	// the match row itself; an unranked ("None") opponent stores nothing.
+	logPath := filepath.Join(tmpDir, "Player.log")
+	lines := []string{
+		`{"clientId":"self-user","screenName":"Self"}`,
+		`[UnityCrossThreadLogger]7/12/2026 11:40:00 AM`,
+		`{"matchId":"match-ranked","eventId":"Ladder","opponentScreenName":"Opp","opponentRankingClass":"Mythic","opponentRankingTier":1,"opponentMythicPercentile":87.5,"opponentMythicLeaderboardPlace":0}`,
+		`{"timestamp":"1783870842000","matchGameRoomStateChangedEvent":{"gameRoomInfo":{"gameRoomConfig":{"reservedPlayers":[{"userId":"opp-user","playerName":"Opp","systemSeatId":1,"teamId":1,"eventId":"Ladder"},{"userId":"self-user","playerName":"Self","systemSeatId":2,"teamId":2,"eventId":"Ladder"}],"matchId":"match-ranked"},"stateType":"MatchGameRoomStateType_Playing"}}}`,
+		`[UnityCrossThreadLogger]7/12/2026 12:00:00 PM {"matchId":"match-play","eventId":"Play","opponentScreenName":"Casual","opponentRankingClass":"None","opponentRankingTier":0}`,
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	store := db.NewStore(database)
+	if _, err := NewParser(store).ParseFile(ctx, logPath, false); err != nil {
+		t.Fatalf("parse file: %v", err)
+	}
+
+	var matches int
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM matches`).Scan(&matches); err != nil {
+		t.Fatalf("count matches: %v", err)
+	}
+	if matches != 2 {
+		t.Fatalf("matches = %d, want 2", matches)
+	}
+	var rankClass, opponentName string
+	var level int64
+	var percentile float64
+	if err := database.QueryRowContext(ctx, `
+		SELECT opponent_rank_class, opponent_rank_level, opponent_mythic_percentile, opponent_name
+		FROM matches WHERE arena_match_id = 'match-ranked'
+	`).Scan(&rankClass, &level, &percentile, &opponentName); err != nil {
+		t.Fatalf("load ranked match: %v", err)
+	}
+	if rankClass != "Mythic" || level != 1 || percentile != 87.5 || opponentName != "Opp" {
+		t.Fatalf("ranked match = %s %d %.1f%% vs %q, want Mythic 1 87.5%% vs Opp", rankClass, level, percentile, opponentName)
+	}
+	var unranked sql.NullString
+	if err := database.QueryRowContext(ctx, `SELECT opponent_rank_class FROM matches WHERE arena_match_id = 'match-play'`).Scan(&unranked); err != nil {
+		t.Fatalf("load play match: %v", err)
+	}
+	if unranked.Valid {
+		t.Fatalf("play match opponent rank = %q, want none", unranked.String)
+	}
+}
+
+func TestParserHandlesLinesLongerThanReadBuffer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	store := db.NewStore(database)
+	parser := NewParser(store)
+
+	lines := []string{
+		`{"filler":"` + strings.Repeat("x", 5*1024*1024) + `"}`,
+		`{"clientId":"self-user","screenName":"AfterLongLine"}`,
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+	info, err := os.Stat(logPath)
+	if err != nil {
+		t.Fatalf("stat log: %v", err)
+	}
+
+	stats, err := parser.ParseFile(ctx, logPath, true)
+	if err != nil {
+		t.Fatalf("parse file: %v", err)
+	}
+	if stats.LinesRead != 2 || stats.BytesRead != info.Size() {
+		t.Fatalf("stats = %d lines / %d bytes, want 2 / %d", stats.LinesRead, stats.BytesRead, info.Size())
+	}
+	playerName, err := store.PlayerName(ctx)
+	if err != nil {
+		t.Fatalf("PlayerName: %v", err)
+	}
+	if playerName != "AfterLongLine" {
+		t.Fatalf("PlayerName = %q, want AfterLongLine", playerName)
+	}
+}
+
+func TestProcessLineSkipsUninterestingLinesWithoutAllocating(t *testing.T) {
+	parser := NewParser(nil)
+	state := parser.stateForLog("", false)
+	state.personaID = "self-user"
+	stats := model.ParseStats{}
+
+	lines := [][]byte{
+		[]byte("[UnityCrossThreadLogger]Client.SceneChange: Home\r\n"),
+		[]byte("  Loading bundle ui_home.bundle\n"),
+		[]byte(`{"transactionId":"abc","timestamp":"638512345678901234"}` + "\n"),
+		[]byte("\n"),
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, line := range lines {
+			if err := parser.processLine(context.Background(), nil, &stats, state, "", 1, 0, line); err != nil {
+				t.Fatalf("processLine: %v", err)
+			}
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("processLine allocated %.0f times per run, want 0", allocs)
+	}
+}
+
+func TestParserSkipsLineWhoseHandlerFails(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	// Fail the deck write after the line's raw event is already stored, so
+	// the savepoint has something to roll back.
+	if _, err := database.ExecContext(ctx, `
+		CREATE TRIGGER fail_bad_deck BEFORE INSERT ON decks
+		WHEN NEW.arena_deck_id = 'deck-bad'
+		BEGIN SELECT RAISE(ABORT, 'deck rejected'); END
+	`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	parser := NewParser(db.NewStore(database))
+	lines := []string{
+		setDeckLogLine(t, "EventSetDeckV2",
+			`{"EventName":"Traditional_Ladder","Summary":{"DeckId":"deck-bad","Name":"Bad","Attributes":[]},"Deck":{"MainDeck":[{"cardId":11,"quantity":4}],"Sideboard":[],"CommandZone":[],"Companions":[]}}`),
+		setDeckLogLine(t, "EventSetDeckV3",
+			`{"EventName":"Traditional_Ladder","Summary":{"DeckId":"deck-good","Name":"Good","Attributes":[]},"Deck":{"MainDeck":[{"cardId":22,"quantity":4}],"Sideboard":[],"CommandZone":[],"Companions":[]}}`),
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+
+	stats, err := parser.ParseFile(ctx, logPath, true)
+	if err != nil {
+		t.Fatalf("parse file: %v", err)
+	}
+	if stats.LinesSkipped != 1 || stats.DecksUpserted != 1 {
+		t.Fatalf("stats = %d skipped / %d decks, want 1 / 1", stats.LinesSkipped, stats.DecksUpserted)
+	}
+
+	var decks, rawEvents int
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM decks`).Scan(&decks); err != nil {
+		t.Fatalf("count decks: %v", err)
+	}
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM events_raw`).Scan(&rawEvents); err != nil {
+		t.Fatalf("count raw events: %v", err)
+	}
+	if decks != 1 || rawEvents != 1 {
+		t.Fatalf("decks = %d, raw events = %d; want the failed line's writes rolled back", decks, rawEvents)
+	}
+
+	var lineNo int64
+	var message string
+	if err := database.QueryRowContext(ctx, `SELECT line_no, error FROM parse_errors`).Scan(&lineNo, &message); err != nil {
+		t.Fatalf("load parse error: %v", err)
+	}
+	if lineNo != 1 || !strings.Contains(message, "deck rejected") {
+		t.Fatalf("parse error = line %d %q, want line 1 deck rejected", lineNo, message)
+	}
+}
+
+func TestParserCountsEventKinds(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	lines := []string{
+		setDeckLogLine(t, "EventJoin", `{"EventName":"Ladder"}`),
+		setDeckLogLine(t, "EventJoin", `{"EventName":"Ladder"}`),
+		setDeckLogLine(t, "GetPlayerCardsV3", `{}`),
+		`<== StartHook(req-2)`,
+		`plain chatter`,
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+
+	quiet := NewParser(db.NewStore(database))
+	stats, err := quiet.ParseFile(ctx, logPath, false)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if stats.EventKinds != nil {
+		t.Fatalf("EventKinds = %v without CountEventKinds, want nil", stats.EventKinds)
+	}
+
+	parser := NewParser(db.NewStore(database))
+	parser.CountEventKinds()
+	stats, err = parser.ParseFile(ctx, logPath, false)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	wantKinds := map[string]int64{"request EventJoin": 2, "request GetPlayerCardsV3": 1, "complete StartHook": 1}
+	wantUnrecognized := map[string]int64{"request GetPlayerCardsV3": 1, "complete StartHook": 1}
+	if len(stats.EventKinds) != len(wantKinds) || len(stats.UnrecognizedKinds) != len(wantUnrecognized) {
+		t.Fatalf("kinds = %v unrecognized = %v, want %v and %v", stats.EventKinds, stats.UnrecognizedKinds, wantKinds, wantUnrecognized)
+	}
+	for kind, n := range wantKinds {
+		if stats.EventKinds[kind] != n {
+			t.Fatalf("EventKinds[%s] = %d, want %d (all: %v)", kind, stats.EventKinds[kind], n, stats.EventKinds)
+		}
+	}
+	for kind, n := range wantUnrecognized {
+		if stats.UnrecognizedKinds[kind] != n {
+			t.Fatalf("UnrecognizedKinds[%s] = %d, want %d (all: %v)", kind, stats.UnrecognizedKinds[kind], n, stats.UnrecognizedKinds)
+		}
+	}
+}
+
+func TestParserRunsRegisteredOutgoingHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	lines := []string{
+		setDeckLogLine(t, "GetPlayerCardsV3", `{"cards":3}`),
+		setDeckLogLine(t, "EventJoin", `{"EventName":"Ladder"}`),
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+
+	parser := NewParser(db.NewStore(database))
+	parser.CountEventKinds()
+	var got []OutgoingRequest
+	parser.RegisterOutgoing("GetPlayerCardsV3", func(ctx context.Context, req OutgoingRequest) error {
+		got = append(got, req)
+		_, err := req.Tx.ExecContext(ctx, `INSERT INTO app_metadata(key, value, updated_at) VALUES ('plugin_seen', ?, ?)`, string(req.Payload), req.ObservedAt)
+		return err
+	})
+	parser.RegisterOutgoing("EventJoin", nil)
+
+	stats, err := parser.ParseFile(ctx, logPath, false)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if len(got) != 1 || got[0].Method != "GetPlayerCardsV3" || got[0].Stats == nil {
+		t.Fatalf("handler calls = %+v, want one GetPlayerCardsV3", got)
+	}
+	if len(stats.UnrecognizedKinds) != 1 || stats.UnrecognizedKinds["request EventJoin"] != 1 {
+		t.Fatalf("UnrecognizedKinds = %v, want only the disabled EventJoin", stats.UnrecognizedKinds)
+	}
+
+	var seen string
+	if err := database.QueryRowContext(ctx, `SELECT value FROM app_metadata WHERE key = 'plugin_seen'`).Scan(&seen); err != nil {
+		t.Fatalf("load handler write: %v", err)
+	}
+	if seen != `{"cards":3}` {
+		t.Fatalf("handler wrote %q, want the request payload", seen)
+	}
+	var runs int
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM event_runs`).Scan(&runs); err != nil {
+		t.Fatalf("count event runs: %v", err)
+	}
+	if runs != 0 {
+		t.Fatalf("event runs = %d, want the built-in EventJoin handler disabled", runs)
+	}
+}
+
+func TestLineSplittersAndTimestamp(t *testing.T) {
+	outgoing := []struct {
+		line, method, envelope string
+		ok                     bool
+	}{
+		{`[UnityCrossThreadLogger]==> EventJoin {"id":"1"}`, "EventJoin", `{"id":"1"}`, true},
+		{"[UnityCrossThreadLogger]==>\tLog_Business_V2 \t{}", "Log_Business_V2", "{}", true},
+		{`[UnityCrossThreadLogger]==> EventJoin`, "", "", false},
+		{`[UnityCrossThreadLogger]==>EventJoin {}`, "", "", false},
+		{`[UnityCrossThreadLogger]==> Event-Join {}`, "", "", false},
+		{`[UnityCrossThreadLogger]Client.SceneChange`, "", "", false},
+	}
+	for _, tc := range outgoing {
+		method, envelope, ok := splitOutgoing([]byte(tc.line))
+		if ok != tc.ok || string(method) != tc.method || string(envelope) != tc.envelope {
+			t.Errorf("splitOutgoing(%q) = %q, %q, %v; want %q, %q, %v", tc.line, method, envelope, ok, tc.method, tc.envelope, tc.ok)
+		}
+	}
+
+	complete := []struct {
+		line, method, id string
+		ok               bool
+	}{
+		{`<== RankGetCombinedRankInfo(abc-123)`, "RankGetCombinedRankInfo", "abc-123", true},
+		{`<== StartHook() trailing`, "StartHook", "", true},
+		{`<== StartHook (abc)`, "", "", false},
+		{`<== StartHook(abc`, "", "", false},
+		{`<==StartHook(abc)`, "", "", false},
+	}
+	for _, tc := range complete {
+		method, id, ok := splitComplete([]byte(tc.line))
+		if ok != tc.ok || string(method) != tc.method || string(id) != tc.id {
+			t.Errorf("splitComplete(%q) = %q, %q, %v; want %q, %q, %v", tc.line, method, id, ok, tc.method, tc.id, tc.ok)
+		}
+	}
+
+	stamps := map[string]string{
+		`[UnityCrossThreadLogger]3/1/2026 5:04:03 PM: Match to X`:     "2026-03-01T17:04:03Z",
+		`[UnityCrossThreadLogger]12/31/2025 12:59:59 AM`:              "2025-12-31T00:59:59Z",
+		`[UnityCrossThreadLogger]3/1/2026 17:04:03 PM`:                "",
+		`[UnityCrossThreadLogger]3/1/2026 5:04 PM`:                    "",
+		`[UnityCrossThreadLogger]Client.SceneChange: Home at 5:04 PM`: "",
+	}
+	for line, want := range stamps {
+		if got := unityLogTimestamp([]byte(line), time.UTC); got != want {
+			t.Errorf("unityLogTimestamp(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestParserStoresRawEventsByLevel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+	lines := []string{
+		setDeckLogLine(t, "EventSetDeckV2",
+			`{"EventName":"Traditional_Ladder","Summary":{"DeckId":"deck-1","Name":"Deck","Attributes":[]},"Deck":{"MainDeck":[{"cardId":11,"quantity":4}],"Sideboard":[],"CommandZone":[],"Companions":[]}}`),
+		`{"timestamp":"1772330782309","greToClientEvent":{"greToClientMessages":[{"type":"GREMessageType_GameStateMessage","systemSeatIds":[2],"gameStateMessage":{"type":"GameStateType_Full","gameStateId":1}}]}}`,
+		`<== StartHook(req-2)`,
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+
+	for _, tc := range []struct {
+		level db.RawEventLevel
+		want  map[string]int
+	}{
+		{db.RawEventsNone, map[string]int{}},
+		{db.RawEventsMinimal, map[string]int{"outgoing": 1}},
+		{db.RawEventsFull, map[string]int{"outgoing": 1, "game_state": 1, "method_complete": 1}},
+	} {
+		database, err := db.Open(filepath.Join(tmpDir, tc.level.String()+".db"))
+		if err != nil {
+			t.Fatalf("open db: %v", err)
+		}
+		defer database.Close()
+		if err := db.Init(ctx, database); err != nil {
+			t.Fatalf("init db: %v", err)
+		}
+		store := db.NewStore(database)
+		store.SetRawEventLevel(tc.level)
+		stats, err := NewParser(store).ParseFile(ctx, logPath, false)
+		if err != nil {
+			t.Fatalf("%s: ParseFile: %v", tc.level, err)
+		}
+		if stats.DecksUpserted != 1 {
+			t.Fatalf("%s: decks = %d, want 1 at every level", tc.level, stats.DecksUpserted)
+		}
+
+		rows, err := database.QueryContext(ctx, `SELECT kind, COUNT(*) FROM events_raw GROUP BY kind`)
+		if err != nil {
+			t.Fatalf("%s: count raw events: %v", tc.level, err)
+		}
+		got := map[string]int{}
+		for rows.Next() {
+			var kind string
+			var n int
+			if err := rows.Scan(&kind, &n); err != nil {
+				t.Fatalf("%s: scan: %v", tc.level, err)
+			}
+			got[kind] = n
+		}
+		rows.Close()
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: raw events by kind = %v, want %v", tc.level, got, tc.want)
+		}
+		for kind, n := range tc.want {
+			if got[kind] != n {
+				t.Fatalf("%s: raw events by kind = %v, want %v", tc.level, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestMaintenanceKeepsRawEventsParsedAtFullLevel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+	lines := []string{
+		setDeckLogLine(t, "EventJoin", `{"EventName":"Ladder"}`),
+		`{"timestamp":"1772330782309","greToClientEvent":{"greToClientMessages":[{"type":"GREMessageType_GameStateMessage","systemSeatIds":[2],"gameStateMessage":{"type":"GameStateType_Full","gameStateId":1}}]}}`,
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	countRaw := func() int {
+		var n int
+		if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM events_raw`).Scan(&n); err != nil {
+			t.Fatalf("count raw events: %v", err)
+		}
+		return n
+	}
+
+	// parse -raw-level full, then the maintenance pass serve or the desktop
+	// app runs with a store at the default level.
+	store := db.NewStore(database)
+	store.SetRawEventLevel(db.RawEventsFull)
+	if _, err := NewParser(store).ParseFile(ctx, logPath, false); err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	stored := countRaw()
+	if stored != len(lines) {
+		t.Fatalf("raw events after full parse = %d, want %d", stored, len(lines))
+	}
+	result, err := db.NewStore(database).RunMaintenance(ctx)
+	if err != nil {
+		t.Fatalf("RunMaintenance: %v", err)
+	}
+	if result.RawEventsPruned != 0 || countRaw() != stored {
+		t.Fatalf("maintenance pruned %d raw events, want the full rows kept", result.RawEventsPruned)
+	}
+
+	// An explicit prune still drops them.
+	if _, err := db.NewStore(database).Prune(ctx, db.RawEventPolicy{}); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if n := countRaw(); n != 0 {
+		t.Fatalf("raw events after prune = %d, want 0", n)
+	}
+}
+
+func TestParserCommitsReadLinesWhenCanceled(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(context.Background(), database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	lines := []string{
+		setDeckLogLine(t, "EventJoin", `{"EventName":"Ladder_A"}`),
+		setDeckLogLine(t, "GetPlayerCardsV3", `{"cards":3}`),
+		setDeckLogLine(t, "EventJoin", `{"EventName":"Ladder_B"}`),
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	parser := NewParser(db.NewStore(database))
+	calls := 0
+	parser.RegisterOutgoing("GetPlayerCardsV3", func(_ context.Context, _ OutgoingRequest) error {
+		calls++
+		cancel()
+		return nil
+	})
+
+	stats, err := parser.ParseFile(ctx, logPath, true)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ParseFile error = %v, want context.Canceled", err)
+	}
+	if stats.LinesRead != 2 {
+		t.Fatalf("lines read = %d, want 2", stats.LinesRead)
+	}
+	state, err := parser.store.GetIngestState(context.Background(), logPath)
+	if err != nil {
+		t.Fatalf("load ingest state: %v", err)
+	}
+	if state.LineNo != 2 || state.Offset != stats.BytesRead {
+		t.Fatalf("saved line %d offset %d, want line 2 offset %d", state.LineNo, state.Offset, stats.BytesRead)
+	}
+
+	countRuns := func() int {
+		var runs int
+		if err := database.QueryRow(`SELECT COUNT(*) FROM event_runs`).Scan(&runs); err != nil {
+			t.Fatalf("count event runs: %v", err)
+		}
+		return runs
+	}
+	if runs := countRuns(); runs != 1 {
+		t.Fatalf("event runs after cancel = %d, want the first line committed", runs)
+	}
+
+	if _, err := parser.ParseFile(context.Background(), logPath, true); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if runs := countRuns(); runs != 2 || calls != 1 {
+		t.Fatalf("after resume: event runs = %d, handler calls = %d; want 2 and 1", runs, calls)
+	}
+}
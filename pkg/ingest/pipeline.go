@@ -0,0 +1,202 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Decoding greToClientEvent JSON is most of the CPU a cold parse spends, and
+// none of it depends on parse state. With decode workers, ParseFile splits
+// into a reader that copies lines into chunks, workers that decode a chunk's
+// GRE messages, and the usual single writer, which takes chunks in file
+// order once they are decoded. Lines are still applied one at a time in
+// order, so per-match state and savepoints behave as without workers.
+const (
+	chunkMaxLines = 256
+	chunkMaxBytes = 4 << 20
+	// pipelineMinBytes keeps tailing's small reads on the plain reader,
+	// where starting goroutines would cost more than decoding saves.
+	pipelineMinBytes = 1 << 20
+)
+
+// logLine is one line of a log, with its GRE message decoded when a worker
+// got to it first.
+type logLine struct {
+	text []byte
+	gre  *greEnvelope
+}
+
+// lineSource yields a log's lines in order, then io.EOF.
+type lineSource interface {
+	next() (logLine, error)
+}
+
+// lineReader reads lines straight out of the bufio.Reader's buffer; each
+// line is only valid until the next call. Lines longer than the buffer are
+// gathered into overflow.
+type lineReader struct {
+	reader   *bufio.Reader
+	overflow []byte
+	eof      bool
+}
+
+func (r *lineReader) next() (logLine, error) {
+	if r.eof {
+		return logLine{}, io.EOF
+	}
+	line, err := r.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		r.overflow = append(r.overflow[:0], line...)
+		for errors.Is(err, bufio.ErrBufferFull) {
+			line, err = r.reader.ReadSlice('\n')
+			r.overflow = append(r.overflow, line...)
+		}
+		line = r.overflow
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return logLine{}, fmt.Errorf("read line: %w", err)
+	}
+	if errors.Is(err, io.EOF) {
+		r.eof = true
+		if len(line) == 0 {
+			return logLine{}, io.EOF
+		}
+	}
+	return logLine{text: line}, nil
+}
+
+// lineChunk is a run of lines copied out of the reader. err is what reading
+// stopped on after them (io.EOF for the last chunk); ready is closed once a
+// worker has decoded the chunk.
+type lineChunk struct {
+	lines []logLine
+	err   error
+	ready chan struct{}
+}
+
+// decodePipeline is the lineSource ParseFile uses with decode workers.
+type decodePipeline struct {
+	ctx    context.Context
+	chunks <-chan *lineChunk
+	cur    *lineChunk
+	pos    int
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// startDecodePipeline starts reading reader with workers decoding behind it.
+// At most about 2×workers chunks are in flight, which bounds memory however
+// far the writer falls behind. close must be called when done.
+func startDecodePipeline(ctx context.Context, reader *bufio.Reader, workers int) *decodePipeline {
+	pipeCtx, cancel := context.WithCancel(ctx)
+	ordered := make(chan *lineChunk, workers*2)
+	work := make(chan *lineChunk, workers)
+	pl := &decodePipeline{ctx: ctx, chunks: ordered, cancel: cancel}
+
+	pl.wg.Add(1)
+	go func() {
+		defer pl.wg.Done()
+		defer close(ordered)
+		defer close(work)
+		src := &lineReader{reader: reader}
+		for {
+			chunk := readChunk(src)
+			// Workers get the chunk before the writer can wait on it.
+			select {
+			case work <- chunk:
+			case <-pipeCtx.Done():
+				return
+			}
+			select {
+			case ordered <- chunk:
+			case <-pipeCtx.Done():
+				return
+			}
+			if chunk.err != nil {
+				return
+			}
+		}
+	}()
+
+	for range workers {
+		pl.wg.Add(1)
+		go func() {
+			defer pl.wg.Done()
+			for chunk := range work {
+				if pipeCtx.Err() == nil {
+					for i := range chunk.lines {
+						chunk.lines[i].gre = predecodeGRE(chunk.lines[i].text)
+					}
+				}
+				close(chunk.ready)
+			}
+		}()
+	}
+	return pl
+}
+
+// readChunk copies up to chunkMaxLines lines (or about chunkMaxBytes) from
+// src into one buffer.
+func readChunk(src *lineReader) *lineChunk {
+	chunk := &lineChunk{ready: make(chan struct{})}
+	var buf []byte
+	size := 0
+	for len(chunk.lines) < chunkMaxLines && size < chunkMaxBytes {
+		line, err := src.next()
+		if err != nil {
+			chunk.err = err
+			break
+		}
+		// Slices of buf stay valid when append moves it; the old array is
+		// never written again.
+		start := len(buf)
+		buf = append(buf, line.text...)
+		chunk.lines = append(chunk.lines, logLine{text: buf[start:len(buf):len(buf)]})
+		size += len(line.text)
+	}
+	return chunk
+}
+
+// predecodeGRE decodes line if processLine is likely to hand it to
+// handleGREJSON. A wrong guess only wastes the decode, since the writer
+// decides for itself which handler runs.
+func predecodeGRE(line []byte) *greEnvelope {
+	line = bytes.TrimSpace(line)
+	if !isGREHead(line) {
+		return nil
+	}
+	return decodeGRE(line)
+}
+
+func (pl *decodePipeline) next() (logLine, error) {
+	for pl.cur == nil || pl.pos >= len(pl.cur.lines) {
+		if pl.cur != nil && pl.cur.err != nil {
+			return logLine{}, pl.cur.err
+		}
+		chunk, ok := <-pl.chunks
+		if !ok {
+			// The reader only stops early when ctx is done.
+			if err := pl.ctx.Err(); err != nil {
+				return logLine{}, err
+			}
+			return logLine{}, io.EOF
+		}
+		<-chunk.ready
+		pl.cur, pl.pos = chunk, 0
+	}
+	line := pl.cur.lines[pl.pos]
+	pl.pos++
+	return line, nil
+}
+
+// close stops the reader and workers and waits for them, so the file can be
+// closed safely.
+func (pl *decodePipeline) close() {
+	pl.cancel()
+	pl.wg.Wait()
+}
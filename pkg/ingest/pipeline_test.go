@@ -0,0 +1,89 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
+)
+
+func TestDecodeWorkersMatchSequentialParse(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+
+	// Enough chatter between the game-state lines to cross pipelineMinBytes
+	// and spread them over several chunks.
+	filler := strings.Repeat("x", 200)
+	lines := []string{
+		`{"clientId":"self-user","screenName":"Self"}`,
+		`{"timestamp":"1772330782273","matchGameRoomStateChangedEvent":{"gameRoomInfo":{"gameRoomConfig":{"reservedPlayers":[{"userId":"opp-user","playerName":"Opp","systemSeatId":1,"teamId":1,"eventId":"Traditional_Ladder"},{"userId":"self-user","playerName":"Self","systemSeatId":2,"teamId":2,"eventId":"Traditional_Ladder"}],"matchId":"match-pipeline"},"stateType":"MatchGameRoomStateType_Playing"}}}`,
+		`{"timestamp":"1772330782309","greToClientEvent":{"greToClientMessages":[{"type":"GREMessageType_GameStateMessage","systemSeatIds":[2],"gameStateMessage":{"type":"GameStateType_Full","gameStateId":1,"gameInfo":{"matchID":"match-pipeline","gameNumber":1},"turnInfo":{"phase":"Phase_Main1","turnNumber":1},"zones":[{"zoneId":27,"type":"ZoneType_Stack","visibility":"Visibility_Public","objectInstanceIds":[]},{"zoneId":28,"type":"ZoneType_Battlefield","visibility":"Visibility_Public","objectInstanceIds":[]}],"gameObjects":[]}}]}}`,
+	}
+	for i := range 3 {
+		for j := range 2500 {
+			lines = append(lines, fmt.Sprintf("[UnityCrossThreadLogger]chatter %d.%d %s", i, j, filler))
+		}
+		stack := []string{"501"}
+		for k := range i {
+			stack = append(stack, fmt.Sprint(502+k))
+		}
+		lines = append(lines, fmt.Sprintf(`{"timestamp":"177233078231%d","greToClientEvent":{"greToClientMessages":[{"type":"GREMessageType_GameStateMessage","systemSeatIds":[2],"gameStateMessage":{"type":"GameStateType_Diff","gameStateId":%d,"prevGameStateId":%d,"turnInfo":{"phase":"Phase_Main1","turnNumber":1},"zones":[{"zoneId":27,"type":"ZoneType_Stack","visibility":"Visibility_Public","objectInstanceIds":[%s]}],"gameObjects":[{"instanceId":%d,"grpId":%d,"type":"GameObjectType_Card","zoneId":27,"visibility":"Visibility_Public","ownerSeatId":1}]}}]}}`,
+			i, i+2, i+1, strings.Join(stack, ","), 501+i, 9501+i))
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+
+	parse := func(name string, workers int) (model.ParseStats, []model.MatchReplayFrameRow) {
+		database, err := db.Open(filepath.Join(tmpDir, name+".db"))
+		if err != nil {
+			t.Fatalf("open db: %v", err)
+		}
+		t.Cleanup(func() { database.Close() })
+		if err := db.Init(ctx, database); err != nil {
+			t.Fatalf("init db: %v", err)
+		}
+		store := db.NewStore(database)
+		parser := NewParser(store)
+		parser.SetDecodeWorkers(workers)
+		stats, err := parser.ParseFile(ctx, logPath, true)
+		if err != nil {
+			t.Fatalf("parse with %d workers: %v", workers, err)
+		}
+		frames, err := store.ListMatchReplayFrames(ctx, 1)
+		if err != nil {
+			t.Fatalf("list replay frames: %v", err)
+		}
+		state, err := store.GetIngestState(ctx, logPath)
+		if err != nil {
+			t.Fatalf("load ingest state: %v", err)
+		}
+		if state.Offset != stats.BytesRead || state.LineNo != int64(len(lines)) {
+			t.Fatalf("%d workers saved offset %d line %d, want %d / %d", workers, state.Offset, state.LineNo, stats.BytesRead, len(lines))
+		}
+		return stats, frames
+	}
+
+	seqStats, seqFrames := parse("sequential", 1)
+	if seqStats.BytesRead < pipelineMinBytes {
+		t.Fatalf("log is %d bytes, want at least %d to exercise the pipeline", seqStats.BytesRead, pipelineMinBytes)
+	}
+	pipeStats, pipeFrames := parse("pipeline", 4)
+	if pipeStats.LinesRead != seqStats.LinesRead || pipeStats.BytesRead != seqStats.BytesRead || pipeStats.MatchesUpserted != seqStats.MatchesUpserted {
+		t.Fatalf("pipeline stats = %+v, sequential = %+v", pipeStats, seqStats)
+	}
+	if len(seqFrames) != 4 || len(pipeFrames) != len(seqFrames) {
+		t.Fatalf("replay frames = %d with workers, %d without; want 4", len(pipeFrames), len(seqFrames))
+	}
+	stack := replayObjectsInZone(pipeFrames[len(pipeFrames)-1], "stack")
+	if len(stack) != 3 || stack[0].InstanceID != 501 || stack[1].InstanceID != 502 || stack[2].InstanceID != 503 {
+		t.Fatalf("final stack with workers = %#v, want 501, 502, 503", stack)
+	}
+}
@@ -6,8 +6,8 @@ import (
 	"encoding/json"
 	"strings"
 
-	"github.com/solean/ponder/internal/db"
-	"github.com/solean/ponder/internal/model"
+	"github.com/solean/ponder/pkg/db"
+	"github.com/solean/ponder/pkg/model"
 )
 
 func (p *Parser) enqueueCompletedMatch(arenaMatchID string) {
@@ -79,7 +79,7 @@ func (p *Parser) handleMethodResponse(ctx context.Context, tx *sql.Tx, stats *mo
 	observedAt := state.pendingResponseObservedAt
 	state.clearPendingResponse()
 
-	if stored, err := p.store.InsertRawEvent(ctx, tx, logPath, lineNo, byteOffset, "method_result", method, requestID, []byte(line), ""); err != nil {
+	if stored, err := p.store.InsertRawEvent(ctx, tx, logPath, lineNo, byteOffset, observedAt, "method_result", method, requestID, []byte(line), ""); err != nil {
 		return err
 	} else if stored {
 		stats.RawEventsStored++
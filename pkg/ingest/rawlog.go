@@ -0,0 +1,240 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+// maxMissingLines bounds how many missing or mistimed line numbers a
+// RawLogCheck lists.
+const maxMissingLines = 20
+
+// RawLogCheck compares one source log with events_raw.
+type RawLogCheck struct {
+	Path  string
+	Lines int64
+	// FirstAt and LastAt are the log's first and last Unity timestamps, the
+	// period it covers; empty when it has none.
+	FirstAt string
+	LastAt  string
+	// Events counts the requests in the log that events_raw keeps, and
+	// Stored how many of those it holds.
+	Events int64
+	Stored int64
+	// Missing lists the line numbers of up to maxMissingLines requests
+	// that are not stored.
+	Missing []int64
+	// StoredFirstAt and StoredLastAt are the earliest and latest timestamps
+	// events_raw recorded for the stored requests, the period it covers of
+	// this log; empty when none was recorded.
+	StoredFirstAt string
+	StoredLastAt  string
+	// Untimed counts stored requests with no recorded timestamp, stored
+	// before events_raw kept them; their period cannot be checked.
+	Untimed int64
+	// Mistimed lists the line numbers of up to maxMissingLines requests
+	// stored with a timestamp other than the one the log has for them.
+	Mistimed []int64
+}
+
+// Complete reports whether every request the log should have left in
+// events_raw is there, under the time the log gives it.
+func (c RawLogCheck) Complete() bool {
+	return c.Stored == c.Events && len(c.Mistimed) == 0
+}
+
+// CheckRawLog reads a log and checks that each request events_raw keeps
+// from it is stored, matched by content so it passes whichever log copy
+// (Player-prev.log, an archive) was parsed, and that it was stored under the
+// Unity timestamp the log has for it. Events dropped by prune show up as
+// missing.
+func CheckRawLog(ctx context.Context, store *db.Store, path string) (RawLogCheck, error) {
+	out := RawLogCheck{Path: path}
+	file, err := os.Open(path)
+	if err != nil {
+		return out, fmt.Errorf("open log: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 4*1024*1024)
+	var overflow []byte
+	var lastAt string
+	for {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		line, readErr := reader.ReadSlice('\n')
+		if errors.Is(readErr, bufio.ErrBufferFull) {
+			overflow = append(overflow[:0], line...)
+			for errors.Is(readErr, bufio.ErrBufferFull) {
+				line, readErr = reader.ReadSlice('\n')
+				overflow = append(overflow, line...)
+			}
+			line = overflow
+		}
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return out, fmt.Errorf("read line: %w", readErr)
+		}
+		if len(line) == 0 && errors.Is(readErr, io.EOF) {
+			break
+		}
+		out.Lines++
+		line = bytes.TrimSpace(line)
+
+		if ts := unityLogTimestamp(line, time.Local); ts != "" {
+			if out.FirstAt == "" {
+				out.FirstAt = ts
+			}
+			out.LastAt = ts
+			lastAt = ts
+		}
+		if bytes.HasPrefix(line, outgoingPrefix) {
+			if err := checkOutgoingLine(ctx, store, &out, line, lastAt); err != nil {
+				return out, fmt.Errorf("line %d: %w", out.Lines, err)
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+	}
+	return out, nil
+}
+
+func checkOutgoingLine(ctx context.Context, store *db.Store, out *RawLogCheck, line []byte, loggedAt string) error {
+	methodName, envelope, ok := splitOutgoing(line)
+	if !ok {
+		return nil
+	}
+	method := string(methodName)
+	var env outgoingEnvelope
+	if err := json.Unmarshal(envelope, &env); err != nil {
+		return nil
+	}
+	payload, err := decodeRawRequest(env.Request)
+	if err != nil {
+		return nil
+	}
+	if !db.KeepsRawEvent("outgoing", method, payload) {
+		return nil
+	}
+	out.Events++
+	storedAt, stored, err := store.FindRawEvent(ctx, "outgoing", method, env.ID, payload)
+	if err != nil {
+		return err
+	}
+	if !stored {
+		if len(out.Missing) < maxMissingLines {
+			out.Missing = append(out.Missing, out.Lines)
+		}
+		return nil
+	}
+	out.Stored++
+	switch {
+	case storedAt == "":
+		out.Untimed++
+		return nil
+	case storedAt != loggedAt && len(out.Mistimed) < maxMissingLines:
+		out.Mistimed = append(out.Mistimed, out.Lines)
+	}
+	if out.StoredFirstAt == "" || storedAt < out.StoredFirstAt {
+		out.StoredFirstAt = storedAt
+	}
+	if storedAt > out.StoredLastAt {
+		out.StoredLastAt = storedAt
+	}
+	return nil
+}
+
+// WriteRawLog writes the stored events back out as the Player.log lines
+// they were read from, oldest first, and returns how many event lines it
+// wrote. An event is preceded by a Unity timestamp line whenever its
+// recorded time differs from the last one written, so the synthetic log
+// covers the period the originals did. Requests are rebuilt from their
+// payloads; other events are written only when events_raw kept their line,
+// which it does at db.RawEventsFull. A database stored at the default level
+// holds just deck submissions and draft picks: enough to rebuild decks and
+// drafts, but not a log a service can replay matches from.
+func WriteRawLog(ctx context.Context, store *db.Store, w io.Writer) (int64, error) {
+	buffered := bufio.NewWriter(w)
+	var written, afterID int64
+	var lastAt string
+	for {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		events, err := store.ListRawEvents(ctx, afterID, reprocessBatchSize)
+		if err != nil {
+			return written, err
+		}
+		if len(events) == 0 {
+			break
+		}
+		for _, event := range events {
+			afterID = event.ID
+			line, err := rawLogLine(event)
+			if err != nil {
+				return written, fmt.Errorf("encode raw event %d: %w", event.ID, err)
+			}
+			if line == "" {
+				continue
+			}
+			if event.ObservedAt != "" && event.ObservedAt != lastAt {
+				lastAt = event.ObservedAt
+				if stamp := unityLogStamp(event.ObservedAt, time.Local); stamp != "" {
+					if _, err := fmt.Fprintf(buffered, "%s%s\n", unityLoggerPrefix, stamp); err != nil {
+						return written, err
+					}
+				}
+			}
+			if _, err := fmt.Fprintln(buffered, line); err != nil {
+				return written, err
+			}
+			written++
+		}
+	}
+	return written, buffered.Flush()
+}
+
+// rawLogLine rebuilds the log line a stored event was read from, or ""
+// when events_raw did not keep enough of it.
+func rawLogLine(event db.RawEvent) (string, error) {
+	switch event.Kind {
+	case "outgoing":
+		if event.Method == "" {
+			return "", nil
+		}
+		envelope, err := json.Marshal(map[string]string{"id": event.RequestID, "request": string(event.Payload)})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s %s", outgoingPrefix, event.Method, envelope), nil
+	case "outgoing_unparsed":
+		if event.RawText == "" {
+			return "", nil
+		}
+		return fmt.Sprintf("%s %s %s", outgoingPrefix, event.Method, event.RawText), nil
+	case "method_result":
+		// The response body is the whole line.
+		return string(event.Payload), nil
+	}
+	return event.RawText, nil
+}
+
+// unityLogStamp formats a stored RFC 3339 time the way Unity stamps logger
+// lines, in loc, so unityLogTimestamp reads it back; "" if it does not parse.
+func unityLogStamp(observedAt string, loc *time.Location) string {
+	parsed, err := time.Parse(time.RFC3339Nano, observedAt)
+	if err != nil {
+		return ""
+	}
+	return parsed.In(loc).Format("1/2/2006 3:04:05 PM")
+}
@@ -0,0 +1,153 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestCheckRawLogAndWriteRawLogRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	open := func(name string) *sql.DB {
+		t.Helper()
+		database, err := db.Open(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatalf("open db: %v", err)
+		}
+		t.Cleanup(func() { database.Close() })
+		if err := db.Init(ctx, database); err != nil {
+			t.Fatalf("init db: %v", err)
+		}
+		return database
+	}
+
+	logPath := filepath.Join(tmpDir, "Player.log")
+	lines := []string{
+		`[UnityCrossThreadLogger]3/12/2026 7:05:12 PM`,
+		setDeckLogLine(t, "EventSetDeckV2",
+			`{"EventName":"Ladder","Summary":{"DeckId":"deck-dimir","Name":"Dimir Mid","Attributes":[{"name":"Format","value":"Standard"}]},"Deck":{"MainDeck":[{"cardId":11,"quantity":4}],"Sideboard":[],"CommandZone":[],"Companions":[]}}`),
+		setDeckLogLine(t, "EventEnterPairing", `{"EventName":"Ladder"}`),
+		`[UnityCrossThreadLogger]3/12/2026 7:09:40 PM`,
+		setDeckLogLine(t, "EventSetDeckV3",
+			`{"EventName":"Ladder","Summary":{"DeckId":"deck-izzet","Name":"Izzet Prowess","Attributes":[{"name":"Format","value":"Standard"}]},"Deck":{"MainDeck":[{"cardId":22,"quantity":4}],"Sideboard":[],"CommandZone":[],"Companions":[]}}`),
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	source := open("source.db")
+	store := db.NewStore(source)
+	if _, err := NewParser(store).ParseFile(ctx, logPath, true); err != nil {
+		t.Fatalf("parse log: %v", err)
+	}
+
+	check, err := CheckRawLog(ctx, store, logPath)
+	if err != nil {
+		t.Fatalf("CheckRawLog: %v", err)
+	}
+	if check.Lines != 5 || check.Events != 2 || !check.Complete() || check.FirstAt == "" || check.FirstAt == check.LastAt {
+		t.Fatalf("check = %+v, want both deck requests stored across the log's period", check)
+	}
+
+	var synthetic bytes.Buffer
+	written, err := WriteRawLog(ctx, store, &synthetic)
+	if err != nil {
+		t.Fatalf("WriteRawLog: %v", err)
+	}
+	if written != 2 {
+		t.Fatalf("wrote %d lines, want 2", written)
+	}
+	if !bytes.HasPrefix(synthetic.Bytes(), []byte(lines[0]+"\n")) || bytes.Count(synthetic.Bytes(), []byte("[UnityCrossThreadLogger]3/12/2026")) != 2 {
+		t.Fatalf("synthetic log = %q, want each request under its original timestamp", synthetic.String())
+	}
+	syntheticPath := filepath.Join(tmpDir, "synthetic.log")
+	if err := os.WriteFile(syntheticPath, synthetic.Bytes(), 0o644); err != nil {
+		t.Fatalf("write synthetic log: %v", err)
+	}
+
+	// The synthetic log parses into the same decks and passes the check
+	// against the database it came from.
+	replayed := open("replayed.db")
+	if _, err := NewParser(db.NewStore(replayed)).ParseFile(ctx, syntheticPath, true); err != nil {
+		t.Fatalf("parse synthetic log: %v", err)
+	}
+	var decks int
+	if err := replayed.QueryRowContext(ctx, `SELECT COUNT(*) FROM decks WHERE arena_deck_id IN ('deck-dimir', 'deck-izzet')`).Scan(&decks); err != nil || decks != 2 {
+		t.Fatalf("decks after replay = %d, %v; want both", decks, err)
+	}
+	if check, err := CheckRawLog(ctx, store, syntheticPath); err != nil || !check.Complete() || check.Events != 2 {
+		t.Fatalf("synthetic check = %+v, %v", check, err)
+	}
+
+	if _, err := source.ExecContext(ctx, `UPDATE events_raw SET observed_at = '2026-03-13T00:00:00Z' WHERE method_name = 'EventSetDeckV2'`); err != nil {
+		t.Fatalf("shift raw event: %v", err)
+	}
+	check, err = CheckRawLog(ctx, store, logPath)
+	if err != nil {
+		t.Fatalf("CheckRawLog after shift: %v", err)
+	}
+	if check.Complete() || check.Stored != 2 || !slices.Equal(check.Mistimed, []int64{2}) || check.StoredLastAt != "2026-03-13T00:00:00Z" {
+		t.Fatalf("check after shift = %+v, want line 2 mistimed", check)
+	}
+
+	if _, err := source.ExecContext(ctx, `DELETE FROM events_raw WHERE method_name = 'EventSetDeckV3'`); err != nil {
+		t.Fatalf("drop raw event: %v", err)
+	}
+	check, err = CheckRawLog(ctx, store, logPath)
+	if err != nil {
+		t.Fatalf("CheckRawLog after prune: %v", err)
+	}
+	if check.Complete() || check.Stored != 1 || !slices.Equal(check.Missing, []int64{5}) {
+		t.Fatalf("check after prune = %+v, want line 5 missing", check)
+	}
+}
+
+func TestWriteRawLogWritesFullLevelLines(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	store := db.NewStore(database)
+	store.SetRawEventLevel(db.RawEventsFull)
+
+	logPath := filepath.Join(tmpDir, "Player.log")
+	lines := []string{
+		`[UnityCrossThreadLogger]3/12/2026 7:05:12 PM`,
+		setDeckLogLine(t, "EventJoin", `{"EventName":"Ladder"}`),
+		`[UnityCrossThreadLogger]3/12/2026 7:06:00 PM`,
+		`{"transactionId":"t-1","greToClientEvent":{"greToClientMessages":[]}}`,
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	if _, err := NewParser(store).ParseFile(ctx, logPath, true); err != nil {
+		t.Fatalf("parse log: %v", err)
+	}
+
+	var synthetic bytes.Buffer
+	written, err := WriteRawLog(ctx, store, &synthetic)
+	if err != nil {
+		t.Fatalf("WriteRawLog: %v", err)
+	}
+	want := strings.Join(lines, "\n") + "\n"
+	if written != 2 || synthetic.String() != want {
+		t.Fatalf("wrote %d lines:\n%s\nwant:\n%s", written, synthetic.String(), want)
+	}
+}
@@ -0,0 +1,73 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// reprocessBatchSize is how many stored raw events one Reprocess transaction
+// applies.
+const reprocessBatchSize = 500
+
+// ReprocessStats reports one Reprocess run.
+type ReprocessStats struct {
+	RawEvents       int64
+	DecksUpserted   int64
+	DraftPicksAdded int64
+}
+
+// Reprocess replays the outgoing requests stored in events_raw through the
+// current handlers, oldest first, then reruns draft repair, so parser fixes
+// reach decks, drafts, and event runs whose log files are gone. The default
+// raw level stores only the draft and deck requests; event joins, pairings,
+// and prize claims are there only when the logs were parsed at
+// RawEventsFull. Matches and games are never rebuilt: their GRE messages
+// are not replayed, even when full rows hold them. Replayed events carry no
+// log timestamp, so times already recorded are kept and missing ones are
+// filled by draft repair.
+func (p *Parser) Reprocess(ctx context.Context) (ReprocessStats, error) {
+	var out ReprocessStats
+	state := p.stateForLog("", true)
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		events, err := p.store.ListRawEvents(ctx, afterID, reprocessBatchSize)
+		if err != nil {
+			return out, err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		tx, err := p.store.BeginTx(ctx)
+		if err != nil {
+			return out, err
+		}
+		var stats model.ParseStats
+		for _, event := range events {
+			afterID = event.ID
+			if event.Kind != "outgoing" || len(event.Payload) == 0 {
+				continue
+			}
+			if err := p.applyOutgoing(ctx, tx, &stats, state, event.Method, event.Payload, ""); err != nil {
+				_ = tx.Rollback()
+				return out, fmt.Errorf("reprocess raw event %d (%s): %w", event.ID, event.Method, err)
+			}
+			out.RawEvents++
+		}
+		if err := tx.Commit(); err != nil {
+			return out, fmt.Errorf("commit reprocessed raw events: %w", err)
+		}
+		out.DecksUpserted += stats.DecksUpserted
+		out.DraftPicksAdded += stats.DraftPicksAdded
+	}
+
+	if err := p.store.RepairDraftDataFromRawEvents(ctx); err != nil {
+		return out, err
+	}
+	return out, nil
+}
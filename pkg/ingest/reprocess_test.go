@@ -0,0 +1,137 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func TestReprocessRebuildsDecksAndDraftsFromRawEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	store := db.NewStore(database)
+
+	lines := []string{
+		setDeckLogLine(t, "EventSetDeckV2",
+			`{"EventName":"Traditional_Ladder","Summary":{"DeckId":"deck-dimir","Name":"Dimir Mid","Attributes":[]},"Deck":{"MainDeck":[{"cardId":11,"quantity":4}],"Sideboard":[],"CommandZone":[],"Companions":[]}}`),
+		setDeckLogLine(t, "EventPlayerDraftMakePick", `{"DraftId":"draft-1","GrpIds":[90001],"Pack":1,"Pick":1}`),
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+	if _, err := NewParser(store).ParseFile(ctx, logPath, true); err != nil {
+		t.Fatalf("parse file: %v", err)
+	}
+
+	// Lose the log and everything derived from it; only events_raw remains.
+	if err := os.Remove(logPath); err != nil {
+		t.Fatalf("remove log: %v", err)
+	}
+	for _, stmt := range []string{
+		`DELETE FROM deck_cards`,
+		`DELETE FROM deck_versions`,
+		`DELETE FROM decks`,
+		`DELETE FROM draft_picks`,
+		`DELETE FROM draft_sessions`,
+	} {
+		if _, err := database.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	stats, err := NewParser(store).Reprocess(ctx)
+	if err != nil {
+		t.Fatalf("Reprocess: %v", err)
+	}
+	if stats.RawEvents != 2 || stats.DecksUpserted != 1 || stats.DraftPicksAdded != 1 {
+		t.Fatalf("stats = %+v, want 2 events, 1 deck, 1 pick", stats)
+	}
+
+	var deckName string
+	var cardRows int64
+	if err := database.QueryRowContext(ctx, `
+		SELECT d.name, (SELECT COUNT(*) FROM deck_cards dc WHERE dc.deck_id = d.id)
+		FROM decks d
+		WHERE d.arena_deck_id = 'deck-dimir'
+	`).Scan(&deckName, &cardRows); err != nil {
+		t.Fatalf("query rebuilt deck: %v", err)
+	}
+	if deckName != "Dimir Mid" || cardRows != 1 {
+		t.Fatalf("deck = %q with %d card rows, want Dimir Mid with 1", deckName, cardRows)
+	}
+	var picks int64
+	if err := database.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM draft_picks dp
+		JOIN draft_sessions ds ON ds.id = dp.draft_session_id
+		WHERE ds.draft_id = 'draft-1'
+	`).Scan(&picks); err != nil {
+		t.Fatalf("count rebuilt picks: %v", err)
+	}
+	if picks != 1 {
+		t.Fatalf("rebuilt picks = %d, want 1", picks)
+	}
+}
+
+func TestReprocessRebuildsEventRunsFromFullRawEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "Player.log")
+
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	store := db.NewStore(database)
+	store.SetRawEventLevel(db.RawEventsFull)
+
+	lines := []string{
+		setDeckLogLine(t, "EventJoin", `{"EventName":"QuickDraft_TMT_20260301","EntryCurrencyType":"Gem","EntryCurrencyPaid":750}`),
+		setDeckLogLine(t, "EventClaimPrize", `{"EventName":"QuickDraft_TMT_20260301"}`),
+	}
+	if err := writeLogLines(logPath, lines, false); err != nil {
+		t.Fatalf("write log lines: %v", err)
+	}
+	if _, err := NewParser(store).ParseFile(ctx, logPath, true); err != nil {
+		t.Fatalf("parse file: %v", err)
+	}
+	if _, err := database.ExecContext(ctx, `DELETE FROM event_runs`); err != nil {
+		t.Fatalf("delete event runs: %v", err)
+	}
+
+	if _, err := NewParser(store).Reprocess(ctx); err != nil {
+		t.Fatalf("Reprocess: %v", err)
+	}
+	var status, currency string
+	var paid int64
+	if err := database.QueryRowContext(ctx, `
+		SELECT status, entry_currency_type, entry_currency_paid
+		FROM event_runs WHERE event_name = 'QuickDraft_TMT_20260301'
+	`).Scan(&status, &currency, &paid); err != nil {
+		t.Fatalf("query rebuilt event run: %v", err)
+	}
+	if status != "claimed" || currency != "Gem" || paid != 750 {
+		t.Fatalf("event run = %s %s %d, want claimed Gem 750", status, currency, paid)
+	}
+}
@@ -0,0 +1,121 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/solean/ponder/pkg/model"
+)
+
+// TailFile parses what has been appended to logPath since the last call,
+// following Arena's rotation: when it restarts, Player.log is renamed to
+// Player-prev.log and a fresh Player.log begins. The lines the old session
+// wrote after the last pass are then only in Player-prev.log, so they are
+// read from there, carrying on from the saved offset, before the new
+// Player.log is parsed from the start. The returned stats hold one entry
+// per file read, the rotated log first.
+func (p *Parser) TailFile(ctx context.Context, logPath string) ([]model.ParseStats, error) {
+	info, err := os.Stat(logPath)
+	if err != nil {
+		return nil, fmt.Errorf("stat log file: %w", err)
+	}
+
+	var out []model.ParseStats
+	resume := true
+	rotated, err := p.rotatedSinceLastTail(ctx, logPath, info)
+	if err != nil {
+		return nil, err
+	}
+	if rotated {
+		resume = false
+		stats, caughtUp, err := p.catchUpRotated(ctx, logPath)
+		if caughtUp {
+			out = append(out, stats)
+		}
+		if err != nil {
+			return out, err
+		}
+	}
+
+	stats, err := p.ParseFile(ctx, logPath, resume)
+	out = append(out, stats)
+	if err != nil {
+		return out, err
+	}
+	p.stateMu.Lock()
+	p.tailed[logPath] = info
+	p.stateMu.Unlock()
+	return out, nil
+}
+
+// rotatedSinceLastTail reports whether logPath is no longer the file the
+// saved offset was read from: a different file than the last TailFile saw,
+// or one shorter than the offset (a tail started after the restart, or a
+// platform that truncates rather than renames).
+func (p *Parser) rotatedSinceLastTail(ctx context.Context, logPath string, info os.FileInfo) (bool, error) {
+	p.stateMu.Lock()
+	last, seen := p.tailed[logPath]
+	p.stateMu.Unlock()
+	if seen && !os.SameFile(last, info) {
+		return true, nil
+	}
+	state, err := p.store.GetIngestState(ctx, logPath)
+	if err != nil {
+		return false, err
+	}
+	return state.Found && state.Offset > info.Size(), nil
+}
+
+// catchUpRotated parses the rotated copy of logPath from where logPath's
+// saved offset left off, with the parse state (an unfinished match, the
+// draft in progress) carried across. It does nothing, reporting false,
+// when there is no rotated copy at least that long to carry on from.
+func (p *Parser) catchUpRotated(ctx context.Context, logPath string) (model.ParseStats, bool, error) {
+	state, err := p.store.GetIngestState(ctx, logPath)
+	if err != nil {
+		return model.ParseStats{}, false, err
+	}
+	if !state.Found || state.Offset == 0 {
+		return model.ParseStats{}, false, nil
+	}
+	prevPath := rotatedLogPath(logPath)
+	prevInfo, err := os.Stat(prevPath)
+	if err != nil || prevInfo.Size() < state.Offset {
+		log.Printf("%s was rotated but %s does not continue it; its last lines may be missing until the next full parse", logPath, prevPath)
+		return model.ParseStats{}, false, nil
+	}
+
+	p.stateMu.Lock()
+	if carried, ok := p.stateByLog[strings.TrimSpace(logPath)]; ok {
+		p.stateByLog[strings.TrimSpace(prevPath)] = carried
+	}
+	p.stateMu.Unlock()
+
+	tx, err := p.store.BeginTx(ctx)
+	if err != nil {
+		return model.ParseStats{}, false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if err := p.store.SaveIngestState(ctx, tx, prevPath, state.Offset, state.LineNo); err != nil {
+		return model.ParseStats{}, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.ParseStats{}, false, fmt.Errorf("commit tx: %w", err)
+	}
+
+	stats, err := p.ParseFile(ctx, prevPath, true)
+	return stats, true, err
+}
+
+// rotatedLogPath names the file Arena rotates logPath into: Player.log
+// becomes Player-prev.log in the same directory.
+func rotatedLogPath(logPath string) string {
+	ext := filepath.Ext(logPath)
+	return strings.TrimSuffix(logPath, ext) + "-prev" + ext
+}
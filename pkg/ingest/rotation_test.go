@@ -0,0 +1,77 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/solean/ponder/pkg/db"
+)
+
+func completedMatchLine(matchID string) string {
+	return fmt.Sprintf(`{"timestamp":"1773367612385","matchGameRoomStateChangedEvent":{"gameRoomInfo":{"gameRoomConfig":{"matchId":%q,"reservedPlayers":[{"userId":"OPP456","playerName":"Opponent","systemSeatId":1,"teamId":1,"eventId":"Traditional_Ladder"},{"userId":"SELF123","playerName":"Self","systemSeatId":2,"teamId":2,"eventId":"Traditional_Ladder"}]},"stateType":"MatchGameRoomStateType_MatchCompleted","finalMatchResult":{"matchId":%q,"matchCompletedReason":"MatchCompletedReasonType_Success","resultList":[{"scope":"MatchScope_Match","result":"ResultType_WinLoss","winningTeamId":2,"reason":"ResultReason_Game"}]}}}}`, matchID, matchID)
+}
+
+func TestTailFileCatchesUpRotatedLog(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tempDir, "ponder.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := db.Init(ctx, database); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	parser := NewParser(db.NewStore(database))
+
+	current := filepath.Join(tempDir, "Player.log")
+	if err := writeLogLines(current, []string{`{"PersonaId":"SELF123"}`, completedMatchLine("match-1")}, false); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	if _, err := parser.TailFile(ctx, current); err != nil {
+		t.Fatalf("first TailFile: %v", err)
+	}
+
+	// The session goes on past the last pass, then Arena restarts: the old
+	// log becomes Player-prev.log and a new, longer one begins.
+	if err := writeLogLines(current, []string{completedMatchLine("match-2")}, true); err != nil {
+		t.Fatalf("append log: %v", err)
+	}
+	if err := os.Rename(current, filepath.Join(tempDir, "Player-prev.log")); err != nil {
+		t.Fatalf("rotate log: %v", err)
+	}
+	lines := []string{`{"PersonaId":"SELF123"}`}
+	for i := 0; i < 5; i++ {
+		lines = append(lines, `{"filler":"a line so the new log outgrows the old offset"}`)
+	}
+	lines = append(lines, completedMatchLine("match-3"))
+	if err := writeLogLines(current, lines, false); err != nil {
+		t.Fatalf("write new log: %v", err)
+	}
+
+	results, err := parser.TailFile(ctx, current)
+	if err != nil {
+		t.Fatalf("TailFile after rotation: %v", err)
+	}
+	if len(results) != 2 || filepath.Base(results[0].LogPath) != "Player-prev.log" || results[1].LogPath != current {
+		t.Fatalf("TailFile parsed %+v, want Player-prev.log then Player.log", results)
+	}
+	if results[0].LinesRead != 1 {
+		t.Fatalf("rotated log lines read = %d, want only the 1 unread line", results[0].LinesRead)
+	}
+
+	for _, matchID := range []string{"match-1", "match-2", "match-3"} {
+		var result string
+		if err := database.QueryRowContext(ctx, `SELECT result FROM matches WHERE arena_match_id = ?`, matchID).Scan(&result); err != nil {
+			t.Fatalf("%s: %v", matchID, err)
+		}
+		if result != "win" {
+			t.Fatalf("%s result = %q, want win", matchID, result)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package ingest
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchLog returns a channel that receives whenever the log at path may have
+// new lines. It watches the log's directory rather than the file, so Arena
+// rotating Player.log into Player-prev.log and starting a fresh one is seen
+// too. Where filesystem notifications are unavailable, or the watcher fails
+// later, it falls back to waking every poll. Wake-ups coalesce: a tail loop
+// that is still parsing gets one more wake, not one per write. notifying
+// reports whether notifications are in use. Even then a slow backstop tick,
+// watchBackstopPolls times poll, keeps waking the loop in case a notification
+// is missed, as happens on some network drives and with overflowed queues.
+func WatchLog(ctx context.Context, path string, poll time.Duration) (wake <-chan struct{}, notifying bool) {
+	ch := make(chan struct{}, 1)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = watcher.Add(filepath.Dir(path)); err != nil {
+			_ = watcher.Close()
+		}
+	}
+	if err != nil {
+		log.Printf("log notifications unavailable, polling every %s: %v", poll, err)
+		go pollWake(ctx, ch, poll)
+		return ch, false
+	}
+
+	go watchWake(ctx, watcher, ch, filepath.Base(path), poll)
+	return ch, true
+}
+
+// watchBackstopPolls is how many poll intervals pass between backstop wakes
+// while notifications are in use.
+const watchBackstopPolls = 10
+
+func watchWake(ctx context.Context, watcher *fsnotify.Watcher, ch chan<- struct{}, name string, poll time.Duration) {
+	backstop := time.NewTicker(watchBackstopPolls * poll)
+	defer backstop.Stop()
+	fallBack := func() {
+		_ = watcher.Close()
+		backstop.Stop()
+		pollWake(ctx, ch, poll)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			_ = watcher.Close()
+			return
+		case <-backstop.C:
+			signalWake(ch)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				fallBack()
+				return
+			}
+			if filepath.Base(event.Name) == name && !event.Has(fsnotify.Chmod) {
+				signalWake(ch)
+			}
+		case err, ok := <-watcher.Errors:
+			if ok {
+				log.Printf("log watcher failed, polling every %s: %v", poll, err)
+			}
+			fallBack()
+			return
+		}
+	}
+}
+
+func pollWake(ctx context.Context, ch chan<- struct{}, poll time.Duration) {
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			signalWake(ch)
+		}
+	}
+}
+
+func signalWake(ch chan<- struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,70 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchLogWakesOnWriteWithoutPolling(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "Player.log")
+	if err := os.WriteFile(logPath, []byte("first\n"), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// An hour-long poll means only a notification can wake us in time.
+	wake, notifying := WatchLog(ctx, logPath, time.Hour)
+	if !notifying {
+		t.Skip("filesystem notifications unavailable here")
+	}
+
+	// Writes to a neighbouring file must not wake the tail loop.
+	if err := os.WriteFile(filepath.Join(filepath.Dir(logPath), "other.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write other file: %v", err)
+	}
+	select {
+	case <-wake:
+		t.Fatal("woke for a write to another file")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+	if _, err := file.WriteString("second\n"); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	_ = file.Close()
+
+	select {
+	case <-wake:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no wake within 5s of appending to the log")
+	}
+}
+
+func TestWatchLogBackstopWakesIdleLog(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "Player.log")
+	if err := os.WriteFile(logPath, []byte("first\n"), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wake, notifying := WatchLog(ctx, logPath, 20*time.Millisecond)
+	if !notifying {
+		t.Skip("filesystem notifications unavailable here")
+	}
+
+	// Nothing writes the log, so only the backstop tick can wake us.
+	select {
+	case <-wake:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no backstop wake for an idle log")
+	}
+}
@@ -0,0 +1,3 @@
+// Package model holds the records package db returns and the HTTP API
+// serves; the JSON tags are the API's field names.
+package model
@@ -0,0 +1,1630 @@
+package model
+
+import "time"
+
+type ParseStats struct {
+	LogPath          string
+	LinesRead        int64
+	BytesRead        int64
+	RawEventsStored  int64
+	MatchesUpserted  int64
+	RankSnapshots    int64
+	EconomySnapshots int64
+	DecksUpserted    int64
+	DraftPicksAdded  int64
+	LinesSkipped     int64
+	StartedAt        time.Time
+	CompletedAt      time.Time
+	// EventKinds and UnrecognizedKinds count dispatched lines by kind, such
+	// as "request EventJoin"; only filled when the parser counts them.
+	EventKinds        map[string]int64
+	UnrecognizedKinds map[string]int64
+}
+
+type MatchRow struct {
+	ID                      int64    `json:"id"`
+	ArenaMatchID            string   `json:"arenaMatchId"`
+	EventName               string   `json:"eventName"`
+	EventDisplayName        string   `json:"eventDisplayName,omitempty"`
+	BestOf                  string   `json:"bestOf"`
+	PlayDraw                string   `json:"playDraw"`
+	Opponent                string   `json:"opponent"`
+	StartedAt               string   `json:"startedAt"`
+	EndedAt                 string   `json:"endedAt"`
+	Result                  string   `json:"result"`
+	WinReason               string   `json:"winReason"`
+	TurnCount               *int64   `json:"turnCount"`
+	SecondsCount            *int64   `json:"secondsCount"`
+	QueueSeconds            *int64   `json:"queueSeconds,omitempty"`
+	Archived                bool     `json:"archived"`
+	Source                  string   `json:"source"`
+	DeckID                  *int64   `json:"deckId"`
+	DeckName                *string  `json:"deckName"`
+	DeckVersionID           *int64   `json:"deckVersionId,omitempty"`
+	DeckVersionNumber       *int64   `json:"deckVersionNumber,omitempty"`
+	DeckColors              []string `json:"deckColors"`
+	DeckColorsKnown         bool     `json:"deckColorsKnown"`
+	OpponentDeckColors      []string `json:"opponentDeckColors"`
+	OpponentDeckColorsKnown bool     `json:"opponentDeckColorsKnown"`
+}
+
+type OpponentObservedCardRow struct {
+	CardID   int64  `json:"cardId"`
+	Quantity int64  `json:"quantity"`
+	CardName string `json:"cardName,omitempty"`
+}
+
+type MatchCardPlayRow struct {
+	ID              int64  `json:"id"`
+	GameNumber      *int64 `json:"gameNumber,omitempty"`
+	InstanceID      int64  `json:"instanceId"`
+	CardID          int64  `json:"cardId"`
+	CardName        string `json:"cardName,omitempty"`
+	OwnerSeatID     *int64 `json:"ownerSeatId,omitempty"`
+	PlayerSide      string `json:"playerSide"`
+	FirstPublicZone string `json:"firstPublicZone"`
+	TurnNumber      *int64 `json:"turnNumber,omitempty"`
+	Phase           string `json:"phase,omitempty"`
+	PlayedAt        string `json:"playedAt,omitempty"`
+}
+
+type MatchReplayChangeRow struct {
+	InstanceID       int64  `json:"instanceId"`
+	CardID           int64  `json:"cardId"`
+	CardName         string `json:"cardName,omitempty"`
+	OwnerSeatID      *int64 `json:"ownerSeatId,omitempty"`
+	PlayerSide       string `json:"playerSide"`
+	Action           string `json:"action"`
+	FromZoneID       *int64 `json:"fromZoneId,omitempty"`
+	FromZoneType     string `json:"fromZoneType,omitempty"`
+	FromZonePosition *int64 `json:"fromZonePosition,omitempty"`
+	ToZoneID         *int64 `json:"toZoneId,omitempty"`
+	ToZoneType       string `json:"toZoneType,omitempty"`
+	ToZonePosition   *int64 `json:"toZonePosition,omitempty"`
+	IsToken          bool   `json:"isToken"`
+}
+
+type MatchReplayFrameObjectRow struct {
+	ID                   int64  `json:"id"`
+	FrameID              int64  `json:"frameId"`
+	InstanceID           int64  `json:"instanceId"`
+	CardID               int64  `json:"cardId"`
+	CardName             string `json:"cardName,omitempty"`
+	OwnerSeatID          *int64 `json:"ownerSeatId,omitempty"`
+	ControllerSeatID     *int64 `json:"controllerSeatId,omitempty"`
+	PlayerSide           string `json:"playerSide"`
+	ZoneID               *int64 `json:"zoneId,omitempty"`
+	ZoneType             string `json:"zoneType"`
+	ZonePosition         *int64 `json:"zonePosition,omitempty"`
+	Visibility           string `json:"visibility,omitempty"`
+	Power                *int64 `json:"power,omitempty"`
+	Toughness            *int64 `json:"toughness,omitempty"`
+	AttackTargetID       *int64 `json:"attackTargetId,omitempty"`
+	BlockAttackerIDsJSON string `json:"blockAttackerIdsJson,omitempty"`
+	CounterSummaryJSON   string `json:"counterSummaryJson,omitempty"`
+	DetailsJSON          string `json:"detailsJson,omitempty"`
+	AttackState          string `json:"attackState,omitempty"`
+	BlockState           string `json:"blockState,omitempty"`
+	IsToken              bool   `json:"isToken"`
+	IsTapped             bool   `json:"isTapped"`
+	HasSummoningSickness bool   `json:"hasSummoningSickness"`
+}
+
+type MatchReplayFrameRow struct {
+	ID                int64                       `json:"id"`
+	GameNumber        *int64                      `json:"gameNumber,omitempty"`
+	GameStateID       *int64                      `json:"gameStateId,omitempty"`
+	PrevGameStateID   *int64                      `json:"prevGameStateId,omitempty"`
+	GameStateType     string                      `json:"gameStateType,omitempty"`
+	GameStage         string                      `json:"gameStage,omitempty"`
+	TurnNumber        *int64                      `json:"turnNumber,omitempty"`
+	Phase             string                      `json:"phase,omitempty"`
+	SelfLifeTotal     *int64                      `json:"selfLifeTotal,omitempty"`
+	OpponentLifeTotal *int64                      `json:"opponentLifeTotal,omitempty"`
+	WinningPlayerSide string                      `json:"winningPlayerSide,omitempty"`
+	WinReason         string                      `json:"winReason,omitempty"`
+	RecordedAt        string                      `json:"recordedAt,omitempty"`
+	ActionsJSON       string                      `json:"actionsJson,omitempty"`
+	AnnotationsJSON   string                      `json:"annotationsJson,omitempty"`
+	Objects           []MatchReplayFrameObjectRow `json:"objects,omitempty"`
+	Changes           []MatchReplayChangeRow      `json:"changes,omitempty"`
+}
+
+// MatchTimeline is a match laid out turn by turn for replay-style views:
+// card plays, life-total changes, and notable game-state annotations.
+type MatchTimeline struct {
+	MatchID int64               `json:"matchId"`
+	Games   []MatchTimelineGame `json:"games"`
+}
+
+type MatchTimelineGame struct {
+	GameNumber        int64               `json:"gameNumber"`
+	WinningPlayerSide string              `json:"winningPlayerSide,omitempty"`
+	WinReason         string              `json:"winReason,omitempty"`
+	Turns             []MatchTimelineTurn `json:"turns"`
+}
+
+// MatchTimelineTurn groups one turn of a game. Turn 0 holds pregame states
+// such as mulligans. Life totals are as of the last recorded state in the
+// turn.
+type MatchTimelineTurn struct {
+	TurnNumber        int64                     `json:"turnNumber"`
+	StartedAt         string                    `json:"startedAt,omitempty"`
+	SelfLifeTotal     *int64                    `json:"selfLifeTotal,omitempty"`
+	OpponentLifeTotal *int64                    `json:"opponentLifeTotal,omitempty"`
+	Plays             []MatchCardPlayRow        `json:"plays"`
+	LifeChanges       []MatchTimelineLifeChange `json:"lifeChanges"`
+	Annotations       []MatchTimelineAnnotation `json:"annotations"`
+}
+
+type MatchTimelineLifeChange struct {
+	PlayerSide  string `json:"playerSide"`
+	From        int64  `json:"from"`
+	To          int64  `json:"to"`
+	Delta       int64  `json:"delta"`
+	Phase       string `json:"phase,omitempty"`
+	GameStateID *int64 `json:"gameStateId,omitempty"`
+	RecordedAt  string `json:"recordedAt,omitempty"`
+}
+
+// MatchTimelineAnnotation is one Arena game-state annotation (zone transfer,
+// damage, counters, tokens, scry). Category is the zone-transfer reason such
+// as "CastSpell" or "Destroy"; Amount is damage or counters moved.
+type MatchTimelineAnnotation struct {
+	Type          string                 `json:"type"`
+	Category      string                 `json:"category,omitempty"`
+	Amount        *int64                 `json:"amount,omitempty"`
+	Phase         string                 `json:"phase,omitempty"`
+	GameStateID   *int64                 `json:"gameStateId,omitempty"`
+	RecordedAt    string                 `json:"recordedAt,omitempty"`
+	Source        *MatchTimelineCardRef  `json:"source,omitempty"`
+	Targets       []MatchTimelineCardRef `json:"targets,omitempty"`
+	TargetPlayers []string               `json:"targetPlayers,omitempty"`
+}
+
+type MatchTimelineCardRef struct {
+	InstanceID int64  `json:"instanceId"`
+	CardID     int64  `json:"cardId"`
+	CardName   string `json:"cardName,omitempty"`
+	PlayerSide string `json:"playerSide"`
+}
+
+type MatchDetail struct {
+	Match                 MatchRow                  `json:"match"`
+	OpponentObservedCards []OpponentObservedCardRow `json:"opponentObservedCards"`
+	CardPlays             []MatchCardPlayRow        `json:"cardPlays"`
+	Games                 []GameRow                 `json:"games"`
+	Coverage              MatchAnalyticsCoverage    `json:"coverage"`
+}
+
+type OpeningHandCardRow struct {
+	CardID   int64  `json:"cardId"`
+	Quantity int64  `json:"quantity"`
+	CardName string `json:"cardName,omitempty"`
+	Kept     bool   `json:"kept"`
+}
+
+type OpeningHandRow struct {
+	ID              int64                `json:"id"`
+	AttemptNumber   int64                `json:"attemptNumber"`
+	Decision        string               `json:"decision"`
+	OfferedHandSize int64                `json:"offeredHandSize"`
+	KeptHandSize    *int64               `json:"keptHandSize,omitempty"`
+	ObservedAt      string               `json:"observedAt,omitempty"`
+	Source          string               `json:"source"`
+	Confidence      string               `json:"confidence"`
+	Cards           []OpeningHandCardRow `json:"cards"`
+}
+
+type GameRow struct {
+	ID                        int64             `json:"id"`
+	GameNumber                int64             `json:"gameNumber"`
+	Result                    string            `json:"result"`
+	WinReason                 string            `json:"winReason,omitempty"`
+	PlayDraw                  string            `json:"playDraw,omitempty"`
+	StartedAt                 string            `json:"startedAt,omitempty"`
+	EndedAt                   string            `json:"endedAt,omitempty"`
+	TurnCount                 *int64            `json:"turnCount,omitempty"`
+	OpeningLifeTotal          *int64            `json:"openingLifeTotal,omitempty"`
+	EndingLifeTotal           *int64            `json:"endingLifeTotal,omitempty"`
+	MulliganCount             *int64            `json:"mulliganCount,omitempty"`
+	KeptHandSize              *int64            `json:"keptHandSize,omitempty"`
+	MinSelfLife               *int64            `json:"minSelfLife,omitempty"`
+	MinOpponentLife           *int64            `json:"minOpponentLife,omitempty"`
+	SelfSpellsCast            *int64            `json:"selfSpellsCast,omitempty"`
+	OpponentSpellsCast        *int64            `json:"opponentSpellsCast,omitempty"`
+	SelfAttackersDeclared     *int64            `json:"selfAttackersDeclared,omitempty"`
+	OpponentAttackersDeclared *int64            `json:"opponentAttackersDeclared,omitempty"`
+	ResultSource              string            `json:"resultSource,omitempty"`
+	ResultConfidence          string            `json:"resultConfidence"`
+	PlayDrawSource            string            `json:"playDrawSource,omitempty"`
+	PlayDrawConfidence        string            `json:"playDrawConfidence"`
+	OpeningHandSource         string            `json:"openingHandSource,omitempty"`
+	OpeningHandConfidence     string            `json:"openingHandConfidence"`
+	OpeningHands              []OpeningHandRow  `json:"openingHands"`
+	TurnStats                 []GameTurnStatRow `json:"turnStats"`
+	Flags                     []GameFlagRow     `json:"flags"`
+}
+
+// GameTurnStatRow is one turn's derived shape. Life, hand size, and land-in-hand
+// reflect the last replay frame of the turn; lands/spells count the player's
+// card plays classified by cached type line with a public-zone fallback.
+// Nil pointers mean the underlying data was not observable, never zero.
+type GameTurnStatRow struct {
+	TurnNumber   int64  `json:"turnNumber"`
+	IsPlayerTurn *bool  `json:"isPlayerTurn,omitempty"`
+	SelfLife     *int64 `json:"selfLife,omitempty"`
+	OpponentLife *int64 `json:"opponentLife,omitempty"`
+	SelfHandSize *int64 `json:"selfHandSize,omitempty"`
+	LandsPlayed  int64  `json:"landsPlayed"`
+	SpellsCast   int64  `json:"spellsCast"`
+	LandInHand   *bool  `json:"landInHand,omitempty"`
+	SpellsInHand *int64 `json:"spellsInHand,omitempty"`
+}
+
+// GameFlagRow is a descriptive decision-review flag computed from turn stats
+// at read time. Every flag is a heuristic prompt for replay review, not a
+// judgment of the play.
+type GameFlagRow struct {
+	Flag       string `json:"flag"`
+	TurnNumber *int64 `json:"turnNumber,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+	Confidence string `json:"confidence"`
+}
+
+type MatchAnalyticsCoverage struct {
+	ReplayAvailable       bool   `json:"replayAvailable"`
+	ReplayFrameCount      int64  `json:"replayFrameCount"`
+	GameCount             int64  `json:"gameCount"`
+	GamesWithResult       int64  `json:"gamesWithResult"`
+	GamesWithOpeningHand  int64  `json:"gamesWithOpeningHand"`
+	GamesWithPlayDraw     int64  `json:"gamesWithPlayDraw"`
+	GamesWithTurnStats    int64  `json:"gamesWithTurnStats"`
+	DeckSnapshotAvailable bool   `json:"deckSnapshotAvailable"`
+	DeckVersionAvailable  bool   `json:"deckVersionAvailable"`
+	OverallConfidence     string `json:"overallConfidence"`
+	DerivedAt             string `json:"derivedAt,omitempty"`
+}
+
+// RecordAgg is a win/loss/draw tally over games with a known result. Unknown
+// results are excluded and reported separately wherever a RecordAgg appears.
+type RecordAgg struct {
+	Games  int64 `json:"games"`
+	Wins   int64 `json:"wins"`
+	Losses int64 `json:"losses"`
+	Draws  int64 `json:"draws"`
+}
+
+// AnalyticsBucket groups games sharing one integer fact (kept-hand size,
+// mulligan count, kept land count) with the record inside that group.
+type AnalyticsBucket struct {
+	Key            int64     `json:"key"`
+	Record         RecordAgg `json:"record"`
+	UnknownResults int64     `json:"unknownResults"`
+}
+
+type DeckCardPerformance struct {
+	CardID             int64     `json:"cardId"`
+	CardName           string    `json:"cardName,omitempty"`
+	GamesSeen          int64     `json:"gamesSeen"`
+	UnknownResultGames int64     `json:"unknownResultGames"`
+	OpeningHand        RecordAgg `json:"openingHand"`
+	Drawn              RecordAgg `json:"drawn"`
+	InHand             RecordAgg `json:"inHand"`
+	Played             RecordAgg `json:"played"`
+	NotPlayed          RecordAgg `json:"notPlayed"`
+	GameOne            RecordAgg `json:"gameOne"`
+	PostBoard          RecordAgg `json:"postBoard"`
+	OnPlay             RecordAgg `json:"onPlay"`
+	OnDraw             RecordAgg `json:"onDraw"`
+	EndedInHandGames   int64     `json:"endedInHandGames"`
+	MulliganGames      int64     `json:"mulliganGames"`
+	MulliganCopies     int64     `json:"mulliganCopies"`
+	AvgFirstSeenTurn   *float64  `json:"avgFirstSeenTurn,omitempty"`
+	AvgFirstPlayedTurn *float64  `json:"avgFirstPlayedTurn,omitempty"`
+	AvgCopiesSeen      *float64  `json:"avgCopiesSeen,omitempty"`
+	AvgCopiesPlayed    *float64  `json:"avgCopiesPlayed,omitempty"`
+}
+
+type DeckAnalyticsCoverage struct {
+	Matches              int64 `json:"matches"`
+	MatchesWithVersion   int64 `json:"matchesWithVersion"`
+	GameCount            int64 `json:"gameCount"`
+	GamesWithResult      int64 `json:"gamesWithResult"`
+	GamesWithOpeningHand int64 `json:"gamesWithOpeningHand"`
+	GamesWithPlayDraw    int64 `json:"gamesWithPlayDraw"`
+	GamesWithCardStats   int64 `json:"gamesWithCardStats"`
+	GamesWithTurnStats   int64 `json:"gamesWithTurnStats"`
+	GamesWithLandJudged  int64 `json:"gamesWithLandJudged"`
+}
+
+// DeckTurnCurvePoint aggregates game shape at one turn across a deck's games
+// with turn stats, split by result. Lands are cumulative land drops through
+// the turn; spells are casts on that turn. Games are only counted at turns
+// they actually reached, and averages are nil when no game qualifies.
+type DeckTurnCurvePoint struct {
+	Turn            int64    `json:"turn"`
+	WinGames        int64    `json:"winGames"`
+	LossGames       int64    `json:"lossGames"`
+	AvgLandsWins    *float64 `json:"avgLandsWins,omitempty"`
+	AvgLandsLosses  *float64 `json:"avgLandsLosses,omitempty"`
+	AvgSpellsWins   *float64 `json:"avgSpellsWins,omitempty"`
+	AvgSpellsLosses *float64 `json:"avgSpellsLosses,omitempty"`
+}
+
+// DeckGameShape is the deck-level game-shape rollup: win rate by ending turn,
+// average winning/losing turn, missed-land-drop record splits (judged games
+// only), and per-turn land/spell curves split by result.
+type DeckGameShape struct {
+	GameLengths            []AnalyticsBucket `json:"gameLengths"`
+	AvgWinningTurn         *float64          `json:"avgWinningTurn,omitempty"`
+	AvgLosingTurn          *float64          `json:"avgLosingTurn,omitempty"`
+	LowestWinLife          *int64            `json:"lowestWinLife,omitempty"`
+	MissedDropGames        RecordAgg         `json:"missedDropGames"`
+	CleanDropGames         RecordAgg         `json:"cleanDropGames"`
+	MissedDropUnknownGames int64             `json:"missedDropUnknownGames"`
+	// Pace: average actions per game over games where they were observed.
+	AvgSpellsCast         *float64             `json:"avgSpellsCast,omitempty"`
+	AvgOpponentSpellsCast *float64             `json:"avgOpponentSpellsCast,omitempty"`
+	AvgAttackers          *float64             `json:"avgAttackers,omitempty"`
+	AvgOpponentAttackers  *float64             `json:"avgOpponentAttackers,omitempty"`
+	TurnCurve             []DeckTurnCurvePoint `json:"turnCurve"`
+}
+
+type DeckAnalytics struct {
+	DeckID                int64                 `json:"deckId"`
+	DeckVersionID         *int64                `json:"deckVersionId,omitempty"`
+	Coverage              DeckAnalyticsCoverage `json:"coverage"`
+	MatchRecord           RecordAgg             `json:"matchRecord"`
+	GameRecord            RecordAgg             `json:"gameRecord"`
+	UnknownResultGames    int64                 `json:"unknownResultGames"`
+	GameOne               RecordAgg             `json:"gameOne"`
+	PostBoard             RecordAgg             `json:"postBoard"`
+	OnPlay                RecordAgg             `json:"onPlay"`
+	OnDraw                RecordAgg             `json:"onDraw"`
+	AverageMulligans      *float64              `json:"averageMulligans,omitempty"`
+	HandSizes             []AnalyticsBucket     `json:"handSizes"`
+	MulliganCounts        []AnalyticsBucket     `json:"mulliganCounts"`
+	LandCounts            []AnalyticsBucket     `json:"landCounts"`
+	LandCountUnknownHands int64                 `json:"landCountUnknownHands"`
+	Cards                 []DeckCardPerformance `json:"cards"`
+	Shape                 DeckGameShape         `json:"shape"`
+}
+
+// DeckAnalyticsGameRef links one aggregated statistic back to a concrete game
+// so the UI can navigate to the match detail and replay it came from.
+type DeckAnalyticsGameRef struct {
+	MatchID           int64  `json:"matchId"`
+	GameNumber        int64  `json:"gameNumber"`
+	Result            string `json:"result"`
+	PlayDraw          string `json:"playDraw,omitempty"`
+	StartedAt         string `json:"startedAt,omitempty"`
+	Opponent          string `json:"opponent,omitempty"`
+	EventName         string `json:"eventName,omitempty"`
+	KeptHandSize      *int64 `json:"keptHandSize,omitempty"`
+	MulliganCount     *int64 `json:"mulliganCount,omitempty"`
+	OpeningKeptCopies int64  `json:"openingKeptCopies,omitempty"`
+	DrawnCopies       int64  `json:"drawnCopies,omitempty"`
+	PlayedCopies      int64  `json:"playedCopies,omitempty"`
+	EndInHandCopies   int64  `json:"endInHandCopies,omitempty"`
+	FirstPlayedTurn   *int64 `json:"firstPlayedTurn,omitempty"`
+}
+
+// OpponentClassification is the derived (or manually corrected) read on one
+// match's opponent, built only from locally observed cards.
+type OpponentClassification struct {
+	Colors        []string `json:"colors"`
+	ColorsKnown   bool     `json:"colorsKnown"`
+	Archetype     string   `json:"archetype"`
+	Source        string   `json:"source"` // "derived" or "manual"
+	Confidence    string   `json:"confidence"`
+	DistinctCards int64    `json:"distinctCards"`
+	ObservedCards int64    `json:"observedCards"`
+	PctObserved   float64  `json:"pctObserved"`
+	AvgManaValue  *float64 `json:"avgManaValue,omitempty"`
+	CreatureShare *float64 `json:"creatureShare,omitempty"`
+}
+
+type MatchupObservedCard struct {
+	CardID      int64  `json:"cardId"`
+	CardName    string `json:"cardName,omitempty"`
+	Matches     int64  `json:"matches"`
+	Copies      int64  `json:"copies"`
+	WinMatches  int64  `json:"winMatches"`
+	LossMatches int64  `json:"lossMatches"`
+}
+
+type MatchupMatchRef struct {
+	MatchID         int64    `json:"matchId"`
+	Opponent        string   `json:"opponent,omitempty"`
+	EventName       string   `json:"eventName,omitempty"`
+	Result          string   `json:"result"`
+	StartedAt       string   `json:"startedAt,omitempty"`
+	Colors          []string `json:"colors"`
+	Archetype       string   `json:"archetype"`
+	ArchetypeSource string   `json:"archetypeSource"`
+	Confidence      string   `json:"confidence"`
+	PctObserved     float64  `json:"pctObserved"`
+}
+
+// MatchupRow is one (opponent colors × archetype) group for a single deck.
+type MatchupRow struct {
+	ColorsKey          string                `json:"colorsKey"`
+	Colors             []string              `json:"colors"`
+	Archetype          string                `json:"archetype"`
+	Matches            RecordAgg             `json:"matches"`
+	Games              RecordAgg             `json:"games"`
+	UnknownResultGames int64                 `json:"unknownResultGames"`
+	GameOne            RecordAgg             `json:"gameOne"`
+	PostBoard          RecordAgg             `json:"postBoard"`
+	OnPlay             RecordAgg             `json:"onPlay"`
+	OnDraw             RecordAgg             `json:"onDraw"`
+	AvgPctObserved     float64               `json:"avgPctObserved"`
+	Confidence         string                `json:"confidence"`
+	TopObservedCards   []MatchupObservedCard `json:"topObservedCards"`
+	LossSkewedCards    []MatchupObservedCard `json:"lossSkewedCards"`
+	MatchRefs          []MatchupMatchRef     `json:"matchRefs"`
+}
+
+type MatchupDeck struct {
+	DeckID   int64        `json:"deckId"`
+	DeckName string       `json:"deckName"`
+	Format   string       `json:"format"`
+	Matches  RecordAgg    `json:"matches"`
+	Rows     []MatchupRow `json:"rows"`
+}
+
+type MatchupsResponse struct {
+	Decks      []MatchupDeck `json:"decks"`
+	Archetypes []string      `json:"archetypes"`
+}
+
+// DeckMatchupsResponse is one deck's matchup data for the deck detail page;
+// Deck is null when the deck has no deck-linked matches yet.
+type DeckMatchupsResponse struct {
+	Deck       *MatchupDeck `json:"deck"`
+	Archetypes []string     `json:"archetypes"`
+}
+
+// LimitedMatchupColorGroup pools the matches you played with one of your own
+// deck color identities within a set; Rows group by opponent color pair.
+type LimitedMatchupColorGroup struct {
+	ColorsKey   string       `json:"colorsKey"`
+	Colors      []string     `json:"colors"`
+	ColorsKnown bool         `json:"colorsKnown"`
+	Matches     RecordAgg    `json:"matches"`
+	DeckCount   int64        `json:"deckCount"`
+	Rows        []MatchupRow `json:"rows"`
+}
+
+// LimitedMatchupSet pools every limited match of one set; rows group by
+// opponent color pair (Archetype is blank on limited rows), and ColorGroups
+// split the same matches by your own deck's color identity.
+type LimitedMatchupSet struct {
+	SetCode     string                     `json:"setCode"`
+	Matches     RecordAgg                  `json:"matches"`
+	DeckCount   int64                      `json:"deckCount"`
+	Rows        []MatchupRow               `json:"rows"`
+	ColorGroups []LimitedMatchupColorGroup `json:"colorGroups"`
+}
+
+type LimitedMatchupsResponse struct {
+	Sets []LimitedMatchupSet `json:"sets"`
+}
+
+// LimitedColorPairStats is the run record of drafts whose final build had
+// one color identity. ColorsKnown is false when the deck or its cards could
+// not be resolved.
+type LimitedColorPairStats struct {
+	ColorsKey   string   `json:"colorsKey"`
+	Colors      []string `json:"colors"`
+	ColorsKnown bool     `json:"colorsKnown"`
+	Runs        int64    `json:"runs"`
+	Trophies    int64    `json:"trophies"`
+	Wins        int64    `json:"wins"`
+	Losses      int64    `json:"losses"`
+	AverageWins float64  `json:"averageWins"`
+	WinRate     float64  `json:"winRate"`
+}
+
+// LimitedFirstPick counts how often one card was taken first in a draft,
+// with the record of the runs that followed.
+type LimitedFirstPick struct {
+	CardID   int64    `json:"cardId"`
+	CardName string   `json:"cardName"`
+	Colors   []string `json:"colors"`
+	Count    int64    `json:"count"`
+	Wins     int64    `json:"wins"`
+	Losses   int64    `json:"losses"`
+}
+
+// LimitedFirstPickColor counts first picks by color: a single color,
+// "multicolor", or "colorless".
+type LimitedFirstPickColor struct {
+	Color string `json:"color"`
+	Count int64  `json:"count"`
+}
+
+// LimitedSetStats summarizes every draft of one set. Runs counts drafts
+// whose deck played at least one match; averages and rates are over runs.
+type LimitedSetStats struct {
+	SetCode         string                  `json:"setCode"`
+	Drafts          int64                   `json:"drafts"`
+	Runs            int64                   `json:"runs"`
+	Trophies        int64                   `json:"trophies"`
+	Wins            int64                   `json:"wins"`
+	Losses          int64                   `json:"losses"`
+	AverageWins     float64                 `json:"averageWins"`
+	WinRate         float64                 `json:"winRate"`
+	LastDraftedAt   string                  `json:"lastDraftedAt"`
+	ColorPairs      []LimitedColorPairStats `json:"colorPairs"`
+	FirstPicks      []LimitedFirstPick      `json:"firstPicks"`
+	FirstPickColors []LimitedFirstPickColor `json:"firstPickColors"`
+}
+
+type LimitedStatsResponse struct {
+	Sets []LimitedSetStats `json:"sets"`
+}
+
+// MatchGameSummary aggregates one match's derived games for matchup rollups.
+type MatchGameSummary struct {
+	Games        RecordAgg
+	UnknownGames int64
+	GameOne      RecordAgg
+	PostBoard    RecordAgg
+	OnPlay       RecordAgg
+	OnDraw       RecordAgg
+}
+
+type DeckSummaryRow struct {
+	DeckID           int64   `json:"deckId"`
+	DeckName         string  `json:"deckName"`
+	Format           string  `json:"format"`
+	EventName        string  `json:"eventName"`
+	EventDisplayName string  `json:"eventDisplayName,omitempty"`
+	Matches          int64   `json:"matches"`
+	Wins             int64   `json:"wins"`
+	Losses           int64   `json:"losses"`
+	WinRate          float64 `json:"winRate"`
+	FirstPlayedAt    string  `json:"firstPlayedAt,omitempty"`
+	LastUpdatedAt    string  `json:"lastUpdatedAt,omitempty"`
+}
+
+type DeckCardRow struct {
+	Section  string `json:"section"`
+	CardID   int64  `json:"cardId"`
+	Quantity int64  `json:"quantity"`
+	CardName string `json:"cardName,omitempty"`
+}
+
+type DeckDetail struct {
+	DeckID           int64            `json:"deckId"`
+	ArenaDeckID      string           `json:"arenaDeckId"`
+	Name             string           `json:"name"`
+	Format           string           `json:"format"`
+	EventName        string           `json:"eventName"`
+	EventDisplayName string           `json:"eventDisplayName,omitempty"`
+	Cards            []DeckCardRow    `json:"cards"`
+	Composition      *DeckComposition `json:"composition,omitempty"`
+	PaperValue       *DeckPaperValue  `json:"paperValue,omitempty"`
+	Matches          []MatchRow       `json:"matches"`
+	Versions         []DeckVersionRow `json:"versions"`
+}
+
+// DeckComposition breaks down a deck's main deck by mana value, color, and
+// card type. Curve buckets run 0 through 7, where 7 also holds anything
+// costlier; lands stay out of the curve and the color counts. A multicolor
+// card counts once toward each of its colors.
+type DeckComposition struct {
+	Cards            int64             `json:"cards"`
+	Lands            int64             `json:"lands"`
+	AvgManaValue     *float64          `json:"avgManaValue,omitempty"`
+	Curve            []DeckCurveBucket `json:"curve"`
+	UnknownManaValue int64             `json:"unknownManaValue"`
+	Colors           []DeckColorCount  `json:"colors"`
+	Types            []DeckTypeCount   `json:"types"`
+}
+
+type DeckCurveBucket struct {
+	ManaValue int64  `json:"manaValue"`
+	Label     string `json:"label"`
+	Cards     int64  `json:"cards"`
+	Creatures int64  `json:"creatures"`
+}
+
+// DeckColorCount counts nonland cards of one color; "C" is colorless.
+type DeckColorCount struct {
+	Color string `json:"color"`
+	Cards int64  `json:"cards"`
+}
+
+// DeckTypeCount counts cards in one type group, using the deck page's
+// grouping: a card lands in the first of lands, creatures, planeswalkers,
+// instants, sorceries, battles, artifacts, enchantments that its type line
+// names, else "other", or "unknown" when its type line isn't known.
+type DeckTypeCount struct {
+	Type  string `json:"type"`
+	Cards int64  `json:"cards"`
+}
+
+// DeckMergeResult reports a merge of duplicate decks into TargetID.
+type DeckMergeResult struct {
+	TargetID     int64 `json:"targetId"`
+	MergedDecks  int64 `json:"mergedDecks"`
+	MovedMatches int64 `json:"movedMatches"`
+}
+
+type DeckVersionRow struct {
+	ID            int64         `json:"id"`
+	VersionNumber int64         `json:"versionNumber"`
+	CardsHash     string        `json:"cardsHash"`
+	Source        string        `json:"source,omitempty"`
+	EffectiveAt   string        `json:"effectiveAt,omitempty"`
+	Cards         []DeckCardRow `json:"cards,omitempty"`
+}
+
+// DeckPrimer is a cached AI-generated strategy primer for a deck. Stale is
+// computed at read time by comparing CardsHash against the current deck list.
+type DeckPrimer struct {
+	DeckID    int64  `json:"deckId"`
+	CardsHash string `json:"cardsHash"`
+	Model     string `json:"model"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"createdAt"`
+	Stale     bool   `json:"stale"`
+}
+
+type DraftSessionRow struct {
+	ID               int64   `json:"id"`
+	EventName        string  `json:"eventName"`
+	EventDisplayName string  `json:"eventDisplayName,omitempty"`
+	DraftID          *string `json:"draftId"`
+	IsBotDraft       bool    `json:"isBotDraft"`
+	StartedAt        string  `json:"startedAt"`
+	CompletedAt      string  `json:"completedAt"`
+	Picks            int64   `json:"picks"`
+	Wins             *int64  `json:"wins,omitempty"`
+	Losses           *int64  `json:"losses,omitempty"`
+}
+
+type DraftPickRow struct {
+	ID            int64           `json:"id"`
+	PackNumber    int64           `json:"packNumber"`
+	PickNumber    int64           `json:"pickNumber"`
+	PickedCardIDs string          `json:"pickedCardIds"`
+	PackCardIDs   string          `json:"packCardIds"`
+	PickTs        string          `json:"pickTs"`
+	PickedCards   []DraftPickCard `json:"pickedCards,omitempty"`
+	PackCards     []DraftPickCard `json:"packCards,omitempty"`
+	Grade         *DraftPickGrade `json:"grade,omitempty"`
+}
+
+// DraftPickCard is one card in a pick or its pack. Rating is the card's
+// 17Lands GIH WR as a fraction, when ratings for the set are imported.
+type DraftPickCard struct {
+	CardID   int64    `json:"cardId"`
+	CardName string   `json:"cardName,omitempty"`
+	Rating   *float64 `json:"rating,omitempty"`
+}
+
+// DraftPickGrade compares a pick with the best-rated card in its pack by
+// 17Lands GIH WR. Delta is the pick's rating minus the best one: 0 when the
+// best card was taken, negative by how much it was passed over.
+type DraftPickGrade struct {
+	PickRating   *float64 `json:"pickRating,omitempty"`
+	BestCardID   int64    `json:"bestCardId"`
+	BestCardName string   `json:"bestCardName,omitempty"`
+	BestRating   float64  `json:"bestRating"`
+	Delta        *float64 `json:"delta,omitempty"`
+}
+
+type LiveMatch struct {
+	Match                 MatchRow                  `json:"match"`
+	OpponentObservedCards []OpponentObservedCardRow `json:"opponentObservedCards"`
+	Deck                  []DeckCardRow             `json:"deck"`
+	DeckTotal             int64                     `json:"deckTotal"`
+	LandCount             int64                     `json:"landCount"`
+	GameNumber            int64                     `json:"gameNumber"`
+	TurnNumber            int64                     `json:"turnNumber"`
+	LibraryEstimate       int64                     `json:"libraryEstimate"`
+}
+
+type SetInfo struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	IconSvgURI string `json:"iconSvgUri,omitempty"`
+	ReleasedAt string `json:"releasedAt,omitempty"`
+}
+
+// CardInfo is one /api/cards entry. Colors is the WUBRG-ordered color
+// identity; SetCode is lowercase, matching /api/sets keys.
+type CardInfo struct {
+	CardID          int64    `json:"cardId"`
+	Name            string   `json:"name,omitempty"`
+	SetCode         string   `json:"setCode,omitempty"`
+	CollectorNumber string   `json:"collectorNumber,omitempty"`
+	Rarity          string   `json:"rarity,omitempty"`
+	Colors          []string `json:"colors"`
+	ManaValue       *float64 `json:"manaValue,omitempty"`
+}
+
+type Overview struct {
+	PlayerName   string     `json:"playerName,omitempty"`
+	TotalMatches int64      `json:"totalMatches"`
+	Wins         int64      `json:"wins"`
+	Losses       int64      `json:"losses"`
+	WinRate      float64    `json:"winRate"`
+	Recent       []MatchRow `json:"recent"`
+	// Limited summarizes draft and sealed runs, as in /api/stats/trophies.
+	Limited TrophySummary `json:"limited"`
+}
+
+type WildcardBalance struct {
+	Common   int64 `json:"common"`
+	Uncommon int64 `json:"uncommon"`
+	Rare     int64 `json:"rare"`
+	Mythic   int64 `json:"mythic"`
+}
+
+type EconomyBoosterCount struct {
+	SetCode string `json:"setCode"`
+	Count   int64  `json:"count"`
+}
+
+type EconomySnapshot struct {
+	ID                    int64                 `json:"id"`
+	ObservedAt            string                `json:"observedAt"`
+	SequenceID            int64                 `json:"sequenceId"`
+	Gold                  int64                 `json:"gold"`
+	Gems                  int64                 `json:"gems"`
+	VaultProgress         int64                 `json:"vaultProgress"`
+	WildcardTrackPosition int64                 `json:"wildcardTrackPosition"`
+	Wildcards             WildcardBalance       `json:"wildcards"`
+	CustomTokens          map[string]int64      `json:"customTokens"`
+	Boosters              []EconomyBoosterCount `json:"boosters"`
+	Vouchers              map[string]int64      `json:"vouchers"`
+	ChangeSources         []string              `json:"changeSources"`
+}
+
+// EconomyTransaction is one normalized entry of an InventoryInfo Changes
+// array. EventName attributes the change to an event run where derivable;
+// EventLink says how ("source_id" and "event_name" are exact, "proximity" is
+// a time-window heuristic).
+type EconomyTransaction struct {
+	ID                 int64                 `json:"id"`
+	ObservedAt         string                `json:"observedAt"`
+	Source             string                `json:"source"`
+	EventName          string                `json:"eventName"`
+	EventLink          string                `json:"eventLink"`
+	GoldDelta          int64                 `json:"goldDelta"`
+	GemsDelta          int64                 `json:"gemsDelta"`
+	WildcardDeltas     WildcardBalance       `json:"wildcardDeltas"`
+	CardsGranted       int64                 `json:"cardsGranted"`
+	VaultProgressDelta int64                 `json:"vaultProgressDelta"`
+	Boosters           []EconomyBoosterCount `json:"boosters"`
+	CustomTokens       map[string]int64      `json:"customTokens"`
+	Vouchers           map[string]int64      `json:"vouchers"`
+}
+
+// EventRunEconomy is the cost/reward summary of one event run. Entry deltas
+// are negative; net values keep gold and gems separate deliberately.
+type EventRunEconomy struct {
+	EventName           string                `json:"eventName"`
+	EventType           string                `json:"eventType"`
+	SetCode             string                `json:"setCode"`
+	Status              string                `json:"status"`
+	StartedAt           string                `json:"startedAt"`
+	EndedAt             string                `json:"endedAt"`
+	Wins                int64                 `json:"wins"`
+	Losses              int64                 `json:"losses"`
+	EntryCurrencyType   string                `json:"entryCurrencyType"`
+	EntryCurrencyPaid   *int64                `json:"entryCurrencyPaid"`
+	EntryGold           int64                 `json:"entryGold"`
+	EntryGems           int64                 `json:"entryGems"`
+	RewardGold          int64                 `json:"rewardGold"`
+	RewardGems          int64                 `json:"rewardGems"`
+	RewardBoosters      []EconomyBoosterCount `json:"rewardBoosters"`
+	RewardCards         int64                 `json:"rewardCards"`
+	RewardVaultProgress int64                 `json:"rewardVaultProgress"`
+	NetGold             int64                 `json:"netGold"`
+	NetGems             int64                 `json:"netGems"`
+	// exact | inferred | none: whether reward attribution used only exact
+	// links, any proximity heuristics, or found no reward data at all.
+	LinkConfidence string `json:"linkConfidence"`
+}
+
+type EconomyHistory struct {
+	Latest       *EconomySnapshot     `json:"latest"`
+	History      []EconomySnapshot    `json:"history"`
+	Transactions []EconomyTransaction `json:"transactions"`
+	EventRuns    []EventRunEconomy    `json:"eventRuns"`
+}
+
+type RankState struct {
+	SeasonOrdinal *int64 `json:"seasonOrdinal"`
+	RankClass     string `json:"rankClass"`
+	Level         *int64 `json:"level"`
+	Step          *int64 `json:"step"`
+	MatchesWon    *int64 `json:"matchesWon"`
+	MatchesLost   *int64 `json:"matchesLost"`
+}
+
+type RankHistoryPoint struct {
+	MatchID          int64     `json:"matchId"`
+	ArenaMatchID     string    `json:"arenaMatchId"`
+	EventName        string    `json:"eventName"`
+	EventDisplayName string    `json:"eventDisplayName,omitempty"`
+	Opponent         string    `json:"opponent"`
+	Result           string    `json:"result"`
+	ObservedAt       string    `json:"observedAt"`
+	EndedAt          string    `json:"endedAt"`
+	Format           string    `json:"format"`
+	SecondsCount     *int64    `json:"secondsCount"`
+	DeckID           *int64    `json:"deckId"`
+	DeckName         string    `json:"deckName"`
+	Constructed      RankState `json:"constructed"`
+	Limited          RankState `json:"limited"`
+}
+
+// RankSeries is one ladder's rank progression, one point per match that
+// changed the ladder's state. Score places a rank on a continuous axis: the
+// integer part indexes the ladder's tiers (Spark/Bronze first, Mythic last)
+// and the fraction is progress through that tier's four levels.
+type RankSeries struct {
+	Format  string            `json:"format"`
+	Tiers   []string          `json:"tiers"`
+	Points  []RankSeriesPoint `json:"points"`
+	Seasons []RankSeason      `json:"seasons"`
+}
+
+type RankSeriesPoint struct {
+	MatchID          int64    `json:"matchId"`
+	EventName        string   `json:"eventName"`
+	EventDisplayName string   `json:"eventDisplayName,omitempty"`
+	Result           string   `json:"result"`
+	ObservedAt       string   `json:"observedAt"`
+	SeasonOrdinal    int64    `json:"seasonOrdinal"`
+	SeasonStart      bool     `json:"seasonStart"`
+	RankClass        string   `json:"rankClass"`
+	Level            *int64   `json:"level"`
+	Step             *int64   `json:"step"`
+	Label            string   `json:"label"`
+	Score            *float64 `json:"score"`
+	MatchesWon       *int64   `json:"matchesWon"`
+	MatchesLost      *int64   `json:"matchesLost"`
+}
+
+// RankSeason marks a season boundary in a RankSeries: the points from
+// StartIndex through EndIndex (inclusive) belong to SeasonOrdinal.
+type RankSeason struct {
+	SeasonOrdinal int64  `json:"seasonOrdinal"`
+	StartIndex    int    `json:"startIndex"`
+	EndIndex      int    `json:"endIndex"`
+	StartedAt     string `json:"startedAt"`
+	EndedAt       string `json:"endedAt"`
+	FinalLabel    string `json:"finalLabel"`
+	MatchesWon    *int64 `json:"matchesWon"`
+	MatchesLost   *int64 `json:"matchesLost"`
+}
+
+type RankHistorySeries struct {
+	Series []RankSeries `json:"series"`
+}
+
+// CardPlayExportRow is one match_card_plays row flattened with its match
+// identity for bulk export, where rows are streamed outside any match detail.
+type CardPlayExportRow struct {
+	MatchID      int64  `json:"matchId"`
+	ArenaMatchID string `json:"arenaMatchId"`
+	MatchCardPlayRow
+}
+
+// DeckCardExportRow is one card line of a deck's current list, flattened
+// with the deck it belongs to for export.
+type DeckCardExportRow struct {
+	DeckID      int64  `json:"deckId"`
+	ArenaDeckID string `json:"arenaDeckId"`
+	DeckName    string `json:"deckName"`
+	Format      string `json:"format"`
+	EventName   string `json:"eventName"`
+	Section     string `json:"section"`
+	CardID      int64  `json:"cardId"`
+	CardName    string `json:"cardName,omitempty"`
+	Quantity    int64  `json:"quantity"`
+}
+
+// DraftPickExportRow is one draft pick flattened with its session for export.
+type DraftPickExportRow struct {
+	DraftSessionID int64  `json:"draftSessionId"`
+	DraftID        string `json:"draftId"`
+	EventName      string `json:"eventName"`
+	IsBotDraft     bool   `json:"isBotDraft"`
+	PackNumber     int64  `json:"packNumber"`
+	PickNumber     int64  `json:"pickNumber"`
+	PickedCardIDs  string `json:"pickedCardIds"`
+	PackCardIDs    string `json:"packCardIds"`
+	PickTs         string `json:"pickTs"`
+}
+
+// ImportedMatch is one match read from another tracker's export. ID is the
+// Arena match id when the export has one, otherwise a stable id derived from
+// the row so importing the same file twice adds nothing.
+type ImportedMatch struct {
+	ID        string
+	EventName string
+	Format    string
+	Opponent  string
+	StartedAt string
+	EndedAt   string
+	Result    string
+	TurnCount int64
+}
+
+// ImportedDraft is one draft session read from another tracker's export.
+type ImportedDraft struct {
+	DraftID   string
+	EventName string
+	StartedAt string
+	Picks     []ImportedDraftPick
+}
+
+// ImportedDraftPick names its cards by Arena id or by card name; names are
+// resolved against the card catalog on import.
+type ImportedDraftPick struct {
+	PackNumber int64
+	PickNumber int64
+	Picked     []string
+	Pack       []string
+	PickedAt   string
+}
+
+// QueueTimeBucket summarizes matchmaking waits for one event or hour of day.
+type QueueTimeBucket struct {
+	Key           string  `json:"key"`
+	EventType     string  `json:"eventType,omitempty"`
+	Hour          *int64  `json:"hour,omitempty"`
+	Matches       int64   `json:"matches"`
+	AvgSeconds    float64 `json:"avgSeconds"`
+	MedianSeconds int64   `json:"medianSeconds"`
+}
+
+// IngestLogStatus is the saved read position of one tailed log. FileSize is
+// -1 when the file can no longer be stat'd (rotated away or unreadable).
+type IngestLogStatus struct {
+	Path        string `json:"path"`
+	Offset      int64  `json:"offset"`
+	LineNo      int64  `json:"lineNo"`
+	FileSize    int64  `json:"fileSize"`
+	BytesBehind int64  `json:"bytesBehind"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+// HealthStatus is the /api/health payload. Status is "ok" when the database
+// answers, "unavailable" otherwise.
+type HealthStatus struct {
+	Status        string `json:"status"`
+	Database      bool   `json:"database"`
+	DatabaseError string `json:"databaseError,omitempty"`
+	SchemaVersion int64  `json:"schemaVersion"`
+	// MigrationVersion is the newest numbered migration applied.
+	MigrationVersion int64             `json:"migrationVersion"`
+	LastIngestAt     string            `json:"lastIngestAt,omitempty"`
+	Logs             []IngestLogStatus `json:"logs"`
+}
+
+// CollectionCardInsight is one card we have held, with the first and last
+// time it showed up in a deck, draft pick, game, or inventory grant.
+// FirstSeenSource and Sources use "deck", "draft", "game", and "grant".
+type CollectionCardInsight struct {
+	CardID          int64    `json:"cardId"`
+	CardName        string   `json:"cardName,omitempty"`
+	FirstSeenAt     string   `json:"firstSeenAt"`
+	FirstSeenSource string   `json:"firstSeenSource"`
+	LastSeenAt      string   `json:"lastSeenAt"`
+	Sources         []string `json:"sources"`
+	TimesPlayed     int64    `json:"timesPlayed"`
+	LastPlayedAt    string   `json:"lastPlayedAt,omitempty"`
+}
+
+// CollectionInsights backs /api/collection/insights. Counts cover every
+// known card; Cards is narrowed by the requested view.
+type CollectionInsights struct {
+	View             string                  `json:"view"`
+	Since            string                  `json:"since"`
+	TotalCards       int64                   `json:"totalCards"`
+	NeverPlayedCount int64                   `json:"neverPlayedCount"`
+	NewCount         int64                   `json:"newCount"`
+	Cards            []CollectionCardInsight `json:"cards"`
+}
+
+// QueueTimeStats aggregates recorded queue waits by event and by the local
+// hour of day the match started.
+type QueueTimeStats struct {
+	Matches int64             `json:"matches"`
+	ByEvent []QueueTimeBucket `json:"byEvent"`
+	ByHour  []QueueTimeBucket `json:"byHour"`
+}
+
+// Settings are the user preferences kept in the database so every command
+// and the web UI share them. Keys missing from the database read as their
+// defaults.
+type Settings struct {
+	// DefaultFormat scopes the overview and match list when a request names
+	// no format; empty shows all.
+	DefaultFormat string `json:"defaultFormat"`
+	// LookupsEnabled allows card lookups against Scryfall.
+	LookupsEnabled bool `json:"lookupsEnabled"`
+	// RetentionDays is the raw event age prune keeps when -max-age-days is
+	// not given; 0 keeps everything.
+	RetentionDays int `json:"retentionDays"`
+	// ArchetypeSignatureSource picks how opponent archetypes are labeled:
+	// "derived" from observed cards, or "manual" labels only.
+	ArchetypeSignatureSource string `json:"archetypeSignatureSource"`
+	// MoxfieldToken and ArchidektToken authorize deck pushes to those
+	// sites; empty falls back to printing import text.
+	MoxfieldToken  string `json:"moxfieldToken"`
+	ArchidektToken string `json:"archidektToken"`
+}
+
+// DeckPushResult is a deck pushed to a deckbuilding site.
+type DeckPushResult struct {
+	Service  string `json:"service"`
+	RemoteID string `json:"remoteId"`
+	URL      string `json:"url"`
+	// Created is false when an earlier push of the deck was updated.
+	Created  bool   `json:"created"`
+	PushedAt string `json:"pushedAt"`
+}
+
+// CollectionExportRow is one card we have held, as the collection export
+// writes it. Arena does not log the collection itself, so Quantity is the
+// most copies seen at once in one of our deck versions, one draft, or our
+// inventory grants, capped at Arena's four.
+type CollectionExportRow struct {
+	CardID          int64  `json:"cardId"`
+	Name            string `json:"name"`
+	SetCode         string `json:"setCode"`
+	SetName         string `json:"setName"`
+	CollectorNumber string `json:"collectorNumber"`
+	Rarity          string `json:"rarity"`
+	Quantity        int64  `json:"quantity"`
+}
+
+// CollectionValueHistory is the collection's paper value at each weekly
+// price snapshot. Cards is the collection's estimated size; PricedCards in
+// a point counts the copies that snapshot had a price for.
+type CollectionValueHistory struct {
+	Cards  int64                  `json:"cards"`
+	Points []CollectionValuePoint `json:"points"`
+}
+
+// CollectionValuePoint is one weekly snapshot, named by its Monday.
+type CollectionValuePoint struct {
+	Week        string  `json:"week"`
+	USD         float64 `json:"usd"`
+	EUR         float64 `json:"eur"`
+	PricedCards int64   `json:"pricedCards"`
+}
+
+// DeckPaperValue is what a deck's main deck and sideboard would cost on
+// paper at the latest prices. UnpricedCards counts copies with no price,
+// such as Arena-only cards.
+type DeckPaperValue struct {
+	USD           float64 `json:"usd"`
+	EUR           float64 `json:"eur"`
+	UnpricedCards int64   `json:"unpricedCards"`
+}
+
+// PickSuggestion is a pick advisor's view of one card in a pack. Score is
+// the advisor's own scale, higher is better; the built-in advisor uses
+// 17Lands GIH WR as a fraction.
+type PickSuggestion struct {
+	CardID   int64   `json:"cardId"`
+	CardName string  `json:"cardName,omitempty"`
+	Score    float64 `json:"score"`
+	Reason   string  `json:"reason,omitempty"`
+}
+
+// DraftPickSuggestions ranks one pick's pack, best first.
+type DraftPickSuggestions struct {
+	PackNumber  int64            `json:"packNumber"`
+	PickNumber  int64            `json:"pickNumber"`
+	Suggestions []PickSuggestion `json:"suggestions"`
+}
+
+// PlayDrawStats compares game win rates on the play and on the draw, over
+// every game and per deck. GamesWithoutPlayDraw counts games whose play or
+// draw could not be told from the log.
+type PlayDrawStats struct {
+	Overall              PlayDrawSplit       `json:"overall"`
+	Decks                []DeckPlayDrawSplit `json:"decks"`
+	GamesWithoutPlayDraw int64               `json:"gamesWithoutPlayDraw"`
+}
+
+// PlayDrawSplit is a record on the play and one on the draw.
+type PlayDrawSplit struct {
+	OnPlay WinRateInterval `json:"onPlay"`
+	OnDraw WinRateInterval `json:"onDraw"`
+}
+
+// DeckPlayDrawSplit is one deck's PlayDrawSplit.
+type DeckPlayDrawSplit struct {
+	DeckID   int64           `json:"deckId"`
+	DeckName string          `json:"deckName"`
+	Format   string          `json:"format"`
+	OnPlay   WinRateInterval `json:"onPlay"`
+	OnDraw   WinRateInterval `json:"onDraw"`
+}
+
+// WinRateInterval is a record with its win rate, draws counting as games not
+// won, and the 95% Wilson score interval around it. The rates are nil with
+// no games.
+type WinRateInterval struct {
+	Record  RecordAgg `json:"record"`
+	WinRate *float64  `json:"winRate,omitempty"`
+	Low     *float64  `json:"low,omitempty"`
+	High    *float64  `json:"high,omitempty"`
+}
+
+// LimitedColorStats breaks one set's draft runs down by the colors of the
+// final build: its main colors, and which of those decks splashed what.
+// UnknownRuns counts runs whose deck had no card with known colors.
+type LimitedColorStats struct {
+	SetCode     string               `json:"setCode"`
+	Runs        int64                `json:"runs"`
+	UnknownRuns int64                `json:"unknownRuns"`
+	ColorPairs  []LimitedColorRecord `json:"colorPairs"`
+	Splashes    []LimitedColorRecord `json:"splashes"`
+	Splashed    LimitedColorRecord   `json:"splashed"`
+	Unsplashed  LimitedColorRecord   `json:"unsplashed"`
+}
+
+// LimitedColorRecord is the record of the runs sharing main colors and,
+// for splashes, a splash. Key joins them as "UB" or "UB+R".
+type LimitedColorRecord struct {
+	Key         string   `json:"key"`
+	Colors      []string `json:"colors"`
+	Splash      []string `json:"splash"`
+	Runs        int64    `json:"runs"`
+	Trophies    int64    `json:"trophies"`
+	Wins        int64    `json:"wins"`
+	Losses      int64    `json:"losses"`
+	AverageWins float64  `json:"averageWins"`
+	WinRate     float64  `json:"winRate"`
+}
+
+// PlaySessionSummary is one play session: a run of matches with no long
+// break between them. Tilted is set when the session held a loss streak of
+// at least the tilt threshold, which began at LossStreakStartedAt.
+type PlaySessionSummary struct {
+	StartedAt           string              `json:"startedAt"`
+	EndedAt             string              `json:"endedAt"`
+	DurationSeconds     int64               `json:"durationSeconds"`
+	Matches             int64               `json:"matches"`
+	Wins                int64               `json:"wins"`
+	Losses              int64               `json:"losses"`
+	Draws               int64               `json:"draws"`
+	WinRate             float64             `json:"winRate"`
+	Events              []string            `json:"events"`
+	EventDisplayNames   []string            `json:"eventDisplayNames"`
+	RankChanges         []SessionRankChange `json:"rankChanges"`
+	LongestLossStreak   int64               `json:"longestLossStreak"`
+	LossStreakStartedAt string              `json:"lossStreakStartedAt,omitempty"`
+	EndingLossStreak    int64               `json:"endingLossStreak"`
+	Tilted              bool                `json:"tilted"`
+}
+
+// SessionRankChange is how one ladder's rank moved over a session. Steps is
+// the net pips gained (negative when lost); it is nil when the season
+// changed mid-session.
+type SessionRankChange struct {
+	Format string `json:"format"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Steps  *int64 `json:"steps,omitempty"`
+}
+
+// MilestoneStats is the player's win streaks and the milestones reached,
+// newest first.
+type MilestoneStats struct {
+	CurrentWinStreak int64       `json:"currentWinStreak"`
+	BestWinStreak    int64       `json:"bestWinStreak"`
+	Milestones       []Milestone `json:"milestones"`
+}
+
+// Milestone is one achievement and the match that reached it. Key is stable
+// across calls, so a notifier can tell which milestones it has announced.
+type Milestone struct {
+	Key        string `json:"key"`
+	Kind       string `json:"kind"`
+	Title      string `json:"title"`
+	Detail     string `json:"detail,omitempty"`
+	Value      int64  `json:"value,omitempty"`
+	MatchID    int64  `json:"matchId"`
+	EventName  string `json:"eventName,omitempty"`
+	AchievedAt string `json:"achievedAt"`
+}
+
+// EconomyStats is what event entries return: the expected value of a run
+// per event kind, and the runs it is averaged over, newest first.
+type EconomyStats struct {
+	// GoldPerGem converts gold into gems for the gem-equivalent figures.
+	GoldPerGem float64            `json:"goldPerGem"`
+	EventTypes []EventTypeEconomy `json:"eventTypes"`
+	Runs       []EventRunEconomy  `json:"runs"`
+}
+
+// EventTypeEconomy totals the settled runs of one event kind: runs whose
+// rewards were claimed and found in the ledger. EV fields are the mean net
+// per settled run (negative when entries cost more than they return), and
+// EVGemEquivalent folds gold in at GoldPerGem. UnsettledRuns are still
+// active or have no rewards on record, and are left out of every total.
+type EventTypeEconomy struct {
+	Kind            string  `json:"kind"`
+	DisplayName     string  `json:"displayName"`
+	Runs            int64   `json:"runs"`
+	UnsettledRuns   int64   `json:"unsettledRuns"`
+	Wins            int64   `json:"wins"`
+	Losses          int64   `json:"losses"`
+	AverageWins     float64 `json:"averageWins"`
+	EntryGold       int64   `json:"entryGold"`
+	EntryGems       int64   `json:"entryGems"`
+	RewardGold      int64   `json:"rewardGold"`
+	RewardGems      int64   `json:"rewardGems"`
+	RewardBoosters  int64   `json:"rewardBoosters"`
+	RewardCards     int64   `json:"rewardCards"`
+	NetGold         int64   `json:"netGold"`
+	NetGems         int64   `json:"netGems"`
+	EVGold          float64 `json:"evGold"`
+	EVGems          float64 `json:"evGems"`
+	EVGemEquivalent float64 `json:"evGemEquivalent"`
+}
+
+// TimeOfDayStats is match win rate by the local hour of day and weekday a
+// match started, in TimeZone.
+type TimeOfDayStats struct {
+	TimeZone string          `json:"timeZone"`
+	Hours    []TimeSlotStats `json:"hours"`
+	Weekdays []TimeSlotStats `json:"weekdays"`
+}
+
+// TimeSlotStats is the match record of one hour of the day (0-23) or one
+// weekday (0 is Sunday).
+type TimeSlotStats struct {
+	Slot  int             `json:"slot"`
+	Label string          `json:"label"`
+	Rate  WinRateInterval `json:"rate"`
+}
+
+// GameLengthStats is how long matches run, in turns and in seconds, overall
+// and grouped by deck, format, set, and the month they started, busiest
+// first except Months, which is oldest first.
+type GameLengthStats struct {
+	Overall GameLengthGroup   `json:"overall"`
+	Decks   []GameLengthGroup `json:"decks"`
+	Formats []GameLengthGroup `json:"formats"`
+	Sets    []GameLengthGroup `json:"sets"`
+	Months  []GameLengthGroup `json:"months"`
+}
+
+// GameLengthGroup averages one group's match lengths. Key is the deck id,
+// format, set code, or YYYY-MM month. Averages cover only the matches with
+// that count recorded (TurnMatches, SecondsMatches) and are nil without any.
+type GameLengthGroup struct {
+	Key              string   `json:"key"`
+	Label            string   `json:"label"`
+	Matches          int64    `json:"matches"`
+	TurnMatches      int64    `json:"turnMatches"`
+	AverageTurns     *float64 `json:"averageTurns"`
+	WinAverageTurns  *float64 `json:"winAverageTurns"`
+	LossAverageTurns *float64 `json:"lossAverageTurns"`
+	SecondsMatches   int64    `json:"secondsMatches"`
+	AverageSeconds   *float64 `json:"averageSeconds"`
+}
+
+// MetaReport is what the player has been facing: opponent decks per format
+// and week, from the same archetype labels as the matchup views.
+type MetaReport struct {
+	Weeks   int64        `json:"weeks"`
+	Formats []MetaFormat `json:"formats"`
+}
+
+// MetaFormat is one format's weekly opponent decks, newest week first.
+type MetaFormat struct {
+	Format  string     `json:"format"`
+	Matches int64      `json:"matches"`
+	Weeks   []MetaWeek `json:"weeks"`
+}
+
+// MetaWeek is one week, starting Monday (UTC), of a format's opponents,
+// most faced first.
+type MetaWeek struct {
+	WeekStart string     `json:"weekStart"`
+	Matches   int64      `json:"matches"`
+	Decks     []MetaDeck `json:"decks"`
+}
+
+// MetaDeck is one opponent color and archetype combination in a week.
+// Share is its fraction of the week's matches; PreviousShare and ShareDelta
+// compare with the week before and are nil when that week had no matches
+// in the format. Record is the player's record against it.
+type MetaDeck struct {
+	ColorsKey     string    `json:"colorsKey"`
+	Colors        []string  `json:"colors"`
+	Archetype     string    `json:"archetype"`
+	Matches       int64     `json:"matches"`
+	Share         float64   `json:"share"`
+	PreviousShare *float64  `json:"previousShare"`
+	ShareDelta    *float64  `json:"shareDelta"`
+	Record        RecordAgg `json:"record"`
+}
+
+// DraftStatsResponse is draft pick analytics per set, most recently drafted
+// set first.
+type DraftStatsResponse struct {
+	Sets []DraftSetAnalytics `json:"sets"`
+}
+
+// DraftSetAnalytics is how one set was drafted. First picks are pack 1,
+// pick 1, by rarity and color. A wheel chance is a card the player passed
+// in the first lap of a pack whose return, eight picks later, was recorded;
+// it wheeled if it was still there. Strong cards are rated at or above
+// StrongRating, the top quarter of the set's imported GIH WRs. The rating
+// fields compare each pick with the best-rated card in its pack; they and
+// the strong-card fields are empty without ratings for the set.
+type DraftSetAnalytics struct {
+	SetCode            string           `json:"setCode"`
+	Drafts             int64            `json:"drafts"`
+	LastDraftedAt      string           `json:"lastDraftedAt"`
+	RatingsImported    bool             `json:"ratingsImported"`
+	FirstPicks         int64            `json:"firstPicks"`
+	FirstPickRarities  []DraftPickShare `json:"firstPickRarities"`
+	FirstPickColors    []DraftPickShare `json:"firstPickColors"`
+	WheelChances       int64            `json:"wheelChances"`
+	Wheeled            int64            `json:"wheeled"`
+	WheelRate          *float64         `json:"wheelRate"`
+	StrongRating       *float64         `json:"strongRating"`
+	StrongWheelChances int64            `json:"strongWheelChances"`
+	StrongWheeled      int64            `json:"strongWheeled"`
+	StrongWheelRate    *float64         `json:"strongWheelRate"`
+	WheeledStrongCards []DraftWheelCard `json:"wheeledStrongCards"`
+	RatedPicks         int64            `json:"ratedPicks"`
+	TopRatedPicks      int64            `json:"topRatedPicks"`
+	TopRatedPickRate   *float64         `json:"topRatedPickRate"`
+	AverageRatingDelta *float64         `json:"averageRatingDelta"`
+}
+
+// DraftPickShare counts first picks with one rarity or color; Share is the
+// fraction of the set's first picks.
+type DraftPickShare struct {
+	Key   string  `json:"key"`
+	Count int64   `json:"count"`
+	Share float64 `json:"share"`
+}
+
+// DraftWheelCard is a strong card that came back to the player: how often
+// it was passed in a first lap and how often it wheeled.
+type DraftWheelCard struct {
+	CardID   int64    `json:"cardId"`
+	CardName string   `json:"cardName,omitempty"`
+	Rating   *float64 `json:"rating,omitempty"`
+	Passed   int64    `json:"passed"`
+	Wheeled  int64    `json:"wheeled"`
+}
+
+// DeckCardDrawStats compares a deck's game win rate with and without each
+// card in hand. Games counts the decided games with derived card facts;
+// Cards lists those with at least MinGames games on both sides first, the
+// cards that most lower the win rate when drawn leading.
+type DeckCardDrawStats struct {
+	DeckID        int64                `json:"deckId"`
+	DeckVersionID *int64               `json:"deckVersionId,omitempty"`
+	MinGames      int64                `json:"minGames"`
+	Games         RecordAgg            `json:"games"`
+	Cards         []DeckCardDrawImpact `json:"cards"`
+}
+
+// DeckCardDrawImpact is one card's record when drawn (kept in the opening
+// hand or drawn later) and when not. WinRateDelta is drawn minus not drawn,
+// set only once both sides reach the sample threshold.
+type DeckCardDrawImpact struct {
+	CardID       int64           `json:"cardId"`
+	CardName     string          `json:"cardName,omitempty"`
+	Drawn        WinRateInterval `json:"drawn"`
+	NotDrawn     WinRateInterval `json:"notDrawn"`
+	WinRateDelta *float64        `json:"winRateDelta,omitempty"`
+	Qualified    bool            `json:"qualified"`
+}
+
+// ManaStats quantifies mana screw and flood, overall and per deck, busiest
+// first. A game is screwed once it has ScrewMissedDrops missed land drops;
+// stuck spells are counted at the end of the player's StuckSpellTurn-th turn.
+type ManaStats struct {
+	StuckSpellTurn   int64       `json:"stuckSpellTurn"`
+	ScrewMissedDrops int64       `json:"screwMissedDrops"`
+	Overall          ManaGroup   `json:"overall"`
+	Decks            []ManaGroup `json:"decks"`
+}
+
+// ManaGroup averages per-game mana facts. Each average covers only the games
+// where its fact was observable: OpeningHandGames for opening lands,
+// TurnStatGames for missed land drops and the screwed split, and
+// StuckSpellGames for spells stuck in hand.
+type ManaGroup struct {
+	DeckID              int64     `json:"deckId,omitempty"`
+	DeckName            string    `json:"deckName,omitempty"`
+	Games               int64     `json:"games"`
+	OpeningHandGames    int64     `json:"openingHandGames"`
+	AverageOpeningLands *float64  `json:"averageOpeningLands"`
+	TurnStatGames       int64     `json:"turnStatGames"`
+	AverageMissedDrops  *float64  `json:"averageMissedDrops"`
+	Screwed             RecordAgg `json:"screwed"`
+	NotScrewed          RecordAgg `json:"notScrewed"`
+	StuckSpellGames     int64     `json:"stuckSpellGames"`
+	AverageStuckSpells  *float64  `json:"averageStuckSpells"`
+}
+
+// Bo3Stats compares best-of-three match and game win rates, over every such
+// match and per deck. MatchesWithoutGames counts matches with no game of
+// known result, which only enter the match record.
+type Bo3Stats struct {
+	Overall             Bo3Split       `json:"overall"`
+	Decks               []DeckBo3Split `json:"decks"`
+	MatchesWithoutGames int64          `json:"matchesWithoutGames"`
+}
+
+// Bo3Split is a match record, the record of those matches' games, and the
+// games split into game one and post-board games. PostBoardDelta is the
+// post-board game win rate minus game one's, nil unless both have games.
+type Bo3Split struct {
+	Matches        WinRateInterval `json:"matches"`
+	Games          WinRateInterval `json:"games"`
+	GameOne        WinRateInterval `json:"gameOne"`
+	PostBoard      WinRateInterval `json:"postBoard"`
+	PostBoardDelta *float64        `json:"postBoardDelta,omitempty"`
+}
+
+// DeckBo3Split is one deck's Bo3Split.
+type DeckBo3Split struct {
+	DeckID   int64  `json:"deckId"`
+	DeckName string `json:"deckName"`
+	Format   string `json:"format"`
+	Bo3Split
+}
+
+// CompanionStats is the player's match record by companion and by Brawl
+// commander: those in their own decks, and those they faced.
+type CompanionStats struct {
+	Companions         []CompanionRecord `json:"companions"`
+	Commanders         []CompanionRecord `json:"commanders"`
+	OpponentCompanions []CompanionRecord `json:"opponentCompanions"`
+	OpponentCommanders []CompanionRecord `json:"opponentCommanders"`
+}
+
+// CompanionRecord is the match record with or against one companion or
+// commander.
+type CompanionRecord struct {
+	CardID   int64           `json:"cardId"`
+	CardName string          `json:"cardName,omitempty"`
+	Rate     WinRateInterval `json:"rate"`
+}
+
+// LimitedRun is one draft or sealed event run and its record. TrophyWins is
+// the win count that earns a trophy in this event; the record is final once
+// Finished is set.
+type LimitedRun struct {
+	EventName        string `json:"eventName"`
+	EventDisplayName string `json:"eventDisplayName,omitempty"`
+	SetCode          string `json:"setCode,omitempty"`
+	Kind             string `json:"kind"`
+	Status           string `json:"status"`
+	StartedAt        string `json:"startedAt,omitempty"`
+	EndedAt          string `json:"endedAt,omitempty"`
+	Wins             int64  `json:"wins"`
+	Losses           int64  `json:"losses"`
+	TrophyWins       int64  `json:"trophyWins"`
+	Trophy           bool   `json:"trophy"`
+	Finished         bool   `json:"finished"`
+}
+
+// TrophySummary counts finished limited runs and their trophies; the
+// averages and rate are nil without one. ActiveRuns are still in progress.
+type TrophySummary struct {
+	Runs         int64    `json:"runs"`
+	ActiveRuns   int64    `json:"activeRuns"`
+	Trophies     int64    `json:"trophies"`
+	TrophyRate   *float64 `json:"trophyRate"`
+	AverageWins  *float64 `json:"averageWins"`
+	LastTrophyAt string   `json:"lastTrophyAt,omitempty"`
+}
+
+// TrophyGroup is the TrophySummary of one set and event kind.
+type TrophyGroup struct {
+	SetCode   string `json:"setCode"`
+	Kind      string `json:"kind"`
+	KindLabel string `json:"kindLabel"`
+	TrophySummary
+}
+
+// TrophyStats is every limited run, newest first, summarized overall and
+// per set and event kind.
+type TrophyStats struct {
+	Summary TrophySummary `json:"summary"`
+	Groups  []TrophyGroup `json:"groups"`
+	Runs    []LimitedRun  `json:"runs"`
+}
+
+// OpponentRankStats weighs match results by the opponent's rank, overall
+// and per deck. Tiers is the scale, bottom up; MatchesWithoutRank counts
+// decided matches whose opponent rank was never announced.
+type OpponentRankStats struct {
+	Tiers              []string            `json:"tiers"`
+	Overall            OpponentRankGroup   `json:"overall"`
+	Decks              []OpponentRankGroup `json:"decks"`
+	MatchesWithoutRank int64               `json:"matchesWithoutRank"`
+}
+
+// OpponentRankGroup is one deck's (or every deck's) record against ranked
+// opponents. AverageOpponentTier is on the Tiers scale, with levels as
+// quarters of a tier; WeightedWinRate counts wins by the opponent's tier
+// and losses by the mirrored tier.
+type OpponentRankGroup struct {
+	DeckID               int64                `json:"deckId,omitempty"`
+	DeckName             string               `json:"deckName,omitempty"`
+	Format               string               `json:"format,omitempty"`
+	Matches              WinRateInterval      `json:"matches"`
+	AverageOpponentTier  *float64             `json:"averageOpponentTier"`
+	AverageOpponentLabel string               `json:"averageOpponentLabel,omitempty"`
+	WeightedWinRate      *float64             `json:"weightedWinRate"`
+	ByTier               []OpponentTierRecord `json:"byTier"`
+}
+
+// OpponentTierRecord is the record against opponents of one tier.
+type OpponentTierRecord struct {
+	Tier   string    `json:"tier"`
+	Record RecordAgg `json:"record"`
+}
+
+// DeckCastCurve sets a deck's mana curve against the turns its spells were
+// actually cast. Turns are the player's own (the game's round), so a card
+// is on curve when cast by the turn matching its mana value. Games counts
+// the deck's games with any recorded cast; Cards lists cards cast late on
+// average (at least MinCasts casts) first.
+type DeckCastCurve struct {
+	DeckID           int64                 `json:"deckId"`
+	DeckVersionID    *int64                `json:"deckVersionId,omitempty"`
+	MinCasts         int64                 `json:"minCasts"`
+	Games            int64                 `json:"games"`
+	Curve            []DeckCastCurveBucket `json:"curve"`
+	UnknownManaValue int64                 `json:"unknownManaValue"`
+	Cards            []DeckCardCastTurns   `json:"cards"`
+}
+
+// DeckCastCurveBucket compares one mana value's nonland copies in the deck
+// with the casts of those cards and with every cast made on that turn. The
+// last bucket folds in costlier cards and later turns.
+type DeckCastCurveBucket struct {
+	ManaValue       int64    `json:"manaValue"`
+	Label           string   `json:"label"`
+	Cards           int64    `json:"cards"`
+	Casts           int64    `json:"casts"`
+	AverageCastTurn *float64 `json:"averageCastTurn,omitempty"`
+	CastsOnTurn     int64    `json:"castsOnTurn"`
+}
+
+// DeckCardCastTurns is one card's casts by turn; CastTurns[i] counts casts on
+// turn i+1. AverageDelay is turns cast after its mana value (a zero-cost
+// card is on curve on turn one); Late marks a card that averages at least a
+// turn behind over MinCasts casts or more.
+type DeckCardCastTurns struct {
+	CardID          int64    `json:"cardId"`
+	CardName        string   `json:"cardName,omitempty"`
+	ManaValue       *float64 `json:"manaValue,omitempty"`
+	Copies          int64    `json:"copies"`
+	Casts           int64    `json:"casts"`
+	CastTurns       []int64  `json:"castTurns"`
+	AverageCastTurn *float64 `json:"averageCastTurn,omitempty"`
+	OnCurveCasts    int64    `json:"onCurveCasts"`
+	AverageDelay    *float64 `json:"averageDelay,omitempty"`
+	Late            bool     `json:"late"`
+}
@@ -0,0 +1,22 @@
+//go:build webdist
+
+// Package web embeds the built frontend (web/dist) so the desktop app and
+// `ponder serve` can run as a single binary. The embed is behind the webdist
+// build tag, which wails.json sets for desktop builds: run `bun run build` in
+// web/ first, then `go build -tags webdist`. Without the tag the binary
+// carries a placeholder page instead, so the Go code builds from a checkout
+// that has no web/dist.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:dist
+var dist embed.FS
+
+// Dist returns the embedded build rooted at web/dist.
+func Dist() (fs.FS, error) {
+	return fs.Sub(dist, "dist")
+}
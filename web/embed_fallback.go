@@ -0,0 +1,17 @@
+//go:build !webdist
+
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed fallback
+var fallback embed.FS
+
+// Dist returns the placeholder page served by binaries built without the
+// webdist tag; it explains how to build one with the frontend.
+func Dist() (fs.FS, error) {
+	return fs.Sub(fallback, "fallback")
+}
@@ -369,6 +369,24 @@ function DeckGameShapeSection({
             <span>life</span>
           </dd>
         </div>
+        <div className="deck-analytics-tile">
+          <dt>Spells per game</dt>
+          <dd>
+            <strong>{shape.avgSpellsCast != null ? shape.avgSpellsCast.toFixed(1) : "—"}</strong>
+            <span>
+              vs {shape.avgOpponentSpellsCast != null ? shape.avgOpponentSpellsCast.toFixed(1) : "—"} opp
+            </span>
+          </dd>
+        </div>
+        <div className="deck-analytics-tile">
+          <dt>Attackers per game</dt>
+          <dd>
+            <strong>{shape.avgAttackers != null ? shape.avgAttackers.toFixed(1) : "—"}</strong>
+            <span>
+              vs {shape.avgOpponentAttackers != null ? shape.avgOpponentAttackers.toFixed(1) : "—"} opp
+            </span>
+          </dd>
+        </div>
         <RecordTile
           label="Kept every land drop"
           record={shape.cleanDropGames}
@@ -1,35 +1,100 @@
 import type {
   AiStatus,
   AutostartStatus,
+  BackupResult,
+  Bo3Stats,
+  CardInfo,
+  CollectionInsights,
+  CollectionInsightsView,
+  CollectionValueHistory,
+  CompanionStats,
   DeckAnalytics,
   DeckAnalyticsGameRef,
   DeckAnalyticsGamesParams,
+  DeckCardDrawStats,
+  DeckCastCurve,
   DeckDetail,
   DeckPrimer,
   DeckSummary,
   DraftPick,
+  DraftPickSuggestions,
   DraftSession,
+  DraftStatsResponse,
   EconomyHistory,
+  EconomyStats,
+  HealthStatus,
   Match,
-  MatchCardPlay,
   MatchDetail,
   MatchReplayFrame,
+  MatchTimeline,
   DeckMatchupsResponse,
+  DeckMergeResult,
+  DeckPushResult,
+  GameLengthStats,
+  LimitedColorStats,
   LimitedMatchupsResponse,
+  LimitedStatsResponse,
+  ManaStats,
+  MetaReport,
+  MilestoneStats,
+  OpponentRankStats,
   Overview,
+  OverviewScope,
+  PlayDrawStats,
+  PlaySessionSummary,
+  QueueTimeStats,
   RankHistoryPoint,
+  RankHistorySeries,
   RuntimeConfig,
+  Settings,
   RuntimeOperation,
   LiveMatch,
   RuntimeStatus,
   SetInfo,
+  TimeOfDayStats,
+  TrophyStats,
   UpdateCheck,
 } from "./types";
+import { BASE_PATH } from "./basePath";
 
-const API_BASE = import.meta.env.VITE_API_BASE ?? "";
+const API_BASE = import.meta.env.VITE_API_BASE ?? BASE_PATH;
+const API_KEY_STORAGE_KEY = "ponder.apiKey";
+
+/**
+ * Bearer token for servers started with -api-key: the key the user entered,
+ * saved in localStorage under "ponder.apiKey". It is never built into the
+ * bundle, which the server hands out without a key.
+ */
+function apiKey(): string {
+  try {
+    return window.localStorage.getItem(API_KEY_STORAGE_KEY) ?? "";
+  } catch {
+    return "";
+  }
+}
+
+/** Saves the key sent with every API request; an empty key clears it. */
+export function saveAPIKey(key: string): void {
+  try {
+    if (key) {
+      window.localStorage.setItem(API_KEY_STORAGE_KEY, key);
+    } else {
+      window.localStorage.removeItem(API_KEY_STORAGE_KEY);
+    }
+  } catch {
+    // Storage unavailable (private mode); requests go without a key.
+  }
+}
+
+function apiFetch(path: string, init: RequestInit = {}): Promise<Response> {
+  const headers = new Headers(init.headers);
+  const key = apiKey();
+  if (key) headers.set("Authorization", `Bearer ${key}`);
+  return fetch(`${API_BASE}${path}`, { ...init, headers });
+}
 
 async function getJSON<T>(path: string): Promise<T> {
-  const res = await fetch(`${API_BASE}${path}`);
+  const res = await apiFetch(path);
   if (!res.ok) {
     const text = await res.text();
     throw new Error(`Request failed (${res.status}): ${text}`);
@@ -37,8 +102,17 @@ async function getJSON<T>(path: string): Promise<T> {
   return (await res.json()) as T;
 }
 
+async function getText(path: string): Promise<string> {
+  const res = await apiFetch(path);
+  if (!res.ok) {
+    const text = await res.text();
+    throw new Error(`Request failed (${res.status}): ${text}`);
+  }
+  return res.text();
+}
+
 async function postJSON<T>(path: string, body?: unknown): Promise<T> {
-  const res = await fetch(`${API_BASE}${path}`, {
+  const res = await apiFetch(path, {
     method: "POST",
     headers: {
       "Content-Type": "application/json",
@@ -52,13 +126,58 @@ async function postJSON<T>(path: string, body?: unknown): Promise<T> {
   return (await res.json()) as T;
 }
 
+async function putJSON<T>(path: string, body: unknown): Promise<T> {
+  const res = await apiFetch(path, {
+    method: "PUT",
+    headers: {
+      "Content-Type": "application/json",
+    },
+    body: JSON.stringify(body),
+  });
+  if (!res.ok) {
+    const text = await res.text();
+    throw new Error(`Request failed (${res.status}): ${text}`);
+  }
+  return (await res.json()) as T;
+}
+
+async function deleteJSON<T>(path: string): Promise<T> {
+  const res = await apiFetch(path, { method: "DELETE" });
+  if (!res.ok) {
+    const text = await res.text();
+    throw new Error(`Request failed (${res.status}): ${text}`);
+  }
+  return (await res.json()) as T;
+}
+
 export const api = {
-  overview: () => getJSON<Overview>("/api/overview"),
+  health: () => getJSON<HealthStatus>("/api/health"),
+  overview: (scope: OverviewScope = {}) => {
+    const search = new URLSearchParams();
+    if (scope.from) search.set("from", scope.from);
+    if (scope.to) search.set("to", scope.to);
+    if (scope.format) search.set("format", scope.format);
+    if (scope.queue) search.set("queue", scope.queue);
+    const query = search.toString();
+    return getJSON<Overview>(query ? `/api/overview?${query}` : "/api/overview");
+  },
   rankHistory: () => getJSON<RankHistoryPoint[]>("/api/rank-history"),
+  rankHistorySeries: () => getJSON<RankHistorySeries>("/api/rank/history"),
+  queueTimes: () => getJSON<QueueTimeStats>("/api/queue-times"),
+  sessions: () => getJSON<PlaySessionSummary[]>("/api/sessions"),
+  settings: () => getJSON<Settings>("/api/settings"),
+  updateSettings: (settings: Partial<Settings>) => putJSON<Settings>("/api/settings", settings),
   economy: () => getJSON<EconomyHistory>("/api/economy"),
-  matches: (limit = 500) => getJSON<Match[]>(`/api/matches?limit=${limit}`),
+  collectionInsights: (view: CollectionInsightsView = "all", since?: string) => {
+    const params = new URLSearchParams({ view });
+    if (since) params.set("since", since);
+    return getJSON<CollectionInsights>(`/api/collection/insights?${params.toString()}`);
+  },
+  collectionValue: () => getJSON<CollectionValueHistory>("/api/collection/value"),
+  matches: (limit = 500, includeArchived = false) =>
+    getJSON<Match[]>(`/api/matches?limit=${limit}${includeArchived ? "&includeArchived=true" : ""}`),
   matchDetail: (matchId: number) => getJSON<MatchDetail>(`/api/matches/${matchId}`),
-  matchTimeline: (matchId: number) => getJSON<MatchCardPlay[]>(`/api/matches/${matchId}/timeline`),
+  matchTimeline: (matchId: number) => getJSON<MatchTimeline>(`/api/matches/${matchId}/timeline`),
   matchReplay: (matchId: number) => getJSON<MatchReplayFrame[]>(`/api/matches/${matchId}/replay`),
   decks: (scope: "constructed" | "draft" | "all" = "constructed") =>
     getJSON<DeckSummary[]>(scope === "constructed" ? "/api/decks" : `/api/decks?scope=${scope}`),
@@ -83,12 +202,62 @@ export const api = {
       query ? `/api/decks/${deckId}/analytics/games?${query}` : `/api/decks/${deckId}/analytics/games`,
     );
   },
+  deckCardDrawImpact: (deckId: number, params: { version?: number; minGames?: number } = {}) => {
+    const search = new URLSearchParams();
+    if (params.version) search.set("version", String(params.version));
+    if (params.minGames) search.set("minGames", String(params.minGames));
+    const query = search.toString();
+    return getJSON<DeckCardDrawStats>(
+      query ? `/api/decks/${deckId}/analytics/cards?${query}` : `/api/decks/${deckId}/analytics/cards`,
+    );
+  },
+  deckCastCurve: (deckId: number, params: { version?: number; minCasts?: number } = {}) => {
+    const search = new URLSearchParams();
+    if (params.version) search.set("version", String(params.version));
+    if (params.minCasts) search.set("minCasts", String(params.minCasts));
+    const query = search.toString();
+    return getJSON<DeckCastCurve>(
+      query ? `/api/decks/${deckId}/analytics/curve?${query}` : `/api/decks/${deckId}/analytics/curve`,
+    );
+  },
+  deleteDeck: (deckId: number) => deleteJSON<{ status: string; deckId: number }>(`/api/decks/${deckId}`),
+  mergeDecks: (targetId: number, sourceIds: number[]) =>
+    postJSON<DeckMergeResult>("/api/decks/merge", { targetId, sourceIds }),
+  deckDecklist: (deckId: number) => getText(`/api/decks/${deckId}/decklist`),
+  pushDeck: (deckId: number, service: "moxfield" | "archidekt") =>
+    postJSON<DeckPushResult>(`/api/decks/${deckId}/push`, { service }),
+  opponentDecklist: (matchId: number) => getText(`/api/matches/${matchId}/opponent-decklist`),
   deckMatchups: (deckId: number) => getJSON<DeckMatchupsResponse>(`/api/decks/${deckId}/matchups`),
   limitedMatchups: () => getJSON<LimitedMatchupsResponse>("/api/limited/matchups"),
+  limitedStats: () => getJSON<LimitedStatsResponse>("/api/stats/limited"),
+  limitedColors: (setCode: string) =>
+    getJSON<LimitedColorStats>(`/api/stats/limited/colors?set=${encodeURIComponent(setCode)}`),
+  playDrawStats: () => getJSON<PlayDrawStats>("/api/stats/playdraw"),
+  milestones: () => getJSON<MilestoneStats>("/api/stats/milestones"),
+  economyStats: () => getJSON<EconomyStats>("/api/stats/economy"),
+  meta: (weeks?: number) => getJSON<MetaReport>(`/api/stats/meta${weeks ? `?weeks=${weeks}` : ""}`),
+  draftStats: () => getJSON<DraftStatsResponse>("/api/stats/drafts"),
+  manaStats: () => getJSON<ManaStats>("/api/stats/mana"),
+  bo3Stats: () => getJSON<Bo3Stats>("/api/stats/bo3"),
+  companionStats: () => getJSON<CompanionStats>("/api/stats/companions"),
+  trophies: () => getJSON<TrophyStats>("/api/stats/trophies"),
+  opponentRanks: () => getJSON<OpponentRankStats>("/api/stats/opponent-ranks"),
+  gameLengthStats: () => getJSON<GameLengthStats>("/api/stats/game-length"),
+  timeOfDayStats: (tz?: string) =>
+    getJSON<TimeOfDayStats>(`/api/stats/time-of-day${tz ? `?tz=${encodeURIComponent(tz)}` : ""}`),
+  deleteMatch: (matchId: number) => deleteJSON<{ status: string; matchId: number }>(`/api/matches/${matchId}`),
+  archiveMatch: (matchId: number) => postJSON<{ status: string; matchId: number }>(`/api/matches/${matchId}/archive`),
+  unarchiveMatch: (matchId: number) => postJSON<{ status: string; matchId: number }>(`/api/matches/${matchId}/unarchive`),
   setOpponentArchetype: (matchId: number, archetype: string) =>
     postJSON<{ status: string; archetype: string }>(`/api/matches/${matchId}/opponent-archetype`, { archetype }),
   drafts: () => getJSON<DraftSession[]>("/api/drafts"),
   draftPicks: (draftId: number) => getJSON<DraftPick[]>(`/api/drafts/${draftId}/picks`),
+  draftSuggestions: (draftId: number, pick?: { pack: number; pick: number }) =>
+    getJSON<DraftPickSuggestions>(
+      `/api/drafts/${draftId}/suggestions${pick ? `?pack=${pick.pack}&pick=${pick.pick}` : ''}`,
+    ),
+  cards: (cardIds: number[]) =>
+    getJSON<Record<string, CardInfo>>(`/api/cards?ids=${cardIds.join(",")}`),
   sets: (codes: string[]) =>
     getJSON<Record<string, SetInfo>>(`/api/sets?codes=${encodeURIComponent(codes.join(","))}`),
   live: () => getJSON<{ live: LiveMatch | null }>("/api/live"),
@@ -102,9 +271,10 @@ export const api = {
   checkForUpdate: () => getJSON<UpdateCheck>("/api/runtime/update-check"),
   pickLogFile: () => postJSON<{ path: string }>("/api/runtime/pick-log"),
   revealPath: (path: string) => postJSON<{ status: string }>("/api/runtime/reveal", { path }),
+  backupNow: () => postJSON<BackupResult>("/api/admin/backup"),
   aiStatus: () => getJSON<AiStatus>("/api/ai/status"),
   deckPrimer: async (deckId: number): Promise<DeckPrimer | null> => {
-    const res = await fetch(`${API_BASE}/api/decks/${deckId}/primer`);
+    const res = await apiFetch(`/api/decks/${deckId}/primer`);
     if (res.status === 404) {
       return null;
     }
@@ -134,7 +304,7 @@ export async function generateDeckPrimer(
 ): Promise<void> {
   let res: Response;
   try {
-    res = await fetch(`${API_BASE}/api/decks/${deckId}/primer`, {
+    res = await apiFetch(`/api/decks/${deckId}/primer`, {
       method: "POST",
       headers: { Accept: "text/event-stream" },
       signal,
@@ -206,3 +376,31 @@ export async function generateDeckPrimer(
     }
   }
 }
+
+type GraphQLResponse<T> = {
+  data?: T;
+  errors?: { message: string; path?: (string | number)[] }[];
+};
+
+/**
+ * Runs a read-only query against /api/graphql. Field errors null only the
+ * failing field, so partial data is returned unless nothing resolved.
+ */
+export async function graphql<T>(query: string, variables?: Record<string, unknown>): Promise<T> {
+  const out = await postJSON<GraphQLResponse<T>>("/api/graphql", { query, variables });
+  if (out.data == null) {
+    throw new Error(out.errors?.map((err) => err.message).join("; ") || "GraphQL request failed");
+  }
+  return out.data;
+}
+
+export type CardImageVersion = "small" | "normal" | "large" | "png" | "art_crop" | "border_crop";
+
+/**
+ * URL for an <img> of a card by Arena id. The server proxies Scryfall and
+ * caches each image on disk, and serves it without the API key.
+ */
+export function cardImageUrl(cardId: number, version: CardImageVersion = "normal"): string {
+  const query = version === "normal" ? "" : `?version=${version}`;
+  return `${API_BASE}/api/cards/${cardId}/image${query}`;
+}
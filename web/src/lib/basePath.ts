@@ -0,0 +1,5 @@
+/**
+ * Path prefix the app is served under: "" at the root, or e.g. "/mtga" when
+ * the backend runs with -base-path (it rewrites index.html's <base href>).
+ */
+export const BASE_PATH = new URL(document.baseURI).pathname.replace(/\/+$/, "");
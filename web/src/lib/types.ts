@@ -20,6 +20,7 @@ export type Match = {
   id: number;
   arenaMatchId: string;
   eventName: string;
+  eventDisplayName?: string;
   bestOf?: "bo1" | "bo3" | "";
   playDraw?: "play" | "draw" | "";
   opponent: string;
@@ -29,6 +30,9 @@ export type Match = {
   winReason: string;
   turnCount?: number | null;
   secondsCount?: number | null;
+  queueSeconds?: number | null;
+  archived: boolean;
+  source: string;
   deckId?: number | null;
   deckName?: string | null;
   deckVersionId?: number | null;
@@ -59,6 +63,57 @@ export type MatchCardPlay = {
   playedAt?: string;
 };
 
+export type MatchTimelineCardRef = {
+  instanceId: number;
+  cardId: number;
+  cardName?: string;
+  playerSide: "self" | "opponent" | "unknown";
+};
+
+export type MatchTimelineLifeChange = {
+  playerSide: "self" | "opponent";
+  from: number;
+  to: number;
+  delta: number;
+  phase?: string;
+  gameStateId?: number;
+  recordedAt?: string;
+};
+
+export type MatchTimelineAnnotation = {
+  type: string;
+  category?: string;
+  amount?: number;
+  phase?: string;
+  gameStateId?: number;
+  recordedAt?: string;
+  source?: MatchTimelineCardRef;
+  targets?: MatchTimelineCardRef[];
+  targetPlayers?: ("self" | "opponent")[];
+};
+
+export type MatchTimelineTurn = {
+  turnNumber: number;
+  startedAt?: string;
+  selfLifeTotal?: number;
+  opponentLifeTotal?: number;
+  plays: MatchCardPlay[];
+  lifeChanges: MatchTimelineLifeChange[];
+  annotations: MatchTimelineAnnotation[];
+};
+
+export type MatchTimelineGame = {
+  gameNumber: number;
+  winningPlayerSide?: string;
+  winReason?: string;
+  turns: MatchTimelineTurn[];
+};
+
+export type MatchTimeline = {
+  matchId: number;
+  games: MatchTimelineGame[];
+};
+
 export type MatchReplayChange = {
   instanceId: number;
   cardId: number;
@@ -163,6 +218,10 @@ export type GameAnalytics = {
   keptHandSize?: number;
   minSelfLife?: number;
   minOpponentLife?: number;
+  selfSpellsCast?: number;
+  opponentSpellsCast?: number;
+  selfAttackersDeclared?: number;
+  opponentAttackersDeclared?: number;
   resultSource?: string;
   resultConfidence: "exact" | "derived" | "unknown";
   playDrawSource?: string;
@@ -185,6 +244,7 @@ export type GameTurnStat = {
   landsPlayed: number;
   spellsCast: number;
   landInHand?: boolean;
+  spellsInHand?: number;
 };
 
 // A descriptive decision-review flag; always a heuristic prompt for replay
@@ -217,6 +277,14 @@ export type Overview = {
   losses: number;
   winRate: number;
   recent: Match[];
+  limited: TrophySummary;
+};
+
+export type OverviewScope = {
+  from?: string;
+  to?: string;
+  format?: string;
+  queue?: string;
 };
 
 export type WildcardBalance = {
@@ -301,10 +369,69 @@ export type RankState = {
   matchesLost?: number | null;
 };
 
+export type IngestLogStatus = {
+  path: string;
+  offset: number;
+  lineNo: number;
+  fileSize: number;
+  bytesBehind: number;
+  updatedAt: string;
+};
+
+export type HealthStatus = {
+  status: "ok" | "unavailable";
+  database: boolean;
+  databaseError?: string;
+  schemaVersion: number;
+  migrationVersion: number;
+  lastIngestAt?: string;
+  logs: IngestLogStatus[];
+};
+
+export type QueueTimeBucket = {
+  key: string;
+  eventType?: string;
+  hour?: number;
+  matches: number;
+  avgSeconds: number;
+  medianSeconds: number;
+};
+
+export type QueueTimeStats = {
+  matches: number;
+  byEvent: QueueTimeBucket[];
+  byHour: QueueTimeBucket[];
+};
+
+export type CollectionCardSource = "deck" | "draft" | "game" | "grant";
+
+export type CollectionCardInsight = {
+  cardId: number;
+  cardName?: string;
+  firstSeenAt: string;
+  firstSeenSource: CollectionCardSource;
+  lastSeenAt: string;
+  sources: CollectionCardSource[];
+  timesPlayed: number;
+  lastPlayedAt?: string;
+};
+
+export type CollectionInsightsView = "all" | "never-played" | "new";
+
+export type CollectionInsights = {
+  view: CollectionInsightsView;
+  since: string;
+  totalCards: number;
+  neverPlayedCount: number;
+  newCount: number;
+  cards: CollectionCardInsight[];
+};
+
 export type RankHistoryPoint = {
   matchId: number;
   arenaMatchId: string;
   eventName: string;
+  eventDisplayName?: string;
   opponent: string;
   result: "win" | "loss" | "unknown";
   observedAt: string;
@@ -317,11 +444,51 @@ export type RankHistoryPoint = {
   limited: RankState;
 };
 
+export type RankSeriesPoint = {
+  matchId: number;
+  eventName: string;
+  eventDisplayName?: string;
+  result: RankHistoryPoint["result"];
+  observedAt: string;
+  seasonOrdinal: number;
+  seasonStart: boolean;
+  rankClass: string;
+  level: number | null;
+  step: number | null;
+  label: string;
+  score: number | null;
+  matchesWon: number | null;
+  matchesLost: number | null;
+};
+
+export type RankSeason = {
+  seasonOrdinal: number;
+  startIndex: number;
+  endIndex: number;
+  startedAt: string;
+  endedAt: string;
+  finalLabel: string;
+  matchesWon: number | null;
+  matchesLost: number | null;
+};
+
+export type RankSeries = {
+  format: "constructed" | "limited";
+  tiers: string[];
+  points: RankSeriesPoint[];
+  seasons: RankSeason[];
+};
+
+export type RankHistorySeries = {
+  series: RankSeries[];
+};
+
 export type DeckSummary = {
   deckId: number;
   deckName: string;
   format: string;
   eventName: string;
+  eventDisplayName?: string;
   matches: number;
   wins: number;
   losses: number;
@@ -343,11 +510,55 @@ export type DeckDetail = {
   name: string;
   format: string;
   eventName: string;
+  eventDisplayName?: string;
   cards: DeckCard[];
+  composition?: DeckComposition;
+  paperValue?: DeckPaperValue;
   matches: Match[] | null;
   versions: DeckVersion[];
 };
 
+export type DeckPaperValue = {
+  usd: number;
+  eur: number;
+  unpricedCards: number;
+};
+
+export type CollectionValuePoint = {
+  week: string;
+  usd: number;
+  eur: number;
+  pricedCards: number;
+};
+
+export type CollectionValueHistory = {
+  cards: number;
+  points: CollectionValuePoint[];
+};
+
+export type DeckCurveBucket = {
+  manaValue: number;
+  label: string;
+  cards: number;
+  creatures: number;
+};
+
+export type DeckComposition = {
+  cards: number;
+  lands: number;
+  avgManaValue?: number;
+  curve: DeckCurveBucket[];
+  unknownManaValue: number;
+  colors: Array<{ color: string; cards: number }>;
+  types: Array<{ type: string; cards: number }>;
+};
+
+export type DeckMergeResult = {
+  targetId: number;
+  mergedDecks: number;
+  movedMatches: number;
+};
+
 export type DeckVersion = {
   id: number;
   versionNumber: number;
@@ -428,6 +639,10 @@ export type DeckGameShape = {
   missedDropGames: RecordAgg;
   cleanDropGames: RecordAgg;
   missedDropUnknownGames: number;
+  avgSpellsCast?: number;
+  avgOpponentSpellsCast?: number;
+  avgAttackers?: number;
+  avgOpponentAttackers?: number;
   turnCurve: DeckTurnCurvePoint[];
 };
 
@@ -563,6 +778,97 @@ export type LimitedMatchupsResponse = {
   sets: LimitedMatchupSet[];
 };
 
+export type LimitedColorPairStats = {
+  colorsKey: string;
+  colors: string[];
+  colorsKnown: boolean;
+  runs: number;
+  trophies: number;
+  wins: number;
+  losses: number;
+  averageWins: number;
+  winRate: number;
+};
+
+export type LimitedFirstPick = {
+  cardId: number;
+  cardName: string;
+  colors: string[];
+  count: number;
+  wins: number;
+  losses: number;
+};
+
+export type LimitedFirstPickColor = {
+  color: string;
+  count: number;
+};
+
+export type LimitedSetStats = {
+  setCode: string;
+  drafts: number;
+  runs: number;
+  trophies: number;
+  wins: number;
+  losses: number;
+  averageWins: number;
+  winRate: number;
+  lastDraftedAt: string;
+  colorPairs: LimitedColorPairStats[];
+  firstPicks: LimitedFirstPick[];
+  firstPickColors: LimitedFirstPickColor[];
+};
+
+export type LimitedStatsResponse = {
+  sets: LimitedSetStats[];
+};
+
+export type LimitedColorRecord = {
+  key: string;
+  colors: string[];
+  splash: string[];
+  runs: number;
+  trophies: number;
+  wins: number;
+  losses: number;
+  averageWins: number;
+  winRate: number;
+};
+
+export type LimitedColorStats = {
+  setCode: string;
+  runs: number;
+  unknownRuns: number;
+  colorPairs: LimitedColorRecord[];
+  splashes: LimitedColorRecord[];
+  splashed: LimitedColorRecord;
+  unsplashed: LimitedColorRecord;
+};
+
+export type WinRateInterval = {
+  record: RecordAgg;
+  winRate?: number;
+  low?: number;
+  high?: number;
+};
+
+export type PlayDrawSplit = {
+  onPlay: WinRateInterval;
+  onDraw: WinRateInterval;
+};
+
+export type DeckPlayDrawSplit = PlayDrawSplit & {
+  deckId: number;
+  deckName: string;
+  format: string;
+};
+
+export type PlayDrawStats = {
+  overall: PlayDrawSplit;
+  decks: DeckPlayDrawSplit[];
+  gamesWithoutPlayDraw: number;
+};
+
 export type AiStatus = {
   available: boolean;
   cliPath?: string;
@@ -582,6 +888,7 @@ export type DeckPrimer = {
 export type DraftSession = {
   id: number;
   eventName: string;
+  eventDisplayName?: string;
   draftId?: string | null;
   isBotDraft: boolean;
   startedAt: string;
@@ -600,11 +907,44 @@ export type DraftPick = {
   pickTs: string;
   pickedCards?: DraftPickCard[];
   packCards?: DraftPickCard[];
+  grade?: DraftPickGrade;
 };
 
 export type DraftPickCard = {
   cardId: number;
   cardName?: string;
+  rating?: number;
+};
+
+export type DraftPickGrade = {
+  pickRating?: number;
+  bestCardId: number;
+  bestCardName?: string;
+  bestRating: number;
+  delta?: number;
+};
+
+export type PickSuggestion = {
+  cardId: number;
+  cardName?: string;
+  score: number;
+  reason?: string;
+};
+
+export type DraftPickSuggestions = {
+  packNumber: number;
+  pickNumber: number;
+  suggestions: PickSuggestion[];
+};
+
+export type CardInfo = {
+  cardId: number;
+  name?: string;
+  setCode?: string;
+  collectorNumber?: string;
+  rarity?: string;
+  colors: string[];
+  manaValue?: number;
 };
 
 export type RuntimeConfig = {
@@ -613,6 +953,15 @@ export type RuntimeConfig = {
   includePrev: boolean;
   autoStartLive: boolean;
   autoCheckUpdates: boolean;
+  backupDir?: string;
+  backupKeep?: number;
+  dbPath?: string;
+};
+
+export type BackupResult = {
+  path: string;
+  sizeBytes: number;
+  completedAt: string;
 };
 
 export type RuntimeOperation = {
@@ -653,6 +1002,8 @@ export type RuntimeStatus = {
   lastError?: string;
   capabilities?: RuntimeCapabilities;
   updateCheck?: UpdateCheck;
+  backupDir: string;
+  lastBackup?: BackupResult;
 };
 
 export type RuntimeCapabilities = {
@@ -676,3 +1027,351 @@ export type UpdateCheck = {
   note?: string;
   checkedAt?: string;
 };
+
+export type Settings = {
+  defaultFormat: string;
+  lookupsEnabled: boolean;
+  retentionDays: number;
+  archetypeSignatureSource: "derived" | "manual";
+  moxfieldToken: string;
+  archidektToken: string;
+};
+
+export type DeckPushResult = {
+  service: string;
+  remoteId: string;
+  url: string;
+  created: boolean;
+  pushedAt: string;
+};
+
+export type SessionRankChange = {
+  format: string;
+  from: string;
+  to: string;
+  steps?: number;
+};
+
+export type PlaySessionSummary = {
+  startedAt: string;
+  endedAt: string;
+  durationSeconds: number;
+  matches: number;
+  wins: number;
+  losses: number;
+  draws: number;
+  winRate: number;
+  events: string[];
+  eventDisplayNames: string[];
+  rankChanges: SessionRankChange[];
+  longestLossStreak: number;
+  lossStreakStartedAt?: string;
+  endingLossStreak: number;
+  tilted: boolean;
+};
+
+export type Milestone = {
+  key: string;
+  kind: "win_streak" | "archetype_first_win" | "trophy" | "wins";
+  title: string;
+  detail?: string;
+  value?: number;
+  matchId: number;
+  eventName?: string;
+  achievedAt: string;
+};
+
+export type MilestoneStats = {
+  currentWinStreak: number;
+  bestWinStreak: number;
+  milestones: Milestone[];
+};
+
+export type EventTypeEconomy = {
+  kind: string;
+  displayName: string;
+  runs: number;
+  unsettledRuns: number;
+  wins: number;
+  losses: number;
+  averageWins: number;
+  entryGold: number;
+  entryGems: number;
+  rewardGold: number;
+  rewardGems: number;
+  rewardBoosters: number;
+  rewardCards: number;
+  netGold: number;
+  netGems: number;
+  evGold: number;
+  evGems: number;
+  evGemEquivalent: number;
+};
+
+export type EconomyStats = {
+  goldPerGem: number;
+  eventTypes: EventTypeEconomy[];
+  runs: EventRunEconomy[];
+};
+
+export type TimeSlotStats = {
+  slot: number;
+  label: string;
+  rate: WinRateInterval;
+};
+
+export type TimeOfDayStats = {
+  timeZone: string;
+  hours: TimeSlotStats[];
+  weekdays: TimeSlotStats[];
+};
+
+export type GameLengthGroup = {
+  key: string;
+  label: string;
+  matches: number;
+  turnMatches: number;
+  averageTurns: number | null;
+  winAverageTurns: number | null;
+  lossAverageTurns: number | null;
+  secondsMatches: number;
+  averageSeconds: number | null;
+};
+
+export type GameLengthStats = {
+  overall: GameLengthGroup;
+  decks: GameLengthGroup[];
+  formats: GameLengthGroup[];
+  sets: GameLengthGroup[];
+  months: GameLengthGroup[];
+};
+
+export type MetaDeck = {
+  colorsKey: string;
+  colors: string[];
+  archetype: string;
+  matches: number;
+  share: number;
+  previousShare: number | null;
+  shareDelta: number | null;
+  record: RecordAgg;
+};
+
+export type MetaWeek = {
+  weekStart: string;
+  matches: number;
+  decks: MetaDeck[];
+};
+
+export type MetaFormat = {
+  format: string;
+  matches: number;
+  weeks: MetaWeek[];
+};
+
+export type MetaReport = {
+  weeks: number;
+  formats: MetaFormat[];
+};
+
+export type DraftPickShare = {
+  key: string;
+  count: number;
+  share: number;
+};
+
+export type DraftWheelCard = {
+  cardId: number;
+  cardName?: string;
+  rating?: number;
+  passed: number;
+  wheeled: number;
+};
+
+export type DraftSetAnalytics = {
+  setCode: string;
+  drafts: number;
+  lastDraftedAt: string;
+  ratingsImported: boolean;
+  firstPicks: number;
+  firstPickRarities: DraftPickShare[];
+  firstPickColors: DraftPickShare[];
+  wheelChances: number;
+  wheeled: number;
+  wheelRate: number | null;
+  strongRating: number | null;
+  strongWheelChances: number;
+  strongWheeled: number;
+  strongWheelRate: number | null;
+  wheeledStrongCards: DraftWheelCard[];
+  ratedPicks: number;
+  topRatedPicks: number;
+  topRatedPickRate: number | null;
+  averageRatingDelta: number | null;
+};
+
+export type DraftStatsResponse = {
+  sets: DraftSetAnalytics[];
+};
+
+export type DeckCardDrawImpact = {
+  cardId: number;
+  cardName?: string;
+  drawn: WinRateInterval;
+  notDrawn: WinRateInterval;
+  winRateDelta?: number;
+  qualified: boolean;
+};
+
+export type DeckCardDrawStats = {
+  deckId: number;
+  deckVersionId?: number;
+  minGames: number;
+  games: RecordAgg;
+  cards: DeckCardDrawImpact[];
+};
+
+export type ManaGroup = {
+  deckId?: number;
+  deckName?: string;
+  games: number;
+  openingHandGames: number;
+  averageOpeningLands: number | null;
+  turnStatGames: number;
+  averageMissedDrops: number | null;
+  screwed: RecordAgg;
+  notScrewed: RecordAgg;
+  stuckSpellGames: number;
+  averageStuckSpells: number | null;
+};
+
+export type ManaStats = {
+  stuckSpellTurn: number;
+  screwMissedDrops: number;
+  overall: ManaGroup;
+  decks: ManaGroup[];
+};
+
+export type Bo3Split = {
+  matches: WinRateInterval;
+  games: WinRateInterval;
+  gameOne: WinRateInterval;
+  postBoard: WinRateInterval;
+  postBoardDelta?: number;
+};
+
+export type DeckBo3Split = Bo3Split & {
+  deckId: number;
+  deckName: string;
+  format: string;
+};
+
+export type Bo3Stats = {
+  overall: Bo3Split;
+  decks: DeckBo3Split[];
+  matchesWithoutGames: number;
+};
+
+export type CompanionRecord = {
+  cardId: number;
+  cardName?: string;
+  rate: WinRateInterval;
+};
+
+export type CompanionStats = {
+  companions: CompanionRecord[];
+  commanders: CompanionRecord[];
+  opponentCompanions: CompanionRecord[];
+  opponentCommanders: CompanionRecord[];
+};
+
+export type LimitedRun = {
+  eventName: string;
+  eventDisplayName?: string;
+  setCode?: string;
+  kind: string;
+  status: string;
+  startedAt?: string;
+  endedAt?: string;
+  wins: number;
+  losses: number;
+  trophyWins: number;
+  trophy: boolean;
+  finished: boolean;
+};
+
+export type TrophySummary = {
+  runs: number;
+  activeRuns: number;
+  trophies: number;
+  trophyRate?: number | null;
+  averageWins?: number | null;
+  lastTrophyAt?: string;
+};
+
+export type TrophyGroup = TrophySummary & {
+  setCode: string;
+  kind: string;
+  kindLabel: string;
+};
+
+export type TrophyStats = {
+  summary: TrophySummary;
+  groups: TrophyGroup[];
+  runs: LimitedRun[];
+};
+
+export type OpponentTierRecord = {
+  tier: string;
+  record: RecordAgg;
+};
+
+export type OpponentRankGroup = {
+  deckId?: number;
+  deckName?: string;
+  format?: string;
+  matches: WinRateInterval;
+  averageOpponentTier?: number | null;
+  averageOpponentLabel?: string;
+  weightedWinRate?: number | null;
+  byTier: OpponentTierRecord[];
+};
+
+export type OpponentRankStats = {
+  tiers: string[];
+  overall: OpponentRankGroup;
+  decks: OpponentRankGroup[];
+  matchesWithoutRank: number;
+};
+
+export type DeckCastCurveBucket = {
+  manaValue: number;
+  label: string;
+  cards: number;
+  casts: number;
+  averageCastTurn?: number;
+  castsOnTurn: number;
+};
+
+export type DeckCardCastTurns = {
+  cardId: number;
+  cardName?: string;
+  manaValue?: number;
+  copies: number;
+  casts: number;
+  castTurns: number[];
+  averageCastTurn?: number;
+  onCurveCasts: number;
+  averageDelay?: number;
+  late: boolean;
+};
+
+export type DeckCastCurve = {
+  deckId: number;
+  deckVersionId?: number;
+  minCasts: number;
+  games: number;
+  curve: DeckCastCurveBucket[];
+  unknownManaValue: number;
+  cards: DeckCardCastTurns[];
+};
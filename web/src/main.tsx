@@ -5,6 +5,7 @@ import { QueryClient, QueryClientProvider } from "@tanstack/react-query";
 
 import { App } from "./App";
 import { AppErrorFallback, ErrorBoundary } from "./components/ErrorBoundary";
+import { BASE_PATH } from "./lib/basePath";
 
 // Self-hosted fonts (bundled by Vite; no network dependency at runtime).
 // Inter: body/reading. IBM Plex Mono: data, labels, numerals. Space Grotesk: display headings.
@@ -26,7 +27,7 @@ ReactDOM.createRoot(document.getElementById("root") as HTMLElement).render(
   <React.StrictMode>
     <ErrorBoundary label="app" fallback={(error, reset) => <AppErrorFallback error={error} onRetry={reset} />}>
       <QueryClientProvider client={queryClient}>
-        <BrowserRouter>
+        <BrowserRouter basename={BASE_PATH || "/"}>
           <App />
         </BrowserRouter>
       </QueryClientProvider>
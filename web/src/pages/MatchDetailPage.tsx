@@ -4017,7 +4017,15 @@ export function MatchDetailPage() {
   const isOpponentCardMetadataLoading = opponentCardPreviewQueries.some(
     (previewQuery) => previewQuery.isPending,
   );
-  const timelineRows = timelineQuery.data ?? query.data?.cardPlays ?? [];
+  const timelineRows = useMemo(
+    () =>
+      timelineQuery.data
+        ? timelineQuery.data.games.flatMap((game) =>
+            game.turns.flatMap((turn) => turn.plays),
+          )
+        : (query.data?.cardPlays ?? []),
+    [query.data?.cardPlays, timelineQuery.data],
+  );
   const replayFrames = replayQuery.data ?? [];
   const replayGroups = useMemo<ReplayGameGroup[]>(
     () =>
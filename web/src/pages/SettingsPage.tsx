@@ -2,7 +2,7 @@ import { useEffect, useMemo, useState, type ReactNode } from "react";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
 
 import { StatusMessage } from "../components/StatusMessage";
-import { api } from "../lib/api";
+import { api, saveAPIKey } from "../lib/api";
 import { APP_NAME } from "../lib/branding";
 import { formatBytes, formatDateTime, formatRelativeTime, shortenHomePath } from "../lib/format";
 import { useThemeControls, type ColorScheme, type ModePreference } from "../lib/theme";
@@ -265,9 +265,47 @@ function syncForm(status: RuntimeStatus): RuntimeConfig {
     includePrev: status.config.includePrev,
     autoStartLive: status.config.autoStartLive ?? false,
     autoCheckUpdates: status.config.autoCheckUpdates ?? false,
+    // Not editable here yet; carried through so saving keeps config.json values.
+    backupDir: status.config.backupDir,
+    backupKeep: status.config.backupKeep,
   };
 }
 
+// APIKeyForm lets the user enter the key of a server started with -api-key;
+// it is kept in this browser only.
+function APIKeyForm({ onSaved }: { onSaved: () => void }) {
+  const [key, setKey] = useState("");
+  return (
+    <section className="panel">
+      <div className="panel-head panel-head--stacked">
+        <h3>API Key</h3>
+        <p>If this server was started with -api-key, enter the key to send with every request.</p>
+      </div>
+      <form
+        className="settings-input-row"
+        onSubmit={(event) => {
+          event.preventDefault();
+          saveAPIKey(key.trim());
+          setKey("");
+          onSaved();
+        }}
+      >
+        <input
+          className="settings-input"
+          type="password"
+          value={key}
+          onChange={(event) => setKey(event.target.value)}
+          autoComplete="off"
+          spellCheck={false}
+        />
+        <button type="submit" className="control-button">
+          Save Key
+        </button>
+      </form>
+    </section>
+  );
+}
+
 export function SettingsPage() {
   const queryClient = useQueryClient();
   const { modePreference, setModePreference, scheme, setScheme } = useThemeControls();
@@ -397,7 +435,14 @@ export function SettingsPage() {
   }, [form.pollIntervalSeconds]);
 
   if (isLoading) return <StatusMessage>Loading runtime settings…</StatusMessage>;
-  if (error) return <StatusMessage tone="error">{(error as Error).message}</StatusMessage>;
+  if (error) {
+    return (
+      <div className="stack-lg">
+        <StatusMessage tone="error">{(error as Error).message}</StatusMessage>
+        <APIKeyForm onSaved={() => void refreshDataQueries(queryClient)} />
+      </div>
+    );
+  }
   if (!data) return <StatusMessage>No runtime status available.</StatusMessage>;
 
   const effectiveActivePath = form.logPath.trim() || data.defaultLogPath;
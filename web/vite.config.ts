@@ -3,6 +3,9 @@ import react from "@vitejs/plugin-react";
 
 export default defineConfig({
   plugins: [react()],
+  // Relative asset URLs resolve against index.html's <base href>, which the
+  // backend points at -base-path when one is set.
+  base: "./",
   server: {
     port: 5173,
     proxy: {